@@ -1,7 +1,10 @@
 // Package config 提供统一的配置管理
 package config
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // BandwidthConfig 带宽统计配置
 //
@@ -26,6 +29,66 @@ type BandwidthConfig struct {
 	// IdleTimeout 空闲超时，超过此时间的条目会被清理
 	// 默认值: 30m
 	IdleTimeout Duration `json:"idle_timeout"`
+
+	// Limits 带宽限速（需启用带宽统计）
+	// 默认值: 不限制
+	Limits BandwidthLimitsConfig `json:"limits"`
+
+	// DataBudget 计费网络的数据预算（需启用带宽统计）
+	// 默认值: 自动检测，不设预算
+	DataBudget DataBudgetConfig `json:"data_budget"`
+}
+
+// RateLimitConfig 单个范围的限速（字节/秒，0 表示不限制）
+type RateLimitConfig struct {
+	// In 下载速率上限
+	In int64 `json:"in"`
+
+	// Out 上传速率上限
+	Out int64 `json:"out"`
+
+	// Burst 突发容量（字节），0 时取 1 秒的速率
+	Burst int64 `json:"burst,omitempty"`
+}
+
+// BandwidthLimitsConfig 带宽限速配置
+//
+// 限速在流读写层生效，运行时可通过 Node.SetBandwidthLimits 调整。
+type BandwidthLimitsConfig struct {
+	// Total 节点总上传/下载上限
+	Total RateLimitConfig `json:"total"`
+
+	// PerPeer 每个对端节点的默认上限
+	PerPeer RateLimitConfig `json:"per_peer"`
+
+	// Peers 指定节点的上限（覆盖 PerPeer）
+	Peers map[string]RateLimitConfig `json:"peers,omitempty"`
+
+	// Protocols 按协议 ID 的上限
+	Protocols map[string]RateLimitConfig `json:"protocols,omitempty"`
+
+	// Realms 按 RealmID 的配额
+	Realms map[string]RateLimitConfig `json:"realms,omitempty"`
+}
+
+// 计费网络模式
+const (
+	DataBudgetModeAuto      = "auto"      // 根据系统提示检测
+	DataBudgetModeMetered   = "metered"   // 始终省流
+	DataBudgetModeUnmetered = "unmetered" // 从不省流
+)
+
+// DataBudgetConfig 数据预算配置
+//
+// 计费网络下按每日预算放慢 DHT 刷新、GossipSub 心跳、存活检测、
+// 中继发现、网络诊断和 Realm 成员心跳等后台流量。运行时可通过 Node.SetDataBudgetMode 调整。
+type DataBudgetConfig struct {
+	// Mode 计费网络模式："auto"、"metered" 或 "unmetered"
+	// 默认值: "auto"（空值等同 auto）
+	Mode string `json:"mode,omitempty"`
+
+	// DailyBytes 每日流量预算（字节，入+出），0 表示不设预算
+	DailyBytes int64 `json:"daily_bytes,omitempty"`
 }
 
 // DefaultBandwidthConfig 返回默认的带宽统计配置
@@ -41,6 +104,49 @@ func DefaultBandwidthConfig() BandwidthConfig {
 
 // Validate 验证带宽统计配置的有效性
 func (c *BandwidthConfig) Validate() error {
-	// 带宽统计配置无需严格验证
+	// 带宽统计配置无需严格验证，只检查限速与数据预算
+	if err := c.Limits.Validate(); err != nil {
+		return err
+	}
+	return c.DataBudget.Validate()
+}
+
+// Validate 验证数据预算配置
+func (c *DataBudgetConfig) Validate() error {
+	switch c.Mode {
+	case "", DataBudgetModeAuto, DataBudgetModeMetered, DataBudgetModeUnmetered:
+	default:
+		return fmt.Errorf("invalid data budget mode: %q", c.Mode)
+	}
+	if c.DailyBytes < 0 {
+		return fmt.Errorf("data budget daily bytes must be non-negative")
+	}
+	return nil
+}
+
+// Validate 验证限速配置
+func (c *BandwidthLimitsConfig) Validate() error {
+	check := func(scope string, l RateLimitConfig) error {
+		if l.In < 0 || l.Out < 0 || l.Burst < 0 {
+			return fmt.Errorf("bandwidth limit for %s must be non-negative", scope)
+		}
+		return nil
+	}
+
+	if err := check("total", c.Total); err != nil {
+		return err
+	}
+	if err := check("per peer", c.PerPeer); err != nil {
+		return err
+	}
+	for name, limits := range map[string]map[string]RateLimitConfig{
+		"peer": c.Peers, "protocol": c.Protocols, "realm": c.Realms,
+	} {
+		for key, l := range limits {
+			if err := check(name+" "+key, l); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
@@ -55,6 +55,7 @@ type KnownPeer struct {
 //   - PathHealth: 路径健康管理
 //   - Recovery: 网络恢复
 //   - ConnectionHealth: 连接健康监控
+//   - Reputation: 节点信誉
 type Config struct {
 	// Identity 身份配置
 	Identity IdentityConfig `json:"identity"`
@@ -104,6 +105,9 @@ type Config struct {
 	// Diagnostics 诊断服务配置
 	Diagnostics DiagnosticsConfig `json:"diagnostics"`
 
+	// Reputation 节点信誉配置
+	Reputation ReputationConfig `json:"reputation"`
+
 	// KnownPeers 已知节点列表
 	//
 	// 启动时将直接连接这些节点，不依赖引导节点或 DHT 发现。
@@ -133,6 +137,7 @@ func NewConfig() *Config {
 		Recovery:         DefaultRecoveryConfig(),
 		ConnectionHealth: DefaultConnectionHealthConfig(),
 		Diagnostics:      DefaultDiagnosticsConfig(),
+		Reputation:       DefaultReputationConfig(),
 	}
 }
 
@@ -186,5 +191,11 @@ func (c *Config) Validate() error {
 	if err := c.ConnectionHealth.Validate(); err != nil {
 		return err
 	}
+	if err := c.Diagnostics.Validate(); err != nil {
+		return err
+	}
+	if err := c.Reputation.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
@@ -265,6 +265,101 @@ func TestResourceConfig(t *testing.T) {
 	t.Log("✅ ResourceConfig 测试通过")
 }
 
+// TestTracingConfig 测试追踪配置
+func TestTracingConfig(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		cfg := DefaultTracingConfig()
+		assert.False(t, cfg.Enabled)
+		assert.Equal(t, "dep2p", cfg.ServiceName)
+		assert.NoError(t, cfg.Validate())
+	})
+
+	t.Run("Validate_MissingFile", func(t *testing.T) {
+		cfg := NewConfig()
+		cfg.Diagnostics.Tracing.Enabled = true
+		assert.Error(t, cfg.Validate())
+
+		cfg.Diagnostics.Tracing.File = "/tmp/dep2p-spans.jsonl"
+		assert.NoError(t, cfg.Validate())
+	})
+
+	t.Log("✅ TracingConfig 测试通过")
+}
+
+// TestTransportConfig_DialStagger 测试错峰拨号配置
+func TestTransportConfig_DialStagger(t *testing.T) {
+	cfg := NewConfig()
+	assert.Equal(t, 250*time.Millisecond, cfg.Transport.DialAddrDelay.Duration())
+	assert.Equal(t, 300*time.Millisecond, cfg.Transport.DialClassDelay.Duration())
+
+	// 0 表示同时拨号
+	cfg.Transport.DialAddrDelay = 0
+	cfg.Transport.DialClassDelay = 0
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Transport.DialClassDelay = Duration(-time.Second)
+	assert.Error(t, cfg.Validate())
+}
+
+// TestCaptureConfig 测试录制配置
+func TestCaptureConfig(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		cfg := DefaultCaptureConfig()
+		assert.False(t, cfg.Enabled)
+		assert.Equal(t, 3, cfg.MaxFiles)
+		assert.NoError(t, cfg.Validate())
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		cfg := NewConfig()
+		cfg.Diagnostics.Capture.Enabled = true
+		assert.Error(t, cfg.Validate())
+
+		cfg.Diagnostics.Capture.File = "/tmp/dep2p.capture"
+		assert.NoError(t, cfg.Validate())
+
+		cfg.Diagnostics.Capture.MaxFiles = -1
+		assert.Error(t, cfg.Validate())
+	})
+
+	t.Log("✅ CaptureConfig 测试通过")
+}
+
+// TestReputationConfig 测试信誉配置
+func TestReputationConfig(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		cfg := DefaultReputationConfig()
+		assert.True(t, cfg.Enabled)
+		assert.False(t, cfg.Persist)
+		assert.Equal(t, -50.0, cfg.BanThreshold)
+		assert.Equal(t, 10000, cfg.MaxPeers)
+		assert.NoError(t, cfg.Validate())
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		cfg := NewConfig()
+		cfg.Reputation.Weights = map[string]float64{"dial": 40}
+		assert.NoError(t, cfg.Validate())
+
+		cfg.Reputation.Weights = map[string]float64{"unknown": 10}
+		assert.Error(t, cfg.Validate())
+
+		cfg.Reputation.Weights = nil
+		cfg.Reputation.BanThreshold = 10
+		assert.Error(t, cfg.Validate())
+
+		// 0 表示不封禁
+		cfg.Reputation.BanThreshold = 0
+		cfg.Reputation.BanDuration = 0
+		assert.NoError(t, cfg.Validate())
+
+		cfg.Reputation.MaxPeers = -1
+		assert.Error(t, cfg.Validate())
+	})
+
+	t.Log("✅ ReputationConfig 测试通过")
+}
+
 // TestPresetConfigs 测试预设配置
 func TestPresetConfigs(t *testing.T) {
 	t.Run("MobileConfig", func(t *testing.T) {
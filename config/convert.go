@@ -76,6 +76,7 @@ func applyMobilePreset(cfg *Config) error {
 	cfg.NAT.EnableAutoNAT = true
 	cfg.NAT.EnableUPnP = true
 	cfg.NAT.EnableNATPMP = true
+	cfg.NAT.EnablePCP = true
 	cfg.NAT.EnableHolePunch = true
 	cfg.NAT.AutoNAT.EnableServer = false // 移动端不应为他人服务
 
@@ -169,6 +170,7 @@ func applyMinimalPreset(cfg *Config) error {
 	cfg.NAT.EnableAutoNAT = true
 	cfg.NAT.EnableUPnP = false   // 禁用 UPnP
 	cfg.NAT.EnableNATPMP = false // 禁用 NAT-PMP
+	cfg.NAT.EnablePCP = false    // 禁用 PCP
 	cfg.NAT.EnableHolePunch = false
 	cfg.NAT.AutoNAT.EnableServer = false
 
@@ -104,6 +104,7 @@ func NewMinimalConfig() *Config {
 	// ════════════════════════════════════════════════════════════════════════
 	cfg.NAT.EnableUPnP = false   // 禁用 UPnP，避免路由器交互
 	cfg.NAT.EnableNATPMP = false // 禁用 NAT-PMP，避免路由器交互
+	cfg.NAT.EnablePCP = false    // 禁用 PCP，避免路由器交互
 
 	// ════════════════════════════════════════════════════════════════════════
 	// 中继配置：完全禁用，不参与中继网络
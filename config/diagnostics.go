@@ -1,5 +1,7 @@
 package config
 
+import "errors"
+
 // DiagnosticsConfig 诊断服务配置
 type DiagnosticsConfig struct {
 	// EnableIntrospect 启用自省服务
@@ -8,6 +10,66 @@ type DiagnosticsConfig struct {
 	// IntrospectAddr 自省服务监听地址
 	// 默认 "127.0.0.1:6060"
 	IntrospectAddr string `json:"introspect_addr" yaml:"introspect_addr"`
+
+	// Tracing 分布式追踪配置
+	Tracing TracingConfig `json:"tracing" yaml:"tracing"`
+
+	// Capture 协议流量录制配置
+	Capture CaptureConfig `json:"capture" yaml:"capture"`
+}
+
+// TracingConfig 分布式追踪配置
+//
+// 启用后节点记录拨号、协商、中继、消息等 Span，并以 OTLP/JSON 行格式
+// 写入本地文件，由 OpenTelemetry Collector 读取后转发到追踪后端。
+// 未启用时节点仍透传收到的追踪上下文，不打断调用链。
+type TracingConfig struct {
+	// Enabled 启用追踪
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// File Span 输出文件（OTLP/JSON 行格式，追加写入）
+	File string `json:"file,omitempty" yaml:"file,omitempty"`
+
+	// ServiceName 资源属性 service.name
+	// 默认 "dep2p"
+	ServiceName string `json:"service_name,omitempty" yaml:"service_name,omitempty"`
+}
+
+// CaptureConfig 协议流量录制配置
+//
+// 启用后创建录制器，可通过自省服务的 /debug/capture 端点启停；
+// AutoStart 为 true 时节点启动即开始录制。录制内容为解密后的
+// 协议数据，只应在排查问题时开启。
+type CaptureConfig struct {
+	// Enabled 启用录制器
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// AutoStart 节点启动时立即开始录制
+	AutoStart bool `json:"auto_start,omitempty" yaml:"auto_start,omitempty"`
+
+	// File 录制文件（JSON 行格式，按大小轮转）
+	File string `json:"file,omitempty" yaml:"file,omitempty"`
+
+	// MaxFileSize 单个录制文件大小上限（字节）
+	// 默认 64 MiB
+	MaxFileSize int64 `json:"max_file_size,omitempty" yaml:"max_file_size,omitempty"`
+
+	// MaxFiles 保留的轮转文件数
+	// 默认 3
+	MaxFiles int `json:"max_files,omitempty" yaml:"max_files,omitempty"`
+
+	// MaxPayload 单帧最多记录的字节数
+	// 默认 64 KiB
+	MaxPayload int `json:"max_payload,omitempty" yaml:"max_payload,omitempty"`
+
+	// Peers 只录制这些节点的流（为空表示全部）
+	Peers []string `json:"peers,omitempty" yaml:"peers,omitempty"`
+
+	// Protocols 只录制这些协议前缀的流（为空表示全部）
+	Protocols []string `json:"protocols,omitempty" yaml:"protocols,omitempty"`
+
+	// Redact 只记录数据长度，不记录内容
+	Redact bool `json:"redact,omitempty" yaml:"redact,omitempty"`
 }
 
 // DefaultDiagnosticsConfig 返回默认诊断配置
@@ -15,5 +77,55 @@ func DefaultDiagnosticsConfig() DiagnosticsConfig {
 	return DiagnosticsConfig{
 		EnableIntrospect: false, // 默认禁用
 		IntrospectAddr:   "127.0.0.1:6060",
+		Tracing:          DefaultTracingConfig(),
+		Capture:          DefaultCaptureConfig(),
+	}
+}
+
+// DefaultTracingConfig 返回默认追踪配置
+func DefaultTracingConfig() TracingConfig {
+	return TracingConfig{
+		Enabled:     false, // 默认禁用
+		ServiceName: "dep2p",
+	}
+}
+
+// DefaultCaptureConfig 返回默认录制配置
+func DefaultCaptureConfig() CaptureConfig {
+	return CaptureConfig{
+		Enabled:     false, // 默认禁用
+		MaxFileSize: 64 << 20,
+		MaxFiles:    3,
+		MaxPayload:  64 << 10,
+	}
+}
+
+// Validate 验证诊断配置
+func (c DiagnosticsConfig) Validate() error {
+	if err := c.Tracing.Validate(); err != nil {
+		return err
+	}
+	return c.Capture.Validate()
+}
+
+// Validate 验证追踪配置
+func (c TracingConfig) Validate() error {
+	if c.Enabled && c.File == "" {
+		return errors.New("tracing file is required when tracing is enabled")
+	}
+	return nil
+}
+
+// Validate 验证录制配置
+func (c CaptureConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.File == "" {
+		return errors.New("capture file is required when capture is enabled")
+	}
+	if c.MaxFileSize < 0 || c.MaxFiles < 0 || c.MaxPayload < 0 {
+		return errors.New("capture limits must be non-negative")
 	}
+	return nil
 }
@@ -135,6 +135,12 @@ type MDNSConfig struct {
 
 	// EnableIPv6 是否支持 IPv6
 	EnableIPv6 bool `json:"enable_ipv6,omitempty"`
+
+	// Protocols 在 TXT 记录中声明的协议，供局域网对端拨号前筛选
+	Protocols []string `json:"protocols,omitempty"`
+
+	// AppVersion 在 TXT 记录中声明的应用版本
+	AppVersion string `json:"app_version,omitempty"`
 }
 
 // BootstrapConfig Bootstrap 配置
@@ -180,6 +186,42 @@ type DNSConfig struct {
 
 	// CacheTTL 缓存 TTL
 	CacheTTL Duration `json:"cache_ttl,omitempty"`
+
+	// RequireSigned 只接受签名的 dnsaddr 记录（需配置 TrustedSigners）
+	RequireSigned bool `json:"require_signed,omitempty"`
+
+	// TrustedSigners 信任的记录签名者 PeerID
+	TrustedSigners []string `json:"trusted_signers,omitempty"`
+
+	// Responder 权威 DNS 应答器（基础设施节点发布 dnsaddr 记录）
+	Responder DNSResponderConfig `json:"responder,omitempty"`
+}
+
+// DNSResponderConfig 权威 DNS 应答器配置
+//
+// 启用后节点应答 _dnsaddr.<Zone> 的 TXT 查询，记录由本节点和健康的
+// 同伴基础设施节点生成，并使用节点身份密钥签名。
+type DNSResponderConfig struct {
+	// Enabled 是否启用
+	Enabled bool `json:"enabled"`
+
+	// Zone 权威区域，例如 "bootstrap.example.com"
+	Zone string `json:"zone,omitempty"`
+
+	// ListenAddr 监听地址（UDP + TCP）
+	ListenAddr string `json:"listen_addr,omitempty"`
+
+	// Peers 同伴基础设施节点（完整 multiaddr，含 /p2p/<peerID>）
+	Peers []string `json:"peers,omitempty"`
+
+	// RecordTTL 记录 TTL
+	RecordTTL Duration `json:"record_ttl,omitempty"`
+
+	// SignatureValidity 签名有效期
+	SignatureValidity Duration `json:"signature_validity,omitempty"`
+
+	// SignedOnly 只发布签名记录（不兼容不识别签名记录的客户端）
+	SignedOnly bool `json:"signed_only,omitempty"`
 }
 
 // DefaultDiscoveryConfig 返回默认发现配置
@@ -261,6 +303,12 @@ func DefaultDiscoveryConfig() DiscoveryConfig {
 			ResolverURL: "https://cloudflare-dns.com/dns-query", // DoH 解析器
 			Timeout:     Duration(10 * time.Second),             // 解析超时：10 秒
 			CacheTTL:    Duration(5 * time.Minute),              // 缓存 TTL：5 分钟
+			Responder: DNSResponderConfig{
+				Enabled:           false,                      // 禁用：仅基础设施节点按需启用
+				ListenAddr:        ":53",                      // 标准 DNS 端口
+				RecordTTL:         Duration(60 * time.Second), // 记录 TTL：60 秒，节点变化后快速生效
+				SignatureValidity: Duration(time.Hour),        // 签名有效期：1 小时
+			},
 		},
 	}
 }
@@ -354,6 +402,19 @@ func (c DiscoveryConfig) Validate() error {
 		if c.DNS.CacheTTL <= 0 {
 			return errors.New("DNS cache TTL must be positive")
 		}
+		if c.DNS.RequireSigned && len(c.DNS.TrustedSigners) == 0 {
+			return errors.New("DNS require_signed needs trusted_signers")
+		}
+	}
+
+	// 验证 DNS 应答器配置
+	if c.DNS.Responder.Enabled {
+		if c.DNS.Responder.Zone == "" {
+			return errors.New("DNS responder zone must not be empty")
+		}
+		if c.DNS.Responder.ListenAddr == "" {
+			return errors.New("DNS responder listen address must not be empty")
+		}
 	}
 
 	return nil
@@ -2,7 +2,10 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"time"
+
+	"github.com/dep2p/go-dep2p/pkg/lib/compress"
 )
 
 // MessagingConfig 消息传递配置
@@ -29,6 +32,18 @@ type MessagingConfig struct {
 
 	// Liveness Liveness 配置
 	Liveness LivenessConfig
+
+	// Mailbox 离线信箱配置
+	Mailbox MailboxConfig
+
+	// Compression 负载压缩配置
+	Compression CompressionConfig
+
+	// Transfer 文件传输配置
+	Transfer TransferConfig
+
+	// Outbox 持久化发件箱配置
+	Outbox OutboxConfig
 }
 
 // PubSubConfig PubSub 配置
@@ -85,6 +100,82 @@ type LivenessConfig struct {
 	EnableAutoRemove bool
 }
 
+// MailboxConfig 离线信箱配置
+//
+// 信箱客户端随 Realm 自动创建；EnableServer 启用后本节点为其他成员
+// 存储离线消息（需要配置数据目录）。
+type MailboxConfig struct {
+	// EnableServer 是否作为信箱节点存储离线消息
+	EnableServer bool
+
+	// DefaultTTL 默认消息 TTL
+	DefaultTTL time.Duration
+
+	// MaxTTL 最大消息 TTL
+	MaxTTL time.Duration
+
+	// MaxMessageSize 单条消息最大字节数
+	MaxMessageSize int
+
+	// MaxPerSender 每个发送方最多待投递消息数
+	MaxPerSender int
+
+	// MaxPerRecipient 每个接收方最多待投递消息数
+	MaxPerRecipient int
+
+	// MailboxPeers 本节点使用的信箱节点 ID
+	MailboxPeers []string
+}
+
+// CompressionConfig 负载压缩配置
+//
+// 压缩通过协议协商启用，对端不支持时自动回退到未压缩传输。
+// 算法名称可选 "zstd"、"snappy"，按偏好排序。
+// Streams 的压缩按流在打开时指定（StreamOptions.Compression）。
+type CompressionConfig struct {
+	// Protocols 按 Messaging 协议启用的压缩算法
+	Protocols map[string][]string
+
+	// Topics 按 PubSub 主题启用的压缩算法
+	Topics map[string][]string
+
+	// MaxDecompressedSize 解压后大小上限（字节，0 表示使用默认值）
+	MaxDecompressedSize int
+}
+
+// TransferConfig 文件传输配置
+//
+// 文件传输服务随 Realm 自动创建，基于 Streams 提供可校验、
+// 可续传、多源并行的内容寻址传输。
+type TransferConfig struct {
+	// ChunkSize 分块大小（字节）
+	ChunkSize int
+
+	// MaxPeers 单次下载同时使用的最大提供者数
+	MaxPeers int
+
+	// MaxUploadRate 上传限速（字节/秒，0 表示不限速）
+	MaxUploadRate int64
+
+	// MaxDownloadRate 下载限速（字节/秒，0 表示不限速）
+	MaxDownloadRate int64
+}
+
+// OutboxConfig 持久化发件箱配置
+//
+// 启用后 Messaging 支持 SendDurable：消息写入存储引擎（需要配置数据目录），
+// 跨重启和断线重连重试，直到送达或过期。
+type OutboxConfig struct {
+	// Enable 是否启用发件箱
+	Enable bool
+
+	// TTL 消息默认有效期
+	TTL time.Duration
+
+	// MaxPending 最多待投递消息数
+	MaxPending int
+}
+
 // DefaultMessagingConfig 返回默认消息配置
 func DefaultMessagingConfig() MessagingConfig {
 	return MessagingConfig{
@@ -129,6 +220,44 @@ func DefaultMessagingConfig() MessagingConfig {
 			MaxMissedHeartbeats: 3,                // 最大丢失心跳：3 次后判定失活
 			EnableAutoRemove:    true,             // 自动移除：启用，自动清理失活节点
 		},
+
+		// ════════════════════════════════════════════════════════════════════
+		// Mailbox 配置（离线消息存储转发）
+		// ════════════════════════════════════════════════════════════════════
+		Mailbox: MailboxConfig{
+			EnableServer:    false,              // 信箱服务端：禁用，仅基础设施节点需要
+			DefaultTTL:      24 * time.Hour,     // 默认 TTL：24 小时
+			MaxTTL:          7 * 24 * time.Hour, // 最大 TTL：7 天
+			MaxMessageSize:  64 << 10,           // 最大消息大小：64 KB
+			MaxPerSender:    1000,               // 每发送方配额：1000 条
+			MaxPerRecipient: 1000,               // 每接收方配额：1000 条
+		},
+
+		// ════════════════════════════════════════════════════════════════════
+		// Compression 配置（负载压缩，按协议/主题启用）
+		// ════════════════════════════════════════════════════════════════════
+		Compression: CompressionConfig{
+			MaxDecompressedSize: compress.DefaultMaxDecompressedSize, // 解压上限：16 MB
+		},
+
+		// ════════════════════════════════════════════════════════════════════
+		// Transfer 配置（可续传的大文件传输）
+		// ════════════════════════════════════════════════════════════════════
+		Transfer: TransferConfig{
+			ChunkSize:       256 << 10, // 分块大小：256 KB
+			MaxPeers:        4,         // 并行提供者：4 个
+			MaxUploadRate:   0,         // 上传限速：不限
+			MaxDownloadRate: 0,         // 下载限速：不限
+		},
+
+		// ════════════════════════════════════════════════════════════════════
+		// Outbox 配置（持久化发件箱，至少一次投递）
+		// ════════════════════════════════════════════════════════════════════
+		Outbox: OutboxConfig{
+			Enable:     false,          // 发件箱：禁用，按需启用
+			TTL:        24 * time.Hour, // 消息有效期：24 小时
+			MaxPending: 10000,          // 待投递上限：10000 条
+		},
 	}
 }
 
@@ -182,6 +311,58 @@ func (c MessagingConfig) Validate() error {
 		}
 	}
 
+	// 验证 Mailbox 配置
+	if c.Mailbox.EnableServer {
+		if c.Mailbox.DefaultTTL <= 0 {
+			return errors.New("mailbox default TTL must be positive")
+		}
+		if c.Mailbox.MaxTTL < c.Mailbox.DefaultTTL {
+			return errors.New("mailbox max TTL must not be less than default TTL")
+		}
+		if c.Mailbox.MaxMessageSize <= 0 {
+			return errors.New("mailbox max message size must be positive")
+		}
+		if c.Mailbox.MaxPerSender <= 0 || c.Mailbox.MaxPerRecipient <= 0 {
+			return errors.New("mailbox quotas must be positive")
+		}
+	}
+
+	// 验证 Compression 配置
+	if c.Compression.MaxDecompressedSize < 0 {
+		return errors.New("compression max decompressed size must not be negative")
+	}
+	for name, algs := range c.Compression.Protocols {
+		if _, err := compress.ParseAlgorithms(algs); err != nil {
+			return fmt.Errorf("compression for protocol %q: %w", name, err)
+		}
+	}
+	for name, algs := range c.Compression.Topics {
+		if _, err := compress.ParseAlgorithms(algs); err != nil {
+			return fmt.Errorf("compression for topic %q: %w", name, err)
+		}
+	}
+
+	// 验证 Transfer 配置
+	if c.Transfer.ChunkSize < 16<<10 || c.Transfer.ChunkSize > 4<<20 {
+		return errors.New("transfer chunk size must be between 16 KB and 4 MB")
+	}
+	if c.Transfer.MaxPeers <= 0 {
+		return errors.New("transfer max peers must be positive")
+	}
+	if c.Transfer.MaxUploadRate < 0 || c.Transfer.MaxDownloadRate < 0 {
+		return errors.New("transfer rate limits must not be negative")
+	}
+
+	// 验证 Outbox 配置
+	if c.Outbox.Enable {
+		if c.Outbox.TTL <= 0 {
+			return errors.New("outbox TTL must be positive")
+		}
+		if c.Outbox.MaxPending <= 0 {
+			return errors.New("outbox max pending must be positive")
+		}
+	}
+
 	return nil
 }
 
@@ -29,6 +29,9 @@ type NATConfig struct {
 	// EnableNATPMP 是否启用 NAT-PMP 端口映射
 	EnableNATPMP bool `json:"enable_natpmp"`
 
+	// EnablePCP 是否启用 PCP（RFC 6887）端口映射
+	EnablePCP bool `json:"enable_pcp"`
+
 	// EnableHolePunch 是否启用 Hole Punching
 	EnableHolePunch bool `json:"enable_holepunch"`
 
@@ -42,6 +45,9 @@ type NATConfig struct {
 	// 21: 新增 NAT-PMP 专用配置
 	NATPMP NATPMPConfig `json:"natpmp,omitempty"`
 
+	// PCP 配置
+	PCP PCPConfig `json:"pcp,omitempty"`
+
 	// Hole Punching 配置
 	HolePunch HolePunchConfig `json:"holepunch,omitempty"`
 
@@ -103,6 +109,13 @@ type NATPMPConfig struct {
 	Timeout time.Duration
 }
 
+// PCPConfig PCP 配置
+type PCPConfig struct {
+	// Timeout PCP 操作超时时间（探测、端口映射）
+	// 默认: 5 秒
+	Timeout time.Duration
+}
+
 // HolePunchConfig Hole Punching 配置
 type HolePunchConfig struct {
 	// MaxRetries 最大重试次数
@@ -127,6 +140,7 @@ func DefaultNATConfig() NATConfig {
 		EnableAutoNAT:   true, // 启用 AutoNAT：自动检测 NAT 类型和公网可达性
 		EnableUPnP:      true, // 启用 UPnP：自动向路由器请求端口映射
 		EnableNATPMP:    true, // 启用 NAT-PMP：Apple 路由器端口映射协议
+		EnablePCP:       true, // 启用 PCP：NAT-PMP 后继协议，支持 IPv6/NAT64
 		EnableHolePunch: true, // 启用 Hole Punching：通过协调服务器打洞直连
 
 		// ════════════════════════════════════════════════════════════════════
@@ -160,6 +174,13 @@ func DefaultNATConfig() NATConfig {
 			Timeout: 5 * time.Second, // NAT-PMP 操作超时：5 秒
 		},
 
+		// ════════════════════════════════════════════════════════════════════
+		// PCP 配置
+		// ════════════════════════════════════════════════════════════════════
+		PCP: PCPConfig{
+			Timeout: 5 * time.Second, // PCP 操作超时：5 秒
+		},
+
 		// ════════════════════════════════════════════════════════════════════
 		// Hole Punching 配置（DCUtR 协议）
 		// ════════════════════════════════════════════════════════════════════
@@ -277,6 +298,12 @@ func (c NATConfig) WithNATPMP(enabled bool) NATConfig {
 	return c
 }
 
+// WithPCP 设置是否启用 PCP
+func (c NATConfig) WithPCP(enabled bool) NATConfig {
+	c.EnablePCP = enabled
+	return c
+}
+
 // WithHolePunch 设置是否启用 Hole Punching
 func (c NATConfig) WithHolePunch(enabled bool) NATConfig {
 	c.EnableHolePunch = enabled
@@ -12,6 +12,7 @@ import (
 //   - Routing: 路由管理
 //   - Member: 成员管理
 //   - Auth: 认证管理
+//   - Topology: 拓扑协议
 type RealmConfig struct {
 	// EnableGateway 启用网关
 	EnableGateway bool
@@ -36,6 +37,9 @@ type RealmConfig struct {
 
 	// Auth 认证配置
 	Auth AuthConfig
+
+	// Topology 拓扑协议配置
+	Topology TopologyConfig
 }
 
 // GatewayConfig 网关配置
@@ -104,6 +108,24 @@ type AuthConfig struct {
 	ReplayWindow time.Duration
 }
 
+// TopologyConfig 拓扑协议配置
+//
+// 启用后成员周期性地与已连接的成员交换邻居摘要（节点、连接类型、RTT），
+// 任一成员都可以组装全 Realm 的连接图，识别分区和单点故障。
+type TopologyConfig struct {
+	// Enable 是否启用拓扑协议
+	Enable bool
+
+	// Interval 摘要交换间隔
+	Interval time.Duration
+
+	// ReportTTL 摘要有效期
+	ReportTTL time.Duration
+
+	// HotRelayShare 中继承载的电路占比达到该值时标记为热点中继
+	HotRelayShare float64
+}
+
 // DefaultRealmConfig 返回默认 Realm 配置
 func DefaultRealmConfig() RealmConfig {
 	return RealmConfig{
@@ -158,6 +180,16 @@ func DefaultRealmConfig() RealmConfig {
 			Timeout:         30 * time.Second, // 认证超时：30 秒
 			ReplayWindow:    5 * time.Minute,  // 重放检测窗口：5 分钟
 		},
+
+		// ════════════════════════════════════════════════════════════════════
+		// Topology 配置（Realm 拓扑协议）
+		// ════════════════════════════════════════════════════════════════════
+		Topology: TopologyConfig{
+			Enable:        false,           // 拓扑协议：禁用，按需启用
+			Interval:      1 * time.Minute, // 摘要交换间隔：1 分钟
+			ReportTTL:     5 * time.Minute, // 摘要有效期：5 分钟
+			HotRelayShare: 0.5,             // 热点中继：承载过半中继电路
+		},
 	}
 }
 
@@ -233,6 +265,19 @@ func (c RealmConfig) Validate() error {
 		}
 	}
 
+	// 验证拓扑配置
+	if c.Topology.Enable {
+		if c.Topology.Interval <= 0 {
+			return errors.New("topology interval must be positive")
+		}
+		if c.Topology.ReportTTL < c.Topology.Interval {
+			return errors.New("topology report TTL must not be less than interval")
+		}
+		if c.Topology.HotRelayShare <= 0 || c.Topology.HotRelayShare > 1 {
+			return errors.New("topology hot relay share must be in (0, 1]")
+		}
+	}
+
 	return nil
 }
 
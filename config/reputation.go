@@ -0,0 +1,92 @@
+// Package config 提供统一的配置管理
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// reputationSignals 可配置权重的信誉信号
+var reputationSignals = map[string]struct{}{
+	"pubsub":     {},
+	"connmgr":    {},
+	"jitter":     {},
+	"pathhealth": {},
+	"witness":    {},
+	"dial":       {},
+}
+
+// ReputationConfig 节点信誉配置
+//
+// 信誉服务汇总 GossipSub 评分、连接管理器标签、抖动、路径健康、
+// 见证报告和拨号结果，为每个节点计算统一的信誉分。
+// 引导节点、中继节点和连接管理器保护的节点不会被封禁。
+type ReputationConfig struct {
+	// Enabled 是否启用信誉服务
+	// 默认值: true
+	Enabled bool `json:"enabled"`
+
+	// HalfLife 信号衰减半衰期
+	// 默认值: 30m
+	HalfLife Duration `json:"half_life"`
+
+	// BanThreshold 临时封禁阈值，信誉分低于该值的节点被临时封禁
+	// 0 表示不封禁
+	// 默认值: -50
+	BanThreshold float64 `json:"ban_threshold"`
+
+	// BanDuration 临时封禁时长
+	// 默认值: 30m
+	BanDuration Duration `json:"ban_duration"`
+
+	// Persist 是否持久化信誉记录（需要存储引擎）
+	// 默认值: false
+	Persist bool `json:"persist"`
+
+	// MaxPeers 记录的节点数上限，超过时先淘汰分数最接近 0 的节点
+	// 默认值: 10000
+	MaxPeers int `json:"max_peers"`
+
+	// Weights 各信号权重（pubsub、connmgr、jitter、pathhealth、witness、dial）
+	// 未配置的信号使用默认权重
+	Weights map[string]float64 `json:"weights,omitempty"`
+}
+
+// DefaultReputationConfig 返回默认的信誉配置
+func DefaultReputationConfig() ReputationConfig {
+	return ReputationConfig{
+		Enabled:      true,
+		HalfLife:     Duration(30 * time.Minute),
+		BanThreshold: -50,
+		BanDuration:  Duration(30 * time.Minute),
+		MaxPeers:     10000,
+	}
+}
+
+// Validate 验证信誉配置的有效性
+func (c *ReputationConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.HalfLife < 0 {
+		return fmt.Errorf("reputation: half_life must be non-negative")
+	}
+	if c.BanThreshold > 0 {
+		return fmt.Errorf("reputation: ban_threshold must be <= 0")
+	}
+	if c.MaxPeers < 0 {
+		return fmt.Errorf("reputation: max_peers must be non-negative")
+	}
+	if c.BanThreshold < 0 && c.BanDuration <= 0 {
+		return fmt.Errorf("reputation: ban_duration must be positive when bans are enabled")
+	}
+	for signal, weight := range c.Weights {
+		if _, ok := reputationSignals[signal]; !ok {
+			return fmt.Errorf("reputation: unknown signal %q in weights", signal)
+		}
+		if weight < 0 {
+			return fmt.Errorf("reputation: weight of %q must be non-negative", signal)
+		}
+	}
+	return nil
+}
@@ -24,6 +24,12 @@ type SecurityConfig struct {
 	// NegotiateTimeout 协议协商超时
 	NegotiateTimeout Duration `json:"negotiate_timeout"`
 
+	// PostQuantum 是否启用 X25519 + ML-KEM-768 混合后量子密钥交换
+	//
+	// 启用后 TLS/Noise 优先协商混合变体，QUIC 通过 ALPN 协商；
+	// 对端不支持时回退到经典 X25519。
+	PostQuantum bool `json:"post_quantum,omitempty"`
+
 	// TLS TLS 配置
 	TLS TLSConfig `json:"tls,omitempty"`
 
@@ -76,6 +82,7 @@ func DefaultSecurityConfig() SecurityConfig {
 		// 协商配置
 		// ════════════════════════════════════════════════════════════════════
 		NegotiateTimeout: Duration(60 * time.Second), // 协商超时：60 秒，包括多轮协议协商
+		PostQuantum:      false,                      // 混合后量子密钥交换：默认关闭，按需启用
 
 		// ════════════════════════════════════════════════════════════════════
 		// TLS 配置
@@ -26,6 +26,12 @@ type TransportConfig struct {
 
 	// 通用配置
 	DialTimeout Duration `json:"dial_timeout"` // 拨号超时
+
+	// 错峰拨号（Happy Eyeballs）
+	// 地址按类别依次拨号：局域网 QUIC、局域网 TCP、公网 QUIC、公网 TCP、中继；
+	// 两个延迟都为 0 时所有地址同时拨号
+	DialAddrDelay  Duration `json:"dial_addr_delay"`  // 同类地址之间的拨号间隔
+	DialClassDelay Duration `json:"dial_class_delay"` // 进入下一类地址前的拨号间隔
 }
 
 // QUICConfig QUIC 传输配置
@@ -47,6 +53,24 @@ type QUICConfig struct {
 
 	// KeepAlivePeriod KeepAlive 周期
 	KeepAlivePeriod Duration `json:"keep_alive_period"`
+
+	// ZeroRTT 重连已知节点时是否使用 0-RTT 早期数据
+	//
+	// 会话票据总是缓存并用于恢复会话；只有调用方经 WithEarlyData 标记的流
+	// 会在 0-RTT 中发送，服务端只对声明为幂等的协议在握手确认前处理。
+	ZeroRTT bool `json:"zero_rtt"`
+
+	// Migration 网络变化时是否迁移连接
+	//
+	// 启用后 Rebind 通过 QUIC 连接迁移把出站连接切换到新的本地地址，
+	// 连接和流不中断；禁用时重建 socket，由 recovery 重新连接。
+	Migration bool `json:"migration"`
+
+	// Multipath 是否在第二个网络接口上维持备用路径
+	//
+	// 启用后每个出站连接额外探测一条经由其他接口的路径，
+	// 当前路径失效时由路径健康管理器决定切换，适用于 Wi-Fi + 蜂窝或双网卡设备。
+	Multipath bool `json:"multipath"`
 }
 
 // TCPConfig TCP 传输配置
@@ -97,6 +121,9 @@ func DefaultTransportConfig() TransportConfig {
 			MaxConnectionReceiveWindow: 15 * 1024 * 1024,           // 连接接收窗口：15 MB，所有流共享
 			KeepAlive:                  true,                       // 启用 KeepAlive：保持连接活跃
 			KeepAlivePeriod:            Duration(15 * time.Second), // KeepAlive 间隔：15 秒
+			ZeroRTT:                    true,                       // 启用 0-RTT：重连已知节点省去一次往返
+			Migration:                  true,                       // 启用连接迁移：网络切换时连接不中断
+			Multipath:                  false,                      // 备用路径默认关闭：额外占用 socket 和探测流量
 		},
 
 		// ════════════════════════════════════════════════════════════════════
@@ -125,7 +152,9 @@ func DefaultTransportConfig() TransportConfig {
 		// ════════════════════════════════════════════════════════════════════
 		// 通用传输配置
 		// ════════════════════════════════════════════════════════════════════
-		DialTimeout: Duration(30 * time.Second), // 拨号超时：30 秒，包括 DNS 解析、TCP 握手等
+		DialTimeout:    Duration(30 * time.Second),       // 拨号超时：30 秒，包括 DNS 解析、TCP 握手等
+		DialAddrDelay:  Duration(250 * time.Millisecond), // 同类地址间隔：250 毫秒（RFC 8305）
+		DialClassDelay: Duration(300 * time.Millisecond), // 类别间隔：300 毫秒，优先给更快的路径机会
 	}
 }
 
@@ -182,6 +211,9 @@ func (c TransportConfig) Validate() error {
 	if c.DialTimeout <= 0 {
 		return errors.New("dial timeout must be positive")
 	}
+	if c.DialAddrDelay < 0 || c.DialClassDelay < 0 {
+		return errors.New("dial stagger delays cannot be negative")
+	}
 
 	return nil
 }
@@ -39,4 +39,29 @@ var (
 
 	// ErrPeerNotFound 节点未找到
 	ErrPeerNotFound = errors.New("peer not found")
+
+	// ────────────────────────────────────────────────────────────────────────
+	// 服务相关错误
+	// ────────────────────────────────────────────────────────────────────────
+
+	// ErrMailboxUnavailable 当前 Realm 未提供离线信箱服务
+	ErrMailboxUnavailable = errors.New("mailbox unavailable")
+
+	// ErrTransferUnavailable 当前 Realm 未提供文件传输服务
+	ErrTransferUnavailable = errors.New("transfer unavailable")
+
+	// ErrOutboxUnavailable 当前 Messaging 服务不支持持久化发件箱
+	ErrOutboxUnavailable = errors.New("outbox unavailable")
+
+	// ErrTopologyUnavailable 当前 Realm 未启用拓扑协议
+	ErrTopologyUnavailable = errors.New("topology unavailable")
+
+	// ErrBandwidthLimiterUnavailable 带宽统计未启用，无法调整限速
+	ErrBandwidthLimiterUnavailable = errors.New("bandwidth limiter unavailable")
+
+	// ErrDataBudgetUnavailable 带宽统计未启用，无法设置数据预算
+	ErrDataBudgetUnavailable = errors.New("data budget unavailable")
+
+	// ErrReputationUnavailable 信誉服务未启用
+	ErrReputationUnavailable = errors.New("reputation unavailable")
 )
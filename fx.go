@@ -29,6 +29,8 @@ import (
 	"github.com/dep2p/go-dep2p/internal/core/recovery/netmon"
 	"github.com/dep2p/go-dep2p/internal/core/recovery/netmon/watcher"
 	"github.com/dep2p/go-dep2p/internal/core/relay"
+	relayclient "github.com/dep2p/go-dep2p/internal/core/relay/client"
+	"github.com/dep2p/go-dep2p/internal/core/reputation"
 	"github.com/dep2p/go-dep2p/internal/core/resourcemgr"
 	"github.com/dep2p/go-dep2p/internal/core/security"
 	"github.com/dep2p/go-dep2p/internal/core/storage"
@@ -37,7 +39,9 @@ import (
 	"github.com/dep2p/go-dep2p/internal/core/swarm/pathhealth"
 	"github.com/dep2p/go-dep2p/internal/core/transport"
 	"github.com/dep2p/go-dep2p/internal/core/upgrader"
+	debugcapture "github.com/dep2p/go-dep2p/internal/debug/capture"
 	"github.com/dep2p/go-dep2p/internal/debug/introspect" // P3 修复：移至 debug 层
+	debugtracing "github.com/dep2p/go-dep2p/internal/debug/tracing"
 
 	// Discovery Layer
 	"github.com/dep2p/go-dep2p/internal/discovery/bootstrap"
@@ -128,6 +132,12 @@ func buildFxApp(cfg *nodeConfig, node *Node) (*fx.App, error) {
 			swarm.Module,       // 连接池
 		)
 
+		// 自定义拨号地址排序器（可选，由 swarm.Module 消费）
+		if cfg.dialRanker != nil {
+			ranker := cfg.dialRanker
+			modules = append(modules, fx.Provide(func() pkgif.DialRanker { return ranker }))
+		}
+
 		// Host（核心门面，依赖传输层）
 		modules = append(modules, host.Module())
 
@@ -167,6 +177,9 @@ func buildFxApp(cfg *nodeConfig, node *Node) (*fx.App, error) {
 		// 将 HolePuncher 注入到 Swarm
 		// 使 Swarm 在直连失败后能够触发 HolePunch 打洞
 		modules = append(modules, fx.Invoke(wireHolePuncher))
+
+		// 网络诊断通过中继协议探测中继（需要 AutoRelay，未加载中继时跳过）
+		modules = append(modules, fx.Invoke(wireRelayProber))
 	}
 
 	// ════════════════════════════════════════════════════════════════════════
@@ -191,6 +204,9 @@ func buildFxApp(cfg *nodeConfig, node *Node) (*fx.App, error) {
 	if cfg.config.Discovery.EnableDNS {
 		modules = append(modules, dns.Module)
 	}
+	if cfg.config.Discovery.DNS.Responder.Enabled {
+		modules = append(modules, dns.ResponderModule)
+	}
 	if cfg.config.Discovery.EnableRendezvous {
 		modules = append(modules, rendezvous.Module)
 	}
@@ -206,6 +222,8 @@ func buildFxApp(cfg *nodeConfig, node *Node) (*fx.App, error) {
 	if cfg.config.Bandwidth.Enabled {
 		modules = append(modules,
 			fx.Provide(provideBandwidthConfig(cfg.config)),
+			fx.Provide(provideBandwidthLimits(cfg.config)),
+			fx.Provide(provideDataBudgetConfig(cfg.config)),
 			bandwidth.Module(),
 		)
 	}
@@ -257,6 +275,24 @@ func buildFxApp(cfg *nodeConfig, node *Node) (*fx.App, error) {
 	// P1 修复完成：9.6 地址管理协议（始终加载）
 	modules = append(modules, addrmgmt.Module())
 
+	// 9.7 分布式追踪（条件加载，配置启用或注入自定义 Exporter）
+	if cfg.config.Diagnostics.Tracing.Enabled || cfg.spanExporter != nil {
+		modules = append(modules, debugtracing.Module(cfg.spanExporter))
+	}
+
+	// 9.8 协议流量录制（条件加载，依赖配置）
+	if cfg.config.Diagnostics.Capture.Enabled {
+		modules = append(modules, debugcapture.Module())
+	}
+
+	// 9.9 节点信誉（条件加载，由 Swarm、ConnMgr、DHT、Relay、PubSub 等消费）
+	if cfg.config.Reputation.Enabled {
+		modules = append(modules,
+			fx.Provide(provideReputationConfig(cfg.config)),
+			reputation.Module(),
+		)
+	}
+
 	// ════════════════════════════════════════════════════════════════════════
 	// 10. RealmManager（始终加载，组件在 JoinRealm 时动态创建）
 	// ════════════════════════════════════════════════════════════════════════
@@ -401,6 +437,47 @@ func provideBandwidthConfig(cfg *config.Config) func() *pkgif.BandwidthConfig {
 	}
 }
 
+// provideBandwidthLimits 提供带宽限速配置
+func provideBandwidthLimits(cfg *config.Config) func() *pkgif.BandwidthLimits {
+	return func() *pkgif.BandwidthLimits {
+		limits := cfg.Bandwidth.Limits
+		convert := func(m map[string]config.RateLimitConfig) map[string]pkgif.BandwidthLimit {
+			if len(m) == 0 {
+				return nil
+			}
+			out := make(map[string]pkgif.BandwidthLimit, len(m))
+			for k, v := range m {
+				out[k] = pkgif.BandwidthLimit(v)
+			}
+			return out
+		}
+		return &pkgif.BandwidthLimits{
+			Total:     pkgif.BandwidthLimit(limits.Total),
+			PerPeer:   pkgif.BandwidthLimit(limits.PerPeer),
+			Peers:     convert(limits.Peers),
+			Protocols: convert(limits.Protocols),
+			Realms:    convert(limits.Realms),
+		}
+	}
+}
+
+// provideDataBudgetConfig 提供数据预算配置
+func provideDataBudgetConfig(cfg *config.Config) func() *pkgif.DataBudgetConfig {
+	return func() *pkgif.DataBudgetConfig {
+		mode := pkgif.DataBudgetAuto
+		switch cfg.Bandwidth.DataBudget.Mode {
+		case config.DataBudgetModeMetered:
+			mode = pkgif.DataBudgetMetered
+		case config.DataBudgetModeUnmetered:
+			mode = pkgif.DataBudgetUnmetered
+		}
+		return &pkgif.DataBudgetConfig{
+			Mode:        mode,
+			DailyBudget: cfg.Bandwidth.DataBudget.DailyBytes,
+		}
+	}
+}
+
 // provideConnectionHealthConfig 提供连接健康监控配置
 func provideConnectionHealthConfig(cfg *config.Config) func() *pkgif.ConnectionHealthMonitorConfig {
 	return func() *pkgif.ConnectionHealthMonitorConfig {
@@ -427,6 +504,52 @@ func providePathHealthConfig(cfg *config.Config) func() *pkgif.PathHealthConfig
 	}
 }
 
+// provideReputationConfig 提供节点信誉配置
+func provideReputationConfig(cfg *config.Config) func() *pkgif.ReputationConfig {
+	return func() *pkgif.ReputationConfig {
+		weights := make(map[pkgif.ReputationSignal]float64, len(cfg.Reputation.Weights))
+		for signal, weight := range cfg.Reputation.Weights {
+			weights[pkgif.ReputationSignal(signal)] = weight
+		}
+		return &pkgif.ReputationConfig{
+			HalfLife:     cfg.Reputation.HalfLife.Duration(),
+			Weights:      weights,
+			BanThreshold: cfg.Reputation.BanThreshold,
+			BanDuration:  cfg.Reputation.BanDuration.Duration(),
+			Persist:      cfg.Reputation.Persist,
+			MaxPeers:     cfg.Reputation.MaxPeers,
+			ExemptPeers:  reputationExemptPeers(cfg),
+		}
+	}
+}
+
+// reputationExemptPeers 返回不封禁的基础设施节点
+//
+// 包括引导节点、中继节点和静态中继，地址中不含 /p2p/ 的条目被忽略。
+func reputationExemptPeers(cfg *config.Config) []string {
+	addrs := append([]string{}, cfg.Discovery.Bootstrap.Peers...)
+	if cfg.Relay.RelayAddr != "" {
+		addrs = append(addrs, cfg.Relay.RelayAddr)
+	}
+	addrs = append(addrs, cfg.Relay.StaticRelays...)
+
+	seen := make(map[string]struct{}, len(addrs))
+	peers := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		info, err := types.AddrInfoFromString(addr)
+		if err != nil || info.ID == "" {
+			continue
+		}
+		id := string(info.ID)
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		peers = append(peers, id)
+	}
+	return peers
+}
+
 // provideRecoveryConfig 提供网络恢复配置
 func provideRecoveryConfig(cfg *config.Config) func() *pkgif.RecoveryConfig {
 	return func() *pkgif.RecoveryConfig {
@@ -791,6 +914,59 @@ func wireHolePuncher(params holePuncherWireParams) {
 	}
 }
 
+// ════════════════════════════════════════════════════════════════════════════
+// 中继探测注入
+// ════════════════════════════════════════════════════════════════════════════
+
+// relayProberWireParams 中继探测注入参数
+type relayProberWireParams struct {
+	fx.In
+
+	NetReportClient *netreport.Client `optional:"true"`
+	AutoRelay       pkgif.AutoRelay   `optional:"true"`
+}
+
+// relayProbeCapable 支持 HOP 探测的 AutoRelay
+type relayProbeCapable interface {
+	ProbeRelay(ctx context.Context, relayAddr string) (*relayclient.ProbeResult, error)
+}
+
+// relayProberAdapter 将 AutoRelay 的探测能力适配为 netreport.RelayProber
+//
+// 探测结果同时回写到 AutoRelay 的候选列表，使网络诊断与中继选择共用同一份测量。
+type relayProberAdapter struct {
+	autoRelay relayProbeCapable
+}
+
+// ProbeRelay 实现 netreport.RelayProber
+func (a *relayProberAdapter) ProbeRelay(ctx context.Context, relay string) (netreport.RelayProbe, error) {
+	result, err := a.autoRelay.ProbeRelay(ctx, relay)
+	if err != nil {
+		return netreport.RelayProbe{}, err
+	}
+	return netreport.RelayProbe{
+		Latency:                result.RTT,
+		LoadKnown:              result.LoadKnown,
+		ReservationUtilization: result.ReservationUtilization(),
+		CircuitUtilization:     result.CircuitUtilization(),
+	}, nil
+}
+
+// wireRelayProber 将中继探测器注入到网络诊断客户端
+func wireRelayProber(params relayProberWireParams) {
+	if params.NetReportClient == nil || params.AutoRelay == nil {
+		return
+	}
+
+	prober, ok := params.AutoRelay.(relayProbeCapable)
+	if !ok {
+		fxLogger.Debug("AutoRelay 不支持探测，跳过中继探测器注入")
+		return
+	}
+
+	params.NetReportClient.SetRelayProber(&relayProberAdapter{autoRelay: prober})
+}
+
 // ════════════════════════════════════════════════════════════════════════════
 // 已知节点连接
 // ════════════════════════════════════════════════════════════════════════════
@@ -18,6 +18,7 @@ require (
 	github.com/flynn/noise v1.1.0
 	github.com/google/uuid v1.6.0
 	github.com/jackpal/gateway v1.0.15
+	github.com/klauspost/compress v1.18.0
 	github.com/libp2p/go-yamux/v5 v5.1.0
 	github.com/miekg/dns v1.1.55
 	github.com/multiformats/go-multistream v0.6.1
@@ -36,7 +37,6 @@ require (
 	github.com/go-logr/logr v1.4.3 // indirect
 	github.com/go-logr/stdr v1.2.2 // indirect
 	github.com/google/flatbuffers v25.2.10+incompatible // indirect
-	github.com/klauspost/compress v1.18.0 // indirect
 	github.com/libp2p/go-buffer-pool v0.0.2 // indirect
 	github.com/multiformats/go-varint v0.0.7 // indirect
 	github.com/pion/dtls/v2 v2.2.7 // indirect
@@ -19,6 +19,12 @@ type Gater struct {
 	blockedSubnets map[string]*net.IPNet // 子网黑名单
 	blockedPorts   map[int]struct{}      // 端口黑名单
 
+	// 节点信誉（可选，被临时封禁的节点视同黑名单）
+	reputation pkgif.Reputation
+
+	// 连接保护（可选，受保护的节点不因信誉封禁被拒绝）
+	protector protector
+
 	// 统计
 	interceptedDials   int64
 	interceptedAccepts int64
@@ -61,10 +67,52 @@ func (g *Gater) IsBlocked(peer string) bool {
 	return blocked
 }
 
+// SetReputation 设置节点信誉服务
+//
+// 设置后被信誉服务临时封禁的节点在拨号和握手阶段被拒绝，封禁到期后自动放行。
+func (g *Gater) SetReputation(rep pkgif.Reputation) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.reputation = rep
+}
+
+// protector 连接保护查询
+type protector interface {
+	IsProtected(peer, tag string) bool
+}
+
+// setProtector 设置连接保护查询
+func (g *Gater) setProtector(p protector) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.protector = p
+}
+
+// isDenied 检查节点是否在黑名单中或被信誉服务封禁
+//
+// 黑名单始终生效；受保护的节点不因信誉封禁被拒绝。
+func (g *Gater) isDenied(peer string) bool {
+	g.mu.RLock()
+	_, blocked := g.blocked[peer]
+	rep := g.reputation
+	prot := g.protector
+	g.mu.RUnlock()
+
+	if blocked {
+		return true
+	}
+	if rep == nil || !rep.IsBanned(peer) {
+		return false
+	}
+	return prot == nil || !prot.IsProtected(peer, "")
+}
+
 // InterceptPeerDial 在拨号前检查是否允许连接到目标节点
 // 返回 true 表示允许，false 表示拒绝
 func (g *Gater) InterceptPeerDial(peerID string) bool {
-	if g.IsBlocked(peerID) {
+	if g.isDenied(peerID) {
 		atomic.AddInt64(&g.interceptedDials, 1)
 		return false
 	}
@@ -74,8 +122,8 @@ func (g *Gater) InterceptPeerDial(peerID string) bool {
 // InterceptAddrDial 在拨号前检查是否允许连接到目标地址
 // 返回 true 表示允许，false 表示拒绝
 func (g *Gater) InterceptAddrDial(peerID string, addr string) bool {
-	// 1. 基于节点 ID 判断
-	if g.IsBlocked(peerID) {
+	// 1. 基于节点 ID 判断（含信誉封禁）
+	if g.isDenied(peerID) {
 		atomic.AddInt64(&g.interceptedDials, 1)
 		return false
 	}
@@ -148,7 +196,7 @@ func (g *Gater) InterceptAccept(conn pkgif.Connection) bool {
 // 返回 true 表示允许，false 表示拒绝
 func (g *Gater) InterceptSecured(_ pkgif.Direction, peerID string, _ pkgif.Connection) bool {
 	// 在握手后可以获取到 PeerID，可以做更精确的过滤
-	if g.IsBlocked(peerID) {
+	if g.isDenied(peerID) {
 		atomic.AddInt64(&g.interceptedAccepts, 1)
 		return false
 	}
@@ -161,9 +209,9 @@ func (g *Gater) InterceptUpgraded(conn pkgif.Connection) (bool, error) {
 	// 连接升级后的最后检查点
 	// 此时连接已完全建立，可以进行最终决策
 
-	// 1. 检查节点是否在黑名单
+	// 1. 检查节点是否在黑名单或被信誉服务封禁
 	remotePeer := string(conn.RemotePeer())
-	if g.IsBlocked(remotePeer) {
+	if g.isDenied(remotePeer) {
 		return false, nil
 	}
 
@@ -3,6 +3,7 @@ package connmgr
 import (
 	"testing"
 
+	"github.com/dep2p/go-dep2p/internal/core/reputation"
 	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
 	"github.com/dep2p/go-dep2p/pkg/types"
 	"github.com/dep2p/go-dep2p/tests/mocks"
@@ -55,6 +56,70 @@ func TestGater_InterceptAddrDial(t *testing.T) {
 	t.Log("✅ InterceptAddrDial 拦截正确")
 }
 
+// TestGater_ReputationBan 测试信誉封禁的节点被拦截
+func TestGater_ReputationBan(t *testing.T) {
+	gater := NewGater()
+	rep, err := reputation.New(pkgif.DefaultReputationConfig(), nil)
+	require.NoError(t, err)
+	gater.SetReputation(rep)
+
+	peer := "bad-peer"
+	mockConn := mocks.NewMockConnection("local-peer", types.PeerID(peer))
+	assert.True(t, gater.InterceptPeerDial(peer))
+
+	rep.Observe(peer, pkgif.ReputationSignalPubSub, -1, "graylisted")
+	rep.Observe(peer, pkgif.ReputationSignalWitness, -1, "rejected_report")
+	rep.Report(peer, pkgif.ReputationSignalDial, -1, "dial_failed")
+	require.True(t, rep.IsBanned(peer))
+
+	assert.False(t, gater.InterceptPeerDial(peer))
+	assert.False(t, gater.InterceptAddrDial(peer, "/ip4/1.2.3.4/tcp/4001"))
+	assert.False(t, gater.InterceptSecured(pkgif.DirInbound, peer, mockConn))
+	allowed, _ := gater.InterceptUpgraded(mockConn)
+	assert.False(t, allowed)
+	assert.False(t, gater.IsBlocked(peer), "reputation ban is not a blocklist entry")
+
+	// 解除封禁后放行
+	rep.Unban(peer)
+	assert.True(t, gater.InterceptPeerDial(peer))
+}
+
+// bannedReputation 封禁所有节点的信誉服务 mock
+type bannedReputation struct {
+	pkgif.Reputation
+}
+
+func (bannedReputation) IsBanned(string) bool { return true }
+
+// TestGater_ReputationBanSkipsProtected 测试受保护节点不因信誉封禁被拦截
+func TestGater_ReputationBanSkipsProtected(t *testing.T) {
+	mgr, err := New(Config{LowWater: 10, HighWater: 20})
+	require.NoError(t, err)
+	defer mgr.Close()
+
+	gater := NewGater()
+	rep, err := reputation.New(pkgif.DefaultReputationConfig(), nil)
+	require.NoError(t, err)
+	registerReputation(reputationInput{Manager: mgr, Gater: gater, Reputation: rep})
+
+	// 受保护的节点在信誉服务中不会被封禁
+	mgr.Protect("protected-peer", "bootstrap")
+	rep.Observe("protected-peer", pkgif.ReputationSignalPubSub, -1, "graylisted")
+	rep.Observe("protected-peer", pkgif.ReputationSignalWitness, -1, "rejected_report")
+	rep.Report("protected-peer", pkgif.ReputationSignalDial, -1, "dial_failed")
+	assert.False(t, rep.IsBanned("protected-peer"))
+	assert.True(t, gater.InterceptPeerDial("protected-peer"))
+
+	// 其他信誉实现报告封禁时，门控器同样放行受保护的节点
+	gater.SetReputation(bannedReputation{})
+	assert.True(t, gater.InterceptPeerDial("protected-peer"))
+	assert.False(t, gater.InterceptPeerDial("other-peer"))
+
+	// 黑名单始终生效
+	gater.BlockPeer("protected-peer")
+	assert.False(t, gater.InterceptPeerDial("protected-peer"))
+}
+
 // TestGater_InterceptAccept 测试拦截入站连接
 func TestGater_InterceptAccept(t *testing.T) {
 	gater := NewGater()
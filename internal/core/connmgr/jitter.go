@@ -206,6 +206,17 @@ func (j *JitterTolerance) NotifyReconnected(peerID string) {
 	}
 }
 
+// recentlyDisconnected 检查节点是否在状态保持时间内断连过
+//
+// 需在 NotifyReconnected 之前调用，用于识别频繁断连的节点。
+func (j *JitterTolerance) recentlyDisconnected(peerID string) bool {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	state, ok := j.disconnectedPeers[peerID]
+	return ok && time.Since(state.DisconnectedAt) <= j.config.StateHoldTime
+}
+
 // ShouldRemove 检查是否应该移除节点
 func (j *JitterTolerance) ShouldRemove(peerID string) bool {
 	if !j.config.Enabled {
@@ -2,6 +2,7 @@ package connmgr
 
 import (
 	"context"
+	"math"
 	"sync"
 	"time"
 
@@ -49,8 +50,23 @@ type Manager struct {
 
 	// 裁剪触发通道
 	trimCh chan struct{}
+
+	// 节点信誉（可选，参与裁剪评分，并接收标签与抖动信号）
+	reputation   pkgif.Reputation
+	reputationMu sync.RWMutex
 }
 
+const (
+	// bannedTrimPenalty 被信誉服务封禁的节点的裁剪评分惩罚，确保优先裁剪
+	bannedTrimPenalty = 1000
+
+	// flapPenalty 频繁断连（状态保持时间内重连）的信誉惩罚
+	flapPenalty = -0.2
+
+	// tagSignalScale 标签权重换算为信誉信号的比例（±100 对应 ±1）
+	tagSignalScale = 100
+)
+
 // Host 定义获取连接的最小接口
 type Host interface {
 	// Connections 返回所有连接
@@ -110,6 +126,7 @@ func (m *Manager) TagPeer(peerID string, tag string, weight int) {
 
 	logger.Debug("为节点添加标签", "peerID", truncateID(peerID, 8), "tag", tag, "weight", weight)
 	m.tags.Set(peerID, tag, weight)
+	m.observeTags(peerID, tag)
 }
 
 // UntagPeer 移除节点标签
@@ -122,6 +139,7 @@ func (m *Manager) UntagPeer(peerID string, tag string) {
 	}
 
 	m.tags.Delete(peerID, tag)
+	m.observeTags(peerID, tag)
 }
 
 // UpsertTag 更新或插入节点标签
@@ -134,6 +152,7 @@ func (m *Manager) UpsertTag(peerID string, tag string, upsert func(int) int) {
 	}
 
 	m.tags.Upsert(peerID, tag, upsert)
+	m.observeTags(peerID, tag)
 }
 
 // GetTagInfo 获取节点的标签信息
@@ -253,8 +272,13 @@ func (n *managerNotifee) Connected(conn pkgif.Connection) {
 		}
 	}
 	
-	// 通知抖动容忍器节点重连成功
+	// 通知抖动容忍器节点重连成功（状态保持时间内重连视为频繁断连）
 	if n.mgr.jitter != nil {
+		if n.mgr.jitter.recentlyDisconnected(peerID) {
+			if rep := n.mgr.getReputation(); rep != nil {
+				rep.Report(peerID, pkgif.ReputationSignalJitter, flapPenalty, "flapping")
+			}
+		}
 		n.mgr.jitter.NotifyReconnected(peerID)
 	}
 	
@@ -403,12 +427,42 @@ func (m *Manager) SetHost(host Host) {
 	m.host = host
 }
 
+// SetReputation 设置节点信誉服务
+//
+// 设置后裁剪评分计入信誉分（被封禁的节点优先裁剪），
+// 标签变化和频繁断连作为信誉信号上报。
+func (m *Manager) SetReputation(rep pkgif.Reputation) {
+	m.reputationMu.Lock()
+	defer m.reputationMu.Unlock()
+	m.reputation = rep
+}
+
+// getReputation 返回节点信誉服务（未设置时为 nil）
+func (m *Manager) getReputation() pkgif.Reputation {
+	m.reputationMu.RLock()
+	defer m.reputationMu.RUnlock()
+	return m.reputation
+}
+
+// observeTags 标签变化后将节点的标签权重上报为信誉信号
+//
+// 连接管理器自动添加的 connected 标签不计入。
+func (m *Manager) observeTags(peer, tag string) {
+	rep := m.getReputation()
+	if rep == nil || tag == "connected" {
+		return
+	}
+	weight := m.tags.Sum(peer) - m.tags.Get(peer, "connected")
+	rep.Observe(peer, pkgif.ReputationSignalConnMgr, float64(weight)/tagSignalScale, "tags")
+}
+
 // calculateScore 计算节点优先级分数
 //
 // 评分规则：
 //  1. 标签权重累加（基础分数）
 //  2. 出站连接加分（主动拨号的节点更重要）
 //  3. 活跃流加分（有数据传输的连接更重要）
+//  4. 信誉分（被封禁的节点大幅扣分）
 func (m *Manager) calculateScore(peer string) int {
 	score := 0
 
@@ -432,6 +486,14 @@ func (m *Manager) calculateScore(peer string) int {
 		score += 20
 	}
 
+	// 4. 信誉分
+	if rep := m.getReputation(); rep != nil {
+		score += int(math.Round(rep.Score(peer)))
+		if rep.IsBanned(peer) {
+			score -= bannedTrimPenalty
+		}
+	}
+
 	return score
 }
 
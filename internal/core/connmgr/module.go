@@ -7,6 +7,7 @@ import (
 	"go.uber.org/fx"
 
 	"github.com/dep2p/go-dep2p/config"
+	"github.com/dep2p/go-dep2p/internal/core/reputation"
 	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
 )
 
@@ -23,9 +24,40 @@ func Module() fx.Option {
 		fx.Invoke(registerLifecycle),
 		fx.Invoke(registerSchedulerLifecycle),  // P2 修复完成
 		fx.Invoke(registerSubnetLimiterLifecycle), // P4 新增
+		fx.Invoke(registerReputation),
 	)
 }
 
+// reputationInput 信誉服务注入参数
+type reputationInput struct {
+	fx.In
+	Manager    pkgif.ConnManager
+	Gater      pkgif.ConnGater
+	Reputation pkgif.Reputation `optional:"true"`
+}
+
+// registerReputation 将信誉服务注入连接管理器和门控器
+//
+// 同时把连接保护反向提供给信誉服务和门控器，受保护的节点不会被封禁。
+func registerReputation(input reputationInput) {
+	if input.Reputation == nil {
+		return
+	}
+	mgr, ok := input.Manager.(*Manager)
+	if ok {
+		mgr.SetReputation(input.Reputation)
+		if svc, ok := input.Reputation.(interface{ SetProtector(reputation.Protector) }); ok {
+			svc.SetProtector(mgr.protects)
+		}
+	}
+	if gater, ok := input.Gater.(*Gater); ok {
+		gater.SetReputation(input.Reputation)
+		if mgr != nil {
+			gater.setProtector(mgr.protects)
+		}
+	}
+}
+
 // ProvideSubnetLimiter 提供子网限制器（P4 新增）
 func ProvideSubnetLimiter() *SubnetLimiter {
 	return NewSubnetLimiter(DefaultSubnetLimiterConfig())
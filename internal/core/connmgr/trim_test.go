@@ -5,6 +5,12 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dep2p/go-dep2p/internal/core/reputation"
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/types"
+	"github.com/dep2p/go-dep2p/tests/mocks"
 )
 
 // TestManager_CalculateScore 测试分数计算
@@ -132,3 +138,71 @@ func TestManager_SetHost(t *testing.T) {
 
 	t.Log("✅ SetHost 设置成功")
 }
+
+// TestManager_TrimWithReputation 测试回收时参考节点信誉
+func TestManager_TrimWithReputation(t *testing.T) {
+	cfg := Config{
+		LowWater:  3,
+		HighWater: 4,
+	}
+	mgr, _ := New(cfg)
+	defer mgr.Close()
+
+	rep, err := reputation.New(pkgif.DefaultReputationConfig(), nil)
+	require.NoError(t, err)
+	mgr.SetReputation(rep)
+
+	host := newMockHost(5)
+	mgr.SetHost(host)
+	peers := host.Peers()
+
+	// 其他节点的标签分数更低，但信誉差的节点仍被优先裁剪
+	for _, p := range peers[2:] {
+		mgr.TagPeer(p, "app", 1)
+	}
+	mgr.TagPeer(peers[0], "app", 10)
+	mgr.TagPeer(peers[1], "app", 10)
+	rep.Observe(peers[0], pkgif.ReputationSignalPubSub, -1, "graylisted")
+	rep.Observe(peers[1], pkgif.ReputationSignalPubSub, -1, "graylisted")
+	rep.Observe(peers[1], pkgif.ReputationSignalWitness, -1, "rejected_report")
+	rep.Report(peers[1], pkgif.ReputationSignalDial, -1, "dial_failed")
+	require.True(t, rep.IsBanned(peers[1]))
+
+	// 标签 10 + 标签信号 1 + GossipSub 信号 -30
+	assert.Equal(t, 10+1-30, mgr.calculateScore(peers[0]))
+	assert.Less(t, mgr.calculateScore(peers[1]), -bannedTrimPenalty)
+
+	mgr.TrimOpenConns(context.Background())
+
+	assert.False(t, host.IsConnected(peers[0]))
+	assert.False(t, host.IsConnected(peers[1]))
+	assert.Equal(t, 3, host.ConnCount())
+}
+
+// TestManager_ReputationSignals 测试标签与频繁断连上报为信誉信号
+func TestManager_ReputationSignals(t *testing.T) {
+	mgr, _ := New(DefaultConfig())
+	defer mgr.Close()
+
+	rep, err := reputation.New(pkgif.DefaultReputationConfig(), nil)
+	require.NoError(t, err)
+	mgr.SetReputation(rep)
+
+	peer := "peer-1"
+	mgr.TagPeer(peer, "connected", 10)
+	assert.Zero(t, rep.Score(peer), "connected tag should not count")
+
+	mgr.TagPeer(peer, "app", 50)
+	assert.InDelta(t, 5, rep.Score(peer), 0.01)
+	mgr.UntagPeer(peer, "app")
+	assert.InDelta(t, 0, rep.Score(peer), 0.01)
+
+	// 状态保持时间内断连后重连
+	mgr.jitter.NotifyDisconnected(peer)
+	mgr.Notifee().Connected(mocks.NewMockConnection("local", types.PeerID(peer)))
+
+	reasons := rep.Get(peer).Reasons
+	require.NotEmpty(t, reasons)
+	assert.Equal(t, pkgif.ReputationSignalJitter, reasons[0].Signal)
+	assert.Equal(t, "flapping", reasons[0].Reason)
+}
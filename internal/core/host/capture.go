@@ -0,0 +1,76 @@
+package host
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/capture"
+)
+
+// errStreamReset 录制中记录的流重置原因
+var errStreamReset = errors.New("stream reset")
+
+// Recorder 返回流量录制器（未配置时为 nil）
+func (h *Host) Recorder() *capture.Recorder {
+	return h.recorder
+}
+
+// Replay 把入站录制回放到本地注册的协议处理器
+//
+// 按录制的协议 ID（协商结果，含压缩等变体后缀）查找处理器。
+func (h *Host) Replay(ctx context.Context, rec *capture.Recording) (*capture.ReplayResult, error) {
+	h.mu.RLock()
+	handler := h.handlers[rec.Protocol]
+	h.mu.RUnlock()
+
+	if handler == nil {
+		return nil, capture.ErrNoHandler
+	}
+	return capture.Replay(ctx, rec, handler)
+}
+
+// recordStream 录制器启用且流匹配过滤条件时包装流
+func (h *Host) recordStream(stream pkgif.Stream, peerID string, inbound bool) pkgif.Stream {
+	session := h.recorder.Open(peerID, stream.Protocol(), inbound)
+	if session == nil {
+		return stream
+	}
+	return &recordingStream{Stream: stream, session: session}
+}
+
+// recordingStream 记录读写数据的流
+type recordingStream struct {
+	pkgif.Stream
+	session *capture.Session
+}
+
+func (s *recordingStream) Read(p []byte) (int, error) {
+	n, err := s.Stream.Read(p)
+	if n > 0 {
+		s.session.Record(capture.DirIn, p[:n])
+	}
+	if err != nil && err != io.EOF {
+		s.session.Close(err)
+	}
+	return n, err
+}
+
+func (s *recordingStream) Write(p []byte) (int, error) {
+	n, err := s.Stream.Write(p)
+	if n > 0 {
+		s.session.Record(capture.DirOut, p[:n])
+	}
+	return n, err
+}
+
+func (s *recordingStream) Close() error {
+	s.session.Close(nil)
+	return s.Stream.Close()
+}
+
+func (s *recordingStream) Reset() error {
+	s.session.Close(errStreamReset)
+	return s.Stream.Reset()
+}
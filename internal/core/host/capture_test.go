@@ -0,0 +1,61 @@
+package host
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/capture"
+	"github.com/dep2p/go-dep2p/tests/mocks"
+)
+
+// TestHost_RecordAndReplay 测试入站流录制后回放到注册的处理器
+func TestHost_RecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dep2p.capture")
+	recorder, err := capture.NewRecorder(capture.Config{Path: path})
+	require.NoError(t, err)
+	require.NoError(t, recorder.Start(nil))
+
+	host, err := New(
+		WithSwarm(mocks.NewMockSwarm("test-peer-id")),
+		WithPeerstore(mocks.NewMockPeerstore()),
+		WithEventBus(mocks.NewMockEventBus()),
+		WithRecorder(recorder),
+	)
+	require.NoError(t, err)
+	defer host.Close()
+	assert.Same(t, recorder, host.Recorder())
+
+	handler := func(s pkgif.Stream) {
+		data, _ := io.ReadAll(s)
+		s.Write(append([]byte("ack:"), data...))
+		s.Close()
+	}
+	host.SetStreamHandler("/test/1.0.0", handler)
+
+	// 模拟协商完成后的入站流
+	raw := mocks.NewMockStreamWithData([]byte("hello"))
+	handler(host.recordStream(raw, "remote-peer", true))
+	assert.Equal(t, "ack:hello", string(raw.WriteData))
+	require.NoError(t, recorder.Stop())
+
+	recs, err := capture.ReadFile(path)
+	require.NoError(t, err)
+	require.Len(t, recs, 1)
+	assert.Equal(t, "remote-peer", recs[0].Peer)
+	assert.True(t, recs[0].Closed)
+
+	res, err := host.Replay(context.Background(), recs[0])
+	require.NoError(t, err)
+	assert.True(t, res.Matches())
+
+	// 处理器移除后无法回放
+	host.RemoveStreamHandler("/test/1.0.0")
+	_, err = host.Replay(context.Background(), recs[0])
+	assert.ErrorIs(t, err, capture.ErrNoHandler)
+}
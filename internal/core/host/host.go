@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -13,7 +14,9 @@ import (
 	"github.com/dep2p/go-dep2p/internal/core/protocol"
 	"github.com/dep2p/go-dep2p/internal/core/relay"
 	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/capture"
 	"github.com/dep2p/go-dep2p/pkg/lib/log"
+	"github.com/dep2p/go-dep2p/pkg/lib/tracing"
 	"github.com/dep2p/go-dep2p/pkg/types"
 	mss "github.com/multiformats/go-multistream"
 )
@@ -37,6 +40,15 @@ type Host struct {
 	// multistream-select muxer 用于入站协议协商
 	mux *mss.MultistreamMuxer[string]
 
+	// handlers 已注册的协议处理器（用于回放录制）
+	handlers map[string]pkgif.StreamHandler
+
+	// idempotent 声明为幂等、允许使用 0-RTT 早期数据的协议
+	idempotent map[string]bool
+
+	// recorder 流量录制器（可选）
+	recorder *capture.Recorder
+
 	// 服务
 	nat   *nat.Service
 	relay *relay.Manager
@@ -69,6 +81,8 @@ func New(opts ...Option) (*Host, error) {
 		ctxCancel:     cancel,
 		config:        DefaultConfig(),
 		mux:           mss.NewMultistreamMuxer[string](),
+		handlers:      make(map[string]pkgif.StreamHandler),
+		idempotent:    make(map[string]bool),
 		peerConnCount: make(map[string]int),
 	}
 
@@ -356,12 +370,12 @@ func truncatePeerID(peerID string, maxLen int) string {
 
 // NewStream 创建到指定节点的新流（默认优先级）
 // 该方法会确保连接存在，然后创建流并进行协议协商
+//
+// 提供多个协议 ID 时按顺序协商，使用远端支持的第一个协议，
+// 可通过 Stream.Protocol() 获取协商结果。
 func (h *Host) NewStream(ctx context.Context, peerID string, protocolIDs ...string) (pkgif.Stream, error) {
 	// 默认使用普通优先级
-	if len(protocolIDs) == 0 {
-		return h.NewStreamWithPriority(ctx, peerID, "", int(pkgif.StreamPriorityNormal))
-	}
-	return h.NewStreamWithPriority(ctx, peerID, protocolIDs[0], int(pkgif.StreamPriorityNormal))
+	return h.newStream(ctx, peerID, int(pkgif.StreamPriorityNormal), protocolIDs)
 }
 
 // NewStreamWithPriority 创建到指定节点的新流（指定优先级）(v1.2 新增)
@@ -369,6 +383,21 @@ func (h *Host) NewStream(ctx context.Context, peerID string, protocolIDs ...stri
 // 允许指定流优先级。在 QUIC 连接上，优先级会传递给底层传输层。
 // 在 TCP 连接上，优先级会被忽略（优雅降级）。
 func (h *Host) NewStreamWithPriority(ctx context.Context, peerID string, protocolID string, priority int) (pkgif.Stream, error) {
+	if protocolID == "" {
+		return h.newStream(ctx, peerID, priority, nil)
+	}
+	return h.newStream(ctx, peerID, priority, []string{protocolID})
+}
+
+// NewStreamWithPriorityOneOf 创建指定优先级的流，按顺序协商多个协议
+//
+// 用于协议存在多个变体（如压缩变体）且需要指定优先级的场景。
+func (h *Host) NewStreamWithPriorityOneOf(ctx context.Context, peerID string, priority int, protocolIDs ...string) (pkgif.Stream, error) {
+	return h.newStream(ctx, peerID, priority, protocolIDs)
+}
+
+// newStream 创建流并按顺序协商协议
+func (h *Host) newStream(ctx context.Context, peerID string, priority int, protocolIDs []string) (pkgif.Stream, error) {
 	if h.closed.Load() {
 		return nil, errors.New("host is closed")
 	}
@@ -383,17 +412,44 @@ func (h *Host) NewStreamWithPriority(ctx context.Context, peerID string, protoco
 		return nil, fmt.Errorf("failed to create stream: %w", err)
 	}
 
+	// 调用方未通过 WithEarlyData 确认请求可重放时不使用 0-RTT 早期数据：
+	// 等待握手确认后在确认的连接上重新建流
+	if !pkgif.EarlyDataAllowed(ctx) {
+		if early, ok := stream.Conn().(pkgif.EarlyDataConnection); ok && !early.HandshakeConfirmed() {
+			stream.Reset()
+			if err := early.AwaitHandshake(ctx); err != nil {
+				return nil, fmt.Errorf("await handshake: %w", err)
+			}
+			stream, err = h.swarm.NewStreamWithPriority(ctx, peerID, priority)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create stream: %w", err)
+			}
+		}
+	}
+
 	// 2. 协议协商（如果提供了协议 ID）
-	if protocolID != "" {
+	if len(protocolIDs) > 0 {
+		protocolIDs = h.preferKnownProtocols(peerID, protocolIDs)
+
+		_, span := tracing.Start(ctx, "dep2p.host.negotiate", tracing.KindClient,
+			tracing.Attr("peer.id", peerID),
+			tracing.Attr("protocol.offered", strings.Join(protocolIDs, ",")))
+
 		// 使用 multistream-select 进行协议协商（客户端侧）
-		selectedProto, err := mss.SelectOneOf([]string{protocolID}, stream)
+		selectedProto, err := mss.SelectOneOf(protocolIDs, stream)
 		if err != nil {
+			span.End(err)
 			stream.Close()
 			return nil, fmt.Errorf("protocol negotiation failed: %w", err)
 		}
 
 		// 设置协商后的协议 ID 到流
 		stream.SetProtocol(selectedProto)
+		span.SetAttributes(tracing.Attr("protocol", selectedProto))
+		span.End(nil)
+
+		// 3. 录制（协商完成后）
+		return h.recordStream(stream, peerID, false), nil
 	}
 
 	return stream, nil
@@ -401,7 +457,12 @@ func (h *Host) NewStreamWithPriority(ctx context.Context, peerID string, protoco
 
 // SetStreamHandler 为指定协议设置流处理器
 // 同时注册到 multistream-select muxer（入站协商）和 Protocol Router（路由）
-func (h *Host) SetStreamHandler(protocolID string, handler pkgif.StreamHandler) {
+func (h *Host) SetStreamHandler(protocolID string, handler pkgif.StreamHandler, opts ...pkgif.StreamHandlerOption) {
+	var options pkgif.StreamHandlerOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
@@ -428,7 +489,18 @@ func (h *Host) SetStreamHandler(protocolID string, handler pkgif.StreamHandler)
 		h.protocol.AddRoute(protocolID, handler)
 	}
 
-	logger.Debug("注册协议处理器", "protocolID", protocolID)
+	if handler != nil {
+		h.handlers[protocolID] = handler
+	} else {
+		delete(h.handlers, protocolID)
+	}
+	if handler != nil && options.Idempotent {
+		h.idempotent[protocolID] = true
+	} else {
+		delete(h.idempotent, protocolID)
+	}
+
+	logger.Debug("注册协议处理器", "protocolID", protocolID, "idempotent", options.Idempotent)
 }
 
 // RemoveStreamHandler 移除指定协议的流处理器
@@ -446,10 +518,50 @@ func (h *Host) RemoveStreamHandler(protocolID string) {
 	if h.protocol != nil {
 		h.protocol.RemoveRoute(protocolID)
 	}
+	delete(h.handlers, protocolID)
+	delete(h.idempotent, protocolID)
 
 	logger.Debug("移除协议处理器", "protocolID", protocolID)
 }
 
+// preferKnownProtocols 按 identify 记录的对端协议调整协商顺序
+//
+// multistream-select 逐个尝试协议，每个被拒绝的变体都多一次往返。
+// Peerstore 中有对端协议记录时，把对端支持的首个协议放到最前，
+// 其余协议保持原顺序作为记录过期时的回退；没有记录时按原顺序协商。
+func (h *Host) preferKnownProtocols(peerID string, protocolIDs []string) []string {
+	if len(protocolIDs) < 2 || h.peerstore == nil {
+		return protocolIDs
+	}
+
+	ids := make([]types.ProtocolID, len(protocolIDs))
+	for i, id := range protocolIDs {
+		ids[i] = types.ProtocolID(id)
+	}
+	known, err := h.peerstore.FirstSupportedProtocol(types.PeerID(peerID), ids...)
+	if err != nil || known == "" || string(known) == protocolIDs[0] {
+		return protocolIDs
+	}
+
+	ordered := make([]string, 0, len(protocolIDs))
+	ordered = append(ordered, string(known))
+	for _, id := range protocolIDs {
+		if id != string(known) {
+			ordered = append(ordered, id)
+		}
+	}
+	return ordered
+}
+
+// isIdempotent 检查本节点是否将协议声明为幂等
+//
+// 只用于入站流：决定 0-RTT 中打开的流能否在握手确认前交给处理器。
+func (h *Host) isIdempotent(protocolID string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.idempotent[protocolID]
+}
+
 // Peerstore 返回节点存储
 func (h *Host) Peerstore() pkgif.Peerstore {
 	return h.peerstore
@@ -465,6 +577,53 @@ func (h *Host) Network() pkgif.Swarm {
 	return h.swarm
 }
 
+// StreamPriorityStats 返回所有连接各优先级写队列的汇总统计
+//
+// 按优先级累加各连接的统计，平均排队时间按发送帧数加权。
+// 没有连接启用优先级调度时返回 nil。
+func (h *Host) StreamPriorityStats() []pkgif.StreamPriorityStats {
+	if h.swarm == nil {
+		return nil
+	}
+
+	var total []pkgif.StreamPriorityStats
+	var delays []time.Duration // 各优先级的累计排队时间
+	for _, conn := range h.swarm.Conns() {
+		pc, ok := conn.(interface {
+			StreamPriorityStats() []pkgif.StreamPriorityStats
+		})
+		if !ok {
+			continue
+		}
+		for _, s := range pc.StreamPriorityStats() {
+			i := int(s.Priority)
+			if i < 0 {
+				continue
+			}
+			for len(total) <= i {
+				total = append(total, pkgif.StreamPriorityStats{Priority: pkgif.StreamPriority(len(total))})
+				delays = append(delays, 0)
+			}
+			t := &total[i]
+			t.QueuedFrames += s.QueuedFrames
+			t.QueuedBytes += s.QueuedBytes
+			t.SentFrames += s.SentFrames
+			t.SentBytes += s.SentBytes
+			t.Throttled += s.Throttled
+			if s.MaxQueueDelay > t.MaxQueueDelay {
+				t.MaxQueueDelay = s.MaxQueueDelay
+			}
+			delays[i] += s.AvgQueueDelay * time.Duration(s.SentFrames)
+		}
+	}
+	for i := range total {
+		if total[i].SentFrames > 0 {
+			total[i].AvgQueueDelay = delays[i] / time.Duration(total[i].SentFrames)
+		}
+	}
+	return total
+}
+
 // AddObservedAddr 添加观测地址
 //
 // P0 修复：暴露 ObservedAddrManager.Add 供 Identify 订阅器使用。
@@ -575,8 +734,21 @@ func (h *Host) handleInboundStream(stream pkgif.Stream) {
 		return
 	}
 
-	// 2. 设置协商后的协议 ID 到流
+	// 0-RTT 数据可能被重放：非幂等协议等待握手确认后再交给处理器，
+	// 被重放的连接无法完成握手，流随连接关闭而丢弃
+	if !h.isIdempotent(selectedProto) {
+		if early, ok := stream.Conn().(pkgif.EarlyDataConnection); ok && !early.HandshakeConfirmed() {
+			if err := early.AwaitHandshake(h.ctx); err != nil {
+				logger.Debug("等待握手确认失败", "remotePeer", remotePeerLabel, "protocol", selectedProto, "error", err)
+				stream.Reset()
+				return
+			}
+		}
+	}
+
+	// 2. 设置协商后的协议 ID 到流，按需录制
 	stream.SetProtocol(selectedProto)
+	stream = h.recordStream(stream, remotePeer, true)
 
 	// 3. 将流路由到对应的协议处理器
 	// 如果 mux 的 handler 不为 nil，优先使用它
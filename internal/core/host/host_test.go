@@ -898,3 +898,57 @@ func TestHost_handleInboundStream_NilMux(t *testing.T) {
 	assert.True(t, mockStream.ResetCalled, "nil mux 时流应该被 reset")
 	t.Log("✅ nil mux 时 handleInboundStream 正确 reset 流")
 }
+
+// priorityStatsConn 提供优先级调度统计的 mock 连接
+type priorityStatsConn struct {
+	*mocks.MockConnection
+	stats []pkgif.StreamPriorityStats
+}
+
+func (c *priorityStatsConn) StreamPriorityStats() []pkgif.StreamPriorityStats {
+	return c.stats
+}
+
+// TestHost_StreamPriorityStats 测试汇总各连接的优先级调度统计
+func TestHost_StreamPriorityStats(t *testing.T) {
+	host, mockSwarm, _, _ := setupTestHost(t)
+	defer host.Close()
+
+	// 没有连接启用优先级调度
+	mockSwarm.ConnsFunc = func() []pkgif.Connection {
+		return []pkgif.Connection{mocks.NewMockConnection("test-peer-id", "peer-1")}
+	}
+	assert.Nil(t, host.StreamPriorityStats())
+
+	a := &priorityStatsConn{
+		MockConnection: mocks.NewMockConnection("test-peer-id", "peer-1"),
+		stats: []pkgif.StreamPriorityStats{
+			{Priority: pkgif.StreamPriorityCritical, SentFrames: 1, SentBytes: 100, AvgQueueDelay: 10 * time.Millisecond, MaxQueueDelay: 10 * time.Millisecond},
+			{Priority: pkgif.StreamPriorityHigh, QueuedFrames: 2, QueuedBytes: 64, Throttled: 1},
+		},
+	}
+	b := &priorityStatsConn{
+		MockConnection: mocks.NewMockConnection("test-peer-id", "peer-2"),
+		stats: []pkgif.StreamPriorityStats{
+			{Priority: pkgif.StreamPriorityCritical, SentFrames: 3, SentBytes: 300, AvgQueueDelay: 2 * time.Millisecond, MaxQueueDelay: 5 * time.Millisecond},
+		},
+	}
+	mockSwarm.ConnsFunc = func() []pkgif.Connection {
+		return []pkgif.Connection{a, mocks.NewMockConnection("test-peer-id", "peer-3"), b}
+	}
+
+	stats := host.StreamPriorityStats()
+	require.Len(t, stats, 2)
+
+	critical := stats[pkgif.StreamPriorityCritical]
+	assert.Equal(t, uint64(4), critical.SentFrames)
+	assert.Equal(t, uint64(400), critical.SentBytes)
+	assert.Equal(t, 4*time.Millisecond, critical.AvgQueueDelay)
+	assert.Equal(t, 10*time.Millisecond, critical.MaxQueueDelay)
+
+	high := stats[pkgif.StreamPriorityHigh]
+	assert.Equal(t, pkgif.StreamPriorityHigh, high.Priority)
+	assert.Equal(t, 2, high.QueuedFrames)
+	assert.Equal(t, int64(64), high.QueuedBytes)
+	assert.Equal(t, uint64(1), high.Throttled)
+}
@@ -78,6 +78,23 @@ func (h *Host) Start(ctx context.Context) error {
 	return nil
 }
 
+// nodeRecorder 支持节点缓存的 Peerstore（可选能力）
+type nodeRecorder interface {
+	UpdateNodeRecord(peerID types.PeerID, addrs []string) error
+}
+
+// recordNode 更新节点缓存
+//
+// 在独立 goroutine 中执行，存储写入不阻塞 Swarm 通知路径。
+func (h *Host) recordNode(recorder nodeRecorder, peerID types.PeerID, addr string) {
+	if h.closed.Load() {
+		return
+	}
+	if err := recorder.UpdateNodeRecord(peerID, []string{addr}); err != nil {
+		logger.Debug("更新节点缓存失败", "peerID", peerID.ShortString(), "error", err)
+	}
+}
+
 // Connected 当 Swarm 建立新连接时调用（实现 SwarmNotifier 接口）
 //
 // 连接去重：只在首次连接时发布事件，避免同一 peer 的多个连接触发重复事件。
@@ -106,6 +123,14 @@ func (h *Host) Connected(conn pkgif.Connection) {
 			logger.Debug("已将连接地址写入 Peerstore",
 				"peerID", peerIDShort,
 				"addr", remoteAddr.String())
+
+			// 记录到节点缓存，重启后可通过 RecoverSeeds 重新找到网络。
+			// 入站连接的远端地址通常是对端的临时端口，无法回拨，只记录出站连接
+			if conn.Stat().Direction == pkgif.DirOutbound {
+				if recorder, ok := h.peerstore.(nodeRecorder); ok {
+					go h.recordNode(recorder, types.PeerID(peerID), remoteAddr.String())
+				}
+			}
 		}
 	}
 
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
 	"github.com/dep2p/go-dep2p/pkg/types"
 	"github.com/dep2p/go-dep2p/tests/mocks"
 )
@@ -129,6 +130,54 @@ func TestHost_SwarmNotifier_Connected(t *testing.T) {
 	assert.NotNil(t, mockEventBus, "EventBus should be set")
 }
 
+// recordingPeerstore 记录节点缓存更新的 Peerstore
+type recordingPeerstore struct {
+	*mocks.MockPeerstore
+	records chan string
+}
+
+func (p *recordingPeerstore) UpdateNodeRecord(peerID types.PeerID, addrs []string) error {
+	p.records <- string(peerID) + " " + addrs[0]
+	return nil
+}
+
+// TestHost_SwarmNotifier_Connected_RecordsOutboundOnly 测试只有出站连接写入节点缓存
+func TestHost_SwarmNotifier_Connected_RecordsOutboundOnly(t *testing.T) {
+	ps := &recordingPeerstore{MockPeerstore: mocks.NewMockPeerstore(), records: make(chan string, 4)}
+	host, err := New(
+		WithSwarm(mocks.NewMockSwarm("test-peer-id")),
+		WithPeerstore(ps),
+		WithEventBus(mocks.NewMockEventBus()),
+	)
+	require.NoError(t, err)
+	defer host.Close()
+
+	localPeer := types.PeerID(host.ID())
+
+	// 入站连接的远端端口是临时端口，不应作为种子记录
+	inbound := mocks.NewMockConnection(localPeer, "inbound-peer")
+	inbound.RemoteAddr, _ = types.NewMultiaddr("/ip4/1.2.3.4/udp/53124/quic-v1")
+	inbound.StatValue.Direction = pkgif.DirInbound
+	host.Connected(inbound)
+
+	outbound := mocks.NewMockConnection(localPeer, "outbound-peer")
+	outbound.RemoteAddr, _ = types.NewMultiaddr("/ip4/5.6.7.8/udp/4001/quic-v1")
+	outbound.StatValue.Direction = pkgif.DirOutbound
+	host.Connected(outbound)
+
+	select {
+	case rec := <-ps.records:
+		assert.Equal(t, "outbound-peer /ip4/5.6.7.8/udp/4001/quic-v1", rec)
+	case <-time.After(time.Second):
+		t.Fatal("出站连接应写入节点缓存")
+	}
+	select {
+	case rec := <-ps.records:
+		t.Fatalf("入站连接不应写入节点缓存: %s", rec)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
 // TestHost_SwarmNotifier_Disconnected 测试断开事件
 func TestHost_SwarmNotifier_Disconnected(t *testing.T) {
 	host, _, _, mockEventBus := setupTestHost(t)
@@ -9,6 +9,7 @@ import (
 	"github.com/dep2p/go-dep2p/config"
 	"github.com/dep2p/go-dep2p/internal/core/protocol"
 	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/capture"
 	"github.com/dep2p/go-dep2p/pkg/types"
 )
 
@@ -28,6 +29,7 @@ type ModuleInput struct {
 	ConnMgr  pkgif.ConnManager     `optional:"true"`
 	ResMgr   pkgif.ResourceManager `optional:"true"`
 	Protocol *protocol.Router      `optional:"true"`
+	Recorder *capture.Recorder     `optional:"true"`
 	// 注意：NAT 和 Relay 不在这里直接依赖，避免循环依赖
 	// 它们在各自的 lifecycle 中启动
 }
@@ -64,6 +66,7 @@ func ProvideHost(input ModuleInput) (ModuleOutput, error) {
 		WithPeerstore(input.Peerstore),
 		WithEventBus(input.EventBus),
 		WithConfig(hostCfg),
+		WithRecorder(input.Recorder),
 	)
 	if err != nil {
 		return ModuleOutput{}, err
@@ -5,6 +5,7 @@ import (
 	"github.com/dep2p/go-dep2p/internal/core/nat"
 	"github.com/dep2p/go-dep2p/internal/core/protocol"
 	"github.com/dep2p/go-dep2p/internal/core/relay"
+	"github.com/dep2p/go-dep2p/pkg/lib/capture"
 )
 
 // Option Host 构造选项类型
@@ -74,6 +75,14 @@ func WithRelay(relayMgr *relay.Manager) Option {
 	}
 }
 
+// WithRecorder 设置流量录制器
+func WithRecorder(r *capture.Recorder) Option {
+	return func(h *Host) error {
+		h.recorder = r
+		return nil
+	}
+}
+
 // WithConfig 设置配置
 func WithConfig(cfg *Config) Option {
 	return func(h *Host) error {
@@ -1,6 +1,7 @@
 package host
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -183,3 +184,87 @@ func TestHost_MuxNotNil(t *testing.T) {
 	// 验证 mux 已初始化
 	assert.NotNil(t, host.mux)
 }
+
+// TestHost_SetStreamHandler_Idempotent 测试幂等协议声明（服务端规则）
+func TestHost_SetStreamHandler_Idempotent(t *testing.T) {
+	host, _, _, _ := setupTestHost(t)
+	defer host.Close()
+
+	handler := func(s pkgif.Stream) {}
+	host.SetStreamHandler("/query/1.0.0", handler, pkgif.WithIdempotent())
+	host.SetStreamHandler("/write/1.0.0", handler)
+
+	assert.True(t, host.isIdempotent("/query/1.0.0"))
+	assert.False(t, host.isIdempotent("/write/1.0.0"))
+	assert.False(t, host.isIdempotent("/unknown/1.0.0"))
+
+	// 重新注册时未声明幂等，声明随之撤销
+	host.SetStreamHandler("/query/1.0.0", handler)
+	assert.False(t, host.isIdempotent("/query/1.0.0"))
+
+	host.SetStreamHandler("/query/1.0.0", handler, pkgif.WithIdempotent())
+	host.RemoveStreamHandler("/query/1.0.0")
+	assert.False(t, host.isIdempotent("/query/1.0.0"))
+}
+
+// earlyConn 握手尚未确认的 0-RTT 连接
+type earlyConn struct {
+	*mocks.MockConnection
+	awaitCalls int
+}
+
+func (c *earlyConn) HandshakeConfirmed() bool { return c.awaitCalls > 0 }
+
+func (c *earlyConn) AwaitHandshake(context.Context) error {
+	c.awaitCalls++
+	return nil
+}
+
+// TestHost_NewStream_EarlyDataOptIn 测试客户端 0-RTT 只由调用方显式开启
+//
+// 即使本节点把协议声明为幂等，未经 WithEarlyData 标记的流仍等待握手确认。
+func TestHost_NewStream_EarlyDataOptIn(t *testing.T) {
+	host, swarm, _, _ := setupTestHost(t)
+	defer host.Close()
+
+	host.SetStreamHandler("/query/1.0.0", func(s pkgif.Stream) {}, pkgif.WithIdempotent())
+
+	conn := &earlyConn{MockConnection: mocks.NewMockConnection(types.PeerID(host.ID()), "remote-peer")}
+	swarm.NewStreamFunc = func(context.Context, string) (pkgif.Stream, error) {
+		s := mocks.NewMockStream()
+		s.ConnValue = conn
+		return s, nil
+	}
+
+	// 未标记：等待握手确认后重新建流
+	_, _ = host.NewStream(context.Background(), "remote-peer", "/query/1.0.0")
+	assert.Equal(t, 1, conn.awaitCalls)
+	assert.Len(t, swarm.NewStreamCalls, 2)
+
+	// 标记后直接在早期数据中打开
+	conn.awaitCalls = 0
+	swarm.NewStreamCalls = nil
+	_, _ = host.NewStream(pkgif.WithEarlyData(context.Background()), "remote-peer", "/write/1.0.0")
+	assert.Equal(t, 0, conn.awaitCalls)
+	assert.Len(t, swarm.NewStreamCalls, 1)
+}
+
+// TestHost_PreferKnownProtocols 测试按 identify 记录的对端协议选择变体
+func TestHost_PreferKnownProtocols(t *testing.T) {
+	host, _, ps, _ := setupTestHost(t)
+	defer host.Close()
+
+	offered := []string{"/msg/1.0.0+zstd", "/msg/1.0.0+snappy", "/msg/1.0.0"}
+
+	// 没有记录：保持原顺序
+	assert.Equal(t, offered, host.preferKnownProtocols("old-peer", offered))
+
+	// 旧节点只支持基础协议：直接选择，其余作为回退
+	require.NoError(t, ps.SetProtocols("old-peer", "/msg/1.0.0", "/ping/1.0.0"))
+	assert.Equal(t, []string{"/msg/1.0.0", "/msg/1.0.0+zstd", "/msg/1.0.0+snappy"},
+		host.preferKnownProtocols("old-peer", offered))
+
+	// 记录中没有任何候选协议：按原顺序回退
+	require.NoError(t, ps.SetProtocols("other-peer", "/ping/1.0.0"))
+	assert.Equal(t, offered, host.preferKnownProtocols("other-peer", offered))
+}
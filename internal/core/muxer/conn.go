@@ -14,22 +14,53 @@ var logger = log.Logger("core/muxer")
 // muxedConn 包装 yamux.Session，实现 MuxedConn 接口
 type muxedConn struct {
 	session *yamux.Session
+
+	// sched 写调度器（未启用时为 nil）
+	sched *scheduledConn
 }
 
 // 确保实现接口
-var _ pkgif.MuxedConn = (*muxedConn)(nil)
+var (
+	_ pkgif.MuxedConn         = (*muxedConn)(nil)
+	_ pkgif.PriorityMuxedConn = (*muxedConn)(nil)
+)
 
-// OpenStream 打开新流
+// OpenStream 打开新流（普通优先级）
 func (c *muxedConn) OpenStream(ctx context.Context) (pkgif.MuxedStream, error) {
-	logger.Debug("打开多路复用流")
+	return c.OpenStreamWithPriority(ctx, pkgif.StreamPriorityNormal)
+}
+
+// OpenStreamWithPriority 打开指定优先级的新流
+//
+// 未启用写调度时优先级被忽略。
+func (c *muxedConn) OpenStreamWithPriority(ctx context.Context, priority pkgif.StreamPriority) (pkgif.MuxedStream, error) {
+	logger.Debug("打开多路复用流", "priority", priority.String())
 	s, err := c.session.OpenStream(ctx)
 	if err != nil {
 		logger.Warn("打开流失败", "error", err)
 		return nil, parseError(err)
 	}
 
+	prio := priorityIndex(priority)
+	if c.sched != nil && prio != int(pkgif.StreamPriorityNormal) {
+		c.sched.setPriority(s.StreamID(), prio)
+	}
+
 	logger.Debug("流打开成功")
-	return &muxedStream{stream: s}, nil
+	return &muxedStream{stream: s, sched: c.sched, priority: prio}, nil
+}
+
+// SupportsStreamPriority 检查是否启用了写调度
+func (c *muxedConn) SupportsStreamPriority() bool {
+	return c.sched != nil
+}
+
+// StreamPriorityStats 返回各优先级写队列的统计
+func (c *muxedConn) StreamPriorityStats() []pkgif.StreamPriorityStats {
+	if c.sched == nil {
+		return nil
+	}
+	return c.sched.stats()
 }
 
 // AcceptStream 接受新流
@@ -39,7 +70,7 @@ func (c *muxedConn) AcceptStream() (pkgif.MuxedStream, error) {
 		return nil, parseError(err)
 	}
 
-	return &muxedStream{stream: s}, nil
+	return &muxedStream{stream: s, sched: c.sched, priority: int(pkgif.StreamPriorityNormal)}, nil
 }
 
 // Close 关闭连接
@@ -79,6 +79,30 @@
 //   - ReserveMemory(size int, prio uint8) error
 //   - ReleaseMemory(size int)
 //
+// # 流优先级调度
+//
+// yamux 本身按写入顺序发送帧，TCP 和中继连接上的共识消息会排在大块同步数据之后。
+// NewConn 在底层连接上插入写调度器（scheduler.go），使 StreamPriority 在这些连接上生效：
+//   - 帧按流 ID 进入 Critical/High/Normal/Low 四个队列，连接写得动时不排队
+//   - SchedulerWeighted（默认）按 8:4:2:1 权重差额轮询，SchedulerStrict 严格按优先级
+//   - 流写入按 16KiB 分片，本优先级排队超过 256KiB 时等待，低优先级不会占满发送通道
+//   - ping、go away 和不带标志的窗口更新进入最高优先级队列，同一流的帧始终保持顺序
+//   - 本端发送 FIN/RST、对端发来 RST 或本端关闭已被重置的流后，释放流的调度状态
+//
+// 使用方式：
+//
+//	pc := muxedConn.(pkgif.PriorityMuxedConn)
+//	stream, _ := pc.OpenStreamWithPriority(ctx, pkgif.StreamPriorityCritical)
+//
+//	// 各优先级队列统计：排队帧数/字节、发送量、限流次数、排队延迟
+//	for _, s := range pc.StreamPriorityStats() {
+//	    fmt.Println(s.Priority, s.QueuedBytes, s.AvgQueueDelay)
+//	}
+//
+// 中继电路在 STOP 流上叠加同样的 muxer，因此调度同样作用于本端写入电路的数据。
+// SwarmConn 转发 StreamPriorityStats，Host.StreamPriorityStats 汇总所有连接，
+// 并通过 /debug/introspect/bandwidth 输出。
+//
 // # 流使用规范
 //
 // 正确关闭流：
@@ -11,8 +11,9 @@ import (
 
 // Config 多路复用器配置
 type Config struct {
-	MaxStreamWindowSize uint32        // 最大流窗口大小
-	KeepAliveInterval   time.Duration // 心跳间隔
+	MaxStreamWindowSize uint32          // 最大流窗口大小
+	KeepAliveInterval   time.Duration   // 心跳间隔
+	Scheduler           SchedulerPolicy // 流写调度策略
 }
 
 // DefaultConfig 返回默认配置
@@ -20,6 +21,7 @@ func DefaultConfig() Config {
 	return Config{
 		MaxStreamWindowSize: 16 * 1024 * 1024, // 16MB
 		KeepAliveInterval:   30 * time.Second, // 30 秒
+		Scheduler:           SchedulerWeighted,
 	}
 }
 
@@ -32,6 +34,7 @@ func ConfigFromUnified(cfg *config.Config) Config {
 	return Config{
 		MaxStreamWindowSize: 16 * 1024 * 1024, // 默认 16MB
 		KeepAliveInterval:   cfg.Transport.QUIC.MaxIdleTimeout.Duration() / 2,
+		Scheduler:           SchedulerWeighted,
 	}
 }
 
@@ -59,8 +62,11 @@ func NewTransportFromParams(p Params) *Transport {
 }
 
 // NewTransportWithConfig 使用配置创建 Transport
-func NewTransportWithConfig(_ Config) *Transport {
+func NewTransportWithConfig(cfg Config) *Transport {
 	t := NewTransport()
 	// 应用配置（如果 Transport 支持）
+	if cfg.Scheduler != t.scheduler {
+		t = &Transport{config: t.config, scheduler: cfg.Scheduler}
+	}
 	return t
 }
@@ -0,0 +1,519 @@
+package muxer
+
+import (
+	"encoding/binary"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-yamux/v5"
+
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+)
+
+// SchedulerPolicy 流写入调度策略
+type SchedulerPolicy int
+
+const (
+	// SchedulerWeighted 加权公平调度（默认）
+	//
+	// 各优先级按 8:4:2:1 的权重做差额轮询（DRR），低优先级流不会被饿死。
+	SchedulerWeighted SchedulerPolicy = iota
+
+	// SchedulerStrict 严格优先级调度
+	//
+	// 高优先级队列非空时不发送低优先级帧。
+	SchedulerStrict
+
+	// SchedulerNone 不调度，帧按 yamux 的写入顺序发送
+	SchedulerNone
+)
+
+// String 返回策略名称
+func (p SchedulerPolicy) String() string {
+	switch p {
+	case SchedulerWeighted:
+		return "weighted"
+	case SchedulerStrict:
+		return "strict"
+	case SchedulerNone:
+		return "none"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	// numPriorities 优先级数量（Critical..Low）
+	numPriorities = int(pkgif.StreamPriorityLow) + 1
+
+	// schedulerQuantum 加权调度每轮的基础配额（字节）
+	schedulerQuantum = 4 * 1024
+
+	// schedulerChunkSize 流写入的分片大小，每片写入前重新检查队列
+	schedulerChunkSize = 16 * 1024
+
+	// priorityQueueLimit 单个优先级排队字节上限，超过后该优先级的流写入等待
+	priorityQueueLimit = 256 * 1024
+
+	// maxBufferedBytes 调度器缓冲字节硬上限（控制帧也受限）
+	maxBufferedBytes = 8 * 1024 * 1024
+
+	// closeFlushTimeout 关闭时发送剩余帧的最长时间
+	closeFlushTimeout = time.Second
+)
+
+// priorityWeights 加权调度的各优先级权重
+var priorityWeights = [numPriorities]int{8, 4, 2, 1}
+
+// yamux 帧头（version, type, flags, streamID, length）
+const (
+	frameHeaderSize = 12
+
+	frameTypeData         = 0
+	frameTypeWindowUpdate = 1
+
+	frameFlagSYN = 1
+	frameFlagACK = 2
+	frameFlagFIN = 4
+	frameFlagRST = 8
+)
+
+// priorityIndex 将优先级转换为队列下标，越界值按最近的优先级处理
+func priorityIndex(p pkgif.StreamPriority) int {
+	if p < pkgif.StreamPriorityCritical {
+		return int(pkgif.StreamPriorityCritical)
+	}
+	if p > pkgif.StreamPriorityLow {
+		return int(pkgif.StreamPriorityLow)
+	}
+	return int(p)
+}
+
+// schedFrame 待发送的 yamux 帧
+type schedFrame struct {
+	buf      []byte
+	enqueued time.Time
+}
+
+// priorityQueue 单个优先级的帧队列
+type priorityQueue struct {
+	frames  []schedFrame
+	bytes   int
+	deficit int
+
+	// 统计
+	sentFrames uint64
+	sentBytes  uint64
+	throttled  uint64
+	totalDelay time.Duration
+	maxDelay   time.Duration
+}
+
+// streamEntry 流的调度状态
+//
+// 同一流的帧必须按顺序发送。流优先级变化时，已排队的帧仍在原队列，
+// 新帧在原队列清空后才进入新队列。
+type streamEntry struct {
+	priority int  // 流当前的优先级
+	queued   int  // 已排队帧所在的队列
+	pending  int  // 已排队的帧数
+	finished bool // 已发送 FIN/RST，或流已被本端关闭、对端重置
+}
+
+// frameObserver 解析入站字节流中的 yamux 帧头
+//
+// 入站帧跨越多次 Read，按帧头中的长度跳过数据帧的负载。
+type frameObserver struct {
+	hdr  [frameHeaderSize]byte
+	hdrN int    // hdr 中已读取的字节数
+	skip uint32 // 当前数据帧剩余的负载字节数
+}
+
+// next 消费 b 中的字节，对每个完整的帧头调用 fn
+func (o *frameObserver) next(b []byte, fn func(hdr []byte)) {
+	for len(b) > 0 {
+		if o.skip > 0 {
+			n := uint32(len(b))
+			if n > o.skip {
+				n = o.skip
+			}
+			o.skip -= n
+			b = b[n:]
+			continue
+		}
+
+		n := copy(o.hdr[o.hdrN:], b)
+		o.hdrN += n
+		b = b[n:]
+		if o.hdrN < frameHeaderSize {
+			return
+		}
+		o.hdrN = 0
+		fn(o.hdr[:])
+		if o.hdr[1] == frameTypeData {
+			o.skip = binary.BigEndian.Uint32(o.hdr[8:12])
+		}
+	}
+}
+
+// scheduledConn 按流优先级调度 yamux 帧的连接包装
+//
+// yamux 的发送循环每次 Write 一个完整帧。scheduledConn 解析帧头中的流 ID，
+// 把帧放入对应优先级的队列后立即返回，由写循环按调度策略发送到底层连接。
+// 只有底层连接写不动（拥塞）时才会排队，此时高优先级帧先于低优先级帧发送。
+//
+// 会话级帧（ping、go away）和不带标志的窗口更新不影响流内顺序，直接进入最高优先级队列。
+type scheduledConn struct {
+	net.Conn
+
+	policy SchedulerPolicy
+
+	mu       sync.Mutex
+	ready    *sync.Cond    // 有帧待发送或正在关闭
+	drained  chan struct{} // 有帧发送完成时关闭并替换，唤醒等待的写入
+	queues   [numPriorities]priorityQueue
+	streams  map[uint32]*streamEntry
+	buffered int
+	cursor   int
+	closing  bool
+	err      error
+
+	// 入站帧解析（仅由 yamux 的接收循环调用 Read）
+	observer frameObserver
+
+	done      chan struct{}
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// newScheduledConn 包装连接并启动写循环
+func newScheduledConn(conn net.Conn, policy SchedulerPolicy) *scheduledConn {
+	c := &scheduledConn{
+		Conn:    conn,
+		policy:  policy,
+		drained: make(chan struct{}),
+		streams: make(map[uint32]*streamEntry),
+		done:    make(chan struct{}),
+	}
+	c.ready = sync.NewCond(&c.mu)
+	go c.writeLoop()
+	return c
+}
+
+// Read 从底层连接读取，并跟踪对端发来的 RST
+//
+// 对端重置流后 yamux 不会再发送该流的帧，需要在这里释放流的调度状态。
+// 对端 FIN 只关闭读方向，本端仍可按原优先级写入，由本端关闭时释放。
+func (c *scheduledConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		c.observer.next(b[:n], c.observeInbound)
+	}
+	return n, err
+}
+
+// observeInbound 处理一个入站帧头
+func (c *scheduledConn) observeInbound(hdr []byte) {
+	typ := hdr[1]
+	if typ != frameTypeData && typ != frameTypeWindowUpdate {
+		return
+	}
+	if binary.BigEndian.Uint16(hdr[2:4])&frameFlagRST == 0 {
+		return
+	}
+	c.release(binary.BigEndian.Uint32(hdr[4:8]))
+}
+
+// release 释放流的调度状态
+//
+// 流已关闭或重置时调用。流仍有排队的帧时，等这些帧发送后再删除。
+func (c *scheduledConn) release(id uint32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e := c.streams[id]
+	if e == nil {
+		return
+	}
+	if e.pending == 0 {
+		delete(c.streams, id)
+		return
+	}
+	e.finished = true
+}
+
+// Write 将一个 yamux 帧放入调度队列
+func (c *scheduledConn) Write(b []byte) (int, error) {
+	c.mu.Lock()
+	for c.err == nil && !c.closing && c.buffered >= maxBufferedBytes {
+		ch := c.drained
+		c.mu.Unlock()
+		<-ch
+		c.mu.Lock()
+	}
+	if c.err != nil {
+		err := c.err
+		c.mu.Unlock()
+		return 0, err
+	}
+	if c.closing {
+		c.mu.Unlock()
+		return 0, net.ErrClosed
+	}
+
+	buf := make([]byte, len(b))
+	copy(buf, b)
+
+	q := &c.queues[c.classifyLocked(buf)]
+	q.frames = append(q.frames, schedFrame{buf: buf, enqueued: time.Now()})
+	q.bytes += len(buf)
+	c.buffered += len(buf)
+
+	c.ready.Signal()
+	c.mu.Unlock()
+	return len(b), nil
+}
+
+// classifyLocked 返回帧应进入的队列（调用方持有 c.mu）
+func (c *scheduledConn) classifyLocked(buf []byte) int {
+	critical := int(pkgif.StreamPriorityCritical)
+	if len(buf) < frameHeaderSize {
+		return critical
+	}
+
+	typ := buf[1]
+	flags := binary.BigEndian.Uint16(buf[2:4])
+	id := binary.BigEndian.Uint32(buf[4:8])
+
+	if id == 0 || (typ != frameTypeData && typ != frameTypeWindowUpdate) {
+		return critical
+	}
+	if typ == frameTypeWindowUpdate && flags == 0 {
+		return critical
+	}
+
+	e := c.streams[id]
+	if e == nil {
+		e = &streamEntry{priority: int(pkgif.StreamPriorityNormal)}
+		c.streams[id] = e
+	}
+	if e.pending == 0 {
+		e.queued = e.priority
+	}
+	e.pending++
+	if flags&(frameFlagFIN|frameFlagRST) != 0 {
+		e.finished = true
+	}
+	return e.queued
+}
+
+// setPriority 设置流的优先级
+func (c *scheduledConn) setPriority(id uint32, prio int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e := c.streams[id]
+	if e == nil {
+		e = &streamEntry{queued: prio}
+		c.streams[id] = e
+	}
+	e.priority = prio
+}
+
+// admit 等待优先级 prio 的队列有空间
+//
+// 流写入每个分片前调用：该优先级排队的字节超过上限时阻塞，
+// 避免低优先级的大量写入占满 yamux 发送通道，拖慢高优先级的帧。
+func (c *scheduledConn) admit(prio int, deadline time.Time) error {
+	var timeout <-chan time.Time
+	for {
+		c.mu.Lock()
+		if c.err != nil || c.closing {
+			c.mu.Unlock()
+			return ErrConnClosed
+		}
+		q := &c.queues[prio]
+		if q.bytes < priorityQueueLimit {
+			c.mu.Unlock()
+			return nil
+		}
+		if timeout == nil {
+			q.throttled++
+		}
+		ch := c.drained
+		c.mu.Unlock()
+
+		if timeout == nil && !deadline.IsZero() {
+			timer := time.NewTimer(time.Until(deadline))
+			defer timer.Stop()
+			timeout = timer.C
+		}
+		select {
+		case <-ch:
+		case <-timeout:
+			return yamux.ErrTimeout
+		}
+	}
+}
+
+// writeLoop 按调度策略将排队的帧写入底层连接
+func (c *scheduledConn) writeLoop() {
+	defer close(c.done)
+
+	for {
+		c.mu.Lock()
+		for c.buffered == 0 && !c.closing {
+			c.ready.Wait()
+		}
+		if c.buffered == 0 || c.err != nil {
+			c.mu.Unlock()
+			return
+		}
+		prio, f := c.nextLocked()
+		c.mu.Unlock()
+
+		_, err := c.Conn.Write(f.buf)
+		delay := time.Since(f.enqueued)
+
+		c.mu.Lock()
+		q := &c.queues[prio]
+		q.bytes -= len(f.buf)
+		c.buffered -= len(f.buf)
+		q.sentFrames++
+		q.sentBytes += uint64(len(f.buf))
+		q.totalDelay += delay
+		if delay > q.maxDelay {
+			q.maxDelay = delay
+		}
+		if err != nil {
+			c.failLocked(err)
+		}
+		close(c.drained)
+		c.drained = make(chan struct{})
+		c.mu.Unlock()
+
+		if err != nil {
+			c.Conn.Close()
+			return
+		}
+	}
+}
+
+// nextLocked 取出下一个要发送的帧（调用方持有 c.mu，且队列非空）
+//
+// 帧出队后计数仍保留在队列中，直到写入完成，使排队字节反映底层连接的拥塞程度。
+func (c *scheduledConn) nextLocked() (int, schedFrame) {
+	prio := c.pickLocked()
+	q := &c.queues[prio]
+	f := q.frames[0]
+	q.frames[0] = schedFrame{}
+	q.frames = q.frames[1:]
+	c.frameSentLocked(f.buf)
+	return prio, f
+}
+
+// pickLocked 按调度策略选择队列
+func (c *scheduledConn) pickLocked() int {
+	if c.policy == SchedulerStrict {
+		for i := range c.queues {
+			if len(c.queues[i].frames) > 0 {
+				return i
+			}
+		}
+	}
+
+	// 差额轮询：轮到的队列增加 权重×配额，配额足够发送队首帧时出队
+	for {
+		q := &c.queues[c.cursor]
+		if len(q.frames) > 0 && q.deficit >= len(q.frames[0].buf) {
+			q.deficit -= len(q.frames[0].buf)
+			return c.cursor
+		}
+		if len(q.frames) == 0 {
+			q.deficit = 0
+		}
+
+		c.cursor = (c.cursor + 1) % numPriorities
+		if next := &c.queues[c.cursor]; len(next.frames) > 0 {
+			next.deficit += priorityWeights[c.cursor] * schedulerQuantum
+		}
+	}
+}
+
+// frameSentLocked 更新帧所属流的调度状态（调用方持有 c.mu）
+func (c *scheduledConn) frameSentLocked(buf []byte) {
+	if len(buf) < frameHeaderSize {
+		return
+	}
+	e := c.streams[binary.BigEndian.Uint32(buf[4:8])]
+	if e == nil || e.pending == 0 {
+		return
+	}
+	e.pending--
+	if e.pending == 0 && e.finished {
+		delete(c.streams, binary.BigEndian.Uint32(buf[4:8]))
+	}
+}
+
+// failLocked 记录写入错误并丢弃排队的帧（调用方持有 c.mu）
+func (c *scheduledConn) failLocked(err error) {
+	if c.err == nil {
+		c.err = err
+	}
+	for i := range c.queues {
+		q := &c.queues[i]
+		q.frames = nil
+		c.buffered -= q.bytes
+		q.bytes = 0
+	}
+	c.streams = make(map[uint32]*streamEntry)
+}
+
+// Close 发送剩余的帧后关闭底层连接
+//
+// 剩余帧最多发送 closeFlushTimeout，对端不读取时不会阻塞关闭。
+func (c *scheduledConn) Close() error {
+	c.closeOnce.Do(func() {
+		c.mu.Lock()
+		c.closing = true
+		c.ready.Broadcast()
+		close(c.drained)
+		c.drained = make(chan struct{})
+		c.mu.Unlock()
+
+		_ = c.Conn.SetWriteDeadline(time.Now().Add(closeFlushTimeout))
+		select {
+		case <-c.done:
+		case <-time.After(closeFlushTimeout):
+		}
+		c.closeErr = c.Conn.Close()
+		<-c.done
+	})
+	return c.closeErr
+}
+
+// stats 返回各优先级的调度统计
+func (c *scheduledConn) stats() []pkgif.StreamPriorityStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := make([]pkgif.StreamPriorityStats, numPriorities)
+	for i := range c.queues {
+		q := &c.queues[i]
+		s := pkgif.StreamPriorityStats{
+			Priority:      pkgif.StreamPriority(i),
+			QueuedFrames:  len(q.frames),
+			QueuedBytes:   int64(q.bytes),
+			SentFrames:    q.sentFrames,
+			SentBytes:     q.sentBytes,
+			Throttled:     q.throttled,
+			MaxQueueDelay: q.maxDelay,
+		}
+		if q.sentFrames > 0 {
+			s.AvgQueueDelay = q.totalDelay / time.Duration(q.sentFrames)
+		}
+		stats[i] = s
+	}
+	return stats
+}
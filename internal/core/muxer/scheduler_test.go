@@ -0,0 +1,480 @@
+package muxer
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+)
+
+// ============================================================================
+// 写调度测试
+// ============================================================================
+
+// gatedConn 记录写入的帧，放行前阻塞写入
+type gatedConn struct {
+	net.Conn
+
+	gate chan struct{}
+
+	mu     sync.Mutex
+	frames [][]byte
+}
+
+func newGatedConn() *gatedConn {
+	return &gatedConn{gate: make(chan struct{})}
+}
+
+func (c *gatedConn) Write(b []byte) (int, error) {
+	<-c.gate
+	c.mu.Lock()
+	c.frames = append(c.frames, append([]byte(nil), b...))
+	c.mu.Unlock()
+	return len(b), nil
+}
+
+func (c *gatedConn) SetWriteDeadline(time.Time) error { return nil }
+
+func (c *gatedConn) Close() error { return nil }
+
+// written 返回已写入帧的流 ID
+func (c *gatedConn) written() []uint32 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ids := make([]uint32, len(c.frames))
+	for i, f := range c.frames {
+		ids[i] = binary.BigEndian.Uint32(f[4:8])
+	}
+	return ids
+}
+
+// testFrame 构造 yamux 数据帧
+func testFrame(id uint32, size int) []byte {
+	buf := make([]byte, frameHeaderSize+size)
+	buf[1] = frameTypeData
+	binary.BigEndian.PutUint32(buf[4:8], id)
+	binary.BigEndian.PutUint32(buf[8:12], uint32(size))
+	return buf
+}
+
+// waitQueued 等待调度器排队的帧数
+func waitQueued(t *testing.T, c *scheduledConn, frames int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		queued := 0
+		for _, s := range c.stats() {
+			queued += s.QueuedFrames
+		}
+		if queued == frames {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("queued frames did not reach %d", frames)
+}
+
+// waitFlushed 等待调度器写完所有帧
+func waitFlushed(t *testing.T, c *scheduledConn) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		c.mu.Lock()
+		buffered := c.buffered
+		c.mu.Unlock()
+		if buffered == 0 {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("scheduler did not flush")
+}
+
+// TestScheduledConn_Strict 测试严格优先级：拥塞时关键帧先于低优先级帧发送
+func TestScheduledConn_Strict(t *testing.T) {
+	gc := newGatedConn()
+	c := newScheduledConn(gc, SchedulerStrict)
+	defer c.Close()
+
+	c.setPriority(1, int(pkgif.StreamPriorityLow))
+	c.setPriority(3, int(pkgif.StreamPriorityCritical))
+
+	// 第一帧进入写循环后阻塞，其余帧排队
+	for i := 0; i < 4; i++ {
+		c.Write(testFrame(1, 1024))
+	}
+	waitQueued(t, c, 3)
+	c.Write(testFrame(3, 64))
+	c.Write(testFrame(3, 64))
+
+	close(gc.gate)
+	waitFlushed(t, c)
+
+	want := []uint32{1, 3, 3, 1, 1, 1}
+	got := gc.written()
+	if len(got) != len(want) {
+		t.Fatalf("written %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("written %v, want %v", got, want)
+		}
+	}
+
+	stats := c.stats()
+	if stats[pkgif.StreamPriorityCritical].SentFrames != 2 {
+		t.Errorf("critical SentFrames = %d, want 2", stats[pkgif.StreamPriorityCritical].SentFrames)
+	}
+	if stats[pkgif.StreamPriorityLow].SentFrames != 4 {
+		t.Errorf("low SentFrames = %d, want 4", stats[pkgif.StreamPriorityLow].SentFrames)
+	}
+}
+
+// TestScheduledConn_Weighted 测试加权调度：积压时按权重分配带宽
+func TestScheduledConn_Weighted(t *testing.T) {
+	gc := newGatedConn()
+	c := newScheduledConn(gc, SchedulerWeighted)
+	defer c.Close()
+
+	c.setPriority(1, int(pkgif.StreamPriorityLow))
+	// 流 3 使用默认的普通优先级
+
+	c.Write(testFrame(1, 4096))
+	waitQueued(t, c, 0)
+	for i := 0; i < 30; i++ {
+		c.Write(testFrame(1, 4096))
+		c.Write(testFrame(3, 4096))
+	}
+
+	close(gc.gate)
+	waitFlushed(t, c)
+
+	// 去掉阻塞的第一帧后，前 30 帧中普通与低优先级约为 2:1
+	var normal, low int
+	for _, id := range gc.written()[1:31] {
+		if id == 3 {
+			normal++
+		} else {
+			low++
+		}
+	}
+	if normal < 18 || low < 8 {
+		t.Errorf("normal=%d low=%d, want about 2:1", normal, low)
+	}
+}
+
+// TestScheduledConn_PriorityChangeKeepsOrder 测试优先级变化时同一流的帧保持顺序
+func TestScheduledConn_PriorityChangeKeepsOrder(t *testing.T) {
+	gc := newGatedConn()
+	c := newScheduledConn(gc, SchedulerStrict)
+	defer c.Close()
+
+	c.setPriority(7, int(pkgif.StreamPriorityLow))
+	c.Write(testFrame(5, 16)) // 阻塞在写循环中
+	waitQueued(t, c, 0)
+
+	first := testFrame(7, 16)
+	first[12] = 1
+	c.Write(first)
+
+	// 已排队的帧仍在低优先级队列，后续帧不能越过它
+	c.setPriority(7, int(pkgif.StreamPriorityCritical))
+	second := testFrame(7, 16)
+	second[12] = 2
+	c.Write(second)
+
+	close(gc.gate)
+	waitFlushed(t, c)
+
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	if len(gc.frames) != 3 || gc.frames[1][12] != 1 || gc.frames[2][12] != 2 {
+		t.Fatal("frames of the same stream were reordered")
+	}
+}
+
+// chunkedConn 按固定大小分片返回预置的入站字节
+type chunkedConn struct {
+	net.Conn
+
+	data  []byte
+	chunk int
+}
+
+func (c *chunkedConn) Read(b []byte) (int, error) {
+	if len(c.data) == 0 {
+		return 0, io.EOF
+	}
+	n := min(c.chunk, len(b), len(c.data))
+	copy(b, c.data[:n])
+	c.data = c.data[n:]
+	return n, nil
+}
+
+func (c *chunkedConn) SetWriteDeadline(time.Time) error { return nil }
+
+func (c *chunkedConn) Close() error { return nil }
+
+// streamCount 返回调度器跟踪的流数量
+func streamCount(c *scheduledConn) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.streams)
+}
+
+// tracked 检查调度器是否跟踪流
+func tracked(c *scheduledConn, id uint32) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.streams[id]
+	return ok
+}
+
+// waitStreams 等待调度器跟踪的流数量
+func waitStreams(t *testing.T, c *scheduledConn, want int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if streamCount(c) == want {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("tracked streams = %d, want %d", streamCount(c), want)
+}
+
+// TestScheduledConn_ReleaseOnRemoteReset 测试对端 RST 释放流状态，帧头跨越多次读取
+func TestScheduledConn_ReleaseOnRemoteReset(t *testing.T) {
+	var in []byte
+	in = append(in, testFrame(3, 100)...) // 负载中不能被当作帧头解析
+	rst := testFrame(5, 0)
+	binary.BigEndian.PutUint16(rst[2:4], frameFlagRST)
+	in = append(in, rst...)
+	fin := testFrame(7, 0)
+	binary.BigEndian.PutUint16(fin[2:4], frameFlagFIN)
+	in = append(in, fin...)
+
+	cc := &chunkedConn{data: in, chunk: 5}
+	c := newScheduledConn(cc, SchedulerStrict)
+	defer c.Close()
+
+	c.setPriority(3, int(pkgif.StreamPriorityLow))
+	c.setPriority(5, int(pkgif.StreamPriorityLow))
+	c.setPriority(7, int(pkgif.StreamPriorityLow))
+
+	buf := make([]byte, 64)
+	for {
+		if _, err := c.Read(buf); err != nil {
+			break
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.streams[5]; ok {
+		t.Error("stream reset by remote should be released")
+	}
+	// 对端 FIN 只关闭读方向，本端仍可按优先级写入
+	if _, ok := c.streams[3]; !ok {
+		t.Error("stream 3 should still be tracked")
+	}
+	if _, ok := c.streams[7]; !ok {
+		t.Error("stream half-closed by remote should still be tracked")
+	}
+}
+
+// TestScheduledConn_ReleaseWaitsForQueuedFrames 测试释放时排队的帧发送后才删除流状态
+func TestScheduledConn_ReleaseWaitsForQueuedFrames(t *testing.T) {
+	gc := newGatedConn()
+	c := newScheduledConn(gc, SchedulerStrict)
+	defer c.Close()
+
+	c.setPriority(1, int(pkgif.StreamPriorityLow))
+	c.Write(testFrame(9, 16)) // 阻塞在写循环中
+	waitQueued(t, c, 0)
+	c.Write(testFrame(1, 16))
+	c.Write(testFrame(1, 16))
+
+	c.release(1)
+	kept := tracked(c, 1)
+
+	close(gc.gate)
+	waitFlushed(t, c)
+	if !kept {
+		t.Fatal("stream with queued frames should be kept until they are sent")
+	}
+	if tracked(c, 1) {
+		t.Fatal("stream should be released after its frames are sent")
+	}
+}
+
+// TestConn_PriorityStreamRelease 测试本端和对端关闭、重置后释放流状态
+func TestConn_PriorityStreamRelease(t *testing.T) {
+	transport := NewTransport()
+
+	clientConn, serverConn := testConnPair(t)
+	defer clientConn.Close()
+	defer serverConn.Close()
+
+	client, err := transport.NewConn(clientConn, false, nil)
+	if err != nil {
+		t.Fatalf("NewConn() failed: %v", err)
+	}
+	defer client.Close()
+
+	server, err := transport.NewConn(serverConn, true, nil)
+	if err != nil {
+		t.Fatalf("NewConn() failed: %v", err)
+	}
+	defer server.Close()
+
+	pc := client.(pkgif.PriorityMuxedConn)
+	sched := client.(*muxedConn).sched
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	open := func() (pkgif.MuxedStream, pkgif.MuxedStream) {
+		t.Helper()
+		stream, err := pc.OpenStreamWithPriority(ctx, pkgif.StreamPriorityLow)
+		if err != nil {
+			t.Fatalf("OpenStreamWithPriority() failed: %v", err)
+		}
+		if _, err := stream.Write([]byte("x")); err != nil {
+			t.Fatalf("Write() failed: %v", err)
+		}
+		accepted, err := server.AcceptStream()
+		if err != nil {
+			t.Fatalf("AcceptStream() failed: %v", err)
+		}
+		return stream, accepted
+	}
+
+	// 对端重置
+	stream, accepted := open()
+	waitStreams(t, sched, 1)
+	accepted.Reset()
+	waitStreams(t, sched, 0)
+	stream.Close()
+	waitStreams(t, sched, 0)
+
+	// 对端关闭后本端关闭
+	stream, accepted = open()
+	waitStreams(t, sched, 1)
+	accepted.Close()
+	time.Sleep(20 * time.Millisecond)
+	if streamCount(sched) != 1 {
+		t.Fatal("stream half-closed by remote should still be tracked")
+	}
+	stream.Close()
+	waitStreams(t, sched, 0)
+
+	// 本端重置
+	stream, accepted = open()
+	waitStreams(t, sched, 1)
+	stream.Reset()
+	waitStreams(t, sched, 0)
+	accepted.Close()
+}
+
+// TestConn_OpenStreamWithPriority 测试优先级流的读写和统计
+func TestConn_OpenStreamWithPriority(t *testing.T) {
+	transport := NewTransport()
+
+	clientConn, serverConn := testConnPair(t)
+	defer clientConn.Close()
+	defer serverConn.Close()
+
+	client, err := transport.NewConn(clientConn, false, nil)
+	if err != nil {
+		t.Fatalf("NewConn() failed: %v", err)
+	}
+	defer client.Close()
+
+	server, err := transport.NewConn(serverConn, true, nil)
+	if err != nil {
+		t.Fatalf("NewConn() failed: %v", err)
+	}
+	defer server.Close()
+
+	pc, ok := client.(pkgif.PriorityMuxedConn)
+	if !ok || !pc.SupportsStreamPriority() {
+		t.Fatal("muxed conn should support stream priority")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := pc.OpenStreamWithPriority(ctx, pkgif.StreamPriorityCritical)
+	if err != nil {
+		t.Fatalf("OpenStreamWithPriority() failed: %v", err)
+	}
+	defer stream.Close()
+
+	// 超过分片大小，验证分片写入
+	data := make([]byte, 3*schedulerChunkSize+100)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	go func() {
+		stream.Write(data)
+	}()
+
+	accepted, err := server.AcceptStream()
+	if err != nil {
+		t.Fatalf("AcceptStream() failed: %v", err)
+	}
+	defer accepted.Close()
+
+	buf := make([]byte, len(data))
+	if _, err := io.ReadFull(accepted, buf); err != nil {
+		t.Fatalf("ReadFull() failed: %v", err)
+	}
+	for i := range buf {
+		if buf[i] != data[i] {
+			t.Fatalf("data mismatch at %d", i)
+		}
+	}
+
+	// 统计在底层写入返回后更新
+	waitFlushed(t, client.(*muxedConn).sched)
+	stats := pc.StreamPriorityStats()
+	if len(stats) != numPriorities {
+		t.Fatalf("len(stats) = %d, want %d", len(stats), numPriorities)
+	}
+	if stats[pkgif.StreamPriorityCritical].SentBytes < uint64(len(data)) {
+		t.Errorf("critical SentBytes = %d, want >= %d", stats[pkgif.StreamPriorityCritical].SentBytes, len(data))
+	}
+}
+
+// TestTransport_SchedulerNone 测试关闭写调度
+func TestTransport_SchedulerNone(t *testing.T) {
+	transport := NewTransportWithConfig(Config{Scheduler: SchedulerNone})
+
+	clientConn, serverConn := testConnPair(t)
+	defer clientConn.Close()
+	defer serverConn.Close()
+
+	client, err := transport.NewConn(clientConn, false, nil)
+	if err != nil {
+		t.Fatalf("NewConn() failed: %v", err)
+	}
+	defer client.Close()
+
+	pc := client.(pkgif.PriorityMuxedConn)
+	if pc.SupportsStreamPriority() {
+		t.Error("SupportsStreamPriority() = true, want false")
+	}
+	if pc.StreamPriorityStats() != nil {
+		t.Error("StreamPriorityStats() should be nil without scheduler")
+	}
+	if NewTransport().Scheduler() != SchedulerWeighted {
+		t.Error("default scheduler should be weighted")
+	}
+}
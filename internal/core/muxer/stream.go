@@ -1,6 +1,7 @@
 package muxer
 
 import (
+	"sync/atomic"
 	"time"
 
 	"github.com/libp2p/go-yamux/v5"
@@ -11,6 +12,11 @@ import (
 // muxedStream 包装 yamux.Stream，实现 MuxedStream 接口
 type muxedStream struct {
 	stream *yamux.Stream
+
+	// 写调度（sched 为 nil 时直接写入）
+	sched         *scheduledConn
+	priority      int
+	writeDeadline atomic.Int64 // UnixNano，0 表示无截止时间
 }
 
 // 确保实现接口
@@ -23,19 +29,72 @@ func (s *muxedStream) Read(p []byte) (n int, err error) {
 }
 
 // Write 向流中写入数据
+//
+// 启用写调度时按 schedulerChunkSize 分片写入，每片写入前等待本优先级的队列有空间。
 func (s *muxedStream) Write(p []byte) (n int, err error) {
-	n, err = s.stream.Write(p)
-	return n, parseError(err)
+	if s.sched == nil {
+		n, err = s.stream.Write(p)
+		return n, parseError(err)
+	}
+
+	for n < len(p) {
+		if err = s.sched.admit(s.priority, s.deadline()); err != nil {
+			return n, err
+		}
+		end := min(n+schedulerChunkSize, len(p))
+		m, err := s.stream.Write(p[n:end])
+		n += m
+		if err != nil {
+			return n, parseError(err)
+		}
+	}
+	return n, nil
+}
+
+// deadline 返回写截止时间
+func (s *muxedStream) deadline() time.Time {
+	if d := s.writeDeadline.Load(); d != 0 {
+		return time.Unix(0, d)
+	}
+	return time.Time{}
+}
+
+// setDeadline 记录写截止时间（供写调度等待使用）
+func (s *muxedStream) setDeadline(t time.Time) {
+	if t.IsZero() {
+		s.writeDeadline.Store(0)
+		return
+	}
+	s.writeDeadline.Store(t.UnixNano())
+}
+
+// release 释放写调度中的流状态
+func (s *muxedStream) release() {
+	if s.sched != nil {
+		s.sched.release(s.stream.StreamID())
+	}
 }
 
 // Close 关闭流（正常关闭）
 func (s *muxedStream) Close() error {
-	return s.stream.Close()
+	return s.closeWith(s.stream.Close)
 }
 
 // CloseWrite 关闭写端
 func (s *muxedStream) CloseWrite() error {
-	return s.stream.CloseWrite()
+	return s.closeWith(s.stream.CloseWrite)
+}
+
+// closeWith 关闭写端
+//
+// 正常关闭时 FIN 帧经过写调度，发送后释放流状态；写端已被重置时
+// yamux 不发送任何帧并返回错误，此时直接释放。
+func (s *muxedStream) closeWith(close func() error) error {
+	err := close()
+	if err != nil {
+		s.release()
+	}
+	return err
 }
 
 // CloseRead 关闭读端
@@ -45,11 +104,13 @@ func (s *muxedStream) CloseRead() error {
 
 // Reset 重置流（异常关闭）
 func (s *muxedStream) Reset() error {
+	defer s.release()
 	return s.stream.Reset()
 }
 
 // SetDeadline 设置读写截止时间
 func (s *muxedStream) SetDeadline(t time.Time) error {
+	s.setDeadline(t)
 	return s.stream.SetDeadline(t)
 }
 
@@ -60,5 +121,6 @@ func (s *muxedStream) SetReadDeadline(t time.Time) error {
 
 // SetWriteDeadline 设置写截止时间
 func (s *muxedStream) SetWriteDeadline(t time.Time) error {
+	s.setDeadline(t)
 	return s.stream.SetWriteDeadline(t)
 }
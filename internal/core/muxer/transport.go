@@ -13,6 +13,9 @@ import (
 // Transport yamux 多路复用器传输
 type Transport struct {
 	config *yamux.Config
+
+	// scheduler 流写调度策略
+	scheduler SchedulerPolicy
 }
 
 // DefaultTransport 默认传输实例
@@ -34,7 +37,7 @@ func init() {
 	// 禁用入站流限制（由 ResourceManager 动态控制）
 	config.MaxIncomingStreams = math.MaxUint32
 	
-	DefaultTransport = &Transport{config: config}
+	DefaultTransport = &Transport{config: config, scheduler: SchedulerWeighted}
 }
 
 // NewTransport 创建新的 Transport
@@ -52,6 +55,13 @@ func (t *Transport) NewConn(conn net.Conn, isServer bool, scope pkgif.PeerScope)
 		}
 	}
 
+	// 写调度：按流优先级发送 yamux 帧
+	var sched *scheduledConn
+	if t.scheduler != SchedulerNone {
+		sched = newScheduledConn(conn, t.scheduler)
+		conn = sched
+	}
+
 	var sess *yamux.Session
 	var err error
 
@@ -62,10 +72,18 @@ func (t *Transport) NewConn(conn net.Conn, isServer bool, scope pkgif.PeerScope)
 	}
 
 	if err != nil {
+		if sched != nil {
+			sched.Close()
+		}
 		return nil, err
 	}
 
-	return &muxedConn{session: sess}, nil
+	return &muxedConn{session: sess, sched: sched}, nil
+}
+
+// Scheduler 返回流写调度策略
+func (t *Transport) Scheduler() SchedulerPolicy {
+	return t.scheduler
 }
 
 // ID 返回多路复用协议标识
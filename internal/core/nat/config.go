@@ -16,6 +16,9 @@ type Config struct {
 	// EnableNATPMP 是否启用 NAT-PMP 端口映射
 	EnableNATPMP bool
 
+	// EnablePCP 是否启用 PCP（RFC 6887）端口映射
+	EnablePCP bool
+
 	// EnableHolePunch 是否启用 Hole Punching
 	EnableHolePunch bool
 
@@ -74,6 +77,10 @@ type Config struct {
 	// UPnPTimeout UPnP 操作超时时间
 	// 默认: 5 秒
 	UPnPTimeout time.Duration
+
+	// PCPTimeout PCP 操作超时时间（探测、端口映射）
+	// 默认: 5 秒
+	PCPTimeout time.Duration
 }
 
 // DefaultConfig 返回默认配置
@@ -82,6 +89,7 @@ func DefaultConfig() *Config {
 		EnableAutoNAT:   true,
 		EnableUPnP:      true,
 		EnableNATPMP:    true,
+		EnablePCP:       true,
 		EnableHolePunch: true,
 		STUNServers: []string{
 			"stun.l.google.com:19302",
@@ -103,6 +111,7 @@ func DefaultConfig() *Config {
 		AutoEnableRelay:         true, // v2.0 默认启用自动 Relay
 		NATPMPTimeout:           5 * time.Second, // 21: 默认 5 秒超时
 		UPnPTimeout:             5 * time.Second, // 默认 5 秒超时
+		PCPTimeout:              5 * time.Second, // 默认 5 秒超时
 	}
 }
 
@@ -184,6 +193,14 @@ func WithNATPMP(enabled bool) Option {
 	}
 }
 
+// WithPCP 设置是否启用 PCP
+func WithPCP(enabled bool) Option {
+	return func(c *Config) error {
+		c.EnablePCP = enabled
+		return nil
+	}
+}
+
 // WithHolePunch 设置是否启用 Hole Punching
 func WithHolePunch(enabled bool) Option {
 	return func(c *Config) error {
@@ -298,6 +315,17 @@ func WithUPnPTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithPCPTimeout 设置 PCP 操作超时时间
+func WithPCPTimeout(timeout time.Duration) Option {
+	return func(c *Config) error {
+		if timeout <= 0 {
+			return errors.New("PCP timeout must be positive")
+		}
+		c.PCPTimeout = timeout
+		return nil
+	}
+}
+
 // ApplyOptions 应用配置选项
 func (c *Config) ApplyOptions(opts ...Option) error {
 	for _, opt := range opts {
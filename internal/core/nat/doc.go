@@ -5,7 +5,7 @@
 // nat 提供 NAT 穿透能力，包括：
 //   - AutoNAT: NAT 类型检测和可达性判断
 //   - STUN: 外部地址获取
-//   - UPnP/NAT-PMP: 自动端口映射 (v1.1+)
+//   - UPnP/NAT-PMP/PCP: 自动端口映射 (v1.1+)
 //   - Hole Punching: UDP 打洞 (v1.1+)
 //
 // # 快速开始
@@ -63,6 +63,7 @@
 //	    EnableAutoNAT:       true,               // 启用 AutoNAT 检测
 //	    EnableUPnP:          true,               // 启用 UPnP 映射
 //	    EnableNATPMP:        true,               // 启用 NAT-PMP 映射
+//	    EnablePCP:           true,               // 启用 PCP 映射
 //	    EnableHolePunch:     true,               // 启用打洞
 //	    STUNServers:         []string{...},      // STUN 服务器列表
 //	    ProbeInterval:       15 * time.Second,   // 探测间隔
@@ -98,6 +99,7 @@
 //   - STUN 客户端（pion/stun v0.6.1，真实实现）
 //   - UPnP 端口映射（huin/goupnp v1.3.0，真实实现）
 //   - NAT-PMP 端口映射（jackpal/go-nat-pmp v1.0.2，真实实现）
+//   - PCP 端口映射（RFC 6887，含 NAT64、纪元检测与 ANNOUNCE 处理）
 //   - Service 生命周期管理
 //   - 配置和错误处理
 //   - 端口映射自动续期
@@ -163,6 +165,8 @@
 //	│   └── MapPort() - 端口映射
 //	├── NATPMPMapper (NAT-PMP 映射器, v1.1)
 //	│   └── MapPort() - 端口映射
+//	├── PCPMapper (PCP 映射器)
+//	│   └── MapPort() - 端口映射
 //	└── HolePuncher (打洞器, v1.1)
 //	    └── DirectConnect() - 直连尝试
 //
@@ -220,6 +224,7 @@
 //   - RFC 5389: STUN (Session Traversal Utilities for NAT)
 //   - RFC 5626: Managing Client-Initiated Connections in SIP
 //   - RFC 6886: NAT-PMP (NAT Port Mapping Protocol)
+//   - RFC 6887: PCP (Port Control Protocol)
 //
 // 架构层：Core Layer
 package nat
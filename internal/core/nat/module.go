@@ -29,6 +29,8 @@ func ConfigFromUnified(cfg *config.Config) *Config {
 		EnableAutoNAT:          cfg.NAT.EnableAutoNAT,
 		EnableUPnP:             cfg.NAT.EnableUPnP,
 		EnableNATPMP:           cfg.NAT.EnableNATPMP,
+		EnablePCP:              cfg.NAT.EnablePCP,
+		PCPTimeout:             cfg.NAT.PCP.Timeout,
 		EnableHolePunch:        cfg.NAT.EnableHolePunch,
 		STUNServers:            cfg.NAT.STUNServers,
 		ProbeInterval:          cfg.NAT.AutoNAT.ProbeInterval,
@@ -80,6 +82,7 @@ func Module() fx.Option {
 		),
 		fx.Invoke(registerLifecycle),
 		fx.Invoke(bindLifecycleCoordinator),
+		fx.Invoke(registerCapabilities),
 	)
 }
 
@@ -119,3 +122,17 @@ func bindLifecycleCoordinator(input lifecycleCoordInput) {
 		input.Service.SetLifecycleCoordinator(input.LifecycleCoordinator)
 	}
 }
+
+// capabilityInput 能力注册参数
+type capabilityInput struct {
+	fx.In
+	Service      *Service
+	Capabilities pkgif.CapabilityRegistry `optional:"true"`
+}
+
+// registerCapabilities 通过 Identify 通告 NAT 类型、可达性和打洞支持
+func registerCapabilities(input capabilityInput) {
+	if input.Capabilities != nil {
+		input.Capabilities.AddCapabilityProvider(input.Service.FillCapabilities)
+	}
+}
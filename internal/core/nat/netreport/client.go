@@ -12,6 +12,9 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/dep2p/go-dep2p/internal/core/nat/pcp"
+	"github.com/dep2p/go-dep2p/pkg/interfaces"
 )
 
 // ============================================================================
@@ -29,6 +32,21 @@ type Client struct {
 
 	// STUN 客户端
 	stunClient *STUNClient
+
+	// 中继探测器（通过中继协议测量 RTT 与负载）
+	relayProber RelayProber
+
+	// 数据预算（可选，计费网络下减少完整探测）
+	dataBudget interfaces.DataBudget
+}
+
+// RelayProber 中继探测器
+//
+// 由中继客户端实现，通过实际的中继协议（而非裸 TCP 连接）探测中继，
+// 因此同样适用于仅监听 QUIC 的中继。
+type RelayProber interface {
+	// ProbeRelay 探测中继，relay 为中继的完整 multiaddr（含 /p2p/<ID>）
+	ProbeRelay(ctx context.Context, relay string) (RelayProbe, error)
 }
 
 // NewClient 创建诊断客户端
@@ -48,8 +66,17 @@ func (c *Client) GetReport(ctx context.Context) (*Report, error) {
 func (c *Client) GetReportWithOptions(ctx context.Context, opts ProbeOptions) (*Report, error) {
 	c.mu.Lock()
 	config := c.config
+	budget := c.dataBudget
+	previous := c.lastReport
 	c.mu.Unlock()
 
+	// 计费网络下按数据预算把完整探测降级为增量探测（复用上一份报告）
+	if opts.Full && previous != nil && budget != nil &&
+		!budget.Allow(interfaces.BudgetSubsystemNetReport, "full_report", config.FullReportInterval) {
+		logger.Debug("数据预算限制，完整诊断降级为增量探测")
+		opts = ProbeOptions{Full: false, PreviousReport: previous}
+	}
+
 	start := time.Now()
 	builder := NewReportBuilder()
 
@@ -58,6 +85,9 @@ func (c *Client) GetReportWithOptions(ctx context.Context, opts ProbeOptions) (*
 		for url, latency := range opts.PreviousReport.RelayLatencies {
 			builder.AddRelayLatency(url, latency)
 		}
+		for url, probe := range opts.PreviousReport.RelayProbes {
+			builder.AddRelayProbe(url, probe)
+		}
 		builder.SetPortMapAvailability(
 			opts.PreviousReport.UPnPAvailable,
 			opts.PreviousReport.NATPMPAvailable,
@@ -183,6 +213,25 @@ func (c *Client) SetRelayServers(relays []string) {
 	c.config.RelayServers = relays
 }
 
+// SetRelayProber 设置中继探测器
+//
+// 未设置探测器时跳过中继探测。
+func (c *Client) SetRelayProber(prober RelayProber) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.relayProber = prober
+}
+
+// SetDataBudget 设置数据预算
+//
+// 计费网络下完整探测（中继、端口映射）按 FullReportInterval 放大后的间隔执行，
+// 其余请求降级为增量探测。
+func (c *Client) SetDataBudget(budget interfaces.DataBudget) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.dataBudget = budget
+}
+
 // ForceFullReport 强制下次生成完整报告
 func (c *Client) ForceFullReport() {
 	c.mu.Lock()
@@ -306,12 +355,20 @@ func (c *Client) runIPv6Probes(ctx context.Context, builder *ReportBuilder) {
 
 // runRelayProbes 运行中继延迟探测
 func (c *Client) runRelayProbes(ctx context.Context, builder *ReportBuilder) {
-	logger.Debug("开始中继延迟探测")
-
 	c.mu.RLock()
 	relays := c.config.RelayServers
+	prober := c.relayProber
+	timeout := c.config.ProbeTimeout
 	c.mu.RUnlock()
 
+	if prober == nil {
+		logger.Debug("未设置中继探测器，跳过中继探测")
+		return
+	}
+
+	logger.Debug("开始中继延迟探测", "relays", len(relays))
+
+	var wg sync.WaitGroup
 	for _, relay := range relays {
 		select {
 		case <-ctx.Done():
@@ -319,25 +376,22 @@ func (c *Client) runRelayProbes(ctx context.Context, builder *ReportBuilder) {
 		default:
 		}
 
-		latency := c.measureRelayLatency(ctx, relay)
-		if latency > 0 {
-			builder.AddRelayLatency(relay, latency)
-		}
-	}
-}
+		wg.Add(1)
+		go func(relay string) {
+			defer wg.Done()
 
-// measureRelayLatency 测量中继延迟
-func (c *Client) measureRelayLatency(_ context.Context, relay string) time.Duration {
-	start := time.Now()
+			probeCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
 
-	// 简单的 TCP 连接测试
-	conn, err := net.DialTimeout("tcp", relay, c.config.ProbeTimeout)
-	if err != nil {
-		return 0
+			probe, err := prober.ProbeRelay(probeCtx, relay)
+			if err != nil {
+				logger.Debug("中继探测失败", "relay", relay, "err", err)
+				return
+			}
+			builder.AddRelayProbe(relay, probe)
+		}(relay)
 	}
-	conn.Close()
-
-	return time.Since(start)
+	wg.Wait()
 }
 
 // runPortMapProbe 运行端口映射协议探测
@@ -346,9 +400,9 @@ func (c *Client) runPortMapProbe(ctx context.Context, builder *ReportBuilder) {
 
 	upnp := c.detectUPnP(ctx)
 	natpmp := c.detectNATPMP(ctx)
-	pcp := c.detectPCP(ctx)
+	pcpAvailable := c.detectPCP(ctx)
 
-	builder.SetPortMapAvailability(upnp, natpmp, pcp)
+	builder.SetPortMapAvailability(upnp, natpmp, pcpAvailable)
 }
 
 // detectUPnP 检测 UPnP 可用性
@@ -439,9 +493,16 @@ func (c *Client) detectNATPMP(ctx context.Context) bool {
 }
 
 // detectPCP 检测 PCP 可用性
-func (c *Client) detectPCP(_ context.Context) bool {
-	// PCP 检测比较复杂，暂时返回 false
-	return false
+//
+// 向网关发送 PCP ANNOUNCE 请求；仅支持 NAT-PMP 的网关以版本 0 回应，
+// 不会被误判为支持 PCP。
+func (c *Client) detectPCP(ctx context.Context) bool {
+	gateway := c.getDefaultGateway()
+	if gateway == nil {
+		return false
+	}
+
+	return pcp.Probe(ctx, &net.UDPAddr{IP: gateway, Port: pcp.ServerPort}, 2*time.Second)
 }
 
 // getDefaultGateway 获取默认网关地址
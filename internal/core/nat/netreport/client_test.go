@@ -3,11 +3,14 @@ package netreport
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/dep2p/go-dep2p/pkg/interfaces"
 )
 
 func TestNewClient(t *testing.T) {
@@ -175,6 +178,120 @@ func TestClient_SetRelayServers(t *testing.T) {
 	assert.Equal(t, newRelays, client.config.RelayServers)
 }
 
+// fakeRelayProber 测试用中继探测器
+type fakeRelayProber struct {
+	probes map[string]RelayProbe
+}
+
+func (f *fakeRelayProber) ProbeRelay(_ context.Context, relay string) (RelayProbe, error) {
+	probe, ok := f.probes[relay]
+	if !ok {
+		return RelayProbe{}, errors.New("unreachable")
+	}
+	return probe, nil
+}
+
+func TestClient_RelayProbes(t *testing.T) {
+	config := DefaultConfig()
+	config.EnableIPv4 = false
+	config.EnableIPv6 = false
+	config.EnablePortMapProbe = false
+	config.EnableCaptivePortalProbe = false
+	config.RelayServers = []string{"/relay/busy", "/relay/idle", "/relay/down"}
+
+	client := NewClient(config)
+
+	// 未设置探测器时跳过中继探测
+	report, err := client.GetReport(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, report.RelayLatencies)
+
+	client.SetRelayProber(&fakeRelayProber{probes: map[string]RelayProbe{
+		"/relay/busy": {Latency: 20 * time.Millisecond, LoadKnown: true, CircuitUtilization: 0.9},
+		"/relay/idle": {Latency: 30 * time.Millisecond, LoadKnown: true},
+	}})
+
+	report, err = client.GetReport(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, report.RelayLatencies, 2)
+	assert.Len(t, report.RelayProbes, 2)
+	assert.InDelta(t, 0.9, report.RelayProbes["/relay/busy"].Utilization(), 1e-9)
+	// 负载加权：30ms×1.0 < 20ms×1.9
+	assert.Equal(t, "/relay/idle", report.PreferredRelay)
+	assert.Equal(t, 30*time.Millisecond, report.BestRelayLatency())
+}
+
+// countingRelayProber 记录探测次数的中继探测器
+type countingRelayProber struct {
+	count int
+}
+
+func (c *countingRelayProber) ProbeRelay(_ context.Context, _ string) (RelayProbe, error) {
+	c.count++
+	return RelayProbe{Latency: 10 * time.Millisecond}, nil
+}
+
+// fakeDataBudget 测试用数据预算
+type fakeDataBudget struct {
+	allow bool
+	calls []string
+}
+
+func (f *fakeDataBudget) Allow(subsystem string, task string, _ time.Duration) bool {
+	f.calls = append(f.calls, subsystem+"/"+task)
+	return f.allow
+}
+func (f *fakeDataBudget) SetMode(interfaces.DataBudgetMode) {}
+func (f *fakeDataBudget) SetDailyBudget(int64)              {}
+func (f *fakeDataBudget) Status() interfaces.DataBudgetStatus {
+	return interfaces.DataBudgetStatus{}
+}
+
+func TestClient_DataBudget(t *testing.T) {
+	config := DefaultConfig()
+	config.EnableIPv4 = false
+	config.EnableIPv6 = false
+	config.EnablePortMapProbe = false
+	config.EnableCaptivePortalProbe = false
+	config.RelayServers = []string{"/relay/a"}
+
+	prober := &countingRelayProber{}
+	budget := &fakeDataBudget{allow: false}
+	client := NewClient(config)
+	client.SetRelayProber(prober)
+	client.SetDataBudget(budget)
+
+	// 首次报告不受预算限制
+	_, err := client.GetReport(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, prober.count)
+
+	// 预算不允许时降级为增量探测，复用上一份报告的中继结果
+	report, err := client.GetReport(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, prober.count)
+	assert.Len(t, report.RelayLatencies, 1)
+	assert.Equal(t, []string{"netreport/full_report"}, budget.calls)
+
+	budget.allow = true
+	_, err = client.GetReport(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, prober.count)
+}
+
+func TestReportBuilder_PreferredRelay_SkipsSaturated(t *testing.T) {
+	b := NewReportBuilder()
+
+	b.AddRelayProbe("full", RelayProbe{Latency: 5 * time.Millisecond, LoadKnown: true, ReservationUtilization: 1})
+	assert.Equal(t, "full", b.report.PreferredRelay, "仅有满载中继时仍选择它")
+
+	b.AddRelayProbe("slow", RelayProbe{Latency: 500 * time.Millisecond})
+	assert.Equal(t, "slow", b.report.PreferredRelay)
+
+	b.AddRelayLatency("legacy", 100*time.Millisecond)
+	assert.Equal(t, "legacy", b.report.PreferredRelay)
+}
+
 func TestClient_GetReportAsync(t *testing.T) {
 	config := DefaultConfig()
 	config.Timeout = 1 * time.Second
@@ -8,7 +8,7 @@ type Config struct {
 	// STUNServers STUN 服务器列表
 	STUNServers []string
 
-	// RelayServers 中继服务器列表
+	// RelayServers 中继服务器列表（完整 multiaddr，含 /p2p/<ID>）
 	RelayServers []string
 
 	// Timeout 诊断超时时间
@@ -5,7 +5,7 @@
 // 本包实现了网络诊断功能，用于：
 // - IPv4/IPv6 连通性检测
 // - NAT 类型检测（对称 NAT / 非对称 NAT）
-// - 中继延迟测量
+// - 中继延迟与负载测量（经由中继协议探测）
 // - 端口映射协议检测（UPnP/NAT-PMP/PCP）
 // - 强制门户检测
 //
@@ -15,13 +15,14 @@
 //   - UDPv4/UDPv6 连通性
 //   - 公网 IP 和端口
 //   - NAT 类型
-//   - 中继延迟
+//   - 中继延迟与负载（首选中继按负载加权延迟选择）
 //   - 端口映射可用性
 //
 // Client - 诊断客户端:
 //   - GetReport: 生成完整诊断报告
 //   - LastReport: 获取缓存的最后报告
 //   - ForceFullReport: 强制完整探测
+//   - SetRelayProber: 注入中继探测器（由 AutoRelay 提供，未注入时跳过中继探测）
 //
 // # NAT 类型检测
 //
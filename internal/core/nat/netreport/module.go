@@ -3,10 +3,12 @@ package netreport
 
 import (
 	"context"
+	"strings"
 
 	"go.uber.org/fx"
 
 	"github.com/dep2p/go-dep2p/config"
+	"github.com/dep2p/go-dep2p/pkg/interfaces"
 	"github.com/dep2p/go-dep2p/pkg/lib/log"
 )
 
@@ -20,7 +22,8 @@ var logger = log.Logger("core/netreport")
 type Params struct {
 	fx.In
 
-	UnifiedCfg *config.Config `optional:"true"`
+	UnifiedCfg *config.Config        `optional:"true"`
+	DataBudget interfaces.DataBudget `optional:"true"`
 }
 
 // Result 模块提供的结果
@@ -48,6 +51,9 @@ func Module() fx.Option {
 func ProvideClient(p Params) (Result, error) {
 	cfg := ConfigFromUnified(p.UnifiedCfg)
 	client := NewClient(cfg)
+	if p.DataBudget != nil {
+		client.SetDataBudget(p.DataBudget)
+	}
 
 	return Result{
 		Client: client,
@@ -68,6 +74,21 @@ func ConfigFromUnified(cfg *config.Config) Config {
 		result.STUNServers = cfg.NAT.STUNServers
 	}
 
+	// 中继探测目标：配置的中继地址与静态中继中的完整 multiaddr
+	// （仅有 PeerID 的静态中继无法直接探测，忽略）
+	var relays []string
+	if cfg.Relay.RelayAddr != "" {
+		relays = append(relays, cfg.Relay.RelayAddr)
+	}
+	for _, addr := range cfg.Relay.StaticRelays {
+		if strings.Contains(addr, "/p2p/") && addr != cfg.Relay.RelayAddr {
+			relays = append(relays, addr)
+		}
+	}
+	if len(relays) > 0 {
+		result.RelayServers = relays
+	}
+
 	return result
 }
 
@@ -78,7 +78,8 @@ type Report struct {
 
 	// 中继信息
 	RelayLatencies map[string]time.Duration // 中继延迟
-	PreferredRelay string                   // 首选中继
+	RelayProbes    map[string]RelayProbe    // 中继探测详情（含自报负载）
+	PreferredRelay string                   // 首选中继（按负载加权延迟选择）
 
 	// 端口映射
 	UPnPAvailable   bool // UPnP 可用
@@ -120,6 +121,43 @@ func (r *Report) BestRelayLatency() time.Duration {
 	return 0
 }
 
+// RelayProbe 中继探测结果
+//
+// 通过中继协议（HOP PROBE）测得的 RTT 与中继自报负载。
+type RelayProbe struct {
+	Latency                time.Duration // 协议层往返时延
+	LoadKnown              bool          // 中继是否报告了负载（旧版中继不报告）
+	ReservationUtilization float64       // 预留利用率（0-1）
+	CircuitUtilization     float64       // 电路利用率（0-1）
+}
+
+// Utilization 返回综合利用率（取预留与电路利用率的较大值）
+func (p RelayProbe) Utilization() float64 {
+	if !p.LoadKnown {
+		return 0
+	}
+	if p.ReservationUtilization > p.CircuitUtilization {
+		return p.ReservationUtilization
+	}
+	return p.CircuitUtilization
+}
+
+// Saturated 返回中继是否已满载
+func (p RelayProbe) Saturated() bool {
+	return p.LoadKnown && p.Utilization() >= 1
+}
+
+// EffectiveLatency 返回负载加权延迟
+//
+// 利用率越高，实际可用性越差：RTT × (1 + 利用率)。
+func (p RelayProbe) EffectiveLatency() time.Duration {
+	util := p.Utilization()
+	if util > 1 {
+		util = 1
+	}
+	return time.Duration(float64(p.Latency) * (1 + util))
+}
+
 // ============================================================================
 //                              报告构建器
 // ============================================================================
@@ -146,6 +184,7 @@ func NewReportBuilder() *ReportBuilder {
 	return &ReportBuilder{
 		report: &Report{
 			RelayLatencies: make(map[string]time.Duration),
+			RelayProbes:    make(map[string]RelayProbe),
 			Timestamp:      time.Now(),
 		},
 		ipv4Mappings: make([]mappingResult, 0),
@@ -285,16 +324,45 @@ func (b *ReportBuilder) AddRelayLatency(url string, latency time.Duration) {
 	b.updatePreferredRelay()
 }
 
+// AddRelayProbe 添加中继探测结果
+func (b *ReportBuilder) AddRelayProbe(url string, probe RelayProbe) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.report.RelayProbes[url] = probe
+	if existing, ok := b.report.RelayLatencies[url]; !ok || probe.Latency < existing {
+		b.report.RelayLatencies[url] = probe.Latency
+	}
+
+	b.updatePreferredRelay()
+}
+
 // updatePreferredRelay 更新首选中继
+//
+// 有探测详情的中继按负载加权延迟比较，满载中继仅在没有其它可选时才被选中。
 func (b *ReportBuilder) updatePreferredRelay() {
 	var bestURL string
 	var bestLatency time.Duration
+	bestSaturated := false
 
 	for url, latency := range b.report.RelayLatencies {
-		if bestURL == "" || latency < bestLatency {
-			bestURL = url
-			bestLatency = latency
+		saturated := false
+		if probe, ok := b.report.RelayProbes[url]; ok {
+			latency = probe.EffectiveLatency()
+			saturated = probe.Saturated()
+		}
+
+		switch {
+		case bestURL == "":
+		case bestSaturated && !saturated:
+		case saturated != bestSaturated:
+			continue
+		case latency >= bestLatency:
+			continue
 		}
+		bestURL = url
+		bestLatency = latency
+		bestSaturated = saturated
 	}
 
 	b.report.PreferredRelay = bestURL
@@ -0,0 +1,224 @@
+package pcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// initialRetransmit 首次重传间隔
+//
+// RFC 6887 建议 3 秒；局域网网关通常在毫秒级响应，使用更短的
+// 初始间隔加快探测，之后按指数退避。
+const initialRetransmit = 250 * time.Millisecond
+
+// MapResult MAP 操作结果
+type MapResult struct {
+	// Protocol 协议（"udp" 或 "tcp"）
+	Protocol string
+
+	// InternalPort 内部端口
+	InternalPort int
+
+	// ExternalPort 服务器分配的外部端口
+	ExternalPort int
+
+	// ExternalIP 服务器分配的外部地址
+	ExternalIP net.IP
+
+	// Lifetime 服务器授予的租期
+	Lifetime time.Duration
+
+	// Epoch 服务器纪元时间（秒）
+	Epoch uint32
+}
+
+// Client PCP 客户端
+//
+// 负责与单个 PCP 服务器交换请求/响应，并按 RFC 6887 §8.5 对每个
+// 响应做纪元校验：服务器时间回退或与本地时间流逝明显不一致时，
+// 说明服务器已重启并丢失了映射状态，此时调用 OnStateLost 回调。
+type Client struct {
+	server  *net.UDPAddr
+	timeout time.Duration
+
+	epochMu    sync.Mutex
+	haveEpoch  bool
+	prevServer uint32
+	prevClient time.Time
+	onLost     func()
+}
+
+// NewClient 创建 PCP 客户端
+//
+// server 的端口为 0 时使用 ServerPort；timeout 为单次请求的总超时。
+func NewClient(server *net.UDPAddr, timeout time.Duration) *Client {
+	addr := *server
+	if addr.Port == 0 {
+		addr.Port = ServerPort
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Client{server: &addr, timeout: timeout}
+}
+
+// Server 返回服务器地址
+func (c *Client) Server() *net.UDPAddr {
+	return c.server
+}
+
+// OnStateLost 设置服务器状态丢失回调
+func (c *Client) OnStateLost(fn func()) {
+	c.epochMu.Lock()
+	c.onLost = fn
+	c.epochMu.Unlock()
+}
+
+// Announce 发送 ANNOUNCE 请求，服务器支持 PCP 时返回 nil
+func (c *Client) Announce(ctx context.Context) error {
+	_, err := c.request(ctx, OpAnnounce, func(clientIP net.IP) ([]byte, error) {
+		return encodeRequest(OpAnnounce, 0, clientIP, nil), nil
+	}, nil)
+	return err
+}
+
+// Map 创建、续期或删除（Lifetime 为 0）映射
+func (c *Client) Map(ctx context.Context, req *MapRequest) (*MapResult, error) {
+	resp, err := c.request(ctx, OpMap, func(clientIP net.IP) ([]byte, error) {
+		return encodeMap(req, clientIP)
+	}, func(r *response) bool {
+		// 只接受与请求匹配的响应（RFC 6887 §11.3）
+		return r.result != ResultSuccess || r.nonce == req.Nonce
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &MapResult{
+		Protocol:     req.Protocol,
+		InternalPort: int(resp.internalPort),
+		ExternalPort: int(resp.externalPort),
+		ExternalIP:   resp.externalIP,
+		Lifetime:     time.Duration(resp.lifetime) * time.Second,
+		Epoch:        resp.epoch,
+	}, nil
+}
+
+// request 发送请求并等待匹配的响应，超时前按指数退避重传
+func (c *Client) request(ctx context.Context, opcode byte, build func(clientIP net.IP) ([]byte, error), match func(*response) bool) (*response, error) {
+	conn, err := net.DialUDP("udp", nil, c.server)
+	if err != nil {
+		return nil, &PCPError{Message: "dial server", Cause: err}
+	}
+	defer conn.Close()
+
+	clientIP := conn.LocalAddr().(*net.UDPAddr).IP
+	pkt, err := build(clientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(c.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	stop := context.AfterFunc(ctx, func() { conn.SetReadDeadline(time.Now()) })
+	defer stop()
+
+	buf := make([]byte, maxPacketSize)
+	retransmit := initialRetransmit
+	for {
+		if _, err := conn.Write(pkt); err != nil {
+			return nil, &PCPError{Message: "send request", Cause: err}
+		}
+
+		wait := time.Now().Add(retransmit)
+		if wait.After(deadline) {
+			wait = deadline
+		}
+		conn.SetReadDeadline(wait)
+
+		for {
+			n, err := conn.Read(buf)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				var netErr net.Error
+				if errors.As(err, &netErr) && netErr.Timeout() {
+					break
+				}
+				return nil, &PCPError{Message: "read response", Cause: err}
+			}
+
+			resp, err := decodeResponse(buf[:n])
+			if err != nil {
+				if errors.Is(err, ErrUnsupportedVersion) {
+					return nil, err
+				}
+				continue
+			}
+			if resp.opcode != opcode || (match != nil && !match(resp)) {
+				continue
+			}
+
+			if c.observeEpoch(resp.epoch) {
+				c.notifyLost()
+			}
+			if resp.result != ResultSuccess {
+				return nil, &ResultError{Code: resp.result}
+			}
+			return resp, nil
+		}
+
+		if !time.Now().Before(deadline) {
+			return nil, fmt.Errorf("%w after %v", ErrTimeout, c.timeout)
+		}
+		retransmit *= 2
+	}
+}
+
+// observeEpoch 按 RFC 6887 §8.5 校验服务器纪元，返回服务器是否丢失了状态
+func (c *Client) observeEpoch(epoch uint32) bool {
+	now := time.Now()
+
+	c.epochMu.Lock()
+	lost := false
+	if c.haveEpoch {
+		curServer, prevServer := int64(epoch), int64(c.prevServer)
+		if curServer < prevServer-1 {
+			lost = true
+		} else {
+			clientDelta := now.Sub(c.prevClient).Seconds()
+			serverDelta := float64(curServer - prevServer)
+			if clientDelta+2 < serverDelta-serverDelta/16 || serverDelta+2 < clientDelta-clientDelta/16 {
+				lost = true
+			}
+		}
+	}
+	c.haveEpoch, c.prevServer, c.prevClient = true, epoch, now
+	c.epochMu.Unlock()
+
+	if lost {
+		logger.Info("PCP 服务器纪元异常，映射状态可能已丢失", "server", c.server.String(), "epoch", epoch)
+	}
+	return lost
+}
+
+// notifyLost 调用状态丢失回调
+func (c *Client) notifyLost() {
+	c.epochMu.Lock()
+	onLost := c.onLost
+	c.epochMu.Unlock()
+	if onLost != nil {
+		onLost()
+	}
+}
+
+// Probe 检测地址上是否运行 PCP 服务器
+func Probe(ctx context.Context, server *net.UDPAddr, timeout time.Duration) bool {
+	return NewClient(server, timeout).Announce(ctx) == nil
+}
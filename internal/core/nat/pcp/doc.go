@@ -0,0 +1,28 @@
+// Package pcp 实现 PCP 端口映射
+//
+// pcp 使用 Port Control Protocol（RFC 6887）在 NAT 设备上创建端口映射。
+// PCP 是 NAT-PMP 的后继协议，同时支持 IPv4、IPv6 以及 NAT64 场景，
+// 越来越多的家用路由器和 IPv6 CPE 只支持 PCP。
+//
+// # 功能
+//
+//   - MAP 请求（IPv4 / IPv6，包括 PCP-for-NAT64）
+//   - 租期过半时自动续期，失败后定期重试
+//   - 基于纪元时间（epoch）检测服务器重启，自动重建映射
+//   - 监听服务器重启后组播的 ANNOUNCE，随机延迟后重建映射
+//
+// # 使用示例
+//
+//	mapper, err := pcp.NewPCPMapperWithTimeout(5 * time.Second)
+//	if err != nil {
+//	    return err // 网关不支持 PCP
+//	}
+//	mapper.Start(ctx)
+//	defer mapper.Stop()
+//
+//	externalPort, err := mapper.MapPort(ctx, "udp", 4001)
+//	if err != nil {
+//	    return err
+//	}
+//	defer mapper.UnmapPort("udp", externalPort)
+package pcp
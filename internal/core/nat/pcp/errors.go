@@ -0,0 +1,68 @@
+package pcp
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 错误定义
+var (
+	// ErrTimeout 服务器未响应
+	ErrTimeout = errors.New("pcp: request timeout")
+
+	// ErrUnsupportedVersion 服务器不支持 PCP（例如仅支持 NAT-PMP）
+	ErrUnsupportedVersion = errors.New("pcp: unsupported version")
+
+	// ErrMalformedResponse 响应格式错误
+	ErrMalformedResponse = errors.New("pcp: malformed response")
+
+	// ErrInvalidRequest 请求参数无效
+	ErrInvalidRequest = errors.New("pcp: invalid request")
+
+	// ErrNoMapping 没有对应的映射
+	ErrNoMapping = errors.New("pcp: no such mapping")
+
+	// ErrNoGateway 未找到网关
+	ErrNoGateway = errors.New("pcp: no gateway found")
+)
+
+// ResultError 服务器返回的非成功结果码
+type ResultError struct {
+	Code ResultCode
+}
+
+func (e *ResultError) Error() string {
+	return fmt.Sprintf("pcp: server returned %s", e.Code)
+}
+
+// PCPError PCP 操作错误
+type PCPError struct {
+	Message string
+	Cause   error
+}
+
+func (e *PCPError) Error() string {
+	if e.Cause != nil {
+		return "pcp: " + e.Message + ": " + e.Cause.Error()
+	}
+	return "pcp: " + e.Message
+}
+
+func (e *PCPError) Unwrap() error {
+	return e.Cause
+}
+
+// MappingError 端口映射错误
+type MappingError struct {
+	Protocol string
+	Port     int
+	Cause    error
+}
+
+func (e *MappingError) Error() string {
+	return fmt.Sprintf("pcp: mapping %s port %d failed: %v", e.Protocol, e.Port, e.Cause)
+}
+
+func (e *MappingError) Unwrap() error {
+	return e.Cause
+}
@@ -0,0 +1,531 @@
+package pcp
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	mathrand "math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/jackpal/gateway"
+
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/log"
+)
+
+var logger = log.Logger("nat/pcp")
+
+// 默认值
+const (
+	// DefaultTimeout 默认 PCP 操作超时
+	DefaultTimeout = 5 * time.Second
+
+	// DefaultLifetime 默认映射租期
+	DefaultLifetime = time.Hour
+
+	// DefaultAnnounceJitter 收到 ANNOUNCE 后重建映射前的最大随机等待（RFC 6887 §14.1.3）
+	DefaultAnnounceJitter = 5 * time.Second
+
+	// renewRetryInterval 续期失败后的重试间隔
+	renewRetryInterval = 30 * time.Second
+
+	// idleCheckInterval 无映射时续期循环的检查间隔
+	idleCheckInterval = time.Minute
+)
+
+// DefaultAnnounceAddrs PCP 服务器重启后组播 ANNOUNCE 的地址
+var DefaultAnnounceAddrs = []string{"224.0.0.1:5350", "[ff02::1]:5350"}
+
+// Config PCP 映射器配置
+type Config struct {
+	// Server PCP 服务器地址（nil 表示使用默认网关）
+	Server *net.UDPAddr
+
+	// Timeout 单次请求超时（网关发现、探测、映射）
+	Timeout time.Duration
+
+	// Lifetime 请求的映射租期
+	Lifetime time.Duration
+
+	// AnnounceAddrs 监听 ANNOUNCE 的地址（为空则不监听）
+	AnnounceAddrs []string
+
+	// AnnounceJitter 收到 ANNOUNCE 后重建映射前的最大随机等待
+	AnnounceJitter time.Duration
+
+	// NAT64 通过 PCP-for-NAT64 请求 IPv4 外部地址（IPv6 客户端）
+	NAT64 bool
+}
+
+// DefaultConfig 返回默认配置
+func DefaultConfig() *Config {
+	return &Config{
+		Timeout:        DefaultTimeout,
+		Lifetime:       DefaultLifetime,
+		AnnounceAddrs:  append([]string(nil), DefaultAnnounceAddrs...),
+		AnnounceJitter: DefaultAnnounceJitter,
+	}
+}
+
+// Mapping 端口映射记录
+type Mapping struct {
+	Protocol     string
+	InternalPort int
+	ExternalPort int
+	ExternalIP   net.IP
+	Lifetime     time.Duration
+	CreatedAt    time.Time
+
+	nonce   [12]byte
+	renewAt time.Time
+}
+
+// PCPMapper PCP 端口映射器
+type PCPMapper struct {
+	client   *Client
+	config   Config
+	mappings map[string]*Mapping
+	mu       sync.RWMutex
+
+	// remapCh 请求立即重建全部映射（服务器丢失状态时）
+	remapCh chan struct{}
+
+	// rescheduleCh 映射变化后重新计算续期时间
+	rescheduleCh chan struct{}
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+	listeners []*net.UDPConn
+
+	// 地址发现集成
+	coordinator   pkgif.ReachabilityCoordinator // 可达性协调器（用于上报候选地址）
+	coordinatorMu sync.RWMutex
+}
+
+// NewPCPMapperWithTimeout 使用默认网关创建 PCP 映射器
+func NewPCPMapperWithTimeout(timeout time.Duration) (*PCPMapper, error) {
+	cfg := DefaultConfig()
+	if timeout > 0 {
+		cfg.Timeout = timeout
+	}
+	return NewPCPMapper(cfg)
+}
+
+// NewPCPMapper 创建 PCP 映射器
+//
+// 创建时向服务器发送 ANNOUNCE 确认其支持 PCP，不支持时返回错误。
+func NewPCPMapper(cfg *Config) (*PCPMapper, error) {
+	config := *DefaultConfig()
+	if cfg != nil {
+		config = *cfg
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+	if config.Lifetime <= 0 {
+		config.Lifetime = DefaultLifetime
+	}
+
+	server := config.Server
+	if server == nil {
+		ip, err := discoverGateway(config.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		server = &net.UDPAddr{IP: ip, Port: ServerPort}
+	}
+
+	client := NewClient(server, config.Timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
+	defer cancel()
+	if err := client.Announce(ctx); err != nil {
+		return nil, &PCPError{Message: "probe server", Cause: err}
+	}
+
+	logger.Info("PCP 映射器已创建", "server", client.Server().String(), "timeout", config.Timeout)
+
+	return &PCPMapper{
+		client:   client,
+		config:   config,
+		mappings: make(map[string]*Mapping),
+		remapCh:  make(chan struct{}, 1),
+
+		rescheduleCh: make(chan struct{}, 1),
+	}, nil
+}
+
+// discoverGateway 带超时地发现默认网关
+func discoverGateway(timeout time.Duration) (net.IP, error) {
+	type result struct {
+		ip  net.IP
+		err error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		ip, err := gateway.DiscoverGateway()
+		ch <- result{ip, err}
+	}()
+
+	select {
+	case r := <-ch:
+		if r.err != nil {
+			return nil, &PCPError{Message: "discover gateway", Cause: r.err}
+		}
+		if r.ip == nil {
+			return nil, ErrNoGateway
+		}
+		return r.ip, nil
+	case <-time.After(timeout):
+		return nil, &PCPError{Message: "discover gateway", Cause: fmt.Errorf("timeout after %v", timeout)}
+	}
+}
+
+// Server 返回 PCP 服务器地址
+func (n *PCPMapper) Server() *net.UDPAddr {
+	return n.client.Server()
+}
+
+func mappingKey(proto string, internalPort int) string {
+	p, _ := protocolNumber(proto)
+	return fmt.Sprintf("%d/%d", p, internalPort)
+}
+
+// MapPort 映射端口，返回外部端口
+//
+// 已存在的映射以相同 nonce 续期，并优先请求之前分配的外部端口。
+func (n *PCPMapper) MapPort(ctx context.Context, proto string, internalPort int) (int, error) {
+	key := mappingKey(proto, internalPort)
+
+	n.mu.RLock()
+	existing := n.mappings[key]
+	n.mu.RUnlock()
+
+	req := &MapRequest{
+		Protocol:      proto,
+		InternalPort:  internalPort,
+		SuggestedPort: internalPort,
+		Lifetime:      uint32(n.config.Lifetime / time.Second),
+	}
+	if n.config.NAT64 {
+		req.SuggestedIP = NAT64Suggestion
+	}
+	if existing != nil {
+		req.Nonce = existing.nonce
+		req.SuggestedPort = existing.ExternalPort
+	} else if _, err := rand.Read(req.Nonce[:]); err != nil {
+		return 0, &MappingError{Protocol: proto, Port: internalPort, Cause: err}
+	}
+
+	res, err := n.client.Map(ctx, req)
+	if err != nil {
+		return 0, &MappingError{Protocol: proto, Port: internalPort, Cause: err}
+	}
+
+	now := time.Now()
+	m := &Mapping{
+		Protocol:     proto,
+		InternalPort: internalPort,
+		ExternalPort: res.ExternalPort,
+		ExternalIP:   res.ExternalIP,
+		Lifetime:     res.Lifetime,
+		CreatedAt:    now,
+		nonce:        req.Nonce,
+		// 在租期过半时续期（RFC 6887 §11.2.1）
+		renewAt: now.Add(res.Lifetime / 2),
+	}
+
+	n.mu.Lock()
+	n.mappings[key] = m
+	n.mu.Unlock()
+
+	select {
+	case n.rescheduleCh <- struct{}{}:
+	default:
+	}
+
+	if existing == nil || existing.ExternalPort != m.ExternalPort || !existing.ExternalIP.Equal(m.ExternalIP) {
+		n.reportMappedAddressToCoordinator(m)
+	}
+
+	logger.Debug("PCP 端口映射成功",
+		"proto", proto,
+		"internalPort", internalPort,
+		"externalPort", m.ExternalPort,
+		"externalIP", m.ExternalIP.String(),
+		"lifetime", m.Lifetime)
+
+	return m.ExternalPort, nil
+}
+
+// UnmapPort 删除映射（port 可以是内部端口或外部端口）
+func (n *PCPMapper) UnmapPort(proto string, port int) error {
+	n.mu.RLock()
+	var found *Mapping
+	var key string
+	for k, m := range n.mappings {
+		if sameProtocol(m.Protocol, proto) && (m.InternalPort == port || m.ExternalPort == port) {
+			found, key = m, k
+			break
+		}
+	}
+	n.mu.RUnlock()
+
+	if found == nil {
+		return &MappingError{Protocol: proto, Port: port, Cause: ErrNoMapping}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), n.config.Timeout)
+	defer cancel()
+	_, err := n.client.Map(ctx, &MapRequest{
+		Nonce:        found.nonce,
+		Protocol:     found.Protocol,
+		InternalPort: found.InternalPort,
+		Lifetime:     0,
+	})
+
+	n.mu.Lock()
+	delete(n.mappings, key)
+	n.mu.Unlock()
+
+	if err != nil {
+		return &MappingError{Protocol: proto, Port: port, Cause: err}
+	}
+	return nil
+}
+
+func sameProtocol(a, b string) bool {
+	pa, _ := protocolNumber(a)
+	pb, _ := protocolNumber(b)
+	return pa == pb
+}
+
+// Mappings 返回当前映射的副本
+func (n *PCPMapper) Mappings() []Mapping {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	out := make([]Mapping, 0, len(n.mappings))
+	for _, m := range n.mappings {
+		out = append(out, *m)
+	}
+	return out
+}
+
+// GetExternalAddress 获取外部地址（来自已建立的映射）
+func (n *PCPMapper) GetExternalAddress() (net.IP, error) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	for _, m := range n.mappings {
+		if m.ExternalIP != nil && !m.ExternalIP.IsUnspecified() {
+			return m.ExternalIP, nil
+		}
+	}
+	return nil, ErrNoMapping
+}
+
+// SetReachabilityCoordinator 设置可达性协调器
+func (n *PCPMapper) SetReachabilityCoordinator(coordinator pkgif.ReachabilityCoordinator) {
+	n.coordinatorMu.Lock()
+	n.coordinator = coordinator
+	n.coordinatorMu.Unlock()
+}
+
+// reportMappedAddressToCoordinator 将映射地址上报到 Coordinator
+func (n *PCPMapper) reportMappedAddressToCoordinator(m *Mapping) {
+	n.coordinatorMu.RLock()
+	coordinator := n.coordinator
+	n.coordinatorMu.RUnlock()
+
+	if coordinator == nil || m.ExternalIP == nil || m.ExternalIP.IsUnspecified() {
+		return
+	}
+
+	transportProto := "udp"
+	if sameProtocol(m.Protocol, "tcp") {
+		transportProto = "tcp"
+	}
+
+	var maddr string
+	if m.ExternalIP.To4() != nil {
+		maddr = fmt.Sprintf("/ip4/%s/%s/%d/quic-v1", m.ExternalIP.String(), transportProto, m.ExternalPort)
+	} else {
+		maddr = fmt.Sprintf("/ip6/%s/%s/%d/quic-v1", m.ExternalIP.String(), transportProto, m.ExternalPort)
+	}
+
+	coordinator.OnDirectAddressCandidate(maddr, "pcp", pkgif.PriorityUnverified)
+	logger.Debug("PCP 地址已上报到 Coordinator", "addr", maddr)
+}
+
+// ============================================================================
+//                              续期与重建
+// ============================================================================
+
+// Start 启动续期循环和 ANNOUNCE 监听
+func (n *PCPMapper) Start(_ context.Context) {
+	// 使用 context.Background() 保证后台循环不受上层 ctx 取消的影响
+	n.ctx, n.cancel = context.WithCancel(context.Background())
+	n.client.OnStateLost(n.triggerRemap)
+
+	for _, addr := range n.config.AnnounceAddrs {
+		conn, err := listenAnnounce(addr)
+		if err != nil {
+			logger.Debug("无法监听 PCP ANNOUNCE", "addr", addr, "error", err)
+			continue
+		}
+		n.listeners = append(n.listeners, conn)
+		n.wg.Add(1)
+		go n.announceLoop(conn)
+	}
+
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		n.renewLoop(n.ctx)
+	}()
+}
+
+// Stop 停止续期循环和 ANNOUNCE 监听
+func (n *PCPMapper) Stop() {
+	if n.cancel != nil {
+		n.cancel()
+	}
+	for _, conn := range n.listeners {
+		conn.Close()
+	}
+	n.wg.Wait()
+}
+
+// triggerRemap 请求立即重建全部映射
+func (n *PCPMapper) triggerRemap() {
+	select {
+	case n.remapCh <- struct{}{}:
+	default:
+	}
+}
+
+// renewLoop 续期循环
+func (n *PCPMapper) renewLoop(ctx context.Context) {
+	timer := time.NewTimer(n.nextRenewal())
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-n.remapCh:
+			n.renewMappings(ctx, true)
+		case <-n.rescheduleCh:
+		case <-timer.C:
+			n.renewMappings(ctx, false)
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(n.nextRenewal())
+	}
+}
+
+// nextRenewal 返回距下一次续期的时间
+func (n *PCPMapper) nextRenewal() time.Duration {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	next := idleCheckInterval
+	for _, m := range n.mappings {
+		if d := time.Until(m.renewAt); d < next {
+			next = d
+		}
+	}
+	if next < 0 {
+		next = 0
+	}
+	return next
+}
+
+// renewMappings 续期到期的映射（all 为 true 时重建全部映射）
+func (n *PCPMapper) renewMappings(ctx context.Context, all bool) {
+	now := time.Now()
+
+	n.mu.RLock()
+	due := make([]*Mapping, 0, len(n.mappings))
+	for _, m := range n.mappings {
+		if all || !now.Before(m.renewAt) {
+			due = append(due, m)
+		}
+	}
+	n.mu.RUnlock()
+
+	for _, m := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		if _, err := n.MapPort(ctx, m.Protocol, m.InternalPort); err != nil {
+			logger.Debug("PCP 映射续期失败", "proto", m.Protocol, "port", m.InternalPort, "error", err)
+			n.mu.Lock()
+			m.renewAt = time.Now().Add(renewRetryInterval)
+			n.mu.Unlock()
+		}
+	}
+}
+
+// listenAnnounce 监听 ANNOUNCE（组播地址加入组播组）
+func listenAnnounce(addr string) (*net.UDPConn, error) {
+	udpAddr, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if udpAddr.IP.IsMulticast() {
+		return net.ListenMulticastUDP("udp", nil, udpAddr)
+	}
+	return net.ListenUDP("udp", udpAddr)
+}
+
+// announceLoop 处理服务器发送的 ANNOUNCE
+//
+// 服务器重启后组播 ANNOUNCE（RFC 6887 §14.1.3）。客户端做纪元校验，
+// 确认状态丢失后随机等待一段时间再重建映射，避免所有客户端同时请求。
+func (n *PCPMapper) announceLoop(conn *net.UDPConn) {
+	defer n.wg.Done()
+
+	buf := make([]byte, maxPacketSize)
+	for {
+		size, from, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		if !from.IP.Equal(n.client.Server().IP) {
+			continue
+		}
+		resp, err := decodeResponse(buf[:size])
+		if err != nil || resp.opcode != OpAnnounce || resp.result != ResultSuccess {
+			continue
+		}
+		if !n.client.observeEpoch(resp.epoch) {
+			continue
+		}
+
+		delay := time.Duration(0)
+		if n.config.AnnounceJitter > 0 {
+			delay = time.Duration(mathrand.Int63n(int64(n.config.AnnounceJitter)))
+		}
+		logger.Info("收到 PCP ANNOUNCE，准备重建映射", "server", from.String(), "delay", delay)
+
+		n.wg.Add(1)
+		go func() {
+			defer n.wg.Done()
+			timer := time.NewTimer(delay)
+			defer timer.Stop()
+			select {
+			case <-n.ctx.Done():
+			case <-timer.C:
+				n.triggerRemap()
+			}
+		}()
+	}
+}
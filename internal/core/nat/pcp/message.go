@@ -0,0 +1,235 @@
+package pcp
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// 协议常量（RFC 6887）
+const (
+	// Version PCP 协议版本
+	Version = 2
+
+	// ServerPort PCP 服务器端口
+	ServerPort = 5351
+
+	// ClientPort PCP 客户端接收组播 ANNOUNCE 的端口
+	ClientPort = 5350
+
+	// headerSize 请求/响应公共头长度
+	headerSize = 24
+
+	// mapPayloadSize MAP 操作负载长度
+	mapPayloadSize = 36
+
+	// maxPacketSize PCP 报文最大长度
+	maxPacketSize = 1100
+
+	// responseBit 响应报文的 R 位
+	responseBit = 0x80
+)
+
+// 操作码
+const (
+	OpAnnounce byte = 0
+	OpMap      byte = 1
+)
+
+// 传输层协议号
+const (
+	protoTCP byte = 6
+	protoUDP byte = 17
+)
+
+// ResultCode PCP 结果码
+type ResultCode byte
+
+// 结果码定义（RFC 6887 §7.4）
+const (
+	ResultSuccess               ResultCode = 0
+	ResultUnsuppVersion         ResultCode = 1
+	ResultNotAuthorized         ResultCode = 2
+	ResultMalformedRequest      ResultCode = 3
+	ResultUnsuppOpcode          ResultCode = 4
+	ResultUnsuppOption          ResultCode = 5
+	ResultMalformedOption       ResultCode = 6
+	ResultNetworkFailure        ResultCode = 7
+	ResultNoResources           ResultCode = 8
+	ResultUnsuppProtocol        ResultCode = 9
+	ResultUserExQuota           ResultCode = 10
+	ResultCannotProvideExternal ResultCode = 11
+	ResultAddressMismatch       ResultCode = 12
+	ResultExcessiveRemotePeers  ResultCode = 13
+)
+
+var resultNames = map[ResultCode]string{
+	ResultSuccess:               "SUCCESS",
+	ResultUnsuppVersion:         "UNSUPP_VERSION",
+	ResultNotAuthorized:         "NOT_AUTHORIZED",
+	ResultMalformedRequest:      "MALFORMED_REQUEST",
+	ResultUnsuppOpcode:          "UNSUPP_OPCODE",
+	ResultUnsuppOption:          "UNSUPP_OPTION",
+	ResultMalformedOption:       "MALFORMED_OPTION",
+	ResultNetworkFailure:        "NETWORK_FAILURE",
+	ResultNoResources:           "NO_RESOURCES",
+	ResultUnsuppProtocol:        "UNSUPP_PROTOCOL",
+	ResultUserExQuota:           "USER_EX_QUOTA",
+	ResultCannotProvideExternal: "CANNOT_PROVIDE_EXTERNAL",
+	ResultAddressMismatch:       "ADDRESS_MISMATCH",
+	ResultExcessiveRemotePeers:  "EXCESSIVE_REMOTE_PEERS",
+}
+
+// String 返回结果码名称
+func (r ResultCode) String() string {
+	if name, ok := resultNames[r]; ok {
+		return name
+	}
+	return fmt.Sprintf("RESULT_%d", byte(r))
+}
+
+// Transient 是否为临时错误（稍后重试可能成功）
+func (r ResultCode) Transient() bool {
+	switch r {
+	case ResultNetworkFailure, ResultNoResources, ResultUserExQuota, ResultExcessiveRemotePeers:
+		return true
+	}
+	return false
+}
+
+// MapRequest MAP 请求
+type MapRequest struct {
+	// Nonce 映射随机数，续期和删除时必须与创建时相同
+	Nonce [12]byte
+
+	// Protocol 协议（"udp" 或 "tcp"）
+	Protocol string
+
+	// InternalPort 内部端口
+	InternalPort int
+
+	// SuggestedPort 建议的外部端口（0 表示由服务器选择）
+	SuggestedPort int
+
+	// SuggestedIP 建议的外部地址
+	//
+	// nil 时按客户端地址族填零地址；IPv6 客户端请求 NAT64 的 IPv4
+	// 外部地址时使用 IPv4 映射的零地址（::ffff:0.0.0.0）。
+	SuggestedIP net.IP
+
+	// Lifetime 请求的租期（秒），0 表示删除映射
+	Lifetime uint32
+}
+
+// response 解析后的响应
+type response struct {
+	opcode   byte
+	result   ResultCode
+	lifetime uint32
+	epoch    uint32
+
+	// MAP 负载
+	nonce        [12]byte
+	protocol     byte
+	internalPort uint16
+	externalPort uint16
+	externalIP   net.IP
+}
+
+// encodeRequest 编码请求头
+func encodeRequest(opcode byte, lifetime uint32, clientIP net.IP, payload []byte) []byte {
+	buf := make([]byte, headerSize, headerSize+len(payload))
+	buf[0] = Version
+	buf[1] = opcode
+	binary.BigEndian.PutUint32(buf[4:8], lifetime)
+	copy(buf[8:24], to16(clientIP))
+	return append(buf, payload...)
+}
+
+// encodeMap 编码 MAP 请求
+func encodeMap(req *MapRequest, clientIP net.IP) ([]byte, error) {
+	proto, err := protocolNumber(req.Protocol)
+	if err != nil {
+		return nil, err
+	}
+	if req.InternalPort <= 0 || req.InternalPort > 0xffff || req.SuggestedPort < 0 || req.SuggestedPort > 0xffff {
+		return nil, fmt.Errorf("%w: port out of range", ErrInvalidRequest)
+	}
+
+	suggested := req.SuggestedIP
+	if suggested == nil {
+		if clientIP.To4() != nil {
+			suggested = net.IPv4zero
+		} else {
+			suggested = net.IPv6zero
+		}
+	}
+
+	payload := make([]byte, mapPayloadSize)
+	copy(payload[0:12], req.Nonce[:])
+	payload[12] = proto
+	binary.BigEndian.PutUint16(payload[16:18], uint16(req.InternalPort))
+	binary.BigEndian.PutUint16(payload[18:20], uint16(req.SuggestedPort))
+	copy(payload[20:36], to16(suggested))
+
+	return encodeRequest(OpMap, req.Lifetime, clientIP, payload), nil
+}
+
+// decodeResponse 解析响应
+func decodeResponse(buf []byte) (*response, error) {
+	if len(buf) > 0 && buf[0] != Version {
+		// NAT-PMP 服务器以版本 0 回应（报文比 PCP 头短）
+		return nil, fmt.Errorf("%w: server version %d", ErrUnsupportedVersion, buf[0])
+	}
+	if len(buf) < headerSize || len(buf) > maxPacketSize || len(buf)%4 != 0 {
+		return nil, fmt.Errorf("%w: bad length %d", ErrMalformedResponse, len(buf))
+	}
+	if buf[1]&responseBit == 0 {
+		return nil, fmt.Errorf("%w: not a response", ErrMalformedResponse)
+	}
+
+	resp := &response{
+		opcode:   buf[1] &^ responseBit,
+		result:   ResultCode(buf[3]),
+		lifetime: binary.BigEndian.Uint32(buf[4:8]),
+		epoch:    binary.BigEndian.Uint32(buf[8:12]),
+	}
+
+	if resp.opcode == OpMap && resp.result == ResultSuccess {
+		if len(buf) < headerSize+mapPayloadSize {
+			return nil, fmt.Errorf("%w: short MAP response", ErrMalformedResponse)
+		}
+		p := buf[headerSize:]
+		copy(resp.nonce[:], p[0:12])
+		resp.protocol = p[12]
+		resp.internalPort = binary.BigEndian.Uint16(p[16:18])
+		resp.externalPort = binary.BigEndian.Uint16(p[18:20])
+		resp.externalIP = net.IP(append([]byte(nil), p[20:36]...))
+		if v4 := resp.externalIP.To4(); v4 != nil {
+			resp.externalIP = v4
+		}
+	}
+	return resp, nil
+}
+
+// protocolNumber 返回协议名对应的协议号
+func protocolNumber(proto string) (byte, error) {
+	switch proto {
+	case "udp", "UDP":
+		return protoUDP, nil
+	case "tcp", "TCP":
+		return protoTCP, nil
+	}
+	return 0, fmt.Errorf("%w: unsupported protocol %q", ErrInvalidRequest, proto)
+}
+
+// to16 返回 16 字节地址（IPv4 转换为 IPv4 映射地址）
+func to16(ip net.IP) net.IP {
+	if ip16 := ip.To16(); ip16 != nil {
+		return ip16
+	}
+	return net.IPv6zero
+}
+
+// NAT64Suggestion IPv6 客户端通过 NAT64 请求 IPv4 外部地址时使用的建议地址
+var NAT64Suggestion = net.IPv4zero.To16()
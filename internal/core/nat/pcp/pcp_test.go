@@ -0,0 +1,414 @@
+package pcp
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================================
+//                              本地 PCP 服务器
+// ============================================================================
+
+// fakeServer 本地 UDP PCP 服务器
+type fakeServer struct {
+	t    *testing.T
+	conn *net.UDPConn
+
+	mu         sync.Mutex
+	version    byte
+	epoch      uint32
+	externalIP net.IP
+	lifetime   uint32 // 授予的最大租期（0 表示按请求授予）
+	mappings   map[[12]byte]uint16
+	requests   []*MapRequest
+	lifetimes  []uint32
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+
+	s := &fakeServer{
+		t:          t,
+		conn:       conn,
+		version:    Version,
+		epoch:      1000,
+		externalIP: net.IPv4(203, 0, 113, 7),
+		mappings:   make(map[[12]byte]uint16),
+	}
+	go s.serve()
+	t.Cleanup(func() { conn.Close() })
+	return s
+}
+
+func (s *fakeServer) addr() *net.UDPAddr {
+	return s.conn.LocalAddr().(*net.UDPAddr)
+}
+
+func (s *fakeServer) setVersion(v byte) {
+	s.mu.Lock()
+	s.version = v
+	s.mu.Unlock()
+}
+
+func (s *fakeServer) setLifetime(l uint32) {
+	s.mu.Lock()
+	s.lifetime = l
+	s.mu.Unlock()
+}
+
+// restart 模拟服务器重启：纪元归零、映射丢失
+func (s *fakeServer) restart() {
+	s.mu.Lock()
+	s.epoch = 0
+	s.mappings = make(map[[12]byte]uint16)
+	s.mu.Unlock()
+}
+
+func (s *fakeServer) mapRequests() ([]*MapRequest, []uint32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*MapRequest(nil), s.requests...), append([]uint32(nil), s.lifetimes...)
+}
+
+func (s *fakeServer) serve() {
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, from, err := s.conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		if resp := s.handle(buf[:n]); resp != nil {
+			s.conn.WriteToUDP(resp, from)
+		}
+	}
+}
+
+func (s *fakeServer) handle(pkt []byte) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.version != Version {
+		// NAT-PMP 服务器：版本 0，结果码 UNSUPP_VERSION
+		return []byte{s.version, pkt[1] | responseBit, 0, 1, 0, 0, 0, 0}
+	}
+	if len(pkt) < headerSize || pkt[0] != Version {
+		return nil
+	}
+
+	opcode := pkt[1]
+	lifetime := binary.BigEndian.Uint32(pkt[4:8])
+	resp := make([]byte, headerSize)
+	resp[0] = Version
+	resp[1] = opcode | responseBit
+	binary.BigEndian.PutUint32(resp[8:12], s.epoch)
+
+	if opcode != OpMap {
+		return resp
+	}
+
+	p := pkt[headerSize:]
+	req := &MapRequest{
+		Protocol:      map[byte]string{protoUDP: "udp", protoTCP: "tcp"}[p[12]],
+		InternalPort:  int(binary.BigEndian.Uint16(p[16:18])),
+		SuggestedPort: int(binary.BigEndian.Uint16(p[18:20])),
+		SuggestedIP:   net.IP(append([]byte(nil), p[20:36]...)),
+		Lifetime:      lifetime,
+	}
+	copy(req.Nonce[:], p[0:12])
+	s.requests = append(s.requests, req)
+
+	granted := lifetime
+	if s.lifetime > 0 && granted > s.lifetime {
+		granted = s.lifetime
+	}
+	s.lifetimes = append(s.lifetimes, granted)
+
+	external, ok := s.mappings[req.Nonce]
+	if !ok {
+		external = uint16(req.SuggestedPort)
+		if external == 0 {
+			external = 40000
+		}
+	}
+	if granted == 0 {
+		delete(s.mappings, req.Nonce)
+	} else {
+		s.mappings[req.Nonce] = external
+	}
+
+	binary.BigEndian.PutUint32(resp[4:8], granted)
+	payload := make([]byte, mapPayloadSize)
+	copy(payload, p[:mapPayloadSize])
+	binary.BigEndian.PutUint16(payload[18:20], external)
+	copy(payload[20:36], s.externalIP.To16())
+	return append(resp, payload...)
+}
+
+// announce 向 addr 发送 ANNOUNCE 响应
+func (s *fakeServer) announce(addr *net.UDPAddr) {
+	s.mu.Lock()
+	pkt := make([]byte, headerSize)
+	pkt[0] = Version
+	pkt[1] = OpAnnounce | responseBit
+	binary.BigEndian.PutUint32(pkt[8:12], s.epoch)
+	s.mu.Unlock()
+	_, err := s.conn.WriteToUDP(pkt, addr)
+	require.NoError(s.t, err)
+}
+
+func newTestMapper(t *testing.T, s *fakeServer, mutate func(*Config)) *PCPMapper {
+	cfg := DefaultConfig()
+	cfg.Server = s.addr()
+	cfg.Timeout = time.Second
+	cfg.AnnounceAddrs = nil
+	if mutate != nil {
+		mutate(cfg)
+	}
+	m, err := NewPCPMapper(cfg)
+	require.NoError(t, err)
+	return m
+}
+
+// ============================================================================
+//                              测试
+// ============================================================================
+
+func TestMessage_EncodeDecode(t *testing.T) {
+	req := &MapRequest{
+		Nonce:         [12]byte{1, 2, 3},
+		Protocol:      "udp",
+		InternalPort:  4001,
+		SuggestedPort: 4002,
+		Lifetime:      3600,
+	}
+	pkt, err := encodeMap(req, net.IPv4(192, 168, 1, 10))
+	require.NoError(t, err)
+	require.Len(t, pkt, headerSize+mapPayloadSize)
+	assert.Equal(t, byte(Version), pkt[0])
+	assert.Equal(t, OpMap, pkt[1])
+	assert.Equal(t, uint32(3600), binary.BigEndian.Uint32(pkt[4:8]))
+	assert.True(t, net.IP(pkt[8:24]).Equal(net.IPv4(192, 168, 1, 10)))
+	assert.Equal(t, protoUDP, pkt[headerSize+12])
+	// IPv4 客户端未指定建议地址时使用 IPv4 零地址
+	assert.True(t, net.IP(pkt[headerSize+20:headerSize+36]).Equal(net.IPv4zero))
+
+	// IPv6 客户端使用 IPv6 零地址
+	pkt6, err := encodeMap(req, net.ParseIP("2001:db8::1"))
+	require.NoError(t, err)
+	assert.Equal(t, net.IPv6zero, net.IP(pkt6[headerSize+20:headerSize+36]))
+
+	// NAT64 建议地址
+	req.SuggestedIP = NAT64Suggestion
+	pkt64, err := encodeMap(req, net.ParseIP("2001:db8::1"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte(NAT64Suggestion), pkt64[headerSize+20:headerSize+36])
+
+	// 构造响应并解析
+	resp := append([]byte(nil), pkt...)
+	resp[1] |= responseBit
+	binary.BigEndian.PutUint32(resp[8:12], 77)
+	copy(resp[headerSize+20:], net.IPv4(203, 0, 113, 1).To16())
+	decoded, err := decodeResponse(resp)
+	require.NoError(t, err)
+	assert.Equal(t, OpMap, decoded.opcode)
+	assert.Equal(t, uint32(77), decoded.epoch)
+	assert.Equal(t, req.Nonce, decoded.nonce)
+	assert.Equal(t, uint16(4002), decoded.externalPort)
+	assert.Equal(t, net.IPv4(203, 0, 113, 1).To4(), decoded.externalIP)
+
+	_, err = encodeMap(&MapRequest{Protocol: "sctp", InternalPort: 1}, net.IPv4zero)
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+	_, err = decodeResponse([]byte{0, 0x80, 0, 1, 0, 0, 0, 0})
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+	_, err = decodeResponse([]byte{Version, 0x80, 0, 0})
+	assert.ErrorIs(t, err, ErrMalformedResponse)
+}
+
+func TestClient_NATPMPServer(t *testing.T) {
+	s := newFakeServer(t)
+	s.setVersion(0)
+
+	err := NewClient(s.addr(), time.Second).Announce(context.Background())
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+	assert.False(t, Probe(context.Background(), s.addr(), time.Second))
+}
+
+func TestClient_Timeout(t *testing.T) {
+	// 绑定后不响应的端口
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	err = NewClient(conn.LocalAddr().(*net.UDPAddr), 300*time.Millisecond).Announce(context.Background())
+	assert.ErrorIs(t, err, ErrTimeout)
+}
+
+func TestProbe(t *testing.T) {
+	s := newFakeServer(t)
+	assert.True(t, Probe(context.Background(), s.addr(), time.Second))
+}
+
+func TestMapper_MapRenewUnmap(t *testing.T) {
+	s := newFakeServer(t)
+	m := newTestMapper(t, s, nil)
+
+	port, err := m.MapPort(context.Background(), "udp", 4001)
+	require.NoError(t, err)
+	assert.Equal(t, 4001, port)
+
+	ip, err := m.GetExternalAddress()
+	require.NoError(t, err)
+	assert.Equal(t, "203.0.113.7", ip.String())
+
+	// 续期复用同一个 nonce
+	_, err = m.MapPort(context.Background(), "udp", 4001)
+	require.NoError(t, err)
+	reqs, _ := s.mapRequests()
+	require.Len(t, reqs, 2)
+	assert.Equal(t, reqs[0].Nonce, reqs[1].Nonce)
+	assert.Equal(t, uint32(DefaultLifetime/time.Second), reqs[0].Lifetime)
+
+	// 删除映射发送租期 0
+	require.NoError(t, m.UnmapPort("udp", 4001))
+	reqs, _ = s.mapRequests()
+	require.Len(t, reqs, 3)
+	assert.Equal(t, uint32(0), reqs[2].Lifetime)
+	assert.Equal(t, reqs[0].Nonce, reqs[2].Nonce)
+	assert.Empty(t, m.Mappings())
+
+	err = m.UnmapPort("udp", 4001)
+	assert.ErrorIs(t, err, ErrNoMapping)
+}
+
+func TestMapper_NAT64(t *testing.T) {
+	s := newFakeServer(t)
+	m := newTestMapper(t, s, func(c *Config) { c.NAT64 = true })
+
+	_, err := m.MapPort(context.Background(), "tcp", 4001)
+	require.NoError(t, err)
+	reqs, _ := s.mapRequests()
+	require.Len(t, reqs, 1)
+	assert.Equal(t, NAT64Suggestion, reqs[0].SuggestedIP)
+	assert.Equal(t, "tcp", reqs[0].Protocol)
+}
+
+func TestMapper_ServerError(t *testing.T) {
+	s := newFakeServer(t)
+	m := newTestMapper(t, s, nil)
+
+	_, err := m.MapPort(context.Background(), "sctp", 4001)
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+
+	var mappingErr *MappingError
+	assert.True(t, errors.As(err, &mappingErr))
+}
+
+func TestMapper_AutoRenew(t *testing.T) {
+	s := newFakeServer(t)
+	s.setLifetime(2) // 服务器只授予 2 秒租期，1 秒后续期
+	m := newTestMapper(t, s, nil)
+	m.Start(context.Background())
+	defer m.Stop()
+
+	_, err := m.MapPort(context.Background(), "udp", 4001)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		reqs, _ := s.mapRequests()
+		return len(reqs) >= 2
+	}, 5*time.Second, 50*time.Millisecond)
+
+	reqs, _ := s.mapRequests()
+	assert.Equal(t, reqs[0].Nonce, reqs[1].Nonce)
+}
+
+func TestMapper_EpochResetRemaps(t *testing.T) {
+	s := newFakeServer(t)
+	m := newTestMapper(t, s, nil)
+	m.Start(context.Background())
+	defer m.Stop()
+
+	_, err := m.MapPort(context.Background(), "udp", 4001)
+	require.NoError(t, err)
+	_, err = m.MapPort(context.Background(), "udp", 4002)
+	require.NoError(t, err)
+
+	// 服务器重启后，下一次响应的纪元回退触发全部映射重建
+	s.restart()
+	_, err = m.MapPort(context.Background(), "udp", 4001)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		reqs, _ := s.mapRequests()
+		return len(reqs) >= 5
+	}, 5*time.Second, 50*time.Millisecond)
+
+	ports := map[int]bool{}
+	reqs, _ := s.mapRequests()
+	for _, r := range reqs[3:] {
+		ports[r.InternalPort] = true
+	}
+	assert.True(t, ports[4002], "4002 should be remapped after server restart")
+}
+
+func TestMapper_Announce(t *testing.T) {
+	// 预先分配监听端口
+	probe, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	announceAddr := probe.LocalAddr().(*net.UDPAddr)
+	probe.Close()
+
+	s := newFakeServer(t)
+	m := newTestMapper(t, s, func(c *Config) {
+		c.AnnounceAddrs = []string{announceAddr.String()}
+		c.AnnounceJitter = 100 * time.Millisecond
+	})
+	m.Start(context.Background())
+	defer m.Stop()
+
+	_, err = m.MapPort(context.Background(), "udp", 4001)
+	require.NoError(t, err)
+
+	// 纪元连续的 ANNOUNCE 不触发重建
+	s.announce(announceAddr)
+	time.Sleep(300 * time.Millisecond)
+	reqs, _ := s.mapRequests()
+	assert.Len(t, reqs, 1)
+
+	// 重启后的 ANNOUNCE 触发重建
+	s.restart()
+	s.announce(announceAddr)
+	require.Eventually(t, func() bool {
+		reqs, _ := s.mapRequests()
+		return len(reqs) >= 2
+	}, 5*time.Second, 50*time.Millisecond)
+
+	require.Len(t, m.Mappings(), 1)
+}
+
+func TestNewPCPMapper_Unsupported(t *testing.T) {
+	s := newFakeServer(t)
+	s.setVersion(0)
+
+	cfg := DefaultConfig()
+	cfg.Server = s.addr()
+	cfg.Timeout = time.Second
+	_, err := NewPCPMapper(cfg)
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+}
+
+func TestResultCode(t *testing.T) {
+	assert.Equal(t, "NO_RESOURCES", ResultNoResources.String())
+	assert.Equal(t, "RESULT_99", ResultCode(99).String())
+	assert.True(t, ResultNoResources.Transient())
+	assert.False(t, ResultNotAuthorized.Transient())
+}
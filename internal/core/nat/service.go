@@ -2,6 +2,7 @@ package nat
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"strconv"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/dep2p/go-dep2p/internal/core/nat/holepunch"
 	"github.com/dep2p/go-dep2p/internal/core/nat/natpmp"
+	"github.com/dep2p/go-dep2p/internal/core/nat/pcp"
 	"github.com/dep2p/go-dep2p/internal/core/nat/stun"
 	"github.com/dep2p/go-dep2p/internal/core/nat/upnp"
 	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
@@ -74,6 +76,7 @@ type Service struct {
 	stunClient    *stun.STUNClient
 	upnp          *upnp.UPnPMapper
 	natpmp        *natpmp.NATPMPMapper
+	pcp           *pcp.PCPMapper
 	puncher       *holepunch.HolePuncher
 	natDetector   *stun.NATTypeDetector
 
@@ -182,12 +185,13 @@ func NewService(config *Config, swarm pkgif.Swarm, eventbus pkgif.EventBus) (*Se
 		}
 	}
 
-	// NAT-001 优化：并行创建 UPnP 和 NAT-PMP 映射器
-	// 这两个操作都可能因为超时而阻塞，并行执行可以将总时间从 ~13秒 降低到 ~2秒
+	// NAT-001 优化：并行创建 UPnP、NAT-PMP 和 PCP 映射器
+	// 这些操作都可能因为超时而阻塞，并行执行可以将总时间从 ~13秒 降低到 ~2秒
 	var wg sync.WaitGroup
-	var upnpErr, natpmpErr error
+	var upnpErr, natpmpErr, pcpErr error
 	var upnpMapper *upnp.UPnPMapper
 	var natpmpMapper *natpmp.NATPMPMapper
+	var pcpMapper *pcp.PCPMapper
 
 	// 并行创建 UPnP 映射器
 	if config.EnableUPnP {
@@ -215,6 +219,19 @@ func NewService(config *Config, swarm pkgif.Swarm, eventbus pkgif.EventBus) (*Se
 		}()
 	}
 
+	// 并行创建 PCP 映射器
+	if config.EnablePCP {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			timeout := config.PCPTimeout
+			if timeout <= 0 {
+				timeout = pcp.DefaultTimeout
+			}
+			pcpMapper, pcpErr = pcp.NewPCPMapperWithTimeout(timeout)
+		}()
+	}
+
 	// 等待并行探测完成
 	wg.Wait()
 
@@ -240,6 +257,18 @@ func NewService(config *Config, swarm pkgif.Swarm, eventbus pkgif.EventBus) (*Se
 		}
 	}
 
+	// 处理 PCP 结果
+	if config.EnablePCP {
+		if pcpErr != nil {
+			logger.Debug("PCP 不可用（这在很多环境中是正常的）",
+				"error", pcpErr,
+				"timeout", config.PCPTimeout)
+		} else {
+			s.pcp = pcpMapper
+			logger.Debug("PCP 映射器已创建", "timeout", config.PCPTimeout)
+		}
+	}
+
 	// 创建 Hole Puncher
 	if config.EnableHolePunch {
 		// TD-001 已完成：完整的 DCUtR 打洞协议实现
@@ -334,6 +363,12 @@ func (s *Service) Start(_ context.Context, host pkgif.Host) error {
 		logger.Debug("NAT-PMP 映射续期已启动")
 	}
 
+	// 启动 PCP 映射续期和 ANNOUNCE 监听
+	if s.pcp != nil {
+		s.pcp.Start(s.ctx)
+		logger.Debug("PCP 映射续期已启动")
+	}
+
 	// 启动时自动执行 NAT 类型检测（异步，不阻塞启动）
 	// NAT 类型信息用于打洞决策：
 	// - Full Cone / Restricted Cone: 可直接打洞
@@ -376,7 +411,7 @@ func (s *Service) Stop() error {
 	logger.Info("正在停止 NAT 服务")
 	s.closed.Store(true)
 
-	// 停止 UPnP 和 NAT-PMP 续期循环
+	// 停止 UPnP、NAT-PMP 和 PCP 续期循环
 	if s.upnp != nil {
 		s.upnp.Stop()
 		logger.Debug("UPnP 已停止")
@@ -385,6 +420,10 @@ func (s *Service) Stop() error {
 		s.natpmp.Stop()
 		logger.Debug("NAT-PMP 已停止")
 	}
+	if s.pcp != nil {
+		s.pcp.Stop()
+		logger.Debug("PCP 已停止")
+	}
 
 	if s.cancel != nil {
 		s.cancel()
@@ -580,6 +619,13 @@ func (s *Service) MapPort(ctx context.Context, proto string, port int) (int, err
 		}
 	}
 
+	// 其次尝试 PCP（NAT-PMP 的后继协议）
+	if s.pcp != nil {
+		if extPort, err := s.pcp.MapPort(ctx, proto, port); err == nil {
+			return extPort, nil
+		}
+	}
+
 	// 回退到 NAT-PMP
 	if s.natpmp != nil {
 		if extPort, err := s.natpmp.MapPort(ctx, proto, port); err == nil {
@@ -610,6 +656,12 @@ func (s *Service) UnmapPort(proto string, port int) error {
 		}
 	}
 
+	if s.pcp != nil {
+		if err := s.pcp.UnmapPort(proto, port); err != nil && !errors.Is(err, pcp.ErrNoMapping) {
+			lastErr = err
+		}
+	}
+
 	if lastErr != nil {
 		return lastErr
 	}
@@ -889,6 +941,22 @@ func (s *Service) GetNATType() types.NATType {
 	return types.NATTypeUnknown
 }
 
+// FillCapabilities 填充 NAT 相关的本节点能力
+//
+// 通过 Identify 通告 NAT 类型、可达性和打洞支持，对端无需单独探测。
+func (s *Service) FillCapabilities(caps *types.PeerCapabilities) {
+	caps.NATType = s.GetNATType()
+	switch s.Reachability() {
+	case ReachabilityPublic:
+		caps.Reachability = types.ReachabilityPublic
+	case ReachabilityPrivate:
+		caps.Reachability = types.ReachabilityPrivate
+	default:
+		caps.Reachability = types.ReachabilityUnknown
+	}
+	caps.HolePunching = s.puncher != nil
+}
+
 // HolePuncher 返回 NAT 打洞器
 //
 // 返回内部的 HolePuncher 实例，用于 Realm Connector 进行 NAT 穿透。
@@ -899,23 +967,26 @@ func (s *Service) HolePuncher() *holepunch.HolePuncher {
 
 // SetReachabilityCoordinator 设置可达性协调器
 //
-// 用于将 STUN/UPnP/NAT-PMP 发现的地址上报到 Coordinator。
+// 用于将 STUN/UPnP/NAT-PMP/PCP 发现的地址上报到 Coordinator。
 // 应在 NAT 服务启动前或启动后立即调用。
-// 该方法会将 coordinator 传递给 UPnP 和 NAT-PMP Mapper。
+// 该方法会将 coordinator 传递给 UPnP、NAT-PMP 和 PCP Mapper。
 func (s *Service) SetReachabilityCoordinator(coordinator pkgif.ReachabilityCoordinator) {
 	s.coordinatorMu.Lock()
 	s.coordinator = coordinator
 	s.coordinatorMu.Unlock()
 
-	// 传递给 UPnP 和 NAT-PMP Mapper
+	// 传递给 UPnP、NAT-PMP 和 PCP Mapper
 	if s.upnp != nil {
 		s.upnp.SetReachabilityCoordinator(coordinator)
 	}
 	if s.natpmp != nil {
 		s.natpmp.SetReachabilityCoordinator(coordinator)
 	}
+	if s.pcp != nil {
+		s.pcp.SetReachabilityCoordinator(coordinator)
+	}
 
-	logger.Debug("Reachability Coordinator 已设置并传递给 UPnP/NATPMP")
+	logger.Debug("Reachability Coordinator 已设置并传递给 UPnP/NATPMP/PCP")
 }
 
 // SetTrustSTUNAddresses 设置 STUN 信任模式
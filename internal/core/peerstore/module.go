@@ -10,6 +10,7 @@ import (
 	"github.com/dep2p/go-dep2p/internal/core/peerstore/addrbook"
 	"github.com/dep2p/go-dep2p/internal/core/peerstore/keybook"
 	"github.com/dep2p/go-dep2p/internal/core/peerstore/metadata"
+	"github.com/dep2p/go-dep2p/internal/core/peerstore/nodedb"
 	"github.com/dep2p/go-dep2p/internal/core/peerstore/protobook"
 	"github.com/dep2p/go-dep2p/internal/core/storage/engine"
 	"github.com/dep2p/go-dep2p/internal/core/storage/kv"
@@ -23,6 +24,7 @@ const (
 	keyBookPrefix   = "k/" // 密钥簿前缀
 	protoBookPrefix = "p/" // 协议簿前缀
 	metadataPrefix  = "m/" // 元数据前缀
+	nodeDBPrefix    = "n/" // 节点数据库前缀
 )
 
 // Config Peerstore 配置
@@ -154,6 +156,13 @@ func NewPersistentPeerstore(eng engine.InternalEngine, keyDeserializer keybook.K
 		return nil, err
 	}
 
+	// 节点数据库同样持久化，重启后仍可从历史种子节点恢复
+	nodeDB, err := nodedb.NewPersistentDB(peerstoreKV.SubStore([]byte(nodeDBPrefix)), nodedb.DefaultConfig())
+	if err != nil {
+		addrBook.Close()
+		return nil, err
+	}
+
 	return &Peerstore{
 		addrBook:           addrBook,
 		keyBook:            keyBook,
@@ -163,6 +172,7 @@ func NewPersistentPeerstore(eng engine.InternalEngine, keyDeserializer keybook.K
 		persistentKeyBook:  keyBook,
 		persistentProto:    protoBook,
 		persistentMeta:     metadataStore,
+		nodeDB:             nodeDB,
 		closed:             false,
 	}, nil
 }
@@ -24,8 +24,15 @@
 //
 // # 实现
 //
-// 目前提供内存实现，适用于测试和开发。
-// 生产环境可使用 Storage 模块提供的持久化存储。
+// 提供两种实现：
+//   - MemoryDB：内存实现，适用于测试和开发
+//   - PersistentDB：基于 Storage 模块的持久化实现，节点重启后种子节点、
+//     拨号历史和 Pong 时间仍然可用
+//
+// PersistentDB 复用 MemoryDB 的过期、淘汰和种子选择逻辑，修改按
+// FlushInterval 批量写入存储引擎。配置 WithDataDir 时 Peerstore 直接创建
+// PersistentDB，启动时从存储引擎加载已有记录；通过 SetNodeDB 把空的
+// PersistentDB 换入时，由 MigrateFrom 迁移内存数据库中已有的记录。
 //
 // # 架构归属
 //
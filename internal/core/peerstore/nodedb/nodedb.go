@@ -90,6 +90,11 @@ type Config struct {
 	// 超过此次数后节点优先级降低
 	// 默认 5
 	MaxFailedDials int
+
+	// FlushInterval 持久化实现批量写入的间隔
+	// 内存实现忽略此项
+	// 默认 5 秒
+	FlushInterval time.Duration
 }
 
 // DefaultConfig 返回默认配置
@@ -99,6 +104,7 @@ func DefaultConfig() Config {
 		NodeExpiry:      7 * 24 * time.Hour,
 		CleanupInterval: 1 * time.Hour,
 		MaxFailedDials:  5,
+		FlushInterval:   5 * time.Second,
 	}
 }
 
@@ -149,6 +155,9 @@ type MemoryDB struct {
 	// 节点记录
 	nodes map[string]*NodeRecord
 
+	// onRemove 清理或淘汰节点时的回调（需要持有锁调用，持久化实现用于同步删除）
+	onRemove func(id string)
+
 	// 生命周期
 	ctx    chan struct{}
 	closed bool
@@ -444,7 +453,7 @@ func (db *MemoryDB) cleanupExpiredLocked() {
 	for id, node := range db.nodes {
 		age := now.Sub(node.LastSeen)
 		if age > db.config.NodeExpiry {
-			delete(db.nodes, id)
+			db.removeLocked(id)
 			count++
 		}
 	}
@@ -472,11 +481,48 @@ func (db *MemoryDB) removeOldestLocked() {
 	}
 
 	if !first {
-		delete(db.nodes, oldestID)
+		db.removeLocked(oldestID)
 		logger.Debug("删除最旧节点", "id", oldestID)
 	}
 }
 
+// removeLocked 清理或淘汰节点（需要持有锁）
+func (db *MemoryDB) removeLocked(id string) {
+	delete(db.nodes, id)
+	if db.onRemove != nil {
+		db.onRemove(id)
+	}
+}
+
+// restore 按原样写入节点记录（用于加载和迁移，保留拨号历史）
+func (db *MemoryDB) restore(node *NodeRecord) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.closed {
+		return
+	}
+	if _, exists := db.nodes[node.ID]; !exists && len(db.nodes) >= db.config.MaxNodes {
+		db.cleanupExpiredLocked()
+		if len(db.nodes) >= db.config.MaxNodes {
+			db.removeOldestLocked()
+		}
+	}
+	db.nodes[node.ID] = node.Clone()
+}
+
+// Records 返回所有节点记录的副本
+func (db *MemoryDB) Records() []*NodeRecord {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	records := make([]*NodeRecord, 0, len(db.nodes))
+	for _, node := range db.nodes {
+		records = append(records, node.Clone())
+	}
+	return records
+}
+
 // ============================================================================
 //                              统计
 // ============================================================================
@@ -0,0 +1,283 @@
+package nodedb
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/dep2p/go-dep2p/internal/core/storage/kv"
+)
+
+// 存储键
+var (
+	// recordPrefix 节点记录前缀，键为 r/<id>
+	recordPrefix = []byte("r/")
+
+	// migratedKey 已从内存数据库迁移的标记
+	migratedKey = []byte("migrated")
+)
+
+// ============================================================================
+//                              Persistent NodeDB 实现
+// ============================================================================
+
+// PersistentDB 基于存储引擎的节点数据库实现
+//
+// 查询和过期、淘汰、种子选择逻辑复用 MemoryDB，保证两种实现语义一致；
+// 修改只记录脏节点 ID，按 FlushInterval 批量写入存储引擎，
+// 避免频繁的 Pong 和拨号更新造成写放大。Close 时写入剩余修改。
+type PersistentDB struct {
+	mem   *MemoryDB
+	store *kv.Store
+
+	flushInterval time.Duration
+
+	// 待写入的节点 ID（记录不存在时写入为删除）
+	dirtyMu sync.Mutex
+	dirty   map[string]struct{}
+
+	// 生命周期
+	closeOnce sync.Once
+	done      chan struct{}
+	wg        sync.WaitGroup
+}
+
+var _ NodeDB = (*PersistentDB)(nil)
+
+// NewPersistentDB 创建持久化节点数据库
+//
+// 打开时加载已保存的记录，已过期的记录在首次写入时删除。
+func NewPersistentDB(store *kv.Store, config Config) (*PersistentDB, error) {
+	if config.FlushInterval == 0 {
+		config.FlushInterval = DefaultConfig().FlushInterval
+	}
+
+	db := &PersistentDB{
+		mem:           NewMemoryDB(config),
+		store:         store,
+		flushInterval: config.FlushInterval,
+		dirty:         make(map[string]struct{}),
+		done:          make(chan struct{}),
+	}
+
+	// 内存层清理或淘汰的节点同步从存储中删除
+	db.mem.mu.Lock()
+	db.mem.onRemove = db.markDirty
+	db.mem.mu.Unlock()
+
+	if err := db.load(); err != nil {
+		db.mem.Close()
+		return nil, err
+	}
+
+	db.wg.Add(1)
+	go db.flushLoop()
+
+	logger.Info("持久化节点数据库已创建", "nodes", db.mem.Size(), "maxNodes", db.mem.config.MaxNodes)
+	return db, nil
+}
+
+// load 从存储加载节点记录
+func (db *PersistentDB) load() error {
+	now := time.Now()
+	expiry := db.mem.config.NodeExpiry
+
+	var loadErr error
+	err := db.store.PrefixScan(recordPrefix, func(key, value []byte) bool {
+		node := &NodeRecord{}
+		if err := json.Unmarshal(value, node); err != nil {
+			loadErr = err
+			return false
+		}
+		if node.ID == "" || now.Sub(node.LastSeen) > expiry {
+			db.markDirty(string(key[len(recordPrefix):]))
+			return true
+		}
+		db.mem.restore(node)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	return loadErr
+}
+
+// UpdateNode 更新节点信息
+func (db *PersistentDB) UpdateNode(node *NodeRecord) error {
+	if err := db.mem.UpdateNode(node); err != nil {
+		return err
+	}
+	db.markDirty(node.ID)
+	return nil
+}
+
+// GetNode 获取节点信息
+func (db *PersistentDB) GetNode(id string) *NodeRecord {
+	return db.mem.GetNode(id)
+}
+
+// RemoveNode 删除节点
+func (db *PersistentDB) RemoveNode(id string) error {
+	if err := db.mem.RemoveNode(id); err != nil {
+		return err
+	}
+	db.markDirty(id)
+	return nil
+}
+
+// QuerySeeds 查询种子节点
+func (db *PersistentDB) QuerySeeds(count int, maxAge time.Duration) []*NodeRecord {
+	return db.mem.QuerySeeds(count, maxAge)
+}
+
+// LastPongReceived 获取最后 Pong 时间
+func (db *PersistentDB) LastPongReceived(id string) time.Time {
+	return db.mem.LastPongReceived(id)
+}
+
+// UpdateLastPong 更新最后 Pong 时间
+func (db *PersistentDB) UpdateLastPong(id string, t time.Time) error {
+	if err := db.mem.UpdateLastPong(id, t); err != nil {
+		return err
+	}
+	db.markDirty(id)
+	return nil
+}
+
+// UpdateDialAttempt 更新拨号尝试
+func (db *PersistentDB) UpdateDialAttempt(id string, success bool) error {
+	if err := db.mem.UpdateDialAttempt(id, success); err != nil {
+		return err
+	}
+	db.markDirty(id)
+	return nil
+}
+
+// Size 返回节点数量
+func (db *PersistentDB) Size() int {
+	return db.mem.Size()
+}
+
+// Stats 返回数据库统计
+func (db *PersistentDB) Stats() DBStats {
+	return db.mem.Stats()
+}
+
+// MigrateFrom 从内存数据库迁移节点记录
+//
+// 只在首次使用时执行：存在迁移标记时直接返回。已有记录不会被覆盖，
+// 迁移的记录保留拨号历史和 Pong 时间。返回迁移的记录数。
+func (db *PersistentDB) MigrateFrom(src *MemoryDB) (int, error) {
+	migrated, err := db.store.Has(migratedKey)
+	if err != nil {
+		return 0, err
+	}
+	if migrated || src == nil {
+		return 0, nil
+	}
+
+	count := 0
+	for _, node := range src.Records() {
+		if db.mem.GetNode(node.ID) != nil {
+			continue
+		}
+		db.mem.restore(node)
+		db.markDirty(node.ID)
+		count++
+	}
+
+	if err := db.Flush(); err != nil {
+		return count, err
+	}
+	if err := db.store.Put(migratedKey, []byte{1}); err != nil {
+		return count, err
+	}
+
+	logger.Info("已从内存节点数据库迁移", "count", count)
+	return count, nil
+}
+
+// Flush 立即写入所有待写入的修改
+func (db *PersistentDB) Flush() error {
+	db.dirtyMu.Lock()
+	dirty := db.dirty
+	db.dirty = make(map[string]struct{})
+	db.dirtyMu.Unlock()
+
+	if len(dirty) == 0 {
+		return nil
+	}
+
+	batch := db.store.NewBatch()
+	for id := range dirty {
+		key := append(append([]byte{}, recordPrefix...), id...)
+		if node := db.mem.GetNode(id); node != nil {
+			if err := batch.PutJSON(key, node); err != nil {
+				db.remark(dirty)
+				return err
+			}
+		} else {
+			batch.Delete(key)
+		}
+	}
+
+	if err := batch.Write(); err != nil {
+		// 写入失败时保留修改，下次重试
+		db.remark(dirty)
+		return err
+	}
+	return nil
+}
+
+// Close 关闭数据库
+//
+// 写入剩余修改后关闭，不关闭底层存储引擎。
+func (db *PersistentDB) Close() error {
+	var err error
+	db.closeOnce.Do(func() {
+		close(db.done)
+		db.wg.Wait()
+
+		if err = db.Flush(); err != nil {
+			logger.Warn("写入节点数据库失败", "error", err)
+		}
+		db.mem.Close()
+		logger.Info("持久化节点数据库已关闭")
+	})
+	return err
+}
+
+// markDirty 标记节点待写入
+func (db *PersistentDB) markDirty(id string) {
+	db.dirtyMu.Lock()
+	db.dirty[id] = struct{}{}
+	db.dirtyMu.Unlock()
+}
+
+// remark 重新标记写入失败的节点
+func (db *PersistentDB) remark(ids map[string]struct{}) {
+	db.dirtyMu.Lock()
+	for id := range ids {
+		db.dirty[id] = struct{}{}
+	}
+	db.dirtyMu.Unlock()
+}
+
+// flushLoop 定期批量写入
+func (db *PersistentDB) flushLoop() {
+	defer db.wg.Done()
+
+	ticker := time.NewTicker(db.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-db.done:
+			return
+		case <-ticker.C:
+			if err := db.Flush(); err != nil {
+				logger.Warn("写入节点数据库失败", "error", err)
+			}
+		}
+	}
+}
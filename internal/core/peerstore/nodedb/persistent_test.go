@@ -0,0 +1,160 @@
+package nodedb
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dep2p/go-dep2p/internal/core/storage/engine"
+	"github.com/dep2p/go-dep2p/internal/core/storage/engine/badger"
+	"github.com/dep2p/go-dep2p/internal/core/storage/kv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestStore 创建测试用存储
+func newTestStore(t *testing.T) *kv.Store {
+	t.Helper()
+
+	eng, err := badger.New(engine.DefaultConfig(filepath.Join(t.TempDir(), "nodedb.db")))
+	require.NoError(t, err)
+	t.Cleanup(func() { eng.Close() })
+	return kv.New(eng, []byte("n/"))
+}
+
+func TestPersistentDB_ReloadAfterClose(t *testing.T) {
+	store := newTestStore(t)
+
+	db, err := NewPersistentDB(store, DefaultConfig())
+	require.NoError(t, err)
+
+	pong := time.Now().Add(-time.Minute).Truncate(time.Millisecond)
+	require.NoError(t, db.UpdateNode(&NodeRecord{
+		ID:       "peer1",
+		Addrs:    []string{"/ip4/1.2.3.4/tcp/4001"},
+		LastSeen: time.Now(),
+	}))
+	require.NoError(t, db.UpdateLastPong("peer1", pong))
+	require.NoError(t, db.UpdateDialAttempt("peer1", false))
+	require.NoError(t, db.Close())
+
+	db, err = NewPersistentDB(store, DefaultConfig())
+	require.NoError(t, err)
+	defer db.Close()
+
+	node := db.GetNode("peer1")
+	require.NotNil(t, node)
+	assert.Equal(t, []string{"/ip4/1.2.3.4/tcp/4001"}, node.Addrs)
+	assert.Equal(t, 1, node.FailedDials)
+	assert.True(t, pong.Equal(db.LastPongReceived("peer1")))
+	assert.Len(t, db.QuerySeeds(10, time.Hour), 1)
+}
+
+func TestPersistentDB_ExpiredOnLoad(t *testing.T) {
+	store := newTestStore(t)
+
+	config := DefaultConfig()
+	config.NodeExpiry = time.Hour
+
+	require.NoError(t, store.PutJSON([]byte("r/old"), &NodeRecord{
+		ID:       "old",
+		LastSeen: time.Now().Add(-2 * time.Hour),
+	}))
+
+	db, err := NewPersistentDB(store, config)
+	require.NoError(t, err)
+
+	assert.Nil(t, db.GetNode("old"))
+	require.NoError(t, db.Close())
+
+	has, err := store.Has([]byte("r/old"))
+	require.NoError(t, err)
+	assert.False(t, has, "过期记录应从存储中删除")
+}
+
+func TestPersistentDB_EvictionDeletes(t *testing.T) {
+	store := newTestStore(t)
+
+	config := DefaultConfig()
+	config.MaxNodes = 2
+
+	db, err := NewPersistentDB(store, config)
+	require.NoError(t, err)
+
+	now := time.Now()
+	require.NoError(t, db.UpdateNode(&NodeRecord{ID: "peer1", LastSeen: now.Add(-2 * time.Minute)}))
+	require.NoError(t, db.UpdateNode(&NodeRecord{ID: "peer2", LastSeen: now.Add(-time.Minute)}))
+	require.NoError(t, db.Flush())
+
+	// 超出容量时淘汰最旧的节点
+	require.NoError(t, db.UpdateNode(&NodeRecord{ID: "peer3", LastSeen: now}))
+	require.NoError(t, db.Close())
+
+	has, err := store.Has([]byte("r/peer1"))
+	require.NoError(t, err)
+	assert.False(t, has)
+
+	db, err = NewPersistentDB(store, config)
+	require.NoError(t, err)
+	defer db.Close()
+	assert.Equal(t, 2, db.Size())
+	assert.NotNil(t, db.GetNode("peer3"))
+}
+
+func TestPersistentDB_MigrateFrom(t *testing.T) {
+	store := newTestStore(t)
+
+	mem := NewMemoryDB(DefaultConfig())
+	defer mem.Close()
+	require.NoError(t, mem.UpdateNode(&NodeRecord{ID: "peer1", LastSeen: time.Now()}))
+	require.NoError(t, mem.UpdateDialAttempt("peer1", true))
+
+	db, err := NewPersistentDB(store, DefaultConfig())
+	require.NoError(t, err)
+	defer db.Close()
+
+	n, err := db.MigrateFrom(mem)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	require.NotNil(t, db.GetNode("peer1"))
+	assert.False(t, db.GetNode("peer1").LastDial.IsZero())
+
+	has, err := store.Has([]byte("r/peer1"))
+	require.NoError(t, err)
+	assert.True(t, has, "迁移的记录应立即写入")
+
+	// 只迁移一次
+	require.NoError(t, mem.UpdateNode(&NodeRecord{ID: "peer2", LastSeen: time.Now()}))
+	n, err = db.MigrateFrom(mem)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.Nil(t, db.GetNode("peer2"))
+}
+
+func TestPersistentDB_BatchedWrites(t *testing.T) {
+	store := newTestStore(t)
+
+	config := DefaultConfig()
+	config.FlushInterval = time.Hour
+
+	db, err := NewPersistentDB(store, config)
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.NoError(t, db.UpdateNode(&NodeRecord{ID: "peer1", LastSeen: time.Now()}))
+
+	has, err := store.Has([]byte("r/peer1"))
+	require.NoError(t, err)
+	assert.False(t, has, "修改应等待批量写入")
+
+	require.NoError(t, db.Flush())
+	has, err = store.Has([]byte("r/peer1"))
+	require.NoError(t, err)
+	assert.True(t, has)
+
+	require.NoError(t, db.RemoveNode("peer1"))
+	require.NoError(t, db.Flush())
+	has, err = store.Has([]byte("r/peer1"))
+	require.NoError(t, err)
+	assert.False(t, has)
+}
@@ -431,7 +431,20 @@ func (ps *Peerstore) NodeDB() nodedb.NodeDB {
 }
 
 // SetNodeDB 设置节点数据库（用于依赖注入）
+//
+// 从内存数据库切换到空的持久化数据库时，先把内存中已有的记录（含种子节点
+// 的拨号历史）迁移过去，随后关闭内存数据库。
 func (ps *Peerstore) SetNodeDB(db nodedb.NodeDB) {
+	if mem, ok := ps.nodeDB.(*nodedb.MemoryDB); ok {
+		if persistent, ok := db.(*nodedb.PersistentDB); ok {
+			if persistent.Size() == 0 {
+				if _, err := persistent.MigrateFrom(mem); err != nil {
+					logger.Warn("迁移节点数据库失败", "error", err)
+				}
+			}
+			mem.Close()
+		}
+	}
 	ps.nodeDB = db
 }
 
@@ -2,9 +2,14 @@ package peerstore
 
 import (
 	"context"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/dep2p/go-dep2p/internal/core/peerstore/nodedb"
+	"github.com/dep2p/go-dep2p/internal/core/storage/engine"
+	"github.com/dep2p/go-dep2p/internal/core/storage/engine/badger"
+	"github.com/dep2p/go-dep2p/internal/core/storage/kv"
 	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
 	"github.com/dep2p/go-dep2p/pkg/types"
 	"github.com/stretchr/testify/assert"
@@ -593,6 +598,39 @@ func TestPeerstore_SetNodeDB(t *testing.T) {
 	assert.NotNil(t, ps.NodeDB())
 }
 
+func TestPeerstore_SetNodeDB_MigratesSeeds(t *testing.T) {
+	ps := NewPeerstore()
+	defer ps.Close()
+
+	// 内存数据库中的种子节点
+	require.NoError(t, ps.UpdateNodeRecord(types.PeerID("seed-1"), []string{"/ip4/1.2.3.4/tcp/4001"}))
+	require.NoError(t, ps.UpdateNodeRecord(types.PeerID("seed-2"), []string{"/ip4/5.6.7.8/udp/4001/quic-v1"}))
+	require.NoError(t, ps.NodeDB().UpdateDialAttempt("seed-1", true))
+
+	eng, err := badger.New(engine.DefaultConfig(filepath.Join(t.TempDir(), "peerstore.db")))
+	require.NoError(t, err)
+	defer eng.Close()
+	store := kv.New(eng, []byte("n/"))
+
+	persistent, err := nodedb.NewPersistentDB(store, nodedb.DefaultConfig())
+	require.NoError(t, err)
+	ps.SetNodeDB(persistent)
+
+	require.Equal(t, 2, ps.NodeDBSize())
+	seed := ps.GetNodeRecord(types.PeerID("seed-1"))
+	require.NotNil(t, seed)
+	assert.Equal(t, []string{"/ip4/1.2.3.4/tcp/4001"}, seed.Addrs)
+	assert.False(t, seed.LastDial.IsZero(), "迁移应保留拨号历史")
+
+	// 重新打开后种子仍在
+	require.NoError(t, persistent.Close())
+	reopened, err := nodedb.NewPersistentDB(store, nodedb.DefaultConfig())
+	require.NoError(t, err)
+	defer reopened.Close()
+	assert.NotNil(t, reopened.GetNode("seed-1"))
+	assert.NotNil(t, reopened.GetNode("seed-2"))
+}
+
 func TestPeerstore_AddrBook(t *testing.T) {
 	ps := NewPeerstore()
 	defer ps.Close()
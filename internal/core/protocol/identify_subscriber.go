@@ -158,6 +158,12 @@ func (s *IdentifySubscriber) handlePeerConnected(peerID string) {
 		}
 	}
 
+	// 保存对端协议列表，建流时据此直接选择对端支持的协议变体
+	identify.StoreProtocols(s.host.Peerstore(), types.PeerID(peerID), info.Protocols)
+
+	// 保存对端能力块，供中继选择、Gossip 候选选择和拨号排序使用
+	identify.StoreCapabilities(s.host.Peerstore(), types.PeerID(peerID), info.Capabilities)
+
 	// P0 修复：将 ObservedAddr 写入本机的 ObservedAddrManager
 	// 这是远端看到的我方地址（可能是公网地址）
 	if info.ObservedAddr != "" {
@@ -28,6 +28,7 @@ func Module() fx.Option {
 			ProvideNegotiator,
 			ProvideRouter,
 			ProvideIdentifySubscriber,
+			ProvideIdentifyService,
 		),
 		// 注册系统协议
 		fx.Invoke(registerSystemProtocols),
@@ -109,12 +110,30 @@ func ProvideRouter(registry pkgif.ProtocolRegistry, negotiator pkgif.ProtocolNeg
 	return NewRouter(registry.(*Registry), negotiator.(*Negotiator))
 }
 
+// identifyServiceOutput Identify 服务输出
+type identifyServiceOutput struct {
+	fx.Out
+
+	Service  *identify.Service
+	Registry pkgif.CapabilityRegistry
+}
+
+// ProvideIdentifyService 提供 Identify 服务
+//
+// 同时作为 CapabilityRegistry 提供，各子系统通过它注册本节点能力。
+func ProvideIdentifyService(host pkgif.Host, registry pkgif.ProtocolRegistry) identifyServiceOutput {
+	service := identify.NewService(host, registry)
+	return identifyServiceOutput{Service: service, Registry: service}
+}
+
 // systemProtocolsInput 系统协议注册输入
 type systemProtocolsInput struct {
 	fx.In
 
-	Registry pkgif.ProtocolRegistry
-	Host     pkgif.Host // 移除 name 标签
+	Lifecycle fx.Lifecycle
+	Registry  pkgif.ProtocolRegistry
+	Host      pkgif.Host // 移除 name 标签
+	Identify  *identify.Service
 }
 
 // registerSystemProtocols 注册系统协议
@@ -130,16 +149,30 @@ func registerSystemProtocols(input systemProtocolsInput) error {
 
 	logger.Info("开始注册系统协议", "nodeID", host.ID())
 
-	// Ping 协议 - 只通过 SetStreamHandler 注册（统一入口）
+	// Ping 协议 - 只通过 SetStreamHandler 注册（统一入口），无副作用，声明为幂等
 	pingService := ping.NewService()
-	host.SetStreamHandler(ping.ProtocolID, pingService.Handler)
+	host.SetStreamHandler(ping.ProtocolID, pingService.Handler, pkgif.WithIdempotent())
 	logger.Debug("Ping 协议已注册", "protocolID", ping.ProtocolID)
 
-	// Identify 协议 - 只通过 SetStreamHandler 注册（统一入口）
-	idService := identify.NewService(host, registry)
-	host.SetStreamHandler(identify.ProtocolID, idService.Handler)
+	// Identify 协议 - 只通过 SetStreamHandler 注册（统一入口），只读查询，声明为幂等
+	idService := input.Identify
+	host.SetStreamHandler(identify.ProtocolID, idService.Handler, pkgif.WithIdempotent())
 	logger.Debug("Identify 协议已注册", "protocolID", identify.ProtocolID)
 
+	// Identify Push 协议 - 接收对端地址和能力变化
+	host.SetStreamHandler(identify.ProtocolIDPush, idService.PushHandler)
+	logger.Debug("Identify Push 协议已注册", "protocolID", identify.ProtocolIDPush)
+
+	input.Lifecycle.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			// 使用独立上下文：OnStart 的 ctx 在启动完成后即取消
+			return idService.Start(context.Background())
+		},
+		OnStop: func(_ context.Context) error {
+			return idService.Stop()
+		},
+	})
+
 	// 验证注册成功
 	registeredProtocols := registry.Protocols()
 	if len(registeredProtocols) < 2 {
@@ -151,7 +184,7 @@ func registerSystemProtocols(input systemProtocolsInput) error {
 
 	logger.Info("系统协议注册完成",
 		"nodeID", host.ID(),
-		"protocols", []string{ping.ProtocolID, identify.ProtocolID})
+		"protocols", []string{ping.ProtocolID, identify.ProtocolID, identify.ProtocolIDPush})
 
 	return nil
 }
@@ -5,10 +5,12 @@
 //   - 支持的协议列表
 //   - 监听地址
 //   - 代理版本
+//   - 能力块（角色、NAT 类型、可达性、打洞、传输、负载提示、Realm 提示）
 //
 // # 协议 ID
 //
 //   /dep2p/identify/1.0.0
+//   /dep2p/sys/identify/push/1.0.0（Identify Push）
 //
 // # 流程
 //
@@ -16,6 +18,18 @@
 //  2. 双方交换 Identify 消息
 //  3. 更新 Peerstore 中的节点信息
 //
+// 本地地址或能力变化时，通过 Identify Push 主动推送给已连接节点。
+// 负载提示变化不触发推送。
+//
+// # 能力块
+//
+// 能力块保存在 Peerstore 元数据 types.PeerCapabilitiesKey 下，
+// 通过 PeerCapabilities 读取。各子系统（NAT、Relay、Realm、Bootstrap）
+// 通过 CapabilityRegistry 注册能力提供者填充本地能力块，
+// 应用也可注册提供者通告 gateway、witness 等角色。
+//
+// Realm 提示使用 types.HashRealm 以通告方 PeerID 加盐计算，不泄露 RealmID。
+//
 // # 使用
 //
 // identify 协议由 Host 自动注册和处理，用户无需手动调用。
@@ -5,12 +5,18 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"sync"
+	"time"
 
+	"github.com/dep2p/go-dep2p/internal/core/peerstore"
 	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/log"
 	"github.com/dep2p/go-dep2p/pkg/protocol"
 	"github.com/dep2p/go-dep2p/pkg/types"
 )
 
+var logger = log.Logger("protocol/identify")
+
 // 协议 ID（使用统一定义）
 var (
 	// ProtocolID Identify 协议 ID
@@ -21,8 +27,16 @@ var (
 )
 
 var (
-	// ErrPushNotImplemented Push 协议尚未实现
-	ErrPushNotImplemented = errors.New("identify push protocol not implemented in v1.0")
+	// ErrNoHost 未设置 Host
+	ErrNoHost = errors.New("identify: host not available")
+)
+
+const (
+	// pushCheckInterval 检查本节点地址和能力变化的间隔
+	pushCheckInterval = 30 * time.Second
+
+	// pushTimeout 单个节点的 Push 超时
+	pushTimeout = 5 * time.Second
 )
 
 // IdentifyInfo 节点身份信息
@@ -47,14 +61,30 @@ type IdentifyInfo struct {
 
 	// ProtocolVersion 协议版本
 	ProtocolVersion string `json:"protocol_version"`
+
+	// Capabilities 节点能力块（旧版本节点不携带）
+	Capabilities *types.PeerCapabilities `json:"capabilities,omitempty"`
 }
 
 // Service Identify 服务
 type Service struct {
 	host     pkgif.Host
 	registry pkgif.ProtocolRegistry
+
+	// 能力提供者
+	providersMu sync.RWMutex
+	providers   []pkgif.CapabilityProvider
+
+	// 最近一次通告的地址和能力（用于检测变化并触发 Push）
+	lastAddrs []string
+	lastCaps  *types.PeerCapabilities
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
 }
 
+var _ pkgif.CapabilityRegistry = (*Service)(nil)
+
 // NewService 创建 Identify 服务
 func NewService(host pkgif.Host, registry pkgif.ProtocolRegistry) *Service {
 	return &Service{
@@ -80,6 +110,7 @@ func (s *Service) Handler(stream pkgif.Stream) {
 		Protocols:       s.getProtocols(),
 		AgentVersion:    "go-dep2p/1.0.0",
 		ProtocolVersion: "dep2p/1.0.0",
+		Capabilities:    s.LocalCapabilities(),
 	}
 
 	// Phase 11 修复：正确填充 PublicKey
@@ -108,8 +139,8 @@ func (s *Service) Handler(stream pkgif.Stream) {
 // Identify 主动识别节点（客户端）
 // 返回远端节点的身份信息
 func Identify(ctx context.Context, host pkgif.Host, peer string) (*IdentifyInfo, error) {
-	// 1. 创建流
-	stream, err := host.NewStream(ctx, peer, ProtocolID)
+	// 1. 创建流（Identify 请求只读取对端信息，允许使用 0-RTT 早期数据）
+	stream, err := host.NewStream(pkgif.WithEarlyData(ctx), peer, ProtocolID)
 	if err != nil {
 		return nil, err
 	}
@@ -157,10 +188,232 @@ func ObserveAddr(stream pkgif.Stream) string {
 	return ""
 }
 
-// Push 推送身份更新（v1.1+，暂不实现）
+// AddCapabilityProvider 注册能力提供者
+func (s *Service) AddCapabilityProvider(provider pkgif.CapabilityProvider) {
+	if provider == nil {
+		return
+	}
+	s.providersMu.Lock()
+	s.providers = append(s.providers, provider)
+	s.providersMu.Unlock()
+}
+
+// LocalCapabilities 返回本节点当前能力
+//
+// 传输协议和连接数由 Host 得出，其余能力由各子系统注册的提供者填充。
+func (s *Service) LocalCapabilities() *types.PeerCapabilities {
+	caps := types.NewPeerCapabilities()
+
+	if s.host != nil {
+		for _, addr := range s.host.Addrs() {
+			if t := types.AddrTransport(addr); t != "" && !containsString(caps.Transports, t) {
+				caps.Transports = append(caps.Transports, t)
+			}
+		}
+		if network := s.host.Network(); network != nil {
+			caps.Load = &types.LoadHint{Conns: len(network.Conns())}
+		}
+	}
+
+	s.providersMu.RLock()
+	providers := s.providers
+	s.providersMu.RUnlock()
+	for _, provider := range providers {
+		provider(caps)
+	}
+	return caps
+}
+
+// Start 启动地址和能力变化检测，变化时向已连接节点推送
+func (s *Service) Start(ctx context.Context) error {
+	if s.host == nil {
+		return nil
+	}
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.lastAddrs = s.host.Addrs()
+	s.lastCaps = s.LocalCapabilities()
+
+	s.wg.Add(1)
+	go s.pushLoop(ctx)
+	return nil
+}
+
+// Stop 停止变化检测
+func (s *Service) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+	return nil
+}
+
+// pushLoop 定期检查本节点地址和能力，变化时推送给所有已连接节点
+func (s *Service) pushLoop(ctx context.Context) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(pushCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			addrs := s.host.Addrs()
+			caps := s.LocalCapabilities()
+			if equalStrings(addrs, s.lastAddrs) && caps.Equal(s.lastCaps) {
+				continue
+			}
+			s.lastAddrs, s.lastCaps = addrs, caps
+			logger.Debug("本节点地址或能力已变化，推送 Identify", "addrs", len(addrs), "roles", caps.Roles)
+			s.PushAll(ctx)
+		}
+	}
+}
+
+// PushAll 向所有已连接节点推送身份更新
+func (s *Service) PushAll(ctx context.Context) {
+	if s.host == nil || s.host.Network() == nil {
+		return
+	}
+	for _, peer := range s.host.Network().Peers() {
+		go func(peer string) {
+			pctx, cancel := context.WithTimeout(ctx, pushTimeout)
+			defer cancel()
+			if err := s.Push(pctx, peer); err != nil {
+				logger.Debug("Identify Push 失败", "peer", types.PeerID(peer).ShortString(), "error", err)
+			}
+		}(peer)
+	}
+}
+
+// Push 向指定节点推送身份更新
+//
+// 推送当前监听地址、协议列表和能力块。
+func (s *Service) Push(ctx context.Context, peer string) error {
+	if s.host == nil {
+		return ErrNoHost
+	}
+
+	stream, err := s.host.NewStream(ctx, peer, ProtocolIDPush)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	info := &IdentifyInfo{
+		PeerID:       s.host.ID(),
+		ListenAddrs:  s.host.Addrs(),
+		Protocols:    s.getProtocols(),
+		Capabilities: s.LocalCapabilities(),
+	}
+	return json.NewEncoder(stream).Encode(info)
+}
+
+// PushHandler 处理 Identify Push（接收端）
+//
+// 只接受与连接远端一致的 PeerID，更新 Peerstore 中的地址、协议和能力。
+func (s *Service) PushHandler(stream pkgif.Stream) {
+	defer stream.Close()
+
+	if s.host == nil || stream.Conn() == nil {
+		return
+	}
+
+	info := &IdentifyInfo{}
+	if err := json.NewDecoder(stream).Decode(info); err != nil {
+		logger.Debug("解析 Identify Push 失败", "error", err)
+		return
+	}
+
+	remote := stream.Conn().RemotePeer()
+	if info.PeerID != "" && types.PeerID(info.PeerID) != remote {
+		logger.Debug("丢弃 PeerID 不符的 Identify Push", "remote", remote.ShortString())
+		return
+	}
+
+	ps := s.host.Peerstore()
+	if ps == nil {
+		return
+	}
+
+	var maddrs []types.Multiaddr
+	for _, addr := range info.ListenAddrs {
+		if ma, err := types.NewMultiaddr(addr); err == nil {
+			maddrs = append(maddrs, ma)
+		}
+	}
+	if len(maddrs) > 0 {
+		ps.AddAddrs(remote, maddrs, peerstore.ConnectedAddrTTL)
+	}
+
+	StoreProtocols(ps, remote, info.Protocols)
+	StoreCapabilities(ps, remote, info.Capabilities)
+}
+
+// StoreProtocols 将对端通告的协议列表保存到 Peerstore
 //
-// 该功能计划在 v1.1 版本中实现。
-// 当前版本调用此方法将返回 ErrPushNotImplemented。
-func (s *Service) Push(_ context.Context, _ string) error {
-	return ErrPushNotImplemented
+// Host 建流时据此直接选择对端支持的协议变体，省去逐个协商的往返。
+// protocols 为空时不做修改。
+func StoreProtocols(ps pkgif.Peerstore, peer types.PeerID, protocols []string) {
+	if ps == nil || len(protocols) == 0 {
+		return
+	}
+	protos := make([]types.ProtocolID, len(protocols))
+	for i, p := range protocols {
+		protos[i] = types.ProtocolID(p)
+	}
+	if err := ps.SetProtocols(peer, protos...); err != nil {
+		logger.Debug("保存节点协议失败", "peer", peer.ShortString(), "error", err)
+	}
+}
+
+// StoreCapabilities 将节点能力保存到 Peerstore 元数据
+//
+// caps 为 nil（旧版本节点）时不做修改。
+func StoreCapabilities(ps pkgif.Peerstore, peer types.PeerID, caps *types.PeerCapabilities) {
+	if ps == nil || caps == nil {
+		return
+	}
+	if err := ps.Put(peer, types.PeerCapabilitiesKey, caps); err != nil {
+		logger.Debug("保存节点能力失败", "peer", peer.ShortString(), "error", err)
+	}
+}
+
+// PeerCapabilities 从 Peerstore 读取节点能力
+//
+// 对端未通告能力时返回 nil。
+func PeerCapabilities(ps pkgif.Peerstore, peer types.PeerID) *types.PeerCapabilities {
+	if ps == nil {
+		return nil
+	}
+	v, err := ps.Get(peer, types.PeerCapabilitiesKey)
+	if err != nil {
+		return nil
+	}
+	caps, _ := types.ParsePeerCapabilities(v)
+	return caps
+}
+
+// containsString 切片是否包含字符串
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// equalStrings 比较字符串切片
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
 }
@@ -2,10 +2,16 @@ package identify
 
 import (
 	"context"
-	"errors"
+	"encoding/json"
+	"net"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/types"
+	"github.com/dep2p/go-dep2p/tests/mocks"
 )
 
 // ============================================================================
@@ -13,22 +19,116 @@ import (
 // ============================================================================
 
 func TestService_Push(t *testing.T) {
+	// 发送端推送，接收端的 PushHandler 从同一管道读取
+	sendSide, recvSide := net.Pipe()
+
+	sender := mocks.NewMockHost("peer-a")
+	sender.AddrsValue = []string{"/ip4/1.2.3.4/udp/4001/quic-v1"}
+	out := mocks.NewMockStream()
+	out.WriteFunc = sendSide.Write
+	out.CloseFunc = sendSide.Close
+	sender.NewStreamFunc = func(_ context.Context, peerID string, protocolIDs ...string) (pkgif.Stream, error) {
+		assert.Equal(t, "peer-b", peerID)
+		assert.Equal(t, []string{ProtocolIDPush}, protocolIDs)
+		return out, nil
+	}
+	senderService := NewService(sender, nil)
+	senderService.AddCapabilityProvider(func(caps *types.PeerCapabilities) {
+		caps.AddRole(types.PeerRoleRelay)
+	})
+
+	receiver := mocks.NewMockHost("peer-b")
+	ps := mocks.NewMockPeerstore()
+	receiver.PeerstoreFunc = func() pkgif.Peerstore { return ps }
+	receiverService := NewService(receiver, nil)
+
+	in := mocks.NewMockStream()
+	in.ReadFunc = recvSide.Read
+	in.CloseFunc = recvSide.Close
+	in.ConnValue = mocks.NewMockConnection("peer-b", "peer-a")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		receiverService.PushHandler(in)
+	}()
+
+	require.NoError(t, senderService.Push(context.Background(), "peer-b"))
+	<-done
+
+	// 能力块到达对端的 Peerstore
+	stored := PeerCapabilities(ps, "peer-a")
+	require.NotNil(t, stored)
+	assert.True(t, stored.HasRole(types.PeerRoleRelay))
+	assert.Equal(t, []string{"quic-v1"}, stored.Transports)
+	assert.Len(t, ps.Addrs("peer-a"), 1)
+}
+
+func TestService_Push_NilHost(t *testing.T) {
 	service := NewService(nil, nil)
 
 	err := service.Push(context.Background(), "peer-123")
-	assert.ErrorIs(t, err, ErrPushNotImplemented)
+	assert.ErrorIs(t, err, ErrNoHost)
 }
 
-func TestService_Push_MultipleCallsReturnSameError(t *testing.T) {
-	service := NewService(nil, nil)
+func TestService_PushHandler_StoresCapabilities(t *testing.T) {
+	host := mocks.NewMockHost("local-peer")
+	ps := mocks.NewMockPeerstore()
+	host.PeerstoreFunc = func() pkgif.Peerstore { return ps }
+	service := NewService(host, nil)
+
+	caps := types.NewPeerCapabilities()
+	caps.AddRole(types.PeerRoleRelay)
+	data, err := json.Marshal(&IdentifyInfo{
+		PeerID:       "remote-peer",
+		ListenAddrs:  []string{"/ip4/1.2.3.4/udp/4001/quic-v1"},
+		Protocols:    []string{"/test/1.0.0"},
+		Capabilities: caps,
+	})
+	require.NoError(t, err)
+
+	stream := mocks.NewMockStreamWithData(data)
+	stream.ConnValue = mocks.NewMockConnection("local-peer", "remote-peer")
+	service.PushHandler(stream)
+
+	stored := PeerCapabilities(ps, "remote-peer")
+	require.NotNil(t, stored)
+	assert.True(t, stored.HasRole(types.PeerRoleRelay))
+	assert.Len(t, ps.Addrs("remote-peer"), 1)
+	protos, _ := ps.GetProtocols("remote-peer")
+	assert.Len(t, protos, 1)
+}
+
+func TestService_PushHandler_RejectsMismatchedPeer(t *testing.T) {
+	host := mocks.NewMockHost("local-peer")
+	ps := mocks.NewMockPeerstore()
+	host.PeerstoreFunc = func() pkgif.Peerstore { return ps }
+	service := NewService(host, nil)
+
+	data, err := json.Marshal(&IdentifyInfo{
+		PeerID:       "other-peer",
+		Capabilities: types.NewPeerCapabilities(),
+	})
+	require.NoError(t, err)
 
-	err1 := service.Push(context.Background(), "peer-1")
-	err2 := service.Push(context.Background(), "peer-2")
-	err3 := service.Push(context.Background(), "peer-3")
+	stream := mocks.NewMockStreamWithData(data)
+	stream.ConnValue = mocks.NewMockConnection("local-peer", "remote-peer")
+	service.PushHandler(stream)
 
-	assert.ErrorIs(t, err1, ErrPushNotImplemented)
-	assert.ErrorIs(t, err2, ErrPushNotImplemented)
-	assert.ErrorIs(t, err3, ErrPushNotImplemented)
+	assert.Nil(t, PeerCapabilities(ps, "remote-peer"))
+	assert.Nil(t, PeerCapabilities(ps, "other-peer"))
+}
+
+func TestStoreProtocols(t *testing.T) {
+	ps := mocks.NewMockPeerstore()
+
+	StoreProtocols(ps, "remote-peer", nil)
+	protos, _ := ps.GetProtocols("remote-peer")
+	assert.Empty(t, protos)
+
+	StoreProtocols(ps, "remote-peer", []string{"/msg/1.0.0", "/msg/1.0.0+zstd"})
+	first, err := ps.FirstSupportedProtocol("remote-peer", "/msg/1.0.0+zstd", "/msg/1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, types.ProtocolID("/msg/1.0.0+zstd"), first)
 }
 
 // ============================================================================
@@ -42,17 +142,48 @@ func TestService_GetProtocols_NilRegistry(t *testing.T) {
 }
 
 // ============================================================================
-//                     Error 测试
+//                     能力块测试
 // ============================================================================
 
-func TestErrPushNotImplemented(t *testing.T) {
-	assert.NotNil(t, ErrPushNotImplemented)
-	assert.Contains(t, ErrPushNotImplemented.Error(), "not implemented")
+func TestService_LocalCapabilities(t *testing.T) {
+	host := mocks.NewMockHost("local-peer")
+	host.AddrsValue = []string{
+		"/ip4/1.2.3.4/udp/4001/quic-v1",
+		"/ip4/1.2.3.4/tcp/4001",
+		"/ip4/5.6.7.8/tcp/4001",
+		"/ip4/9.9.9.9/tcp/4001/p2p/relay/p2p-circuit",
+	}
+	service := NewService(host, nil)
+	service.AddCapabilityProvider(func(caps *types.PeerCapabilities) {
+		caps.AddRole(types.PeerRoleRelay)
+		caps.NATType = types.NATTypeNone
+	})
+	service.AddCapabilityProvider(func(caps *types.PeerCapabilities) {
+		caps.AddRealm("local-peer", "realm-1")
+	})
+
+	caps := service.LocalCapabilities()
+	assert.Equal(t, types.CapabilitiesVersion, caps.Version)
+	assert.Equal(t, []string{"quic-v1", "tcp"}, caps.Transports)
+	assert.True(t, caps.HasRole(types.PeerRoleRelay))
+	assert.Equal(t, types.NATTypeNone, caps.NATType)
+	assert.True(t, caps.InRealm("local-peer", "realm-1"))
 }
 
-func TestErrPushNotImplemented_IsError(t *testing.T) {
-	err := ErrPushNotImplemented
-	assert.True(t, errors.Is(err, ErrPushNotImplemented))
+func TestService_Handler_IncludesCapabilities(t *testing.T) {
+	host := mocks.NewMockHost("local-peer")
+	service := NewService(host, nil)
+	service.AddCapabilityProvider(func(caps *types.PeerCapabilities) {
+		caps.AddRole(types.PeerRoleBootstrap)
+	})
+
+	stream := mocks.NewMockStream()
+	service.Handler(stream)
+
+	var info IdentifyInfo
+	require.NoError(t, json.Unmarshal(stream.WriteData, &info))
+	require.NotNil(t, info.Capabilities)
+	assert.True(t, info.Capabilities.HasRole(types.PeerRoleBootstrap))
 }
 
 // ============================================================================
@@ -75,8 +75,8 @@ func (s *Service) Handler(stream pkgif.Stream) {
 // Ping 主动 Ping 节点（客户端）
 // 返回往返时间（RTT）
 func Ping(ctx context.Context, host pkgif.Host, peer string) (time.Duration, error) {
-	// 1. 创建流
-	stream, err := host.NewStream(ctx, peer, ProtocolID)
+	// 1. 创建流（Ping 可安全重放，允许使用 0-RTT 早期数据）
+	stream, err := host.NewStream(pkgif.WithEarlyData(ctx), peer, ProtocolID)
 	if err != nil {
 		return 0, err
 	}
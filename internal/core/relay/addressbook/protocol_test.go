@@ -216,7 +216,7 @@ func (h *mockHost) Connect(ctx context.Context, peerID string, addrs []string) e
 	return nil
 }
 
-func (h *mockHost) SetStreamHandler(protocolID string, handler pkgif.StreamHandler) {
+func (h *mockHost) SetStreamHandler(protocolID string, handler pkgif.StreamHandler, _ ...pkgif.StreamHandlerOption) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 	h.handlers[protocolID] = handler
@@ -5,13 +5,13 @@ import (
 	"sync"
 	"time"
 	
+	"github.com/dep2p/go-dep2p/internal/core/relay/client"
 	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
-	"github.com/dep2p/go-dep2p/pkg/protocol"
 )
 
 // CandidateMetrics 候选指标收集器
 //
-// 定期测量候选中继的延迟、容量、可靠性。
+// 定期通过 HOP 协议探测候选中继的延迟、负载与可靠性。
 type CandidateMetrics struct {
 	mu sync.RWMutex
 	
@@ -37,6 +37,8 @@ type RelayMetrics struct {
 	Latency     time.Duration // 延迟
 	Capacity    float64       // 容量（0-1）
 	Reliability float64       // 可靠性（0-1）
+	Utilization float64       // 中继自报利用率（0-1）
+	LoadKnown   bool          // 中继是否报告了负载
 	
 	// 统计数据
 	PingCount    int       // Ping 次数
@@ -111,38 +113,65 @@ func (m *CandidateMetrics) collectMetrics() {
 func (m *CandidateMetrics) measurePeer(peerID string) {
 	ctx, cancel := context.WithTimeout(m.ctx, 5*time.Second)
 	defer cancel()
-	
-	// 测量延迟（通过 Ping）
-	latency, err := m.ping(ctx, peerID)
-	
+
+	if m.host == nil {
+		return
+	}
+
+	// 通过 HOP 协议探测延迟与负载
+	result, err := client.Probe(ctx, m.host, peerID)
+	if err != nil {
+		result = nil
+	}
+	m.RecordProbe(peerID, result)
+}
+
+// RecordProbe 记录一次探测结果
+//
+// result 为 nil 表示探测失败。中继报告了负载时，容量为剩余可用比例；
+// 否则按延迟估算容量。
+func (m *CandidateMetrics) RecordProbe(peerID string, result *client.ProbeResult) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	
+
 	metrics, exists := m.metrics[peerID]
 	if !exists {
 		return
 	}
-	
+
 	metrics.PingCount++
 	metrics.LastPing = time.Now()
-	
-	if err != nil {
-		// Ping 失败
+
+	if result == nil {
+		// 探测失败
 		metrics.FailureCount++
 	} else {
-		// Ping 成功
+		// 探测成功
 		metrics.SuccessCount++
 		metrics.LastSuccess = time.Now()
-		metrics.Latency = latency
+		metrics.Latency = result.RTT
+		metrics.LoadKnown = result.LoadKnown
+		metrics.Utilization = result.Utilization()
 	}
-	
+
 	// 计算可靠性（成功率）
 	if metrics.PingCount > 0 {
 		metrics.Reliability = float64(metrics.SuccessCount) / float64(metrics.PingCount)
 	}
-	
+
+	if result == nil {
+		return
+	}
+
+	// 中继自报负载：容量 = 1 - 利用率
+	if result.LoadKnown {
+		metrics.Capacity = 1 - metrics.Utilization
+		return
+	}
+
 	// 计算容量（简化：基于延迟）
 	// 延迟越低，容量越高
+	latency := result.RTT
 	if latency > 0 {
 		// 100ms 以下 = 1.0，1000ms 以上 = 0.1
 		if latency < 100*time.Millisecond {
@@ -156,38 +185,6 @@ func (m *CandidateMetrics) measurePeer(peerID string) {
 	}
 }
 
-// ping 测量到节点的延迟
-func (m *CandidateMetrics) ping(ctx context.Context, peerID string) (time.Duration, error) {
-	if m.host == nil {
-		return 0, nil
-	}
-	
-	// 使用 NewStream 测试连接
-	start := time.Now()
-	
-	stream, err := m.host.NewStream(ctx, peerID, string(protocol.Ping))
-	if err != nil {
-		return 0, err
-	}
-	defer stream.Close()
-	
-	// 发送简单的 ping 消息
-	_, err = stream.Write([]byte("ping"))
-	if err != nil {
-		return 0, err
-	}
-	
-	// 读取响应
-	buf := make([]byte, 4)
-	_, err = stream.Read(buf)
-	if err != nil {
-		return 0, err
-	}
-	
-	latency := time.Since(start)
-	return latency, nil
-}
-
 // AddCandidate 添加候选（开始收集指标）
 func (m *CandidateMetrics) AddCandidate(peerID string) {
 	m.mu.Lock()
@@ -225,6 +222,8 @@ func (m *CandidateMetrics) GetMetrics(peerID string) *RelayMetrics {
 			Latency:      metrics.Latency,
 			Capacity:     metrics.Capacity,
 			Reliability:  metrics.Reliability,
+			Utilization:  metrics.Utilization,
+			LoadKnown:    metrics.LoadKnown,
 			PingCount:    metrics.PingCount,
 			SuccessCount: metrics.SuccessCount,
 			FailureCount: metrics.FailureCount,
@@ -248,6 +247,8 @@ func (m *CandidateMetrics) GetAllMetrics() map[string]*RelayMetrics {
 			Latency:      metrics.Latency,
 			Capacity:     metrics.Capacity,
 			Reliability:  metrics.Reliability,
+			Utilization:  metrics.Utilization,
+			LoadKnown:    metrics.LoadKnown,
 			PingCount:    metrics.PingCount,
 			SuccessCount: metrics.SuccessCount,
 			FailureCount: metrics.FailureCount,
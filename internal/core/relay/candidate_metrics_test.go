@@ -6,6 +6,9 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/dep2p/go-dep2p/internal/core/nat"
+	"github.com/dep2p/go-dep2p/internal/core/relay/client"
 )
 
 // ============================================================================
@@ -310,3 +313,71 @@ func TestCandidateMetrics_Concurrent(t *testing.T) {
 		<-done
 	}
 }
+
+// ============================================================================
+//                     RecordProbe 测试
+// ============================================================================
+
+func TestCandidateMetrics_RecordProbe_Load(t *testing.T) {
+	m := NewCandidateMetrics(nil, nil)
+	m.AddCandidate("peer-1")
+
+	m.RecordProbe("peer-1", &client.ProbeResult{
+		RTT:         40 * time.Millisecond,
+		LoadKnown:   true,
+		Circuits:    3,
+		MaxCircuits: 4,
+	})
+
+	metrics := m.GetMetrics("peer-1")
+	require.NotNil(t, metrics)
+	assert.Equal(t, 40*time.Millisecond, metrics.Latency)
+	assert.True(t, metrics.LoadKnown)
+	assert.InDelta(t, 0.75, metrics.Utilization, 1e-9)
+	assert.InDelta(t, 0.25, metrics.Capacity, 1e-9, "容量为剩余可用比例")
+	assert.Equal(t, 1.0, metrics.Reliability)
+}
+
+func TestCandidateMetrics_RecordProbe_LegacyAndFailure(t *testing.T) {
+	m := NewCandidateMetrics(nil, nil)
+	m.AddCandidate("peer-1")
+
+	// 旧版中继不报告负载，按延迟估算容量
+	m.RecordProbe("peer-1", &client.ProbeResult{RTT: 50 * time.Millisecond})
+	metrics := m.GetMetrics("peer-1")
+	assert.False(t, metrics.LoadKnown)
+	assert.Equal(t, 1.0, metrics.Capacity)
+
+	// 探测失败只影响可靠性
+	m.RecordProbe("peer-1", nil)
+	metrics = m.GetMetrics("peer-1")
+	assert.Equal(t, 1, metrics.FailureCount)
+	assert.InDelta(t, 0.5, metrics.Reliability, 1e-9)
+	assert.Equal(t, 1.0, metrics.Capacity)
+
+	// 未知候选忽略
+	m.RecordProbe("unknown", &client.ProbeResult{})
+	assert.Nil(t, m.GetMetrics("unknown"))
+}
+
+func TestCandidatePool_SelectBest_AvoidsSaturatedRelay(t *testing.T) {
+	pool := NewRelayCandidatePool("realm")
+	m := NewCandidateMetrics(nil, nil)
+	pool.SetMetrics(m)
+
+	for _, id := range []string{"saturated", "loaded"} {
+		pool.Add(&RelayCandidate{PeerID: id, Reachability: nat.ReachabilityPublic})
+		m.AddCandidate(id)
+	}
+
+	m.RecordProbe("saturated", &client.ProbeResult{
+		RTT: 10 * time.Millisecond, LoadKnown: true, Reservations: 8, MaxReservations: 8,
+	})
+	m.RecordProbe("loaded", &client.ProbeResult{
+		RTT: 150 * time.Millisecond, LoadKnown: true, Reservations: 6, MaxReservations: 8,
+	})
+
+	best := pool.SelectBest()
+	require.NotNil(t, best)
+	assert.Equal(t, "loaded", best.PeerID)
+}
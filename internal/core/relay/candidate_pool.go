@@ -112,6 +112,7 @@ func (p *RelayCandidatePool) SelectBest() *RelayCandidate {
 					Latency:     int64(metrics.Latency.Milliseconds()),
 					Capacity:    metrics.Capacity,
 					Reliability: metrics.Reliability,
+					Saturated:   metrics.LoadKnown && metrics.Utilization >= 1,
 				}
 			} else {
 				// 无指标数据，使用默认值
@@ -50,6 +50,12 @@ const (
 
 	// blacklistLong 长期黑名单时间
 	blacklistLong = 1 * time.Hour
+
+	// candidateProbeInterval 候选探测结果的有效期
+	candidateProbeInterval = 5 * time.Minute
+
+	// maxConcurrentProbes 最大并发探测数
+	maxConcurrentProbes = 4
 )
 
 // ============================================================================
@@ -124,6 +130,14 @@ type AutoRelay struct {
 	onAddrsChanged   func([]string)
 	onAddrsChangedMu sync.RWMutex
 
+	// 数据预算（可选，计费网络下放慢发现与候选探测）
+	dataBudget   pkgif.DataBudget
+	dataBudgetMu sync.RWMutex
+
+	// 节点信誉（可选，跳过被封禁的候选，信誉为负的排在后面）
+	reputation   pkgif.Reputation
+	reputationMu sync.RWMutex
+
 	// 日志指数退避
 	lastNoRelayLog     time.Time     // 上次打印"需要更多中继"的时间
 	noRelayLogInterval time.Duration // 当前日志间隔（指数退避）
@@ -153,6 +167,10 @@ type relayCandidate struct {
 	latency  time.Duration
 	lastSeen time.Time
 	priority int
+
+	// 最近一次 HOP 探测结果（nil 表示未探测或探测失败）
+	probe       *ProbeResult
+	lastProbeAt time.Time
 }
 
 // NewAutoRelay 创建 AutoRelay
@@ -347,7 +365,7 @@ func (ar *AutoRelay) discoveryLoop() {
 		case <-ar.ctx.Done():
 			return
 		case <-ticker.C:
-			if ar.IsEnabled() {
+			if ar.IsEnabled() && ar.allowBackground("discovery", ar.config.DiscoveryInterval) {
 				ar.discoverRelays()
 			}
 		}
@@ -391,6 +409,12 @@ func (ar *AutoRelay) ensureRelays() {
 		return
 	}
 
+	// 监听建立前不拨号：此时的出站连接只能使用临时端口，中继观测到的
+	// NAT 映射与监听端口不一致，后续打洞无法使用，由维护循环稍后重试
+	if ar.host != nil && len(ar.host.Addrs()) == 0 {
+		return
+	}
+
 	needed := ar.config.MinRelays - activeCount
 
 	// 使用指数退避来减少日志频率
@@ -417,6 +441,9 @@ func (ar *AutoRelay) ensureRelays() {
 	}
 	ar.lastActiveCount = activeCount
 
+	// 探测候选的延迟与负载，用于排序
+	ar.probeCandidates()
+
 	// 获取候选列表
 	candidates := ar.getCandidates(needed * 2)
 
@@ -645,24 +672,62 @@ func (ar *AutoRelay) getCandidates(count int) []*relayCandidate {
 	ar.candidatesMu.RLock()
 	defer ar.candidatesMu.RUnlock()
 
+	rep := ar.getReputation()
 	candidates := make([]*relayCandidate, 0, len(ar.candidates))
+	caps := make(map[string]*types.PeerCapabilities, len(ar.candidates))
+	scores := make(map[string]float64, len(ar.candidates))
 	for _, c := range ar.candidates {
+		if rep != nil {
+			scores[c.relayID] = rep.Score(c.relayID)
+		}
 		// 如果是首选中继，提升优先级
 		if ar.isPreferredRelay(c.relayID) {
 			preferredCand := *c
 			preferredCand.priority = preferredRelayPriority
 			candidates = append(candidates, &preferredCand)
-		} else {
-			candidates = append(candidates, c)
+			caps[c.relayID] = ar.peerCapabilities(c.relayID)
+			continue
+		}
+
+		// 被信誉封禁的中继跳过（静态中继除外）
+		if c.priority < preferredRelayPriority && rep != nil && rep.IsBanned(c.relayID) {
+			continue
 		}
+
+		// Identify 能力块表明对端未提供中继服务时跳过，省去一次预约尝试（静态中继除外）
+		peerCaps := ar.peerCapabilities(c.relayID)
+		if c.priority < preferredRelayPriority && peerCaps != nil && !peerCaps.HasRole(types.PeerRoleRelay) {
+			continue
+		}
+		caps[c.relayID] = peerCaps
+		candidates = append(candidates, c)
 	}
 
-	// 按优先级和延迟排序
+	// 按优先级、负载和延迟排序：
+	// 满负载的中继排在最后（探测结果或 Identify 负载提示），
+	// 信誉为负的排在其余之后，通告了中继角色的排在未知的之前，已探测的排在未探测的之前，
+	// 同等条件下按负载加权延迟升序
 	sort.Slice(candidates, func(i, j int) bool {
-		if candidates[i].priority != candidates[j].priority {
-			return candidates[i].priority > candidates[j].priority
+		a, b := candidates[i], candidates[j]
+		if a.priority != b.priority {
+			return a.priority > b.priority
+		}
+		aCaps, bCaps := caps[a.relayID], caps[b.relayID]
+		as := a.saturated() || (a.probe == nil && aCaps != nil && aCaps.Load.Saturated())
+		bs := b.saturated() || (b.probe == nil && bCaps != nil && bCaps.Load.Saturated())
+		if as != bs {
+			return !as
+		}
+		if aBad, bBad := scores[a.relayID] < 0, scores[b.relayID] < 0; aBad != bBad {
+			return !aBad
 		}
-		return candidates[i].latency < candidates[j].latency
+		if aRelay, bRelay := aCaps.HasRole(types.PeerRoleRelay), bCaps.HasRole(types.PeerRoleRelay); aRelay != bRelay {
+			return aRelay
+		}
+		if (a.probe != nil) != (b.probe != nil) {
+			return a.probe != nil
+		}
+		return a.effectiveLatency() < b.effectiveLatency()
 	})
 
 	if len(candidates) > count {
@@ -672,6 +737,200 @@ func (ar *AutoRelay) getCandidates(count int) []*relayCandidate {
 	return candidates
 }
 
+// peerCapabilities 读取对端通过 Identify 通告的能力（未知时返回 nil）
+func (ar *AutoRelay) peerCapabilities(relayID string) *types.PeerCapabilities {
+	if ar.peerstore == nil {
+		return nil
+	}
+	v, err := ar.peerstore.Get(types.PeerID(relayID), types.PeerCapabilitiesKey)
+	if err != nil {
+		return nil
+	}
+	caps, _ := types.ParsePeerCapabilities(v)
+	return caps
+}
+
+// ============================================================================
+//                              中继探测
+// ============================================================================
+
+// saturated 候选是否已满负载
+func (c *relayCandidate) saturated() bool {
+	return c.probe != nil && c.probe.Saturated()
+}
+
+// effectiveLatency 负载加权延迟（未探测时为原始延迟）
+func (c *relayCandidate) effectiveLatency() time.Duration {
+	if c.probe != nil {
+		return c.probe.EffectiveLatency()
+	}
+	return c.latency
+}
+
+// probeCandidates 探测需要刷新的候选
+//
+// 跳过活跃中继、黑名单中的中继以及最近探测过的中继，并发数受限。
+func (ar *AutoRelay) probeCandidates() {
+	if ar.host == nil || ar.ctx == nil {
+		return
+	}
+	if !ar.allowBackground("probe", candidateProbeInterval) {
+		return
+	}
+
+	now := time.Now()
+	ar.candidatesMu.RLock()
+	var stale []string
+	for id, c := range ar.candidates {
+		if now.Sub(c.lastProbeAt) >= candidateProbeInterval {
+			stale = append(stale, id)
+		}
+	}
+	ar.candidatesMu.RUnlock()
+
+	sem := make(chan struct{}, maxConcurrentProbes)
+	var wg sync.WaitGroup
+	for _, id := range stale {
+		ar.activeRelaysMu.RLock()
+		_, active := ar.activeRelays[id]
+		ar.activeRelaysMu.RUnlock()
+		if active || ar.isBlacklisted(id) {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(relayID string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			ctx, cancel := context.WithTimeout(ar.ctx, DefaultProbeTimeout)
+			defer cancel()
+			result, err := Probe(ctx, ar.host, relayID)
+			if err != nil {
+				autorelayLogger.Debug("中继探测失败", "relay", relayID, "err", err)
+			}
+			ar.RecordProbe(relayID, result)
+		}(id)
+	}
+	wg.Wait()
+}
+
+// ProbeRelay 探测指定地址的中继并记录结果
+//
+// relayAddr 为带 /p2p/<id> 的完整 multiaddr。未知中继会加入候选列表，
+// 结果随后参与候选排序。用于网络诊断复用 AutoRelay 的探测结果。
+func (ar *AutoRelay) ProbeRelay(ctx context.Context, relayAddr string) (*ProbeResult, error) {
+	if ar.host == nil {
+		return nil, ErrNotConnected
+	}
+
+	ai, err := types.AddrInfoFromString(relayAddr)
+	if err != nil {
+		return nil, err
+	}
+	relayID := string(ai.ID)
+	addrs := make([]string, 0, len(ai.Addrs))
+	for _, addr := range ai.Addrs {
+		addrs = append(addrs, addr.String())
+	}
+
+	if len(addrs) > 0 {
+		if err := ar.host.Connect(ctx, relayID, addrs); err != nil {
+			ar.RecordProbe(relayID, nil)
+			return nil, err
+		}
+	}
+
+	ar.candidatesMu.Lock()
+	if _, exists := ar.candidates[relayID]; !exists {
+		ar.candidates[relayID] = &relayCandidate{
+			relayID:  relayID,
+			addrs:    addrs,
+			lastSeen: time.Now(),
+		}
+	}
+	ar.candidatesMu.Unlock()
+
+	result, err := Probe(ctx, ar.host, relayID)
+	ar.RecordProbe(relayID, result)
+	return result, err
+}
+
+// RecordProbe 记录候选的探测结果
+//
+// result 为 nil 表示探测失败，清除旧结果使其排在已探测候选之后。
+func (ar *AutoRelay) RecordProbe(relayID string, result *ProbeResult) {
+	ar.candidatesMu.Lock()
+	defer ar.candidatesMu.Unlock()
+
+	cand, ok := ar.candidates[relayID]
+	if !ok {
+		return
+	}
+	cand.lastProbeAt = time.Now()
+	cand.probe = result
+	if result != nil {
+		cand.latency = result.RTT
+		cand.lastSeen = result.ProbedAt
+	}
+}
+
+// ProbeResults 返回所有候选最近一次成功的探测结果
+func (ar *AutoRelay) ProbeResults() map[string]ProbeResult {
+	ar.candidatesMu.RLock()
+	defer ar.candidatesMu.RUnlock()
+
+	results := make(map[string]ProbeResult)
+	for id, c := range ar.candidates {
+		if c.probe != nil {
+			results[id] = *c.probe
+		}
+	}
+	return results
+}
+
+// ============================================================================
+//                              数据预算
+// ============================================================================
+
+// SetDataBudget 设置数据预算
+//
+// 计费网络下中继发现与候选探测按预算节流，已有预留的续期不受影响。
+func (ar *AutoRelay) SetDataBudget(budget pkgif.DataBudget) {
+	ar.dataBudgetMu.Lock()
+	defer ar.dataBudgetMu.Unlock()
+	ar.dataBudget = budget
+}
+
+// allowBackground 检查数据预算是否允许执行后台任务
+func (ar *AutoRelay) allowBackground(task string, interval time.Duration) bool {
+	ar.dataBudgetMu.RLock()
+	budget := ar.dataBudget
+	ar.dataBudgetMu.RUnlock()
+	return budget == nil || budget.Allow(pkgif.BudgetSubsystemRelay, task, interval)
+}
+
+// ============================================================================
+//                              节点信誉
+// ============================================================================
+
+// SetReputation 设置信誉服务
+//
+// 选择候选时跳过被封禁的中继，信誉为负的中继排在其余候选之后。
+func (ar *AutoRelay) SetReputation(rep pkgif.Reputation) {
+	ar.reputationMu.Lock()
+	defer ar.reputationMu.Unlock()
+	ar.reputation = rep
+}
+
+// getReputation 获取信誉服务
+func (ar *AutoRelay) getReputation() pkgif.Reputation {
+	ar.reputationMu.RLock()
+	defer ar.reputationMu.RUnlock()
+	return ar.reputation
+}
+
 // ============================================================================
 //                              首选中继
 // ============================================================================
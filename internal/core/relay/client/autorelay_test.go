@@ -7,8 +7,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/dep2p/go-dep2p/internal/core/reputation"
 	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
 	"github.com/dep2p/go-dep2p/pkg/types"
+	"github.com/dep2p/go-dep2p/tests/mocks"
 )
 
 // ============================================================================
@@ -499,6 +501,42 @@ func TestAutoRelay_GetCandidates_WithPreferred(t *testing.T) {
 	t.Log("✅ 首选中继优先排序正常")
 }
 
+// TestAutoRelay_GetCandidates_Reputation 测试按信誉筛选和排序候选
+func TestAutoRelay_GetCandidates_Reputation(t *testing.T) {
+	config := DefaultAutoRelayConfig()
+	client := &mockRelayClient{}
+	ar := NewAutoRelay(config, client, nil, nil)
+
+	rep, err := reputation.New(pkgif.DefaultReputationConfig(), nil)
+	if err != nil {
+		t.Fatalf("reputation.New failed: %v", err)
+	}
+	ar.SetReputation(rep)
+
+	ar.AddCandidate("relay1", []string{}, 10)
+	ar.AddCandidate("relay2", []string{}, 10)
+	ar.AddCandidate("relay3", []string{}, 10)
+
+	// relay1 被封禁，relay2 信誉为负
+	for _, signal := range []pkgif.ReputationSignal{
+		pkgif.ReputationSignalPubSub,
+		pkgif.ReputationSignalWitness,
+		pkgif.ReputationSignalDial,
+	} {
+		rep.Observe("relay1", signal, -1, "bad")
+	}
+	rep.Observe("relay2", pkgif.ReputationSignalDial, -0.5, "dial_failed")
+
+	candidates := ar.getCandidates(10)
+
+	if len(candidates) != 2 {
+		t.Fatalf("candidates count = %d, want 2", len(candidates))
+	}
+	if candidates[0].relayID != "relay3" || candidates[1].relayID != "relay2" {
+		t.Errorf("candidates = [%s %s], want [relay3 relay2]", candidates[0].relayID, candidates[1].relayID)
+	}
+}
+
 // TestBuildCircuitAddr 测试构建 circuit 地址
 func TestBuildCircuitAddr(t *testing.T) {
 	addr := buildCircuitAddr("/ip4/1.2.3.4/tcp/1234/p2p/relay-id", "local-id")
@@ -657,14 +695,50 @@ func TestAutoRelay_GetCandidates_ZeroOrNegativeCount(t *testing.T) {
 	t.Logf("请求 -1 个候选返回 %d 个", len(candidates))
 }
 
+// TestAutoRelay_GetCandidates_Capabilities 测试按 Identify 能力块排序候选
+func TestAutoRelay_GetCandidates_Capabilities(t *testing.T) {
+	config := DefaultAutoRelayConfig()
+	peerstore := newMockPeerstore()
+	ar := NewAutoRelay(config, &mockRelayClient{}, nil, peerstore)
+
+	ar.AddCandidate("unknown", []string{}, 0)
+	ar.AddCandidate("advertised", []string{}, 0)
+	ar.AddCandidate("busy", []string{}, 0)
+	ar.AddCandidate("not-relay", []string{}, 0)
+
+	relayCaps := types.NewPeerCapabilities()
+	relayCaps.AddRole(types.PeerRoleRelay)
+	peerstore.Put("advertised", types.PeerCapabilitiesKey, relayCaps)
+
+	busyCaps := types.NewPeerCapabilities()
+	busyCaps.AddRole(types.PeerRoleRelay)
+	busyCaps.Load = &types.LoadHint{RelayCircuits: 128, MaxRelayCircuits: 128}
+	peerstore.Put("busy", types.PeerCapabilitiesKey, busyCaps)
+
+	peerstore.Put("not-relay", types.PeerCapabilitiesKey, types.NewPeerCapabilities())
+
+	candidates := ar.getCandidates(10)
+	ids := make([]string, len(candidates))
+	for i, c := range candidates {
+		ids[i] = c.relayID
+	}
+
+	// 通告中继角色的优先，满负载的最后，明确不提供中继的被跳过
+	if len(ids) != 3 || ids[0] != "advertised" || ids[1] != "unknown" || ids[2] != "busy" {
+		t.Errorf("candidates = %v, want [advertised unknown busy]", ids)
+	}
+}
+
 // mockPeerstore 模拟 Peerstore（用于 HOP 协议检查测试）
 type mockPeerstore struct {
 	protocols map[types.PeerID][]types.ProtocolID
+	metadata  map[types.PeerID]map[string]interface{}
 }
 
 func newMockPeerstore() *mockPeerstore {
 	return &mockPeerstore{
 		protocols: make(map[types.PeerID][]types.ProtocolID),
+		metadata:  make(map[types.PeerID]map[string]interface{}),
 	}
 }
 
@@ -718,8 +792,14 @@ func (m *mockPeerstore) RemoveProtocols(peerID types.PeerID, protos ...types.Pro
 func (m *mockPeerstore) FirstSupportedProtocol(peerID types.PeerID, protos ...types.ProtocolID) (types.ProtocolID, error) {
 	return "", nil
 }
-func (m *mockPeerstore) Get(peerID types.PeerID, key string) (interface{}, error) { return nil, nil }
+func (m *mockPeerstore) Get(peerID types.PeerID, key string) (interface{}, error) {
+	return m.metadata[peerID][key], nil
+}
 func (m *mockPeerstore) Put(peerID types.PeerID, key string, val interface{}) error {
+	if m.metadata[peerID] == nil {
+		m.metadata[peerID] = make(map[string]interface{})
+	}
+	m.metadata[peerID][key] = val
 	return nil
 }
 func (m *mockPeerstore) Peers() []types.PeerID                { return nil }
@@ -876,6 +956,30 @@ func TestAutoRelay_EnsureRelays_AlreadyHaveEnough(t *testing.T) {
 	}
 }
 
+// TestAutoRelay_EnsureRelays_WaitsForListen 测试监听建立前不发起预留
+func TestAutoRelay_EnsureRelays_WaitsForListen(t *testing.T) {
+	config := DefaultAutoRelayConfig()
+	config.StaticRelays = []string{"relay1"}
+	client := &mockRelayClient{}
+	host := mocks.NewMockHost("local")
+	host.AddrsValue = nil
+	ar := NewAutoRelay(config, client, host, nil)
+
+	ar.Start(context.Background())
+	defer ar.Stop()
+
+	ar.ensureRelays()
+	if atomic.LoadInt32(&client.reserveCalls) != 0 {
+		t.Error("监听建立前不应该发起预留")
+	}
+
+	host.AddrsValue = []string{"/ip4/10.0.0.1/tcp/4001"}
+	ar.ensureRelays()
+	if atomic.LoadInt32(&client.reserveCalls) == 0 {
+		t.Error("监听建立后应该发起预留")
+	}
+}
+
 // TestAutoRelay_RefreshReservations_ExpiryZero 测试 Expiry 返回 0 的情况
 // 这是 BUG #B12 的回归测试
 func TestAutoRelay_RefreshReservations_ExpiryZero(t *testing.T) {
@@ -15,6 +15,7 @@ import (
 	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
 	"github.com/dep2p/go-dep2p/pkg/lib/log"
 	"github.com/dep2p/go-dep2p/pkg/lib/multiaddr"
+	"github.com/dep2p/go-dep2p/pkg/lib/tracing"
 	"github.com/dep2p/go-dep2p/pkg/protocol"
 	"github.com/dep2p/go-dep2p/pkg/types"
 	mss "github.com/multiformats/go-multistream"
@@ -41,6 +42,7 @@ const (
 	MsgTypeReserve = 0
 	MsgTypeConnect = 1
 	MsgTypeStatus  = 2
+	MsgTypeProbe   = 3 // 轻量探测：测量 RTT 并获取中继负载
 )
 
 // 状态码
@@ -150,7 +152,10 @@ func (c *Client) Reserve(ctx context.Context) (*Reservation, error) {
 }
 
 // Connect 通过中继连接目标节点
-func (c *Client) Connect(ctx context.Context, target types.PeerID) (pkgif.Connection, error) {
+func (c *Client) Connect(ctx context.Context, target types.PeerID) (_ pkgif.Connection, err error) {
+	ctx, span := c.startSpan(ctx, target)
+	defer func() { span.End(err) }()
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -186,8 +191,8 @@ func (c *Client) Connect(ctx context.Context, target types.PeerID) (pkgif.Connec
 	}
 	stream.SetProtocol(selectedProto)
 
-	// 2. 发送 CONNECT 消息 + 目标节点 ID
-	if err := c.writeMessage(stream, MsgTypeConnect, []byte(target)); err != nil {
+	// 2. 发送 CONNECT 消息 + 目标节点 ID（附带追踪上下文）
+	if err := c.writeMessage(stream, MsgTypeConnect, connectPayload(ctx, target)); err != nil {
 		stream.Close()
 		return nil, err
 	}
@@ -253,7 +258,10 @@ func (c *Client) Connect(ctx context.Context, target types.PeerID) (pkgif.Connec
 // 返回：
 //   - pkgif.Connection: 中继电路连接
 //   - error: 错误信息
-func (c *Client) ConnectAsInitiator(ctx context.Context, target types.PeerID) (pkgif.Connection, error) {
+func (c *Client) ConnectAsInitiator(ctx context.Context, target types.PeerID) (_ pkgif.Connection, err error) {
+	ctx, span := c.startSpan(ctx, target)
+	defer func() { span.End(err) }()
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -292,7 +300,7 @@ func (c *Client) ConnectAsInitiator(ctx context.Context, target types.PeerID) (p
 		"target", safePeerIDPrefix(target),
 		"relay", safePeerIDPrefix(c.relayPeer))
 
-	if err := c.writeMessage(stream, MsgTypeConnect, []byte(target)); err != nil {
+	if err := c.writeMessage(stream, MsgTypeConnect, connectPayload(ctx, target)); err != nil {
 		stream.Close()
 		return nil, fmt.Errorf("send CONNECT message: %w", err)
 	}
@@ -344,6 +352,20 @@ func (c *Client) ConnectAsInitiator(ctx context.Context, target types.PeerID) (p
 	return circuit, nil
 }
 
+// connectPayload 构造 CONNECT 消息体：目标节点 ID，有追踪上下文时追加 traceparent
+//
+// 中继据此把 dep2p.relay.hop Span 挂在本端 dep2p.relay.connect Span 之下。
+func connectPayload(ctx context.Context, target types.PeerID) []byte {
+	return tracing.AppendTraceContext([]byte(target), tracing.SpanContextFromContext(ctx))
+}
+
+// startSpan 开始经中继建立电路的追踪 Span
+func (c *Client) startSpan(ctx context.Context, target types.PeerID) (context.Context, *tracing.Span) {
+	return tracing.Start(ctx, "dep2p.relay.connect", tracing.KindClient,
+		tracing.Attr("peer.id", string(target)),
+		tracing.Attr("relay.id", string(c.relayPeer)))
+}
+
 // createRelayCircuit 创建中继电路
 //
 // 在 STOP 流上叠加 yamux muxer，实现多路复用。
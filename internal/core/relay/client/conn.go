@@ -344,6 +344,14 @@ func (c *RelayCircuit) RemoteMultiaddr() types.Multiaddr {
 //
 // 只有 Closed 和 Draining 状态才拒绝操作。
 func (c *RelayCircuit) NewStream(ctx context.Context) (pkgif.Stream, error) {
+	return c.NewStreamWithPriority(ctx, int(pkgif.StreamPriorityNormal))
+}
+
+// NewStreamWithPriority 在此电路上创建新流（指定优先级）(v1.2 新增)
+//
+// 优先级交给电路上 yamux muxer 的写调度器：本端写入电路拥塞时按优先级发送。
+// 中继节点按顺序转发，不再重新调度。
+func (c *RelayCircuit) NewStreamWithPriority(ctx context.Context, priority int) (pkgif.Stream, error) {
 	//
 	state := c.State()
 	if state == CircuitStateClosed {
@@ -363,7 +371,7 @@ func (c *RelayCircuit) NewStream(ctx context.Context) (pkgif.Stream, error) {
 	}
 
 	// 通过 muxer 创建新流
-	muxedStream, err := c.muxer.OpenStream(ctx)
+	muxedStream, err := c.openMuxedStream(ctx, pkgif.StreamPriority(priority))
 	if err != nil {
 		clientLogger.Warn("创建流失败", "error", err)
 		return nil, err
@@ -391,19 +399,28 @@ func (c *RelayCircuit) NewStream(ctx context.Context) (pkgif.Stream, error) {
 	return wrapped, nil
 }
 
-// NewStreamWithPriority 在此电路上创建新流（指定优先级）(v1.2 新增)
-//
-// 中继连接不支持流优先级，此方法直接调用 NewStream，忽略优先级参数。
-// 中继连接通过中继节点转发数据，优先级控制无法传递到最终目的地。
-func (c *RelayCircuit) NewStreamWithPriority(ctx context.Context, _ int) (pkgif.Stream, error) {
-	return c.NewStream(ctx)
+// openMuxedStream 通过 muxer 打开流，muxer 不支持优先级时忽略优先级
+func (c *RelayCircuit) openMuxedStream(ctx context.Context, priority pkgif.StreamPriority) (pkgif.MuxedStream, error) {
+	if pc, ok := c.muxer.(pkgif.PriorityMuxedConn); ok {
+		return pc.OpenStreamWithPriority(ctx, priority)
+	}
+	return c.muxer.OpenStream(ctx)
 }
 
 // SupportsStreamPriority 检查连接是否支持流优先级 (v1.2 新增)
 //
-// 中继连接不支持流优先级。
+// 电路上的 muxer 启用了写调度时返回 true。
 func (c *RelayCircuit) SupportsStreamPriority() bool {
-	return false
+	pc, ok := c.muxer.(pkgif.PriorityMuxedConn)
+	return ok && pc.SupportsStreamPriority()
+}
+
+// StreamPriorityStats 返回电路各优先级写队列的统计
+func (c *RelayCircuit) StreamPriorityStats() []pkgif.StreamPriorityStats {
+	if pc, ok := c.muxer.(pkgif.PriorityMuxedConn); ok {
+		return pc.StreamPriorityStats()
+	}
+	return nil
 }
 
 // AcceptStream 接受对方创建的流
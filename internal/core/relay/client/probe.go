@@ -0,0 +1,166 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+)
+
+// PROBE 消息
+//
+// 请求负载: [nonce(8)]
+// 响应负载: [nonce(8)][reservations(4)][maxReservations(4)][circuits(4)][maxCircuits(4)]
+const (
+	probeNonceSize   = 8
+	probePayloadSize = 24
+)
+
+// DefaultProbeTimeout 默认探测超时
+const DefaultProbeTimeout = 5 * time.Second
+
+var (
+	// ErrInvalidProbe 探测响应格式错误
+	ErrInvalidProbe = errors.New("relay: invalid probe response")
+)
+
+// ProbeResult 中继探测结果
+type ProbeResult struct {
+	// RTT 探测往返时间（不含建连耗时）
+	RTT time.Duration
+
+	// LoadKnown 中继是否报告了负载（旧版本中继不支持 PROBE，仅有 RTT）
+	LoadKnown bool
+
+	// 中继自报的负载，上限为 0 表示不限制
+	Reservations    int
+	MaxReservations int
+	Circuits        int
+	MaxCircuits     int
+
+	// ProbedAt 探测时间
+	ProbedAt time.Time
+}
+
+// ReservationUtilization 预约利用率（0-1，未知或不限制时为 0）
+func (r *ProbeResult) ReservationUtilization() float64 {
+	return utilization(r.Reservations, r.MaxReservations)
+}
+
+// CircuitUtilization 电路利用率（0-1，未知或不限制时为 0）
+func (r *ProbeResult) CircuitUtilization() float64 {
+	return utilization(r.Circuits, r.MaxCircuits)
+}
+
+// Utilization 综合利用率，取预约与电路利用率的较大值
+func (r *ProbeResult) Utilization() float64 {
+	res, circ := r.ReservationUtilization(), r.CircuitUtilization()
+	if res > circ {
+		return res
+	}
+	return circ
+}
+
+// Saturated 中继是否已满（无法再接受预约或电路）
+func (r *ProbeResult) Saturated() bool {
+	return r.Utilization() >= 1
+}
+
+// EffectiveLatency 按负载加权的延迟，用于中继排序
+//
+// 满负载的中继延迟按两倍计算。
+func (r *ProbeResult) EffectiveLatency() time.Duration {
+	return time.Duration(float64(r.RTT) * (1 + r.Utilization()))
+}
+
+func utilization(used, max int) float64 {
+	if max <= 0 {
+		return 0
+	}
+	u := float64(used) / float64(max)
+	if u > 1 {
+		return 1
+	}
+	return u
+}
+
+// Probe 使用 HOP 协议探测中继
+//
+// 发送 PROBE 消息并测量往返时间，同时获取中继的预约与电路负载。
+// 探测不创建预约也不占用电路。不支持 PROBE 的旧版本中继返回
+// StatusUnexpectedMessage，此时结果只包含 RTT。
+func Probe(ctx context.Context, host pkgif.Host, relayPeer string) (*ProbeResult, error) {
+	if host == nil {
+		return nil, ErrNotConnected
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultProbeTimeout)
+		defer cancel()
+	}
+
+	stream, err := host.NewStream(ctx, relayPeer, HopProtocolID)
+	if err != nil {
+		return nil, fmt.Errorf("open hop stream: %w", err)
+	}
+	defer stream.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		stream.SetDeadline(deadline)
+	}
+
+	nonce := make([]byte, probeNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	c := &Client{} // 仅复用消息编解码
+	start := time.Now()
+	if err := c.writeMessage(stream, MsgTypeProbe, nonce); err != nil {
+		return nil, err
+	}
+	msgType, data, err := c.readMessage(stream)
+	if err != nil {
+		return nil, err
+	}
+	result := &ProbeResult{RTT: time.Since(start), ProbedAt: time.Now()}
+
+	switch msgType {
+	case MsgTypeProbe:
+		if err := decodeProbe(data, nonce, result); err != nil {
+			return nil, err
+		}
+		result.LoadKnown = true
+		return result, nil
+	case MsgTypeStatus:
+		// 旧版本中继：仍然完成了一次 HOP 往返，RTT 有效
+		if status := decodeStatus(data); status == StatusUnexpectedMessage {
+			return result, nil
+		} else if status != StatusOK {
+			return nil, statusToError(status)
+		}
+		return nil, ErrUnexpectedMessage
+	default:
+		return nil, ErrUnexpectedMessage
+	}
+}
+
+// decodeProbe 解码 PROBE 响应
+func decodeProbe(data, nonce []byte, result *ProbeResult) error {
+	if len(data) < probePayloadSize {
+		return ErrInvalidProbe
+	}
+	for i := 0; i < probeNonceSize; i++ {
+		if data[i] != nonce[i] {
+			return ErrInvalidProbe
+		}
+	}
+	result.Reservations = int(binary.BigEndian.Uint32(data[8:]))
+	result.MaxReservations = int(binary.BigEndian.Uint32(data[12:]))
+	result.Circuits = int(binary.BigEndian.Uint32(data[16:]))
+	result.MaxCircuits = int(binary.BigEndian.Uint32(data[20:]))
+	return nil
+}
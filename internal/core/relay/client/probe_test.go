@@ -0,0 +1,146 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dep2p/go-dep2p/internal/core/relay/server"
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/types"
+	"github.com/dep2p/go-dep2p/tests/mocks"
+)
+
+// probeLimiter 报告容量上限的测试限制器
+type probeLimiter struct{}
+
+func (probeLimiter) CanReserve(types.PeerID) bool               { return true }
+func (probeLimiter) CanConnect(types.PeerID, types.PeerID) bool { return true }
+func (probeLimiter) ReserveFor() time.Duration                  { return time.Hour }
+func (probeLimiter) MaxCircuitsPerPeer() int                    { return 4 }
+func (probeLimiter) MaxReservations() int                       { return 4 }
+func (probeLimiter) MaxCircuitsTotal() int                      { return 10 }
+
+// TestProbe_RelayServer 测试与中继服务端完成探测
+func TestProbe_RelayServer(t *testing.T) {
+	pn := mocks.NewPipeNet()
+	local := pn.AddHost("local")
+	relayHost := pn.AddHost("relay")
+
+	srv := server.NewServer(relayHost.Network(), probeLimiter{})
+	relayHost.SetStreamHandler(HopProtocolID, srv.HandleHop)
+
+	result, err := Probe(context.Background(), local, "relay")
+	require.NoError(t, err)
+	assert.True(t, result.LoadKnown)
+	assert.Equal(t, 4, result.MaxReservations)
+	assert.Equal(t, 10, result.MaxCircuits)
+	assert.Zero(t, result.Utilization())
+	assert.False(t, result.Saturated())
+	assert.Greater(t, result.RTT, time.Duration(0))
+}
+
+// TestProbe_LegacyRelay 测试不支持 PROBE 的旧版本中继只返回 RTT
+func TestProbe_LegacyRelay(t *testing.T) {
+	pn := mocks.NewPipeNet()
+	local := pn.AddHost("local")
+	relayHost := pn.AddHost("relay")
+
+	relayHost.SetStreamHandler(HopProtocolID, func(s pkgif.Stream) {
+		defer s.Close()
+		c := &Client{}
+		if _, _, err := c.readMessage(s); err != nil {
+			return
+		}
+		_ = c.writeMessage(s, MsgTypeStatus, []byte{StatusUnexpectedMessage})
+	})
+
+	result, err := Probe(context.Background(), local, "relay")
+	require.NoError(t, err)
+	assert.False(t, result.LoadKnown)
+	assert.Zero(t, result.Utilization())
+}
+
+// TestProbe_NonceMismatch 测试 nonce 不匹配的响应被拒绝
+func TestProbe_NonceMismatch(t *testing.T) {
+	pn := mocks.NewPipeNet()
+	local := pn.AddHost("local")
+	relayHost := pn.AddHost("relay")
+
+	relayHost.SetStreamHandler(HopProtocolID, func(s pkgif.Stream) {
+		defer s.Close()
+		c := &Client{}
+		if _, _, err := c.readMessage(s); err != nil {
+			return
+		}
+		_ = c.writeMessage(s, MsgTypeProbe, make([]byte, probePayloadSize))
+	})
+
+	_, err := Probe(context.Background(), local, "relay")
+	assert.ErrorIs(t, err, ErrInvalidProbe)
+}
+
+// TestProbeResult_Utilization 测试利用率与负载加权延迟
+func TestProbeResult_Utilization(t *testing.T) {
+	r := &ProbeResult{
+		RTT:             100 * time.Millisecond,
+		LoadKnown:       true,
+		Reservations:    1,
+		MaxReservations: 4,
+		Circuits:        5,
+		MaxCircuits:     10,
+	}
+	assert.InDelta(t, 0.25, r.ReservationUtilization(), 1e-9)
+	assert.InDelta(t, 0.5, r.CircuitUtilization(), 1e-9)
+	assert.InDelta(t, 0.5, r.Utilization(), 1e-9)
+	assert.Equal(t, 150*time.Millisecond, r.EffectiveLatency())
+	assert.False(t, r.Saturated())
+
+	// 超出上限按满载计算
+	r.Reservations = 9
+	assert.Equal(t, 1.0, r.Utilization())
+	assert.True(t, r.Saturated())
+
+	// 上限为 0 表示不限制
+	unlimited := &ProbeResult{Reservations: 100, Circuits: 100}
+	assert.Zero(t, unlimited.Utilization())
+}
+
+// TestAutoRelay_GetCandidates_ProbeOrder 测试探测结果参与候选排序
+func TestAutoRelay_GetCandidates_ProbeOrder(t *testing.T) {
+	ar := NewAutoRelay(DefaultAutoRelayConfig(), &mockRelayClient{}, nil, nil)
+
+	ar.AddCandidate("busy", []string{}, 10)
+	ar.AddCandidate("fast", []string{}, 10)
+	ar.AddCandidate("full", []string{}, 10)
+	ar.AddCandidate("unprobed", []string{}, 10)
+
+	ar.RecordProbe("busy", &ProbeResult{
+		RTT: 20 * time.Millisecond, LoadKnown: true, Circuits: 9, MaxCircuits: 10,
+	})
+	ar.RecordProbe("fast", &ProbeResult{
+		RTT: 30 * time.Millisecond, LoadKnown: true, Circuits: 0, MaxCircuits: 10,
+	})
+	ar.RecordProbe("full", &ProbeResult{
+		RTT: 5 * time.Millisecond, LoadKnown: true, Reservations: 4, MaxReservations: 4,
+	})
+
+	candidates := ar.getCandidates(10)
+	require.Len(t, candidates, 4)
+	ids := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		ids = append(ids, c.relayID)
+	}
+	// fast: 30ms×1.0 < busy: 20ms×1.9；已探测优先于未探测；满载排最后
+	assert.Equal(t, []string{"fast", "busy", "unprobed", "full"}, ids)
+
+	results := ar.ProbeResults()
+	assert.Len(t, results, 3)
+
+	// 清除探测结果
+	ar.RecordProbe("full", nil)
+	assert.Len(t, ar.ProbeResults(), 2)
+}
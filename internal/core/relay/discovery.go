@@ -141,6 +141,10 @@ type RelayDiscovery struct {
 	latencyCache   map[types.PeerID]time.Duration
 	latencyCacheMu sync.RWMutex
 
+	// 数据预算（可选，计费网络下放慢发现与发布）
+	dataBudget   pkgif.DataBudget
+	dataBudgetMu sync.RWMutex
+
 	// 本地中继服务状态
 	isRelayServer     bool
 	relayServerConfig RelayServerConfig
@@ -738,7 +742,9 @@ func (rd *RelayDiscovery) discoveryLoop() {
 		case <-rd.ctx.Done():
 			return
 		case <-ticker.C:
-			_, _ = rd.Discover(rd.ctx)
+			if rd.allowBackground("discovery", rd.discoveryInterval) {
+				_, _ = rd.Discover(rd.ctx)
+			}
 			rd.cleanupExpired()
 		}
 	}
@@ -756,11 +762,30 @@ func (rd *RelayDiscovery) advertiseLoop() {
 		case <-rd.ctx.Done():
 			return
 		case <-ticker.C:
-			_ = rd.Advertise(rd.ctx)
+			if rd.allowBackground("advertise", rd.advertiseInterval) {
+				_ = rd.Advertise(rd.ctx)
+			}
 		}
 	}
 }
 
+// SetDataBudget 设置数据预算
+//
+// 计费网络下周期性的发现与发布按预算节流，显式调用 Discover/Advertise 不受影响。
+func (rd *RelayDiscovery) SetDataBudget(budget pkgif.DataBudget) {
+	rd.dataBudgetMu.Lock()
+	defer rd.dataBudgetMu.Unlock()
+	rd.dataBudget = budget
+}
+
+// allowBackground 检查数据预算是否允许执行后台任务
+func (rd *RelayDiscovery) allowBackground(task string, interval time.Duration) bool {
+	rd.dataBudgetMu.RLock()
+	budget := rd.dataBudget
+	rd.dataBudgetMu.RUnlock()
+	return budget == nil || budget.Allow(pkgif.BudgetSubsystemRelay, task, interval)
+}
+
 // cleanupExpired 清理过期中继
 func (rd *RelayDiscovery) cleanupExpired() {
 	rd.relaysMu.Lock()
@@ -86,6 +86,7 @@ type RelayInfo struct {
 	Latency     int64    // 延迟（毫秒）
 	Capacity    float64  // 容量（0-1）
 	Reliability float64  // 可靠性（0-1）
+	Saturated   bool     // 中继自报已满载（无法再接受预留或电路）
 }
 
 // RelayCandidate 中继候选
@@ -2,6 +2,7 @@ package relay
 
 import (
 	"context"
+	"io"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -13,6 +14,7 @@ import (
 	"github.com/dep2p/go-dep2p/internal/core/relay/client"
 	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
 	"github.com/dep2p/go-dep2p/pkg/lib/multiaddr"
+	"github.com/dep2p/go-dep2p/pkg/lib/tracing"
 	"github.com/dep2p/go-dep2p/pkg/protocol"
 	"github.com/dep2p/go-dep2p/pkg/types"
 )
@@ -343,6 +345,21 @@ func (m *Manager) RelayStats() pkgif.RelayStats {
 	return relay.Stats()
 }
 
+// FillCapabilities 填充中继相关的本节点能力
+//
+// 启用中继服务时通告 relay 角色和电路负载，对端据此选择中继。
+func (m *Manager) FillCapabilities(caps *types.PeerCapabilities) {
+	if !m.IsRelayEnabled() {
+		return
+	}
+	caps.AddRole(types.PeerRoleRelay)
+	if caps.Load == nil {
+		caps.Load = &types.LoadHint{}
+	}
+	caps.Load.RelayCircuits = m.RelayStats().ActiveCircuits
+	caps.Load.MaxRelayCircuits = m.config.MaxCircuits
+}
+
 // SetRelayAddr 设置要使用的 Relay 地址
 //
 // 时序对齐（Phase A5）：设置 Relay 后通知 lifecycle coordinator
@@ -1006,10 +1023,14 @@ func (m *Manager) registerClientStopHandler() {
 
 		length := uint32(header[1])<<24 | uint32(header[2])<<16 | uint32(header[3])<<8 | uint32(header[4])
 		var srcPeerID types.PeerID
+		var parent tracing.SpanContext
 		srcShort := "unknown"
 		if length > 0 && length < 1024 {
-			srcPeer := make([]byte, length)
-			if _, err := stream.Read(srcPeer); err == nil {
+			data := make([]byte, length)
+			if _, err := io.ReadFull(stream, data); err == nil {
+				// 消息体为源节点 ID，可能附带中继跳的追踪上下文
+				var srcPeer []byte
+				srcPeer, parent = tracing.SplitTraceContext(data)
 				srcPeerID = types.PeerID(srcPeer)
 				srcShort = string(srcPeer)
 				if len(srcShort) > 8 {
@@ -1019,12 +1040,18 @@ func (m *Manager) registerClientStopHandler() {
 		}
 		logger.Debug("收到中继连接请求", "from", srcShort)
 
+		_, span := tracing.Start(tracing.ContextWithSpanContext(context.Background(), parent),
+			"dep2p.relay.stop", tracing.KindServer,
+			tracing.Attr("peer.id", string(srcPeerID)))
+
 		response := []byte{2, 0, 0, 0, 1, 0}
 		if _, err := stream.Write(response); err != nil {
 			logger.Debug("发送 STOP 响应失败", "error", err)
+			span.End(err)
 			stream.Close()
 			return
 		}
+		span.End(nil)
 
 		// v2.0 重构：创建 RelayCircuit（支持多路复用）
 		//
@@ -38,11 +38,13 @@ func ProvideRelayService(input RelayServiceInput) (RelayServiceOutput, error) {
 type AutoRelayInput struct {
 	fx.In
 
-	Config    *config.Config `optional:"true"`
-	Swarm     pkgif.Swarm
-	Host      pkgif.Host
-	Peerstore pkgif.Peerstore
-	Discovery pkgif.Discovery `optional:"true"`
+	Config     *config.Config `optional:"true"`
+	Swarm      pkgif.Swarm
+	Host       pkgif.Host
+	Peerstore  pkgif.Peerstore
+	Discovery  pkgif.Discovery `optional:"true"`
+	DataBudget pkgif.DataBudget `optional:"true"`
+	Reputation pkgif.Reputation `optional:"true"`
 }
 
 // ProvideAutoRelay 提供 AutoRelay
@@ -70,7 +72,14 @@ func ProvideAutoRelay(input AutoRelayInput) pkgif.AutoRelay {
 		}
 	}
 
-	return client.NewAutoRelay(cfg, relayClient, input.Host, input.Peerstore)
+	autoRelay := client.NewAutoRelay(cfg, relayClient, input.Host, input.Peerstore)
+	if input.DataBudget != nil {
+		autoRelay.SetDataBudget(input.DataBudget)
+	}
+	if input.Reputation != nil {
+		autoRelay.SetReputation(input.Reputation)
+	}
+	return autoRelay
 }
 
 // Module 返回 Fx 模块
@@ -113,14 +122,19 @@ func ProvideRelayTransport(input RelayTransportInput) *RelayTransport {
 // RelayDiscoveryInput RelayDiscovery 依赖
 type RelayDiscoveryInput struct {
 	fx.In
-	Discovery pkgif.Discovery `optional:"true"`
-	Host      pkgif.Host
-	Peerstore pkgif.Peerstore
+	Discovery  pkgif.Discovery `optional:"true"`
+	Host       pkgif.Host
+	Peerstore  pkgif.Peerstore
+	DataBudget pkgif.DataBudget `optional:"true"`
 }
 
 // ProvideRelayDiscovery 提供 RelayDiscovery
 func ProvideRelayDiscovery(input RelayDiscoveryInput) *RelayDiscovery {
-	return NewRelayDiscovery(input.Discovery, input.Host, input.Peerstore, DefaultRelayDiscoveryConfig())
+	rd := NewRelayDiscovery(input.Discovery, input.Host, input.Peerstore, DefaultRelayDiscoveryConfig())
+	if input.DataBudget != nil {
+		rd.SetDataBudget(input.DataBudget)
+	}
+	return rd
 }
 
 // registerRelayTransportLifecycle 注册 RelayTransport 生命周期
@@ -160,6 +174,7 @@ func bindManagerDependencies(input struct {
 	AutoRelay            pkgif.AutoRelay               `optional:"true"`
 	Coordinator          pkgif.ReachabilityCoordinator `name:"reachability_coordinator" optional:"true"`
 	LifecycleCoordinator *lifecycle.Coordinator        `optional:"true"`
+	Capabilities         pkgif.CapabilityRegistry      `optional:"true"`
 }) {
 	if input.AutoRelay != nil {
 		input.Manager.SetAutoRelay(input.AutoRelay)
@@ -170,6 +185,9 @@ func bindManagerDependencies(input struct {
 	if input.LifecycleCoordinator != nil {
 		input.Manager.SetLifecycleCoordinator(input.LifecycleCoordinator)
 	}
+	if input.Capabilities != nil {
+		input.Capabilities.AddCapabilityProvider(input.Manager.FillCapabilities)
+	}
 }
 
 type autoRelayLifecycleInput struct {
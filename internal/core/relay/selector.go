@@ -59,6 +59,9 @@ func (s *Selector) SelectBest(relays []RelayInfo, target string) RelayInfo {
 	return best
 }
 
+// saturatedPenalty 满载中继的扣分（大于其余加分之和）
+const saturatedPenalty = 100
+
 // calculateScore 计算中继评分
 func (s *Selector) calculateScore(relay RelayInfo, target string) int {
 	score := 100 // 基础分
@@ -80,6 +83,17 @@ func (s *Selector) calculateScore(relay RelayInfo, target string) int {
 	// 可靠性评分（0-1 → 0-20分）
 	score += int(relay.Reliability * 20)
 	
+	// Identify 能力块：通告中继角色加分，负载提示满载同样扣分
+	caps := s.peerCapabilities(relay.ID)
+	if caps.HasRole(types.PeerRoleRelay) {
+		score += 10
+	}
+
+	// 满载惩罚：满载中继即使延迟低也应排在最后
+	if relay.Saturated || caps != nil && caps.Load.Saturated() {
+		score -= saturatedPenalty
+	}
+	
 	// 区域感知评分（如果启用了 GeoIP）
 	if s.geoResolver != nil && s.geoResolver.IsAvailable() {
 		targetRegion := s.getRegion(target)
@@ -92,6 +106,19 @@ func (s *Selector) calculateScore(relay RelayInfo, target string) int {
 	return score
 }
 
+// peerCapabilities 读取中继通过 Identify 通告的能力（未知时返回 nil）
+func (s *Selector) peerCapabilities(relayID string) *types.PeerCapabilities {
+	if s.peerstore == nil || relayID == "" {
+		return nil
+	}
+	v, err := s.peerstore.Get(types.PeerID(relayID), types.PeerCapabilitiesKey)
+	if err != nil {
+		return nil
+	}
+	caps, _ := types.ParsePeerCapabilities(v)
+	return caps
+}
+
 // getRegion 从地址字符串提取区域信息
 func (s *Selector) getRegion(addr string) string {
 	if s.geoResolver == nil || !s.geoResolver.IsAvailable() {
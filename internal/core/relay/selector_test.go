@@ -5,6 +5,9 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/dep2p/go-dep2p/pkg/types"
+	"github.com/dep2p/go-dep2p/tests/mocks"
 )
 
 // TestSelector_SelectBest 测试选择最佳中继
@@ -26,6 +29,45 @@ func TestSelector_SelectBest(t *testing.T) {
 	t.Log("✅ SelectBest 选择低延迟高可靠中继")
 }
 
+// TestSelector_SelectBest_Saturated 测试满载中继排在最后
+func TestSelector_SelectBest_Saturated(t *testing.T) {
+	selector := NewSelector()
+
+	relays := []RelayInfo{
+		{ID: "relay-full", Latency: 10, Capacity: 0, Reliability: 1, Saturated: true},
+		{ID: "relay-slow", Latency: 300, Capacity: 0.1, Reliability: 0.5},
+	}
+
+	best := selector.SelectBest(relays, "")
+	assert.Equal(t, "relay-slow", best.ID)
+}
+
+// TestSelector_SelectBest_Capabilities 测试使用 Identify 能力块评分
+func TestSelector_SelectBest_Capabilities(t *testing.T) {
+	selector := NewSelector()
+	ps := mocks.NewMockPeerstore()
+	selector.SetPeerstore(ps)
+
+	busy := types.NewPeerCapabilities()
+	busy.AddRole(types.PeerRoleRelay)
+	busy.Load = &types.LoadHint{RelayCircuits: 10, MaxRelayCircuits: 10}
+	require.NoError(t, ps.Put("relay-busy", types.PeerCapabilitiesKey, busy))
+
+	advertised := types.NewPeerCapabilities()
+	advertised.AddRole(types.PeerRoleRelay)
+	require.NoError(t, ps.Put("relay-advertised", types.PeerCapabilitiesKey, advertised))
+
+	relays := []RelayInfo{
+		{ID: "relay-busy", Latency: 10, Capacity: 1, Reliability: 1},
+		{ID: "relay-unknown", Latency: 60},
+		{ID: "relay-advertised", Latency: 60},
+	}
+
+	// 负载提示满载的中继被扣分，同等条件下通告中继角色的优先
+	best := selector.SelectBest(relays, "")
+	assert.Equal(t, "relay-advertised", best.ID)
+}
+
 // TestSelector_SelectBest_Empty 测试空列表
 func TestSelector_SelectBest_Empty(t *testing.T) {
 	selector := NewSelector()
@@ -0,0 +1,94 @@
+package server
+
+import (
+	"encoding/binary"
+	"io"
+	"time"
+)
+
+// PROBE 消息
+//
+// 请求负载: [nonce(8)]
+// 响应负载: [nonce(8)][reservations(4)][maxReservations(4)][circuits(4)][maxCircuits(4)]
+// 上限为 0 表示不限制。
+const (
+	probeNonceSize   = 8
+	probePayloadSize = 24
+)
+
+// CapacityLimiter 可选接口：报告服务端容量上限
+//
+// Limiter 实现此接口时，PROBE 响应携带上限，客户端据此计算利用率。
+type CapacityLimiter interface {
+	// MaxReservations 最大预约数（0 = 不限制）
+	MaxReservations() int
+	// MaxCircuitsTotal 最大活跃电路数（0 = 不限制）
+	MaxCircuitsTotal() int
+}
+
+// probeLoad PROBE 响应中的负载信息
+type probeLoad struct {
+	Reservations    int
+	MaxReservations int
+	Circuits        int
+	MaxCircuits     int
+}
+
+// encodeProbe 编码 PROBE 响应负载
+func encodeProbe(nonce []byte, stats probeLoad) []byte {
+	buf := make([]byte, probePayloadSize)
+	copy(buf[:probeNonceSize], nonce)
+	binary.BigEndian.PutUint32(buf[8:], clampUint32(stats.Reservations))
+	binary.BigEndian.PutUint32(buf[12:], clampUint32(stats.MaxReservations))
+	binary.BigEndian.PutUint32(buf[16:], clampUint32(stats.Circuits))
+	binary.BigEndian.PutUint32(buf[20:], clampUint32(stats.MaxCircuits))
+	return buf
+}
+
+// handleProbe 处理探测请求
+//
+// 探测不需要预约，也不占用电路，原样回显 nonce 并附带当前负载。
+func (s *Server) handleProbe(w io.Writer, nonce []byte) {
+	if len(nonce) != probeNonceSize {
+		s.writeStatus(w, StatusMalformedMessage)
+		return
+	}
+	s.writeMessage(w, MsgTypeProbe, encodeProbe(nonce, s.currentLoad()))
+}
+
+// currentLoad 采集当前负载
+func (s *Server) currentLoad() probeLoad {
+	now := time.Now()
+	s.mu.RLock()
+	stats := probeLoad{}
+	for _, expire := range s.reservations {
+		if now.Before(expire) {
+			stats.Reservations++
+		}
+	}
+	total := 0
+	for _, count := range s.circuits {
+		total += count
+	}
+	limiter := s.limiter
+	s.mu.RUnlock()
+
+	// 每条电路在源和目标两侧各计数一次
+	stats.Circuits = (total + 1) / 2
+
+	if cl, ok := limiter.(CapacityLimiter); ok {
+		stats.MaxReservations = cl.MaxReservations()
+		stats.MaxCircuits = cl.MaxCircuitsTotal()
+	}
+	return stats
+}
+
+func clampUint32(v int) uint32 {
+	if v < 0 {
+		return 0
+	}
+	if uint64(v) > 0xffffffff {
+		return 0xffffffff
+	}
+	return uint32(v)
+}
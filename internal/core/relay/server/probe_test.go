@@ -0,0 +1,100 @@
+package server
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dep2p/go-dep2p/pkg/types"
+	"github.com/dep2p/go-dep2p/tests/mocks"
+)
+
+// capacityLimiter 报告容量上限的测试限制器
+type capacityLimiter struct {
+	mockLimiter
+	maxReservations int
+	maxCircuits     int
+}
+
+func (c *capacityLimiter) MaxReservations() int  { return c.maxReservations }
+func (c *capacityLimiter) MaxCircuitsTotal() int { return c.maxCircuits }
+
+// probeRequest 构造 PROBE 请求并交给 HandleHop 处理
+func probeRequest(t *testing.T, server *Server, nonce []byte) *mocks.MockStream {
+	t.Helper()
+
+	stream := mocks.NewMockStream()
+	stream.ProtocolID = HopProtocolID
+	stream.ConnValue = mocks.NewMockConnection(types.PeerID("prober"), types.PeerID("relay-server"))
+
+	msg := make([]byte, 5+len(nonce))
+	msg[0] = MsgTypeProbe
+	binary.BigEndian.PutUint32(msg[1:5], uint32(len(nonce)))
+	copy(msg[5:], nonce)
+	stream.ReadData = msg
+
+	server.HandleHop(stream)
+	return stream
+}
+
+// TestRelayServer_HandleProbe 测试探测响应携带负载与上限
+func TestRelayServer_HandleProbe(t *testing.T) {
+	limiter := &capacityLimiter{
+		mockLimiter:     mockLimiter{canReserve: true, canConnect: true, reserveFor: time.Hour, maxCircuits: 10},
+		maxReservations: 4,
+		maxCircuits:     8,
+	}
+	server := NewServer(mocks.NewMockSwarm("relay-server"), limiter)
+
+	server.mu.Lock()
+	server.reservations[types.PeerID("a")] = time.Now().Add(time.Hour)
+	server.reservations[types.PeerID("b")] = time.Now().Add(time.Hour)
+	server.reservations[types.PeerID("expired")] = time.Now().Add(-time.Minute)
+	server.circuits[types.PeerID("a")] = 1
+	server.circuits[types.PeerID("b")] = 1
+	server.mu.Unlock()
+
+	nonce := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	stream := probeRequest(t, server, nonce)
+
+	require.Len(t, stream.WriteData, 5+probePayloadSize)
+	assert.Equal(t, byte(MsgTypeProbe), stream.WriteData[0])
+	payload := stream.WriteData[5:]
+	assert.Equal(t, nonce, payload[:probeNonceSize])
+	assert.Equal(t, uint32(2), binary.BigEndian.Uint32(payload[8:]), "过期预约不计入")
+	assert.Equal(t, uint32(4), binary.BigEndian.Uint32(payload[12:]))
+	assert.Equal(t, uint32(1), binary.BigEndian.Uint32(payload[16:]), "电路两端各计一次")
+	assert.Equal(t, uint32(8), binary.BigEndian.Uint32(payload[20:]))
+
+	// 探测不创建预约
+	server.mu.RLock()
+	_, reserved := server.reservations[types.PeerID("prober")]
+	server.mu.RUnlock()
+	assert.False(t, reserved)
+}
+
+// TestRelayServer_HandleProbe_NoCapacity 测试限制器不报告上限时上限为 0
+func TestRelayServer_HandleProbe_NoCapacity(t *testing.T) {
+	server, _ := setupTestServer(t)
+
+	stream := probeRequest(t, server, make([]byte, probeNonceSize))
+
+	require.Len(t, stream.WriteData, 5+probePayloadSize)
+	payload := stream.WriteData[5:]
+	assert.Zero(t, binary.BigEndian.Uint32(payload[12:]))
+	assert.Zero(t, binary.BigEndian.Uint32(payload[20:]))
+}
+
+// TestRelayServer_HandleProbe_Malformed 测试 nonce 长度错误
+func TestRelayServer_HandleProbe_Malformed(t *testing.T) {
+	server, _ := setupTestServer(t)
+
+	stream := probeRequest(t, server, []byte{1, 2, 3})
+
+	require.GreaterOrEqual(t, len(stream.WriteData), 6)
+	assert.Equal(t, byte(MsgTypeStatus), stream.WriteData[0])
+	assert.Equal(t, byte(StatusMalformedMessage), stream.WriteData[5])
+}
@@ -12,6 +12,7 @@ import (
 
 	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
 	"github.com/dep2p/go-dep2p/pkg/lib/log"
+	"github.com/dep2p/go-dep2p/pkg/lib/tracing"
 	"github.com/dep2p/go-dep2p/pkg/protocol"
 	"github.com/dep2p/go-dep2p/pkg/types"
 	mss "github.com/multiformats/go-multistream"
@@ -37,6 +38,7 @@ const (
 	MsgTypeReserve = 0
 	MsgTypeConnect = 1
 	MsgTypeStatus  = 2
+	MsgTypeProbe   = 3 // 轻量探测：测量 RTT 并获取中继负载
 )
 
 // 状态码
@@ -184,7 +186,8 @@ func (s *Server) HandleStop(stream pkgif.Stream) {
 		return
 	}
 
-	srcPeer := types.PeerID(data)
+	payload, parent := tracing.SplitTraceContext(data)
+	srcPeer := types.PeerID(payload)
 
 	// 1. 验证源节点 ID 有效性
 	if srcPeer == "" {
@@ -192,15 +195,32 @@ func (s *Server) HandleStop(stream pkgif.Stream) {
 		return
 	}
 
+	// 目标侧 Span：父 Span 为中继的 dep2p.relay.hop
+	_, span := tracing.Start(tracing.ContextWithSpanContext(context.Background(), parent),
+		"dep2p.relay.stop", tracing.KindServer,
+		tracing.Attr("peer.id", string(srcPeer)))
+	var status int
+	reply := func(code int) {
+		status = code
+		s.writeStatus(stream, code)
+	}
+	defer func() {
+		if status != StatusOK {
+			span.End(fmt.Errorf("relay status %d", status))
+			return
+		}
+		span.End(nil)
+	}()
+
 	// 2. ACL 检查：本节点是否允许来自 srcPeer 的连接
 	if s.acl != nil && !s.acl.AllowConnect(srcPeer, localPeer) {
-		s.writeStatus(stream, StatusPermissionDenied)
+		reply(StatusPermissionDenied)
 		return
 	}
 
 	// 3. Limiter 检查：资源限制
 	if s.limiter != nil && !s.limiter.CanConnect(srcPeer, localPeer) {
-		s.writeStatus(stream, StatusResourceLimitExceeded)
+		reply(StatusResourceLimitExceeded)
 		return
 	}
 
@@ -212,14 +232,14 @@ func (s *Server) HandleStop(stream pkgif.Stream) {
 	}
 	if maxCircuitsPerPeer > 0 && s.circuits[localPeer] >= maxCircuitsPerPeer {
 		s.mu.Unlock()
-		s.writeStatus(stream, StatusResourceLimitExceeded)
+		reply(StatusResourceLimitExceeded)
 		return
 	}
 	s.circuits[localPeer]++
 	s.mu.Unlock()
 
 	// 5. 返回成功，准备接收中继数据
-	s.writeStatus(stream, StatusOK)
+	reply(StatusOK)
 
 	// 注意：此时流保持打开，后续的数据由中继服务器双向转发
 	// 流的关闭由 defer 处理，或者由中继服务器在 relay() 结束后关闭
@@ -349,6 +369,9 @@ func (s *Server) HandleHop(stream pkgif.Stream) {
 		}
 		serverLogger.Info("HandleHop: 收到 CONNECT 请求", "peer", peerShort, "target", targetShort)
 		s.handleConnect(stream, peer, data)
+	case MsgTypeProbe:
+		serverLogger.Debug("处理 PROBE 请求", "peer", peerShort)
+		s.handleProbe(stream, data)
 	default:
 		serverLogger.Warn("HOP 协议收到未知消息类型", "peer", peerShort, "msgType", msgType)
 		s.writeStatus(stream, StatusUnexpectedMessage)
@@ -398,11 +421,28 @@ func (s *Server) handleConnect(stream pkgif.Stream, src types.PeerID, data []byt
 		srcShort = srcShort[:8]
 	}
 
+	// 中继跳 Span：覆盖电路建立过程，父 Span 为源节点 CONNECT 携带的追踪上下文
+	payload, parent := tracing.SplitTraceContext(data)
+	ctx, span := tracing.Start(tracing.ContextWithSpanContext(context.Background(), parent),
+		"dep2p.relay.hop", tracing.KindServer,
+		tracing.Attr("peer.id", string(src)),
+		tracing.Attr("relay.target", string(payload)))
+	status := StatusOK
+	reply := func(code int) {
+		status = code
+		s.writeStatus(stream, code)
+	}
+	defer func() {
+		if status != StatusOK {
+			span.End(fmt.Errorf("relay status %d", status))
+		}
+	}()
+
 	// 解析目标节点
-	target := types.PeerID(data)
+	target := types.PeerID(payload)
 	if target == "" {
 		serverLogger.Warn("CONNECT 失败: 目标为空", "src", srcShort)
-		s.writeStatus(stream, StatusMalformedMessage)
+		reply(StatusMalformedMessage)
 		return
 	}
 
@@ -429,7 +469,7 @@ func (s *Server) handleConnect(stream pkgif.Stream, src types.PeerID, data []byt
 		serverLogger.Warn("CONNECT 失败: 目标节点无预约或已过期",
 			"src", srcShort, "target", targetShort,
 			"dstOK", dstOK, "dstExpired", dstOK && time.Now().After(dstExpire))
-		s.writeStatus(stream, StatusNoReservation)
+		reply(StatusNoReservation)
 		return
 	}
 
@@ -439,7 +479,7 @@ func (s *Server) handleConnect(stream pkgif.Stream, src types.PeerID, data []byt
 	// 3. ACL 检查
 	if s.acl != nil && !s.acl.AllowConnect(src, target) {
 		serverLogger.Warn("CONNECT 失败: ACL 拒绝", "src", srcShort, "target", targetShort)
-		s.writeStatus(stream, StatusPermissionDenied)
+		reply(StatusPermissionDenied)
 		return
 	}
 
@@ -457,7 +497,7 @@ func (s *Server) handleConnect(stream pkgif.Stream, src types.PeerID, data []byt
 			"src", srcShort, "target", targetShort,
 			"srcCircuits", srcCircuits, "targetCircuits", targetCircuits,
 			"maxCircuitsPerPeer", maxCircuitsPerPeer)
-		s.writeStatus(stream, StatusResourceLimitExceeded)
+		reply(StatusResourceLimitExceeded)
 		return
 	}
 	s.circuits[src]++
@@ -485,7 +525,7 @@ func (s *Server) handleConnect(stream pkgif.Stream, src types.PeerID, data []byt
 				"target", string(target)[:8],
 				"error", err)
 			s.decrementCircuits(src, target)
-			s.writeStatus(stream, StatusTargetUnreachable)
+			reply(StatusTargetUnreachable)
 			return
 		}
 	}
@@ -496,7 +536,7 @@ func (s *Server) handleConnect(stream pkgif.Stream, src types.PeerID, data []byt
 			"target", string(target)[:8],
 			"error", err)
 		s.decrementCircuits(src, target)
-		s.writeStatus(stream, StatusInternalError)
+		reply(StatusInternalError)
 		return
 	}
 
@@ -508,19 +548,19 @@ func (s *Server) handleConnect(stream pkgif.Stream, src types.PeerID, data []byt
 			"error", err)
 		dstStream.Close()
 		s.decrementCircuits(src, target)
-		s.writeStatus(stream, StatusProtocolError)
+		reply(StatusProtocolError)
 		return
 	}
 	dstStream.SetProtocol(selectedProto)
 
-	// 6. 发送 STOP CONNECT 消息到目标
-	if err := s.writeStopConnect(dstStream, src); err != nil {
+	// 6. 发送 STOP CONNECT 消息到目标（附带中继跳的追踪上下文）
+	if err := s.writeStopConnect(dstStream, src, tracing.SpanContextFromContext(ctx)); err != nil {
 		serverLogger.Warn("发送 STOP CONNECT 失败",
 			"target", string(target)[:8],
 			"error", err)
 		dstStream.Close()
 		s.decrementCircuits(src, target)
-		s.writeStatus(stream, StatusInternalError)
+		reply(StatusInternalError)
 		return
 	}
 
@@ -530,14 +570,14 @@ func (s *Server) handleConnect(stream pkgif.Stream, src types.PeerID, data []byt
 		serverLogger.Warn("读取目标响应失败", "target", string(target)[:8], "error", err)
 		dstStream.Close()
 		s.decrementCircuits(src, target)
-		s.writeStatus(stream, StatusInternalError)
+		reply(StatusInternalError)
 		return
 	}
 	if msgType != MsgTypeStatus {
 		serverLogger.Warn("目标响应消息类型异常", "target", string(target)[:8], "msgType", msgType)
 		dstStream.Close()
 		s.decrementCircuits(src, target)
-		s.writeStatus(stream, StatusProtocolError)
+		reply(StatusProtocolError)
 		return
 	}
 	targetStatus := decodeStatus(statusData)
@@ -546,13 +586,15 @@ func (s *Server) handleConnect(stream pkgif.Stream, src types.PeerID, data []byt
 		dstStream.Close()
 		s.decrementCircuits(src, target)
 		// 透传目标的状态码
-		s.writeStatus(stream, targetStatus)
+		reply(targetStatus)
 		return
 	}
 
 	// 8. 返回成功给源节点
 	serverLogger.Info("CONNECT 成功", "src", srcShort, "target", targetShort)
-	s.writeStatus(stream, StatusOK)
+	reply(StatusOK)
+
+	span.End(nil)
 
 	// 9. 双向转发
 	s.relay(stream, dstStream, src, target)
@@ -628,8 +670,10 @@ func (s *Server) writeStatus(w io.Writer, status int) error {
 }
 
 // writeStopConnect 写入 STOP CONNECT 消息
-func (s *Server) writeStopConnect(w io.Writer, src types.PeerID) error {
-	return s.writeMessage(w, MsgTypeConnect, []byte(src))
+//
+// 消息体为源节点 ID，sc 有效时追加 traceparent（见 tracing.AppendTraceContext）。
+func (s *Server) writeStopConnect(w io.Writer, src types.PeerID, sc tracing.SpanContext) error {
+	return s.writeMessage(w, MsgTypeConnect, tracing.AppendTraceContext([]byte(src), sc))
 }
 
 // writeMessage 写入消息
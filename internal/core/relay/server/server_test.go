@@ -1,12 +1,15 @@
 package server
 
 import (
+	"bytes"
+	"context"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/dep2p/go-dep2p/pkg/lib/tracing"
 	"github.com/dep2p/go-dep2p/pkg/types"
 	"github.com/dep2p/go-dep2p/tests/mocks"
 )
@@ -334,3 +337,82 @@ func TestRelayServer_LimiterDeny(t *testing.T) {
 	// 状态码在第 6 个字节 (index 5)
 	assert.Equal(t, byte(StatusResourceLimitExceeded), stream.WriteData[5])
 }
+
+// withTracing 在测试期间安装内存 Exporter，返回远端父 Span 上下文
+func withTracing(t *testing.T) (*tracing.MemoryExporter, tracing.SpanContext) {
+	t.Helper()
+	exp := tracing.NewMemoryExporter()
+	prev := tracing.SetExporter(exp)
+	t.Cleanup(func() { tracing.SetExporter(prev) })
+
+	sc, err := tracing.ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	require.NoError(t, err)
+	return exp, sc
+}
+
+// findSpan 导出并查找指定名称的 Span
+func findSpan(t *testing.T, exp *tracing.MemoryExporter, name string) tracing.SpanData {
+	t.Helper()
+	require.NoError(t, tracing.ForceFlush(context.Background()))
+	for _, s := range exp.Spans() {
+		if s.Name == name {
+			return s
+		}
+	}
+	t.Fatalf("span %s not exported", name)
+	return tracing.SpanData{}
+}
+
+// TestRelayServer_HandleConnect_TraceContext 测试 CONNECT 携带的追踪上下文
+func TestRelayServer_HandleConnect_TraceContext(t *testing.T) {
+	exp, parent := withTracing(t)
+	server, _ := setupTestServer(t)
+	server.acl = &mockACL{allowReserve: true, allowConnect: true}
+
+	var msg bytes.Buffer
+	require.NoError(t, server.writeMessage(&msg, MsgTypeConnect,
+		tracing.AppendTraceContext([]byte("target-peer"), parent)))
+
+	stream := mocks.NewMockStream()
+	stream.ProtocolID = HopProtocolID
+	stream.ConnValue = mocks.NewMockConnection(types.PeerID("client-peer"), types.PeerID("relay-server"))
+	stream.ReadData = msg.Bytes()
+
+	server.HandleHop(stream)
+
+	// 目标没有预约，但追踪上下文不应混入目标节点 ID
+	require.GreaterOrEqual(t, len(stream.WriteData), 6)
+	assert.Equal(t, byte(StatusNoReservation), stream.WriteData[5])
+
+	hop := findSpan(t, exp, "dep2p.relay.hop")
+	assert.Equal(t, parent.TraceID, hop.TraceID)
+	assert.Equal(t, parent.SpanID, hop.ParentSpanID)
+	assert.True(t, hop.RemoteParent)
+	assert.Contains(t, hop.Attributes, tracing.Attr("relay.target", "target-peer"))
+	assert.NotEmpty(t, hop.Error)
+}
+
+// TestRelayServer_HandleStop_TraceContext 测试 STOP 携带的追踪上下文
+func TestRelayServer_HandleStop_TraceContext(t *testing.T) {
+	exp, parent := withTracing(t)
+	server, _ := setupTestServer(t)
+
+	var msg bytes.Buffer
+	require.NoError(t, server.writeStopConnect(&msg, types.PeerID("src-peer"), parent))
+
+	stream := mocks.NewMockStream()
+	stream.ProtocolID = StopProtocolID
+	stream.ConnValue = mocks.NewMockConnection(types.PeerID("target-peer"), types.PeerID("relay-server"))
+	stream.ReadData = msg.Bytes()
+
+	server.HandleStop(stream)
+
+	require.GreaterOrEqual(t, len(stream.WriteData), 6)
+	assert.Equal(t, byte(StatusOK), stream.WriteData[5])
+
+	stop := findSpan(t, exp, "dep2p.relay.stop")
+	assert.Equal(t, parent.TraceID, stop.TraceID)
+	assert.Equal(t, parent.SpanID, stop.ParentSpanID)
+	assert.Contains(t, stop.Attributes, tracing.Attr("peer.id", "src-peer"))
+	assert.Empty(t, stop.Error)
+}
@@ -665,6 +665,16 @@ func (l *serverLimiterAdapter) MaxCircuitsPerPeer() int {
 	return l.defaults.MaxCircuitsPerPeer
 }
 
+// MaxReservations 最大预约数（实现 server.CapacityLimiter）
+func (l *serverLimiterAdapter) MaxReservations() int {
+	return l.defaults.MaxReservations
+}
+
+// MaxCircuitsTotal 最大活跃电路数（实现 server.CapacityLimiter）
+func (l *serverLimiterAdapter) MaxCircuitsTotal() int {
+	return l.defaults.MaxCircuitsTotal
+}
+
 // ReleaseCircuit 释放电路
 func (l *serverLimiterAdapter) ReleaseCircuit(peer types.PeerID) {
 	key := string(peer)
@@ -0,0 +1,50 @@
+// Package reputation 提供跨子系统共享的节点信誉服务
+//
+// 节点质量信号原本分散在各子系统中：GossipSub 评分、连接管理器标签、
+// 抖动容错、路径健康、见证报告和 Swarm 拨号失败。本包把这些信号汇总为
+// 统一的信誉分，供连接裁剪、DHT 查询、中继选择和连接门控共同参考。
+//
+// # 信号模型
+//
+// 每个信号的值限制在 [-1, 1]，按半衰期（默认 30 分钟）向 0 衰减：
+//
+//	value(t) = value(t0) * 2^(-(t - t0) / HalfLife)
+//
+// 上报方式有两种：
+//   - Report: 事件增量（拨号失败、断连、被否决的见证报告），累加到衰减后的当前值
+//   - Observe: 当前水平（GossipSub 评分、路径状态、标签权重），替换当前值
+//
+// 统一分为各信号值按权重加权之和，默认权重之和为 100：
+//
+//	pubsub 30, dial 20, pathhealth 15, witness 15, jitter 10, connmgr 10
+//
+// # 临时封禁
+//
+// 信号更新后分数低于 BanThreshold（默认 -50）的节点被临时封禁 BanDuration
+// （默认 30 分钟）。封禁期间连接门控拒绝拨号和入站连接，DHT 查询与中继选择跳过该节点。
+// 单纯离线的节点只会累积拨号失败（最多 -20），不会被封禁。
+//
+// 引导节点、中继节点（ExemptPeers）和连接管理器保护的节点只记录信号，不封禁；
+// 封禁后才被保护的节点同样视为未封禁。
+//
+// # 容量上限
+//
+// 记录的节点数达到 MaxPeers（默认 10000）时，新节点写入前先淘汰一批
+// 分数最接近 0 的节点，处于封禁中的节点最后淘汰。
+//
+// # 使用示例
+//
+//	svc, _ := reputation.New(interfaces.DefaultReputationConfig(), nil)
+//	svc.Report(peerID, interfaces.ReputationSignalDial, -0.2, "dial_failed")
+//	svc.Observe(peerID, interfaces.ReputationSignalPubSub, -0.8, "graylisted")
+//
+//	rep := svc.Get(peerID)
+//	for _, r := range rep.Reasons {
+//	    fmt.Println(r.Signal, r.Reason, r.Contribution)
+//	}
+//
+// # 持久化
+//
+// 启用 Persist 且存储引擎可用时，记录保存在 reputation/ 前缀下，
+// 每分钟和停止时批量写入。重启后信号按保存时的更新时间继续衰减。
+package reputation
@@ -0,0 +1,70 @@
+package reputation
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+
+	"github.com/dep2p/go-dep2p/internal/core/storage/engine"
+	"github.com/dep2p/go-dep2p/internal/core/storage/kv"
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+)
+
+// storePrefix 信誉记录在存储引擎中的前缀
+var storePrefix = []byte("reputation/")
+
+// Module 返回 Fx 模块
+func Module() fx.Option {
+	return fx.Module("reputation",
+		fx.Provide(ProvideReputation),
+		fx.Invoke(registerLifecycle),
+	)
+}
+
+// reputationInput 信誉服务输入参数
+type reputationInput struct {
+	fx.In
+	Config *pkgif.ReputationConfig `optional:"true"`
+	Engine engine.InternalEngine   `optional:"true"`
+}
+
+// ProvideReputation 提供信誉服务
+//
+// 配置启用持久化且存储引擎可用时，信誉记录跨重启保留。
+func ProvideReputation(input reputationInput) (pkgif.Reputation, error) {
+	cfg := pkgif.DefaultReputationConfig()
+	if input.Config != nil {
+		cfg = *input.Config
+	}
+
+	var store *kv.Store
+	if cfg.Persist && input.Engine != nil {
+		store = kv.New(input.Engine, storePrefix)
+	}
+	return New(cfg, store)
+}
+
+// lifecycleInput 生命周期输入参数
+type lifecycleInput struct {
+	fx.In
+	LC         fx.Lifecycle
+	Reputation pkgif.Reputation
+}
+
+// registerLifecycle 注册生命周期
+func registerLifecycle(input lifecycleInput) {
+	svc, ok := input.Reputation.(*Service)
+	if !ok {
+		return
+	}
+
+	input.LC.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			// 后台循环的生命周期独立于 OnStart 的超时上下文
+			return svc.Start(context.Background())
+		},
+		OnStop: func(_ context.Context) error {
+			return svc.Stop()
+		},
+	})
+}
@@ -0,0 +1,96 @@
+package reputation
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// peerPrefix 信誉记录前缀，键为 p/<peerID>
+var peerPrefix = []byte("p/")
+
+// peerKey 返回节点记录的存储键
+func peerKey(peerID string) []byte {
+	return append(append([]byte{}, peerPrefix...), peerID...)
+}
+
+// load 从存储加载信誉记录
+//
+// 信号按保存时的更新时间继续衰减，已衰减殆尽的记录在下次清理时删除。
+func (s *Service) load() error {
+	var loadErr error
+	err := s.store.PrefixScan(peerPrefix, func(key, value []byte) bool {
+		st := &peerState{}
+		if err := json.Unmarshal(value, st); err != nil {
+			loadErr = err
+			return false
+		}
+		peerID := string(key[len(peerPrefix):])
+		if len(st.Signals) == 0 && !st.BannedUntil.After(time.Now()) {
+			s.dirty[peerID] = struct{}{}
+			return true
+		}
+		s.peers[peerID] = st
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if loadErr != nil {
+		return loadErr
+	}
+
+	logger.Debug("已加载信誉记录", "peers", len(s.peers))
+	return nil
+}
+
+// Flush 立即写入所有待写入的修改（未启用持久化时为空操作）
+func (s *Service) Flush() error {
+	if s.store == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	if len(s.dirty) == 0 {
+		s.mu.Unlock()
+		return nil
+	}
+	dirty := s.dirty
+	s.dirty = make(map[string]struct{})
+
+	batch := s.store.NewBatch()
+	for peerID := range dirty {
+		if st, ok := s.peers[peerID]; ok {
+			if err := batch.PutJSON(peerKey(peerID), st); err != nil {
+				s.remarkLocked(dirty)
+				s.mu.Unlock()
+				return err
+			}
+		} else {
+			batch.Delete(peerKey(peerID))
+		}
+	}
+	s.mu.Unlock()
+
+	if err := batch.Write(); err != nil {
+		// 写入失败时保留修改，下次重试
+		s.mu.Lock()
+		s.remarkLocked(dirty)
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// markDirtyLocked 标记节点待写入
+func (s *Service) markDirtyLocked(peerID string) {
+	if s.store != nil {
+		s.dirty[peerID] = struct{}{}
+	}
+}
+
+// remarkLocked 重新标记写入失败的节点
+func (s *Service) remarkLocked(ids map[string]struct{}) {
+	for id := range ids {
+		s.dirty[id] = struct{}{}
+	}
+}
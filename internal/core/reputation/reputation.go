@@ -0,0 +1,470 @@
+package reputation
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dep2p/go-dep2p/internal/core/storage/kv"
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/log"
+)
+
+var logger = log.Logger("core/reputation")
+
+const (
+	// maintenanceInterval 清理衰减殆尽的记录并写入存储的间隔
+	maintenanceInterval = time.Minute
+
+	// forgetThreshold 衰减后绝对值低于该值的信号视为已消失
+	forgetThreshold = 0.01
+
+	// evictFraction 达到节点数上限时一次淘汰上限的 1/evictFraction
+	evictFraction = 16
+)
+
+// truncateID 安全截取 ID 用于日志显示
+func truncateID(id string) string {
+	if len(id) <= 8 {
+		return id
+	}
+	return id[:8]
+}
+
+// signalState 单个信号的状态
+type signalState struct {
+	Value     float64   `json:"value"`
+	Reason    string    `json:"reason"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// peerState 节点的信誉状态
+type peerState struct {
+	Signals     map[pkgif.ReputationSignal]*signalState `json:"signals"`
+	BannedUntil time.Time                               `json:"banned_until,omitempty"`
+}
+
+// Protector 连接保护查询接口（由连接管理器实现）
+type Protector interface {
+	// IsProtected 检查节点是否受保护，tag 为空时检查任意标签
+	IsProtected(peerID string, tag string) bool
+}
+
+// Service 节点信誉服务
+//
+// 信号值在写入时按半衰期衰减后再累加或替换，读取时同样按经过的时间衰减，
+// 因此不需要后台定时衰减；后台循环只负责清理和持久化。
+type Service struct {
+	mu     sync.RWMutex
+	config pkgif.ReputationConfig
+	peers  map[string]*peerState
+
+	// 不封禁的节点：配置的基础设施节点和连接管理器保护的节点
+	exempt    map[string]struct{}
+	protector Protector
+
+	// 持久化（可选）
+	store *kv.Store
+	dirty map[string]struct{}
+
+	// 生命周期
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+var _ pkgif.Reputation = (*Service)(nil)
+
+// New 创建信誉服务
+//
+// store 非空时从存储加载已保存的记录，并在后台和 Stop 时写回。
+func New(config pkgif.ReputationConfig, store *kv.Store) (*Service, error) {
+	defaults := pkgif.DefaultReputationConfig()
+	if config.HalfLife <= 0 {
+		config.HalfLife = defaults.HalfLife
+	}
+	if config.BanDuration <= 0 {
+		config.BanDuration = defaults.BanDuration
+	}
+	weights := pkgif.DefaultReputationWeights()
+	for signal, weight := range config.Weights {
+		weights[signal] = weight
+	}
+	config.Weights = weights
+	if config.MaxPeers <= 0 {
+		config.MaxPeers = pkgif.DefaultReputationMaxPeers
+	}
+
+	s := &Service{
+		config: config,
+		peers:  make(map[string]*peerState),
+		exempt: make(map[string]struct{}, len(config.ExemptPeers)),
+		store:  store,
+		dirty:  make(map[string]struct{}),
+	}
+	for _, peerID := range config.ExemptPeers {
+		s.exempt[peerID] = struct{}{}
+	}
+
+	if store != nil {
+		if err := s.load(); err != nil {
+			return nil, err
+		}
+		if len(s.peers) > s.config.MaxPeers {
+			s.evictLocked(len(s.peers)-s.config.MaxPeers, time.Now())
+		}
+	}
+	return s, nil
+}
+
+// SetProtector 设置连接保护查询（可选）
+//
+// 设置后受保护的节点不会被封禁。
+func (s *Service) SetProtector(p Protector) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.protector = p
+}
+
+// isExempt 检查节点是否免于封禁
+//
+// 不持有 s.mu 调用：连接管理器可能在持有自身锁时查询信誉。
+func (s *Service) isExempt(peerID string) bool {
+	s.mu.RLock()
+	_, exempt := s.exempt[peerID]
+	protector := s.protector
+	s.mu.RUnlock()
+
+	return exempt || (protector != nil && protector.IsProtected(peerID, ""))
+}
+
+// ============================================================================
+//                              生命周期
+// ============================================================================
+
+// Start 启动后台清理与持久化
+func (s *Service) Start(ctx context.Context) error {
+	s.mu.Lock()
+	if s.ctx != nil {
+		s.mu.Unlock()
+		return nil
+	}
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go s.maintenanceLoop()
+
+	logger.Info("信誉服务已启动", "peers", s.size(), "persist", s.store != nil)
+	return nil
+}
+
+// Stop 停止后台循环并写入剩余修改
+func (s *Service) Stop() error {
+	s.mu.Lock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+
+	if err := s.Flush(); err != nil {
+		logger.Warn("写入信誉记录失败", "error", err)
+		return err
+	}
+	return nil
+}
+
+// maintenanceLoop 定期清理并持久化
+func (s *Service) maintenanceLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(maintenanceInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			s.prune()
+			if err := s.Flush(); err != nil {
+				logger.Debug("写入信誉记录失败", "error", err)
+			}
+		}
+	}
+}
+
+// prune 清理信号已衰减殆尽且未封禁的节点
+func (s *Service) prune() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for peerID, st := range s.peers {
+		if st.BannedUntil.After(now) {
+			continue
+		}
+		for signal, sig := range st.Signals {
+			if math.Abs(s.decayed(sig, now)) < forgetThreshold {
+				delete(st.Signals, signal)
+			}
+		}
+		if len(st.Signals) == 0 {
+			delete(s.peers, peerID)
+			s.markDirtyLocked(peerID)
+		}
+	}
+}
+
+// ============================================================================
+//                              信号上报
+// ============================================================================
+
+// Report 上报事件信号
+func (s *Service) Report(peerID string, signal pkgif.ReputationSignal, delta float64, reason string) {
+	if peerID == "" || delta == 0 {
+		return
+	}
+	exempt := s.isExempt(peerID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	sig := s.signalLocked(peerID, signal, now)
+	sig.Value = clamp(s.decayed(sig, now) + delta)
+	sig.Reason = reason
+	sig.UpdatedAt = now
+	s.afterUpdateLocked(peerID, exempt, now)
+}
+
+// Observe 上报水平信号
+func (s *Service) Observe(peerID string, signal pkgif.ReputationSignal, value float64, reason string) {
+	if peerID == "" {
+		return
+	}
+	exempt := s.isExempt(peerID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	sig := s.signalLocked(peerID, signal, now)
+	sig.Value = clamp(value)
+	sig.Reason = reason
+	sig.UpdatedAt = now
+	s.afterUpdateLocked(peerID, exempt, now)
+}
+
+// signalLocked 获取或创建信号状态
+//
+// 新节点使记录数达到上限时先淘汰一批分数最接近 0 的节点。
+func (s *Service) signalLocked(peerID string, signal pkgif.ReputationSignal, now time.Time) *signalState {
+	st, ok := s.peers[peerID]
+	if !ok {
+		if len(s.peers) >= s.config.MaxPeers {
+			s.evictLocked(max(1, s.config.MaxPeers/evictFraction), now)
+		}
+		st = &peerState{Signals: make(map[pkgif.ReputationSignal]*signalState)}
+		s.peers[peerID] = st
+	}
+	sig, ok := st.Signals[signal]
+	if !ok {
+		sig = &signalState{}
+		st.Signals[signal] = sig
+	}
+	return sig
+}
+
+// evictLocked 淘汰 n 个分数最接近 0 的节点
+//
+// 这些节点对裁剪和封禁的影响最小；处于封禁中的节点最后淘汰，避免提前解封。
+func (s *Service) evictLocked(n int, now time.Time) {
+	type candidate struct {
+		peerID string
+		banned bool
+		score  float64
+	}
+	candidates := make([]candidate, 0, len(s.peers))
+	for peerID, st := range s.peers {
+		candidates = append(candidates, candidate{
+			peerID: peerID,
+			banned: st.BannedUntil.After(now),
+			score:  math.Abs(s.scoreLocked(st, now)),
+		})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].banned != candidates[j].banned {
+			return !candidates[i].banned
+		}
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score < candidates[j].score
+		}
+		return candidates[i].peerID < candidates[j].peerID
+	})
+
+	n = min(n, len(candidates))
+	for _, c := range candidates[:n] {
+		delete(s.peers, c.peerID)
+		s.markDirtyLocked(c.peerID)
+	}
+	logger.Debug("信誉记录达到上限，已淘汰", "evicted", n, "peers", len(s.peers))
+}
+
+// afterUpdateLocked 信号更新后检查封禁并标记待写入
+//
+// exempt 为 true 的节点只记录信号，不封禁。
+func (s *Service) afterUpdateLocked(peerID string, exempt bool, now time.Time) {
+	s.markDirtyLocked(peerID)
+
+	threshold := s.config.BanThreshold
+	if threshold >= 0 || exempt {
+		return
+	}
+	st := s.peers[peerID]
+	if st.BannedUntil.After(now) {
+		return
+	}
+	if score := s.scoreLocked(st, now); score < threshold {
+		st.BannedUntil = now.Add(s.config.BanDuration)
+		logger.Info("节点信誉过低，临时封禁",
+			"peerID", truncateID(peerID),
+			"score", score,
+			"until", st.BannedUntil)
+	}
+}
+
+// ============================================================================
+//                              查询
+// ============================================================================
+
+// Score 返回节点的统一信誉分
+func (s *Service) Score(peerID string) float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	st, ok := s.peers[peerID]
+	if !ok {
+		return 0
+	}
+	return s.scoreLocked(st, time.Now())
+}
+
+// IsBanned 检查节点是否处于临时封禁
+//
+// 封禁后才被保护或加入基础设施节点的节点视为未封禁。
+func (s *Service) IsBanned(peerID string) bool {
+	s.mu.RLock()
+	st, ok := s.peers[peerID]
+	banned := ok && st.BannedUntil.After(time.Now())
+	s.mu.RUnlock()
+
+	return banned && !s.isExempt(peerID)
+}
+
+// Get 返回节点的信誉快照
+func (s *Service) Get(peerID string) pkgif.PeerReputation {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	st, ok := s.peers[peerID]
+	if !ok {
+		return pkgif.PeerReputation{PeerID: peerID}
+	}
+	return s.snapshotLocked(peerID, st, time.Now())
+}
+
+// List 返回所有已记录节点的信誉快照，按分数升序
+func (s *Service) List() []pkgif.PeerReputation {
+	s.mu.RLock()
+	now := time.Now()
+	result := make([]pkgif.PeerReputation, 0, len(s.peers))
+	for peerID, st := range s.peers {
+		result = append(result, s.snapshotLocked(peerID, st, now))
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Score != result[j].Score {
+			return result[i].Score < result[j].Score
+		}
+		return result[i].PeerID < result[j].PeerID
+	})
+	return result
+}
+
+// Unban 解除节点封禁并清空其信号
+func (s *Service) Unban(peerID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.peers[peerID]; !ok {
+		return
+	}
+	delete(s.peers, peerID)
+	s.markDirtyLocked(peerID)
+	logger.Info("已解除节点封禁", "peerID", truncateID(peerID))
+}
+
+// snapshotLocked 生成节点信誉快照
+func (s *Service) snapshotLocked(peerID string, st *peerState, now time.Time) pkgif.PeerReputation {
+	rep := pkgif.PeerReputation{
+		PeerID:  peerID,
+		Reasons: make([]pkgif.ReputationReason, 0, len(st.Signals)),
+	}
+	if st.BannedUntil.After(now) {
+		rep.Banned = true
+		rep.BannedUntil = st.BannedUntil
+	}
+	for signal, sig := range st.Signals {
+		value := s.decayed(sig, now)
+		contribution := value * s.config.Weights[signal]
+		rep.Score += contribution
+		rep.Reasons = append(rep.Reasons, pkgif.ReputationReason{
+			Signal:       signal,
+			Reason:       sig.Reason,
+			Value:        value,
+			Contribution: contribution,
+			UpdatedAt:    sig.UpdatedAt,
+		})
+	}
+	sort.Slice(rep.Reasons, func(i, j int) bool {
+		return math.Abs(rep.Reasons[i].Contribution) > math.Abs(rep.Reasons[j].Contribution)
+	})
+	return rep
+}
+
+// scoreLocked 计算加权分数
+func (s *Service) scoreLocked(st *peerState, now time.Time) float64 {
+	score := 0.0
+	for signal, sig := range st.Signals {
+		score += s.decayed(sig, now) * s.config.Weights[signal]
+	}
+	return score
+}
+
+// decayed 返回按半衰期衰减到 now 的信号值
+func (s *Service) decayed(sig *signalState, now time.Time) float64 {
+	elapsed := now.Sub(sig.UpdatedAt)
+	if elapsed <= 0 {
+		return sig.Value
+	}
+	return sig.Value * math.Exp2(-elapsed.Seconds()/s.config.HalfLife.Seconds())
+}
+
+// size 返回已记录的节点数
+func (s *Service) size() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.peers)
+}
+
+// clamp 将信号值限制在 [-1, 1]
+func clamp(v float64) float64 {
+	return math.Max(-1, math.Min(1, v))
+}
@@ -0,0 +1,264 @@
+package reputation
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dep2p/go-dep2p/internal/core/storage/engine"
+	"github.com/dep2p/go-dep2p/internal/core/storage/engine/badger"
+	"github.com/dep2p/go-dep2p/internal/core/storage/kv"
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+)
+
+// newTestService 创建不持久化的信誉服务
+func newTestService(t *testing.T) *Service {
+	t.Helper()
+
+	svc, err := New(pkgif.DefaultReputationConfig(), nil)
+	require.NoError(t, err)
+	return svc
+}
+
+// age 将节点所有信号的更新时间回拨 d
+func age(s *Service, peerID string, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, sig := range s.peers[peerID].Signals {
+		sig.UpdatedAt = sig.UpdatedAt.Add(-d)
+	}
+}
+
+func TestService_ReportAccumulatesAndClamps(t *testing.T) {
+	svc := newTestService(t)
+
+	svc.Report("peer1", pkgif.ReputationSignalDial, -0.4, "dial_failed")
+	svc.Report("peer1", pkgif.ReputationSignalDial, -0.4, "dial_failed")
+	assert.InDelta(t, -16, svc.Score("peer1"), 0.01)
+
+	// 信号值限制在 [-1, 1]，单一信号的贡献不超过其权重
+	for i := 0; i < 10; i++ {
+		svc.Report("peer1", pkgif.ReputationSignalDial, -0.4, "dial_failed")
+	}
+	assert.InDelta(t, -20, svc.Score("peer1"), 0.01)
+	assert.False(t, svc.IsBanned("peer1"), "dial failures alone should not ban")
+
+	assert.Zero(t, svc.Score("unknown"))
+}
+
+func TestService_Decay(t *testing.T) {
+	svc := newTestService(t)
+
+	svc.Observe("peer1", pkgif.ReputationSignalPubSub, -1, "graylisted")
+	assert.InDelta(t, -30, svc.Score("peer1"), 0.01)
+
+	age(svc, "peer1", 30*time.Minute)
+	assert.InDelta(t, -15, svc.Score("peer1"), 0.01)
+
+	// 事件在衰减后的值上累加
+	svc.Report("peer1", pkgif.ReputationSignalPubSub, -0.5, "invalid_message")
+	assert.InDelta(t, -30, svc.Score("peer1"), 0.01)
+
+	// 衰减殆尽的记录被清理
+	age(svc, "peer1", 10*time.Hour)
+	svc.prune()
+	assert.Empty(t, svc.List())
+}
+
+func TestService_GetReasons(t *testing.T) {
+	svc := newTestService(t)
+
+	svc.Observe("peer1", pkgif.ReputationSignalPathHealth, 0.5, "healthy")
+	svc.Observe("peer1", pkgif.ReputationSignalPubSub, -0.5, "below_gossip_threshold")
+
+	rep := svc.Get("peer1")
+	assert.Equal(t, "peer1", rep.PeerID)
+	assert.InDelta(t, -7.5, rep.Score, 0.01)
+	require.Len(t, rep.Reasons, 2)
+	assert.Equal(t, pkgif.ReputationSignalPubSub, rep.Reasons[0].Signal)
+	assert.Equal(t, "below_gossip_threshold", rep.Reasons[0].Reason)
+	assert.InDelta(t, -15, rep.Reasons[0].Contribution, 0.01)
+	assert.Equal(t, pkgif.ReputationSignalPathHealth, rep.Reasons[1].Signal)
+}
+
+func TestService_TemporaryBan(t *testing.T) {
+	svc := newTestService(t)
+
+	svc.Observe("peer1", pkgif.ReputationSignalPubSub, -1, "graylisted")
+	svc.Report("peer1", pkgif.ReputationSignalWitness, -1, "rejected_report")
+	assert.False(t, svc.IsBanned("peer1"))
+
+	svc.Report("peer1", pkgif.ReputationSignalDial, -0.5, "dial_failed")
+	require.True(t, svc.IsBanned("peer1"))
+
+	rep := svc.Get("peer1")
+	assert.True(t, rep.Banned)
+	assert.WithinDuration(t, time.Now().Add(30*time.Minute), rep.BannedUntil, time.Second)
+
+	// 封禁到期后自动解除
+	svc.mu.Lock()
+	svc.peers["peer1"].BannedUntil = time.Now().Add(-time.Second)
+	svc.mu.Unlock()
+	assert.False(t, svc.IsBanned("peer1"))
+
+	// 手动解除封禁时清空信号
+	svc.Report("peer1", pkgif.ReputationSignalDial, -0.1, "dial_failed")
+	require.True(t, svc.IsBanned("peer1"))
+	svc.Unban("peer1")
+	assert.False(t, svc.IsBanned("peer1"))
+	assert.Zero(t, svc.Score("peer1"))
+}
+
+func TestService_BanDisabled(t *testing.T) {
+	cfg := pkgif.DefaultReputationConfig()
+	cfg.BanThreshold = 0
+	svc, err := New(cfg, nil)
+	require.NoError(t, err)
+
+	for _, signal := range []pkgif.ReputationSignal{
+		pkgif.ReputationSignalPubSub,
+		pkgif.ReputationSignalDial,
+		pkgif.ReputationSignalWitness,
+	} {
+		svc.Observe("peer1", signal, -1, "bad")
+	}
+	assert.InDelta(t, -65, svc.Score("peer1"), 0.01)
+	assert.False(t, svc.IsBanned("peer1"))
+}
+
+// protectorFunc 连接保护查询 mock
+type protectorFunc func(peerID string) bool
+
+func (f protectorFunc) IsProtected(peerID string, _ string) bool { return f(peerID) }
+
+func TestService_ExemptPeersNotBanned(t *testing.T) {
+	cfg := pkgif.DefaultReputationConfig()
+	cfg.ExemptPeers = []string{"bootstrap1"}
+	svc, err := New(cfg, nil)
+	require.NoError(t, err)
+
+	protected := map[string]bool{"protected1": true}
+	svc.SetProtector(protectorFunc(func(peerID string) bool { return protected[peerID] }))
+
+	for _, peerID := range []string{"bootstrap1", "protected1", "peer1"} {
+		svc.Observe(peerID, pkgif.ReputationSignalPubSub, -1, "graylisted")
+		svc.Report(peerID, pkgif.ReputationSignalWitness, -1, "rejected_report")
+		svc.Report(peerID, pkgif.ReputationSignalDial, -1, "dial_failed")
+	}
+
+	// 信号照常记录，但不封禁
+	assert.InDelta(t, -65, svc.Score("bootstrap1"), 0.01)
+	assert.False(t, svc.IsBanned("bootstrap1"))
+	assert.False(t, svc.Get("bootstrap1").Banned)
+	assert.InDelta(t, -65, svc.Score("protected1"), 0.01)
+	assert.False(t, svc.IsBanned("protected1"))
+	assert.True(t, svc.IsBanned("peer1"))
+
+	// 封禁后才受保护的节点视为未封禁
+	protected["peer1"] = true
+	assert.False(t, svc.IsBanned("peer1"))
+}
+
+func TestService_MaxPeersEvictsClosestToZero(t *testing.T) {
+	cfg := pkgif.DefaultReputationConfig()
+	cfg.MaxPeers = 4
+	svc, err := New(cfg, nil)
+	require.NoError(t, err)
+
+	svc.Observe("strong-bad", pkgif.ReputationSignalPubSub, -0.9, "graylisted")
+	svc.Observe("strong-good", pkgif.ReputationSignalPubSub, 0.9, "mesh")
+	svc.Observe("weak-bad", pkgif.ReputationSignalPubSub, -0.05, "low")
+	svc.Observe("weak-good", pkgif.ReputationSignalPubSub, 0.1, "low")
+	require.Equal(t, 4, svc.size())
+
+	// 达到上限，淘汰分数最接近 0 的节点
+	svc.Observe("new", pkgif.ReputationSignalDial, 0.5, "dial_ok")
+	assert.Equal(t, 4, svc.size())
+	_, ok := svc.peers["weak-bad"]
+	assert.False(t, ok, "closest to zero should be evicted first")
+	for _, peerID := range []string{"strong-bad", "strong-good", "weak-good", "new"} {
+		_, ok := svc.peers[peerID]
+		assert.True(t, ok, peerID)
+	}
+
+	// 已记录的节点更新不触发淘汰
+	svc.Observe("strong-good", pkgif.ReputationSignalDial, 0.5, "dial_ok")
+	assert.Equal(t, 4, svc.size())
+}
+
+func TestService_MaxPeersKeepsBannedPeers(t *testing.T) {
+	cfg := pkgif.DefaultReputationConfig()
+	cfg.MaxPeers = 2
+	cfg.BanThreshold = -5
+	svc, err := New(cfg, nil)
+	require.NoError(t, err)
+
+	// 封禁后信号衰减到接近 0，仍不先于未封禁节点淘汰
+	svc.Observe("banned", pkgif.ReputationSignalPubSub, -0.5, "graylisted")
+	require.True(t, svc.IsBanned("banned"))
+	age(svc, "banned", 5*time.Hour)
+	svc.Observe("peer1", pkgif.ReputationSignalPubSub, 0.5, "mesh")
+
+	svc.Observe("peer2", pkgif.ReputationSignalPubSub, 0.6, "mesh")
+	assert.True(t, svc.IsBanned("banned"))
+	_, ok := svc.peers["peer1"]
+	assert.False(t, ok)
+}
+
+func TestService_CustomWeights(t *testing.T) {
+	cfg := pkgif.DefaultReputationConfig()
+	cfg.Weights = map[pkgif.ReputationSignal]float64{pkgif.ReputationSignalDial: 50}
+	svc, err := New(cfg, nil)
+	require.NoError(t, err)
+
+	svc.Observe("peer1", pkgif.ReputationSignalDial, -1, "dial_failed")
+	svc.Observe("peer1", pkgif.ReputationSignalJitter, -1, "flapping")
+	assert.InDelta(t, -60, svc.Score("peer1"), 0.01)
+}
+
+func TestService_List(t *testing.T) {
+	svc := newTestService(t)
+
+	svc.Observe("good", pkgif.ReputationSignalPathHealth, 1, "healthy")
+	svc.Observe("bad", pkgif.ReputationSignalPathHealth, -1, "dead")
+
+	list := svc.List()
+	require.Len(t, list, 2)
+	assert.Equal(t, "bad", list[0].PeerID)
+	assert.Equal(t, "good", list[1].PeerID)
+}
+
+func TestService_Persistence(t *testing.T) {
+	eng, err := badger.New(engine.DefaultConfig(filepath.Join(t.TempDir(), "reputation.db")))
+	require.NoError(t, err)
+	t.Cleanup(func() { eng.Close() })
+	store := kv.New(eng, storePrefix)
+
+	svc, err := New(pkgif.DefaultReputationConfig(), store)
+	require.NoError(t, err)
+	svc.Observe("peer1", pkgif.ReputationSignalPubSub, -1, "graylisted")
+	svc.Report("peer1", pkgif.ReputationSignalWitness, -1, "rejected_report")
+	svc.Report("peer1", pkgif.ReputationSignalDial, -0.5, "dial_failed")
+	svc.Observe("peer2", pkgif.ReputationSignalPathHealth, 0.5, "healthy")
+	require.True(t, svc.IsBanned("peer1"))
+	require.NoError(t, svc.Stop())
+
+	reloaded, err := New(pkgif.DefaultReputationConfig(), store)
+	require.NoError(t, err)
+	assert.True(t, reloaded.IsBanned("peer1"))
+	assert.InDelta(t, -55, reloaded.Score("peer1"), 0.1)
+	assert.InDelta(t, 7.5, reloaded.Score("peer2"), 0.1)
+	assert.Equal(t, "graylisted", reloaded.Get("peer1").Reasons[0].Reason)
+
+	// 解除封禁后删除存储记录
+	reloaded.Unban("peer1")
+	require.NoError(t, reloaded.Flush())
+
+	again, err := New(pkgif.DefaultReputationConfig(), store)
+	require.NoError(t, err)
+	assert.False(t, again.IsBanned("peer1"))
+	assert.Len(t, again.List(), 1)
+}
@@ -8,6 +8,7 @@
 //   - 证书生成：嵌入 Ed25519 公钥到证书扩展
 //   - 身份验证：实现 INV-001（验证 PeerID 匹配）
 //   - 前向保密：TLS 1.3 强制 ECDHE
+//   - 混合后量子（可选）：X25519 + ML-KEM-768，经典节点自动回退
 //
 // # 使用示例
 //
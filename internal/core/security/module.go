@@ -5,6 +5,8 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/dep2p/go-dep2p/config"
@@ -32,6 +34,8 @@ type Config struct {
 	Preferred string // "tls" or "noise"
 	// NegotiateTimeout 协商超时
 	NegotiateTimeout time.Duration
+	// PostQuantum 是否启用 X25519 + ML-KEM-768 混合握手
+	PostQuantum bool
 }
 
 // ConfigFromUnified 从统一配置创建安全配置
@@ -57,6 +61,7 @@ func ConfigFromUnified(cfg *config.Config) Config {
 		Transports:       transports,
 		Preferred:        cfg.Security.PreferredProtocol,
 		NegotiateTimeout: cfg.Security.NegotiateTimeout.Duration(),
+		PostQuantum:      cfg.Security.PostQuantum,
 	}
 }
 
@@ -130,6 +135,9 @@ func ProvideNoiseTransport(id pkgif.Identity) (pkgif.SecureTransport, error) {
 //
 // SecurityMux 实现了 multistream-select 协议协商，
 // 支持多个安全传输协议（TLS, Noise）并动态选择。
+//
+// 启用后量子时，每个协议额外注册混合变体（"tls-pq"、"noise-pq"），
+// 出站协商优先提议混合变体；不支持的节点回复 na 后回退到经典协议。
 type SecurityMux struct {
 	transports       map[string]pkgif.SecureTransport
 	preferred        string
@@ -137,6 +145,9 @@ type SecurityMux struct {
 	negotiateTimeout time.Duration
 }
 
+// hybridSuffix 混合后量子协议名后缀
+const hybridSuffix = "-pq"
+
 // 确保实现接口
 var _ pkgif.SecureTransport = (*SecurityMux)(nil)
 
@@ -173,33 +184,19 @@ func NewSecurityMux(params SecurityMuxParams) (*SecurityMux, error) {
 
 	// 注册启用的传输协议
 	for _, proto := range cfg.Transports {
-		switch proto {
-		case "tls":
-			t, err := tls.New(id)
-			if err != nil {
-				return nil, fmt.Errorf("create tls transport: %w", err)
-			}
-			// 集成 AccessControl（如果存在）
-			if params.AccessControl != nil {
-				t.SetAccessControl(params.AccessControl)
-				logger.Debug("TLS Transport 已集成 AccessControl")
-			}
-			mux.transports[proto] = t
+		t, err := newSecureTransport(params, proto, false)
+		if err != nil {
+			return nil, err
+		}
+		mux.transports[proto] = t
 
-		case "noise":
-			t, err := noise.New(id)
+		// 注册混合后量子变体
+		if cfg.PostQuantum {
+			t, err := newSecureTransport(params, proto, true)
 			if err != nil {
-				return nil, fmt.Errorf("create noise transport: %w", err)
-			}
-			// 集成 IdentityBinding（如果存在）
-			if params.IdentityBinding != nil {
-				t.SetIdentityBinding(params.IdentityBinding)
-				logger.Debug("Noise Transport 已集成 IdentityBinding")
+				return nil, err
 			}
-			mux.transports[proto] = t
-
-		default:
-			return nil, fmt.Errorf("unknown transport protocol: %s", proto)
+			mux.transports[proto+hybridSuffix] = t
 		}
 	}
 
@@ -224,6 +221,47 @@ func NewSecurityMux(params SecurityMuxParams) (*SecurityMux, error) {
 	return mux, nil
 }
 
+// newSecureTransport 创建单个安全传输协议
+func newSecureTransport(params SecurityMuxParams, proto string, hybrid bool) (pkgif.SecureTransport, error) {
+	id := params.Identity
+	switch proto {
+	case "tls":
+		newTLS := tls.New
+		if hybrid {
+			newTLS = tls.NewHybrid
+		}
+		t, err := newTLS(id)
+		if err != nil {
+			return nil, fmt.Errorf("create tls transport: %w", err)
+		}
+		// 集成 AccessControl（如果存在）
+		if params.AccessControl != nil {
+			t.SetAccessControl(params.AccessControl)
+			logger.Debug("TLS Transport 已集成 AccessControl")
+		}
+		return t, nil
+
+	case "noise":
+		newNoise := noise.New
+		if hybrid {
+			newNoise = noise.NewHybrid
+		}
+		t, err := newNoise(id)
+		if err != nil {
+			return nil, fmt.Errorf("create noise transport: %w", err)
+		}
+		// 集成 IdentityBinding（如果存在）
+		if params.IdentityBinding != nil {
+			t.SetIdentityBinding(params.IdentityBinding)
+			logger.Debug("Noise Transport 已集成 IdentityBinding")
+		}
+		return t, nil
+
+	default:
+		return nil, fmt.Errorf("unknown transport protocol: %s", proto)
+	}
+}
+
 // ID 返回协议标识
 func (m *SecurityMux) ID() types.ProtocolID {
 	return types.ProtocolID("/security/multistream/1.0.0")
@@ -231,7 +269,7 @@ func (m *SecurityMux) ID() types.ProtocolID {
 
 // SecureInbound 保护入站连接（服务器端）
 func (m *SecurityMux) SecureInbound(ctx context.Context, conn net.Conn, remotePeer types.PeerID) (pkgif.SecureConn, error) {
-	logger.Debug("安全协商入站连接", "remotePeer", string(remotePeer)[:8])
+	logger.Debug("安全协商入站连接", "remotePeer", remotePeer.ShortString())
 	
 	// 设置协商超时
 	deadline := time.Now().Add(m.negotiateTimeout)
@@ -277,7 +315,7 @@ func (m *SecurityMux) SecureInbound(ctx context.Context, conn net.Conn, remotePe
 
 // SecureOutbound 保护出站连接（客户端端）
 func (m *SecurityMux) SecureOutbound(ctx context.Context, conn net.Conn, remotePeer types.PeerID) (pkgif.SecureConn, error) {
-	logger.Debug("安全协商出站连接", "remotePeer", string(remotePeer)[:8], "preferred", m.preferred)
+	logger.Debug("安全协商出站连接", "remotePeer", remotePeer.ShortString(), "preferred", m.preferred)
 	
 	// 设置协商超时
 	deadline := time.Now().Add(m.negotiateTimeout)
@@ -289,20 +327,8 @@ func (m *SecurityMux) SecureOutbound(ctx context.Context, conn net.Conn, remoteP
 	}
 	defer conn.SetDeadline(time.Time{}) // 清除超时
 
-	// 构建协议列表（首选协议在前）
-	protocols := make([]string, 0, len(m.transports))
-
-	// 首选协议放在第一位
-	if m.preferred != "" {
-		protocols = append(protocols, m.preferred)
-	}
-
-	// 添加其他协议
-	for proto := range m.transports {
-		if proto != m.preferred {
-			protocols = append(protocols, proto)
-		}
-	}
+	// 构建协议列表（混合协议、首选协议在前）
+	protocols := m.proposals()
 
 	// 客户端协商：提议协议列表，服务端选择
 	selectedProto, err := mss.SelectOneOf(protocols, conn)
@@ -330,6 +356,32 @@ func (m *SecurityMux) SecureOutbound(ctx context.Context, conn net.Conn, remoteP
 	return secConn, err
 }
 
+// proposals 返回出站协商时的提议顺序
+//
+// multistream-select 每被拒绝一个提议就多一次往返，因此启用后量子时只提议
+// 首选协议的一个混合变体，随后是经典的首选协议，其余经典协议作为最后的回退。
+// 经典节点拒绝混合变体后只多一次往返即可落到经典首选协议。
+func (m *SecurityMux) proposals() []string {
+	preferred := strings.TrimSuffix(m.preferred, hybridSuffix)
+
+	var protocols []string
+	if _, ok := m.transports[preferred+hybridSuffix]; ok {
+		protocols = append(protocols, preferred+hybridSuffix)
+	}
+	if _, ok := m.transports[preferred]; ok {
+		protocols = append(protocols, preferred)
+	}
+
+	var rest []string
+	for proto := range m.transports {
+		if proto != preferred && !strings.HasSuffix(proto, hybridSuffix) {
+			rest = append(rest, proto)
+		}
+	}
+	sort.Strings(rest)
+	return append(protocols, rest...)
+}
+
 // ListProtocols 列出所有支持的协议
 func (m *SecurityMux) ListProtocols() []string {
 	protocols := make([]string, 0, len(m.transports))
@@ -2,12 +2,16 @@ package security
 
 import (
 	"context"
+	"io"
 	"net"
 	"testing"
 	"time"
 
 	"github.com/dep2p/go-dep2p/config"
 	"github.com/dep2p/go-dep2p/internal/core/identity"
+	"github.com/dep2p/go-dep2p/internal/core/security/noise"
+	"github.com/dep2p/go-dep2p/internal/core/security/tls"
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
 	"github.com/dep2p/go-dep2p/pkg/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -318,3 +322,98 @@ func TestModule(t *testing.T) {
 	opt := Module()
 	assert.NotNil(t, opt)
 }
+
+// handshakeMux 使用两个 SecurityMux 完成握手并互相发送一条消息
+func handshakeMux(t *testing.T, clientCfg, serverCfg Config) (client, server pkgif.SecureConn) {
+	t.Helper()
+	serverIdentity, err := identity.Generate()
+	require.NoError(t, err)
+	clientIdentity, err := identity.Generate()
+	require.NoError(t, err)
+
+	serverMux, err := NewSecurityMux(SecurityMuxParams{Identity: serverIdentity, Config: serverCfg})
+	require.NoError(t, err)
+	clientMux, err := NewSecurityMux(SecurityMuxParams{Identity: clientIdentity, Config: clientCfg})
+	require.NoError(t, err)
+
+	serverConn, clientConn := net.Pipe()
+	t.Cleanup(func() { serverConn.Close(); clientConn.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var serverErr error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		server, serverErr = serverMux.SecureInbound(ctx, serverConn, "")
+		if serverErr != nil {
+			return
+		}
+		buf := make([]byte, 4)
+		if _, serverErr = io.ReadFull(server, buf); serverErr == nil {
+			_, serverErr = server.Write(buf)
+		}
+	}()
+
+	client, err = clientMux.SecureOutbound(ctx, clientConn, types.PeerID(serverIdentity.PeerID()))
+	require.NoError(t, err)
+	_, err = client.Write([]byte("ping"))
+	require.NoError(t, err)
+	buf := make([]byte, 4)
+	_, err = io.ReadFull(client, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(buf))
+
+	<-done
+	require.NoError(t, serverErr)
+	return client, server
+}
+
+func TestSecurityMux_PostQuantum(t *testing.T) {
+	config := func(preferred string, postQuantum bool) Config {
+		cfg := NewConfig()
+		cfg.Preferred = preferred
+		cfg.PostQuantum = postQuantum
+		return cfg
+	}
+
+	tests := []struct {
+		name        string
+		client      Config
+		server      Config
+		protocol    types.ProtocolID
+		keyExchange string
+	}{
+		{"hybrid noise", config("noise", true), config("tls", true), noise.HybridProtocolID, pkgif.KeyExchangeX25519MLKEM768},
+		{"hybrid tls", config("tls", true), config("noise", true), tls.HybridProtocolID, pkgif.KeyExchangeX25519MLKEM768},
+		{"classical server", config("noise", true), config("tls", false), noise.ProtocolID, pkgif.KeyExchangeX25519},
+		{"classical client", config("tls", false), config("tls", true), tls.ProtocolID, pkgif.KeyExchangeX25519},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, server := handshakeMux(t, tt.client, tt.server)
+			for _, conn := range []pkgif.SecureConn{client, server} {
+				state := conn.ConnState()
+				assert.Equal(t, tt.protocol, state.Protocol)
+				assert.Equal(t, tt.keyExchange, state.KeyExchange)
+			}
+		})
+	}
+}
+
+func TestSecurityMux_Proposals(t *testing.T) {
+	id, err := identity.Generate()
+	require.NoError(t, err)
+
+	cfg := NewConfig()
+	cfg.Preferred = "noise"
+	cfg.PostQuantum = true
+	mux, err := NewSecurityMux(SecurityMuxParams{Identity: id, Config: cfg})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"noise-pq", "noise", "tls"}, mux.proposals(), "只提议一个混合变体")
+
+	mux, err = NewSecurityMux(SecurityMuxParams{Identity: id, Config: NewConfig()})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tls", "noise"}, mux.proposals())
+}
@@ -29,6 +29,9 @@ type secureConn struct {
 	// 节点信息
 	localPeer  types.PeerID
 	remotePeer types.PeerID
+
+	// hybrid 是否为 X25519 + ML-KEM-768 混合握手
+	hybrid bool
 	
 	// 读写锁
 	readMu  sync.Mutex
@@ -147,12 +150,18 @@ func (c *secureConn) RemotePublicKey() []byte {
 
 // ConnState 返回连接状态
 func (c *secureConn) ConnState() pkgif.SecureConnState {
-	return pkgif.SecureConnState{
-		Protocol:        types.ProtocolID("/noise/1.0.0"),
+	state := pkgif.SecureConnState{
+		Protocol:        ProtocolID,
 		LocalPeer:       c.localPeer,
 		RemotePeer:      c.remotePeer,
 		LocalPublicKey:  nil,
 		RemotePublicKey: nil,
 		Opened:          true,
+		KeyExchange:     pkgif.KeyExchangeX25519,
+	}
+	if c.hybrid {
+		state.Protocol = HybridProtocolID
+		state.KeyExchange = pkgif.KeyExchangeX25519MLKEM768
 	}
+	return state
 }
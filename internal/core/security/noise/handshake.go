@@ -15,6 +15,7 @@ package noise
 
 import (
 	"crypto/ed25519"
+	"crypto/mlkem"
 	"crypto/sha512"
 	"encoding/binary"
 	"fmt"
@@ -47,11 +48,12 @@ const payloadSigPrefix = "noise-libp2p-static-key:"
 //   - privKey: 本地私钥（Ed25519）
 //   - remotePeer: 期望的远程 PeerID（用于验证，可为空）
 //   - isInitiator: true = 客户端，false = 服务器
+//   - hybrid: 是否使用 X25519 + ML-KEM-768 混合握手（Noise_XXpsk3）
 //
 // 返回：
 //   - *secureConn: 加密连接
 //   - error: 握手失败时的错误
-func performHandshake(conn net.Conn, privKey pkgif.PrivateKey, remotePeer types.PeerID, isInitiator bool) (*secureConn, error) {
+func performHandshake(conn net.Conn, privKey pkgif.PrivateKey, remotePeer types.PeerID, isInitiator, hybrid bool) (*secureConn, error) {
 	// 1. 密钥转换：Ed25519 -> Curve25519
 	privKeyBytes, err := privKey.Raw()
 	if err != nil {
@@ -71,12 +73,17 @@ func performHandshake(conn net.Conn, privKey pkgif.PrivateKey, remotePeer types.
 	cs := noise.NewCipherSuite(noise.DH25519, noise.CipherChaChaPoly, noise.HashSHA256)
 	staticKeypair := noise.DHKey{Private: curve25519Priv, Public: curve25519Pub}
 
-	hs, err := noise.NewHandshakeState(noise.Config{
+	hsConfig := noise.Config{
 		CipherSuite:   cs,
 		Pattern:       noise.HandshakeXX,
 		Initiator:     isInitiator,
 		StaticKeypair: staticKeypair,
-	})
+	}
+	if hybrid {
+		// psk 在收到 ML-KEM 密文后设置
+		hsConfig.PresharedKeyPlacement = hybridPSKPlacement
+	}
+	hs, err := noise.NewHandshakeState(hsConfig)
 	if err != nil {
 		return nil, fmt.Errorf("create handshake state: %w", err)
 	}
@@ -92,9 +99,9 @@ func performHandshake(conn net.Conn, privKey pkgif.PrivateKey, remotePeer types.
 	var remotePayload []byte
 
 	if isInitiator {
-		sendCS, recvCS, remotePayload, err = clientHandshake(conn, hs, localPayload)
+		sendCS, recvCS, remotePayload, err = clientHandshake(conn, hs, localPayload, hybrid)
 	} else {
-		sendCS, recvCS, remotePayload, err = serverHandshake(conn, hs, localPayload)
+		sendCS, recvCS, remotePayload, err = serverHandshake(conn, hs, localPayload, hybrid)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("handshake: %w", err)
@@ -129,6 +136,7 @@ func performHandshake(conn net.Conn, privKey pkgif.PrivateKey, remotePeer types.
 		recvCS:     recvCS,
 		localPeer:  localPeer,
 		remotePeer: actualRemotePeer,
+		hybrid:     hybrid,
 		readBuf:    nil,
 	}, nil
 }
@@ -218,9 +226,21 @@ func handleRemotePayload(payloadBytes []byte, remoteStatic []byte) (types.PeerID
 //  1. -> e                              (发送临时公钥)
 //  2. <- e, ee, s, es, payload          (接收响应者的静态公钥和 payload)
 //  3. -> s, se, payload                 (发送本地静态公钥和 payload)
-func clientHandshake(conn net.Conn, hs *noise.HandshakeState, localPayload []byte) (*noise.CipherState, *noise.CipherState, []byte, error) {
-	// 轮次 1: 发送 e (空 payload)
-	msg1, _, _, err := hs.WriteMessage(nil, nil)
+//
+// 混合握手时第 1 条消息携带 ML-KEM 封装公钥，第 2 条消息 payload 前缀为密文。
+func clientHandshake(conn net.Conn, hs *noise.HandshakeState, localPayload []byte, hybrid bool) (*noise.CipherState, *noise.CipherState, []byte, error) {
+	var dk *mlkem.DecapsulationKey768
+	var msg1Payload []byte
+	if hybrid {
+		var err error
+		dk, msg1Payload, err = newHybridKEM()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	// 轮次 1: 发送 e (经典握手为空 payload)
+	msg1, _, _, err := hs.WriteMessage(nil, msg1Payload)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("write message 1: %w", err)
 	}
@@ -237,6 +257,12 @@ func clientHandshake(conn net.Conn, hs *noise.HandshakeState, localPayload []byt
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("read message 2: %w", err)
 	}
+	if hybrid {
+		remotePayload, err = decapsulateHybrid(hs, dk, remotePayload)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
 
 	// 轮次 3: 发送 s, se, payload (最后一轮，返回 CipherStates)
 	msg3, cs1, cs2, err := hs.WriteMessage(nil, localPayload)
@@ -257,16 +283,25 @@ func clientHandshake(conn net.Conn, hs *noise.HandshakeState, localPayload []byt
 //  1. <- e                              (接收临时公钥)
 //  2. -> e, ee, s, es, payload          (发送本地静态公钥和 payload)
 //  3. <- s, se, payload                 (接收发起者的静态公钥和 payload)
-func serverHandshake(conn net.Conn, hs *noise.HandshakeState, localPayload []byte) (*noise.CipherState, *noise.CipherState, []byte, error) {
+//
+// 混合握手时根据第 1 条消息中的 ML-KEM 封装公钥生成密文，作为第 2 条消息 payload 前缀。
+func serverHandshake(conn net.Conn, hs *noise.HandshakeState, localPayload []byte, hybrid bool) (*noise.CipherState, *noise.CipherState, []byte, error) {
 	// 轮次 1: 接收 e
 	msg1, err := readFrame(conn)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("receive message 1: %w", err)
 	}
-	_, _, _, err = hs.ReadMessage(nil, msg1)
+	msg1Payload, _, _, err := hs.ReadMessage(nil, msg1)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("read message 1: %w", err)
 	}
+	if hybrid {
+		ciphertext, err := encapsulateHybrid(hs, msg1Payload)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		localPayload = append(ciphertext, localPayload...)
+	}
 
 	// 轮次 2: 发送 e, ee, s, es, payload
 	msg2, _, _, err := hs.WriteMessage(nil, localPayload)
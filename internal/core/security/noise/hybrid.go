@@ -0,0 +1,75 @@
+// Package noise 实现 Noise 协议安全传输
+//
+// 混合后量子握手（Noise_XXpsk3 + ML-KEM-768）：
+//
+//	-> e, payload(ek)                         (发起者发送临时公钥和 ML-KEM 封装公钥)
+//	<- e, ee, s, es, payload(ct || identity)  (响应者封装共享密钥，回传密文)
+//	-> s, se, psk, payload(identity)          (双方将 ML-KEM 共享密钥作为 psk 混入)
+//
+// 最终会话密钥同时依赖 X25519 与 ML-KEM-768，任一算法未被攻破即保证机密性。
+// ML-KEM 密钥对每次握手临时生成，保持前向保密。
+package noise
+
+import (
+	"crypto/mlkem"
+	"fmt"
+
+	"github.com/flynn/noise"
+
+	"github.com/dep2p/go-dep2p/pkg/types"
+)
+
+// 协议标识
+const (
+	// ProtocolID 经典 Noise XX（X25519）
+	ProtocolID = types.ProtocolID("/noise/1.0.0")
+
+	// HybridProtocolID 混合后量子 Noise（X25519 + ML-KEM-768）
+	HybridProtocolID = types.ProtocolID("/noise-pq/1.0.0")
+)
+
+// hybridPSKPlacement ML-KEM 共享密钥作为 psk 混入第 3 条消息（Noise_XXpsk3）
+const hybridPSKPlacement = 3
+
+// newHybridKEM 生成发起者的临时 ML-KEM-768 密钥对
+//
+// 返回解封装私钥和需要在第 1 条消息中发送的封装公钥。
+func newHybridKEM() (*mlkem.DecapsulationKey768, []byte, error) {
+	dk, err := mlkem.GenerateKey768()
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate ml-kem key: %w", err)
+	}
+	return dk, dk.EncapsulationKey().Bytes(), nil
+}
+
+// encapsulateHybrid 响应者根据发起者的封装公钥生成共享密钥
+//
+// 共享密钥设置为握手 psk，返回需要回传给发起者的密文。
+func encapsulateHybrid(hs *noise.HandshakeState, ekBytes []byte) ([]byte, error) {
+	ek, err := mlkem.NewEncapsulationKey768(ekBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ml-kem encapsulation key: %w", err)
+	}
+	sharedKey, ciphertext := ek.Encapsulate()
+	if err := hs.SetPresharedKey(sharedKey); err != nil {
+		return nil, fmt.Errorf("set psk: %w", err)
+	}
+	return ciphertext, nil
+}
+
+// decapsulateHybrid 发起者从第 2 条消息的 payload 中取出密文并解封装
+//
+// 共享密钥设置为握手 psk，返回剩余的身份 payload。
+func decapsulateHybrid(hs *noise.HandshakeState, dk *mlkem.DecapsulationKey768, payload []byte) ([]byte, error) {
+	if len(payload) < mlkem.CiphertextSize768 {
+		return nil, fmt.Errorf("ml-kem ciphertext too short: %d", len(payload))
+	}
+	sharedKey, err := dk.Decapsulate(payload[:mlkem.CiphertextSize768])
+	if err != nil {
+		return nil, fmt.Errorf("decapsulate ml-kem: %w", err)
+	}
+	if err := hs.SetPresharedKey(sharedKey); err != nil {
+		return nil, fmt.Errorf("set psk: %w", err)
+	}
+	return payload[mlkem.CiphertextSize768:], nil
+}
@@ -17,6 +17,7 @@ var logger = log.Logger("core/security/noise")
 type Transport struct {
 	identity        pkgif.Identity
 	identityBinding *IdentityBinding // 可选的身份绑定验证器
+	hybrid          bool             // 是否使用 X25519 + ML-KEM-768 混合握手
 }
 
 // New 创建 Noise 传输
@@ -30,6 +31,19 @@ func New(identity pkgif.Identity) (*Transport, error) {
 	}, nil
 }
 
+// NewHybrid 创建混合后量子 Noise 传输
+//
+// 在 Noise XX 的基础上以 ML-KEM-768 共享密钥作为 psk（Noise_XXpsk3），
+// 协议标识为 HybridProtocolID，与经典 Noise 并列注册到安全协商中。
+func NewHybrid(identity pkgif.Identity) (*Transport, error) {
+	t, err := New(identity)
+	if err != nil {
+		return nil, err
+	}
+	t.hybrid = true
+	return t, nil
+}
+
 // SetIdentityBinding 设置身份绑定验证器
 //
 // 设置后，握手完成时会验证远程节点的身份绑定
@@ -44,7 +58,10 @@ func (t *Transport) GetIdentityBinding() *IdentityBinding {
 
 // ID 返回协议标识
 func (t *Transport) ID() types.ProtocolID {
-	return types.ProtocolID("/noise/1.0.0")
+	if t.hybrid {
+		return HybridProtocolID
+	}
+	return ProtocolID
 }
 
 // SecureInbound 保护入站连接
@@ -64,7 +81,7 @@ func (t *Transport) SecureInbound(_ context.Context, conn net.Conn, remotePeer t
 	privKey := t.identity.PrivateKey()
 	
 	// 执行 Noise XX 握手（服务器端）
-	secConn, err := performHandshake(conn, privKey, remotePeer, false)
+	secConn, err := performHandshake(conn, privKey, remotePeer, false, t.hybrid)
 	if err != nil {
 		logger.Warn("Noise 握手失败", "remotePeer", remotePeerLabel, "error", err)
 		return nil, fmt.Errorf("handshake failed: %w", err)
@@ -101,7 +118,7 @@ func (t *Transport) SecureOutbound(_ context.Context, conn net.Conn, remotePeer
 	privKey := t.identity.PrivateKey()
 	
 	// 执行 Noise XX 握手（客户端）
-	secConn, err := performHandshake(conn, privKey, remotePeer, true)
+	secConn, err := performHandshake(conn, privKey, remotePeer, true, t.hybrid)
 	if err != nil {
 		logger.Warn("Noise 握手失败", "remotePeer", remotePeerLabel, "error", err)
 		return nil, fmt.Errorf("handshake failed: %w", err)
@@ -28,6 +28,14 @@ func TestTransport_ID(t *testing.T) {
 	assert.Equal(t, types.ProtocolID("/noise/1.0.0"), transport.ID())
 }
 
+func TestTransport_NewHybrid(t *testing.T) {
+	id, _ := identity.Generate()
+	transport, err := NewHybrid(id)
+	require.NoError(t, err)
+
+	assert.Equal(t, types.ProtocolID("/noise-pq/1.0.0"), transport.ID())
+}
+
 func TestTransport_New_NilIdentity(t *testing.T) {
 	transport, err := New(nil)
 	assert.Error(t, err)
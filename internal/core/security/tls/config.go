@@ -32,6 +32,9 @@ type Config struct {
 	
 	// RequireClientCert 服务端是否要求客户端证书
 	RequireClientCert bool
+
+	// PostQuantum 是否启用 X25519MLKEM768 混合密钥交换（通过 ALPN 协商）
+	PostQuantum bool
 }
 
 // NewFromIdentity 从 Identity 创建 TLS 配置
@@ -62,7 +65,7 @@ func NewFromIdentity(identity pkgif.Identity) (*Config, error) {
 		Identity:          identity,
 		Certificate:       cert,
 		ServerName:        "dep2p",
-		NextProtos:        []string{"dep2p"},
+		NextProtos:        []string{ALPN},
 		MinVersion:        tls.VersionTLS13, // 强制 TLS 1.3
 		RequireClientCert: true,             // 双向认证
 	}, nil
@@ -75,7 +78,7 @@ func NewFromIdentity(identity pkgif.Identity) (*Config, error) {
 func DefaultConfig() *Config {
 	return &Config{
 		ServerName:        "dep2p",
-		NextProtos:        []string{"dep2p"},
+		NextProtos:        []string{ALPN},
 		MinVersion:        tls.VersionTLS13,
 		RequireClientCert: true,
 	}
@@ -112,18 +115,23 @@ func (c *Config) ServerConfig(remotePeer types.PeerID) *tls.Config {
 		clientAuth = tls.RequireAnyClientCert
 	}
 	
-	return &tls.Config{
+	config := &tls.Config{
 		Certificates:       []tls.Certificate{*c.Certificate},
 		ClientAuth:         clientAuth,
 		MinVersion:         c.MinVersion,
 		InsecureSkipVerify: true, //nolint:gosec // G402: P2P 使用自定义 PeerID 验证替代 CA 验证
 		NextProtos:         c.NextProtos,
+		CurvePreferences:   curvePreferences(false),
 		
 		// 自定义证书验证（INV-001）
 		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
 			return VerifyPeerCertificate(rawCerts, remotePeer)
 		},
 	}
+	if c.PostQuantum {
+		enableHybridServer(config)
+	}
+	return config
 }
 
 // ClientConfig 生成客户端 TLS 配置
@@ -136,18 +144,23 @@ func (c *Config) ServerConfig(remotePeer types.PeerID) *tls.Config {
 // 返回：
 //   - *tls.Config: Go 标准库的 TLS 配置
 func (c *Config) ClientConfig(remotePeer types.PeerID) *tls.Config {
-	return &tls.Config{
+	config := &tls.Config{
 		Certificates:       []tls.Certificate{*c.Certificate},
 		MinVersion:         c.MinVersion,
 		InsecureSkipVerify: true, //nolint:gosec // G402: P2P 使用自定义 PeerID 验证替代 CA 验证
 		ServerName:         c.ServerName,
-		NextProtos:         c.NextProtos,
+		NextProtos:         append([]string{}, c.NextProtos...),
+		CurvePreferences:   curvePreferences(false),
 		
 		// 自定义证书验证（INV-001）
 		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
 			return VerifyPeerCertificate(rawCerts, remotePeer)
 		},
 	}
+	if c.PostQuantum {
+		enableHybridClient(config)
+	}
+	return config
 }
 
 // Clone 克隆配置
@@ -159,5 +172,6 @@ func (c *Config) Clone() *Config {
 		NextProtos:        append([]string{}, c.NextProtos...),
 		MinVersion:        c.MinVersion,
 		RequireClientCert: c.RequireClientCert,
+		PostQuantum:       c.PostQuantum,
 	}
 }
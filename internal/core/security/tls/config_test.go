@@ -2,9 +2,11 @@ package tls
 
 import (
 	"crypto/tls"
+	"net"
 	"testing"
 
 	"github.com/dep2p/go-dep2p/internal/core/identity"
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
 	"github.com/dep2p/go-dep2p/pkg/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -203,3 +205,52 @@ func TestConfig_Clone(t *testing.T) {
 
 	t.Log("✅ Clone 配置克隆成功")
 }
+
+// TestConfig_PostQuantumALPN 测试混合密钥交换的 ALPN 协商与回退
+func TestConfig_PostQuantumALPN(t *testing.T) {
+	newConfig := func(postQuantum bool) *Config {
+		id, err := identity.Generate()
+		require.NoError(t, err)
+		cfg, err := NewFromIdentity(id)
+		require.NoError(t, err)
+		cfg.PostQuantum = postQuantum
+		return cfg
+	}
+
+	tests := []struct {
+		name   string
+		client bool
+		server bool
+		alpn   string
+	}{
+		{"both hybrid", true, true, HybridALPN},
+		{"classical server", true, false, ALPN},
+		{"classical client", false, true, ALPN},
+		{"both classical", false, false, ALPN},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientConn, serverConn := net.Pipe()
+			defer clientConn.Close()
+			defer serverConn.Close()
+
+			server := tls.Server(serverConn, newConfig(tt.server).ServerConfig(""))
+			client := tls.Client(clientConn, newConfig(tt.client).ClientConfig(""))
+			errCh := make(chan error, 1)
+			go func() { errCh <- server.Handshake() }()
+			require.NoError(t, client.Handshake())
+			require.NoError(t, <-errCh)
+
+			proto := client.ConnectionState().NegotiatedProtocol
+			assert.Equal(t, tt.alpn, proto)
+			assert.Equal(t, proto, server.ConnectionState().NegotiatedProtocol)
+			if tt.client && tt.server {
+				assert.Equal(t, HybridProtocolID, ProtocolForALPN(proto))
+				assert.Equal(t, pkgif.KeyExchangeX25519MLKEM768, KeyExchangeForALPN(proto))
+			} else {
+				assert.Equal(t, ProtocolID, ProtocolForALPN(proto))
+				assert.Equal(t, pkgif.KeyExchangeX25519, KeyExchangeForALPN(proto))
+			}
+		})
+	}
+}
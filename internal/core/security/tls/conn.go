@@ -15,6 +15,8 @@ var _ pkgif.SecureConn = (*secureConn)(nil)
 type secureConn struct {
 	*tls.Conn // 嵌入 TLS 连接
 
+	protocol     types.ProtocolID
+	keyExchange  string
 	localPeer    types.PeerID
 	remotePeer   types.PeerID
 	localPubKey  []byte
@@ -24,11 +26,15 @@ type secureConn struct {
 // newSecureConn 创建安全连接
 func newSecureConn(
 	tlsConn *tls.Conn,
+	protocol types.ProtocolID,
+	keyExchange string,
 	localPeer, remotePeer types.PeerID,
 	localPubKey, remotePubKey []byte,
 ) *secureConn {
 	return &secureConn{
 		Conn:         tlsConn,
+		protocol:     protocol,
+		keyExchange:  keyExchange,
 		localPeer:    localPeer,
 		remotePeer:   remotePeer,
 		localPubKey:  localPubKey,
@@ -59,11 +65,12 @@ func (c *secureConn) RemotePublicKey() []byte {
 // ConnState 返回连接状态
 func (c *secureConn) ConnState() pkgif.SecureConnState {
 	return pkgif.SecureConnState{
-		Protocol:        types.ProtocolID("/tls/1.0.0"),
+		Protocol:        c.protocol,
 		LocalPeer:       c.localPeer,
 		RemotePeer:      c.remotePeer,
 		LocalPublicKey:  c.localPubKey,  // []byte
 		RemotePublicKey: c.remotePubKey, // []byte
 		Opened:          true,            // 握手已完成
+		KeyExchange:     c.keyExchange,
 	}
 }
@@ -0,0 +1,90 @@
+// Package tls 实现 TLS 1.3 安全传输
+package tls
+
+import (
+	"crypto/tls"
+	"slices"
+
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/types"
+)
+
+// 协议标识
+const (
+	// ProtocolID 经典 TLS 1.3（X25519）
+	ProtocolID = types.ProtocolID("/tls/1.0.0")
+
+	// HybridProtocolID 混合后量子 TLS 1.3（X25519MLKEM768）
+	HybridProtocolID = types.ProtocolID("/tls-pq/1.0.0")
+
+	// ALPN 经典握手的 ALPN 标识
+	ALPN = "dep2p"
+
+	// HybridALPN 混合握手的 ALPN 标识
+	//
+	// QUIC 不经过 multistream 安全协商，通过 ALPN 区分混合握手。
+	HybridALPN = "dep2p-pq"
+)
+
+// curvePreferences 返回密钥交换曲线偏好
+//
+// 经典模式固定为 X25519，混合模式只接受 X25519MLKEM768，
+// 这样握手结果由协商出的协议唯一确定，可以如实反映到连接统计中。
+func curvePreferences(hybrid bool) []tls.CurveID {
+	if hybrid {
+		return []tls.CurveID{tls.X25519MLKEM768}
+	}
+	return []tls.CurveID{tls.X25519}
+}
+
+// keyExchange 返回协议对应的密钥交换算法
+func keyExchange(hybrid bool) string {
+	if hybrid {
+		return pkgif.KeyExchangeX25519MLKEM768
+	}
+	return pkgif.KeyExchangeX25519
+}
+
+// KeyExchangeForALPN 返回 ALPN 协商结果对应的密钥交换算法
+func KeyExchangeForALPN(proto string) string {
+	return keyExchange(proto == HybridALPN)
+}
+
+// ProtocolForALPN 返回 ALPN 协商结果对应的安全协议
+func ProtocolForALPN(proto string) types.ProtocolID {
+	if proto == HybridALPN {
+		return HybridProtocolID
+	}
+	return ProtocolID
+}
+
+// offersHybrid 客户端是否同时提供混合 ALPN 和 X25519MLKEM768
+func offersHybrid(hello *tls.ClientHelloInfo) bool {
+	return slices.Contains(hello.SupportedProtos, HybridALPN) &&
+		slices.Contains(hello.SupportedCurves, tls.X25519MLKEM768)
+}
+
+// enableHybridServer 为服务端配置启用混合握手
+//
+// 客户端提供混合 ALPN 和 X25519MLKEM768 时只使用混合曲线并选择
+// HybridALPN，否则沿用经典配置，经典客户端不受影响。
+func enableHybridServer(config *tls.Config) {
+	hybrid := config.Clone()
+	hybrid.NextProtos = []string{HybridALPN}
+	hybrid.CurvePreferences = curvePreferences(true)
+
+	config.GetConfigForClient = func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+		if offersHybrid(hello) {
+			return hybrid, nil
+		}
+		return nil, nil
+	}
+}
+
+// enableHybridClient 为客户端配置启用混合握手
+//
+// 优先提议混合 ALPN，并同时提供两种曲线，以便回退到经典服务端。
+func enableHybridClient(config *tls.Config) {
+	config.NextProtos = append([]string{HybridALPN}, config.NextProtos...)
+	config.CurvePreferences = []tls.CurveID{tls.X25519MLKEM768, tls.X25519}
+}
@@ -26,6 +26,7 @@ type Transport struct {
 	identity      pkgif.Identity
 	cert          *tls.Certificate
 	accessControl *AccessControl // 可选的访问控制
+	hybrid        bool           // 是否使用 X25519MLKEM768 混合密钥交换
 }
 
 // New 创建 TLS 传输
@@ -53,6 +54,19 @@ func New(identity pkgif.Identity) (*Transport, error) {
 	}, nil
 }
 
+// NewHybrid 创建混合后量子 TLS 传输
+//
+// 握手只接受 X25519MLKEM768 密钥交换，协议标识为 HybridProtocolID。
+// 与经典 TLS 并列注册到安全协商中，由 multistream 决定使用哪一个。
+func NewHybrid(identity pkgif.Identity) (*Transport, error) {
+	t, err := New(identity)
+	if err != nil {
+		return nil, err
+	}
+	t.hybrid = true
+	return t, nil
+}
+
 // SetAccessControl 设置访问控制
 //
 // 设置后，握手前会检查远程节点是否被允许连接
@@ -67,7 +81,10 @@ func (t *Transport) GetAccessControl() *AccessControl {
 
 // ID 返回协议标识
 func (t *Transport) ID() types.ProtocolID {
-	return types.ProtocolID("/tls/1.0.0")
+	if t.hybrid {
+		return HybridProtocolID
+	}
+	return ProtocolID
 }
 
 // SecureInbound 保护入站连接（服务器端握手）
@@ -114,7 +131,8 @@ func (t *Transport) SecureInbound(ctx context.Context, conn net.Conn, remotePeer
 			return VerifyPeerCertificate(rawCerts, remotePeer)
 		},
 
-		NextProtos: []string{"dep2p"}, // ALPN
+		NextProtos:       []string{ALPN},             // ALPN
+		CurvePreferences: curvePreferences(t.hybrid), // 密钥交换曲线
 	}
 
 	remotePeerLabel := string(remotePeer)
@@ -181,7 +199,7 @@ func (t *Transport) SecureInbound(ctx context.Context, conn net.Conn, remotePeer
 		remotePeerLabel = remotePeerLabel[:8]
 	}
 	logger.Debug("TLS 握手成功", "remotePeer", remotePeerLabel)
-	return newSecureConn(tlsConn, localPeer, remotePeer, localPubKeyBytes, remotePubKeyBytes), nil
+	return newSecureConn(tlsConn, t.ID(), keyExchange(t.hybrid), localPeer, remotePeer, localPubKeyBytes, remotePubKeyBytes), nil
 }
 
 // SecureOutbound 保护出站连接（客户端握手）
@@ -228,7 +246,8 @@ func (t *Transport) SecureOutbound(ctx context.Context, conn net.Conn, remotePee
 			return VerifyPeerCertificate(rawCerts, remotePeer)
 		},
 
-		NextProtos: []string{"dep2p"}, // ALPN
+		NextProtos:       []string{ALPN},             // ALPN
+		CurvePreferences: curvePreferences(t.hybrid), // 密钥交换曲线
 	}
 
 	remotePeerLabel := string(remotePeer)
@@ -285,5 +304,5 @@ func (t *Transport) SecureOutbound(ctx context.Context, conn net.Conn, remotePee
 		remotePeerLabel = remotePeerLabel[:8]
 	}
 	logger.Debug("TLS 握手成功", "remotePeer", remotePeerLabel)
-	return newSecureConn(tlsConn, localPeer, remotePeer, localPubKeyBytes, remotePubKeyBytes), nil
+	return newSecureConn(tlsConn, t.ID(), keyExchange(t.hybrid), localPeer, remotePeer, localPubKeyBytes, remotePubKeyBytes), nil
 }
@@ -0,0 +1,250 @@
+// Package bandwidth 提供带宽统计模块的实现
+package bandwidth
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/log"
+)
+
+var logger = log.Logger("core/bandwidth")
+
+// ============================================================================
+//                              数据预算
+// ============================================================================
+
+// 后台任务间隔的放大倍数
+const (
+	// meteredScale 计费网络（预算充足或未设预算）
+	meteredScale = 4
+
+	// lowBudgetScale 今日已用超过 lowBudgetRatio
+	lowBudgetScale = 16
+
+	// exhaustedScale 今日预算已用完
+	exhaustedScale = 64
+
+	// lowBudgetRatio 预算紧张的使用比例
+	lowBudgetRatio = 0.8
+)
+
+// hintTTL 系统计费提示的缓存时间
+const hintTTL = time.Minute
+
+// taskKey 后台任务标识
+type taskKey struct {
+	subsystem string
+	task      string
+}
+
+// throttleEntry 子系统节流记录
+type throttleEntry struct {
+	skipped     uint64
+	lastSkipped time.Time
+}
+
+// Budget 数据预算实现
+//
+// 实现 interfaces.DataBudget 接口。
+//
+// 今日用量取自带宽计数器的总流量（入+出），按本地时间零点滚动。
+// 计费网络下后台任务的间隔按用量放大：预算充足 4 倍，超过 80% 16 倍，
+// 用完 64 倍。后台任务不会完全停止，以保持最低限度的连通性。
+type Budget struct {
+	mu sync.Mutex
+
+	counter interfaces.BandwidthCounter // 可为 nil（用量始终为 0）
+
+	mode        interfaces.DataBudgetMode
+	dailyBudget int64
+
+	// 今日用量基线
+	dayStart time.Time
+	baseline int64
+
+	// 系统计费提示（DataBudgetAuto 时使用）
+	hint       func() bool
+	hintValue  bool
+	hintExpiry time.Time
+
+	lastScale int
+	lastRun   map[taskKey]time.Time
+	entries   map[string]*throttleEntry
+}
+
+// 确保实现接口
+var _ interfaces.DataBudget = (*Budget)(nil)
+
+// NewBudget 创建数据预算
+func NewBudget(counter interfaces.BandwidthCounter, cfg interfaces.DataBudgetConfig) *Budget {
+	now := time.Now()
+	b := &Budget{
+		counter:     counter,
+		mode:        cfg.Mode,
+		dailyBudget: cfg.DailyBudget,
+		dayStart:    startOfDay(now),
+		hint:        detectMetered,
+		lastScale:   1,
+		lastRun:     make(map[taskKey]time.Time),
+		entries:     make(map[string]*throttleEntry),
+	}
+	b.baseline = b.totalBytes()
+	return b
+}
+
+// ==================== 节流 ====================
+
+// Allow 报告子系统的后台任务本次是否执行
+//
+// 后台任务按固定间隔触发，节流时只执行每 scale 次中的一次。
+// 判断留出半个间隔的余量，避免定时器抖动导致多跳过一次。
+func (b *Budget) Allow(subsystem string, task string, interval time.Duration) bool {
+	now := time.Now()
+	key := taskKey{subsystem: subsystem, task: task}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	scale := b.scaleLocked(now)
+	if last, ok := b.lastRun[key]; ok && scale > 1 {
+		next := time.Duration(scale-1)*interval + interval/2
+		if now.Sub(last) < next {
+			e := b.entries[subsystem]
+			if e == nil {
+				e = &throttleEntry{}
+				b.entries[subsystem] = e
+			}
+			e.skipped++
+			e.lastSkipped = now
+			return false
+		}
+	}
+	b.lastRun[key] = now
+	return true
+}
+
+// scaleLocked 计算当前的间隔倍数（调用方持有 mu）
+func (b *Budget) scaleLocked(now time.Time) int {
+	scale := 1
+	if b.meteredLocked(now) {
+		scale = meteredScale
+		if b.dailyBudget > 0 {
+			used := b.usedLocked(now)
+			switch {
+			case used >= b.dailyBudget:
+				scale = exhaustedScale
+			case float64(used) >= float64(b.dailyBudget)*lowBudgetRatio:
+				scale = lowBudgetScale
+			}
+		}
+	}
+
+	if scale != b.lastScale {
+		logger.Info("后台流量节流级别变化",
+			"from", b.lastScale,
+			"to", scale,
+			"mode", b.mode.String(),
+			"dailyBudget", b.dailyBudget)
+		b.lastScale = scale
+	}
+	return scale
+}
+
+// meteredLocked 判断当前是否按计费网络处理（调用方持有 mu）
+func (b *Budget) meteredLocked(now time.Time) bool {
+	switch b.mode {
+	case interfaces.DataBudgetMetered:
+		return true
+	case interfaces.DataBudgetUnmetered:
+		return false
+	}
+
+	if now.After(b.hintExpiry) {
+		b.hintValue = b.hint != nil && b.hint()
+		b.hintExpiry = now.Add(hintTTL)
+	}
+	return b.hintValue
+}
+
+// usedLocked 返回今日已用流量（调用方持有 mu）
+func (b *Budget) usedLocked(now time.Time) int64 {
+	total := b.totalBytes()
+
+	// 跨天时以当前总量为新基线
+	if day := startOfDay(now); day.After(b.dayStart) {
+		b.dayStart = day
+		b.baseline = total
+	}
+	// 计数器被重置
+	if total < b.baseline {
+		b.baseline = 0
+	}
+	return total - b.baseline
+}
+
+// totalBytes 返回计数器的总流量
+func (b *Budget) totalBytes() int64 {
+	if b.counter == nil {
+		return 0
+	}
+	return b.counter.GetTotals().TotalBytes()
+}
+
+// startOfDay 返回本地时间零点
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// ==================== 运行时调整 ====================
+
+// SetMode 设置计费网络模式
+func (b *Budget) SetMode(mode interfaces.DataBudgetMode) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.mode = mode
+	b.hintExpiry = time.Time{}
+}
+
+// SetDailyBudget 设置每日流量预算
+func (b *Budget) SetDailyBudget(bytes int64) {
+	if bytes < 0 {
+		bytes = 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.dailyBudget = bytes
+}
+
+// ==================== 状态 ====================
+
+// Status 返回数据预算状态
+func (b *Budget) Status() interfaces.DataBudgetStatus {
+	now := time.Now()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := interfaces.DataBudgetStatus{
+		Mode:        b.mode,
+		Metered:     b.meteredLocked(now),
+		DailyBudget: b.dailyBudget,
+		UsedToday:   b.usedLocked(now),
+		Scale:       b.scaleLocked(now),
+	}
+
+	for subsystem, e := range b.entries {
+		status.Throttled = append(status.Throttled, interfaces.DataBudgetThrottle{
+			Subsystem:   subsystem,
+			Skipped:     e.skipped,
+			LastSkipped: e.lastSkipped,
+		})
+	}
+	sort.Slice(status.Throttled, func(i, j int) bool {
+		return status.Throttled[i].Subsystem < status.Throttled[j].Subsystem
+	})
+	return status
+}
@@ -0,0 +1,168 @@
+// Package bandwidth 提供带宽统计模块的实现
+package bandwidth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dep2p/go-dep2p/pkg/interfaces"
+)
+
+// newTestBudget 创建不依赖系统提示的数据预算
+func newTestBudget(counter interfaces.BandwidthCounter, cfg interfaces.DataBudgetConfig) *Budget {
+	b := NewBudget(counter, cfg)
+	b.hint = func() bool { return false }
+	return b
+}
+
+// runTicks 以 interval 为间隔模拟 n 次定时触发，返回执行次数
+func runTicks(b *Budget, subsystem, task string, interval time.Duration, n int) int {
+	ran := 0
+	for i := 0; i < n; i++ {
+		// 回拨上次执行时间，模拟经过了一个间隔
+		b.mu.Lock()
+		key := taskKey{subsystem: subsystem, task: task}
+		if last, ok := b.lastRun[key]; ok {
+			b.lastRun[key] = last.Add(-interval)
+		}
+		b.mu.Unlock()
+
+		if b.Allow(subsystem, task, interval) {
+			ran++
+		}
+	}
+	return ran
+}
+
+// TestBudget_Unmetered 测试非计费网络不节流
+func TestBudget_Unmetered(t *testing.T) {
+	b := newTestBudget(nil, interfaces.DataBudgetConfig{})
+
+	for i := 0; i < 10; i++ {
+		if !b.Allow(interfaces.BudgetSubsystemDHT, "refresh", time.Hour) {
+			t.Fatal("Allow() = false on unmetered network")
+		}
+	}
+
+	status := b.Status()
+	if status.Metered || status.Scale != 1 || len(status.Throttled) != 0 {
+		t.Errorf("Status() = %+v", status)
+	}
+}
+
+// TestBudget_Metered 测试计费网络按倍数跳过
+func TestBudget_Metered(t *testing.T) {
+	b := newTestBudget(nil, interfaces.DataBudgetConfig{Mode: interfaces.DataBudgetMetered})
+
+	ran := runTicks(b, interfaces.BudgetSubsystemPubSub, "mesh", time.Second, 4*meteredScale)
+	if ran != 4 {
+		t.Errorf("ran %d of %d ticks, want 4", ran, 4*meteredScale)
+	}
+
+	status := b.Status()
+	if !status.Metered || status.Scale != meteredScale {
+		t.Errorf("Status() = %+v", status)
+	}
+	if len(status.Throttled) != 1 ||
+		status.Throttled[0].Subsystem != interfaces.BudgetSubsystemPubSub ||
+		status.Throttled[0].Skipped != uint64(4*meteredScale-4) {
+		t.Errorf("Throttled = %+v", status.Throttled)
+	}
+}
+
+// TestBudget_TasksIndependent 测试同一子系统的不同任务互不影响
+func TestBudget_TasksIndependent(t *testing.T) {
+	b := newTestBudget(nil, interfaces.DataBudgetConfig{Mode: interfaces.DataBudgetMetered})
+
+	// 频繁的任务不应占用低频任务的执行机会
+	runTicks(b, interfaces.BudgetSubsystemDHT, "address_check", time.Minute, 10)
+	if !b.Allow(interfaces.BudgetSubsystemDHT, "refresh", time.Hour) {
+		t.Error("first refresh should run")
+	}
+}
+
+// TestBudget_DailyBudget 测试按今日用量提高节流倍数
+func TestBudget_DailyBudget(t *testing.T) {
+	counter := NewCounter(interfaces.DefaultBandwidthConfig())
+	counter.LogSentMessage(5000) // 创建前的流量不计入今日用量
+
+	b := newTestBudget(counter, interfaces.DataBudgetConfig{
+		Mode:        interfaces.DataBudgetMetered,
+		DailyBudget: 1000,
+	})
+
+	tests := []struct {
+		sent  int64
+		used  int64
+		scale int
+	}{
+		{100, 100, meteredScale},
+		{750, 850, lowBudgetScale},
+		{200, 1050, exhaustedScale},
+	}
+	for _, tt := range tests {
+		counter.LogSentMessage(tt.sent)
+		status := b.Status()
+		if status.UsedToday != tt.used || status.Scale != tt.scale {
+			t.Errorf("used %d: Status() = {UsedToday: %d, Scale: %d}, want {%d, %d}",
+				tt.used, status.UsedToday, status.Scale, tt.used, tt.scale)
+		}
+	}
+
+	// 提高预算后恢复
+	b.SetDailyBudget(1 << 20)
+	if scale := b.Status().Scale; scale != meteredScale {
+		t.Errorf("Scale after raising budget = %d, want %d", scale, meteredScale)
+	}
+
+	// 关闭省流模式
+	b.SetMode(interfaces.DataBudgetUnmetered)
+	if scale := b.Status().Scale; scale != 1 {
+		t.Errorf("Scale when unmetered = %d, want 1", scale)
+	}
+}
+
+// TestBudget_DayRollover 测试跨天重置用量
+func TestBudget_DayRollover(t *testing.T) {
+	counter := NewCounter(interfaces.DefaultBandwidthConfig())
+	b := newTestBudget(counter, interfaces.DataBudgetConfig{DailyBudget: 1000})
+
+	counter.LogSentMessage(800)
+	if used := b.Status().UsedToday; used != 800 {
+		t.Fatalf("UsedToday = %d, want 800", used)
+	}
+
+	b.mu.Lock()
+	b.dayStart = b.dayStart.Add(-24 * time.Hour)
+	b.mu.Unlock()
+
+	if used := b.Status().UsedToday; used != 0 {
+		t.Errorf("UsedToday after rollover = %d, want 0", used)
+	}
+
+	// 计数器重置后不出现负数
+	counter.Reset()
+	if used := b.Status().UsedToday; used != 0 {
+		t.Errorf("UsedToday after counter reset = %d, want 0", used)
+	}
+}
+
+// TestBudget_AutoHint 测试自动模式使用系统提示
+func TestBudget_AutoHint(t *testing.T) {
+	b := newTestBudget(nil, interfaces.DataBudgetConfig{})
+	b.hint = func() bool { return true }
+
+	if status := b.Status(); !status.Metered || status.Scale != meteredScale {
+		t.Errorf("Status() = %+v, want metered", status)
+	}
+
+	// 提示结果被缓存，切换模式时重新检测
+	b.hint = func() bool { return false }
+	if !b.Status().Metered {
+		t.Error("hint should be cached")
+	}
+	b.SetMode(interfaces.DataBudgetAuto)
+	if b.Status().Metered {
+		t.Error("SetMode should refresh hint")
+	}
+}
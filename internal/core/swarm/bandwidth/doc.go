@@ -82,6 +82,19 @@
 //	}
 //	counter := bandwidth.NewCounter(cfg)
 //
+// # 数据预算
+//
+// Budget 面向计费网络（移动、卫星），按每日流量预算放慢后台任务。
+// 各子系统的后台循环在每次触发时调用 Allow，节流时只执行每 N 次中的一次：
+//
+//	if budget.Allow(interfaces.BudgetSubsystemDHT, "refresh", interval) {
+//	    refresh()
+//	}
+//
+// 节流倍数：非计费网络 1；计费网络 4；今日用量超过 80% 16；用完 64。
+// DataBudgetAuto 模式下根据默认路由所在接口判断是否为计费网络
+// （Linux/Android 的 wwan、rmnet、ppp 等），其他平台需显式设置。
+//
 // # 架构
 //
 // bandwidth 依赖：
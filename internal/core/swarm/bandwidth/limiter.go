@@ -0,0 +1,369 @@
+// Package bandwidth 提供带宽统计模块的实现
+package bandwidth
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/protocol"
+)
+
+// ============================================================================
+//                              带宽限速器
+// ============================================================================
+
+// 限速范围
+const (
+	ScopeTotal    = "total"
+	ScopePeer     = "peer"
+	ScopeProtocol = "protocol"
+	ScopeRealm    = "realm"
+)
+
+// bucket 单个范围的上传/下载令牌桶
+type bucket struct {
+	in  *rate.Limiter // nil 表示下载不限制
+	out *rate.Limiter // nil 表示上传不限制
+
+	limit interfaces.BandwidthLimit
+
+	throttled atomic.Uint64
+	waited    atomic.Int64
+	lastUsed  atomic.Int64
+}
+
+// newBucket 创建令牌桶
+func newBucket(limit interfaces.BandwidthLimit) *bucket {
+	b := &bucket{}
+	b.set(limit)
+	b.lastUsed.Store(time.Now().UnixNano())
+	return b
+}
+
+// set 更新限速，已有的令牌桶原地调整（调用方持有 Limiter.mu 写锁）
+func (b *bucket) set(limit interfaces.BandwidthLimit) {
+	b.limit = limit
+	b.in = adjust(b.in, limit.In, limit.Burst)
+	b.out = adjust(b.out, limit.Out, limit.Burst)
+}
+
+// adjust 按速率创建或调整令牌桶，速率为 0 时返回 nil
+func adjust(l *rate.Limiter, bytesPerSec, burst int64) *rate.Limiter {
+	if bytesPerSec <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = bytesPerSec
+	}
+	if burst > math.MaxInt32 {
+		burst = math.MaxInt32
+	}
+	if l == nil {
+		return rate.NewLimiter(rate.Limit(bytesPerSec), int(burst))
+	}
+	l.SetLimit(rate.Limit(bytesPerSec))
+	l.SetBurst(int(burst))
+	return l
+}
+
+// limiter 返回指定方向的令牌桶（调用方持有 Limiter.mu）
+func (b *bucket) limiter(dir interfaces.Direction) *rate.Limiter {
+	if dir == interfaces.DirInbound {
+		return b.in
+	}
+	return b.out
+}
+
+// Limiter 带宽限速器实现
+//
+// 实现 interfaces.BandwidthLimiter 接口。
+//
+// 每个范围是一对令牌桶（上传/下载），一次读写在所有适用的桶上预约令牌，
+// 等待其中最长的延迟。令牌按预约顺序发放，竞争同一个桶的流按分片轮流获得配额，
+// 不会出现某个大流独占带宽；各节点有独立的桶，保证节点之间公平。
+type Limiter struct {
+	mu sync.RWMutex
+
+	limits interfaces.BandwidthLimits
+
+	total     *bucket
+	peers     map[string]*bucket // 节点限速（指定节点或 PerPeer 派生）
+	protocols map[string]*bucket
+	realms    map[string]*bucket
+}
+
+// 确保实现接口
+var _ interfaces.BandwidthLimiter = (*Limiter)(nil)
+
+// NewLimiter 创建带宽限速器
+func NewLimiter(limits interfaces.BandwidthLimits) *Limiter {
+	l := &Limiter{
+		total:     newBucket(interfaces.BandwidthLimit{}),
+		peers:     make(map[string]*bucket),
+		protocols: make(map[string]*bucket),
+		realms:    make(map[string]*bucket),
+	}
+	l.SetLimits(limits)
+	return l
+}
+
+// ==================== 配额 ====================
+
+// WaitN 等待 dir 方向 size 字节的配额
+//
+// size 超过桶容量时分多次预约。ctx 取消时归还未使用的预约。
+func (l *Limiter) WaitN(ctx context.Context, dir interfaces.Direction, size int, proto string, peer string) error {
+	if size <= 0 {
+		return nil
+	}
+
+	slots := l.slots(dir, proto, peer)
+	if len(slots) == 0 {
+		return nil
+	}
+
+	// 单次预约不能超过最小的桶容量
+	chunk := size
+	for _, s := range slots {
+		if burst := s.lim.Burst(); burst < chunk {
+			chunk = burst
+		}
+	}
+
+	for size > 0 {
+		n := min(chunk, size)
+		if err := wait(ctx, slots, n); err != nil {
+			return err
+		}
+		size -= n
+	}
+	return nil
+}
+
+// slot 本次读写适用的令牌桶
+type slot struct {
+	b   *bucket
+	lim *rate.Limiter
+}
+
+// wait 在所有桶上预约 n 个令牌并等待
+func wait(ctx context.Context, slots []slot, n int) error {
+	now := time.Now()
+	reservations := make([]*rate.Reservation, 0, len(slots))
+	var delay time.Duration
+	for _, s := range slots {
+		r := s.lim.ReserveN(now, n)
+		reservations = append(reservations, r)
+		if d := r.DelayFrom(now); d > delay {
+			delay = d
+		}
+		s.b.lastUsed.Store(now.UnixNano())
+	}
+	if delay == 0 {
+		return nil
+	}
+
+	// 记录令牌不足的桶
+	for _, s := range slots {
+		if s.lim.TokensAt(now) < 0 {
+			s.b.throttled.Add(1)
+			s.b.waited.Add(int64(delay))
+		}
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		for _, r := range reservations {
+			r.CancelAt(now)
+		}
+		return ctx.Err()
+	}
+}
+
+// slots 返回适用于本次读写的令牌桶
+func (l *Limiter) slots(dir interfaces.Direction, proto string, peer string) []slot {
+	l.mu.RLock()
+	peerBucket := l.peers[peer]
+	l.mu.RUnlock()
+
+	// PerPeer 派生的节点桶按需创建
+	if peerBucket == nil && peer != "" {
+		l.mu.Lock()
+		if peerBucket = l.peers[peer]; peerBucket == nil && !l.limits.PerPeer.IsZero() {
+			peerBucket = newBucket(l.limits.PerPeer)
+			l.peers[peer] = peerBucket
+		}
+		l.mu.Unlock()
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	candidates := [4]*bucket{l.total, l.protocols[proto], nil, peerBucket}
+	if realm := protocol.ExtractRealmID(protocol.ID(proto)); realm != "" {
+		candidates[2] = l.realms[realm]
+	}
+
+	out := make([]slot, 0, len(candidates))
+	for _, b := range candidates {
+		if b == nil {
+			continue
+		}
+		if lim := b.limiter(dir); lim != nil {
+			out = append(out, slot{b: b, lim: lim})
+		}
+	}
+	return out
+}
+
+// ==================== 运行时调整 ====================
+
+// SetLimits 替换全部限速配置
+//
+// 已有的令牌桶原地调整速率，正在等待的读写按新速率继续。
+func (l *Limiter) SetLimits(limits interfaces.BandwidthLimits) {
+	limits = cloneLimits(limits)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.limits = limits
+	l.total.set(limits.Total)
+
+	// 节点：指定节点优先，其余已创建的节点桶跟随 PerPeer
+	for peer, b := range l.peers {
+		if limit, ok := limits.Peers[peer]; ok {
+			b.set(limit)
+		} else if !limits.PerPeer.IsZero() {
+			b.set(limits.PerPeer)
+		} else {
+			delete(l.peers, peer)
+		}
+	}
+	for peer, limit := range limits.Peers {
+		if _, ok := l.peers[peer]; !ok {
+			l.peers[peer] = newBucket(limit)
+		}
+	}
+
+	syncBuckets(l.protocols, limits.Protocols)
+	syncBuckets(l.realms, limits.Realms)
+}
+
+// syncBuckets 按配置更新、创建或删除令牌桶
+func syncBuckets(buckets map[string]*bucket, limits map[string]interfaces.BandwidthLimit) {
+	for key, b := range buckets {
+		limit, ok := limits[key]
+		if !ok {
+			delete(buckets, key)
+			continue
+		}
+		b.set(limit)
+	}
+	for key, limit := range limits {
+		if _, ok := buckets[key]; !ok {
+			buckets[key] = newBucket(limit)
+		}
+	}
+}
+
+// Limits 返回当前限速配置
+func (l *Limiter) Limits() interfaces.BandwidthLimits {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return cloneLimits(l.limits)
+}
+
+// cloneLimits 复制限速配置（map 不与调用方共享）
+func cloneLimits(limits interfaces.BandwidthLimits) interfaces.BandwidthLimits {
+	clone := func(m map[string]interfaces.BandwidthLimit) map[string]interfaces.BandwidthLimit {
+		if len(m) == 0 {
+			return nil
+		}
+		out := make(map[string]interfaces.BandwidthLimit, len(m))
+		for k, v := range m {
+			out[k] = v
+		}
+		return out
+	}
+	limits.Peers = clone(limits.Peers)
+	limits.Protocols = clone(limits.Protocols)
+	limits.Realms = clone(limits.Realms)
+	return limits
+}
+
+// ==================== 状态 ====================
+
+// Status 返回各限速范围的状态
+//
+// 只包含设置了限速的范围，按范围和键排序。
+func (l *Limiter) Status() []interfaces.BandwidthLimitStatus {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var status []interfaces.BandwidthLimitStatus
+	add := func(scope string, key string, b *bucket) {
+		if b.limit.IsZero() {
+			return
+		}
+		status = append(status, interfaces.BandwidthLimitStatus{
+			Scope:     scope,
+			Key:       key,
+			Limit:     b.limit,
+			Throttled: b.throttled.Load(),
+			Waited:    time.Duration(b.waited.Load()),
+		})
+	}
+
+	add(ScopeTotal, "", l.total)
+	for _, key := range sortedKeys(l.peers) {
+		add(ScopePeer, key, l.peers[key])
+	}
+	for _, key := range sortedKeys(l.protocols) {
+		add(ScopeProtocol, key, l.protocols[key])
+	}
+	for _, key := range sortedKeys(l.realms) {
+		add(ScopeRealm, key, l.realms[key])
+	}
+	return status
+}
+
+// sortedKeys 返回排序后的键
+func sortedKeys(m map[string]*bucket) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ==================== 管理 ====================
+
+// TrimIdle 清理空闲的节点限速桶
+//
+// 只清理由 PerPeer 派生的桶，指定节点的桶保留。
+func (l *Limiter) TrimIdle(since time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for peer, b := range l.peers {
+		if _, pinned := l.limits.Peers[peer]; pinned {
+			continue
+		}
+		if time.Unix(0, b.lastUsed.Load()).Before(since) {
+			delete(l.peers, peer)
+		}
+	}
+}
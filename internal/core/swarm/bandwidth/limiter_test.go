@@ -0,0 +1,158 @@
+// Package bandwidth 提供带宽统计模块的实现
+package bandwidth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dep2p/go-dep2p/pkg/interfaces"
+)
+
+// TestLimiter_NoLimits 测试未设置限速时不等待
+func TestLimiter_NoLimits(t *testing.T) {
+	limiter := NewLimiter(interfaces.BandwidthLimits{})
+
+	start := time.Now()
+	for i := 0; i < 100; i++ {
+		if err := limiter.WaitN(context.Background(), interfaces.DirOutbound, 1<<20, "/test/1.0", "peer"); err != nil {
+			t.Fatalf("WaitN failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("WaitN without limits took %v", elapsed)
+	}
+	if len(limiter.Status()) != 0 {
+		t.Errorf("Status() = %v, want empty", limiter.Status())
+	}
+}
+
+// TestLimiter_Scopes 测试各范围的限速
+func TestLimiter_Scopes(t *testing.T) {
+	realmProto := "/dep2p/app/realm-1/chat/1.0.0"
+	limiter := NewLimiter(interfaces.BandwidthLimits{
+		PerPeer:   interfaces.BandwidthLimit{Out: 10 * 1024},
+		Protocols: map[string]interfaces.BandwidthLimit{"/sync/1.0": {In: 10 * 1024}},
+		Realms:    map[string]interfaces.BandwidthLimit{"realm-1": {Out: 10 * 1024}},
+	})
+
+	tests := []struct {
+		name    string
+		dir     interfaces.Direction
+		proto   string
+		peer    string
+		limited bool
+	}{
+		{"per peer upload", interfaces.DirOutbound, "/other/1.0", "peer-a", true},
+		{"per peer download", interfaces.DirInbound, "/other/1.0", "peer-b", false},
+		{"protocol download", interfaces.DirInbound, "/sync/1.0", "", true},
+		{"realm upload", interfaces.DirOutbound, realmProto, "", true},
+		{"other realm", interfaces.DirOutbound, "/dep2p/app/realm-2/chat/1.0.0", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 第一次耗尽突发容量，第二次需要等待约 0.5 秒
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			if err := limiter.WaitN(ctx, tt.dir, 10*1024, tt.proto, tt.peer); err != nil {
+				t.Fatalf("first WaitN failed: %v", err)
+			}
+			err := limiter.WaitN(ctx, tt.dir, 5*1024, tt.proto, tt.peer)
+			if tt.limited && err == nil {
+				t.Error("second WaitN should be throttled")
+			}
+			if !tt.limited && err != nil {
+				t.Errorf("second WaitN failed: %v", err)
+			}
+		})
+	}
+
+	status := limiter.Status()
+	scopes := make(map[string]bool)
+	for _, s := range status {
+		scopes[s.Scope+":"+s.Key] = true
+		if s.Throttled == 0 && s.Scope != ScopePeer {
+			t.Errorf("%s:%s Throttled = 0", s.Scope, s.Key)
+		}
+	}
+	for _, want := range []string{"peer:peer-a", "protocol:/sync/1.0", "realm:realm-1"} {
+		if !scopes[want] {
+			t.Errorf("Status() missing %s", want)
+		}
+	}
+}
+
+// TestLimiter_Rate 测试限速速率
+func TestLimiter_Rate(t *testing.T) {
+	limiter := NewLimiter(interfaces.BandwidthLimits{
+		Total: interfaces.BandwidthLimit{Out: 100 * 1024, Burst: 10 * 1024},
+	})
+
+	// 突发 10KB 后按 100KB/s 发放：40KB 约需 0.3 秒
+	start := time.Now()
+	if err := limiter.WaitN(context.Background(), interfaces.DirOutbound, 40*1024, "", ""); err != nil {
+		t.Fatalf("WaitN failed: %v", err)
+	}
+	elapsed := time.Since(start)
+	if elapsed < 250*time.Millisecond || elapsed > time.Second {
+		t.Errorf("WaitN took %v, want about 300ms", elapsed)
+	}
+}
+
+// TestLimiter_SetLimits 测试运行时调整限速
+func TestLimiter_SetLimits(t *testing.T) {
+	limiter := NewLimiter(interfaces.BandwidthLimits{
+		Total:   interfaces.BandwidthLimit{Out: 1024},
+		PerPeer: interfaces.BandwidthLimit{Out: 1024},
+	})
+	limiter.WaitN(context.Background(), interfaces.DirOutbound, 1024, "", "peer-a")
+
+	// 放宽总量，取消节点默认限速，指定 peer-b
+	limiter.SetLimits(interfaces.BandwidthLimits{
+		Total: interfaces.BandwidthLimit{Out: 1 << 30},
+		Peers: map[string]interfaces.BandwidthLimit{"peer-b": {In: 2048}},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := limiter.WaitN(ctx, interfaces.DirOutbound, 64*1024, "", "peer-a"); err != nil {
+		t.Errorf("WaitN after raising limits failed: %v", err)
+	}
+
+	limits := limiter.Limits()
+	if limits.Total.Out != 1<<30 || !limits.PerPeer.IsZero() || limits.Peers["peer-b"].In != 2048 {
+		t.Errorf("Limits() = %+v", limits)
+	}
+
+	status := limiter.Status()
+	if len(status) != 2 || status[0].Scope != ScopeTotal || status[1].Key != "peer-b" {
+		t.Errorf("Status() = %+v", status)
+	}
+
+	// 返回的配置不与内部共享
+	limits.Peers["peer-b"] = interfaces.BandwidthLimit{}
+	if limiter.Limits().Peers["peer-b"].In != 2048 {
+		t.Error("Limits() should return a copy")
+	}
+}
+
+// TestLimiter_TrimIdle 测试清理空闲节点桶
+func TestLimiter_TrimIdle(t *testing.T) {
+	limiter := NewLimiter(interfaces.BandwidthLimits{
+		PerPeer: interfaces.BandwidthLimit{Out: 1024},
+		Peers:   map[string]interfaces.BandwidthLimit{"pinned": {Out: 1024}},
+	})
+	limiter.WaitN(context.Background(), interfaces.DirOutbound, 1, "", "idle")
+
+	limiter.TrimIdle(time.Now().Add(time.Second))
+
+	limiter.mu.RLock()
+	defer limiter.mu.RUnlock()
+	if _, ok := limiter.peers["idle"]; ok {
+		t.Error("idle peer bucket should be trimmed")
+	}
+	if _, ok := limiter.peers["pinned"]; !ok {
+		t.Error("pinned peer bucket should be kept")
+	}
+}
@@ -0,0 +1,70 @@
+//go:build linux
+
+package bandwidth
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// routeFile 内核路由表
+const routeFile = "/proc/net/route"
+
+// meteredIfacePrefixes 蜂窝/拨号网络接口名前缀
+//
+// wwan/wwp: ModemManager 管理的 4G/5G 模块；rmnet/ccmni: Android 高通/联发科基带；
+// ppp: 拨号与部分卫星终端。
+var meteredIfacePrefixes = []string{"wwan", "wwp", "rmnet", "ccmni", "ppp"}
+
+// detectMetered 根据默认路由所在的接口判断是否为计费网络
+//
+// 读取失败或没有默认路由时返回 false。
+func detectMetered() bool {
+	f, err := os.Open(routeFile)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	iface := defaultRouteIface(bufio.NewScanner(f))
+	return isMeteredIface(iface)
+}
+
+// defaultRouteIface 返回度量值最小的默认路由所在接口
+func defaultRouteIface(scanner *bufio.Scanner) string {
+	const rtfUp = 0x1
+
+	best, bestMetric := "", -1
+	scanner.Scan() // 跳过表头
+	for scanner.Scan() {
+		// Iface Destination Gateway Flags RefCnt Use Metric Mask ...
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 8 || fields[1] != "00000000" || fields[7] != "00000000" {
+			continue
+		}
+		flags, err := strconv.ParseUint(fields[3], 16, 32)
+		if err != nil || flags&rtfUp == 0 {
+			continue
+		}
+		metric, err := strconv.Atoi(fields[6])
+		if err != nil {
+			continue
+		}
+		if bestMetric < 0 || metric < bestMetric {
+			best, bestMetric = fields[0], metric
+		}
+	}
+	return best
+}
+
+// isMeteredIface 判断接口名是否属于蜂窝/拨号网络
+func isMeteredIface(iface string) bool {
+	for _, prefix := range meteredIfacePrefixes {
+		if strings.HasPrefix(iface, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+//go:build linux
+
+package bandwidth
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+// TestDefaultRouteIface 测试从路由表解析默认路由接口
+func TestDefaultRouteIface(t *testing.T) {
+	const header = "Iface\tDestination\tGateway \tFlags\tRefCnt\tUse\tMetric\tMask\t\tMTU\tWindow\tIRTT\n"
+
+	tests := []struct {
+		name    string
+		routes  string
+		iface   string
+		metered bool
+	}{
+		{
+			name:   "wifi",
+			routes: "wlan0\t00000000\t0101A8C0\t0003\t0\t0\t600\t00000000\t0\t0\t0\n",
+			iface:  "wlan0",
+		},
+		{
+			name: "cellular preferred",
+			routes: "wlan0\t00000000\t0101A8C0\t0003\t0\t0\t600\t00000000\t0\t0\t0\n" +
+				"wwan0\t00000000\t01000A0A\t0003\t0\t0\t100\t00000000\t0\t0\t0\n",
+			iface:   "wwan0",
+			metered: true,
+		},
+		{
+			name: "down route ignored",
+			routes: "rmnet0\t00000000\t01000A0A\t0002\t0\t0\t0\t00000000\t0\t0\t0\n" +
+				"eth0\t00000000\t010200C0\t0003\t0\t0\t100\t00000000\t0\t0\t0\n",
+			iface: "eth0",
+		},
+		{
+			name:   "no default route",
+			routes: "eth0\t000200C0\t00000000\t0001\t0\t0\t0\t00FFFFFF\t0\t0\t0\n",
+			iface:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			iface := defaultRouteIface(bufio.NewScanner(strings.NewReader(header + tt.routes)))
+			if iface != tt.iface {
+				t.Errorf("defaultRouteIface() = %q, want %q", iface, tt.iface)
+			}
+			if got := isMeteredIface(iface); got != tt.metered {
+				t.Errorf("isMeteredIface(%q) = %v, want %v", iface, got, tt.metered)
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package bandwidth
+
+// detectMetered 当前平台没有可用的计费网络提示
+//
+// 需要省流时请显式设置 DataBudgetMetered。
+func detectMetered() bool {
+	return false
+}
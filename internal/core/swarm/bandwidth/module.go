@@ -13,7 +13,7 @@ import (
 // Module 返回 Fx 模块
 func Module() fx.Option {
 	return fx.Module("bandwidth",
-		fx.Provide(ProvideCounter),
+		fx.Provide(ProvideCounter, ProvideLimiter, ProvideBudget),
 		fx.Invoke(registerLifecycle),
 	)
 }
@@ -27,11 +27,47 @@ func ProvideCounter(cfg *interfaces.BandwidthConfig) interfaces.BandwidthCounter
 	return NewCounter(*cfg)
 }
 
+// limiterInput 限速器输入参数
+type limiterInput struct {
+	fx.In
+	Limits *interfaces.BandwidthLimits `optional:"true"`
+}
+
+// ProvideLimiter 提供带宽限速器
+//
+// 未配置限速时也提供限速器（不限制），以便运行时调整。
+func ProvideLimiter(input limiterInput) interfaces.BandwidthLimiter {
+	var limits interfaces.BandwidthLimits
+	if input.Limits != nil {
+		limits = *input.Limits
+	}
+	return NewLimiter(limits)
+}
+
+// budgetInput 数据预算输入参数
+type budgetInput struct {
+	fx.In
+	Counter interfaces.BandwidthCounter
+	Config  *interfaces.DataBudgetConfig `optional:"true"`
+}
+
+// ProvideBudget 提供数据预算
+//
+// 默认按系统提示自动判断计费网络，不设每日预算。
+func ProvideBudget(input budgetInput) interfaces.DataBudget {
+	var cfg interfaces.DataBudgetConfig
+	if input.Config != nil {
+		cfg = *input.Config
+	}
+	return NewBudget(input.Counter, cfg)
+}
+
 // lifecycleInput 生命周期输入参数
 type lifecycleInput struct {
 	fx.In
 	LC      fx.Lifecycle
 	Counter interfaces.BandwidthCounter
+	Limiter interfaces.BandwidthLimiter `optional:"true"`
 	Config  *interfaces.BandwidthConfig `optional:"true"`
 }
 
@@ -56,7 +92,11 @@ func registerLifecycle(input lifecycleInput) {
 					for {
 						select {
 						case <-ticker.C:
-							input.Counter.TrimIdle(time.Now().Add(-config.IdleTimeout))
+							since := time.Now().Add(-config.IdleTimeout)
+							input.Counter.TrimIdle(since)
+							if input.Limiter != nil {
+								input.Limiter.TrimIdle(since)
+							}
 						case <-stopTrim:
 							return
 						}
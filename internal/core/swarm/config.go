@@ -2,6 +2,8 @@ package swarm
 
 import (
 	"time"
+
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
 )
 
 // Config Swarm 配置
@@ -18,6 +20,13 @@ type Config struct {
 	// MaxConcurrentDials 最大并发拨号数
 	MaxConcurrentDials int
 
+	// AddrDialDelay 同类地址之间的拨号间隔（Happy Eyeballs 错峰）
+	AddrDialDelay time.Duration
+
+	// ClassDialDelay 进入下一类地址前的拨号间隔
+	// AddrDialDelay 和 ClassDialDelay 都为 0 时所有地址同时拨号
+	ClassDialDelay time.Duration
+
 	// 连接健康检测配置
 	// 用于检测 QUIC 直连的健康状态，加速离线检测
 
@@ -36,6 +45,8 @@ func DefaultConfig() *Config {
 		DialTimeoutLocal:   5 * time.Second,
 		NewStreamTimeout:   15 * time.Second,
 		MaxConcurrentDials: 100,
+		AddrDialDelay:      DefaultAddrDialDelay,
+		ClassDialDelay:     DefaultClassDialDelay,
 
 		// 连接健康检测默认配置
 		ConnHealthInterval: 30 * time.Second,
@@ -57,6 +68,9 @@ func (c *Config) Validate() error {
 	if c.MaxConcurrentDials <= 0 {
 		return ErrInvalidConfig
 	}
+	if c.AddrDialDelay < 0 || c.ClassDialDelay < 0 {
+		return ErrInvalidConfig
+	}
 	return nil
 }
 
@@ -76,3 +90,11 @@ func WithConfig(config *Config) Option {
 		return nil
 	}
 }
+
+// WithDialRanker 设置拨号地址排序器（nil 表示使用默认分级错峰排序）
+func WithDialRanker(ranker pkgif.DialRanker) Option {
+	return func(s *Swarm) error {
+		s.dialRanker = ranker
+		return nil
+	}
+}
@@ -131,6 +131,21 @@ func (c *SwarmConn) SupportsStreamPriority() bool {
 	return c.conn.SupportsStreamPriority()
 }
 
+// StreamPriorityStats 返回各优先级写队列的统计
+//
+// 代理底层连接，底层连接未启用优先级调度时返回 nil。
+func (c *SwarmConn) StreamPriorityStats() []pkgif.StreamPriorityStats {
+	if pc, ok := c.conn.(priorityStatsConn); ok {
+		return pc.StreamPriorityStats()
+	}
+	return nil
+}
+
+// priorityStatsConn 提供优先级调度统计的连接（可选能力）
+type priorityStatsConn interface {
+	StreamPriorityStats() []pkgif.StreamPriorityStats
+}
+
 // GetStreams 获取所有流
 func (c *SwarmConn) GetStreams() []pkgif.Stream {
 	c.streamsMu.Lock()
@@ -149,6 +164,24 @@ func (c *SwarmConn) IsClosed() bool {
 	return c.closed
 }
 
+// HandshakeConfirmed 握手是否已确认
+//
+// 底层连接不支持 0-RTT 时握手总是在连接建立前完成。
+func (c *SwarmConn) HandshakeConfirmed() bool {
+	if early, ok := c.conn.(pkgif.EarlyDataConnection); ok {
+		return early.HandshakeConfirmed()
+	}
+	return true
+}
+
+// AwaitHandshake 等待底层连接握手确认
+func (c *SwarmConn) AwaitHandshake(ctx context.Context) error {
+	if early, ok := c.conn.(pkgif.EarlyDataConnection); ok {
+		return early.AwaitHandshake(ctx)
+	}
+	return nil
+}
+
 // Stat 返回连接统计信息
 func (c *SwarmConn) Stat() pkgif.ConnectionStat {
 	// 委托给底层连接
@@ -201,6 +234,11 @@ func (c *SwarmConn) getBandwidthCounter() pkgif.BandwidthCounter {
 	return c.swarm.getBandwidthCounter()
 }
 
+// getBandwidthLimiter 获取带宽限速器（内部方法）
+func (c *SwarmConn) getBandwidthLimiter() pkgif.BandwidthLimiter {
+	return c.swarm.getBandwidthLimiter()
+}
+
 // ConnType 返回连接类型（v2.0 新增）
 //
 // SwarmConn 代理底层连接的 ConnType 方法。
@@ -76,6 +76,24 @@ func TestSwarmConn_LocalMultiaddr_Nil(t *testing.T) {
 	assert.Nil(t, sc.RemoteMultiaddr())
 }
 
+// TestSwarmConn_StreamPriorityStats 测试转发底层连接的优先级调度统计
+func TestSwarmConn_StreamPriorityStats(t *testing.T) {
+	swarm, err := NewSwarm("local-peer")
+	require.NoError(t, err)
+	defer swarm.Close()
+
+	// 底层连接不提供统计
+	sc := newSwarmConn(swarm, &mockConnForTest{localPeer: "local-peer", remotePeer: "remote-peer"})
+	assert.Nil(t, sc.StreamPriorityStats())
+
+	stats := []pkgif.StreamPriorityStats{{Priority: pkgif.StreamPriorityCritical, SentFrames: 3}}
+	sc = newSwarmConn(swarm, &priorityStatsConnForTest{
+		mockConnForTest: mockConnForTest{localPeer: "local-peer", remotePeer: "remote-peer"},
+		stats:           stats,
+	})
+	assert.Equal(t, stats, sc.StreamPriorityStats())
+}
+
 // ============================================================================
 //                     Mock Connection
 // ============================================================================
@@ -141,3 +159,13 @@ func (m *mockConnForTest) IsClosed() bool {
 func (m *mockConnForTest) ConnType() pkgif.ConnectionType {
 	return pkgif.ConnectionTypeDirect
 }
+
+// priorityStatsConnForTest 提供优先级调度统计的 mock 连接
+type priorityStatsConnForTest struct {
+	mockConnForTest
+	stats []pkgif.StreamPriorityStats
+}
+
+func (m *priorityStatsConnForTest) StreamPriorityStats() []pkgif.StreamPriorityStats {
+	return m.stats
+}
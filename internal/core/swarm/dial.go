@@ -3,6 +3,7 @@ package swarm
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -16,6 +17,12 @@ import (
 // 超过此阈值后，后续失败日志降为 DEBUG 级别，减少日志噪音
 const dialFailureLogThreshold = 3
 
+// 拨号结果对应的信誉信号增量
+const (
+	dialFailurePenalty = -0.2
+	dialSuccessReward  = 0.1
+)
+
 // ============================================================================
 //                              Relay 地址退避机制
 // ============================================================================
@@ -146,8 +153,8 @@ func (s *Swarm) getDialFailures(peerID string) int {
 	return 0
 }
 
-// OPT-2: incrementDialFailures 增加拨号失败次数并返回当前值
-func (s *Swarm) incrementDialFailures(peerID string) int {
+// OPT-2: countDialFailure 增加拨号失败次数并返回当前值
+func (s *Swarm) countDialFailure(peerID string) int {
 	// 最多尝试 10 次 CAS，避免高并发下无限循环
 	const maxRetries = 10
 	for i := 0; i < maxRetries; i++ {
@@ -167,9 +174,24 @@ func (s *Swarm) incrementDialFailures(peerID string) int {
 	return 1
 }
 
+// incrementDialFailures 增加拨号失败次数并返回当前值
+//
+// 同时向信誉服务报告拨号失败。
+func (s *Swarm) incrementDialFailures(peerID string) int {
+	if rep := s.Reputation(); rep != nil {
+		rep.Report(peerID, pkgif.ReputationSignalDial, dialFailurePenalty, "dial_failed")
+	}
+	return s.countDialFailure(peerID)
+}
+
 // OPT-2: resetDialFailures 重置拨号失败次数（连接成功时调用）
+//
+// 同时向信誉服务报告拨号成功。
 func (s *Swarm) resetDialFailures(peerID string) {
 	s.dialFailures.Delete(peerID)
+	if rep := s.Reputation(); rep != nil {
+		rep.Report(peerID, pkgif.ReputationSignalDial, dialSuccessReward, "dial_succeeded")
+	}
 }
 
 // dialResult 拨号结果
@@ -178,6 +200,55 @@ type dialResult struct {
 	err  error
 }
 
+// addrDialResult 单个地址的拨号结果（dialWorker 内部使用）
+type addrDialResult struct {
+	index    int
+	conn     pkgif.Connection
+	err      error
+	duration time.Duration
+}
+
+// DialAttemptResult 单个地址的拨号结局
+type DialAttemptResult int
+
+const (
+	// DialSkipped 未发起（已有地址胜出或超时）
+	DialSkipped DialAttemptResult = iota
+	// DialCanceled 已发起，因其他地址胜出或超时被取消
+	DialCanceled
+	// DialFailed 拨号失败
+	DialFailed
+	// DialWon 拨号成功并被采用
+	DialWon
+)
+
+func (r DialAttemptResult) String() string {
+	names := []string{"skipped", "canceled", "failed", "won"}
+	if int(r) < len(names) {
+		return names[r]
+	}
+	return "unknown"
+}
+
+// DialAttempt 单个地址的拨号记录
+type DialAttempt struct {
+	Addr     string
+	Delay    time.Duration // 计划的启动延迟
+	Result   DialAttemptResult
+	Duration time.Duration // 拨号耗时（未完成时为 0）
+	Err      error
+}
+
+func (a DialAttempt) String() string {
+	if a.Err != nil {
+		return fmt.Sprintf("%s: %s after %s: %v", a.Addr, a.Result, a.Duration, a.Err)
+	}
+	if a.Duration > 0 {
+		return fmt.Sprintf("%s: %s after %s", a.Addr, a.Result, a.Duration)
+	}
+	return fmt.Sprintf("%s: %s", a.Addr, a.Result)
+}
+
 // dialPeer 拨号连接到指定节点（完整实现）
 //
 // 实现惰性中继策略 + HolePunch 打洞（符合设计文档 Section 8.3）：
@@ -212,6 +283,12 @@ func (s *Swarm) dialPeer(ctx context.Context, peerID string) (pkgif.Connection,
 		return nil, ErrDialToSelf
 	}
 
+	// 检查连接门控
+	if gater := s.getGater(); gater != nil && !gater.InterceptPeerDial(peerID) {
+		logger.Debug("连接门控拒绝拨号", "peerID", peerShort)
+		return nil, ErrDialGated
+	}
+
 	// 2. 尝试直连
 	var directErr error
 
@@ -240,9 +317,12 @@ func (s *Swarm) dialPeer(ctx context.Context, peerID string) (pkgif.Connection,
 		// Phase 0 修复：使用路径健康管理器进一步优化地址排序
 		directAddrs = s.rankAddrsWithHealth(peerID, directAddrs)
 
+		// 跳过对端 Identify 能力块中未通告的传输协议（陈旧地址）
+		directAddrs = s.filterAddrsByCapabilities(peerID, directAddrs)
+
 		logger.Info("尝试直连", "peerID", peerShort, "addrCount", len(directAddrs), "firstAddr", directAddrs[0])
 
-		// 并发拨号
+		// 分级错峰拨号
 		conn, err := s.dialWorker(ctx, peerID, directAddrs)
 		if err == nil {
 			logger.Info("直连成功", "peerID", peerShort, "remoteAddr", conn.RemoteMultiaddr())
@@ -760,7 +840,12 @@ func isPrivateAddr(addr string) bool {
 		strings.Contains(addr, "localhost")
 }
 
-// dialWorker 并发拨号工作器
+// dialWorker 分级错峰拨号工作器（Happy Eyeballs）
+//
+// 按 DialRanker 给出的延迟依次发起拨号：
+//   - 任一地址成功即取消其余拨号，之后才完成的连接直接关闭，不进入连接池
+//   - 拨号失败且没有进行中的拨号时，立即发起下一个地址，后续地址的间隔保持不变
+//   - 每个地址的结果记录在 DialError.Attempts 中，并输出到调试日志
 func (s *Swarm) dialWorker(ctx context.Context, peerID string, addrs []string) (pkgif.Connection, error) {
 	// 确定超时时间
 	timeout := s.config.DialTimeout
@@ -779,34 +864,91 @@ func (s *Swarm) dialWorker(ctx context.Context, peerID string, addrs []string) (
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	// 结果通道
-	results := make(chan dialResult, len(addrs))
+	plan := s.getDialRanker()(addrs)
+	if len(plan) == 0 {
+		return nil, ErrNoAddresses
+	}
+	sort.SliceStable(plan, func(i, j int) bool { return plan[i].Delay < plan[j].Delay })
 
-	// 并发拨号所有地址
-	for _, addr := range addrs {
-		go func(addr string) {
-			conn, err := s.dialAddr(ctx, peerID, addr)
-			results <- dialResult{conn, err}
-		}(addr)
+	attempts := make([]DialAttempt, len(plan))
+	for i, p := range plan {
+		attempts[i] = DialAttempt{Addr: p.Addr, Delay: p.Delay}
 	}
 
-	// 等待第一个成功或全部失败
-	var errs []error
-	for i := 0; i < len(addrs); i++ {
+	// 结果通道（带缓冲，胜出后剩余拨号不会阻塞）
+	results := make(chan addrDialResult, len(plan))
+	start := time.Now()
+	next, active := 0, 0
+
+	startDial := func(i int) {
+		attempts[i].Result = DialCanceled
+		active++
+		go func() {
+			dialStart := time.Now()
+			conn, err := s.dialAddrConn(ctx, peerID, plan[i].Addr)
+			results <- addrDialResult{index: i, conn: conn, err: err, duration: time.Since(dialStart)}
+		}()
+	}
+
+	// 胜出或超时后关闭仍在进行的拨号产生的连接
+	abandon := func() {
+		go func(n int) {
+			for i := 0; i < n; i++ {
+				if res := <-results; res.err == nil {
+					res.conn.Close()
+				}
+			}
+		}(active)
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		// 发起所有到期的拨号
+		elapsed := time.Since(start)
+		for next < len(plan) && plan[next].Delay <= elapsed {
+			startDial(next)
+			next++
+		}
+		if active == 0 && next == len(plan) {
+			break
+		}
+
+		var timerC <-chan time.Time
+		if next < len(plan) {
+			timer.Reset(plan[next].Delay - elapsed)
+			timerC = timer.C
+		}
+
 		select {
+		case <-timerC:
 		case res := <-results:
+			active--
+			attempt := &attempts[res.index]
+			attempt.Duration = res.duration
 			if res.err == nil {
 				// 第一个成功的连接胜出，取消其他拨号
+				attempt.Result = DialWon
 				cancel()
-				return res.conn, nil
+				abandon()
+				conn := s.adoptDialedConn(res.conn, attempt.Addr, res.duration)
+				logger.Debug("拨号完成", "peerID", truncateID(peerID, 8), "attempts", attempts)
+				return conn, nil
+			}
+			attempt.Result = DialFailed
+			attempt.Err = res.err
+			if active == 0 && next < len(plan) {
+				// 没有进行中的拨号，立即发起下一个地址，后续计划整体提前
+				start = start.Add(-(plan[next].Delay - time.Since(start)))
 			}
-			errs = append(errs, res.err)
 		case <-ctx.Done():
-			// 超时或取消
+			abandon()
+			logger.Debug("拨号未完成", "peerID", truncateID(peerID, 8), "attempts", attempts, "error", ctx.Err())
 			if ctx.Err() == context.DeadlineExceeded {
 				// 超时后检查死亡路径
 				s.cleanupDeadPaths(peerID, addrs)
-				return nil, ErrDialTimeout
+				return nil, &DialError{Peer: peerID, Errors: []error{ErrDialTimeout}, Attempts: attempts}
 			}
 			return nil, ctx.Err()
 		}
@@ -816,9 +958,17 @@ func (s *Swarm) dialWorker(ctx context.Context, peerID string, addrs []string) (
 	s.cleanupDeadPaths(peerID, addrs)
 
 	// 所有拨号都失败
+	var errs []error
+	for _, a := range attempts {
+		if a.Err != nil {
+			errs = append(errs, a.Err)
+		}
+	}
+	logger.Debug("拨号全部失败", "peerID", truncateID(peerID, 8), "attempts", attempts)
 	return nil, &DialError{
-		Peer:   peerID,
-		Errors: errs,
+		Peer:     peerID,
+		Errors:   errs,
+		Attempts: attempts,
 	}
 }
 
@@ -868,8 +1018,21 @@ func (s *Swarm) cleanupDeadPaths(peerID string, addrs []string) {
 		"deadAddrs", deadAddrs)
 }
 
-// dialAddr 拨号单个地址
+// dialAddr 拨号单个地址并加入连接池
 func (s *Swarm) dialAddr(ctx context.Context, peerID string, addr string) (pkgif.Connection, error) {
+	startTime := time.Now()
+	transportConn, err := s.dialAddrConn(ctx, peerID, addr)
+	if err != nil {
+		return nil, err
+	}
+	return s.adoptDialedConn(transportConn, addr, time.Since(startTime)), nil
+}
+
+// dialAddrConn 拨号单个地址，返回尚未加入连接池的传输层连接
+//
+// 拨号结果报告给 PathHealthManager；因 ctx 取消（如其他地址已胜出）
+// 而失败的拨号不计入路径健康度。
+func (s *Swarm) dialAddrConn(ctx context.Context, peerID string, addr string) (pkgif.Connection, error) {
 	logger.Debug("开始拨号地址",
 		"peerID", truncateID(peerID, 8),
 		"addr", addr)
@@ -908,8 +1071,10 @@ func (s *Swarm) dialAddr(ctx context.Context, peerID string, addr string) (pkgif
 			"addr", addr,
 			"error", err,
 			"duration", rtt)
-		// 报告拨号失败给 PathHealthManager
-		s.reportDialResult(peerID, addr, rtt, err)
+		// 报告拨号失败给 PathHealthManager（被取消的拨号不代表路径故障）
+		if ctx.Err() != context.Canceled {
+			s.reportDialResult(peerID, addr, rtt, err)
+		}
 		return nil, fmt.Errorf("dial %s: %w", addr, err)
 	}
 
@@ -931,6 +1096,11 @@ func (s *Swarm) dialAddr(ctx context.Context, peerID string, addr string) (pkgif
 	// 报告拨号成功给 PathHealthManager
 	s.reportDialResult(peerID, addr, rtt, nil)
 
+	return transportConn, nil
+}
+
+// adoptDialedConn 把拨号成功的传输层连接加入连接池
+func (s *Swarm) adoptDialedConn(transportConn pkgif.Connection, addr string, rtt time.Duration) pkgif.Connection {
 	// 封装为 Swarm 连接
 	conn := newSwarmConn(s, transportConn)
 
@@ -942,7 +1112,7 @@ func (s *Swarm) dialAddr(ctx context.Context, peerID string, addr string) (pkgif
 
 	// P0-2: 记录直连成功日志，包含连接类型和 RTT
 	logger.Debug("直连拨号成功",
-		"peerID", truncateID(peerID, 8),
+		"peerID", truncateID(string(transportConn.RemotePeer()), 8),
 		"connType", conn.ConnType().String(),
 		"addr", addr,
 		"rtt", rtt)
@@ -952,7 +1122,7 @@ func (s *Swarm) dialAddr(ctx context.Context, peerID string, addr string) (pkgif
 
 	// 注：ConnMgr 通过事件总线自动处理连接事件
 
-	return conn, nil
+	return conn
 }
 
 // reportDialResult 报告拨号结果给 PathHealthManager
@@ -1053,6 +1223,38 @@ func (s *Swarm) SetConnMgr(connmgr pkgif.ConnManager) {
 	s.connmgr = connmgr
 }
 
+// SetConnGater 设置连接门控
+//
+// 设置后拨号前和接受入站连接时检查门控，拒绝被阻止或被信誉封禁的节点。
+func (s *Swarm) SetConnGater(gater pkgif.ConnGater) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gater = gater
+}
+
+// getGater 获取连接门控
+func (s *Swarm) getGater() pkgif.ConnGater {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.gater
+}
+
+// SetReputation 设置信誉服务
+//
+// 设置后拨号成功与失败会作为信誉信号上报。
+func (s *Swarm) SetReputation(reputation pkgif.Reputation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reputation = reputation
+}
+
+// Reputation 返回信誉服务（未设置时为 nil）
+func (s *Swarm) Reputation() pkgif.Reputation {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.reputation
+}
+
 // SetEventBus 设置 EventBus
 func (s *Swarm) SetEventBus(eventbus pkgif.EventBus) {
 	s.mu.Lock()
@@ -1067,6 +1269,15 @@ func (s *Swarm) SetBandwidthCounter(bandwidth pkgif.BandwidthCounter) {
 	s.bandwidth = bandwidth
 }
 
+// SetBandwidthLimiter 设置带宽限速器
+//
+// 设置后 Swarm 流的读写按限速器的配额进行。
+func (s *Swarm) SetBandwidthLimiter(limiter pkgif.BandwidthLimiter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bandwidthLimiter = limiter
+}
+
 // SetPathHealthManager 设置路径健康管理器
 //
 // Phase 0 修复：集成 pathhealth 模块
@@ -1079,6 +1290,23 @@ func (s *Swarm) SetPathHealthManager(manager pkgif.PathHealthManager) {
 	s.pathHealthManager = manager
 }
 
+// SetDialRanker 设置拨号地址排序器（nil 表示使用默认分级错峰排序）
+func (s *Swarm) SetDialRanker(ranker pkgif.DialRanker) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dialRanker = ranker
+}
+
+// getDialRanker 获取拨号地址排序器
+func (s *Swarm) getDialRanker() pkgif.DialRanker {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.dialRanker != nil {
+		return s.dialRanker
+	}
+	return DefaultDialRanker(s.config.AddrDialDelay, s.config.ClassDialDelay)
+}
+
 // getBandwidthCounter 获取带宽计数器（内部方法）
 func (s *Swarm) getBandwidthCounter() pkgif.BandwidthCounter {
 	s.mu.RLock()
@@ -1086,6 +1314,38 @@ func (s *Swarm) getBandwidthCounter() pkgif.BandwidthCounter {
 	return s.bandwidth
 }
 
+// getBandwidthLimiter 获取带宽限速器（内部方法）
+func (s *Swarm) getBandwidthLimiter() pkgif.BandwidthLimiter {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.bandwidthLimiter
+}
+
+// BandwidthLimiter 返回带宽限速器
+//
+// 用于 Node API 层通过类型断言调整限速。未启用时返回 nil。
+func (s *Swarm) BandwidthLimiter() pkgif.BandwidthLimiter {
+	return s.getBandwidthLimiter()
+}
+
+// SetDataBudget 设置数据预算
+//
+// 设置后连接健康检测按数据预算节流。
+func (s *Swarm) SetDataBudget(budget pkgif.DataBudget) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dataBudget = budget
+}
+
+// DataBudget 返回数据预算
+//
+// 用于 Node API 层通过类型断言调整省流模式。未启用时返回 nil。
+func (s *Swarm) DataBudget() pkgif.DataBudget {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.dataBudget
+}
+
 // BandwidthCounter 返回带宽计数器（v1.1 新增公开方法）
 //
 // 用于 Node API 层通过类型断言访问带宽统计功能。
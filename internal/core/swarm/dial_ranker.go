@@ -0,0 +1,165 @@
+package swarm
+
+import (
+	"strings"
+	"time"
+
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/types"
+)
+
+// 默认拨号错峰延迟
+const (
+	// DefaultAddrDialDelay 同类地址之间的拨号间隔（RFC 8305 连接尝试延迟）
+	DefaultAddrDialDelay = 250 * time.Millisecond
+
+	// DefaultClassDialDelay 进入下一类地址前的拨号间隔
+	DefaultClassDialDelay = 300 * time.Millisecond
+)
+
+// addrClass 地址类别（数值越小越先拨号）
+type addrClass int
+
+const (
+	classLocalQUIC addrClass = iota
+	classLocalTCP
+	classPublicQUIC
+	classPublicTCP
+	classOther
+	classRelay
+	numAddrClasses
+)
+
+// classifyAddr 按网络范围和传输协议对地址分类
+func classifyAddr(addr string) addrClass {
+	if strings.Contains(addr, "/p2p-circuit") {
+		return classRelay
+	}
+	quic := strings.Contains(addr, "/quic")
+	tcp := strings.Contains(addr, "/tcp")
+	if isPrivateAddr(addr) {
+		switch {
+		case quic:
+			return classLocalQUIC
+		case tcp:
+			return classLocalTCP
+		}
+	}
+	switch {
+	case quic:
+		return classPublicQUIC
+	case tcp:
+		return classPublicTCP
+	}
+	return classOther
+}
+
+// isIP6Addr 是否为 IPv6 地址
+func isIP6Addr(addr string) bool {
+	return strings.HasPrefix(addr, "/ip6/") || strings.HasPrefix(addr, "/dns6/")
+}
+
+// interleaveFamilies 交错排列 IPv6 和 IPv4 地址
+//
+// 从排在最前的地址族开始交替取地址，同族内保持原有顺序。
+func interleaveFamilies(addrs []string) []string {
+	if len(addrs) < 2 {
+		return addrs
+	}
+	var first, second []string
+	leadIP6 := isIP6Addr(addrs[0])
+	for _, addr := range addrs {
+		if isIP6Addr(addr) == leadIP6 {
+			first = append(first, addr)
+		} else {
+			second = append(second, addr)
+		}
+	}
+	result := make([]string, 0, len(addrs))
+	for i := 0; i < len(first) || i < len(second); i++ {
+		if i < len(first) {
+			result = append(result, first[i])
+		}
+		if i < len(second) {
+			result = append(result, second[i])
+		}
+	}
+	return result
+}
+
+// DefaultDialRanker 返回默认的分级错峰拨号排序器
+//
+// 地址按类别依次拨号：局域网 QUIC、局域网 TCP、公网 QUIC、公网 TCP、
+// 其他、中继。同类地址保持输入顺序（即路径健康度排序）并交错 IPv6/IPv4，
+// 相邻地址间隔 addrDelay，进入下一类间隔 classDelay。
+// 两个延迟都为 0 时所有地址同时拨号。
+func DefaultDialRanker(addrDelay, classDelay time.Duration) pkgif.DialRanker {
+	return func(addrs []string) []pkgif.AddrDelay {
+		var classes [numAddrClasses][]string
+		for _, addr := range addrs {
+			c := classifyAddr(addr)
+			classes[c] = append(classes[c], addr)
+		}
+
+		result := make([]pkgif.AddrDelay, 0, len(addrs))
+		var delay time.Duration
+		for _, group := range classes {
+			if len(group) == 0 {
+				continue
+			}
+			if len(result) > 0 {
+				delay += classDelay
+			}
+			for i, addr := range interleaveFamilies(group) {
+				if i > 0 {
+					delay += addrDelay
+				}
+				result = append(result, pkgif.AddrDelay{Addr: addr, Delay: delay})
+			}
+		}
+		return result
+	}
+}
+
+// NoDelayDialRanker 所有地址按输入顺序同时拨号
+func NoDelayDialRanker(addrs []string) []pkgif.AddrDelay {
+	result := make([]pkgif.AddrDelay, len(addrs))
+	for i, addr := range addrs {
+		result[i] = pkgif.AddrDelay{Addr: addr}
+	}
+	return result
+}
+
+// filterAddrsByCapabilities 按对端通告的传输协议过滤地址
+//
+// 对端通过 Identify 通告了支持的传输时，跳过不支持的传输的地址，
+// 这类地址通常来自对端已关闭的监听器。未通告能力或过滤后为空时保持原样。
+func (s *Swarm) filterAddrsByCapabilities(peerID string, addrs []string) []string {
+	if s.peerstore == nil {
+		return addrs
+	}
+	v, err := s.peerstore.Get(types.PeerID(peerID), types.PeerCapabilitiesKey)
+	if err != nil {
+		return addrs
+	}
+	caps, ok := types.ParsePeerCapabilities(v)
+	if !ok || len(caps.Transports) == 0 {
+		return addrs
+	}
+
+	filtered := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		if t := types.AddrTransport(addr); t == "" || caps.SupportsTransport(t) {
+			filtered = append(filtered, addr)
+		}
+	}
+	if len(filtered) == 0 {
+		return addrs
+	}
+	if len(filtered) < len(addrs) {
+		logger.Debug("按对端能力跳过不支持的传输地址",
+			"peerID", truncateID(peerID, 8),
+			"skipped", len(addrs)-len(filtered))
+	}
+	return filtered
+}
@@ -0,0 +1,304 @@
+package swarm
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dep2p/go-dep2p/internal/core/peerstore"
+	"github.com/dep2p/go-dep2p/internal/core/swarm/pathhealth"
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/types"
+)
+
+// ============================================================================
+//                     拨号排序测试
+// ============================================================================
+
+func TestDefaultDialRanker(t *testing.T) {
+	addrs := []string{
+		"/ip4/1.2.3.4/tcp/4001",
+		"/ip4/1.2.3.4/udp/4001/quic-v1",
+		"/ip4/1.2.3.5/udp/4001/quic-v1",
+		"/ip6/2001:db8::1/udp/4001/quic-v1",
+		"/ip4/192.168.1.10/tcp/4001",
+		"/ip4/192.168.1.10/udp/4001/quic-v1",
+		"/ip4/5.6.7.8/udp/4001/quic-v1/p2p/relay/p2p-circuit",
+	}
+
+	plan := DefaultDialRanker(10*time.Millisecond, 100*time.Millisecond)(addrs)
+	require.Len(t, plan, len(addrs))
+
+	expected := []pkgif.AddrDelay{
+		{Addr: "/ip4/192.168.1.10/udp/4001/quic-v1", Delay: 0},
+		{Addr: "/ip4/192.168.1.10/tcp/4001", Delay: 100 * time.Millisecond},
+		// 公网 QUIC：IPv4 排在最前，与 IPv6 交错
+		{Addr: "/ip4/1.2.3.4/udp/4001/quic-v1", Delay: 200 * time.Millisecond},
+		{Addr: "/ip6/2001:db8::1/udp/4001/quic-v1", Delay: 210 * time.Millisecond},
+		{Addr: "/ip4/1.2.3.5/udp/4001/quic-v1", Delay: 220 * time.Millisecond},
+		{Addr: "/ip4/1.2.3.4/tcp/4001", Delay: 320 * time.Millisecond},
+		{Addr: "/ip4/5.6.7.8/udp/4001/quic-v1/p2p/relay/p2p-circuit", Delay: 420 * time.Millisecond},
+	}
+	assert.Equal(t, expected, plan)
+
+	// 延迟为 0 时同时拨号
+	for _, p := range DefaultDialRanker(0, 0)(addrs) {
+		assert.Zero(t, p.Delay)
+	}
+	assert.Empty(t, DefaultDialRanker(0, 0)(nil))
+}
+
+// ============================================================================
+//                     错峰拨号测试
+// ============================================================================
+
+// dialBehavior 测试传输层对单个地址的拨号行为
+type dialBehavior struct {
+	after time.Duration // 返回结果前的等待时间
+	fail  bool          // 是否失败
+	hang  bool          // 一直阻塞到 ctx 结束
+}
+
+// scriptedTransport 按地址返回预设结果的传输层
+type scriptedTransport struct {
+	peer      types.PeerID
+	behaviors map[string]dialBehavior
+
+	mu      sync.Mutex
+	start   time.Time
+	started map[string]time.Duration
+	conns   []*stubDialConn
+}
+
+func newScriptedTransport(peer types.PeerID, behaviors map[string]dialBehavior) *scriptedTransport {
+	return &scriptedTransport{
+		peer:      peer,
+		behaviors: behaviors,
+		start:     time.Now(),
+		started:   make(map[string]time.Duration),
+	}
+}
+
+func (t *scriptedTransport) Dial(ctx context.Context, raddr types.Multiaddr, _ types.PeerID) (pkgif.Connection, error) {
+	addr := raddr.String()
+	t.mu.Lock()
+	t.started[addr] = time.Since(t.start)
+	t.mu.Unlock()
+
+	b := t.behaviors[addr]
+	if b.hang {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	time.Sleep(b.after)
+	if b.fail {
+		return nil, errors.New("connection refused")
+	}
+	conn := &stubDialConn{remote: t.peer, done: make(chan struct{})}
+	t.mu.Lock()
+	t.conns = append(t.conns, conn)
+	t.mu.Unlock()
+	return conn, nil
+}
+
+func (t *scriptedTransport) startedAt(addr string) (time.Duration, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	d, ok := t.started[addr]
+	return d, ok
+}
+
+func (t *scriptedTransport) CanDial(types.Multiaddr) bool { return true }
+func (t *scriptedTransport) Listen(types.Multiaddr) (pkgif.Listener, error) {
+	return nil, errors.New("not supported")
+}
+func (t *scriptedTransport) Protocols() []int { return nil }
+func (t *scriptedTransport) Close() error     { return nil }
+
+// stubDialConn 拨号测试用连接，AcceptStream 阻塞到连接关闭
+type stubDialConn struct {
+	testConnForDial
+	remote types.PeerID
+	closed atomic.Bool
+	once   sync.Once
+	done   chan struct{}
+}
+
+func (c *stubDialConn) RemotePeer() types.PeerID { return c.remote }
+func (c *stubDialConn) IsClosed() bool           { return c.closed.Load() }
+func (c *stubDialConn) AcceptStream() (pkgif.Stream, error) {
+	<-c.done
+	return nil, errors.New("closed")
+}
+func (c *stubDialConn) Close() error {
+	c.closed.Store(true)
+	c.once.Do(func() { close(c.done) })
+	return nil
+}
+
+// newStaggerTestSwarm 创建使用脚本传输层的 Swarm
+func newStaggerTestSwarm(t *testing.T, tr pkgif.Transport, opts ...Option) *Swarm {
+	t.Helper()
+	s, err := NewSwarm("local-peer", opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { s.Close() })
+	require.NoError(t, s.AddTransport("quic", tr))
+	require.NoError(t, s.AddTransport("tcp", tr))
+	return s
+}
+
+// staggerConfig 返回指定错峰延迟的配置
+func staggerConfig(addrDelay, classDelay time.Duration) *Config {
+	cfg := DefaultConfig()
+	cfg.AddrDialDelay = addrDelay
+	cfg.ClassDialDelay = classDelay
+	return cfg
+}
+
+func TestDialWorker_Stagger(t *testing.T) {
+	const (
+		lanQUIC    = "/ip4/192.168.1.10/udp/4001/quic-v1"
+		publicQUIC = "/ip4/1.2.3.4/udp/4001/quic-v1"
+		publicTCP  = "/ip4/1.2.3.4/tcp/4001"
+	)
+	tr := newScriptedTransport("remote-peer", map[string]dialBehavior{
+		lanQUIC:    {hang: true},
+		publicQUIC: {},
+	})
+	s := newStaggerTestSwarm(t, tr, WithConfig(staggerConfig(50*time.Millisecond, 50*time.Millisecond)))
+	health := pathhealth.NewManager(pathhealth.DefaultConfig())
+	s.SetPathHealthManager(health)
+
+	conn, err := s.dialWorker(context.Background(), "remote-peer", []string{publicTCP, publicQUIC, lanQUIC})
+	require.NoError(t, err)
+	assert.Equal(t, types.PeerID("remote-peer"), conn.RemotePeer())
+
+	// 局域网 QUIC 最先发起，公网 QUIC 错峰后发起，公网 TCP 未发起
+	at, ok := tr.startedAt(lanQUIC)
+	require.True(t, ok)
+	assert.Less(t, at, 50*time.Millisecond)
+	at, ok = tr.startedAt(publicQUIC)
+	require.True(t, ok)
+	assert.GreaterOrEqual(t, at, 50*time.Millisecond)
+	_, ok = tr.startedAt(publicTCP)
+	assert.False(t, ok)
+
+	// 被取消的拨号不计入路径健康度
+	assert.NotNil(t, health.GetPathStats("remote-peer", publicQUIC))
+	time.Sleep(20 * time.Millisecond)
+	assert.Nil(t, health.GetPathStats("remote-peer", lanQUIC))
+}
+
+func TestDialWorker_FailureStartsNextImmediately(t *testing.T) {
+	const (
+		first  = "/ip4/1.2.3.4/udp/4001/quic-v1"
+		second = "/ip4/1.2.3.4/tcp/4001"
+	)
+	tr := newScriptedTransport("remote-peer", map[string]dialBehavior{
+		first: {fail: true},
+	})
+	s := newStaggerTestSwarm(t, tr, WithConfig(staggerConfig(time.Second, time.Second)))
+
+	_, err := s.dialWorker(context.Background(), "remote-peer", []string{first, second})
+	require.NoError(t, err)
+
+	at, ok := tr.startedAt(second)
+	require.True(t, ok)
+	assert.Less(t, at, 500*time.Millisecond)
+}
+
+func TestDialWorker_LateWinnerClosed(t *testing.T) {
+	const (
+		fast = "/ip4/1.2.3.4/udp/4001/quic-v1"
+		slow = "/ip4/1.2.3.5/udp/4001/quic-v1"
+	)
+	tr := newScriptedTransport("remote-peer", map[string]dialBehavior{
+		slow: {after: 30 * time.Millisecond},
+	})
+	s := newStaggerTestSwarm(t, tr, WithDialRanker(NoDelayDialRanker))
+
+	conn, err := s.dialWorker(context.Background(), "remote-peer", []string{fast, slow})
+	require.NoError(t, err)
+
+	// 慢的拨号晚于胜出者完成，连接被关闭且不进入连接池
+	require.Eventually(t, func() bool {
+		tr.mu.Lock()
+		defer tr.mu.Unlock()
+		return len(tr.conns) == 2 && tr.conns[1].IsClosed()
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []pkgif.Connection{conn}, s.ConnsToPeer("remote-peer"))
+}
+
+func TestDialWorker_AllFailedReportsAttempts(t *testing.T) {
+	const (
+		a = "/ip4/1.2.3.4/udp/4001/quic-v1"
+		b = "/ip4/1.2.3.4/tcp/4001"
+	)
+	tr := newScriptedTransport("remote-peer", map[string]dialBehavior{
+		a: {fail: true},
+		b: {fail: true},
+	})
+	s := newStaggerTestSwarm(t, tr)
+
+	_, err := s.dialWorker(context.Background(), "remote-peer", []string{a, b})
+	var dialErr *DialError
+	require.ErrorAs(t, err, &dialErr)
+	require.Len(t, dialErr.Attempts, 2)
+	assert.Len(t, dialErr.Errors, 2)
+	for _, attempt := range dialErr.Attempts {
+		assert.Equal(t, DialFailed, attempt.Result)
+		assert.Error(t, attempt.Err)
+	}
+}
+
+func TestDialWorker_TimeoutReportsAttempts(t *testing.T) {
+	const (
+		a = "/ip4/1.2.3.4/udp/4001/quic-v1"
+		b = "/ip4/1.2.3.4/tcp/4001"
+	)
+	tr := newScriptedTransport("remote-peer", map[string]dialBehavior{
+		a: {hang: true},
+	})
+	cfg := staggerConfig(time.Second, time.Second)
+	cfg.DialTimeout = 50 * time.Millisecond
+	s := newStaggerTestSwarm(t, tr, WithConfig(cfg))
+
+	_, err := s.dialWorker(context.Background(), "remote-peer", []string{a, b})
+	assert.ErrorIs(t, err, ErrDialTimeout)
+	var dialErr *DialError
+	require.ErrorAs(t, err, &dialErr)
+	assert.Equal(t, DialCanceled, dialErr.Attempts[0].Result)
+	assert.Equal(t, DialSkipped, dialErr.Attempts[1].Result)
+}
+
+func TestFilterAddrsByCapabilities(t *testing.T) {
+	const (
+		quicAddr  = "/ip4/1.2.3.4/udp/4001/quic-v1"
+		tcpAddr   = "/ip4/1.2.3.4/tcp/4001"
+		relayAddr = "/ip4/5.6.7.8/tcp/4001/p2p/relay/p2p-circuit"
+	)
+	s, err := NewSwarm("local-peer")
+	require.NoError(t, err)
+	defer s.Close()
+	ps := peerstore.NewPeerstore()
+	s.SetPeerstore(ps)
+
+	addrs := []string{quicAddr, tcpAddr, relayAddr}
+
+	// 未通告能力时保持原样
+	assert.Equal(t, addrs, s.filterAddrsByCapabilities("remote-peer", addrs))
+
+	caps := types.NewPeerCapabilities()
+	caps.Transports = []string{"quic-v1"}
+	require.NoError(t, ps.Put("remote-peer", types.PeerCapabilitiesKey, caps))
+	assert.Equal(t, []string{quicAddr, relayAddr}, s.filterAddrsByCapabilities("remote-peer", addrs))
+
+	// 过滤后为空时保持原样
+	assert.Equal(t, []string{tcpAddr}, s.filterAddrsByCapabilities("remote-peer", []string{tcpAddr}))
+}
@@ -5,6 +5,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/dep2p/go-dep2p/internal/core/connmgr"
+	"github.com/dep2p/go-dep2p/internal/core/reputation"
 	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
 	"github.com/dep2p/go-dep2p/pkg/types"
 	"github.com/stretchr/testify/assert"
@@ -46,6 +48,43 @@ func TestSwarm_DialPeer_Timeout(t *testing.T) {
 	assert.ErrorIs(t, err, ErrNoAddresses)
 }
 
+// TestSwarm_DialPeer_Gated 测试连接门控拒绝拨号
+func TestSwarm_DialPeer_Gated(t *testing.T) {
+	s, err := NewSwarm("test-peer")
+	require.NoError(t, err)
+	defer s.Close()
+
+	gater := connmgr.NewGater()
+	gater.BlockPeer("blocked-peer")
+	s.SetConnGater(gater)
+
+	_, err = s.DialPeer(context.Background(), "blocked-peer")
+	assert.ErrorIs(t, err, ErrDialGated)
+
+	// 未被阻止的节点照常拨号
+	_, err = s.DialPeer(context.Background(), "other-peer")
+	assert.ErrorIs(t, err, ErrNoAddresses)
+}
+
+// TestSwarm_DialPeer_ReportsReputation 测试拨号结果上报信誉服务
+func TestSwarm_DialPeer_ReportsReputation(t *testing.T) {
+	s, err := NewSwarm("test-peer")
+	require.NoError(t, err)
+	defer s.Close()
+
+	rep, err := reputation.New(pkgif.DefaultReputationConfig(), nil)
+	require.NoError(t, err)
+	s.SetReputation(rep)
+
+	_, err = s.DialPeer(context.Background(), "remote-peer")
+	require.Error(t, err)
+	assert.Less(t, rep.Score("remote-peer"), 0.0)
+
+	s.resetDialFailures("remote-peer")
+	assert.Equal(t, pkgif.ReputationSignalDial, rep.Get("remote-peer").Reasons[0].Signal)
+	assert.Equal(t, "dial_succeeded", rep.Get("remote-peer").Reasons[0].Reason)
+}
+
 // TestSwarm_DialPeer_ConnectionReuse 测试连接复用
 func TestSwarm_DialPeer_ConnectionReuse(t *testing.T) {
 	s, err := NewSwarm("test-peer")
@@ -13,7 +13,7 @@
 //
 // 拨号调度：
 //   - 智能地址排序（优先本地、优先 QUIC）
-//   - 并发拨号（Dial Many）
+//   - 分级错峰拨号（Happy Eyeballs，可替换 DialRanker）
 //   - 拨号超时与重试
 //
 // 监听管理：
@@ -125,6 +125,8 @@
 //   - DialTimeoutLocal: 本地网络拨号超时（默认 5s）
 //   - NewStreamTimeout: 创建流超时（默认 15s）
 //   - MaxConcurrentDials: 最大并发拨号数（默认 100）
+//   - AddrDialDelay: 同类地址之间的拨号间隔（默认 250ms）
+//   - ClassDialDelay: 进入下一类地址前的拨号间隔（默认 300ms）
 //   - ConnHealthInterval: 连接健康检测间隔（默认 30s，设为 0 禁用）
 //   - ConnHealthTimeout: 单次健康检测超时（默认 10s）
 //
@@ -160,14 +162,15 @@
 //
 // # 性能
 //
-// 并发拨号：
-//   - 同时尝试多个地址
-//   - 第一个成功的连接胜出
-//   - 其他拨号自动取消
+// 分级错峰拨号：
+//   - 地址先按路径健康度排序，再由 DialRanker 分配启动延迟
+//   - 默认顺序：局域网 QUIC > 局域网 TCP > 公网 QUIC > 公网 TCP > 中继，
+//     同类地址交错 IPv6/IPv4
+//   - 第一个成功的连接胜出，其他拨号取消，晚到的连接直接关闭
+//   - 拨号失败且无进行中的拨号时立即尝试下一个地址
+//   - 每个地址的结果记录在 DialError.Attempts 中，被取消的拨号不计入路径健康度
 //
-// 地址优先级：
-//   - 本地网络 > QUIC > TCP
-//   - 减少延迟，提高成功率
+// 避免同时拨号全部地址冲击 NAT 映射表和资源管理器配额。
 //
 // 连接复用：
 //   - 每个节点可以有多个连接
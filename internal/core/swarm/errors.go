@@ -32,12 +32,18 @@ var (
 
 	// ErrNoRelayAvailable 没有可用的 Relay
 	ErrNoRelayAvailable = errors.New("no relay available")
+
+	// ErrDialGated 连接门控拒绝拨号（节点被阻止或被信誉封禁）
+	ErrDialGated = errors.New("dial refused by connection gater")
 )
 
 // DialError 拨号错误，包含多个地址的错误信息
 type DialError struct {
 	Peer   string
 	Errors []error
+
+	// Attempts 每个地址的拨号记录（按计划启动顺序）
+	Attempts []DialAttempt
 }
 
 func (e *DialError) Error() string {
@@ -126,6 +126,14 @@ func (s *Swarm) acceptConn(transportConn pkgif.Connection) {
 
 	// 使用 truncateID 安全截断 PeerID，避免长度不足时 panic
 	peerLabel := truncateID(peerID, 8)
+
+	// 检查连接门控（被阻止或被信誉封禁的节点）
+	if gater := s.getGater(); gater != nil && !gater.InterceptSecured(pkgif.DirInbound, peerID, transportConn) {
+		logger.Debug("连接门控拒绝入站连接", "peerID", peerLabel)
+		transportConn.Close()
+		return
+	}
+
 	logger.Debug("接受新连接", "peerID", peerLabel)
 
 	// 封装为 Swarm 连接
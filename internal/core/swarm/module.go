@@ -45,7 +45,12 @@ type SwarmParams struct {
 	ConnMgr           pkgif.ConnManager       `optional:"true"`
 	EventBus          pkgif.EventBus          `optional:"true"`
 	BandwidthCounter  pkgif.BandwidthCounter  `optional:"true"`
+	BandwidthLimiter  pkgif.BandwidthLimiter  `optional:"true"`
+	DataBudget        pkgif.DataBudget        `optional:"true"`
 	PathHealthManager pkgif.PathHealthManager `optional:"true"` // Phase 0 修复：路径健康管理
+	DialRanker        pkgif.DialRanker        `optional:"true"` // 自定义拨号地址排序
+	ConnGater         pkgif.ConnGater         `optional:"true"` // 连接门控
+	Reputation        pkgif.Reputation        `optional:"true"` // 节点信誉
 }
 
 // ConfigFromUnified 从统一配置创建 Swarm 配置
@@ -62,6 +67,8 @@ func ConfigFromUnified(cfg *config.Config) *Config {
 		DialTimeoutLocal:   cfg.Transport.DialTimeout.Duration() / 3, // 本地拨号更快
 		NewStreamTimeout:   cfg.Transport.DialTimeout.Duration(),
 		MaxConcurrentDials: 100, // 默认值
+		AddrDialDelay:      cfg.Transport.DialAddrDelay.Duration(),
+		ClassDialDelay:     cfg.Transport.DialClassDelay.Duration(),
 
 		// 连接健康检测配置
 		// 使用默认配置的值，确保健康检测正常工作
@@ -76,7 +83,7 @@ func NewSwarmFromParams(params SwarmParams) (pkgif.Swarm, error) {
 	cfg := ConfigFromUnified(params.UnifiedCfg)
 
 	// 创建 Swarm
-	s, err := NewSwarm(params.LocalPeer, WithConfig(cfg))
+	s, err := NewSwarm(params.LocalPeer, WithConfig(cfg), WithDialRanker(params.DialRanker))
 	if err != nil {
 		return nil, err
 	}
@@ -120,10 +127,30 @@ func NewSwarmFromParams(params SwarmParams) (pkgif.Swarm, error) {
 		s.SetBandwidthCounter(params.BandwidthCounter)
 	}
 
+	// 设置 BandwidthLimiter
+	if params.BandwidthLimiter != nil {
+		s.SetBandwidthLimiter(params.BandwidthLimiter)
+	}
+
+	// 设置 DataBudget
+	if params.DataBudget != nil {
+		s.SetDataBudget(params.DataBudget)
+	}
+
 	// Phase 0 修复：设置 PathHealthManager
 	if params.PathHealthManager != nil {
 		s.SetPathHealthManager(params.PathHealthManager)
 	}
 
+	// 设置连接门控
+	if params.ConnGater != nil {
+		s.SetConnGater(params.ConnGater)
+	}
+
+	// 设置信誉服务
+	if params.Reputation != nil {
+		s.SetReputation(params.Reputation)
+	}
+
 	return s, nil
 }
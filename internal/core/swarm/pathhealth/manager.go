@@ -24,6 +24,9 @@ type Manager struct {
 	// peerID -> addr -> *Path
 	peerPaths map[string]map[string]*Path
 
+	// 信誉服务（可选，按最佳路径状态上报信号）
+	reputation interfaces.Reputation
+
 	// 运行状态
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -183,6 +186,7 @@ func (m *Manager) ReportProbe(peerID string, addr string, rtt time.Duration, err
 	}
 
 	path.RecordProbe(rtt, err)
+	m.observeReputation(peerID)
 
 	if err != nil {
 		logger.Debug("路径探测失败",
@@ -197,6 +201,54 @@ func (m *Manager) ReportProbe(peerID string, addr string, rtt time.Duration, err
 	}
 }
 
+// 路径状态对应的信誉信号水平
+const (
+	healthyPathSignal = 0.5
+	suspectPathSignal = -0.3
+	deadPathSignal    = -1.0
+)
+
+// SetReputation 设置信誉服务
+//
+// 设置后每次探测按节点最佳路径的状态上报信誉信号：
+// 存在健康路径为正，仅剩可疑路径为负，全部死亡为 -1。
+func (m *Manager) SetReputation(reputation interfaces.Reputation) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reputation = reputation
+}
+
+// observeReputation 按最佳路径状态上报信誉信号
+func (m *Manager) observeReputation(peerID string) {
+	m.mu.RLock()
+	rep := m.reputation
+	if rep == nil {
+		m.mu.RUnlock()
+		return
+	}
+	var healthy, suspect, dead int
+	for _, path := range m.peerPaths[peerID] {
+		switch path.GetState() {
+		case interfaces.PathStateHealthy:
+			healthy++
+		case interfaces.PathStateSuspect:
+			suspect++
+		case interfaces.PathStateDead:
+			dead++
+		}
+	}
+	m.mu.RUnlock()
+
+	switch {
+	case healthy > 0:
+		rep.Observe(peerID, interfaces.ReputationSignalPathHealth, healthyPathSignal, "healthy")
+	case suspect > 0:
+		rep.Observe(peerID, interfaces.ReputationSignalPathHealth, suspectPathSignal, "suspect")
+	case dead > 0:
+		rep.Observe(peerID, interfaces.ReputationSignalPathHealth, deadPathSignal, "dead")
+	}
+}
+
 // ReportHandshake 报告握手结果
 func (m *Manager) ReportHandshake(peerID string, addr string, rtt time.Duration, err error) {
 	// 握手结果与探测结果处理相同
@@ -327,8 +379,8 @@ func (m *Manager) RankAddrs(peerID string, addrs []string) []string {
 		scores[i] = addrScore{addr: addr, score: score}
 	}
 
-	// 按评分排序（越低越好）
-	sort.Slice(scores, func(i, j int) bool {
+	// 按评分排序（越低越好），评分相同时保持输入顺序
+	sort.SliceStable(scores, func(i, j int) bool {
 		return scores[i].score < scores[j].score
 	})
 
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/dep2p/go-dep2p/internal/core/reputation"
 	"github.com/dep2p/go-dep2p/pkg/interfaces"
 )
 
@@ -464,3 +465,36 @@ func TestManager_OnNetworkChange(t *testing.T) {
 		t.Errorf("expected ConsecutiveFailures to be reset, got %d", stats.ConsecutiveFailures)
 	}
 }
+
+// TestManager_ReportsReputation 测试按最佳路径状态上报信誉信号
+func TestManager_ReportsReputation(t *testing.T) {
+	config := DefaultConfig()
+	config.DeadFailureThreshold = 2
+	manager := NewManager(config)
+
+	rep, err := reputation.New(interfaces.DefaultReputationConfig(), nil)
+	if err != nil {
+		t.Fatalf("reputation.New failed: %v", err)
+	}
+	manager.SetReputation(rep)
+
+	peerID := "test-peer-1"
+	addr := "/ip4/192.168.1.1/udp/4001/quic-v1"
+
+	manager.ReportProbe(peerID, addr, 10*time.Millisecond, nil)
+	if got := rep.Score(peerID); got <= 0 {
+		t.Errorf("expected positive score for healthy path, got %v", got)
+	}
+
+	probeErr := errors.New("connection timeout")
+	manager.ReportProbe(peerID, addr, 0, probeErr)
+	manager.ReportProbe(peerID, addr, 0, probeErr)
+
+	reasons := rep.Get(peerID).Reasons
+	if len(reasons) != 1 || reasons[0].Reason != "dead" {
+		t.Fatalf("expected dead path reason, got %+v", reasons)
+	}
+	if got := rep.Score(peerID); got >= 0 {
+		t.Errorf("expected negative score for dead path, got %v", got)
+	}
+}
@@ -13,6 +13,7 @@ func Module() fx.Option {
 	return fx.Module("pathhealth",
 		fx.Provide(ProvideManager),
 		fx.Invoke(registerLifecycle),
+		fx.Invoke(registerReputation),
 	)
 }
 
@@ -45,3 +46,20 @@ func registerLifecycle(input lifecycleInput) {
 		},
 	})
 }
+
+// reputationInput 信誉服务输入参数
+type reputationInput struct {
+	fx.In
+	Manager    interfaces.PathHealthManager
+	Reputation interfaces.Reputation `optional:"true"`
+}
+
+// registerReputation 将信誉服务注入路径健康管理器
+func registerReputation(input reputationInput) {
+	if input.Reputation == nil {
+		return
+	}
+	if m, ok := input.Manager.(*Manager); ok {
+		m.SetReputation(input.Reputation)
+	}
+}
@@ -1,43 +1,128 @@
 package swarm
 
 import (
+	"context"
+	"errors"
+	"os"
+	"sync/atomic"
 	"time"
 
 	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
 	"github.com/dep2p/go-dep2p/pkg/types"
 )
 
+// limitChunkSize 限速时单次写入的分片大小
+//
+// 大块写入按分片获取配额，与其他流轮流发送。
+const limitChunkSize = 16 * 1024
+
 // SwarmStream Swarm 流封装
 type SwarmStream struct {
 	conn     *SwarmConn
 	stream   pkgif.Stream // 底层流
 	protocol string       // 协商后的协议 ID
+
+	// 限速等待的上下文，流关闭或重置时取消
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// 读写截止时间（UnixNano，0 表示无截止时间），限速等待同样受其约束
+	readDeadline  atomic.Int64
+	writeDeadline atomic.Int64
 }
 
 // newSwarmStream 创建 Swarm 流
 func newSwarmStream(conn *SwarmConn, stream pkgif.Stream) *SwarmStream {
+	ctx, cancel := context.WithCancel(context.Background())
 	return &SwarmStream{
 		conn:     conn,
 		stream:   stream,
 		protocol: "",
+		ctx:      ctx,
+		cancel:   cancel,
 	}
 }
 
 // Read 读取数据
+//
+// 启用限速时，读到数据后等待下载配额再返回，由流量控制向对端施加背压。
 func (s *SwarmStream) Read(p []byte) (n int, err error) {
 	n, err = s.stream.Read(p)
 	if n > 0 {
+		peer := string(s.conn.RemotePeer())
+		proto := s.Protocol()
 		if bw := s.conn.getBandwidthCounter(); bw != nil {
-			peer := string(s.conn.RemotePeer())
-			proto := s.Protocol()
 			bw.LogRecvStream(int64(n), proto, peer)
 		}
+		if bl := s.conn.getBandwidthLimiter(); bl != nil {
+			if werr := s.waitN(bl, &s.readDeadline, pkgif.DirInbound, n, proto, peer); werr != nil && err == nil {
+				err = werr
+			}
+		}
 	}
 	return n, err
 }
 
 // Write 写入数据
+//
+// 启用限速时按 limitChunkSize 分片，每片写入前等待上传配额。
 func (s *SwarmStream) Write(p []byte) (n int, err error) {
+	bl := s.conn.getBandwidthLimiter()
+	if bl == nil {
+		return s.write(p)
+	}
+
+	peer := string(s.conn.RemotePeer())
+	proto := s.Protocol()
+	for n < len(p) {
+		end := min(n+limitChunkSize, len(p))
+		if err = s.waitN(bl, &s.writeDeadline, pkgif.DirOutbound, end-n, proto, peer); err != nil {
+			return n, err
+		}
+		m, err := s.write(p[n:end])
+		n += m
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// waitN 等待限速配额
+//
+// 设置了截止时间时等待不超过截止时间，超时返回 os.ErrDeadlineExceeded，
+// 与底层流的超时错误一致。
+func (s *SwarmStream) waitN(bl pkgif.BandwidthLimiter, deadline *atomic.Int64, dir pkgif.Direction, n int, proto, peer string) error {
+	d := deadline.Load()
+	if d == 0 {
+		return bl.WaitN(s.ctx, dir, n, proto, peer)
+	}
+
+	t := time.Unix(0, d)
+	if !time.Now().Before(t) {
+		return os.ErrDeadlineExceeded
+	}
+	ctx, cancel := context.WithDeadline(s.ctx, t)
+	defer cancel()
+
+	err := bl.WaitN(ctx, dir, n, proto, peer)
+	if err != nil && s.ctx.Err() == nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return os.ErrDeadlineExceeded
+	}
+	return err
+}
+
+// storeDeadline 记录截止时间
+func storeDeadline(deadline *atomic.Int64, t time.Time) {
+	if t.IsZero() {
+		deadline.Store(0)
+		return
+	}
+	deadline.Store(t.UnixNano())
+}
+
+// write 写入底层流并记录流量
+func (s *SwarmStream) write(p []byte) (n int, err error) {
 	n, err = s.stream.Write(p)
 	if n > 0 {
 		if bw := s.conn.getBandwidthCounter(); bw != nil {
@@ -53,6 +138,7 @@ func (s *SwarmStream) Write(p []byte) (n int, err error) {
 func (s *SwarmStream) Close() error {
 	// 从连接中移除
 	s.conn.removeStream(s)
+	s.cancelWait()
 	
 	// 关闭底层流
 	return s.stream.Close()
@@ -81,9 +167,17 @@ func (s *SwarmStream) Conn() pkgif.Connection {
 
 // Reset 重置流
 func (s *SwarmStream) Reset() error {
+	s.cancelWait()
 	return s.stream.Reset()
 }
 
+// cancelWait 取消正在进行的限速等待
+func (s *SwarmStream) cancelWait() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
 // CloseWrite 关闭写端（半关闭）
 //
 // 发送 FIN 信号告知对方"我已发送完毕"，但仍可读取对方的数据。
@@ -100,16 +194,20 @@ func (s *SwarmStream) CloseRead() error {
 
 // SetDeadline 设置读写超时
 func (s *SwarmStream) SetDeadline(t time.Time) error {
+	storeDeadline(&s.readDeadline, t)
+	storeDeadline(&s.writeDeadline, t)
 	return s.stream.SetDeadline(t)
 }
 
 // SetReadDeadline 设置读超时
 func (s *SwarmStream) SetReadDeadline(t time.Time) error {
+	storeDeadline(&s.readDeadline, t)
 	return s.stream.SetReadDeadline(t)
 }
 
 // SetWriteDeadline 设置写超时
 func (s *SwarmStream) SetWriteDeadline(t time.Time) error {
+	storeDeadline(&s.writeDeadline, t)
 	return s.stream.SetWriteDeadline(t)
 }
 
@@ -2,7 +2,9 @@ package swarm
 
 import (
 	"context"
+	"errors"
 	"io"
+	"os"
 	"testing"
 	"time"
 
@@ -668,3 +670,116 @@ func TestSwarmStream_Close(t *testing.T) {
 
 	t.Log("✅ Close 正常工作")
 }
+
+// TestSwarmStream_BandwidthLimit 测试 SwarmStream 按限速器配额写入
+func TestSwarmStream_BandwidthLimit(t *testing.T) {
+	swarm, err := NewSwarm("test-peer")
+	if err != nil {
+		t.Fatalf("NewSwarm failed: %v", err)
+	}
+	defer swarm.Close()
+
+	// 上传 64KB/s，突发 16KB：写入 48KB 至少等待 0.5 秒
+	limiter := bandwidth.NewLimiter(pkgif.BandwidthLimits{
+		Protocols: map[string]pkgif.BandwidthLimit{
+			"/test/protocol/1.0": {Out: 64 * 1024, Burst: 16 * 1024},
+		},
+	})
+	swarm.SetBandwidthLimiter(limiter)
+
+	mockConn := &mockConnection{remotePeer: types.PeerID("remote-peer-123")}
+	limited := &mockStream{protocol: "/test/protocol/1.0", conn: mockConn}
+	swarmStream := newSwarmStream(newSwarmConn(swarm, mockConn), limited)
+
+	start := time.Now()
+	n, err := swarmStream.Write(make([]byte, 48*1024))
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if n != 48*1024 || len(limited.writeData) != 48*1024 {
+		t.Errorf("Write() = %d, written %d, want %d", n, len(limited.writeData), 48*1024)
+	}
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Errorf("Write took %v, want >= 400ms", elapsed)
+	}
+
+	// 其他协议不受限
+	other := newSwarmStream(newSwarmConn(swarm, mockConn), &mockStream{protocol: "/other/1.0", conn: mockConn})
+	start = time.Now()
+	if _, err := other.Write(make([]byte, 48*1024)); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("unlimited Write took %v", elapsed)
+	}
+
+	// 关闭流取消等待
+	limiter.SetLimits(pkgif.BandwidthLimits{Total: pkgif.BandwidthLimit{Out: 1024}})
+	blocked := newSwarmStream(newSwarmConn(swarm, mockConn), &mockStream{protocol: "/test/protocol/1.0", conn: mockConn})
+	blocked.Write(make([]byte, 1024)) // 耗尽突发容量
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		blocked.Reset()
+	}()
+	if _, err := blocked.Write(make([]byte, 1024)); err == nil {
+		t.Error("Write should fail after Reset")
+	}
+}
+
+// TestSwarmStream_BandwidthLimitDeadline 测试限速等待遵守读写截止时间
+func TestSwarmStream_BandwidthLimitDeadline(t *testing.T) {
+	swarm, err := NewSwarm("test-peer")
+	if err != nil {
+		t.Fatalf("NewSwarm failed: %v", err)
+	}
+	defer swarm.Close()
+
+	// 1KB/s：耗尽突发容量后再读写 1KB 需要等待约 1 秒
+	limiter := bandwidth.NewLimiter(pkgif.BandwidthLimits{
+		Total: pkgif.BandwidthLimit{In: 1024, Out: 1024},
+	})
+	swarm.SetBandwidthLimiter(limiter)
+
+	mockConn := &mockConnection{remotePeer: types.PeerID("remote-peer-123")}
+
+	// 写截止时间
+	w := newSwarmStream(newSwarmConn(swarm, mockConn), &mockStream{protocol: "/test/protocol/1.0", conn: mockConn})
+	w.Write(make([]byte, 1024))
+	w.SetWriteDeadline(time.Now().Add(50 * time.Millisecond))
+	start := time.Now()
+	if _, err := w.Write(make([]byte, 1024)); !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Errorf("Write() error = %v, want os.ErrDeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Write took %v, want to stop at the deadline", elapsed)
+	}
+
+	// 截止时间已过时不再等待
+	if _, err := w.Write(make([]byte, 1024)); !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Errorf("Write() error = %v, want os.ErrDeadlineExceeded", err)
+	}
+
+	// 读截止时间（SetDeadline 同时设置读写）
+	r := newSwarmStream(newSwarmConn(swarm, mockConn), &mockStream{
+		protocol: "/test/protocol/1.0",
+		conn:     mockConn,
+		readData: make([]byte, 2048),
+	})
+	buf := make([]byte, 1024)
+	r.Read(buf)
+	r.SetDeadline(time.Now().Add(50 * time.Millisecond))
+	start = time.Now()
+	n, err := r.Read(buf)
+	if n != 1024 || !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Errorf("Read() = %d, %v, want 1024, os.ErrDeadlineExceeded", n, err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Read took %v, want to stop at the deadline", elapsed)
+	}
+
+	// 清除截止时间后恢复为等待配额
+	r.SetDeadline(time.Time{})
+	if r.readDeadline.Load() != 0 || r.writeDeadline.Load() != 0 {
+		t.Error("zero deadline should clear the wait deadline")
+	}
+}
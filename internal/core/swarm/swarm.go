@@ -10,6 +10,7 @@ import (
 
 	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
 	"github.com/dep2p/go-dep2p/pkg/lib/log"
+	"github.com/dep2p/go-dep2p/pkg/lib/tracing"
 	"github.com/dep2p/go-dep2p/pkg/types"
 )
 
@@ -45,7 +46,14 @@ type Swarm struct {
 	connmgr           pkgif.ConnManager
 	eventbus          pkgif.EventBus
 	bandwidth         pkgif.BandwidthCounter
+	bandwidthLimiter  pkgif.BandwidthLimiter
+	dataBudget        pkgif.DataBudget        // 数据预算（计费网络下放慢健康检测）
 	pathHealthManager pkgif.PathHealthManager // Phase 0 修复：路径健康管理
+	gater             pkgif.ConnGater         // 连接门控（拒绝被阻止或封禁的节点）
+	reputation        pkgif.Reputation        // 节点信誉（记录拨号结果）
+
+	// 拨号地址排序器（nil 时按配置的错峰延迟使用默认排序）
+	dialRanker pkgif.DialRanker
 
 	// Relay 惰性回退支持（v2.0 统一接口）
 	// 当直连失败时，通过此接口尝试 Relay 连接
@@ -202,6 +210,13 @@ func (s *Swarm) DialPeer(ctx context.Context, peerID string) (pkgif.Connection,
 		return nil, ErrDialToSelf
 	}
 
+	// 复用已有连接不记录拨号 Span
+	if conns := s.ConnsToPeer(peerID); len(conns) > 0 {
+		return conns[0], nil
+	}
+
+	ctx, span := tracing.Start(ctx, "dep2p.swarm.dial", tracing.KindClient, tracing.Attr("peer.id", peerID))
+
 	// 调用完整的拨号逻辑（在 dial.go 中实现）
 	// dialPeer 会：
 	//  1. 检查已有连接复用
@@ -213,7 +228,13 @@ func (s *Swarm) DialPeer(ctx context.Context, peerID string) (pkgif.Connection,
 	conn, err := s.dialPeer(ctx, peerID)
 	if err != nil {
 		logger.Debug("拨号失败", "peerID", truncateID(peerID, 8), "error", err)
+	} else {
+		span.SetAttributes(tracing.Attr("conn.type", conn.ConnType().String()))
+		if addr := conn.RemoteMultiaddr(); addr != nil {
+			span.SetAttributes(tracing.Attr("net.peer.addr", addr.String()))
+		}
 	}
+	span.End(err)
 	return conn, err
 }
 
@@ -631,6 +652,11 @@ func (s *Swarm) connHealthCheckLoop(ctx context.Context) {
 			logger.Debug("连接健康检测循环已停止")
 			return
 		case <-ticker.C:
+			// 计费网络下按数据预算跳过部分检测
+			if budget := s.DataBudget(); budget != nil &&
+				!budget.Allow(pkgif.BudgetSubsystemLiveness, "conn_health", s.config.ConnHealthInterval) {
+				continue
+			}
 			s.checkAllConnections(ctx)
 		}
 	}
@@ -0,0 +1,109 @@
+package mocknet
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock 链路模型使用的时钟
+//
+// 延迟、带宽、抖动和丢包重传都按 Clock 计算到达时间并等待。测试注入
+// ManualClock 后，链路时间只随 Advance 推进，与墙上时钟无关。
+// 连接的读写截止时间由调用方按墙上时钟设置，不受 Clock 影响。
+type Clock interface {
+	// Now 返回当前时间
+	Now() time.Time
+
+	// After 返回在 d 之后收到当前时间的通道，以及释放等待的函数
+	After(d time.Duration) (<-chan time.Time, func())
+}
+
+// realClock 墙上时钟
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) After(d time.Duration) (<-chan time.Time, func()) {
+	t := time.NewTimer(d)
+	return t.C, func() { t.Stop() }
+}
+
+// ManualClock 手动推进的时钟
+//
+// 时间只在调用 Advance 时前进，到期的等待者按到期时间顺序被唤醒。
+type ManualClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*manualWaiter
+}
+
+type manualWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+// 确保实现接口
+var _ Clock = (*ManualClock)(nil)
+
+// NewManualClock 创建从 start 开始的手动时钟
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{now: start}
+}
+
+// Now 返回当前时间
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After 返回在 d 之后收到当前时间的通道
+func (c *ManualClock) After(d time.Duration) (<-chan time.Time, func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	w := &manualWaiter{at: c.now.Add(d), ch: make(chan time.Time, 1)}
+	if d <= 0 {
+		w.ch <- c.now
+		return w.ch, func() {}
+	}
+	c.waiters = append(c.waiters, w)
+	return w.ch, func() { c.remove(w) }
+}
+
+// Advance 将时间推进 d，唤醒所有已到期的等待者
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	sort.SliceStable(c.waiters, func(i, j int) bool { return c.waiters[i].at.Before(c.waiters[j].at) })
+	kept := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			kept = append(kept, w)
+			continue
+		}
+		w.ch <- c.now
+	}
+	c.waiters = kept
+}
+
+// Waiters 返回正在等待的数量，测试用它判断链路是否已进入等待
+func (c *ManualClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+func (c *ManualClock) remove(w *manualWaiter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, x := range c.waiters {
+		if x == w {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package mocknet
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/types"
+)
+
+// 确保实现接口
+var (
+	_ pkgif.Connection = (*connection)(nil)
+	_ pkgif.Stream     = (*stream)(nil)
+)
+
+// connection 升级后的模拟网络连接
+type connection struct {
+	pkgif.UpgradedConn
+	localPeer  types.PeerID
+	localAddr  types.Multiaddr
+	remoteAddr types.Multiaddr
+	direction  pkgif.Direction
+	opened     time.Time
+
+	mu      sync.RWMutex
+	streams []pkgif.Stream
+}
+
+func newConnection(upgraded pkgif.UpgradedConn, localPeer types.PeerID, localAddr, remoteAddr types.Multiaddr, dir pkgif.Direction) *connection {
+	return &connection{
+		UpgradedConn: upgraded,
+		localPeer:    localPeer,
+		localAddr:    localAddr,
+		remoteAddr:   remoteAddr,
+		direction:    dir,
+		opened:       time.Now(),
+	}
+}
+
+// LocalPeer 返回本地节点 ID
+func (c *connection) LocalPeer() types.PeerID {
+	return c.localPeer
+}
+
+// LocalMultiaddr 返回本地多地址
+func (c *connection) LocalMultiaddr() types.Multiaddr {
+	return c.localAddr
+}
+
+// RemoteMultiaddr 返回远端多地址
+//
+// 入站连接返回经过 NAT 转换后的来源地址。
+func (c *connection) RemoteMultiaddr() types.Multiaddr {
+	return c.remoteAddr
+}
+
+// NewStream 创建新流
+func (c *connection) NewStream(ctx context.Context) (pkgif.Stream, error) {
+	muxed, err := c.OpenStream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.track(muxed, types.DirOutbound), nil
+}
+
+// NewStreamWithPriority 创建新流（模拟网络不支持优先级，忽略参数）
+func (c *connection) NewStreamWithPriority(ctx context.Context, _ int) (pkgif.Stream, error) {
+	return c.NewStream(ctx)
+}
+
+// SupportsStreamPriority 模拟网络连接不支持流优先级
+func (c *connection) SupportsStreamPriority() bool {
+	return false
+}
+
+// AcceptStream 接受新流
+func (c *connection) AcceptStream() (pkgif.Stream, error) {
+	muxed, err := c.UpgradedConn.AcceptStream()
+	if err != nil {
+		return nil, err
+	}
+	return c.track(muxed, types.DirInbound), nil
+}
+
+func (c *connection) track(muxed pkgif.MuxedStream, dir types.Direction) pkgif.Stream {
+	s := &stream{MuxedStream: muxed, conn: c, direction: dir, opened: time.Now()}
+	c.mu.Lock()
+	c.streams = append(c.streams, s)
+	c.mu.Unlock()
+	return s
+}
+
+// GetStreams 获取所有流
+func (c *connection) GetStreams() []pkgif.Stream {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]pkgif.Stream(nil), c.streams...)
+}
+
+// Stat 返回连接统计
+func (c *connection) Stat() pkgif.ConnectionStat {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return pkgif.ConnectionStat{
+		Direction:  c.direction,
+		Opened:     c.opened.Unix(),
+		NumStreams: len(c.streams),
+	}
+}
+
+// ConnType 模拟网络连接始终为直连
+func (c *connection) ConnType() pkgif.ConnectionType {
+	return pkgif.ConnectionTypeDirect
+}
+
+// stream 模拟网络连接上的流
+type stream struct {
+	pkgif.MuxedStream
+	conn      *connection
+	direction types.Direction
+	opened    time.Time
+
+	mu       sync.RWMutex
+	protocol string
+}
+
+// Conn 返回所属连接
+func (s *stream) Conn() pkgif.Connection {
+	return s.conn
+}
+
+// Protocol 返回协议 ID
+func (s *stream) Protocol() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.protocol
+}
+
+// SetProtocol 设置协议 ID
+func (s *stream) SetProtocol(protocol string) {
+	s.mu.Lock()
+	s.protocol = protocol
+	s.mu.Unlock()
+}
+
+// Stat 返回流统计
+func (s *stream) Stat() types.StreamStat {
+	return types.StreamStat{
+		Direction: s.direction,
+		Opened:    s.opened,
+		Protocol:  types.ProtocolID(s.Protocol()),
+	}
+}
+
+// IsClosed 检查流是否已关闭（以连接状态为准）
+func (s *stream) IsClosed() bool {
+	return s.conn.IsClosed()
+}
+
+// State 返回流当前状态
+func (s *stream) State() types.StreamState {
+	if s.conn.IsClosed() {
+		return types.StreamStateClosed
+	}
+	return types.StreamStateOpen
+}
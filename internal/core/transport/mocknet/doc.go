@@ -0,0 +1,42 @@
+// Package mocknet 实现内存模拟网络传输
+//
+// mocknet 在进程内模拟主机、链路、NAT 和网络分区，用于测试连接管理、
+// Relay、打洞和重连逻辑。连接建立后仍交给真实的 Upgrader 完成安全握手
+// 与多路复用，只替换最底层的字节传输。
+//
+// # 特性
+//
+//   - 链路参数：延迟、抖动、带宽、丢包（按重传延迟补发，保持可靠有序）
+//   - NAT：完全锥形、受限锥形、端口受限锥形、对称型（RFC 4787 语义）
+//   - 运行时断开链路、分区与恢复，已有连接被立即重置
+//   - 固定随机种子时抖动与丢包的随机序列可复现
+//
+// 链路延迟、带宽整形、重传和握手重试按 Config.Clock 计时，默认使用墙上
+// 时钟。通过 WithClock 注入 ManualClock 后，链路时间只随 Advance 推进，
+// 到达时间与丢包序列完全由种子和推进步长决定，测试不再依赖真实等待。
+// 连接的读写截止时间始终按墙上时钟判断。
+//
+// # 地址格式
+//
+// 与 TCP 相同，公网主机分配 11.0.0.0/8 地址，NAT 后的主机分配 10.0.0.0/8 地址：
+//
+//	/ip4/11.0.0.1/tcp/4001
+//
+// # 使用示例
+//
+//	mn := mocknet.New(mocknet.WithSeed(1))
+//	nat, _ := mn.NewNAT(types.NATTypePortRestricted, "")
+//	hostA, _ := mn.NewHost()
+//	hostB, _ := mn.NewHost(mocknet.BehindNAT(nat))
+//
+//	mn.Link(hostA, hostB, mocknet.LinkOptions{Latency: 20 * time.Millisecond})
+//
+//	transport := hostA.NewTransport(localPeer, upgrader)
+//	addr, _ := types.NewMultiaddr(hostA.Addr(4001))
+//	listener, err := transport.Listen(addr)
+//
+//	mn.Partition([]*mocknet.Host{hostA}, []*mocknet.Host{hostB})
+//	mn.Heal()
+//
+// 节点级测试通过 transport.Factory 接入，参见 tests/testutil/mocknet.go。
+package mocknet
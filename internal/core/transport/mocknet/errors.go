@@ -0,0 +1,36 @@
+package mocknet
+
+import "errors"
+
+// 错误定义
+var (
+	// ErrTransportClosed 传输已关闭
+	ErrTransportClosed = errors.New("mocknet: transport closed")
+
+	// ErrListenerClosed 监听器已关闭
+	ErrListenerClosed = errors.New("mocknet: listener closed")
+
+	// ErrInvalidAddr 地址无效（需要 /ip4|ip6/.../tcp/...）
+	ErrInvalidAddr = errors.New("mocknet: invalid address")
+
+	// ErrAddrInUse 地址已被占用
+	ErrAddrInUse = errors.New("mocknet: address already in use")
+
+	// ErrAddrInvalidForHost 监听地址不属于本主机
+	ErrAddrInvalidForHost = errors.New("mocknet: address not assigned to host")
+
+	// ErrNoRoute 目标地址不可路由（例如 NAT 后的私网地址）
+	ErrNoRoute = errors.New("mocknet: no route to host")
+
+	// ErrConnRefused 目标端口没有监听器
+	ErrConnRefused = errors.New("mocknet: connection refused")
+
+	// ErrDialTimeout 拨号超时（链路断开、分区、NAT 过滤或丢包）
+	ErrDialTimeout = errors.New("mocknet: dial timeout")
+
+	// ErrConnReset 连接被重置（链路断开或分区）
+	ErrConnReset = errors.New("mocknet: connection reset")
+
+	// ErrDuplicateIP IP 已被其他主机或 NAT 使用
+	ErrDuplicateIP = errors.New("mocknet: duplicate ip")
+)
@@ -0,0 +1,543 @@
+package mocknet
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dep2p/go-dep2p/internal/core/identity"
+	"github.com/dep2p/go-dep2p/internal/core/muxer"
+	"github.com/dep2p/go-dep2p/internal/core/security/tls"
+	"github.com/dep2p/go-dep2p/internal/core/upgrader"
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/types"
+)
+
+// ============================================================================
+//                              测试辅助
+// ============================================================================
+
+func mustHost(t *testing.T, n *Network, opts ...HostOption) *Host {
+	t.Helper()
+	h, err := n.NewHost(opts...)
+	require.NoError(t, err)
+	return h
+}
+
+func mustNAT(t *testing.T, n *Network, typ types.NATType) *NAT {
+	t.Helper()
+	nat, err := n.NewNAT(typ, "")
+	require.NoError(t, err)
+	return nat
+}
+
+// listenRaw 在主机上注册不做升级的监听器，返回接收原始连接的通道
+func listenRaw(t *testing.T, h *Host, port int) chan *rawConn {
+	t.Helper()
+	l := &Listener{
+		transport: h.NewTransport("", nil),
+		addr:      &net.TCPAddr{IP: h.ip, Port: port},
+		raw:       make(chan *rawConn, acceptBacklog),
+		upgraded:  make(chan pkgif.Connection),
+		done:      make(chan struct{}),
+	}
+	h.net.mu.Lock()
+	h.listeners[port] = l
+	h.net.mu.Unlock()
+	t.Cleanup(func() { l.Close() })
+	return l.raw
+}
+
+func dialRaw(n *Network, src *Host, dst string, timeout time.Duration) (*rawConn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	addr, err := net.ResolveTCPAddr("tcp", dst)
+	if err != nil {
+		return nil, err
+	}
+	if v4 := addr.IP.To4(); v4 != nil {
+		addr.IP = v4
+	}
+	return n.dial(ctx, src, addr)
+}
+
+func hostPort(ip net.IP, port int) string {
+	return net.JoinHostPort(ip.String(), strconv.Itoa(port))
+}
+
+func newUpgrader(t *testing.T) (types.PeerID, pkgif.Upgrader) {
+	t.Helper()
+	id, err := identity.Generate()
+	require.NoError(t, err)
+	sec, err := tls.New(id)
+	require.NoError(t, err)
+	up, err := upgrader.New(id, upgrader.Config{
+		SecurityTransports: []pkgif.SecureTransport{sec},
+		StreamMuxers:       []pkgif.StreamMuxer{muxer.NewTransport()},
+	})
+	require.NoError(t, err)
+	return types.PeerID(id.PeerID()), up
+}
+
+// ============================================================================
+//                              数据通道
+// ============================================================================
+
+func TestPipe_ReadWriteClose(t *testing.T) {
+	n := New()
+	a, b := mustHost(t, n), mustHost(t, n)
+	accepted := listenRaw(t, b, 4001)
+
+	c, err := dialRaw(n, a, hostPort(b.IP(), 4001), time.Second)
+	require.NoError(t, err)
+	s := <-accepted
+
+	assert.Equal(t, a.IP().String(), s.RemoteAddr().(*net.TCPAddr).IP.String())
+
+	_, err = c.Write([]byte("hello"))
+	require.NoError(t, err)
+	buf := make([]byte, 16)
+	nr, err := s.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(buf[:nr]))
+
+	// 关闭后对端读完剩余数据再收到 EOF
+	_, err = c.Write([]byte("bye"))
+	require.NoError(t, err)
+	require.NoError(t, c.Close())
+	data, err := io.ReadAll(s)
+	require.NoError(t, err)
+	assert.Equal(t, "bye", string(data))
+
+	_, err = c.Read(buf)
+	assert.ErrorIs(t, err, net.ErrClosed)
+	assert.Eventually(t, func() bool { return n.ConnCount() == 0 }, time.Second, 10*time.Millisecond)
+}
+
+func TestPipe_ReadDeadline(t *testing.T) {
+	n := New()
+	a, b := mustHost(t, n), mustHost(t, n)
+	accepted := listenRaw(t, b, 4001)
+
+	c, err := dialRaw(n, a, hostPort(b.IP(), 4001), time.Second)
+	require.NoError(t, err)
+	<-accepted
+
+	require.NoError(t, c.SetReadDeadline(time.Now().Add(50*time.Millisecond)))
+	_, err = c.Read(make([]byte, 1))
+	assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+}
+
+func TestPipe_LatencyAndBandwidth(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	n := New(WithClock(clock), WithDefaultLink(LinkOptions{
+		Latency:   50 * time.Millisecond,
+		Bandwidth: 100 * 1024, // 100 KiB/s
+	}))
+	a, b := mustHost(t, n), mustHost(t, n)
+	accepted := listenRaw(t, b, 4001)
+
+	// 建立连接耗时一个往返
+	dialed := make(chan *rawConn, 1)
+	go func() {
+		c, err := dialRaw(n, a, hostPort(b.IP(), 4001), 5*time.Second)
+		assert.NoError(t, err)
+		dialed <- c
+	}()
+	waitForWaiter(t, clock)
+	clock.Advance(100*time.Millisecond - time.Nanosecond)
+	assert.Never(t, func() bool { return len(dialed) > 0 }, 20*time.Millisecond, time.Millisecond)
+	clock.Advance(time.Nanosecond)
+	c := <-dialed
+	require.NotNil(t, c)
+	s := <-accepted
+
+	// 20 KiB 在 100 KiB/s 上发送耗时 200ms，加上 50ms 延迟后全部到达
+	payload := make([]byte, 20*1024)
+	_, err := c.Write(payload)
+	require.NoError(t, err)
+
+	var received atomic.Int64
+	go func() {
+		buf := make([]byte, len(payload))
+		for {
+			nr, err := s.Read(buf)
+			received.Add(int64(nr))
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	clock.Advance(250*time.Millisecond - time.Nanosecond)
+	last := int64(len(payload) % segmentSize)
+	assert.Eventually(t, func() bool { return received.Load() == int64(len(payload))-last }, time.Second, time.Millisecond)
+	assert.Never(t, func() bool { return received.Load() == int64(len(payload)) }, 20*time.Millisecond, time.Millisecond)
+	clock.Advance(time.Nanosecond)
+	assert.Eventually(t, func() bool { return received.Load() == int64(len(payload)) }, time.Second, time.Millisecond)
+}
+
+func TestPipe_LossIsReliable(t *testing.T) {
+	payload := make([]byte, 10*segmentSize)
+	for i := range payload {
+		payload[i] = byte(i)
+	}
+
+	// send 在固定种子的有损链路上写入数据，返回每个分段的到达时间（相对写入时刻）
+	send := func() (*rawConn, *ManualClock, []time.Duration) {
+		clock := NewManualClock(time.Unix(0, 0))
+		n := New(WithSeed(7), WithClock(clock), WithDefaultLink(LinkOptions{Loss: 0.2, Jitter: 5 * time.Millisecond}))
+		a, b := mustHost(t, n), mustHost(t, n)
+		accepted := listenRaw(t, b, 4001)
+
+		// 握手报文可能被丢弃，按重试间隔推进时钟直到拨号成功
+		dialed := make(chan *rawConn, 1)
+		go func() {
+			c, err := dialRaw(n, a, hostPort(b.IP(), 4001), 10*time.Second)
+			assert.NoError(t, err)
+			dialed <- c
+		}()
+		var c *rawConn
+		for c == nil {
+			select {
+			case c = <-dialed:
+			case <-time.After(time.Millisecond):
+				if clock.Waiters() > 0 {
+					clock.Advance(n.config.DialRetryInterval)
+				}
+			}
+		}
+		s := <-accepted
+
+		base := clock.Now()
+		_, err := c.Write(payload)
+		require.NoError(t, err)
+
+		c.out.mu.Lock()
+		arrivals := make([]time.Duration, len(c.out.segs))
+		for i, seg := range c.out.segs {
+			arrivals[i] = seg.at.Sub(base)
+		}
+		c.out.mu.Unlock()
+		return s, clock, arrivals
+	}
+
+	s, clock, arrivals := send()
+	_, _, again := send()
+	assert.Equal(t, arrivals, again, "相同种子下分段到达时间可复现")
+	require.Len(t, arrivals, 10)
+	assert.Greater(t, arrivals[len(arrivals)-1], 5*time.Millisecond, "丢失的分段按重传延迟补发")
+
+	clock.Advance(arrivals[len(arrivals)-1])
+	got := make([]byte, len(payload))
+	_, err := io.ReadFull(s, got)
+	require.NoError(t, err)
+	assert.Equal(t, payload, got, "丢包后数据仍然完整有序")
+}
+
+// waitForWaiter 等待链路进入时钟等待
+func waitForWaiter(t *testing.T, clock *ManualClock) {
+	t.Helper()
+	require.Eventually(t, func() bool { return clock.Waiters() > 0 }, time.Second, time.Millisecond)
+}
+
+// ============================================================================
+//                              链路与分区
+// ============================================================================
+
+func TestNetwork_UnlinkResetsConns(t *testing.T) {
+	n := New(WithDialRetryInterval(10 * time.Millisecond))
+	a, b := mustHost(t, n), mustHost(t, n)
+	accepted := listenRaw(t, b, 4001)
+
+	c, err := dialRaw(n, a, hostPort(b.IP(), 4001), time.Second)
+	require.NoError(t, err)
+	s := <-accepted
+
+	n.Unlink(a, b)
+	_, err = s.Read(make([]byte, 1))
+	assert.ErrorIs(t, err, ErrConnReset)
+	_, err = c.Write([]byte("x"))
+	assert.ErrorIs(t, err, ErrConnReset)
+
+	_, ok := n.LinkOptions(a, b)
+	assert.False(t, ok)
+
+	// 链路断开时拨号超时
+	_, err = dialRaw(n, a, hostPort(b.IP(), 4001), 100*time.Millisecond)
+	assert.ErrorIs(t, err, ErrDialTimeout)
+
+	// 恢复链路
+	n.Link(a, b, LinkOptions{})
+	_, err = dialRaw(n, a, hostPort(b.IP(), 4001), time.Second)
+	assert.NoError(t, err)
+}
+
+func TestNetwork_AutoLinkDisabled(t *testing.T) {
+	n := New(WithAutoLink(false), WithDialRetryInterval(10*time.Millisecond))
+	a, b := mustHost(t, n), mustHost(t, n)
+	listenRaw(t, b, 4001)
+
+	_, err := dialRaw(n, a, hostPort(b.IP(), 4001), 50*time.Millisecond)
+	assert.ErrorIs(t, err, ErrDialTimeout)
+
+	n.LinkAll(LinkOptions{})
+	_, err = dialRaw(n, a, hostPort(b.IP(), 4001), time.Second)
+	assert.NoError(t, err)
+}
+
+func TestNetwork_Partition(t *testing.T) {
+	n := New(WithDialRetryInterval(10 * time.Millisecond))
+	a, b, c := mustHost(t, n), mustHost(t, n), mustHost(t, n)
+	acceptB := listenRaw(t, b, 4001)
+	listenRaw(t, c, 4001)
+
+	conn, err := dialRaw(n, a, hostPort(b.IP(), 4001), time.Second)
+	require.NoError(t, err)
+	<-acceptB
+
+	// {a} | {b, c}
+	n.Partition([]*Host{a}, []*Host{b, c})
+	_, err = conn.Read(make([]byte, 1))
+	assert.ErrorIs(t, err, ErrConnReset)
+
+	_, err = dialRaw(n, a, hostPort(c.IP(), 4001), 50*time.Millisecond)
+	assert.ErrorIs(t, err, ErrDialTimeout)
+	_, err = dialRaw(n, b, hostPort(c.IP(), 4001), time.Second)
+	assert.NoError(t, err)
+
+	n.Heal()
+	_, err = dialRaw(n, a, hostPort(c.IP(), 4001), time.Second)
+	assert.NoError(t, err)
+}
+
+func TestNetwork_Errors(t *testing.T) {
+	n := New()
+	a := mustHost(t, n)
+	b := mustHost(t, n)
+
+	_, err := dialRaw(n, a, hostPort(b.IP(), 4001), time.Second)
+	assert.ErrorIs(t, err, ErrConnRefused)
+
+	_, err = dialRaw(n, a, "1.2.3.4:4001", time.Second)
+	assert.ErrorIs(t, err, ErrNoRoute)
+
+	_, err = n.NewHost(WithIP(a.IP().String()))
+	assert.ErrorIs(t, err, ErrDuplicateIP)
+
+	tr := a.NewTransport("", nil)
+	_, err = tr.Listen(toMultiaddr(&net.TCPAddr{IP: b.IP(), Port: 1}))
+	assert.ErrorIs(t, err, ErrAddrInvalidForHost)
+	_, err = tr.Listen(toMultiaddr(&net.TCPAddr{IP: a.IP(), Port: 1}))
+	require.NoError(t, err)
+	_, err = tr.Listen(toMultiaddr(&net.TCPAddr{IP: a.IP(), Port: 1}))
+	assert.ErrorIs(t, err, ErrAddrInUse)
+}
+
+// ============================================================================
+//                              NAT
+// ============================================================================
+
+func TestNAT_OutboundTranslation(t *testing.T) {
+	n := New()
+	nat := mustNAT(t, n, types.NATTypePortRestricted)
+	priv := mustHost(t, n, BehindNAT(nat))
+	pub := mustHost(t, n)
+	accepted := listenRaw(t, pub, 4001)
+
+	assert.True(t, priv.IP().IsPrivate())
+	assert.False(t, pub.IP().IsPrivate())
+
+	_, err := dialRaw(n, priv, hostPort(pub.IP(), 4001), time.Second)
+	require.NoError(t, err)
+	s := <-accepted
+
+	observed := s.RemoteAddr().(*net.TCPAddr)
+	assert.True(t, observed.IP.Equal(nat.PublicIP()), "对端看到 NAT 公网地址")
+	assert.Equal(t, 1, nat.Mappings())
+
+	// NAT 后的私网地址从外部不可达
+	_, err = dialRaw(n, pub, hostPort(priv.IP(), 4001), time.Second)
+	assert.ErrorIs(t, err, ErrNoRoute)
+}
+
+// natScenario 内部主机先连接观察者，然后由外部主机直连其映射地址
+func natScenario(t *testing.T, typ types.NATType, fromObserver bool) error {
+	n := New(WithDialRetryInterval(10 * time.Millisecond))
+	nat := mustNAT(t, n, typ)
+	priv := mustHost(t, n, BehindNAT(nat))
+	observer := mustHost(t, n)
+	other := mustHost(t, n)
+	listenRaw(t, priv, 4001)
+	acceptObs := listenRaw(t, observer, 4001)
+	listenRaw(t, other, 4001)
+
+	_, err := dialRaw(n, priv, hostPort(observer.IP(), 4001), time.Second)
+	require.NoError(t, err)
+	mapped := (<-acceptObs).RemoteAddr().(*net.TCPAddr)
+
+	src := other
+	if fromObserver {
+		src = observer
+	}
+	_, err = dialRaw(n, src, mapped.String(), 100*time.Millisecond)
+	return err
+}
+
+func TestNAT_Filtering(t *testing.T) {
+	// 完全锥形：任何外部主机都能连入映射地址
+	assert.NoError(t, natScenario(t, types.NATTypeFullCone, false))
+
+	// 受限锥形：同一地址的其他端口可以连入，其他地址不行
+	assert.ErrorIs(t, natScenario(t, types.NATTypeRestrictedCone, false), ErrDialTimeout)
+	assert.NoError(t, natScenario(t, types.NATTypeRestrictedCone, true))
+
+	// 端口受限锥形：只有曾经连接过的地址:端口可以连入
+	assert.ErrorIs(t, natScenario(t, types.NATTypePortRestricted, false), ErrDialTimeout)
+	assert.NoError(t, natScenario(t, types.NATTypePortRestricted, true))
+}
+
+func TestNAT_SymmetricMapping(t *testing.T) {
+	n := New()
+	nat := mustNAT(t, n, types.NATTypeSymmetric)
+	priv := mustHost(t, n, BehindNAT(nat))
+	a, b := mustHost(t, n), mustHost(t, n)
+	acceptA := listenRaw(t, a, 4001)
+	acceptB := listenRaw(t, b, 4001)
+
+	_, err := dialRaw(n, priv, hostPort(a.IP(), 4001), time.Second)
+	require.NoError(t, err)
+	_, err = dialRaw(n, priv, hostPort(b.IP(), 4001), time.Second)
+	require.NoError(t, err)
+
+	portA := (<-acceptA).RemoteAddr().(*net.TCPAddr).Port
+	portB := (<-acceptB).RemoteAddr().(*net.TCPAddr).Port
+	assert.NotEqual(t, portA, portB, "对称型 NAT 为每个目标分配不同端口")
+}
+
+// TestNAT_HolePunch 两个端口受限锥形 NAT 后的主机同时拨号打通连接
+func TestNAT_HolePunch(t *testing.T) {
+	n := New(WithDialRetryInterval(10 * time.Millisecond))
+	natA := mustNAT(t, n, types.NATTypePortRestricted)
+	natB := mustNAT(t, n, types.NATTypePortRestricted)
+	a := mustHost(t, n, BehindNAT(natA))
+	b := mustHost(t, n, BehindNAT(natB))
+	observer := mustHost(t, n)
+	listenRaw(t, a, 4001)
+	listenRaw(t, b, 4001)
+	acceptObs := listenRaw(t, observer, 4001)
+
+	// 双方先连接观察者，得到各自的映射地址
+	_, err := dialRaw(n, a, hostPort(observer.IP(), 4001), time.Second)
+	require.NoError(t, err)
+	mappedA := (<-acceptObs).RemoteAddr().String()
+	_, err = dialRaw(n, b, hostPort(observer.IP(), 4001), time.Second)
+	require.NoError(t, err)
+	mappedB := (<-acceptObs).RemoteAddr().String()
+
+	// 单方拨号被过滤
+	_, err = dialRaw(n, a, mappedB, 50*time.Millisecond)
+	require.ErrorIs(t, err, ErrDialTimeout)
+
+	// 同时拨号：双方的出站报文在各自 NAT 上打开了过滤规则
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, errs[0] = dialRaw(n, a, mappedB, time.Second)
+	}()
+	go func() {
+		defer wg.Done()
+		_, errs[1] = dialRaw(n, b, mappedA, time.Second)
+	}()
+	wg.Wait()
+	assert.NoError(t, errs[0])
+	assert.NoError(t, errs[1])
+}
+
+// ============================================================================
+//                              传输（真实升级器）
+// ============================================================================
+
+func TestTransport_UpgradeAndStreams(t *testing.T) {
+	n := New(WithDefaultLink(LinkOptions{Latency: 5 * time.Millisecond}))
+	ha, hb := mustHost(t, n), mustHost(t, n)
+	peerA, upA := newUpgrader(t)
+	peerB, upB := newUpgrader(t)
+
+	ta := ha.NewTransport(peerA, upA)
+	tb := hb.NewTransport(peerB, upB)
+	defer ta.Close()
+	defer tb.Close()
+
+	l, err := tb.Listen(toMultiaddr(&net.TCPAddr{IP: net.IPv4zero, Port: 0}))
+	require.NoError(t, err)
+	assert.Contains(t, l.Addr().String(), hb.IP().String())
+	assert.True(t, ta.CanDial(l.Addr()))
+
+	accepted := make(chan pkgif.Connection, 1)
+	go func() {
+		c, err := l.Accept()
+		if err == nil {
+			accepted <- c
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	conn, err := ta.Dial(ctx, l.Addr(), peerB)
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.Equal(t, peerB, conn.RemotePeer())
+
+	var server pkgif.Connection
+	select {
+	case server = <-accepted:
+	case <-ctx.Done():
+		t.Fatal("accept timeout")
+	}
+	assert.Equal(t, peerA, server.RemotePeer())
+	assert.Equal(t, pkgif.DirInbound, server.Stat().Direction)
+
+	go func() {
+		s, err := server.AcceptStream()
+		if err != nil {
+			return
+		}
+		io.Copy(s, s)
+		s.Close()
+	}()
+
+	s, err := conn.NewStream(ctx)
+	require.NoError(t, err)
+	_, err = s.Write([]byte("ping"))
+	require.NoError(t, err)
+	buf := make([]byte, 4)
+	_, err = io.ReadFull(s, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(buf))
+
+	// 断开链路后连接失效
+	n.Unlink(ha, hb)
+	_, err = s.Write([]byte("x"))
+	if err == nil {
+		_, err = s.Read(buf)
+	}
+	assert.Error(t, err)
+
+	// 关闭后不能再拨号
+	require.NoError(t, ta.Close())
+	_, err = ta.Dial(ctx, l.Addr(), peerB)
+	assert.True(t, errors.Is(err, ErrTransportClosed))
+}
@@ -0,0 +1,109 @@
+package mocknet
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/dep2p/go-dep2p/pkg/types"
+)
+
+// natPortBase NAT 公网端口分配起点
+const natPortBase = 20000
+
+// NAT 模拟的 NAT 设备
+//
+// 映射与过滤行为按 NAT 类型区分（RFC 4787 术语）：
+//
+//   - FullCone：端点无关映射，无过滤
+//   - RestrictedCone：端点无关映射，按地址过滤
+//   - PortRestricted：端点无关映射，按地址和端口过滤
+//   - Symmetric：端点相关映射（每个目标一个公网端口），按地址和端口过滤
+//
+// 映射在内部主机首次向外发起连接时创建，之后一直有效。
+type NAT struct {
+	net      *Network
+	typ      types.NATType
+	publicIP net.IP
+
+	// 以下字段由 net.mu 保护
+	mappings map[string]*natMapping // 映射键 -> 映射
+	byPort   map[int]*natMapping    // 公网端口 -> 映射
+	nextPort int
+}
+
+// natMapping NAT 映射
+type natMapping struct {
+	private    *net.TCPAddr
+	publicPort int
+	allowed    map[string]struct{} // 允许入站的来源（地址或地址:端口）
+}
+
+// Type 返回 NAT 类型
+func (n *NAT) Type() types.NATType {
+	return n.typ
+}
+
+// PublicIP 返回 NAT 公网地址
+func (n *NAT) PublicIP() net.IP {
+	return n.publicIP
+}
+
+// Mappings 返回当前映射数量
+func (n *NAT) Mappings() int {
+	n.net.mu.Lock()
+	defer n.net.mu.Unlock()
+	return len(n.byPort)
+}
+
+// outbound 内部主机向 dst 发起连接，返回转换后的公网端点（调用方持有 net.mu）
+func (n *NAT) outbound(private, dst *net.TCPAddr) *net.TCPAddr {
+	key := private.String()
+	if n.typ == types.NATTypeSymmetric {
+		key += "|" + dst.String()
+	}
+
+	m, ok := n.mappings[key]
+	if !ok {
+		m = &natMapping{
+			private:    private,
+			publicPort: n.nextPort,
+			allowed:    make(map[string]struct{}),
+		}
+		n.nextPort++
+		n.mappings[key] = m
+		n.byPort[m.publicPort] = m
+	}
+
+	// 记录过滤规则
+	m.allowed[dst.IP.String()] = struct{}{}
+	m.allowed[dst.String()] = struct{}{}
+
+	return &net.TCPAddr{IP: n.publicIP, Port: m.publicPort}
+}
+
+// inbound 外部来源 src 连接公网端口 port，返回内部端点（调用方持有 net.mu）
+//
+// 没有映射或被过滤时返回 nil（报文被丢弃）。
+func (n *NAT) inbound(src *net.TCPAddr, port int) *net.TCPAddr {
+	m, ok := n.byPort[port]
+	if !ok {
+		return nil
+	}
+
+	switch n.typ {
+	case types.NATTypeFullCone:
+	case types.NATTypeRestrictedCone:
+		if _, ok := m.allowed[src.IP.String()]; !ok {
+			return nil
+		}
+	default:
+		if _, ok := m.allowed[src.String()]; !ok {
+			return nil
+		}
+	}
+	return m.private
+}
+
+func (n *NAT) String() string {
+	return fmt.Sprintf("NAT(%s, %s)", n.typ, n.publicIP)
+}
@@ -0,0 +1,639 @@
+package mocknet
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/dep2p/go-dep2p/pkg/lib/log"
+	"github.com/dep2p/go-dep2p/pkg/types"
+)
+
+var logger = log.Logger("core/transport/mocknet")
+
+// 地址分配
+var (
+	// publicBase 公网主机和 NAT 公网地址的分配起点
+	//
+	// 使用 11.0.0.0/8 而不是文档地址段，文档地址段会被可达性模块当作非公网地址。
+	publicBase = net.IPv4(11, 0, 0, 0)
+
+	// privateBase NAT 后主机地址的分配起点
+	privateBase = net.IPv4(10, 0, 0, 0)
+)
+
+// ephemeralPortBase 主机临时端口分配起点
+const ephemeralPortBase = 40000
+
+// LinkOptions 链路参数
+type LinkOptions struct {
+	// Latency 单向延迟
+	Latency time.Duration
+
+	// Jitter 额外的随机延迟上限
+	Jitter time.Duration
+
+	// Bandwidth 带宽（字节/秒，0 表示不限）
+	Bandwidth int64
+
+	// Loss 丢包率（0~1）
+	//
+	// 连接是可靠的字节流，丢失的分段在重传超时后补发，表现为延迟增加；
+	// 建立连接时丢失的握手报文表现为拨号重试。
+	Loss float64
+}
+
+// Config 模拟网络配置
+type Config struct {
+	// Seed 随机数种子，相同种子得到相同的丢包与抖动序列
+	Seed int64
+
+	// DefaultLink 未显式配置的主机对之间使用的链路参数
+	DefaultLink LinkOptions
+
+	// AutoLink 未显式配置的主机对是否默认连通
+	AutoLink bool
+
+	// DialRetryInterval 握手报文丢失后的重试间隔
+	DialRetryInterval time.Duration
+
+	// Clock 链路模型的时钟（nil 表示墙上时钟）
+	Clock Clock
+}
+
+// DefaultConfig 返回默认配置
+func DefaultConfig() Config {
+	return Config{
+		Seed:              1,
+		AutoLink:          true,
+		DialRetryInterval: 100 * time.Millisecond,
+	}
+}
+
+// Option 配置选项
+type Option func(*Config)
+
+// WithSeed 设置随机数种子
+func WithSeed(seed int64) Option {
+	return func(c *Config) {
+		c.Seed = seed
+	}
+}
+
+// WithDefaultLink 设置默认链路参数
+func WithDefaultLink(opts LinkOptions) Option {
+	return func(c *Config) {
+		c.DefaultLink = opts
+	}
+}
+
+// WithAutoLink 设置主机对是否默认连通
+//
+// 关闭后只有通过 Link 显式连接的主机之间可以通信。
+func WithAutoLink(enable bool) Option {
+	return func(c *Config) {
+		c.AutoLink = enable
+	}
+}
+
+// WithDialRetryInterval 设置握手重试间隔
+func WithDialRetryInterval(d time.Duration) Option {
+	return func(c *Config) {
+		if d > 0 {
+			c.DialRetryInterval = d
+		}
+	}
+}
+
+// WithClock 设置链路模型的时钟
+//
+// 注入 ManualClock 后，延迟、带宽、丢包重传和握手重试只随 Advance 推进。
+func WithClock(clock Clock) Option {
+	return func(c *Config) {
+		c.Clock = clock
+	}
+}
+
+// linkKey 主机对（id 小的在前）
+type linkKey struct {
+	a, b int
+}
+
+func keyOf(a, b *Host) linkKey {
+	if a.id > b.id {
+		a, b = b, a
+	}
+	return linkKey{a.id, b.id}
+}
+
+// linkState 显式配置的链路
+type linkState struct {
+	up   bool
+	opts LinkOptions
+}
+
+// connPair 网络中的一条原始连接
+type connPair struct {
+	a, b         *Host
+	aConn, bConn *rawConn
+}
+
+// Network 模拟网络
+//
+// Network 是内存中的网络控制器：管理主机、主机之间的链路、NAT 设备和
+// 网络分区。主机上的 Transport 实现 pkgif.Transport，连接建立后使用
+// 真实的 Upgrader（安全握手 + 多路复用），因此 Swarm、Relay、打洞等
+// 上层代码运行在与真实网络相同的路径上。
+type Network struct {
+	mu sync.Mutex
+
+	config Config
+	rng    *rand.Rand
+
+	hosts     []*Host
+	byIP      map[string]*Host
+	nats      map[string]*NAT
+	links     map[linkKey]*linkState
+	partition map[int]int // 主机 id -> 分组；nil 表示没有分区
+	conns     map[*connPair]struct{}
+
+	nextPublic  uint32
+	nextPrivate uint32
+}
+
+// New 创建模拟网络
+func New(opts ...Option) *Network {
+	cfg := DefaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = realClock{}
+	}
+	return &Network{
+		config: cfg,
+		rng:    rand.New(rand.NewSource(cfg.Seed)),
+		byIP:   make(map[string]*Host),
+		nats:   make(map[string]*NAT),
+		links:  make(map[linkKey]*linkState),
+		conns:  make(map[*connPair]struct{}),
+	}
+}
+
+// HostOption 主机选项
+type HostOption func(*hostOptions)
+
+type hostOptions struct {
+	ip  net.IP
+	nat *NAT
+}
+
+// WithIP 指定主机地址
+func WithIP(ip string) HostOption {
+	return func(o *hostOptions) {
+		o.ip = net.ParseIP(ip)
+	}
+}
+
+// BehindNAT 将主机放在 NAT 设备之后
+func BehindNAT(n *NAT) HostOption {
+	return func(o *hostOptions) {
+		o.nat = n
+	}
+}
+
+// NewHost 创建主机
+//
+// 未指定地址时自动分配：公网主机从 11.0.0.0/8 分配，NAT 后主机从
+// 10.0.0.0/8 分配。
+func (n *Network) NewHost(opts ...HostOption) (*Host, error) {
+	var o hostOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	ip := o.ip
+	if ip == nil {
+		if o.nat != nil {
+			ip = n.allocIP(privateBase, &n.nextPrivate)
+		} else {
+			ip = n.allocIP(publicBase, &n.nextPublic)
+		}
+	}
+	if err := n.checkIPFree(ip); err != nil {
+		return nil, err
+	}
+
+	h := &Host{
+		net:       n,
+		id:        len(n.hosts),
+		ip:        ip,
+		nat:       o.nat,
+		listeners: make(map[int]*Listener),
+		nextPort:  ephemeralPortBase,
+	}
+	n.hosts = append(n.hosts, h)
+	n.byIP[ip.String()] = h
+	return h, nil
+}
+
+// NewNAT 创建 NAT 设备
+//
+// publicIP 为空时自动分配公网地址。
+func (n *Network) NewNAT(typ types.NATType, publicIP string) (*NAT, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var ip net.IP
+	if publicIP == "" {
+		ip = n.allocIP(publicBase, &n.nextPublic)
+	} else if ip = net.ParseIP(publicIP); ip == nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAddr, publicIP)
+	}
+	if err := n.checkIPFree(ip); err != nil {
+		return nil, err
+	}
+
+	nat := &NAT{
+		net:      n,
+		typ:      typ,
+		publicIP: ip,
+		mappings: make(map[string]*natMapping),
+		byPort:   make(map[int]*natMapping),
+		nextPort: natPortBase,
+	}
+	n.nats[ip.String()] = nat
+	return nat, nil
+}
+
+// allocIP 分配下一个未使用的地址（调用方持有 n.mu）
+func (n *Network) allocIP(base net.IP, counter *uint32) net.IP {
+	for {
+		*counter++
+		v := binary.BigEndian.Uint32(base.To4()) + *counter
+		ip := make(net.IP, 4)
+		binary.BigEndian.PutUint32(ip, v)
+		if ip[3] == 0 || ip[3] == 255 {
+			continue
+		}
+		if n.checkIPFree(ip) == nil {
+			return ip
+		}
+	}
+}
+
+// checkIPFree 检查地址是否未被占用（调用方持有 n.mu）
+func (n *Network) checkIPFree(ip net.IP) error {
+	if _, ok := n.byIP[ip.String()]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateIP, ip)
+	}
+	if _, ok := n.nats[ip.String()]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateIP, ip)
+	}
+	return nil
+}
+
+// Hosts 返回所有主机
+func (n *Network) Hosts() []*Host {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]*Host(nil), n.hosts...)
+}
+
+// ============================================================================
+//                              链路与分区
+// ============================================================================
+
+// Link 连接两台主机（或更新链路参数）
+func (n *Network) Link(a, b *Host, opts LinkOptions) {
+	n.mu.Lock()
+	n.links[keyOf(a, b)] = &linkState{up: true, opts: opts}
+	n.mu.Unlock()
+}
+
+// LinkAll 用相同参数连接所有主机
+func (n *Network) LinkAll(opts LinkOptions) {
+	n.mu.Lock()
+	for i, a := range n.hosts {
+		for _, b := range n.hosts[i+1:] {
+			n.links[keyOf(a, b)] = &linkState{up: true, opts: opts}
+		}
+	}
+	n.mu.Unlock()
+}
+
+// Unlink 断开两台主机之间的链路
+//
+// 两台主机之间已有的连接立即被重置，新的拨号将超时。
+func (n *Network) Unlink(a, b *Host) {
+	n.mu.Lock()
+	n.links[keyOf(a, b)] = &linkState{up: false}
+	severed := n.collectSevered()
+	n.mu.Unlock()
+
+	resetAll(severed)
+}
+
+// LinkOptions 返回两台主机之间的链路参数，链路断开时 ok 为 false
+func (n *Network) LinkOptions(a, b *Host) (opts LinkOptions, ok bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.linkBetween(a, b)
+}
+
+// Partition 将网络划分为互不连通的分组
+//
+// 未出现在任何分组中的主机归入一个额外的分组。跨分组的已有连接
+// 立即被重置。
+func (n *Network) Partition(groups ...[]*Host) {
+	n.mu.Lock()
+	n.partition = make(map[int]int)
+	for i, group := range groups {
+		for _, h := range group {
+			n.partition[h.id] = i + 1
+		}
+	}
+	severed := n.collectSevered()
+	n.mu.Unlock()
+
+	resetAll(severed)
+}
+
+// Heal 解除网络分区
+func (n *Network) Heal() {
+	n.mu.Lock()
+	n.partition = nil
+	n.mu.Unlock()
+}
+
+// linkBetween 返回两台主机之间的有效链路（调用方持有 n.mu）
+func (n *Network) linkBetween(a, b *Host) (LinkOptions, bool) {
+	if a == b {
+		return LinkOptions{}, true
+	}
+	if n.partition != nil && n.partition[a.id] != n.partition[b.id] {
+		return LinkOptions{}, false
+	}
+	if l, ok := n.links[keyOf(a, b)]; ok {
+		return l.opts, l.up
+	}
+	return n.config.DefaultLink, n.config.AutoLink
+}
+
+// collectSevered 取出所有链路已断开的连接（调用方持有 n.mu）
+func (n *Network) collectSevered() []*connPair {
+	var severed []*connPair
+	for c := range n.conns {
+		if _, up := n.linkBetween(c.a, c.b); !up {
+			severed = append(severed, c)
+			delete(n.conns, c)
+		}
+	}
+	return severed
+}
+
+func resetAll(pairs []*connPair) {
+	for _, c := range pairs {
+		c.aConn.reset()
+		c.bConn.reset()
+	}
+	if len(pairs) > 0 {
+		logger.Debug("链路断开，重置连接", "count", len(pairs))
+	}
+}
+
+// ConnCount 返回网络中的原始连接数
+func (n *Network) ConnCount() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.conns)
+}
+
+// ============================================================================
+//                              拨号
+// ============================================================================
+
+// route 路由结果
+type route struct {
+	dstHost  *Host
+	listener *Listener
+	local    *net.TCPAddr // 拨号方本地端点
+	observed *net.TCPAddr // 接收方看到的来源端点
+	target   *net.TCPAddr // 接收方本地端点
+}
+
+// errDropped 报文被丢弃（链路断开、分区、NAT 过滤或丢包），需要重试
+var errDropped = fmt.Errorf("mocknet: packet dropped")
+
+// resolve 计算从 src 到 dst 的路由（调用方持有 n.mu）
+func (n *Network) resolve(src *Host, dst *net.TCPAddr) (*route, error) {
+	local := &net.TCPAddr{IP: src.ip, Port: src.sourcePort()}
+	observed := local
+	var dstHost *Host
+	var target *net.TCPAddr
+
+	if h, ok := n.byIP[dst.IP.String()]; ok {
+		// 直接寻址主机：公网主机，或同一 NAT 后的局域网主机
+		if h.nat != nil && h.nat != src.nat {
+			return nil, ErrNoRoute
+		}
+		if src.nat != nil && src.nat != h.nat {
+			observed = src.nat.outbound(local, dst)
+		}
+		dstHost, target = h, dst
+	} else if nat, ok := n.nats[dst.IP.String()]; ok {
+		if src.nat != nil && src.nat != nat {
+			observed = src.nat.outbound(local, dst)
+		} else if src.nat == nat {
+			// 不支持 NAT 回环（hairpin）
+			return nil, ErrNoRoute
+		}
+		target = nat.inbound(observed, dst.Port)
+		if target == nil {
+			return nil, errDropped
+		}
+		dstHost = n.byIP[target.IP.String()]
+		if dstHost == nil {
+			return nil, errDropped
+		}
+	} else {
+		return nil, ErrNoRoute
+	}
+
+	opts, up := n.linkBetween(src, dstHost)
+	if !up {
+		return nil, errDropped
+	}
+	if opts.Loss > 0 && n.rng.Float64() < opts.Loss {
+		return nil, errDropped
+	}
+
+	l, ok := dstHost.listeners[target.Port]
+	if !ok || l.isClosed() {
+		return nil, ErrConnRefused
+	}
+
+	return &route{
+		dstHost:  dstHost,
+		listener: l,
+		local:    local,
+		observed: observed,
+		target:   target,
+	}, nil
+}
+
+// dial 从 src 向 dst 建立原始连接
+//
+// 报文被丢弃时按 DialRetryInterval 重试（模拟握手重传），直到 ctx 结束。
+func (n *Network) dial(ctx context.Context, src *Host, dst *net.TCPAddr) (*rawConn, error) {
+	for {
+		n.mu.Lock()
+		r, err := n.resolve(src, dst)
+		if err == nil {
+			opts, _ := n.linkBetween(src, r.dstHost)
+			n.mu.Unlock()
+
+			// 握手耗时一个往返
+			if rtt := 2 * opts.Latency; rtt > 0 {
+				timerC, stop := n.config.Clock.After(rtt)
+				select {
+				case <-ctx.Done():
+					stop()
+					return nil, fmt.Errorf("%w: %v", ErrDialTimeout, ctx.Err())
+				case <-timerC:
+				}
+			}
+			return n.connect(src, r)
+		}
+		n.mu.Unlock()
+
+		if err != errDropped {
+			return nil, err
+		}
+
+		timerC, stop := n.config.Clock.After(n.config.DialRetryInterval)
+		select {
+		case <-ctx.Done():
+			stop()
+			return nil, fmt.Errorf("%w: %v", ErrDialTimeout, ctx.Err())
+		case <-timerC:
+		}
+	}
+}
+
+// connect 创建连接并投递给监听器
+func (n *Network) connect(src *Host, r *route) (*rawConn, error) {
+	n.mu.Lock()
+	seed := n.rng.Int63()
+	n.mu.Unlock()
+
+	dstHost := r.dstHost
+	linkFn := func() (LinkOptions, bool) {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		return n.linkBetween(src, dstHost)
+	}
+
+	// 拨号方看到的远端地址是它拨号的地址；接收方看到的是转换后的来源地址
+	dialerConn, listenerConn := newConnPair(r.local, r.target, linkFn, n.config.Clock, seed)
+	listenerConn.local = r.target
+	listenerConn.remote = r.observed
+
+	pair := &connPair{a: src, b: dstHost, aConn: dialerConn, bConn: listenerConn}
+	remove := func() {
+		n.mu.Lock()
+		delete(n.conns, pair)
+		n.mu.Unlock()
+	}
+	dialerConn.onClose = remove
+	listenerConn.onClose = remove
+
+	n.mu.Lock()
+	n.conns[pair] = struct{}{}
+	n.mu.Unlock()
+
+	if !r.listener.deliver(listenerConn) {
+		remove()
+		return nil, ErrConnRefused
+	}
+	return dialerConn, nil
+}
+
+// ============================================================================
+//                              主机
+// ============================================================================
+
+// Host 模拟网络中的主机
+type Host struct {
+	net *Network
+	id  int
+	ip  net.IP
+	nat *NAT
+
+	// 以下字段由 net.mu 保护
+	listeners map[int]*Listener
+	nextPort  int
+}
+
+// IP 返回主机地址
+func (h *Host) IP() net.IP {
+	return h.ip
+}
+
+// NAT 返回主机所在的 NAT 设备（公网主机返回 nil）
+func (h *Host) NAT() *NAT {
+	return h.nat
+}
+
+// Network 返回主机所属网络
+func (h *Host) Network() *Network {
+	return h.net
+}
+
+// Addr 返回主机在指定端口上的多地址
+func (h *Host) Addr(port int) string {
+	proto := "ip4"
+	if h.ip.To4() == nil {
+		proto = "ip6"
+	}
+	return fmt.Sprintf("/%s/%s/tcp/%d", proto, h.ip, port)
+}
+
+func (h *Host) String() string {
+	return h.ip.String()
+}
+
+// sourcePort 返回出站连接使用的本地端口（调用方持有 net.mu）
+//
+// 有监听器时复用监听端口（与 QUIC 共用套接字一致），使 NAT 映射
+// 指向监听器，打洞后对端可以直接连入；否则分配临时端口。
+func (h *Host) sourcePort() int {
+	port := 0
+	for p, l := range h.listeners {
+		if !l.isClosed() && (port == 0 || p < port) {
+			port = p
+		}
+	}
+	if port != 0 {
+		return port
+	}
+	return h.allocPort()
+}
+
+// allocPort 分配临时端口（调用方持有 net.mu）
+func (h *Host) allocPort() int {
+	for {
+		port := h.nextPort
+		h.nextPort++
+		if _, used := h.listeners[port]; !used {
+			return port
+		}
+	}
+}
@@ -0,0 +1,336 @@
+package mocknet
+
+import (
+	"io"
+	"math/rand"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// segmentSize 分段大小，丢包按分段计算
+	segmentSize = 1400
+
+	// maxBuffered 单方向最大未读字节数，超过后写入阻塞（流量控制）
+	maxBuffered = 1 << 20
+
+	// minRetransmit 丢包后的最小重传延迟
+	minRetransmit = 200 * time.Millisecond
+)
+
+// segment 在途数据分段
+type segment struct {
+	data []byte
+	at   time.Time // 到达时间
+}
+
+// halfPipe 单方向的数据通道
+//
+// 写入的数据按链路参数计算到达时间：带宽决定发送耗时，延迟与抖动
+// 决定传播时间，丢包的分段按重传延迟补发。通道是可靠有序的（与 TCP
+// 一致），后写入的分段不会早于先写入的分段到达。
+type halfPipe struct {
+	link  func() (LinkOptions, bool)
+	clock Clock
+	rng   *rand.Rand
+
+	mu        sync.Mutex
+	segs      []segment
+	buffered  int
+	busyUntil time.Time
+	lastAt    time.Time
+	eof       bool  // 写端已关闭，读完后返回 EOF
+	rerr      error // 读端错误（重置或读端关闭）
+	werr      error // 写端错误
+	notify    chan struct{}
+}
+
+func newHalfPipe(link func() (LinkOptions, bool), clock Clock, seed int64) *halfPipe {
+	return &halfPipe{
+		link:   link,
+		clock:  clock,
+		rng:    rand.New(rand.NewSource(seed)),
+		notify: make(chan struct{}),
+	}
+}
+
+// signal 唤醒等待者（调用方持有 p.mu）
+func (p *halfPipe) signal() {
+	close(p.notify)
+	p.notify = make(chan struct{})
+}
+
+// write 写入数据
+func (p *halfPipe) write(b []byte, deadline func() time.Time) (int, error) {
+	written := 0
+	for written < len(b) {
+		p.mu.Lock()
+		if p.werr != nil {
+			err := p.werr
+			p.mu.Unlock()
+			return written, err
+		}
+		if p.buffered >= maxBuffered {
+			wait := p.notify
+			p.mu.Unlock()
+			if err := p.waitUntil(wait, time.Time{}, deadline()); err != nil {
+				return written, err
+			}
+			continue
+		}
+
+		opts, up := p.link()
+		if !up {
+			// 链路已断开：数据无法送达，连接随后会被重置
+			p.mu.Unlock()
+			return written, ErrConnReset
+		}
+
+		n := len(b) - written
+		if n > segmentSize {
+			n = segmentSize
+		}
+		if room := maxBuffered - p.buffered; n > room {
+			n = room
+		}
+		data := make([]byte, n)
+		copy(data, b[written:written+n])
+
+		start := p.clock.Now()
+		if p.busyUntil.After(start) {
+			start = p.busyUntil
+		}
+		if opts.Bandwidth > 0 {
+			start = start.Add(time.Duration(int64(n) * int64(time.Second) / opts.Bandwidth))
+		}
+		p.busyUntil = start
+
+		delay := opts.Latency
+		if opts.Jitter > 0 {
+			delay += time.Duration(p.rng.Int63n(int64(opts.Jitter)))
+		}
+		if opts.Loss > 0 {
+			// 可靠传输：丢失的分段在重传超时后补发
+			rto := 2*opts.Latency + minRetransmit
+			for p.rng.Float64() < opts.Loss {
+				delay += rto
+				rto *= 2
+			}
+		}
+		at := start.Add(delay)
+		if at.Before(p.lastAt) {
+			at = p.lastAt
+		}
+		p.lastAt = at
+
+		p.segs = append(p.segs, segment{data: data, at: at})
+		p.buffered += n
+		p.signal()
+		p.mu.Unlock()
+
+		written += n
+	}
+	return written, nil
+}
+
+// read 读取已到达的数据
+func (p *halfPipe) read(b []byte, deadline func() time.Time) (int, error) {
+	for {
+		p.mu.Lock()
+		if p.rerr != nil {
+			err := p.rerr
+			p.mu.Unlock()
+			return 0, err
+		}
+
+		var arrival time.Time
+		if len(p.segs) > 0 {
+			now := p.clock.Now()
+			if !now.Before(p.segs[0].at) {
+				n := 0
+				for n < len(b) && len(p.segs) > 0 && !now.Before(p.segs[0].at) {
+					head := &p.segs[0]
+					c := copy(b[n:], head.data)
+					n += c
+					head.data = head.data[c:]
+					if len(head.data) == 0 {
+						p.segs = p.segs[1:]
+					}
+				}
+				p.buffered -= n
+				p.signal()
+				p.mu.Unlock()
+				return n, nil
+			}
+			arrival = p.segs[0].at
+		} else if p.eof {
+			p.mu.Unlock()
+			return 0, io.EOF
+		}
+
+		wait := p.notify
+		p.mu.Unlock()
+		if err := p.waitUntil(wait, arrival, deadline()); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// closeWrite 关闭写端，对端读完剩余数据后返回 EOF
+func (p *halfPipe) closeWrite() {
+	p.mu.Lock()
+	if p.werr == nil {
+		p.werr = net.ErrClosed
+	}
+	p.eof = true
+	p.signal()
+	p.mu.Unlock()
+}
+
+// closeRead 关闭读端，丢弃未读数据，对端写入返回错误
+func (p *halfPipe) closeRead(readErr, writeErr error) {
+	p.mu.Lock()
+	if p.rerr == nil {
+		p.rerr = readErr
+	}
+	if p.werr == nil {
+		p.werr = writeErr
+	}
+	p.segs = nil
+	p.buffered = 0
+	p.signal()
+	p.mu.Unlock()
+}
+
+// waitUntil 等待通知、到达时间或截止时间
+//
+// 零值时间表示不限。到达时间按链路时钟等待，截止时间按墙上时钟等待，
+// 截止时间到达时返回 os.ErrDeadlineExceeded。
+func (p *halfPipe) waitUntil(notify <-chan struct{}, arrival, deadline time.Time) error {
+	var timerC, deadlineC <-chan time.Time
+	if !arrival.IsZero() {
+		c, stop := p.clock.After(arrival.Sub(p.clock.Now()))
+		defer stop()
+		timerC = c
+	}
+	if !deadline.IsZero() {
+		d := time.Until(deadline)
+		if d <= 0 {
+			return os.ErrDeadlineExceeded
+		}
+		t := time.NewTimer(d)
+		defer t.Stop()
+		deadlineC = t.C
+	}
+
+	select {
+	case <-notify:
+	case <-timerC:
+	case <-deadlineC:
+		return os.ErrDeadlineExceeded
+	}
+	return nil
+}
+
+// rawConn 模拟网络中的一端原始连接（实现 net.Conn）
+type rawConn struct {
+	local  *net.TCPAddr
+	remote *net.TCPAddr
+	in     *halfPipe
+	out    *halfPipe
+
+	// onClose 连接关闭时回调（从网络中注销）
+	onClose func()
+
+	mu        sync.Mutex
+	rDeadline time.Time
+	wDeadline time.Time
+	closeOnce sync.Once
+}
+
+// 确保实现接口
+var _ net.Conn = (*rawConn)(nil)
+
+// newConnPair 创建一对相连的原始连接
+func newConnPair(a, b *net.TCPAddr, link func() (LinkOptions, bool), clock Clock, seed int64) (*rawConn, *rawConn) {
+	ab := newHalfPipe(link, clock, seed)
+	ba := newHalfPipe(link, clock, seed+1)
+	return &rawConn{local: a, remote: b, in: ba, out: ab},
+		&rawConn{local: b, remote: a, in: ab, out: ba}
+}
+
+func (c *rawConn) Read(b []byte) (int, error) {
+	return c.in.read(b, c.readDeadline)
+}
+
+func (c *rawConn) Write(b []byte) (int, error) {
+	return c.out.write(b, c.writeDeadline)
+}
+
+// Close 关闭连接：对端读完剩余数据后收到 EOF，本端读写返回 net.ErrClosed
+func (c *rawConn) Close() error {
+	c.closeOnce.Do(func() {
+		c.out.closeWrite()
+		c.in.closeRead(net.ErrClosed, ErrConnReset)
+		if c.onClose != nil {
+			c.onClose()
+		}
+	})
+	return nil
+}
+
+// reset 立即中断连接（链路断开或分区），两端读写都返回 ErrConnReset
+func (c *rawConn) reset() {
+	c.in.closeRead(ErrConnReset, ErrConnReset)
+	c.out.closeRead(ErrConnReset, ErrConnReset)
+}
+
+func (c *rawConn) LocalAddr() net.Addr  { return c.local }
+func (c *rawConn) RemoteAddr() net.Addr { return c.remote }
+
+func (c *rawConn) SetDeadline(t time.Time) error {
+	c.mu.Lock()
+	c.rDeadline, c.wDeadline = t, t
+	c.mu.Unlock()
+	c.wake()
+	return nil
+}
+
+func (c *rawConn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	c.rDeadline = t
+	c.mu.Unlock()
+	c.wake()
+	return nil
+}
+
+func (c *rawConn) SetWriteDeadline(t time.Time) error {
+	c.mu.Lock()
+	c.wDeadline = t
+	c.mu.Unlock()
+	c.wake()
+	return nil
+}
+
+// wake 唤醒阻塞的读写，使其重新检查截止时间
+func (c *rawConn) wake() {
+	for _, p := range []*halfPipe{c.in, c.out} {
+		p.mu.Lock()
+		p.signal()
+		p.mu.Unlock()
+	}
+}
+
+func (c *rawConn) readDeadline() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rDeadline
+}
+
+func (c *rawConn) writeDeadline() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.wDeadline
+}
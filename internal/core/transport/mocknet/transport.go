@@ -0,0 +1,324 @@
+package mocknet
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/types"
+)
+
+// acceptBacklog 监听器待处理连接队列长度
+const acceptBacklog = 64
+
+// inboundUpgradeTimeout 入站连接升级超时
+const inboundUpgradeTimeout = 30 * time.Second
+
+// 确保实现了接口
+var (
+	_ pkgif.Transport = (*Transport)(nil)
+	_ pkgif.Listener  = (*Listener)(nil)
+)
+
+// Transport 模拟网络传输
+//
+// 地址格式与 TCP 相同（/ip4/<ip>/tcp/<port>），连接建立后交给 Upgrader
+// 完成安全握手与多路复用。
+type Transport struct {
+	host      *Host
+	localPeer types.PeerID
+	upgrader  pkgif.Upgrader
+
+	mu        sync.Mutex
+	listeners []*Listener
+	closed    bool
+}
+
+// NewTransport 在主机上创建传输
+func (h *Host) NewTransport(localPeer types.PeerID, upgrader pkgif.Upgrader) *Transport {
+	return &Transport{
+		host:      h,
+		localPeer: localPeer,
+		upgrader:  upgrader,
+	}
+}
+
+// Host 返回传输所在主机
+func (t *Transport) Host() *Host {
+	return t.host
+}
+
+// Dial 拨号连接
+func (t *Transport) Dial(ctx context.Context, raddr types.Multiaddr, peerID types.PeerID) (pkgif.Connection, error) {
+	t.mu.Lock()
+	closed := t.closed
+	t.mu.Unlock()
+	if closed {
+		return nil, ErrTransportClosed
+	}
+
+	dst, err := parseMultiaddr(raddr)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := t.host.net.dial(ctx, t.host, dst)
+	if err != nil {
+		return nil, fmt.Errorf("dial %s: %w", dst, err)
+	}
+
+	if t.upgrader == nil {
+		raw.Close()
+		return nil, fmt.Errorf("dial %s: no upgrader", dst)
+	}
+
+	upgraded, err := t.upgrader.Upgrade(ctx, raw, pkgif.DirOutbound, peerID)
+	if err != nil {
+		raw.Close()
+		return nil, fmt.Errorf("upgrade connection: %w", err)
+	}
+
+	return newConnection(upgraded, t.localPeer, toMultiaddr(raw.local), raddr, pkgif.DirOutbound), nil
+}
+
+// CanDial 检查是否支持拨号
+func (t *Transport) CanDial(addr types.Multiaddr) bool {
+	_, err := parseMultiaddr(addr)
+	return err == nil
+}
+
+// Listen 监听地址
+//
+// 地址必须是主机自己的地址或未指定地址，端口为 0 时自动分配。
+func (t *Transport) Listen(laddr types.Multiaddr) (pkgif.Listener, error) {
+	addr, err := parseMultiaddr(laddr)
+	if err != nil {
+		return nil, err
+	}
+	if !addr.IP.IsUnspecified() && !addr.IP.Equal(t.host.ip) {
+		return nil, fmt.Errorf("%w: %s", ErrAddrInvalidForHost, addr.IP)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return nil, ErrTransportClosed
+	}
+
+	n := t.host.net
+	n.mu.Lock()
+	port := addr.Port
+	if port == 0 {
+		port = t.host.allocPort()
+	}
+	if l, used := t.host.listeners[port]; used && !l.isClosed() {
+		n.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s", ErrAddrInUse, t.host.Addr(port))
+	}
+
+	l := &Listener{
+		transport: t,
+		addr:      &net.TCPAddr{IP: t.host.ip, Port: port},
+		raw:       make(chan *rawConn, acceptBacklog),
+		upgraded:  make(chan pkgif.Connection, acceptBacklog),
+		done:      make(chan struct{}),
+	}
+	t.host.listeners[port] = l
+	n.mu.Unlock()
+
+	t.listeners = append(t.listeners, l)
+	go l.upgradeLoop()
+
+	return l, nil
+}
+
+// Protocols 返回支持的协议
+func (t *Transport) Protocols() []int {
+	return []int{types.ProtocolTCP}
+}
+
+// Close 关闭传输及其监听器
+func (t *Transport) Close() error {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return nil
+	}
+	t.closed = true
+	listeners := t.listeners
+	t.listeners = nil
+	t.mu.Unlock()
+
+	for _, l := range listeners {
+		l.Close()
+	}
+	return nil
+}
+
+// ============================================================================
+//                              监听器
+// ============================================================================
+
+// Listener 模拟网络监听器
+type Listener struct {
+	transport *Transport
+	addr      *net.TCPAddr
+
+	raw      chan *rawConn
+	upgraded chan pkgif.Connection
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// deliver 投递入站原始连接，队列已满或监听器已关闭时返回 false
+func (l *Listener) deliver(c *rawConn) bool {
+	select {
+	case <-l.done:
+		return false
+	default:
+	}
+	select {
+	case l.raw <- c:
+		return true
+	default:
+		return false
+	}
+}
+
+// upgradeLoop 并发升级入站连接，避免慢握手阻塞后续连接
+func (l *Listener) upgradeLoop() {
+	for {
+		select {
+		case <-l.done:
+			return
+		case raw := <-l.raw:
+			go l.upgrade(raw)
+		}
+	}
+}
+
+func (l *Listener) upgrade(raw *rawConn) {
+	upgrader := l.transport.upgrader
+	if upgrader == nil {
+		raw.Close()
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), inboundUpgradeTimeout)
+	defer cancel()
+
+	// 入站升级：remotePeer 为空，由握手后确定
+	upgraded, err := upgrader.Upgrade(ctx, raw, pkgif.DirInbound, "")
+	if err != nil {
+		logger.Debug("入站连接升级失败", "remote", raw.remote.String(), "error", err)
+		raw.Close()
+		return
+	}
+
+	conn := newConnection(upgraded, l.transport.localPeer, toMultiaddr(raw.local), toMultiaddr(raw.remote), pkgif.DirInbound)
+	select {
+	case l.upgraded <- conn:
+	case <-l.done:
+		conn.Close()
+	}
+}
+
+// Accept 接受新连接
+func (l *Listener) Accept() (pkgif.Connection, error) {
+	select {
+	case conn := <-l.upgraded:
+		return conn, nil
+	case <-l.done:
+		return nil, ErrListenerClosed
+	}
+}
+
+// Close 关闭监听器
+func (l *Listener) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.done)
+
+		host := l.transport.host
+		host.net.mu.Lock()
+		if host.listeners[l.addr.Port] == l {
+			delete(host.listeners, l.addr.Port)
+		}
+		host.net.mu.Unlock()
+
+		// 关闭尚未升级的连接
+		for {
+			select {
+			case raw := <-l.raw:
+				raw.Close()
+			default:
+				return
+			}
+		}
+	})
+	return nil
+}
+
+func (l *Listener) isClosed() bool {
+	select {
+	case <-l.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Addr 返回监听地址
+func (l *Listener) Addr() types.Multiaddr {
+	return toMultiaddr(l.addr)
+}
+
+// Multiaddr 返回多地址格式
+func (l *Listener) Multiaddr() types.Multiaddr {
+	return l.Addr()
+}
+
+// ============================================================================
+//                              地址转换
+// ============================================================================
+
+// parseMultiaddr 解析 /ip4|ip6/<ip>/tcp/<port>
+func parseMultiaddr(addr types.Multiaddr) (*net.TCPAddr, error) {
+	if addr == nil {
+		return nil, ErrInvalidAddr
+	}
+	ipStr, err := addr.ValueForProtocol(types.ProtocolIP4)
+	if err != nil {
+		ipStr, err = addr.ValueForProtocol(types.ProtocolIP6)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidAddr, addr)
+		}
+	}
+	portStr, err := addr.ValueForProtocol(types.ProtocolTCP)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAddr, addr)
+	}
+	ip := net.ParseIP(ipStr)
+	port, err := strconv.Atoi(portStr)
+	if ip == nil || err != nil || port < 0 || port > 0xffff {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAddr, addr)
+	}
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	return &net.TCPAddr{IP: ip, Port: port}, nil
+}
+
+// toMultiaddr 将 TCP 端点转换为多地址
+func toMultiaddr(addr *net.TCPAddr) types.Multiaddr {
+	proto := "ip4"
+	if addr.IP.To4() == nil {
+		proto = "ip6"
+	}
+	maddr, _ := types.NewMultiaddr(fmt.Sprintf("/%s/%s/tcp/%d", proto, addr.IP, addr.Port))
+	return maddr
+}
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/dep2p/go-dep2p/config"
+	"github.com/dep2p/go-dep2p/internal/core/storage/engine"
+	"github.com/dep2p/go-dep2p/internal/core/storage/kv"
 	"github.com/dep2p/go-dep2p/internal/core/transport/quic"
 	"github.com/dep2p/go-dep2p/internal/core/transport/tcp"
 	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
@@ -32,6 +34,18 @@ type Config struct {
 
 	// 通用配置
 	DialTimeout time.Duration
+
+	// PostQuantum 是否启用 X25519MLKEM768 混合密钥交换（QUIC 通过 ALPN 协商）
+	PostQuantum bool
+
+	// QUICZeroRTT 重连已知节点时是否使用 QUIC 0-RTT
+	QUICZeroRTT bool
+
+	// QUICMigration 网络变化时是否迁移 QUIC 连接
+	QUICMigration bool
+
+	// QUICMultipath 是否在第二个网络接口上维持 QUIC 备用路径
+	QUICMultipath bool
 }
 
 // ConfigFromUnified 从统一配置创建传输配置
@@ -47,6 +61,10 @@ func ConfigFromUnified(cfg *config.Config) Config {
 		QUICMaxStreams:     cfg.Transport.QUIC.MaxStreams,
 		TCPTimeout:         cfg.Transport.TCP.Timeout.Duration(),
 		DialTimeout:        cfg.Transport.DialTimeout.Duration(),
+		PostQuantum:        cfg.Security.PostQuantum,
+		QUICZeroRTT:        cfg.Transport.QUIC.ZeroRTT,
+		QUICMigration:      cfg.Transport.QUIC.Migration,
+		QUICMultipath:      cfg.Transport.QUIC.Multipath,
 	}
 }
 
@@ -62,6 +80,9 @@ func NewConfig() Config {
 
 		TCPTimeout:  10 * time.Second,
 		DialTimeout: 30 * time.Second,
+
+		QUICZeroRTT:   true,
+		QUICMigration: true,
 	}
 }
 
@@ -93,9 +114,19 @@ func NewTransportManager(cfg Config, identity pkgif.Identity, upgrader pkgif.Upg
 
 	// 创建 QUIC 传输（从 Identity 获取 TLS 配置）
 	if cfg.EnableQUIC {
-		quicTransport := quic.New(localPeer, identity)
+		newQUIC := quic.New
+		if cfg.PostQuantum {
+			newQUIC = quic.NewPostQuantum
+		}
+		quicTransport := newQUIC(localPeer, identity)
+		quicTransport.SetZeroRTT(cfg.QUICZeroRTT)
+		quicTransport.SetMigration(cfg.QUICMigration)
+		if cfg.QUICMultipath {
+			quicTransport.EnableMultipath(quic.DefaultMultipathConfig())
+		}
 		tm.transports = append(tm.transports, quicTransport)
-		logger.Debug("QUIC 传输已创建")
+		logger.Debug("QUIC 传输已创建", "postQuantum", cfg.PostQuantum, "zeroRTT", cfg.QUICZeroRTT,
+			"migration", cfg.QUICMigration, "multipath", cfg.QUICMultipath)
 	}
 
 	// 创建 TCP 传输（需要 Upgrader 进行安全握手）
@@ -109,6 +140,35 @@ func NewTransportManager(cfg Config, identity pkgif.Identity, upgrader pkgif.Upg
 	return tm
 }
 
+// SetStorageEngine 设置存储引擎
+//
+// QUIC 会话票据写入存储引擎，重启后重连已知节点仍可恢复会话。
+// 未设置时票据只保存在内存中。
+func (tm *TransportManager) SetStorageEngine(eng engine.InternalEngine) {
+	for _, t := range tm.transports {
+		if qt, ok := t.(*quic.Transport); ok {
+			qt.SetSessionStore(kv.New(eng, []byte("quic/sessions/")))
+		}
+	}
+}
+
+// SetPathHealthManager 设置路径健康管理器
+//
+// 启用 QUIC 备用路径时，路径探测结果上报到该管理器并由其决定切换。
+// 未设置时每个 QUIC 传输使用独立的管理器。
+func (tm *TransportManager) SetPathHealthManager(health pkgif.PathHealthManager) {
+	if !tm.config.QUICMultipath || health == nil {
+		return
+	}
+	for _, t := range tm.transports {
+		if qt, ok := t.(*quic.Transport); ok {
+			cfg := quic.DefaultMultipathConfig()
+			cfg.Health = health
+			qt.EnableMultipath(cfg)
+		}
+	}
+}
+
 // GetTransports 获取所有传输
 func (tm *TransportManager) GetTransports() []pkgif.Transport {
 	return tm.transports
@@ -178,9 +238,56 @@ func ProvideConfig(cfg *config.Config) Config {
 	return ConfigFromUnified(cfg)
 }
 
+// Factory 自定义传输工厂
+//
+// 通过 Fx 提供 Factory 时，传输管理器使用它创建的传输替代 QUIC/TCP，
+// 测试中用于接入模拟网络（mocknet）。
+type Factory func(localPeer types.PeerID, identity pkgif.Identity, upgrader pkgif.Upgrader) []pkgif.Transport
+
+// TransportParams 传输层依赖参数
+type TransportParams struct {
+	fx.In
+
+	Config   Config
+	Identity pkgif.Identity
+	Upgrader pkgif.Upgrader
+	Engine     engine.InternalEngine    `optional:"true"`
+	Factory    Factory                  `optional:"true"`
+	PathHealth pkgif.PathHealthManager `optional:"true"`
+}
+
+// NewTransportManagerWithFactory 使用自定义工厂创建传输管理器
+func NewTransportManagerWithFactory(cfg Config, identity pkgif.Identity, upgrader pkgif.Upgrader, factory Factory) *TransportManager {
+	localPeer := types.PeerID("")
+	if identity != nil {
+		localPeer = types.PeerID(identity.PeerID())
+	}
+
+	tm := &TransportManager{
+		config:     cfg,
+		localPeer:  localPeer,
+		identity:   identity,
+		upgrader:   upgrader,
+		transports: factory(localPeer, identity, upgrader),
+	}
+	logger.Info("传输管理器创建成功（自定义传输）", "transportCount", len(tm.transports))
+	return tm
+}
+
 // ProvideTransports 提供 TransportManager 和 Transport 列表
-func ProvideTransports(cfg Config, identity pkgif.Identity, upgrader pkgif.Upgrader) TransportOutput {
-	tm := NewTransportManager(cfg, identity, upgrader)
+func ProvideTransports(p TransportParams) TransportOutput {
+	var tm *TransportManager
+	if p.Factory != nil {
+		tm = NewTransportManagerWithFactory(p.Config, p.Identity, p.Upgrader, p.Factory)
+	} else {
+		tm = NewTransportManager(p.Config, p.Identity, p.Upgrader)
+	}
+	if p.Engine != nil {
+		tm.SetStorageEngine(p.Engine)
+	}
+	if p.PathHealth != nil {
+		tm.SetPathHealthManager(p.PathHealth)
+	}
 	return TransportOutput{
 		TransportManager: tm,
 		Transports:       tm.GetTransports(),
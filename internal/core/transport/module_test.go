@@ -24,6 +24,8 @@ func TestNewConfig(t *testing.T) {
 	assert.Equal(t, 1024, cfg.QUICMaxStreams)
 	assert.Equal(t, 10*time.Second, cfg.TCPTimeout)
 	assert.Equal(t, 30*time.Second, cfg.DialTimeout)
+	assert.True(t, cfg.QUICMigration, "QUIC 连接迁移应该默认启用")
+	assert.False(t, cfg.QUICMultipath, "QUIC 备用路径应该默认禁用")
 
 	t.Log("✅ NewConfig 返回正确的默认值")
 }
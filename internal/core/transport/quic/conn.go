@@ -3,11 +3,13 @@ package quic
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"sync"
 	"time"
 
+	securitytls "github.com/dep2p/go-dep2p/internal/core/security/tls"
 	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
 	"github.com/dep2p/go-dep2p/pkg/types"
 	"github.com/quic-go/quic-go"
@@ -19,7 +21,10 @@ func splitHostPort(addr string) (host, port string, err error) {
 }
 
 // 确保实现了接口
-var _ pkgif.Connection = (*Connection)(nil)
+var (
+	_ pkgif.Connection          = (*Connection)(nil)
+	_ pkgif.EarlyDataConnection = (*Connection)(nil)
+)
 
 // Connection QUIC 连接
 type Connection struct {
@@ -34,6 +39,13 @@ type Connection struct {
 	streams []pkgif.Stream
 	opened  time.Time
 	closed  bool
+
+	// activePath 迁移后使用的路径（nil 表示握手时的路径）
+	activePath *quic.Path
+	// activeLocal 当前路径的本地地址
+	//
+	// 路径切换时 quic-go 的 LocalAddr 并发读取不安全，因此在这里记录。
+	activeLocal net.Addr
 }
 
 // newConnection 创建新连接
@@ -46,9 +58,45 @@ func newConnection(quicConn *quic.Conn, local, remote types.PeerID, remoteAddr t
 		direction:  dir,
 		streams:    make([]pkgif.Stream, 0),
 		opened:     time.Now(),
+
+		activeLocal: quicConn.LocalAddr(),
+	}
+}
+
+// HandshakeConfirmed 握手是否已确认
+//
+// 0-RTT 连接在握手确认前就已返回，此前收发的数据可能被重放。
+func (c *Connection) HandshakeConfirmed() bool {
+	select {
+	case <-c.quicConn.HandshakeComplete():
+		return true
+	default:
+		return false
 	}
 }
 
+// AwaitHandshake 等待握手确认
+//
+// 服务端拒绝 0-RTT 时，握手完成后切换到新的流表，之前的早期流以
+// quic.Err0RTTRejected 失败，需要由（幂等的）上层协议重试。
+func (c *Connection) AwaitHandshake(ctx context.Context) error {
+	if _, err := c.quicConn.NextConnection(ctx); err != nil {
+		return err
+	}
+	if !c.HandshakeConfirmed() {
+		return ErrConnectionClosed
+	}
+	return nil
+}
+
+// retryAfter0RTTRejected 在 0-RTT 被拒绝时等待握手完成，返回是否可以重试
+func (c *Connection) retryAfter0RTTRejected(ctx context.Context, err error) bool {
+	if !errors.Is(err, quic.Err0RTTRejected) {
+		return false
+	}
+	return c.AwaitHandshake(ctx) == nil
+}
+
 // LocalPeer 返回本地节点 ID
 func (c *Connection) LocalPeer() types.PeerID {
 	return c.localPeer
@@ -56,7 +104,9 @@ func (c *Connection) LocalPeer() types.PeerID {
 
 // LocalMultiaddr 返回本地多地址
 func (c *Connection) LocalMultiaddr() types.Multiaddr {
-	localUDPAddr := c.quicConn.LocalAddr()
+	c.mu.RLock()
+	localUDPAddr := c.activeLocal
+	c.mu.RUnlock()
 	if localUDPAddr == nil {
 		return nil
 	}
@@ -122,6 +172,9 @@ func (c *Connection) NewStreamWithPriority(ctx context.Context, priority int) (p
 
 	// 在锁外调用可能阻塞的 OpenStreamSync
 	quicStream, err := c.quicConn.OpenStreamSync(ctx)
+	if err != nil && c.retryAfter0RTTRejected(ctx, err) {
+		quicStream, err = c.quicConn.OpenStreamSync(ctx)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -161,6 +214,9 @@ func (c *Connection) AcceptStream() (pkgif.Stream, error) {
 
 	// 在锁外调用可能阻塞的 AcceptStream
 	quicStream, err := c.quicConn.AcceptStream(context.Background())
+	if err != nil && c.retryAfter0RTTRejected(context.Background(), err) {
+		quicStream, err = c.quicConn.AcceptStream(context.Background())
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -194,11 +250,18 @@ func (c *Connection) Stat() pkgif.ConnectionStat {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
+	// 0-RTT 连接在收到 ServerHello 前尚未完成 ALPN 协商，每次从当前状态读取
+	state := c.quicConn.ConnectionState()
+	alpn := state.TLS.NegotiatedProtocol
 	return pkgif.ConnectionStat{
-		Direction:  c.direction,
-		Opened:     c.opened.Unix(),
-		Transient:  false,
-		NumStreams: len(c.streams),
+		Direction:   c.direction,
+		Opened:      c.opened.Unix(),
+		Transient:   false,
+		NumStreams:  len(c.streams),
+		Security:    securitytls.ProtocolForALPN(alpn),
+		KeyExchange: securitytls.KeyExchangeForALPN(alpn),
+		Resumed:     state.TLS.DidResume,
+		EarlyData:   state.Used0RTT,
 	}
 }
 
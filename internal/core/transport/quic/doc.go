@@ -11,6 +11,28 @@
 //   - 0-RTT 连接恢复
 //   - 连接迁移
 //
+// # 会话恢复与 0-RTT
+//
+// 会话票据按 PeerID 缓存（可通过 SetSessionStore 持久化），重连已知节点时
+// 使用 DialEarly 发送 0-RTT 数据。恢复会话同样通过 VerifyConnection 校验
+// 证书中的 PeerID。0-RTT 数据可能被重放，Host 两端各自把关：
+// 客户端只在 ctx 经 WithEarlyData 标记时发送早期数据，服务端只把
+// 注册时声明 WithIdempotent 的协议在握手确认前交给处理器。
+//
+// 服务端票据密钥随机生成，每 6 小时轮换一次，上一个密钥只用于解密，
+// 密钥不落盘。本节点重启后对端持有的票据失效，回退到完整握手。
+//
+// # 连接迁移
+//
+// 网络变化时 Rebind 默认不再重建 socket（SetMigration），而是为每个出站连接
+// 在新的本地 socket 上建立路径，PATH_CHALLENGE 验证通过后切换，连接和流不中断。
+// 只有拨号方可以发起迁移，入站连接由对端迁移。共享 socket 绑定在具体 IP 时
+// 仍重建 socket。
+//
+// EnableMultipath 为出站连接在第二个网络接口上维持已验证的备用路径。
+// 两条路径的探测结果以本地地址上报给路径健康管理器，ShouldSwitch
+// 决定切换时立即切到备用路径，适用于 Wi-Fi + 蜂窝或双网卡设备。
+//
 // # 地址格式
 //
 //   /ip4/1.2.3.4/udp/4001/quic-v1
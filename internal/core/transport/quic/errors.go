@@ -15,4 +15,7 @@ var (
 
 	// ErrNoCertificate 没有证书
 	ErrNoCertificate = errors.New("no TLS certificate available")
+
+	// ErrMigrationNotSupported 连接不支持迁移（只有拨号方可以发起迁移）
+	ErrMigrationNotSupported = errors.New("connection migration not supported")
 )
@@ -15,17 +15,78 @@ import (
 // 确保实现了接口
 var _ pkgif.Listener = (*Listener)(nil)
 
+// quicListener quic.Listener 与 quic.EarlyListener 的公共接口
+type quicListener interface {
+	Accept(ctx context.Context) (*quic.Conn, error)
+	Close() error
+	Addr() net.Addr
+}
+
 // Listener QUIC 监听器
 type Listener struct {
-	quicListener *quic.Listener
+	quicListener quicListener
 	localAddr    types.Multiaddr
 	localPeer    types.PeerID
 	transport    *Transport
+
+	// 0-RTT 监听器：连接在握手确认前返回，由 acceptLoop 筛选后放入 ready
+	ready     chan *quic.Conn
+	done      chan struct{}
+	acceptErr error
+}
+
+// newListener 创建监听器
+//
+// early 为 true 时 ql 是 quic.EarlyListener，启动后台循环并发等待握手。
+func newListener(ql quicListener, addr types.Multiaddr, local types.PeerID, t *Transport, early bool) *Listener {
+	l := &Listener{
+		quicListener: ql,
+		localAddr:    addr,
+		localPeer:    local,
+		transport:    t,
+	}
+	if early {
+		l.ready = make(chan *quic.Conn, 16)
+		l.done = make(chan struct{})
+		go l.acceptLoop()
+	}
+	return l
+}
+
+// acceptLoop 接受早期连接
+//
+// 使用 0-RTT 的连接携带了会话票据中的对端证书，可以立即交付；
+// 完整握手的连接在客户端证书到达之前无法确定 PeerID，需等待握手完成。
+// 每个连接在独立 goroutine 中等待，慢速握手不会阻塞其他连接。
+func (l *Listener) acceptLoop() {
+	for {
+		conn, err := l.quicListener.Accept(context.Background())
+		if err != nil {
+			l.acceptErr = err
+			close(l.done)
+			return
+		}
+
+		go func(conn *quic.Conn) {
+			if !conn.ConnectionState().Used0RTT {
+				select {
+				case <-conn.HandshakeComplete():
+				case <-conn.Context().Done():
+					return
+				}
+			}
+			select {
+			case l.ready <- conn:
+			case <-l.done:
+				conn.CloseWithError(0, "listener closed")
+			}
+		}(conn)
+	}
 }
 
 // Accept 接受新连接
 func (l *Listener) Accept() (pkgif.Connection, error) {
-	quicConn, err := l.quicListener.Accept(context.Background())
+	quicConn, err := l.accept()
 	if err != nil {
 		return nil, err
 	}
@@ -49,6 +110,19 @@ func (l *Listener) Accept() (pkgif.Connection, error) {
 	return newConnection(quicConn, l.localPeer, remotePeer, remoteAddr, pkgif.DirInbound), nil
 }
 
+// accept 获取下一个可交付的 QUIC 连接
+func (l *Listener) accept() (*quic.Conn, error) {
+	if l.ready == nil {
+		return l.quicListener.Accept(context.Background())
+	}
+	select {
+	case conn := <-l.ready:
+		return conn, nil
+	case <-l.done:
+		return nil, l.acceptErr
+	}
+}
+
 // Close 关闭监听器
 func (l *Listener) Close() error {
 	return l.quicListener.Close()
@@ -0,0 +1,268 @@
+package quic
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/quic-go/quic-go"
+)
+
+// migrateTimeout 单个连接迁移的路径验证超时
+const migrateTimeout = 3 * time.Second
+
+// pathSocket 迁移和备用路径使用的额外 socket
+//
+// quic-go 的连接会在用过的每个 quic.Transport 上注册连接 ID，
+// 关闭其中任何一个都会销毁连接，因此 socket 在所有用过它的连接关闭后才释放。
+type pathSocket struct {
+	tr    *quic.Transport
+	udp   *net.UDPConn
+	local *net.UDPAddr
+	conns map[*Connection]struct{}
+}
+
+// close 关闭 socket
+func (ps *pathSocket) close() {
+	ps.tr.Close()
+	ps.udp.Close()
+}
+
+// ============================================================================
+//                              连接迁移
+// ============================================================================
+
+// Migrate 将连接迁移到 tr 上的新路径
+//
+// 先通过 PATH_CHALLENGE 验证新路径，验证成功后切换，已有的流不受影响。
+// 只有拨号方可以发起迁移，入站连接返回 ErrMigrationNotSupported。
+// tr 在连接关闭前必须保持打开。
+func (c *Connection) Migrate(ctx context.Context, tr *quic.Transport) error {
+	path, err := c.addPath(tr)
+	if err != nil {
+		return err
+	}
+	if err := path.Probe(ctx); err != nil {
+		c.closePath(path)
+		return fmt.Errorf("probe path: %w", err)
+	}
+
+	prev, err := c.switchPath(path, tr.Conn.LocalAddr())
+	if err != nil {
+		return err
+	}
+	c.closePath(prev)
+	return nil
+}
+
+// addPath 在 tr 上为连接创建新路径（尚未探测）
+func (c *Connection) addPath(tr *quic.Transport) (*quic.Path, error) {
+	if c.direction != pkgif.DirOutbound {
+		return nil, ErrMigrationNotSupported
+	}
+	path, err := c.quicConn.AddPath(tr)
+	if err != nil {
+		return nil, fmt.Errorf("add path: %w", err)
+	}
+	return path, nil
+}
+
+// switchPath 切换到已验证的路径，返回之前的迁移路径（握手路径返回 nil）
+func (c *Connection) switchPath(path *quic.Path, local net.Addr) (*quic.Path, error) {
+	if err := path.Switch(); err != nil {
+		return nil, fmt.Errorf("switch path: %w", err)
+	}
+
+	c.mu.Lock()
+	prev := c.activePath
+	c.activePath = path
+	c.activeLocal = local
+	c.mu.Unlock()
+	return prev, nil
+}
+
+// closePath 放弃路径
+//
+// 连接关闭后 quic-go 不允许再操作路径，此时直接忽略。
+func (c *Connection) closePath(path *quic.Path) {
+	if path == nil || c.quicConn.Context().Err() != nil {
+		return
+	}
+	path.Close()
+}
+
+// localUDPAddr 返回当前路径的本地 UDP 地址
+func (c *Connection) localUDPAddr() *net.UDPAddr {
+	c.mu.RLock()
+	addr, _ := c.activeLocal.(*net.UDPAddr)
+	c.mu.RUnlock()
+	return addr
+}
+
+// ============================================================================
+//                              传输层支持
+// ============================================================================
+
+// SetMigration 设置 Rebind 时是否迁移连接
+//
+// 启用后，共享 socket 绑定在未指定地址时 Rebind 不再重建 socket，
+// 而是把出站连接迁移到新的本地 socket，连接和流在网络切换时保持不变。
+// 入站连接由对端（拨号方）负责迁移。
+func (t *Transport) SetMigration(enable bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.migration = enable
+}
+
+// trackConn 记录出站连接，连接关闭后释放其使用的路径 socket
+func (t *Transport) trackConn(c *Connection) {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return
+	}
+	t.conns[c] = struct{}{}
+	multipath := t.multipath
+	t.mu.Unlock()
+
+	if multipath != nil {
+		t.startPathKeeper(c, *multipath)
+	}
+
+	go func() {
+		<-c.quicConn.Context().Done()
+		t.releaseConn(c)
+	}()
+}
+
+// releaseConn 移除连接，关闭不再被任何连接使用的路径 socket
+func (t *Transport) releaseConn(c *Connection) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.conns, c)
+	for tr, ps := range t.pathSockets {
+		delete(ps.conns, c)
+		if len(ps.conns) == 0 {
+			ps.close()
+			delete(t.pathSockets, tr)
+		}
+	}
+}
+
+// pathSocketLocked 获取绑定到 ip 的路径 socket，不存在时创建（调用方持有 t.mu）
+//
+// ip 为 nil 时总是创建新的 socket（用于迁移）。
+func (t *Transport) pathSocketLocked(ip net.IP) (*pathSocket, error) {
+	if ip != nil {
+		for _, ps := range t.pathSockets {
+			if ps.local.IP.Equal(ip) {
+				return ps, nil
+			}
+		}
+	}
+
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: ip})
+	if err != nil {
+		return nil, fmt.Errorf("listen udp for path: %w", err)
+	}
+	ps := &pathSocket{
+		tr:    &quic.Transport{Conn: conn},
+		udp:   conn,
+		local: conn.LocalAddr().(*net.UDPAddr),
+		conns: make(map[*Connection]struct{}),
+	}
+	t.pathSockets[ps.tr] = ps
+	return ps, nil
+}
+
+// addPath 在绑定到 ip 的路径 socket 上为连接创建新路径
+func (t *Transport) addPath(c *Connection, ip net.IP) (*quic.Path, *net.UDPAddr, error) {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return nil, nil, ErrTransportClosed
+	}
+	ps, err := t.pathSocketLocked(ip)
+	if err != nil {
+		t.mu.Unlock()
+		return nil, nil, err
+	}
+	ps.conns[c] = struct{}{}
+	t.mu.Unlock()
+
+	path, err := c.addPath(ps.tr)
+	if err != nil {
+		return nil, nil, err
+	}
+	return path, ps.local, nil
+}
+
+// migrate 将出站连接迁移到新的本地 socket
+//
+// 只在共享 socket 绑定在未指定地址时迁移：此时监听不受接口变化影响，无需重建；
+// 绑定在具体 IP 的 socket 仍走重建流程。返回是否已处理本次 Rebind。
+func (t *Transport) migrate(ctx context.Context) (bool, error) {
+	t.mu.Lock()
+	if !t.migration || t.closed || t.udpConn == nil {
+		t.mu.Unlock()
+		return false, nil
+	}
+	if local, ok := t.udpConn.LocalAddr().(*net.UDPAddr); !ok || !local.IP.IsUnspecified() {
+		t.mu.Unlock()
+		return false, nil
+	}
+
+	conns := make([]*Connection, 0, len(t.conns))
+	for c := range t.conns {
+		conns = append(conns, c)
+	}
+	if len(conns) == 0 {
+		t.mu.Unlock()
+		return true, nil
+	}
+
+	ps, err := t.pathSocketLocked(nil)
+	if err != nil {
+		t.mu.Unlock()
+		return true, err
+	}
+	for _, c := range conns {
+		ps.conns[c] = struct{}{}
+	}
+	t.mu.Unlock()
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		failed  int
+		lastErr error
+	)
+	for _, c := range conns {
+		wg.Add(1)
+		go func(c *Connection) {
+			defer wg.Done()
+
+			mctx, cancel := context.WithTimeout(ctx, migrateTimeout)
+			defer cancel()
+			if err := c.Migrate(mctx, ps.tr); err != nil {
+				logger.Debug("连接迁移失败", "peer", c.remotePeer.ShortString(), "error", err)
+				mu.Lock()
+				failed++
+				lastErr = err
+				mu.Unlock()
+			}
+		}(c)
+	}
+	wg.Wait()
+
+	logger.Info("QUIC 连接迁移完成", "local", ps.local.String(), "total", len(conns), "failed", failed)
+	if failed == len(conns) {
+		return true, fmt.Errorf("migrate connections: %w", lastErr)
+	}
+	return true, nil
+}
@@ -0,0 +1,158 @@
+package quic
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dep2p/go-dep2p/internal/core/identity"
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/types"
+	"github.com/quic-go/quic-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMigrationPair 创建回显服务端和拨号方传输
+func newMigrationPair(t *testing.T) (client *Transport, serverAddr types.Multiaddr, serverPeer types.PeerID) {
+	t.Helper()
+
+	serverID, err := identity.Generate()
+	require.NoError(t, err)
+	clientID, err := identity.Generate()
+	require.NoError(t, err)
+	serverPeer = types.PeerID(serverID.PeerID())
+
+	server := New(serverPeer, serverID)
+	t.Cleanup(func() { server.Close() })
+	l := echoListener(t, server)
+
+	client = New(types.PeerID(clientID.PeerID()), clientID)
+	t.Cleanup(func() { client.Close() })
+	return client, l.Addr(), serverPeer
+}
+
+func TestConnection_Migrate(t *testing.T) {
+	client, addr, serverPeer := newMigrationPair(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	conn, err := client.Dial(ctx, addr, serverPeer)
+	require.NoError(t, err)
+	defer conn.Close()
+	roundTrip(t, conn)
+
+	udp, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	tr := &quic.Transport{Conn: udp}
+	defer udp.Close()
+	defer tr.Close()
+
+	require.NoError(t, conn.(*Connection).Migrate(ctx, tr))
+	roundTrip(t, conn)
+
+	port := udp.LocalAddr().(*net.UDPAddr).Port
+	assert.Contains(t, conn.LocalMultiaddr().String(), "/udp/"+strconv.Itoa(port)+"/")
+}
+
+func TestConnection_Migrate_Inbound(t *testing.T) {
+	conn := &Connection{direction: pkgif.DirInbound}
+	err := conn.Migrate(context.Background(), &quic.Transport{})
+	assert.ErrorIs(t, err, ErrMigrationNotSupported)
+}
+
+func TestQUICTransport_RebindMigrates(t *testing.T) {
+	client, addr, serverPeer := newMigrationPair(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// 未监听时拨号使用绑定在未指定地址的 socket，Rebind 迁移连接
+	conn, err := client.Dial(ctx, addr, serverPeer)
+	require.NoError(t, err)
+	defer conn.Close()
+	roundTrip(t, conn)
+	before := conn.LocalMultiaddr().String()
+
+	require.NoError(t, client.Rebind(ctx))
+	assert.False(t, conn.IsClosed())
+	roundTrip(t, conn)
+	assert.NotEqual(t, before, conn.LocalMultiaddr().String())
+
+	// 连接关闭后释放迁移 socket
+	conn.Close()
+	require.Eventually(t, func() bool {
+		client.mu.RLock()
+		defer client.mu.RUnlock()
+		return len(client.pathSockets) == 0
+	}, 5*time.Second, 20*time.Millisecond)
+}
+
+func TestQUICTransport_RebindWithoutMigration(t *testing.T) {
+	client, addr, serverPeer := newMigrationPair(t)
+	client.SetMigration(false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	conn, err := client.Dial(ctx, addr, serverPeer)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// 重建 socket 会关闭已有连接
+	require.NoError(t, client.Rebind(ctx))
+	select {
+	case <-conn.(*Connection).quicConn.Context().Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("连接应随旧 socket 关闭")
+	}
+}
+
+func TestQUICTransport_MultipathSwitchesOnInterfaceDown(t *testing.T) {
+	// 127.0.0.2 作为第二个接口；移除 127.0.0.1 模拟接口断开
+	probe, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 2)})
+	if err != nil {
+		t.Skip("127.0.0.2 不可用")
+	}
+	probe.Close()
+
+	client, addr, serverPeer := newMigrationPair(t)
+	client.EnableMultipath(MultipathConfig{ProbeInterval: 50 * time.Millisecond, ProbeTimeout: time.Second})
+
+	var primaryDown atomic.Bool
+	client.interfaceAddrs = func() ([]net.Addr, error) {
+		addrs := []net.Addr{&net.IPNet{IP: net.IPv4(127, 0, 0, 2), Mask: net.CIDRMask(8, 32)}}
+		if !primaryDown.Load() {
+			addrs = append(addrs, &net.IPNet{IP: net.IPv4(127, 0, 0, 1), Mask: net.CIDRMask(8, 32)})
+		}
+		return addrs, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	conn, err := client.Dial(ctx, addr, serverPeer)
+	require.NoError(t, err)
+	defer conn.Close()
+	roundTrip(t, conn)
+
+	// 等待备用路径建立
+	require.Eventually(t, func() bool {
+		client.mu.RLock()
+		defer client.mu.RUnlock()
+		return len(client.pathSockets) == 1
+	}, 5*time.Second, 20*time.Millisecond)
+
+	primaryDown.Store(true)
+	require.Eventually(t, func() bool {
+		return strings.HasPrefix(conn.LocalMultiaddr().String(), "/ip4/127.0.0.2/")
+	}, 5*time.Second, 20*time.Millisecond)
+
+	assert.False(t, conn.IsClosed())
+	roundTrip(t, conn)
+}
@@ -0,0 +1,324 @@
+package quic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/dep2p/go-dep2p/internal/core/swarm/pathhealth"
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/quic-go/quic-go"
+)
+
+// errInterfaceDown 路径所在的本地接口已不可用
+var errInterfaceDown = errors.New("local interface down")
+
+// keeperSeq 路径维护器序号，用于区分到同一节点的多个连接
+var keeperSeq atomic.Uint64
+
+// MultipathConfig 备用路径配置
+type MultipathConfig struct {
+	// ProbeInterval 路径探测间隔
+	ProbeInterval time.Duration
+
+	// ProbeTimeout 单次备用路径探测超时
+	ProbeTimeout time.Duration
+
+	// Health 路径健康管理器，用于切换决策（nil 时使用独立的管理器）
+	Health pkgif.PathHealthManager
+}
+
+// DefaultMultipathConfig 返回默认备用路径配置
+func DefaultMultipathConfig() MultipathConfig {
+	return MultipathConfig{
+		ProbeInterval: 5 * time.Second,
+		ProbeTimeout:  2 * time.Second,
+	}
+}
+
+// EnableMultipath 启用备用路径
+//
+// 启用后每个出站连接在另一个网络接口上维持一条已验证的备用路径。
+// 两条路径按 ProbeInterval 探测并上报给路径健康管理器，
+// ShouldSwitch 决定切换时立即切到备用路径，连接和流不中断。
+// 只影响之后建立的连接，应在 Dial 之前调用。
+func (t *Transport) EnableMultipath(cfg MultipathConfig) {
+	defaults := DefaultMultipathConfig()
+	if cfg.ProbeInterval <= 0 {
+		cfg.ProbeInterval = defaults.ProbeInterval
+	}
+	if cfg.ProbeTimeout <= 0 {
+		cfg.ProbeTimeout = defaults.ProbeTimeout
+	}
+	if cfg.Health == nil {
+		cfg.Health = pathhealth.NewManager(nil)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.multipath = &cfg
+}
+
+// pathKeeper 为单个出站连接维护备用路径
+//
+// 路径以本地地址（/ip4/<本地 IP>/udp/<端口>/quic-v1）上报给健康管理器，
+// 节点键为 "<PeerID>#<序号>"，不与 Swarm 上报的远端地址混淆。
+type pathKeeper struct {
+	t      *Transport
+	conn   *Connection
+	cfg    MultipathConfig
+	key    string
+	remote *net.UDPAddr
+
+	// 活动路径
+	activeLocal string
+	activeIP    net.IP
+	activeAddr  string
+
+	// 备用路径
+	standby      *quic.Path
+	standbyLocal *net.UDPAddr
+	standbyAddr  string
+}
+
+// startPathKeeper 启动连接的备用路径维护
+func (t *Transport) startPathKeeper(c *Connection, cfg MultipathConfig) {
+	remote, ok := c.quicConn.RemoteAddr().(*net.UDPAddr)
+	if !ok {
+		return
+	}
+
+	k := &pathKeeper{
+		t:      t,
+		conn:   c,
+		cfg:    cfg,
+		key:    fmt.Sprintf("%s#%d", c.remotePeer, keeperSeq.Add(1)),
+		remote: remote,
+	}
+	go k.run(c.quicConn.Context())
+}
+
+// run 按探测间隔维护路径，连接关闭时退出
+func (k *pathKeeper) run(ctx context.Context) {
+	defer k.cfg.Health.RemovePeer(k.key)
+
+	ticker := time.NewTicker(k.cfg.ProbeInterval)
+	defer ticker.Stop()
+
+	for {
+		k.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick 探测两条路径并按健康管理器的决策切换
+func (k *pathKeeper) tick(ctx context.Context) {
+	ips, err := k.localIPs()
+	if err != nil {
+		logger.Debug("获取本地接口地址失败", "error", err)
+		return
+	}
+
+	k.observeActive(ips)
+	k.observeStandby(ctx, ips)
+	k.maybeSwitch()
+}
+
+// observeActive 上报活动路径的状态
+//
+// 活动路径的 RTT 取自连接统计；本地 IP 不在接口列表中时上报失败。
+func (k *pathKeeper) observeActive(ips []net.IP) {
+	local := k.conn.localUDPAddr()
+	if local == nil {
+		return
+	}
+
+	// 本地 socket 变化（切换或迁移）后重新确定活动路径所在的接口。
+	// 绑定在未指定地址的 socket 取当时路由到对端使用的源地址。
+	if local.String() != k.activeLocal {
+		ip := local.IP
+		if ip.IsUnspecified() {
+			routed, err := routeSourceIP(k.remote)
+			if err != nil {
+				return
+			}
+			ip = routed
+		}
+		k.activeLocal = local.String()
+		k.activeIP = ip
+		k.activeAddr = pathAddr(ip, local.Port)
+	}
+
+	if !containsIP(ips, k.activeIP) {
+		k.cfg.Health.ReportProbe(k.key, k.activeAddr, 0, errInterfaceDown)
+		return
+	}
+	k.cfg.Health.ReportProbe(k.key, k.activeAddr, k.conn.quicConn.ConnectionStats().SmoothedRTT, nil)
+}
+
+// observeStandby 建立并探测备用路径
+//
+// quic-go 不会再次验证已验证的路径，因此每轮在备用接口上建立一条新路径并探测，
+// 验证成功后替换原备用路径。
+func (k *pathKeeper) observeStandby(ctx context.Context, ips []net.IP) {
+	ip := k.pickStandbyIP(ips)
+	if k.standby != nil && !containsIP(ips, k.standbyLocal.IP) {
+		k.cfg.Health.ReportProbe(k.key, k.standbyAddr, 0, errInterfaceDown)
+		k.dropStandby()
+	} else if k.standby != nil {
+		ip = k.standbyLocal.IP
+	}
+	if ip == nil {
+		return
+	}
+
+	path, local, err := k.t.addPath(k.conn, ip)
+	if err != nil {
+		logger.Debug("创建备用路径失败", "peer", k.conn.remotePeer.ShortString(), "local", ip.String(), "error", err)
+		return
+	}
+	addr := pathAddr(local.IP, local.Port)
+
+	pctx, cancel := context.WithTimeout(ctx, k.cfg.ProbeTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err = path.Probe(pctx)
+	k.cfg.Health.ReportProbe(k.key, addr, time.Since(start), err)
+	if err != nil {
+		k.conn.closePath(path)
+		return
+	}
+
+	k.dropStandby()
+	k.standby = path
+	k.standbyLocal = local
+	k.standbyAddr = addr
+}
+
+// maybeSwitch 按 ShouldSwitch 的决策切换到备用路径
+func (k *pathKeeper) maybeSwitch() {
+	if k.standby == nil || k.activeAddr == "" {
+		return
+	}
+
+	current := pathhealth.GeneratePathID(k.activeAddr, pkgif.PathTypeDirect)
+	decision := k.cfg.Health.ShouldSwitch(k.key, current)
+	if !decision.ShouldSwitch ||
+		decision.TargetPath != pathhealth.GeneratePathID(k.standbyAddr, pkgif.PathTypeDirect) {
+		return
+	}
+
+	oldLocal := k.conn.localUDPAddr()
+	prev, err := k.conn.switchPath(k.standby, k.standbyLocal)
+	if err != nil {
+		logger.Debug("切换到备用路径失败", "peer", k.conn.remotePeer.ShortString(), "error", err)
+		return
+	}
+	logger.Info("QUIC 连接切换到备用路径",
+		"peer", k.conn.remotePeer.ShortString(),
+		"reason", decision.Reason.String(),
+		"from", k.activeAddr,
+		"to", k.standbyAddr)
+
+	// 原活动路径如果是之前切换过去的路径，保留为备用路径；
+	// 握手路径无法再次探测，下次探测时在其他接口上建立新的备用路径
+	oldAddr := k.activeAddr
+	k.activeLocal = k.standbyLocal.String()
+	k.activeIP = k.standbyLocal.IP
+	k.activeAddr = k.standbyAddr
+
+	k.standby = prev
+	if prev != nil {
+		k.standbyLocal = oldLocal
+		k.standbyAddr = oldAddr
+	} else {
+		k.dropStandby()
+	}
+}
+
+// dropStandby 放弃备用路径
+func (k *pathKeeper) dropStandby() {
+	if k.standby != nil {
+		k.conn.closePath(k.standby)
+	}
+	k.standby = nil
+	k.standbyLocal = nil
+	k.standbyAddr = ""
+}
+
+// pickStandbyIP 选择与活动路径不同、与对端地址族相同的本地 IP
+func (k *pathKeeper) pickStandbyIP(ips []net.IP) net.IP {
+	for _, ip := range ips {
+		if !ip.Equal(k.activeIP) {
+			return ip
+		}
+	}
+	return nil
+}
+
+// localIPs 返回可用于到达对端的本地 IP
+//
+// 只保留与对端地址族相同的地址；对端为回环地址时只保留回环地址，
+// 否则排除回环和链路本地地址。
+func (k *pathKeeper) localIPs() ([]net.IP, error) {
+	addrs, err := k.t.interfaceAddrs()
+	if err != nil {
+		return nil, err
+	}
+
+	remoteV4 := k.remote.IP.To4() != nil
+	loopback := k.remote.IP.IsLoopback()
+
+	ips := make([]net.IP, 0, len(addrs))
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		ip := ipNet.IP
+		if (ip.To4() != nil) != remoteV4 || ip.IsLoopback() != loopback || ip.IsLinkLocalUnicast() {
+			continue
+		}
+		ips = append(ips, ip)
+	}
+	return ips, nil
+}
+
+// routeSourceIP 返回路由到 remote 时使用的本地源地址
+//
+// UDP connect 只查询路由，不发送数据。
+func routeSourceIP(remote *net.UDPAddr) (net.IP, error) {
+	conn, err := net.DialUDP("udp", nil, remote)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).IP, nil
+}
+
+// pathAddr 生成路径的本地多地址字符串
+func pathAddr(ip net.IP, port int) string {
+	if ip.To4() != nil {
+		return fmt.Sprintf("/ip4/%s/udp/%d/quic-v1", ip.String(), port)
+	}
+	return fmt.Sprintf("/ip6/%s/udp/%d/quic-v1", ip.String(), port)
+}
+
+// containsIP 检查 ip 是否在列表中
+func containsIP(ips []net.IP, ip net.IP) bool {
+	for _, candidate := range ips {
+		if candidate.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,211 @@
+// Package quic 实现 QUIC 传输
+package quic
+
+import (
+	"crypto/rand"
+	"crypto/tls"
+	"sync"
+	"time"
+
+	"github.com/dep2p/go-dep2p/internal/core/storage/kv"
+	"github.com/dep2p/go-dep2p/pkg/types"
+)
+
+// persistedSession 持久化的会话票据
+type persistedSession struct {
+	Ticket []byte `json:"ticket"`
+	State  []byte `json:"state"`
+}
+
+// sessionCache 按 PeerID 缓存 TLS 会话票据
+//
+// 标准库按 ServerName 索引会话，而所有节点共用同一个 ServerName，
+// 因此每次拨号通过 forPeer 获取绑定到目标节点的视图。
+// 设置 store 后会话写入存储引擎，节点重启后仍可恢复。
+type sessionCache struct {
+	mu       sync.Mutex
+	sessions map[types.PeerID]*tls.ClientSessionState
+	store    *kv.Store
+}
+
+// newSessionCache 创建会话缓存
+func newSessionCache() *sessionCache {
+	return &sessionCache{
+		sessions: make(map[types.PeerID]*tls.ClientSessionState),
+	}
+}
+
+// setStore 设置持久化存储
+func (c *sessionCache) setStore(store *kv.Store) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.store = store
+}
+
+// forPeer 返回绑定到指定节点的会话缓存视图
+func (c *sessionCache) forPeer(peer types.PeerID) tls.ClientSessionCache {
+	return &peerSessionCache{cache: c, peer: peer}
+}
+
+// get 获取节点的会话票据，内存未命中时从存储加载
+func (c *sessionCache) get(peer types.PeerID) (*tls.ClientSessionState, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cs, ok := c.sessions[peer]; ok {
+		return cs, true
+	}
+	if c.store == nil {
+		return nil, false
+	}
+
+	var persisted persistedSession
+	if err := c.store.GetJSON([]byte(peer), &persisted); err != nil {
+		return nil, false
+	}
+	state, err := tls.ParseSessionState(persisted.State)
+	if err != nil {
+		logger.Debug("丢弃无法解析的会话票据", "peer", peer.ShortString(), "error", err)
+		c.store.Delete([]byte(peer))
+		return nil, false
+	}
+	cs, err := tls.NewResumptionState(persisted.Ticket, state)
+	if err != nil {
+		c.store.Delete([]byte(peer))
+		return nil, false
+	}
+
+	c.sessions[peer] = cs
+	return cs, true
+}
+
+// put 保存节点的会话票据，cs 为 nil 时删除
+func (c *sessionCache) put(peer types.PeerID, cs *tls.ClientSessionState) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cs == nil {
+		delete(c.sessions, peer)
+		if c.store != nil {
+			c.store.Delete([]byte(peer))
+		}
+		return
+	}
+
+	c.sessions[peer] = cs
+	if c.store == nil {
+		return
+	}
+
+	ticket, state, err := cs.ResumptionState()
+	if err != nil || state == nil {
+		return
+	}
+	stateBytes, err := state.Bytes()
+	if err != nil {
+		return
+	}
+	if err := c.store.PutJSON([]byte(peer), persistedSession{Ticket: ticket, State: stateBytes}); err != nil {
+		logger.Debug("持久化会话票据失败", "peer", peer.ShortString(), "error", err)
+	}
+}
+
+// peerSessionCache 绑定到单个节点的会话缓存视图
+//
+// 票据只在 VerifyConnection 确认对端身份后才会由标准库写入，
+// 因此命中的票据一定属于目标节点，0-RTT 数据只有该节点能解密。
+type peerSessionCache struct {
+	cache *sessionCache
+	peer  types.PeerID
+}
+
+// Get 实现 tls.ClientSessionCache
+func (p *peerSessionCache) Get(_ string) (*tls.ClientSessionState, bool) {
+	return p.cache.get(p.peer)
+}
+
+// Put 实现 tls.ClientSessionCache
+func (p *peerSessionCache) Put(_ string, cs *tls.ClientSessionState) {
+	p.cache.put(p.peer, cs)
+}
+
+// sessionTicketKeyRotation 会话票据密钥的轮换间隔
+//
+// 新票据总是用当前密钥加密，上一个密钥只用于解密，
+// 因此票据最长在两个轮换周期内有效，此后泄露的密钥无法解密更早的会话。
+const sessionTicketKeyRotation = 6 * time.Hour
+
+// ticketKeyRotator 定期轮换服务端会话票据密钥
+//
+// 密钥随机生成且只保存在内存中：本节点重启后对端持有的票据失效，
+// 重新走完整握手；对端重启时从存储恢复的票据仍可使用。
+type ticketKeyRotator struct {
+	mu       sync.Mutex
+	conf     *tls.Config
+	interval time.Duration
+	current  [32]byte
+	timer    *time.Timer
+	stopped  bool
+}
+
+// newTicketKeyRotator 创建密钥轮换器并立即设置第一个密钥
+func newTicketKeyRotator(conf *tls.Config, interval time.Duration) (*ticketKeyRotator, error) {
+	r := &ticketKeyRotator{conf: conf, interval: interval}
+	if _, err := rand.Read(r.current[:]); err != nil {
+		return nil, err
+	}
+	conf.SetSessionTicketKeys([][32]byte{r.current})
+	return r, nil
+}
+
+// start 启动定时轮换（重复调用无副作用）
+func (r *ticketKeyRotator) start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.timer != nil || r.stopped {
+		return
+	}
+	r.timer = time.AfterFunc(r.interval, r.tick)
+}
+
+// tick 轮换一次并安排下一次
+func (r *ticketKeyRotator) tick() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stopped {
+		return
+	}
+	r.rotateLocked()
+	r.timer.Reset(r.interval)
+}
+
+// rotate 生成新的加密密钥，上一个密钥保留用于解密
+func (r *ticketKeyRotator) rotate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rotateLocked()
+}
+
+// rotateLocked 执行轮换（调用方持有 r.mu）
+func (r *ticketKeyRotator) rotateLocked() {
+	var next [32]byte
+	if _, err := rand.Read(next[:]); err != nil {
+		logger.Warn("生成会话票据密钥失败", "error", err)
+		return
+	}
+	r.conf.SetSessionTicketKeys([][32]byte{next, r.current})
+	r.current = next
+}
+
+// stop 停止定时轮换
+func (r *ticketKeyRotator) stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.stopped = true
+	if r.timer != nil {
+		r.timer.Stop()
+	}
+}
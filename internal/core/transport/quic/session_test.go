@@ -0,0 +1,235 @@
+package quic
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dep2p/go-dep2p/internal/core/identity"
+	"github.com/dep2p/go-dep2p/internal/core/storage/engine"
+	"github.com/dep2p/go-dep2p/internal/core/storage/engine/badger"
+	"github.com/dep2p/go-dep2p/internal/core/storage/kv"
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// echoListener 监听本地地址并回显每个流的数据
+func echoListener(t *testing.T, tr *Transport) pkgif.Listener {
+	t.Helper()
+
+	laddr, err := types.NewMultiaddr("/ip4/127.0.0.1/udp/0/quic-v1")
+	require.NoError(t, err)
+	l, err := tr.Listen(laddr)
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				for {
+					s, err := conn.AcceptStream()
+					if err != nil {
+						return
+					}
+					go func() {
+						defer s.Close()
+						io.Copy(s, s)
+					}()
+				}
+			}()
+		}
+	}()
+	return l
+}
+
+// roundTrip 打开流并等待回显
+func roundTrip(t *testing.T, conn pkgif.Connection) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	s, err := conn.NewStream(ctx)
+	require.NoError(t, err)
+	defer s.Close()
+
+	_, err = s.Write([]byte("ping"))
+	require.NoError(t, err)
+	buf := make([]byte, 4)
+	_, err = io.ReadFull(s, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(buf))
+}
+
+func TestQUICTransport_ZeroRTTResumption(t *testing.T) {
+	serverID, err := identity.Generate()
+	require.NoError(t, err)
+	clientID, err := identity.Generate()
+	require.NoError(t, err)
+	serverPeer := types.PeerID(serverID.PeerID())
+	clientPeer := types.PeerID(clientID.PeerID())
+
+	server := New(serverPeer, serverID)
+	defer server.Close()
+	l := echoListener(t, server)
+
+	eng, err := badger.New(engine.DefaultConfig(filepath.Join(t.TempDir(), "sessions.db")))
+	require.NoError(t, err)
+	defer eng.Close()
+	store := kv.New(eng, []byte("quic/sessions/"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// 首次拨号：完整握手，收到会话票据
+	first := New(clientPeer, clientID)
+	first.SetSessionStore(store)
+	conn, err := first.Dial(ctx, l.Addr(), serverPeer)
+	require.NoError(t, err)
+	roundTrip(t, conn)
+	assert.False(t, conn.Stat().Resumed)
+	require.Eventually(t, func() bool {
+		_, ok := first.sessions.get(serverPeer)
+		return ok
+	}, 5*time.Second, 20*time.Millisecond)
+	conn.Close()
+	first.Close()
+
+	// 模拟重启：新的传输实例从存储加载票据，使用 0-RTT 重连
+	second := New(clientPeer, clientID)
+	second.SetSessionStore(store)
+	defer second.Close()
+	conn, err = second.Dial(ctx, l.Addr(), serverPeer)
+	require.NoError(t, err)
+	defer conn.Close()
+	roundTrip(t, conn)
+
+	early := conn.(pkgif.EarlyDataConnection)
+	require.NoError(t, early.AwaitHandshake(ctx))
+	assert.True(t, early.HandshakeConfirmed())
+	stat := conn.Stat()
+	assert.True(t, stat.Resumed)
+	assert.True(t, stat.EarlyData)
+	assert.Equal(t, serverPeer, conn.RemotePeer())
+}
+
+func TestQUICTransport_DialVerifiesPeerID(t *testing.T) {
+	serverID, err := identity.Generate()
+	require.NoError(t, err)
+	clientID, err := identity.Generate()
+	require.NoError(t, err)
+	otherID, err := identity.Generate()
+	require.NoError(t, err)
+
+	server := New(types.PeerID(serverID.PeerID()), serverID)
+	defer server.Close()
+	l := echoListener(t, server)
+
+	client := New(types.PeerID(clientID.PeerID()), clientID)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err = client.Dial(ctx, l.Addr(), types.PeerID(otherID.PeerID()))
+	assert.Error(t, err, "证书中的 PeerID 与期望不符时拨号应失败")
+
+	_, ok := client.sessions.get(types.PeerID(otherID.PeerID()))
+	assert.False(t, ok, "校验失败的连接不应缓存会话票据")
+}
+
+func TestQUICTransport_TicketKeyRotation(t *testing.T) {
+	serverID, err := identity.Generate()
+	require.NoError(t, err)
+	clientID, err := identity.Generate()
+	require.NoError(t, err)
+	serverPeer := types.PeerID(serverID.PeerID())
+
+	server := New(serverPeer, serverID)
+	defer server.Close()
+	require.NotNil(t, server.ticketKeys)
+	l := echoListener(t, server)
+
+	client := New(types.PeerID(clientID.PeerID()), clientID)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// dial 拨号一次并返回连接是否恢复了会话
+	dial := func() bool {
+		conn, err := client.Dial(ctx, l.Addr(), serverPeer)
+		require.NoError(t, err)
+		defer conn.Close()
+		require.NoError(t, conn.(pkgif.EarlyDataConnection).AwaitHandshake(ctx))
+		roundTrip(t, conn)
+		return conn.Stat().Resumed
+	}
+
+	assert.False(t, dial())
+	require.Eventually(t, func() bool {
+		_, ok := client.sessions.get(serverPeer)
+		return ok
+	}, 5*time.Second, 20*time.Millisecond)
+
+	// 轮换一次：旧密钥仍可解密票据
+	server.ticketKeys.rotate()
+	assert.True(t, dial(), "上一个密钥签发的票据应仍可恢复")
+
+	// 轮换两次后旧票据无法解密，回退到完整握手
+	client.sessions.put(serverPeer, nil)
+	assert.False(t, dial())
+	require.Eventually(t, func() bool {
+		_, ok := client.sessions.get(serverPeer)
+		return ok
+	}, 5*time.Second, 20*time.Millisecond)
+	server.ticketKeys.rotate()
+	server.ticketKeys.rotate()
+	assert.False(t, dial(), "两个轮换周期前的票据不应再被接受")
+}
+
+func TestTicketKeyRotator_RandomPerInstance(t *testing.T) {
+	id, err := identity.Generate()
+	require.NoError(t, err)
+
+	// 同一身份的两个实例（模拟重启）使用不同的票据密钥
+	a := New(types.PeerID(id.PeerID()), id)
+	defer a.Close()
+	b := New(types.PeerID(id.PeerID()), id)
+	defer b.Close()
+	assert.NotEqual(t, a.ticketKeys.current, b.ticketKeys.current)
+
+	prev := a.ticketKeys.current
+	a.ticketKeys.rotate()
+	assert.NotEqual(t, prev, a.ticketKeys.current)
+}
+
+func TestTicketKeyRotator_Timer(t *testing.T) {
+	r, err := newTicketKeyRotator(&tls.Config{}, 10*time.Millisecond)
+	require.NoError(t, err)
+	first := r.current
+
+	r.start()
+	require.Eventually(t, func() bool {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return r.current != first
+	}, 2*time.Second, 5*time.Millisecond)
+
+	r.stop()
+	r.mu.Lock()
+	stopped := r.current
+	r.mu.Unlock()
+	time.Sleep(50 * time.Millisecond)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	assert.Equal(t, stopped, r.current, "停止后不应再轮换")
+}
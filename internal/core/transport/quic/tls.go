@@ -22,6 +22,14 @@ import (
 //   - *tls.Config: 客户端 TLS 配置
 //   - error: 创建失败时的错误
 func NewTLSConfig(id pkgif.Identity) (serverConf *tls.Config, clientConf *tls.Config, err error) {
+	return newTLSConfig(id, false)
+}
+
+// newTLSConfig 从 Identity 创建 TLS 配置
+//
+// postQuantum 为 true 时通过 ALPN 优先协商 X25519MLKEM768 混合密钥交换，
+// 对端不支持时回退到经典 X25519。
+func newTLSConfig(id pkgif.Identity, postQuantum bool) (serverConf *tls.Config, clientConf *tls.Config, err error) {
 	if id == nil {
 		return nil, nil, fmt.Errorf("identity is nil")
 	}
@@ -31,6 +39,7 @@ func NewTLSConfig(id pkgif.Identity) (serverConf *tls.Config, clientConf *tls.Co
 	if err != nil {
 		return nil, nil, fmt.Errorf("create tls config: %w", err)
 	}
+	cfg.PostQuantum = postQuantum
 	
 	// 生成服务器配置（用于监听）
 	serverConf = cfg.ServerConfig("")
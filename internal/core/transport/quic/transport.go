@@ -9,6 +9,8 @@ import (
 	"sync"
 	"time"
 
+	securitytls "github.com/dep2p/go-dep2p/internal/core/security/tls"
+	"github.com/dep2p/go-dep2p/internal/core/storage/kv"
 	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
 	"github.com/dep2p/go-dep2p/pkg/types"
 	"github.com/quic-go/quic-go"
@@ -39,8 +41,32 @@ type Transport struct {
 	listeners map[string]*Listener
 	closed    bool
 
+	// sessions 按对端缓存的 TLS 会话票据（用于会话恢复）
+	sessions *sessionCache
+
+	// ticketKeys 服务端会话票据密钥轮换（nil 表示使用标准库默认密钥）
+	ticketKeys *ticketKeyRotator
+
+	// zeroRTT 恢复会话时是否使用 0-RTT 早期数据
+	zeroRTT bool
+
 	// rebind 支持
 	rebindSupport *RebindSupport
+
+	// migration Rebind 时迁移出站连接，而不是重建 socket
+	migration bool
+
+	// conns 出站连接（用于迁移和备用路径）
+	conns map[*Connection]struct{}
+
+	// pathSockets 迁移和备用路径使用的额外 socket
+	pathSockets map[*quic.Transport]*pathSocket
+
+	// multipath 备用路径配置（nil 表示未启用）
+	multipath *MultipathConfig
+
+	// interfaceAddrs 返回本机网络接口地址（测试中替换）
+	interfaceAddrs func() ([]net.Addr, error)
 }
 
 // New 创建 QUIC 传输
@@ -52,13 +78,32 @@ type Transport struct {
 // 返回：
 //   - *Transport: QUIC 传输实例
 func New(localPeer types.PeerID, identity pkgif.Identity) *Transport {
+	return newTransport(localPeer, identity, false)
+}
+
+// NewPostQuantum 创建启用混合后量子密钥交换的 QUIC 传输
+//
+// 通过 ALPN 优先协商 X25519MLKEM768，对端不支持时回退到经典 X25519，
+// 协商结果体现在连接统计的 Security 和 KeyExchange 中。
+func NewPostQuantum(localPeer types.PeerID, identity pkgif.Identity) *Transport {
+	return newTransport(localPeer, identity, true)
+}
+
+// newTransport 创建 QUIC 传输
+func newTransport(localPeer types.PeerID, identity pkgif.Identity, postQuantum bool) *Transport {
 	var serverTLS, clientTLS *tls.Config
+	var ticketKeys *ticketKeyRotator
 
 	// 从 Identity 生成 TLS 配置
 	if identity != nil {
 		var err error
-		serverTLS, clientTLS, err = NewTLSConfig(identity)
-		if err != nil {
+		serverTLS, clientTLS, err = newTLSConfig(identity, postQuantum)
+		if err == nil {
+			// 票据密钥随机生成并定期轮换，保证前向安全
+			if ticketKeys, err = newTicketKeyRotator(serverTLS, sessionTicketKeyRotation); err != nil {
+				logger.Warn("初始化会话票据密钥失败，使用默认密钥", "error", err)
+			}
+		} else {
 			// 回退到简化配置（仅用于测试）
 			serverTLS = &tls.Config{
 				MinVersion: tls.VersionTLS13,
@@ -95,8 +140,15 @@ func New(localPeer types.PeerID, identity pkgif.Identity) *Transport {
 			EnableDatagrams:       true,
 			Allow0RTT:             true,
 		},
-		listeners:     make(map[string]*Listener),
-		rebindSupport: NewRebindSupport(),
+		listeners:      make(map[string]*Listener),
+		sessions:       newSessionCache(),
+		ticketKeys:     ticketKeys,
+		zeroRTT:        true,
+		rebindSupport:  NewRebindSupport(),
+		migration:      true,
+		conns:          make(map[*Connection]struct{}),
+		pathSockets:    make(map[*quic.Transport]*pathSocket),
+		interfaceAddrs: net.InterfaceAddrs,
 	}
 
 	// 设置 rebind 函数
@@ -129,6 +181,7 @@ func (t *Transport) Dial(ctx context.Context, raddr types.Multiaddr, peerID type
 	}
 
 	quicTransport := t.quicTransport
+	zeroRTT := t.zeroRTT
 	t.mu.Unlock()
 
 	// 解析地址
@@ -138,12 +191,58 @@ func (t *Transport) Dial(ctx context.Context, raddr types.Multiaddr, peerID type
 	}
 
 	// 使用共享 quic.Transport 拨号（复用监听端口！）
-	quicConn, err := quicTransport.Dial(ctx, udpAddr, t.clientTLSConf, t.config)
+	// 持有目标节点的会话票据时 DialEarly 立即返回，流数据作为 0-RTT 发送
+	dial := quicTransport.Dial
+	if zeroRTT {
+		dial = quicTransport.DialEarly
+	}
+	quicConn, err := dial(ctx, udpAddr, t.dialTLSConfig(peerID), t.config)
 	if err != nil {
 		return nil, fmt.Errorf("dial: %w", err)
 	}
 
-	return newConnection(quicConn, t.localPeer, peerID, raddr, pkgif.DirOutbound), nil
+	conn := newConnection(quicConn, t.localPeer, peerID, raddr, pkgif.DirOutbound)
+	t.trackConn(conn)
+	return conn, nil
+}
+
+// dialTLSConfig 生成拨号到指定节点的客户端 TLS 配置
+//
+// 会话票据按 PeerID 缓存。恢复会话时标准库不会调用 VerifyPeerCertificate，
+// 因此通过 VerifyConnection 对完整握手和会话恢复统一校验证书中的 PeerID。
+func (t *Transport) dialTLSConfig(peerID types.PeerID) *tls.Config {
+	conf := t.clientTLSConf.Clone()
+	if peerID == "" || t.identity == nil {
+		return conf
+	}
+
+	conf.ClientSessionCache = t.sessions.forPeer(peerID)
+	conf.VerifyConnection = func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return securitytls.ErrNoCertificate
+		}
+		return securitytls.VerifyPeerCertificate([][]byte{cs.PeerCertificates[0].Raw}, peerID)
+	}
+	return conf
+}
+
+// SetSessionStore 设置会话票据的持久化存储
+//
+// 设置后会话票据写入存储引擎，节点重启后重连已知节点仍可恢复会话。
+func (t *Transport) SetSessionStore(store *kv.Store) {
+	t.sessions.setStore(store)
+}
+
+// SetZeroRTT 设置恢复会话时是否使用 0-RTT 早期数据
+//
+// 关闭后仍会恢复会话（省去证书交换），但数据在握手完成后才发送。
+// 应在 Listen/Dial 之前调用。
+func (t *Transport) SetZeroRTT(enable bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.zeroRTT = enable
+	t.config.Allow0RTT = enable
 }
 
 // CanDial 检查是否支持拨号
@@ -182,7 +281,7 @@ func (t *Transport) Listen(laddr types.Multiaddr) (pkgif.Listener, error) {
 	}
 
 	// 使用共享 quic.Transport 监听
-	quicListener, err := t.quicTransport.Listen(t.serverTLSConf, t.config)
+	quicListener, err := t.listenLocked()
 	if err != nil {
 		return nil, fmt.Errorf("listen: %w", err)
 	}
@@ -199,18 +298,27 @@ func (t *Transport) Listen(laddr types.Multiaddr) (pkgif.Listener, error) {
 	}
 	actualMultiaddr, _ := types.NewMultiaddr(actualAddrStr)
 
-	listener := &Listener{
-		quicListener: quicListener,
-		localAddr:    actualMultiaddr,
-		localPeer:    t.localPeer,
-		transport:    t,
-	}
-
+	listener := newListener(quicListener, actualMultiaddr, t.localPeer, t, t.zeroRTT)
 	t.listeners[actualAddrStr] = listener
 
+	// 开始接受入站连接后才需要轮换票据密钥
+	if t.ticketKeys != nil {
+		t.ticketKeys.start()
+	}
+
 	return listener, nil
 }
 
+// listenLocked 在共享 quic.Transport 上创建监听器（调用方持有 t.mu）
+//
+// 启用 0-RTT 时使用 ListenEarly，恢复会话的连接可以在握手确认前接收早期数据。
+func (t *Transport) listenLocked() (quicListener, error) {
+	if t.zeroRTT {
+		return t.quicTransport.ListenEarly(t.serverTLSConf, t.config)
+	}
+	return t.quicTransport.Listen(t.serverTLSConf, t.config)
+}
+
 // Protocols 返回支持的协议
 func (t *Transport) Protocols() []int {
 	return []int{types.ProtocolQUIC_V1}
@@ -227,6 +335,10 @@ func (t *Transport) Close() error {
 
 	t.closed = true
 
+	if t.ticketKeys != nil {
+		t.ticketKeys.stop()
+	}
+
 	// 关闭所有监听器
 	for _, l := range t.listeners {
 		l.Close()
@@ -244,6 +356,12 @@ func (t *Transport) Close() error {
 		t.udpConn = nil
 	}
 
+	// 关闭迁移和备用路径的 socket
+	for tr, ps := range t.pathSockets {
+		ps.close()
+		delete(t.pathSockets, tr)
+	}
+
 	return nil
 }
 
@@ -260,12 +378,22 @@ func (t *Transport) Rebind(ctx context.Context) error {
 
 // doRebind 执行实际的 rebind 操作
 //
+// 启用连接迁移时优先迁移出站连接，否则重建 socket。
+func (t *Transport) doRebind(ctx context.Context) error {
+	if migrated, err := t.migrate(ctx); migrated {
+		return err
+	}
+	return t.rebindSockets()
+}
+
+// rebindSockets 重建 socket
+//
 // 重新创建共享 UDP socket 和 quic.Transport：
 // 1. 保存当前监听地址
 // 2. 关闭旧的 quicTransport 和 udpConn
 // 3. 创建新的 UDP socket 和 quic.Transport
 // 4. 重新创建监听器
-func (t *Transport) doRebind(_ context.Context) error {
+func (t *Transport) rebindSockets() error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
@@ -319,20 +447,13 @@ func (t *Transport) doRebind(_ context.Context) error {
 		}
 
 		// 使用共享 quic.Transport 创建监听器
-		quicListener, err := t.quicTransport.Listen(t.serverTLSConf, t.config)
+		quicListener, err := t.listenLocked()
 		if err != nil {
 			lastErr = err
 			continue
 		}
 
-		listener := &Listener{
-			quicListener: quicListener,
-			localAddr:    addr,
-			localPeer:    t.localPeer,
-			transport:    t,
-		}
-
-		t.listeners[addr.String()] = listener
+		t.listeners[addr.String()] = newListener(quicListener, addr, t.localPeer, t, t.zeroRTT)
 	}
 
 	// 更新 rebind 支持的当前地址
@@ -479,3 +479,65 @@ func TestQUICTransport_GetRebindSupport(t *testing.T) {
 
 	t.Log("✅ GetRebindSupport 返回有效的 RebindSupport")
 }
+
+func TestQUICTransport_PostQuantumStat(t *testing.T) {
+	newTransport := func(postQuantum bool) (*Transport, types.PeerID) {
+		id, err := identity.Generate()
+		require.NoError(t, err)
+		peer := types.PeerID(id.PeerID())
+		if postQuantum {
+			return NewPostQuantum(peer, id), peer
+		}
+		return New(peer, id), peer
+	}
+
+	tests := []struct {
+		name        string
+		dialer      bool
+		listener    bool
+		keyExchange string
+	}{
+		{"both hybrid", true, true, pkgif.KeyExchangeX25519MLKEM768},
+		{"classical listener", true, false, pkgif.KeyExchangeX25519},
+		{"classical dialer", false, true, pkgif.KeyExchangeX25519},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialer, _ := newTransport(tt.dialer)
+			defer dialer.Close()
+			listener, listenPeer := newTransport(tt.listener)
+			defer listener.Close()
+
+			laddr, err := types.NewMultiaddr("/ip4/127.0.0.1/udp/0/quic-v1")
+			require.NoError(t, err)
+			l, err := listener.Listen(laddr)
+			require.NoError(t, err)
+			defer l.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			acceptCh := make(chan pkgif.Connection, 1)
+			go func() {
+				conn, err := l.Accept()
+				if err == nil {
+					acceptCh <- conn
+				}
+			}()
+
+			dialed, err := dialer.Dial(ctx, l.Addr(), listenPeer)
+			require.NoError(t, err)
+			defer dialed.Close()
+			var accepted pkgif.Connection
+			select {
+			case accepted = <-acceptCh:
+				defer accepted.Close()
+			case <-ctx.Done():
+				t.Fatal("accept timeout")
+			}
+
+			for _, conn := range []pkgif.Connection{dialed, accepted} {
+				assert.Equal(t, tt.keyExchange, conn.Stat().KeyExchange)
+			}
+		})
+	}
+}
@@ -332,6 +332,7 @@ func (m *mockUpgradedConn) Close() error                  { m.closed = true; ret
 func (m *mockUpgradedConn) IsClosed() bool                { return m.closed }
 func (m *mockUpgradedConn) Security() types.ProtocolID    { return "/tls/1.0.0" }
 func (m *mockUpgradedConn) Muxer() string                 { return "/yamux/1.0.0" }
+func (m *mockUpgradedConn) KeyExchange() string           { return "x25519" }
 func (m *mockUpgradedConn) OpenStream(ctx context.Context) (pkgif.MuxedStream, error) {
 	stream := &mockMuxedStream{}
 	m.streams = append(m.streams, stream)
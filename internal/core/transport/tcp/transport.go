@@ -222,10 +222,12 @@ func (c *upgradedConnection) Stat() pkgif.ConnectionStat {
 	defer c.mu.RUnlock()
 
 	return pkgif.ConnectionStat{
-		Direction:  pkgif.DirOutbound,
-		Opened:     c.opened.Unix(),
-		Transient:  false,
-		NumStreams: len(c.streams),
+		Direction:   pkgif.DirOutbound,
+		Opened:      c.opened.Unix(),
+		Transient:   false,
+		NumStreams:  len(c.streams),
+		Security:    c.Security(),
+		KeyExchange: c.KeyExchange(),
 	}
 }
 
@@ -238,8 +240,24 @@ func (c *upgradedConnection) IsClosed() bool {
 
 // NewStream 创建新流
 func (c *upgradedConnection) NewStream(ctx context.Context) (pkgif.Stream, error) {
+	return c.NewStreamWithPriority(ctx, int(pkgif.StreamPriorityNormal))
+}
+
+// NewStreamWithPriority 创建新流（指定优先级）(v1.2 新增)
+//
+// 优先级交给 yamux 写调度器，在底层 TCP 连接拥塞时按优先级发送。
+// 多路复用器不支持优先级时忽略优先级参数。
+func (c *upgradedConnection) NewStreamWithPriority(ctx context.Context, priority int) (pkgif.Stream, error) {
 	// UpgradedConn 的 OpenStream 返回 MuxedStream
-	muxedStream, err := c.OpenStream(ctx)
+	var (
+		muxedStream pkgif.MuxedStream
+		err         error
+	)
+	if pc, ok := c.UpgradedConn.(pkgif.PriorityMuxedConn); ok {
+		muxedStream, err = pc.OpenStreamWithPriority(ctx, pkgif.StreamPriority(priority))
+	} else {
+		muxedStream, err = c.OpenStream(ctx)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -255,18 +273,20 @@ func (c *upgradedConnection) NewStream(ctx context.Context) (pkgif.Stream, error
 	return stream, nil
 }
 
-// NewStreamWithPriority 创建新流（指定优先级）(v1.2 新增)
-//
-// TCP 连接不支持流优先级，此方法直接调用 NewStream，忽略优先级参数。
-func (c *upgradedConnection) NewStreamWithPriority(ctx context.Context, _ int) (pkgif.Stream, error) {
-	return c.NewStream(ctx)
-}
-
 // SupportsStreamPriority 检查连接是否支持流优先级 (v1.2 新增)
 //
-// 升级后的 TCP 连接不支持流优先级。
+// 多路复用器启用了写调度时返回 true。
 func (c *upgradedConnection) SupportsStreamPriority() bool {
-	return false
+	pc, ok := c.UpgradedConn.(pkgif.PriorityMuxedConn)
+	return ok && pc.SupportsStreamPriority()
+}
+
+// StreamPriorityStats 返回各优先级写队列的统计
+func (c *upgradedConnection) StreamPriorityStats() []pkgif.StreamPriorityStats {
+	if pc, ok := c.UpgradedConn.(pkgif.PriorityMuxedConn); ok {
+		return pc.StreamPriorityStats()
+	}
+	return nil
 }
 
 // AcceptStream 接受新流
@@ -2,12 +2,17 @@
 package upgrader
 
 import (
+	"context"
+
 	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
 	"github.com/dep2p/go-dep2p/pkg/types"
 )
 
 // 确保实现了接口
-var _ pkgif.UpgradedConn = (*upgradedConn)(nil)
+var (
+	_ pkgif.UpgradedConn      = (*upgradedConn)(nil)
+	_ pkgif.PriorityMuxedConn = (*upgradedConn)(nil)
+)
 
 // upgradedConn 升级后的连接
 type upgradedConn struct {
@@ -59,6 +64,11 @@ func (c *upgradedConn) Muxer() string {
 	return c.muxerID
 }
 
+// KeyExchange 返回握手使用的密钥交换算法
+func (c *upgradedConn) KeyExchange() string {
+	return c.secConn.ConnState().KeyExchange
+}
+
 // Close 关闭连接并释放资源
 //
 // 关闭顺序：
@@ -81,3 +91,27 @@ func (c *upgradedConn) Close() error {
 func (c *upgradedConn) Scope() pkgif.ConnManagementScope {
 	return c.connScope
 }
+
+// OpenStreamWithPriority 打开指定优先级的新流
+//
+// 多路复用器不支持优先级调度时退化为 OpenStream。
+func (c *upgradedConn) OpenStreamWithPriority(ctx context.Context, priority pkgif.StreamPriority) (pkgif.MuxedStream, error) {
+	if pc, ok := c.MuxedConn.(pkgif.PriorityMuxedConn); ok {
+		return pc.OpenStreamWithPriority(ctx, priority)
+	}
+	return c.MuxedConn.OpenStream(ctx)
+}
+
+// SupportsStreamPriority 检查多路复用器是否启用了优先级调度
+func (c *upgradedConn) SupportsStreamPriority() bool {
+	pc, ok := c.MuxedConn.(pkgif.PriorityMuxedConn)
+	return ok && pc.SupportsStreamPriority()
+}
+
+// StreamPriorityStats 返回各优先级写队列的统计
+func (c *upgradedConn) StreamPriorityStats() []pkgif.StreamPriorityStats {
+	if pc, ok := c.MuxedConn.(pkgif.PriorityMuxedConn); ok {
+		return pc.StreamPriorityStats()
+	}
+	return nil
+}
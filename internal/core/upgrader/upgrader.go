@@ -183,15 +183,20 @@ func (u *Upgrader) Upgrade(
 	}
 
 	// 7. 封装为 UpgradedConn
+	// 安全协议取实际握手的协议（SecurityMux 内部可能选择 TLS、Noise 或其混合变体）
+	securityProto := secConn.ConnState().Protocol
+	if securityProto == "" {
+		securityProto = secTransport.ID()
+	}
 	upgradedConn := newUpgradedConnWithScope(
 		muxedConn,
 		secConn,
-		secTransport.ID(),
+		securityProto,
 		muxer.ID(),
 		connScope,
 	)
 	
-	logger.Info("连接升级成功", "remotePeer", truncateID(string(secConn.RemotePeer()), 8), "security", secTransport.ID(), "muxer", muxer.ID())
+	logger.Info("连接升级成功", "remotePeer", truncateID(string(secConn.RemotePeer()), 8), "security", securityProto, "keyExchange", upgradedConn.KeyExchange(), "muxer", muxer.ID())
 
 	return upgradedConn, nil
 }
@@ -275,6 +280,11 @@ func (c *quicUpgradedConn) Muxer() string {
 	return "/quic/muxer/1.0"
 }
 
+// KeyExchange 返回 QUIC TLS 握手使用的密钥交换算法
+func (c *quicUpgradedConn) KeyExchange() string {
+	return c.Stat().KeyExchange
+}
+
 // OpenStream 打开新流
 func (c *quicUpgradedConn) OpenStream(ctx context.Context) (pkgif.MuxedStream, error) {
 	stream, err := c.NewStream(ctx)
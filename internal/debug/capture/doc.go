@@ -0,0 +1,8 @@
+// Package capture 将协议流量录制器接入节点生命周期
+//
+// 配置 Diagnostics.Capture.Enabled 后创建录制器并注入 Host，
+// 自省服务通过 /debug/capture 端点控制启停。AutoStart 为 true 时
+// 节点启动即开始录制，节点停止时关闭录制文件。
+//
+// 录制格式与回放见 pkg/lib/capture。
+package capture
@@ -0,0 +1,55 @@
+package capture
+
+import (
+	"context"
+
+	"github.com/dep2p/go-dep2p/config"
+	libcapture "github.com/dep2p/go-dep2p/pkg/lib/capture"
+	"go.uber.org/fx"
+)
+
+// Module 返回流量录制 Fx 模块
+func Module() fx.Option {
+	return fx.Module("capture",
+		fx.Provide(NewFromConfig),
+		fx.Invoke(registerLifecycle),
+	)
+}
+
+// RecorderConfigFromUnified 从统一配置创建录制器配置
+func RecorderConfigFromUnified(cfg config.CaptureConfig) libcapture.Config {
+	rc := libcapture.Config{
+		Path:        cfg.File,
+		MaxFileSize: cfg.MaxFileSize,
+		MaxFiles:    cfg.MaxFiles,
+		MaxPayload:  cfg.MaxPayload,
+		Filter: libcapture.Filter{
+			Peers:     cfg.Peers,
+			Protocols: cfg.Protocols,
+		},
+	}
+	if cfg.Redact {
+		rc.Redact = libcapture.RedactAll
+	}
+	return rc
+}
+
+// NewFromConfig 从统一配置创建录制器
+func NewFromConfig(cfg *config.Config) (*libcapture.Recorder, error) {
+	return libcapture.NewRecorder(RecorderConfigFromUnified(cfg.Diagnostics.Capture))
+}
+
+// registerLifecycle 注册生命周期钩子
+func registerLifecycle(lc fx.Lifecycle, cfg *config.Config, recorder *libcapture.Recorder) {
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			if cfg.Diagnostics.Capture.AutoStart {
+				return recorder.Start(nil)
+			}
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			return recorder.Stop()
+		},
+	})
+}
@@ -9,7 +9,11 @@
 //	GET /debug/introspect/node - 节点信息
 //	GET /debug/introspect/connections - 连接信息
 //	GET /debug/introspect/peers - 节点列表
-//	GET /debug/introspect/bandwidth - 带宽统计
+//	GET /debug/introspect/bandwidth - 带宽统计、限速、数据预算与流优先级调度状态
+//	GET /debug/introspect/topology - Realm 拓扑（JSON，?format=dot 输出 GraphViz）
+//	GET /debug/capture         - 流量录制状态
+//	POST /debug/capture/start  - 开始录制（?peer=&protocol= 过滤）
+//	POST /debug/capture/stop   - 停止录制
 //	GET /debug/pprof/*         - Go pprof 端点
 //	GET /health                - 健康检查
 //
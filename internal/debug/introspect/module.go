@@ -5,6 +5,7 @@ import (
 
 	"github.com/dep2p/go-dep2p/config"
 	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/capture"
 	"go.uber.org/fx"
 )
 
@@ -23,10 +24,14 @@ func Module() fx.Option {
 type IntrospectParams struct {
 	fx.In
 
-	UnifiedCfg        *config.Config    `optional:"true"`
-	Host              pkgif.Host        `optional:"true"`
-	ConnManager       pkgif.ConnManager `optional:"true"`
-	BandwidthReporter BandwidthReporter `optional:"true"`
+	UnifiedCfg        *config.Config         `optional:"true"`
+	Host              pkgif.Host             `optional:"true"`
+	ConnManager       pkgif.ConnManager      `optional:"true"`
+	BandwidthReporter BandwidthReporter      `optional:"true"`
+	BandwidthLimiter  pkgif.BandwidthLimiter `optional:"true"`
+	DataBudget        pkgif.DataBudget       `optional:"true"`
+	RealmManager      pkgif.RealmManager     `optional:"true"`
+	Recorder          *capture.Recorder      `optional:"true"`
 }
 
 // IntrospectOutput 自省服务输出
@@ -61,6 +66,14 @@ func NewFromParams(params IntrospectParams) IntrospectOutput {
 	cfg.Host = params.Host
 	cfg.ConnManager = params.ConnManager
 	cfg.BandwidthReporter = params.BandwidthReporter
+	cfg.BandwidthLimiter = params.BandwidthLimiter
+	cfg.DataBudget = params.DataBudget
+	if provider, ok := params.RealmManager.(TopologyProvider); ok {
+		cfg.Topology = provider
+	}
+	if params.Recorder != nil {
+		cfg.Capture = params.Recorder
+	}
 
 	return IntrospectOutput{
 		Server: New(*cfg),
@@ -7,10 +7,13 @@ import (
 	"net/http"
 	"net/http/pprof"
 	"runtime"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/dep2p/go-dep2p/internal/realm/topology"
 	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/capture"
 	"github.com/dep2p/go-dep2p/pkg/lib/log"
 )
 
@@ -37,10 +40,41 @@ type Config struct {
 	// BandwidthReporter 可选的带宽报告器
 	BandwidthReporter BandwidthReporter
 
+	// BandwidthLimiter 可选的带宽限速器
+	BandwidthLimiter pkgif.BandwidthLimiter
+
+	// DataBudget 可选的数据预算
+	DataBudget pkgif.DataBudget
+
+	// Topology 可选的 Realm 拓扑提供者
+	Topology TopologyProvider
+
+	// Capture 可选的流量录制控制器
+	Capture CaptureController
+
 	// CustomHandlers 自定义处理器
 	CustomHandlers map[string]http.HandlerFunc
 }
 
+// TopologyProvider Realm 拓扑提供接口
+type TopologyProvider interface {
+	TopologyGraph() (*topology.Graph, error)
+}
+
+// CaptureController 流量录制控制接口
+type CaptureController interface {
+	Start(filter *capture.Filter) error
+	Stop() error
+	Status() capture.Status
+}
+
+// StreamPriorityReporter 流优先级调度统计接口
+//
+// Host 实现该接口时，带宽信息中包含各优先级写队列的汇总统计。
+type StreamPriorityReporter interface {
+	StreamPriorityStats() []pkgif.StreamPriorityStats
+}
+
 // BandwidthReporter 带宽报告接口
 type BandwidthReporter interface {
 	GetBandwidthForPeer(peer string) (in, out int64)
@@ -96,6 +130,12 @@ func (s *Server) Start(_ context.Context) error {
 	mux.HandleFunc("/debug/introspect/peers", s.handlePeers)
 	mux.HandleFunc("/debug/introspect/bandwidth", s.handleBandwidth)
 	mux.HandleFunc("/debug/introspect/runtime", s.handleRuntime)
+	mux.HandleFunc("/debug/introspect/topology", s.handleTopology)
+
+	// 流量录制控制
+	mux.HandleFunc("/debug/capture", s.handleCaptureStatus)
+	mux.HandleFunc("/debug/capture/start", s.handleCaptureStart)
+	mux.HandleFunc("/debug/capture/stop", s.handleCaptureStop)
 
 	// pprof 端点
 	mux.HandleFunc("/debug/pprof/", pprof.Index)
@@ -211,8 +251,11 @@ type PeerInfo struct {
 
 // BandwidthInfo 带宽信息
 type BandwidthInfo struct {
-	TotalIn  int64 `json:"total_in"`
-	TotalOut int64 `json:"total_out"`
+	TotalIn    int64                        `json:"total_in"`
+	TotalOut   int64                        `json:"total_out"`
+	Limits     []pkgif.BandwidthLimitStatus `json:"limits,omitempty"`
+	DataBudget *pkgif.DataBudgetStatus      `json:"data_budget,omitempty"`
+	Priorities []pkgif.StreamPriorityStats  `json:"priorities,omitempty"`
 }
 
 // RuntimeInfo 运行时信息
@@ -337,6 +380,96 @@ func (s *Server) handleRuntime(w http.ResponseWriter, r *http.Request) {
 	s.writeJSON(w, info)
 }
 
+// handleTopology 处理 Realm 拓扑请求
+//
+// 默认返回 JSON，format=dot 时返回 GraphViz DOT。
+func (s *Server) handleTopology(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if s.config.Topology == nil {
+		http.Error(w, "Topology not available", http.StatusServiceUnavailable)
+		return
+	}
+	graph, err := s.config.Topology.TopologyGraph()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	switch r.URL.Query().Get("format") {
+	case "", "json":
+		s.writeJSON(w, graph)
+	case "dot":
+		w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+		if err := graph.WriteDOT(w); err != nil {
+			logger.Debug("写入 DOT 失败", "error", err)
+		}
+	default:
+		http.Error(w, "Unsupported format", http.StatusBadRequest)
+	}
+}
+
+// handleCaptureStatus 返回录制状态
+func (s *Server) handleCaptureStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.config.Capture == nil {
+		http.Error(w, "Capture not available", http.StatusServiceUnavailable)
+		return
+	}
+	s.writeJSON(w, s.config.Capture.Status())
+}
+
+// handleCaptureStart 开始录制
+//
+// 查询参数 peer、protocol 可重复或以逗号分隔，指定时替换过滤条件。
+func (s *Server) handleCaptureStart(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.config.Capture == nil {
+		http.Error(w, "Capture not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	var filter *capture.Filter
+	query := r.URL.Query()
+	if query.Has("peer") || query.Has("protocol") {
+		filter = &capture.Filter{
+			Peers:     splitQuery(query["peer"]),
+			Protocols: splitQuery(query["protocol"]),
+		}
+	}
+	if err := s.config.Capture.Start(filter); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.writeJSON(w, s.config.Capture.Status())
+}
+
+// handleCaptureStop 停止录制
+func (s *Server) handleCaptureStop(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.config.Capture == nil {
+		http.Error(w, "Capture not available", http.StatusServiceUnavailable)
+		return
+	}
+	if err := s.config.Capture.Stop(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.writeJSON(w, s.config.Capture.Status())
+}
+
 // handleHealth 处理健康检查请求
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -417,15 +550,26 @@ func (s *Server) collectConnectionInfo() *ConnectionInfo {
 
 // collectBandwidthInfo 收集带宽信息
 func (s *Server) collectBandwidthInfo() *BandwidthInfo {
-	if s.config.BandwidthReporter == nil {
+	priorities, hasPriorities := s.config.Host.(StreamPriorityReporter)
+	if s.config.BandwidthReporter == nil && s.config.BandwidthLimiter == nil && s.config.DataBudget == nil && !hasPriorities {
 		return nil
 	}
 
-	in, out := s.config.BandwidthReporter.GetBandwidthTotals()
-	return &BandwidthInfo{
-		TotalIn:  in,
-		TotalOut: out,
+	info := &BandwidthInfo{}
+	if hasPriorities {
+		info.Priorities = priorities.StreamPriorityStats()
+	}
+	if s.config.BandwidthReporter != nil {
+		info.TotalIn, info.TotalOut = s.config.BandwidthReporter.GetBandwidthTotals()
 	}
+	if s.config.BandwidthLimiter != nil {
+		info.Limits = s.config.BandwidthLimiter.Status()
+	}
+	if s.config.DataBudget != nil {
+		status := s.config.DataBudget.Status()
+		info.DataBudget = &status
+	}
+	return info
 }
 
 // collectRuntimeInfo 收集运行时信息
@@ -447,6 +591,19 @@ func (s *Server) collectRuntimeInfo() *RuntimeInfo {
 //                              辅助方法
 // ============================================================================
 
+// splitQuery 展开可重复且以逗号分隔的查询参数
+func splitQuery(values []string) []string {
+	var out []string
+	for _, v := range values {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				out = append(out, item)
+			}
+		}
+	}
+	return out
+}
+
 // writeJSON 写入 JSON 响应
 func (s *Server) writeJSON(w http.ResponseWriter, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
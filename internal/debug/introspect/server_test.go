@@ -5,12 +5,19 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/dep2p/go-dep2p/internal/core/swarm/bandwidth"
+	"github.com/dep2p/go-dep2p/internal/realm/topology"
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/capture"
+	"github.com/dep2p/go-dep2p/tests/mocks"
 )
 
 func TestNew(t *testing.T) {
@@ -310,3 +317,189 @@ func TestServer_BandwidthEndpoint_WithReporter(t *testing.T) {
 	assert.Equal(t, int64(1000), bandwidth.TotalIn)
 	assert.Equal(t, int64(2000), bandwidth.TotalOut)
 }
+
+// priorityHost 提供流优先级调度统计的 mock Host
+type priorityHost struct {
+	*mocks.MockHost
+	stats []pkgif.StreamPriorityStats
+}
+
+func (h *priorityHost) StreamPriorityStats() []pkgif.StreamPriorityStats {
+	return h.stats
+}
+
+func TestServer_BandwidthEndpoint_Priorities(t *testing.T) {
+	host := &priorityHost{
+		MockHost: mocks.NewMockHost("local-peer"),
+		stats: []pkgif.StreamPriorityStats{
+			{Priority: pkgif.StreamPriorityCritical, SentFrames: 3, SentBytes: 300},
+			{Priority: pkgif.StreamPriorityLow, QueuedFrames: 1, Throttled: 2},
+		},
+	}
+
+	server := New(Config{
+		Addr: "127.0.0.1:0",
+		Host: host,
+	})
+
+	ctx := context.Background()
+	err := server.Start(ctx)
+	require.NoError(t, err)
+	defer server.Stop()
+
+	time.Sleep(50 * time.Millisecond)
+
+	resp, err := http.Get("http://" + server.Addr() + "/debug/introspect/bandwidth")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var bandwidth BandwidthInfo
+	err = json.NewDecoder(resp.Body).Decode(&bandwidth)
+	require.NoError(t, err)
+
+	assert.Equal(t, host.stats, bandwidth.Priorities)
+}
+
+// mockTopologyProvider 模拟拓扑提供者
+type mockTopologyProvider struct {
+	graph *topology.Graph
+	err   error
+}
+
+func (m *mockTopologyProvider) TopologyGraph() (*topology.Graph, error) {
+	return m.graph, m.err
+}
+
+func TestServer_TopologyEndpoint(t *testing.T) {
+	graph := topology.BuildGraph("realm-1", []string{"a", "b"}, []*topology.Report{
+		{Peer: "a", Timestamp: time.Now().UnixNano(), Neighbors: []topology.Neighbor{{Peer: "b", Conn: topology.ConnDirect}}},
+	}, nil, time.Now())
+
+	server := New(Config{
+		Addr:     "127.0.0.1:0",
+		Topology: &mockTopologyProvider{graph: graph},
+	})
+
+	ctx := context.Background()
+	err := server.Start(ctx)
+	require.NoError(t, err)
+	defer server.Stop()
+
+	base := "http://" + server.Addr() + "/debug/introspect/topology"
+
+	// JSON（默认）
+	resp, err := http.Get(base)
+	require.NoError(t, err)
+	var decoded topology.Graph
+	err = json.NewDecoder(resp.Body).Decode(&decoded)
+	resp.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "realm-1", decoded.RealmID)
+	assert.Len(t, decoded.Edges, 1)
+
+	// DOT
+	resp, err = http.Get(base + "?format=dot")
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "text/vnd.graphviz")
+	assert.True(t, strings.HasPrefix(string(body), "graph "))
+
+	// 不支持的格式
+	resp, err = http.Get(base + "?format=xml")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestServer_TopologyEndpoint_Unavailable(t *testing.T) {
+	server := New(Config{Addr: "127.0.0.1:0"})
+
+	ctx := context.Background()
+	err := server.Start(ctx)
+	require.NoError(t, err)
+	defer server.Stop()
+
+	resp, err := http.Get("http://" + server.Addr() + "/debug/introspect/topology")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
+
+func TestServer_CaptureEndpoints(t *testing.T) {
+	recorder, err := capture.NewRecorder(capture.Config{Path: filepath.Join(t.TempDir(), "dep2p.capture")})
+	require.NoError(t, err)
+
+	server := New(Config{Addr: "127.0.0.1:0", Capture: recorder})
+	err = server.Start(context.Background())
+	require.NoError(t, err)
+	defer server.Stop()
+
+	base := "http://" + server.Addr() + "/debug/capture"
+	decode := func(resp *http.Response) capture.Status {
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var st capture.Status
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&st))
+		return st
+	}
+
+	resp, err := http.Get(base)
+	require.NoError(t, err)
+	assert.False(t, decode(resp).Active)
+
+	// 开始录制需要 POST
+	resp, err = http.Get(base + "/start")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+
+	resp, err = http.Post(base+"/start?peer=a,b&protocol=/dep2p/app/", "", nil)
+	require.NoError(t, err)
+	st := decode(resp)
+	assert.True(t, st.Active)
+	assert.Equal(t, []string{"a", "b"}, st.Filter.Peers)
+	assert.Equal(t, []string{"/dep2p/app/"}, st.Filter.Protocols)
+
+	resp, err = http.Post(base+"/stop", "", nil)
+	require.NoError(t, err)
+	assert.False(t, decode(resp).Active)
+}
+
+func TestServer_CaptureEndpoints_Unavailable(t *testing.T) {
+	server := New(Config{Addr: "127.0.0.1:0"})
+	err := server.Start(context.Background())
+	require.NoError(t, err)
+	defer server.Stop()
+
+	resp, err := http.Get("http://" + server.Addr() + "/debug/capture")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
+
+func TestServer_BandwidthEndpoint_Limits(t *testing.T) {
+	limiter := bandwidth.NewLimiter(pkgif.BandwidthLimits{
+		Total: pkgif.BandwidthLimit{In: 4096, Out: 1024},
+	})
+	server := New(Config{Addr: "127.0.0.1:0", BandwidthLimiter: limiter})
+
+	err := server.Start(context.Background())
+	require.NoError(t, err)
+	defer server.Stop()
+
+	resp, err := http.Get("http://" + server.Addr() + "/debug/introspect/bandwidth")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var info BandwidthInfo
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&info))
+	require.Len(t, info.Limits, 1)
+	assert.Equal(t, "total", info.Limits[0].Scope)
+	assert.Equal(t, int64(1024), info.Limits[0].Limit.Out)
+}
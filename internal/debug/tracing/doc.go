@@ -0,0 +1,17 @@
+// Package tracing 将分布式追踪接入节点生命周期
+//
+// 节点启动时安装全局 Span Exporter，停止时恢复之前的 Exporter 并刷新。
+// Span 的记录与传播见 pkg/lib/tracing。
+//
+// # 导出器
+//
+//   - 配置 Diagnostics.Tracing.File 时使用 FileExporter，以 OTLP/JSON
+//     行格式追加写入文件，资源属性包含 service.name 与 dep2p.peer.id
+//   - 通过 dep2p.WithSpanExporter 注入自定义 Exporter 时优先使用，
+//     其生命周期由调用方负责，节点停止时不会调用 Shutdown
+//
+// # 注意
+//
+// Exporter 为进程级全局状态。同一进程运行多个节点时以最后启动的节点
+// 为准，Span 可通过 peer.id 等属性区分。
+package tracing
@@ -0,0 +1,91 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/dep2p/go-dep2p/config"
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/log"
+	libtracing "github.com/dep2p/go-dep2p/pkg/lib/tracing"
+	"go.uber.org/fx"
+)
+
+var logger = log.Logger("debug/tracing")
+
+// Module 返回分布式追踪 Fx 模块
+//
+// custom 非 nil 时使用自定义 Exporter，否则按配置创建文件导出器。
+func Module(custom libtracing.Exporter) fx.Option {
+	return fx.Module("tracing",
+		fx.Invoke(func(lc fx.Lifecycle, params TracingParams) {
+			registerLifecycle(lc, params, custom)
+		}),
+	)
+}
+
+// TracingParams 追踪模块依赖参数
+type TracingParams struct {
+	fx.In
+
+	UnifiedCfg *config.Config `optional:"true"`
+	Host       pkgif.Host     `optional:"true"`
+}
+
+// NewFileExporter 按配置创建文件导出器
+//
+// 资源属性包含 service.name，peerID 非空时追加 dep2p.peer.id。
+func NewFileExporter(cfg config.TracingConfig, peerID string) (*libtracing.FileExporter, error) {
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = config.DefaultTracingConfig().ServiceName
+	}
+	resource := []libtracing.Attribute{libtracing.Attr("service.name", serviceName)}
+	if peerID != "" {
+		resource = append(resource, libtracing.Attr("dep2p.peer.id", peerID))
+	}
+	return libtracing.NewFileExporter(cfg.File, resource...)
+}
+
+// registerLifecycle 注册生命周期钩子
+func registerLifecycle(lc fx.Lifecycle, params TracingParams, custom libtracing.Exporter) {
+	var exporter, prev libtracing.Exporter
+	owned := false
+
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			exporter = custom
+			if exporter == nil {
+				if params.UnifiedCfg == nil || !params.UnifiedCfg.Diagnostics.Tracing.Enabled {
+					return nil
+				}
+				var peerID string
+				if params.Host != nil {
+					peerID = params.Host.ID()
+				}
+				fileExporter, err := NewFileExporter(params.UnifiedCfg.Diagnostics.Tracing, peerID)
+				if err != nil {
+					return err
+				}
+				exporter, owned = fileExporter, true
+				logger.Info("分布式追踪已启用", "file", params.UnifiedCfg.Diagnostics.Tracing.File)
+			} else {
+				logger.Info("分布式追踪已启用（自定义 Exporter）")
+			}
+			prev = libtracing.SetExporter(exporter)
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			if exporter == nil {
+				return nil
+			}
+			// 其他节点已替换 Exporter 时不覆盖
+			if libtracing.CurrentExporter() == exporter {
+				libtracing.SetExporter(prev)
+			}
+			if owned {
+				return exporter.Shutdown(ctx)
+			}
+			return nil
+		},
+	})
+}
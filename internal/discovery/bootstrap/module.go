@@ -112,8 +112,23 @@ func ProvideBootstrap(input ModuleInput) (ModuleOutput, error) {
 var Module = fx.Module("discovery/bootstrap",
 	fx.Provide(ProvideBootstrap),
 	fx.Invoke(registerLifecycle),
+	fx.Invoke(registerCapabilities),
 )
 
+// capabilityInput 能力注册输入
+type capabilityInput struct {
+	fx.In
+	BootstrapService *BootstrapService        `name:"bootstrap_service"`
+	Capabilities     pkgif.CapabilityRegistry `optional:"true"`
+}
+
+// registerCapabilities 通过 Identify 通告 bootstrap 角色
+func registerCapabilities(input capabilityInput) {
+	if input.Capabilities != nil && input.BootstrapService != nil {
+		input.Capabilities.AddCapabilityProvider(input.BootstrapService.FillCapabilities)
+	}
+}
+
 // lifecycleInput Lifecycle 注册输入
 type lifecycleInput struct {
 	fx.In
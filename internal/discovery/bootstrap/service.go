@@ -205,6 +205,13 @@ func (s *BootstrapService) IsEnabled() bool {
 	return s.enabled.Load()
 }
 
+// FillCapabilities 启用引导能力时通告 bootstrap 角色
+func (s *BootstrapService) FillCapabilities(caps *types.PeerCapabilities) {
+	if s.IsEnabled() {
+		caps.AddRole(types.PeerRoleBootstrap)
+	}
+}
+
 // ════════════════════════════════════════════════════════════════════════════
 // 统计信息
 // ════════════════════════════════════════════════════════════════════════════
@@ -232,7 +232,11 @@ func (c *Coordinator) parallelFindPeers(
 		go func(d pkgif.Discovery) {
 			defer wg.Done()
 
-			ch, err := d.FindPeers(ctx, ns, pkgif.WithLimit(options.Limit))
+			findOpts := []pkgif.DiscoveryOption{pkgif.WithLimit(options.Limit)}
+			if options.Watch {
+				findOpts = append(findOpts, pkgif.WithWatch())
+			}
+			ch, err := d.FindPeers(ctx, ns, findOpts...)
 			if err != nil {
 				return
 			}
@@ -253,7 +257,11 @@ func (c *Coordinator) parallelFindPeers(
 		close(resultCh)
 	}()
 
-	// 去重并输出
+	// 去重并输出（持续订阅时不按数量截断）
+	if options.Watch {
+		c.watchDedupAndOutput(ctx, resultCh, out)
+		return
+	}
 	c.dedupAndOutput(ctx, resultCh, out, options.Limit)
 }
 
@@ -299,6 +307,53 @@ func (c *Coordinator) dedupAndOutput(
 	}
 }
 
+// watchDedupAndOutput 持续订阅模式下去重并输出结果
+//
+// 按节点 ID 与记录版本去重：多个发现组件报告同一版本只输出一次，
+// 节点重新注册（版本变化）时再次输出。节点过期事件移除去重记录并
+// 原样转发，之后该节点重新加入仍会输出。
+func (c *Coordinator) watchDedupAndOutput(
+	ctx context.Context,
+	in <-chan types.PeerInfo,
+	out chan<- types.PeerInfo,
+) {
+	seen := make(map[types.PeerID]uint64)
+
+	for peer := range in {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		if peer.Expired {
+			if _, ok := seen[peer.ID]; !ok {
+				continue
+			}
+			delete(seen, peer.ID)
+
+			if c.config.EnableCache {
+				c.removeCache(peer.ID)
+			}
+		} else {
+			if seq, ok := seen[peer.ID]; ok && seq == peer.Seq {
+				continue
+			}
+			seen[peer.ID] = peer.Seq
+
+			if c.config.EnableCache {
+				c.updateCache(peer)
+			}
+		}
+
+		select {
+		case out <- peer:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 // Advertise 广播自身（实现 Discovery 接口）
 func (c *Coordinator) Advertise(ctx context.Context, ns string, opts ...pkgif.DiscoveryOption) (time.Duration, error) {
 	if !c.started.Load() {
@@ -399,6 +454,23 @@ func (c *Coordinator) updateCache(peer types.PeerInfo) {
 	c.cacheOrder = append(c.cacheOrder, peer.ID)
 }
 
+// removeCache 移除缓存条目
+func (c *Coordinator) removeCache(id types.PeerID) {
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+
+	if _, exists := c.peerCache[id]; !exists {
+		return
+	}
+	delete(c.peerCache, id)
+	for i, cached := range c.cacheOrder {
+		if cached == id {
+			c.cacheOrder = append(c.cacheOrder[:i], c.cacheOrder[i+1:]...)
+			break
+		}
+	}
+}
+
 // cacheCleanupLoop 缓存清理循环
 func (c *Coordinator) cacheCleanupLoop() {
 	ticker := time.NewTicker(c.config.CacheTTL)
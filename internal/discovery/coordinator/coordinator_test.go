@@ -216,6 +216,39 @@ func TestCoordinator_FindPeers_Dedup(t *testing.T) {
 	assert.Equal(t, 1, len(peers))
 }
 
+// TestCoordinator_FindPeers_WatchDedup 测试持续订阅按节点与记录版本去重
+func TestCoordinator_FindPeers_WatchDedup(t *testing.T) {
+	coord := NewCoordinator(DefaultConfig())
+
+	v1 := types.PeerInfo{ID: "peer1", Seq: 1}
+	v2 := types.PeerInfo{ID: "peer1", Seq: 2}
+	gone := types.PeerInfo{ID: "peer1", Seq: 2, Expired: true}
+	stray := types.PeerInfo{ID: "peer2", Expired: true}
+
+	mock := &mockDiscovery{name: "test", peers: []types.PeerInfo{v1, v1, v2, v2, gone, stray, v2}}
+	coord.RegisterDiscovery("test", mock)
+
+	ctx := context.Background()
+	require.NoError(t, coord.Start(ctx))
+
+	ch, err := coord.FindPeers(ctx, "test", pkgif.WithWatch())
+	require.NoError(t, err)
+
+	var peers []types.PeerInfo
+	for peer := range ch {
+		peers = append(peers, peer)
+	}
+
+	// 同一版本只输出一次，版本变化再次输出；过期后去重记录被清除，
+	// 重新加入时再次输出；从未输出过的节点的过期事件被忽略
+	require.Len(t, peers, 4)
+	assert.Equal(t, uint64(1), peers[0].Seq)
+	assert.Equal(t, uint64(2), peers[1].Seq)
+	assert.True(t, peers[2].Expired)
+	assert.False(t, peers[3].Expired)
+	assert.Equal(t, uint64(2), peers[3].Seq)
+}
+
 // TestCoordinator_Advertise 测试广播
 func TestCoordinator_Advertise(t *testing.T) {
 	coord := NewCoordinator(DefaultConfig())
@@ -56,6 +56,12 @@ type DHT struct {
 	// reachabilityChecker 可达性检测器（用于发布前验证）
 	reachabilityChecker ReachabilityChecker
 
+	// dataBudget 数据预算（可选，计费网络下放慢后台刷新）
+	dataBudget pkgif.DataBudget
+
+	// reputation 节点信誉（可选，迭代查询跳过被封禁的节点）
+	reputation pkgif.Reputation
+
 	// providerCache Provider 查询结果缓存
 	// v2.0.1: 缓存 DHT Provider 查询结果，减少重复查询
 	providerCache *ProviderCache
@@ -83,6 +89,38 @@ func (d *DHT) SetEventBus(eb pkgif.EventBus) {
 	d.eventBus = eb
 }
 
+// SetDataBudget 设置数据预算（可选）
+// 设置后，路由表刷新与地址变化续期检查按预算节流；定时续期不受影响，避免 PeerRecord 过期
+func (d *DHT) SetDataBudget(budget pkgif.DataBudget) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.dataBudget = budget
+}
+
+// SetReputation 设置信誉服务（可选）
+// 设置后，迭代查询跳过被信誉封禁的节点
+func (d *DHT) SetReputation(rep pkgif.Reputation) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.reputation = rep
+}
+
+// isBanned 检查节点是否被信誉封禁
+func (d *DHT) isBanned(id types.NodeID) bool {
+	d.mu.RLock()
+	rep := d.reputation
+	d.mu.RUnlock()
+	return rep != nil && rep.IsBanned(string(id))
+}
+
+// allowBackground 检查数据预算是否允许执行后台任务
+func (d *DHT) allowBackground(task string, interval time.Duration) bool {
+	d.mu.RLock()
+	budget := d.dataBudget
+	d.mu.RUnlock()
+	return budget == nil || budget.Allow(pkgif.BudgetSubsystemDHT, task, interval)
+}
+
 // New 创建 DHT 实例
 func New(host pkgif.Host, peerstore pkgif.Peerstore, opts ...ConfigOption) (*DHT, error) {
 	if host == nil {
@@ -1178,6 +1216,9 @@ func (d *DHT) refreshLoop() {
 	for {
 		select {
 		case <-ticker.C:
+			if !d.allowBackground("refresh", d.config.RefreshInterval) {
+				continue
+			}
 			// 刷新路由表
 			d.routingTable.RemoveExpiredNodes()
 
@@ -1253,7 +1294,10 @@ func (d *DHT) republishLoop() {
 			d.republishLocalPeerRecordIfNeeded("interval")
 
 		case <-addressCheckTicker.C:
-			// 地址变化检测
+			// 地址变化检测（计费网络下按数据预算节流）
+			if !d.allowBackground("address_check", time.Minute) {
+				continue
+			}
 			d.republishLocalPeerRecordIfNeeded("address_check")
 
 		case <-d.ctx.Done():
@@ -457,7 +457,7 @@ func (m *mockHost) Connect(ctx context.Context, peerID string, addrs []string) e
 	return nil
 }
 
-func (m *mockHost) SetStreamHandler(protocolID string, handler pkgif.StreamHandler) {
+func (m *mockHost) SetStreamHandler(protocolID string, handler pkgif.StreamHandler, _ ...pkgif.StreamHandlerOption) {
 }
 
 func (m *mockHost) RemoveStreamHandler(protocolID string) {
@@ -30,6 +30,8 @@ type Params struct {
 	UnifiedCfg  *config.Config                  `optional:"true"`
 	Identity    pkgif.Identity                  `optional:"true"` // Step A5: 用于签名 PeerRecord
 	Coordinator pkgif.ReachabilityCoordinator   `name:"reachability_coordinator" optional:"true"` // Step A5: 可达性协调器
+	DataBudget  pkgif.DataBudget                `optional:"true"` // 数据预算，计费网络下放慢后台刷新
+	Reputation  pkgif.Reputation                `optional:"true"` // 节点信誉，查询跳过被封禁的节点
 }
 
 // Result DHT 导出结果
@@ -126,6 +128,16 @@ func NewFromParams(p Params) (Result, error) {
 		logger.Info("DHT EventBus 已设置，将自动处理连接事件")
 	}
 
+	// 设置 DataBudget 用于后台刷新节流
+	if p.DataBudget != nil {
+		dht.SetDataBudget(p.DataBudget)
+	}
+
+	// 设置信誉服务，迭代查询跳过被封禁的节点
+	if p.Reputation != nil {
+		dht.SetReputation(p.Reputation)
+	}
+
 	// Step A5 对齐：初始化 LocalPeerRecordManager
 	// 使用 Identity 的私钥进行 PeerRecord 签名
 	if p.Identity != nil {
@@ -227,8 +227,12 @@ func (q *iterativeQuery) getNextBatch() []*RoutingNode {
 		if len(nodesToQuery) < availableSlots {
 			// 检查是否已查询过
 			if _, queried := q.queried[node.ID]; !queried {
-				nodesToQuery = append(nodesToQuery, node)
 				q.queried[node.ID] = struct{}{}
+				// 跳过被信誉封禁的节点
+				if q.dht.isBanned(node.ID) {
+					continue
+				}
+				nodesToQuery = append(nodesToQuery, node)
 			}
 		} else {
 			remaining = append(remaining, node)
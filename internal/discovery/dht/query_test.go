@@ -3,6 +3,8 @@ package dht
 import (
 	"testing"
 
+	"github.com/dep2p/go-dep2p/internal/core/reputation"
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
 	"github.com/dep2p/go-dep2p/pkg/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -130,6 +132,36 @@ func TestGetNextBatch_SkipQueried(t *testing.T) {
 	t.Log("✅ 正确跳过已查询节点")
 }
 
+// TestGetNextBatch_SkipBanned 测试跳过被信誉封禁的节点
+func TestGetNextBatch_SkipBanned(t *testing.T) {
+	rep, err := reputation.New(pkgif.DefaultReputationConfig(), nil)
+	require.NoError(t, err)
+	dht := &DHT{}
+	dht.SetReputation(rep)
+
+	// 累积到封禁阈值以下
+	rep.Observe("peer-2", pkgif.ReputationSignalPubSub, -1, "graylisted")
+	rep.Observe("peer-2", pkgif.ReputationSignalWitness, -1, "rejected_report")
+	rep.Observe("peer-2", pkgif.ReputationSignalDial, -1, "dial_failed")
+	require.True(t, rep.IsBanned("peer-2"))
+
+	q := newIterativeQuery(dht, "target", MessageTypeFindNode, "")
+	q.pending = []*RoutingNode{
+		{ID: "peer-1"},
+		{ID: "peer-2"},
+		{ID: "peer-3"},
+	}
+
+	batch := q.getNextBatch()
+
+	assert.Equal(t, 2, len(batch))
+	for _, node := range batch {
+		assert.NotEqual(t, "peer-2", string(node.ID), "不应该返回被封禁的节点")
+	}
+	_, queried := q.queried["peer-2"]
+	assert.True(t, queried, "被封禁的节点应标记为已处理")
+}
+
 // ============================================================================
 // containsNode 测试
 // ============================================================================
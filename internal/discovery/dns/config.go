@@ -3,6 +3,8 @@ package dns
 import (
 	"errors"
 	"time"
+
+	"github.com/dep2p/go-dep2p/pkg/types"
 )
 
 // ============================================================================
@@ -28,6 +30,12 @@ type Config struct {
 
 	// RefreshInterval 后台刷新间隔
 	RefreshInterval time.Duration
+
+	// RequireSigned 只接受签名记录，需同时配置 TrustedSigners
+	RequireSigned bool
+
+	// TrustedSigners 信任的记录签名者（通常为发布记录的基础设施节点）
+	TrustedSigners []types.PeerID
 }
 
 // DefaultConfig 默认配置
@@ -59,5 +67,8 @@ func (c *Config) Validate() error {
 	if c.RefreshInterval <= 0 {
 		return errors.New("refresh interval must be positive")
 	}
+	if c.RequireSigned && len(c.TrustedSigners) == 0 {
+		return errors.New("require signed records without trusted signers")
+	}
 	return nil
 }
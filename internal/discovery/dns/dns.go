@@ -46,6 +46,8 @@ func NewDiscoverer(config Config) *Discoverer {
 		MaxDepth:       config.MaxDepth,
 		CustomResolver: config.CustomResolver,
 		CacheTTL:       config.CacheTTL,
+		RequireSigned:  config.RequireSigned,
+		TrustedSigners: config.TrustedSigners,
 	}
 
 	d := &Discoverer{
@@ -26,6 +26,14 @@
 //   - 定期刷新配置的域名
 //   - 更新节点列表
 //
+// 5. 签名记录
+//   - dnssig= 记录携带签名者公钥、过期时间和签名
+//   - RequireSigned 时只接受 TrustedSigners 签名的记录
+//
+// 6. 权威应答器
+//   - Responder 为 _dnsaddr.<zone> 应答 TXT 查询
+//   - 记录由本节点和健康的同伴基础设施节点生成并签名
+//
 // # DNS 记录格式
 //
 // ## dnsaddr TXT 记录
@@ -120,6 +128,8 @@
 //   - MaxDepth: 3 - 最大递归深度
 //   - CacheTTL: 5min - 缓存 TTL
 //   - RefreshInterval: 5min - 刷新间隔
+//   - RequireSigned: false - 只接受签名记录
+//   - TrustedSigners: [] - 受信任的签名者
 //
 // # 生命周期
 //
@@ -127,6 +137,12 @@
 //
 //  1. refreshLoop(): 后台刷新循环（RefreshInterval）
 //
+// ## Responder
+//
+//  1. Start(): 启动 UDP/TCP 服务器
+//  2. refreshLoop(): 检查节点健康并重新签名（RefreshInterval）
+//  3. Stop(): 关闭服务器
+//
 // # 并发安全
 //
 // 所有公共方法都是并发安全的：
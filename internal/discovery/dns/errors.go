@@ -27,4 +27,22 @@ var (
 
 	// ErrEmptyDomain 空域名
 	ErrEmptyDomain = errors.New("dns: empty domain")
+
+	// ErrUnsignedRecord 记录未签名
+	ErrUnsignedRecord = errors.New("dns: unsigned record")
+
+	// ErrInvalidSignature 签名记录无效
+	ErrInvalidSignature = errors.New("dns: invalid record signature")
+
+	// ErrSignatureExpired 签名记录已过期
+	ErrSignatureExpired = errors.New("dns: record signature expired")
+
+	// ErrUntrustedSigner 签名者不在信任列表中
+	ErrUntrustedSigner = errors.New("dns: untrusted record signer")
+
+	// ErrNilSigningKey 签名密钥为空
+	ErrNilSigningKey = errors.New("dns: nil signing key")
+
+	// ErrResponderStarted 应答器已启动
+	ErrResponderStarted = errors.New("dns: responder already started")
 )
@@ -1,10 +1,14 @@
 package dns
 
 import (
+	"context"
+	"time"
+
 	"go.uber.org/fx"
 
 	"github.com/dep2p/go-dep2p/config"
 	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/types"
 )
 
 // Module DNS 发现模块
@@ -43,9 +47,23 @@ func ConfigFromUnified(cfg *config.Config) Config {
 		MaxDepth:        3, // 默认值
 		CustomResolver:  cfg.Discovery.DNS.ResolverURL,
 		RefreshInterval: cfg.Discovery.DNS.Timeout.Duration() * 30, // 刷新间隔为超时的30倍
+		RequireSigned:   cfg.Discovery.DNS.RequireSigned,
+		TrustedSigners:  toPeerIDs(cfg.Discovery.DNS.TrustedSigners),
 	}
 }
 
+// toPeerIDs 转换 PeerID 字符串列表
+func toPeerIDs(ids []string) []types.PeerID {
+	if len(ids) == 0 {
+		return nil
+	}
+	out := make([]types.PeerID, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, types.PeerID(id))
+	}
+	return out
+}
+
 // NewFromParams 从 Fx 参数创建 Discoverer
 func NewFromParams(p Params) (Result, error) {
 	cfg := ConfigFromUnified(p.UnifiedCfg)
@@ -56,3 +74,76 @@ func NewFromParams(p Params) (Result, error) {
 		Discovery: discoverer,
 	}, nil
 }
+
+// ============================================================================
+//                              权威应答器模块
+// ============================================================================
+
+// ResponderModule 权威 DNS 应答器模块（基础设施节点）
+var ResponderModule = fx.Module("discovery_dns_responder",
+	fx.Provide(NewResponderFromParams),
+	fx.Invoke(registerResponderLifecycle),
+)
+
+// ResponderParams 应答器依赖参数
+type ResponderParams struct {
+	fx.In
+
+	Host       pkgif.Host
+	Identity   pkgif.Identity
+	UnifiedCfg *config.Config `optional:"true"`
+}
+
+// ResponderConfigFromUnified 从统一配置创建应答器配置
+func ResponderConfigFromUnified(cfg *config.Config) ResponderConfig {
+	rc := DefaultResponderConfig()
+	if cfg == nil {
+		return rc
+	}
+	src := cfg.Discovery.DNS.Responder
+	rc.Zone = src.Zone
+	if src.ListenAddr != "" {
+		rc.ListenAddr = src.ListenAddr
+	}
+	if src.RecordTTL > 0 {
+		rc.RecordTTL = src.RecordTTL.Duration()
+	}
+	if src.SignatureValidity > 0 {
+		rc.SignatureValidity = src.SignatureValidity.Duration()
+	}
+	rc.PublishUnsigned = !src.SignedOnly
+	return rc
+}
+
+// NewResponderFromParams 从 Fx 参数创建应答器
+func NewResponderFromParams(p ResponderParams) (*Responder, error) {
+	var peerAddrs []string
+	if p.UnifiedCfg != nil {
+		peerAddrs = p.UnifiedCfg.Discovery.DNS.Responder.Peers
+	}
+
+	var peers []types.PeerInfo
+	for _, s := range peerAddrs {
+		info, err := types.AddrInfoFromString(s)
+		if err != nil {
+			logger.Warn("忽略无效的同伴基础设施节点地址", "addr", s, "error", err)
+			continue
+		}
+		peers = append(peers, info.ToPeerInfo())
+	}
+
+	source := NewHostNodeSource(p.Host, peers, 5*time.Second)
+	return NewResponder(ResponderConfigFromUnified(p.UnifiedCfg), p.Identity.PrivateKey(), source)
+}
+
+// registerResponderLifecycle 注册应答器生命周期
+func registerResponderLifecycle(lc fx.Lifecycle, r *Responder) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return r.Start(ctx)
+		},
+		OnStop: func(ctx context.Context) error {
+			return r.Stop(ctx)
+		},
+	})
+}
@@ -41,6 +41,12 @@ type ResolverConfig struct {
 
 	// CacheTTL 缓存 TTL
 	CacheTTL time.Duration
+
+	// RequireSigned 只接受签名记录（dnssig=），拒绝普通 dnsaddr 记录
+	RequireSigned bool
+
+	// TrustedSigners 信任的签名者；非空时只接受这些节点签名的记录
+	TrustedSigners []types.PeerID
 }
 
 // DefaultResolverConfig 默认配置
@@ -139,7 +145,14 @@ func (r *Resolver) ResolveWithDepth(ctx context.Context, domain string, maxDepth
 	var peers []types.PeerInfo
 	seen := make(map[string]bool)
 
-	for _, record := range records {
+	now := time.Now()
+	for _, txt := range records {
+		record, err := r.acceptRecord(txt, domain, now)
+		if err != nil {
+			logger.Debug("忽略 DNS 记录", "domain", domain, "error", err)
+			continue
+		}
+
 		peer, nestedDomain, err := ParseDNSAddr(record)
 		if err != nil {
 			continue
@@ -175,6 +188,37 @@ func (r *Resolver) ResolveWithDepth(ctx context.Context, domain string, maxDepth
 	return peers, nil
 }
 
+// acceptRecord 检查记录签名，返回可解析的 dnsaddr 记录
+//
+// 签名记录验证通过后返回内层记录；普通记录在 RequireSigned 时被拒绝。
+func (r *Resolver) acceptRecord(txt, domain string, now time.Time) (string, error) {
+	if !strings.HasPrefix(txt, DNSSigPrefix) {
+		if r.config.RequireSigned {
+			return "", ErrUnsignedRecord
+		}
+		return txt, nil
+	}
+
+	signed, err := VerifyDNSSig(txt, domain, now)
+	if err != nil {
+		return "", err
+	}
+	if len(r.config.TrustedSigners) > 0 && !containsPeer(r.config.TrustedSigners, signed.Signer) {
+		return "", fmt.Errorf("%w: %s", ErrUntrustedSigner, signed.Signer)
+	}
+	return signed.Record, nil
+}
+
+// containsPeer 检查列表是否包含节点
+func containsPeer(list []types.PeerID, id types.PeerID) bool {
+	for _, p := range list {
+		if p == id {
+			return true
+		}
+	}
+	return false
+}
+
 // resolveTXT 查询 DNS TXT 记录
 func (r *Resolver) resolveTXT(ctx context.Context, domain string) ([]string, error) {
 	// 设置超时
@@ -0,0 +1,469 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	miekg "github.com/miekg/dns"
+
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/types"
+)
+
+// ============================================================================
+//                              应答器配置
+// ============================================================================
+
+// ResponderConfig 权威 DNS 应答器配置
+type ResponderConfig struct {
+	// Zone 权威区域，例如 "bootstrap.example.com"
+	// 应答 _dnsaddr.<Zone> 的 TXT 查询，需在上级域中将该区域委派给本节点
+	Zone string
+
+	// ListenAddr 监听地址（UDP 与 TCP），默认 ":53"
+	ListenAddr string
+
+	// RecordTTL 应答记录 TTL
+	RecordTTL time.Duration
+
+	// RefreshInterval 记录重新生成间隔（重新检查节点健康并重新签名）
+	RefreshInterval time.Duration
+
+	// SignatureValidity 签名有效期，应大于 RefreshInterval + RecordTTL
+	SignatureValidity time.Duration
+
+	// PublishUnsigned 是否同时发布普通 dnsaddr 记录（兼容不验证签名的客户端）
+	PublishUnsigned bool
+
+	// MaxNodes 最多发布的节点数
+	MaxNodes int
+}
+
+// DefaultResponderConfig 默认应答器配置
+func DefaultResponderConfig() ResponderConfig {
+	return ResponderConfig{
+		ListenAddr:        ":53",
+		RecordTTL:         60 * time.Second,
+		RefreshInterval:   30 * time.Second,
+		SignatureValidity: time.Hour,
+		PublishUnsigned:   true,
+		MaxNodes:          16,
+	}
+}
+
+// Validate 验证配置
+func (c *ResponderConfig) Validate() error {
+	if err := ValidateDomain(c.Zone); err != nil {
+		return err
+	}
+	if c.ListenAddr == "" {
+		return errors.New("listen address is empty")
+	}
+	if c.RecordTTL <= 0 || c.RefreshInterval <= 0 {
+		return errors.New("record TTL and refresh interval must be positive")
+	}
+	if c.SignatureValidity <= c.RefreshInterval+c.RecordTTL {
+		return errors.New("signature validity must exceed refresh interval plus record TTL")
+	}
+	if c.MaxNodes <= 0 {
+		return errors.New("max nodes must be positive")
+	}
+	return nil
+}
+
+// ============================================================================
+//                              节点来源
+// ============================================================================
+
+// NodeSource 提供当前健康的基础设施节点（Bootstrap / Relay）
+type NodeSource interface {
+	// Nodes 返回当前健康的节点及其可公开地址
+	Nodes(ctx context.Context) []types.PeerInfo
+}
+
+// HostNodeSource 基于 Host 的节点来源
+//
+// 包含本节点（使用 ShareableAddrs）以及配置的同伴基础设施节点；
+// 同伴节点只有在本轮连接检查成功时才被视为健康。
+type HostNodeSource struct {
+	host         pkgif.Host
+	peers        []types.PeerInfo
+	checkTimeout time.Duration
+}
+
+// NewHostNodeSource 创建基于 Host 的节点来源
+func NewHostNodeSource(host pkgif.Host, peers []types.PeerInfo, checkTimeout time.Duration) *HostNodeSource {
+	if checkTimeout <= 0 {
+		checkTimeout = 5 * time.Second
+	}
+	return &HostNodeSource{host: host, peers: peers, checkTimeout: checkTimeout}
+}
+
+// Nodes 实现 NodeSource
+func (s *HostNodeSource) Nodes(ctx context.Context) []types.PeerInfo {
+	var nodes []types.PeerInfo
+
+	if self := s.self(); len(self.Addrs) > 0 {
+		nodes = append(nodes, self)
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, peer := range s.peers {
+		if string(peer.ID) == s.host.ID() {
+			continue
+		}
+		wg.Add(1)
+		go func(peer types.PeerInfo) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, s.checkTimeout)
+			defer cancel()
+
+			addrs := make([]string, 0, len(peer.Addrs))
+			for _, addr := range peer.Addrs {
+				addrs = append(addrs, addr.String())
+			}
+			if err := s.host.Connect(checkCtx, string(peer.ID), addrs); err != nil {
+				logger.Debug("基础设施节点不健康，跳过发布", "peer", peer.ID.ShortString(), "error", err)
+				return
+			}
+			mu.Lock()
+			nodes = append(nodes, peer)
+			mu.Unlock()
+		}(peer)
+	}
+	wg.Wait()
+
+	return nodes
+}
+
+// self 返回本节点信息
+func (s *HostNodeSource) self() types.PeerInfo {
+	info := types.PeerInfo{ID: types.PeerID(s.host.ID())}
+	for _, addrStr := range s.host.ShareableAddrs() {
+		addr, err := types.NewMultiaddr(addrStr)
+		if err != nil {
+			continue
+		}
+		info.Addrs = append(info.Addrs, addr)
+	}
+	return info
+}
+
+// ============================================================================
+//                              Responder 实现
+// ============================================================================
+
+// Responder 权威 DNS 应答器
+//
+// 在基础设施节点内运行，根据 NodeSource 提供的健康节点生成
+// _dnsaddr.<Zone> TXT 记录，并用节点身份密钥签名。
+type Responder struct {
+	config ResponderConfig
+	key    pkgif.PrivateKey
+	source NodeSource
+	zone   string // 规范化的区域名（带尾随点）
+
+	mu      sync.RWMutex
+	records []string // 当前 TXT 记录
+	serial  uint32   // SOA 序列号（记录生成时间）
+
+	servers []*miekg.Server
+	ctx     context.Context
+	cancel  context.CancelFunc
+	started atomic.Bool
+	wg      sync.WaitGroup
+}
+
+// NewResponder 创建权威 DNS 应答器
+func NewResponder(config ResponderConfig, key pkgif.PrivateKey, source NodeSource) (*Responder, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, ErrNilSigningKey
+	}
+	if source == nil {
+		return nil, errors.New("dns: nil node source")
+	}
+
+	return &Responder{
+		config: config,
+		key:    key,
+		source: source,
+		zone:   miekg.Fqdn(canonicalZone(config.Zone)),
+	}, nil
+}
+
+// Start 启动应答器（UDP + TCP）
+func (r *Responder) Start(_ context.Context) error {
+	if r.started.Swap(true) {
+		return ErrResponderStarted
+	}
+
+	// 与 Discoverer 相同：Fx OnStart 的 ctx 返回后会被取消
+	r.ctx, r.cancel = context.WithCancel(context.Background())
+
+	for _, network := range []string{"udp", "tcp"} {
+		server, err := r.listen(network)
+		if err != nil {
+			r.shutdownServers()
+			r.cancel()
+			r.started.Store(false)
+			return fmt.Errorf("dns responder listen %s %s: %w", network, r.config.ListenAddr, err)
+		}
+		r.servers = append(r.servers, server)
+	}
+
+	r.wg.Add(1)
+	go r.refreshLoop()
+
+	logger.Info("DNS 应答器已启动", "zone", r.zone, "listen", r.config.ListenAddr)
+	return nil
+}
+
+// Stop 停止应答器
+func (r *Responder) Stop(_ context.Context) error {
+	if !r.started.Swap(false) {
+		return nil
+	}
+	r.cancel()
+	r.shutdownServers()
+	r.wg.Wait()
+	logger.Info("DNS 应答器已停止", "zone", r.zone)
+	return nil
+}
+
+// Addrs 返回实际监听地址（UDP 在前）
+func (r *Responder) Addrs() []net.Addr {
+	var addrs []net.Addr
+	for _, s := range r.servers {
+		if s.PacketConn != nil {
+			addrs = append(addrs, s.PacketConn.LocalAddr())
+		} else if s.Listener != nil {
+			addrs = append(addrs, s.Listener.Addr())
+		}
+	}
+	return addrs
+}
+
+// Records 返回当前发布的 TXT 记录
+func (r *Responder) Records() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]string(nil), r.records...)
+}
+
+// Refresh 立即重新生成记录
+func (r *Responder) Refresh(ctx context.Context) {
+	nodes := r.source.Nodes(ctx)
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	if len(nodes) > r.config.MaxNodes {
+		nodes = nodes[:r.config.MaxNodes]
+	}
+
+	now := time.Now()
+	expires := now.Add(r.config.SignatureValidity)
+
+	var records []string
+	for _, node := range nodes {
+		for _, addr := range node.Addrs {
+			record := DNSAddrPrefix + withPeerID(addr, node.ID)
+			if r.config.PublishUnsigned {
+				records = append(records, record)
+			}
+			signed, err := SignDNSAddr(r.key, r.zone, record, expires)
+			if err != nil {
+				logger.Warn("签名 dnsaddr 记录失败", "error", err)
+				continue
+			}
+			records = append(records, signed)
+		}
+	}
+
+	r.mu.Lock()
+	r.records = records
+	r.serial = uint32(now.Unix())
+	r.mu.Unlock()
+
+	logger.Debug("DNS 应答器记录已更新", "nodes", len(nodes), "records", len(records))
+}
+
+// refreshLoop 定期重新生成记录
+//
+// 首轮生成在后台进行，避免同伴健康检查阻塞启动。
+func (r *Responder) refreshLoop() {
+	defer r.wg.Done()
+
+	r.Refresh(r.ctx)
+
+	ticker := time.NewTicker(r.config.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+			r.Refresh(r.ctx)
+		}
+	}
+}
+
+// listen 在指定网络上启动 DNS 服务器
+func (r *Responder) listen(network string) (*miekg.Server, error) {
+	server := &miekg.Server{Net: network, Handler: miekg.HandlerFunc(r.ServeDNS)}
+
+	switch network {
+	case "udp":
+		pc, err := net.ListenPacket("udp", r.config.ListenAddr)
+		if err != nil {
+			return nil, err
+		}
+		server.PacketConn = pc
+	default:
+		// TCP 使用与 UDP 相同的端口（ListenAddr 端口为 0 时跟随 UDP 实际端口）
+		addr := r.config.ListenAddr
+		if len(r.servers) > 0 {
+			addr = r.servers[0].PacketConn.LocalAddr().String()
+		}
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			return nil, err
+		}
+		server.Listener = l
+	}
+
+	started := make(chan struct{})
+	server.NotifyStartedFunc = func() { close(started) }
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		if err := server.ActivateAndServe(); err != nil {
+			logger.Debug("DNS 服务器退出", "net", network, "error", err)
+		}
+	}()
+	<-started
+
+	return server, nil
+}
+
+// shutdownServers 关闭所有 DNS 服务器
+func (r *Responder) shutdownServers() {
+	for _, s := range r.servers {
+		if err := s.Shutdown(); err != nil {
+			logger.Debug("关闭 DNS 服务器失败", "error", err)
+		}
+	}
+	r.servers = nil
+}
+
+// ServeDNS 处理 DNS 查询（实现 dns.Handler）
+func (r *Responder) ServeDNS(w miekg.ResponseWriter, req *miekg.Msg) {
+	resp := new(miekg.Msg)
+	resp.SetReply(req)
+	resp.Authoritative = true
+
+	if len(req.Question) != 1 {
+		resp.Rcode = miekg.RcodeFormatError
+		_ = w.WriteMsg(resp)
+		return
+	}
+
+	q := req.Question[0]
+	name := strings.ToLower(q.Name)
+	recordName := DNSAddrDomainPrefix + r.zone
+
+	switch {
+	case !miekg.IsSubDomain(r.zone, name):
+		resp.Authoritative = false
+		resp.Rcode = miekg.RcodeRefused
+	case name == recordName && (q.Qtype == miekg.TypeTXT || q.Qtype == miekg.TypeANY):
+		resp.Answer = r.txtAnswers(recordName)
+	case name == r.zone && (q.Qtype == miekg.TypeSOA || q.Qtype == miekg.TypeANY):
+		resp.Answer = []miekg.RR{r.soa()}
+	case name == recordName || name == r.zone:
+		// 名称存在但无该类型记录（NODATA）
+		resp.Ns = []miekg.RR{r.soa()}
+	default:
+		resp.Rcode = miekg.RcodeNameError
+		resp.Ns = []miekg.RR{r.soa()}
+	}
+
+	// UDP 响应按客户端声明的大小截断
+	if _, isUDP := w.RemoteAddr().(*net.UDPAddr); isUDP {
+		size := miekg.MinMsgSize
+		if opt := req.IsEdns0(); opt != nil {
+			size = int(opt.UDPSize())
+		}
+		resp.Truncate(size)
+	}
+
+	if err := w.WriteMsg(resp); err != nil {
+		logger.Debug("写入 DNS 响应失败", "error", err)
+	}
+}
+
+// txtAnswers 构建 TXT 应答
+func (r *Responder) txtAnswers(name string) []miekg.RR {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ttl := uint32(r.config.RecordTTL / time.Second)
+	answers := make([]miekg.RR, 0, len(r.records))
+	for _, record := range r.records {
+		answers = append(answers, &miekg.TXT{
+			Hdr: miekg.RR_Header{Name: name, Rrtype: miekg.TypeTXT, Class: miekg.ClassINET, Ttl: ttl},
+			Txt: splitTXT(record),
+		})
+	}
+	return answers
+}
+
+// soa 构建区域 SOA 记录
+func (r *Responder) soa() miekg.RR {
+	r.mu.RLock()
+	serial := r.serial
+	r.mu.RUnlock()
+
+	ttl := uint32(r.config.RecordTTL / time.Second)
+	return &miekg.SOA{
+		Hdr:     miekg.RR_Header{Name: r.zone, Rrtype: miekg.TypeSOA, Class: miekg.ClassINET, Ttl: ttl},
+		Ns:      "ns." + r.zone,
+		Mbox:    "hostmaster." + r.zone,
+		Serial:  serial,
+		Refresh: uint32(r.config.RefreshInterval / time.Second),
+		Retry:   uint32(r.config.RefreshInterval / time.Second),
+		Expire:  uint32(r.config.SignatureValidity / time.Second),
+		Minttl:  ttl,
+	}
+}
+
+// splitTXT 将记录拆分为不超过 255 字节的字符串（解析端会将其拼接）
+func splitTXT(record string) []string {
+	const maxLen = 255
+	var parts []string
+	for len(record) > maxLen {
+		parts = append(parts, record[:maxLen])
+		record = record[maxLen:]
+	}
+	return append(parts, record)
+}
+
+// withPeerID 确保地址以 /p2p/<peerID> 结尾
+func withPeerID(addr types.Multiaddr, id types.PeerID) string {
+	if _, err := addr.ValueForProtocol(types.P_P2P); err == nil {
+		return addr.String()
+	}
+	return addr.String() + "/p2p/" + string(id)
+}
@@ -0,0 +1,233 @@
+package dns
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	miekg "github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dep2p/go-dep2p/internal/core/identity"
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/types"
+)
+
+const testZone = "bootstrap.dep2p.test"
+
+// staticSource 固定节点来源
+type staticSource []types.PeerInfo
+
+func (s staticSource) Nodes(context.Context) []types.PeerInfo { return s }
+
+// newTestKey 生成签名密钥及其 PeerID
+func newTestKey(t *testing.T) (pkgif.PrivateKey, types.PeerID) {
+	t.Helper()
+	priv, pub, err := identity.GenerateEd25519Key()
+	require.NoError(t, err)
+	id, err := identity.PeerIDFromPublicKey(pub)
+	require.NoError(t, err)
+	return priv, types.PeerID(id)
+}
+
+// testNode 构造节点信息
+func testNode(t *testing.T, id types.PeerID, addr string) types.PeerInfo {
+	t.Helper()
+	ma, err := types.NewMultiaddr(addr)
+	require.NoError(t, err)
+	return types.PeerInfo{ID: id, Addrs: []types.Multiaddr{ma}}
+}
+
+// TestSignedRecord_RoundTrip 测试签名记录生成与验证
+func TestSignedRecord_RoundTrip(t *testing.T) {
+	key, signer := newTestKey(t)
+	record := DNSAddrPrefix + "/ip4/1.2.3.4/tcp/4001/p2p/" + string(signer)
+	now := time.Now()
+
+	txt, err := SignDNSAddr(key, testZone, record, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(txt, DNSSigPrefix))
+	assert.True(t, strings.HasSuffix(txt, record))
+
+	// 区域名不区分大小写，带不带 _dnsaddr. 前缀均可
+	signed, err := VerifyDNSSig(txt, DNSAddrDomainPrefix+strings.ToUpper(testZone)+".", now)
+	require.NoError(t, err)
+	assert.Equal(t, record, signed.Record)
+	assert.Equal(t, signer, signed.Signer)
+
+	// 其他区域
+	_, err = VerifyDNSSig(txt, "other.dep2p.test", now)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	// 篡改记录
+	tampered := strings.Replace(txt, "1.2.3.4", "6.6.6.6", 1)
+	_, err = VerifyDNSSig(tampered, testZone, now)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	// 过期
+	_, err = VerifyDNSSig(txt, testZone, now.Add(2*time.Hour))
+	assert.ErrorIs(t, err, ErrSignatureExpired)
+
+	// 格式错误与未签名
+	_, err = VerifyDNSSig(DNSSigPrefix+"1 x", testZone, now)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+	_, err = VerifyDNSSig(record, testZone, now)
+	assert.ErrorIs(t, err, ErrUnsignedRecord)
+
+	_, err = SignDNSAddr(nil, testZone, record, now)
+	assert.ErrorIs(t, err, ErrNilSigningKey)
+}
+
+// TestResolver_AcceptRecord 测试签名策略
+func TestResolver_AcceptRecord(t *testing.T) {
+	key, signer := newTestKey(t)
+	_, other := newTestKey(t)
+	record := DNSAddrPrefix + "/ip4/1.2.3.4/tcp/4001/p2p/" + string(signer)
+	txt, err := SignDNSAddr(key, testZone, record, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	domain := DNSAddrDomainPrefix + testZone
+
+	cfg := DefaultResolverConfig()
+	r := NewResolver(cfg)
+	got, err := r.acceptRecord(record, domain, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, record, got)
+	got, err = r.acceptRecord(txt, domain, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, record, got)
+
+	cfg.RequireSigned = true
+	cfg.TrustedSigners = []types.PeerID{other}
+	r = NewResolver(cfg)
+	_, err = r.acceptRecord(record, domain, time.Now())
+	assert.ErrorIs(t, err, ErrUnsignedRecord)
+	_, err = r.acceptRecord(txt, domain, time.Now())
+	assert.ErrorIs(t, err, ErrUntrustedSigner)
+
+	cfg.TrustedSigners = append(cfg.TrustedSigners, signer)
+	r = NewResolver(cfg)
+	got, err = r.acceptRecord(txt, domain, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, record, got)
+}
+
+// TestConfig_RequireSigned 测试签名配置验证
+func TestConfig_RequireSigned(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.RequireSigned = true
+	assert.Error(t, cfg.Validate())
+
+	cfg.TrustedSigners = []types.PeerID{"signer"}
+	assert.NoError(t, cfg.Validate())
+}
+
+// startTestResponder 启动本地应答器
+func startTestResponder(t *testing.T, key pkgif.PrivateKey, nodes ...types.PeerInfo) *Responder {
+	t.Helper()
+	cfg := DefaultResponderConfig()
+	cfg.Zone = testZone
+	cfg.ListenAddr = "127.0.0.1:0"
+
+	r, err := NewResponder(cfg, key, staticSource(nodes))
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background()))
+	t.Cleanup(func() { _ = r.Stop(context.Background()) })
+
+	require.Eventually(t, func() bool { return len(r.Records()) > 0 }, 2*time.Second, 10*time.Millisecond)
+	return r
+}
+
+// TestResponder_EndToEnd 测试应答器发布的记录可被 Resolver 验证解析
+func TestResponder_EndToEnd(t *testing.T) {
+	key, signer := newTestKey(t)
+	_, nodeA := newTestKey(t)
+	_, nodeB := newTestKey(t)
+
+	r := startTestResponder(t, key,
+		testNode(t, nodeA, "/ip4/1.2.3.4/tcp/4001"),
+		testNode(t, nodeB, "/ip4/5.6.7.8/udp/4001/quic-v1/p2p/"+string(nodeB)),
+	)
+	// 每个地址一条普通记录和一条签名记录
+	assert.Len(t, r.Records(), 4)
+
+	resolve := func(cfg ResolverConfig) ([]types.PeerInfo, error) {
+		cfg.CustomResolver = r.Addrs()[0].String()
+		cfg.Timeout = 2 * time.Second
+		return NewResolver(cfg).Resolve(context.Background(), testZone)
+	}
+
+	// 不要求签名
+	peers, err := resolve(DefaultResolverConfig())
+	require.NoError(t, err)
+	assert.Len(t, peers, 2)
+
+	// 要求签名且信任应答器
+	cfg := DefaultResolverConfig()
+	cfg.RequireSigned = true
+	cfg.TrustedSigners = []types.PeerID{signer}
+	peers, err = resolve(cfg)
+	require.NoError(t, err)
+	ids := []types.PeerID{peers[0].ID, peers[1].ID}
+	assert.ElementsMatch(t, []types.PeerID{nodeA, nodeB}, ids)
+
+	// 不信任的签名者：所有记录被拒绝
+	cfg.TrustedSigners = []types.PeerID{nodeA}
+	peers, err = resolve(cfg)
+	require.NoError(t, err)
+	assert.Empty(t, peers)
+}
+
+// TestResponder_Authority 测试区域内外查询的应答码
+func TestResponder_Authority(t *testing.T) {
+	key, _ := newTestKey(t)
+	_, node := newTestKey(t)
+	r := startTestResponder(t, key, testNode(t, node, "/ip4/1.2.3.4/tcp/4001"))
+
+	query := func(name string, qtype uint16, network string) *miekg.Msg {
+		msg := new(miekg.Msg)
+		msg.SetQuestion(miekg.Fqdn(name), qtype)
+		client := &miekg.Client{Net: network, Timeout: 2 * time.Second}
+		addr := r.Addrs()[0].String()
+		if network == "tcp" {
+			addr = r.Addrs()[1].String()
+		}
+		resp, _, err := client.Exchange(msg, addr)
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := query(DNSAddrDomainPrefix+testZone, miekg.TypeTXT, "tcp")
+	assert.True(t, resp.Authoritative)
+	assert.Equal(t, miekg.RcodeSuccess, resp.Rcode)
+	assert.Len(t, resp.Answer, 2)
+
+	resp = query(testZone, miekg.TypeSOA, "udp")
+	assert.Equal(t, miekg.RcodeSuccess, resp.Rcode)
+	require.Len(t, resp.Answer, 1)
+	assert.Equal(t, miekg.TypeSOA, resp.Answer[0].Header().Rrtype)
+
+	resp = query(DNSAddrDomainPrefix+testZone, miekg.TypeA, "udp")
+	assert.Equal(t, miekg.RcodeSuccess, resp.Rcode)
+	assert.Empty(t, resp.Answer)
+	assert.Len(t, resp.Ns, 1)
+
+	resp = query("missing."+testZone, miekg.TypeTXT, "udp")
+	assert.Equal(t, miekg.RcodeNameError, resp.Rcode)
+
+	resp = query("example.com", miekg.TypeTXT, "udp")
+	assert.Equal(t, miekg.RcodeRefused, resp.Rcode)
+}
+
+// TestResponderConfig_Validate 测试应答器配置验证
+func TestResponderConfig_Validate(t *testing.T) {
+	cfg := DefaultResponderConfig()
+	assert.Error(t, cfg.Validate(), "zone is required")
+
+	cfg.Zone = testZone
+	assert.NoError(t, cfg.Validate())
+
+	cfg.SignatureValidity = cfg.RefreshInterval
+	assert.Error(t, cfg.Validate())
+}
@@ -0,0 +1,163 @@
+package dns
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/crypto"
+	"github.com/dep2p/go-dep2p/pkg/types"
+)
+
+// ============================================================================
+//                              签名 dnsaddr 记录
+// ============================================================================
+//
+// 签名记录格式（单条 TXT 记录）：
+//
+//	dnssig=<版本> <过期时间(Unix 秒)> <base64url(公钥)> <base64url(签名)> <dnsaddr 记录>
+//
+// 例如：
+//
+//	dnssig=1 1767225600 AgAAACD... MEUCIQ... dnsaddr=/ip4/1.2.3.4/tcp/4001/p2p/Qm...
+//
+// 签名覆盖：域分隔符、区域名、过期时间、dnsaddr 记录。区域名绑定防止记录被
+// 搬到其他域名下重放，过期时间限制被截获记录的有效期。公钥采用
+// crypto.MarshalPublicKey 格式，客户端由公钥派生签名者 PeerID 并与信任列表比对。
+//
+// 签名记录使用独立前缀，不认识它的旧客户端会忽略它，继续使用同一区域中的
+// 普通 dnsaddr 记录。
+
+const (
+	// DNSSigPrefix 签名记录前缀
+	DNSSigPrefix = "dnssig="
+
+	// dnssigVersion 签名记录格式版本
+	dnssigVersion = "1"
+
+	// dnssigDomain 签名域分隔符
+	dnssigDomain = "dep2p-dnsaddr-v1"
+)
+
+// SignedRecord 已验证的签名记录
+type SignedRecord struct {
+	// Record 内层 dnsaddr 记录
+	Record string
+
+	// Signer 签名者 PeerID
+	Signer types.PeerID
+
+	// Expires 过期时间
+	Expires time.Time
+}
+
+// SignDNSAddr 为 dnsaddr 记录生成签名记录
+//
+// zone 为记录所在域名（可带或不带 "_dnsaddr." 前缀）。
+func SignDNSAddr(key pkgif.PrivateKey, zone, record string, expires time.Time) (string, error) {
+	if key == nil {
+		return "", ErrNilSigningKey
+	}
+	if !strings.HasPrefix(record, DNSAddrPrefix) {
+		return "", ErrInvalidDNSAddr
+	}
+
+	pubBytes, err := marshalSigningKey(key.PublicKey())
+	if err != nil {
+		return "", err
+	}
+
+	exp := expires.Unix()
+	sig, err := key.Sign(dnssigPayload(zone, exp, record))
+	if err != nil {
+		return "", fmt.Errorf("sign dnsaddr record: %w", err)
+	}
+
+	return fmt.Sprintf("%s%s %d %s %s %s",
+		DNSSigPrefix,
+		dnssigVersion,
+		exp,
+		base64.RawURLEncoding.EncodeToString(pubBytes),
+		base64.RawURLEncoding.EncodeToString(sig),
+		record,
+	), nil
+}
+
+// VerifyDNSSig 解析并验证签名记录
+//
+// zone 为查询的域名，必须与签名时的区域一致；now 用于检查过期。
+func VerifyDNSSig(txt, zone string, now time.Time) (*SignedRecord, error) {
+	if !strings.HasPrefix(txt, DNSSigPrefix) {
+		return nil, ErrUnsignedRecord
+	}
+
+	fields := strings.SplitN(strings.TrimPrefix(txt, DNSSigPrefix), " ", 5)
+	if len(fields) != 5 || fields[0] != dnssigVersion {
+		return nil, fmt.Errorf("%w: malformed record", ErrInvalidSignature)
+	}
+
+	exp, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad expiry", ErrInvalidSignature)
+	}
+	pubBytes, err := base64.RawURLEncoding.DecodeString(fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad public key", ErrInvalidSignature)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(fields[3])
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad signature", ErrInvalidSignature)
+	}
+	record := fields[4]
+
+	pub, err := crypto.UnmarshalPublicKeyBytes(pubBytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	ok, err := pub.Verify(dnssigPayload(zone, exp, record), sig)
+	if err != nil || !ok {
+		return nil, ErrInvalidSignature
+	}
+
+	expires := time.Unix(exp, 0)
+	if now.After(expires) {
+		return nil, ErrSignatureExpired
+	}
+
+	signer, err := crypto.PeerIDFromPublicKey(pub)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+
+	return &SignedRecord{Record: record, Signer: signer, Expires: expires}, nil
+}
+
+// dnssigPayload 构建签名数据
+func dnssigPayload(zone string, expires int64, record string) []byte {
+	return []byte(dnssigDomain + "\n" + canonicalZone(zone) + "\n" + strconv.FormatInt(expires, 10) + "\n" + record)
+}
+
+// canonicalZone 规范化区域名：小写、去掉 "_dnsaddr." 前缀和尾随点
+func canonicalZone(zone string) string {
+	zone = strings.ToLower(strings.TrimSuffix(zone, "."))
+	return strings.TrimPrefix(zone, DNSAddrDomainPrefix)
+}
+
+// marshalSigningKey 按 crypto.MarshalPublicKey 格式序列化公钥
+func marshalSigningKey(pub pkgif.PublicKey) ([]byte, error) {
+	if pub == nil {
+		return nil, ErrNilSigningKey
+	}
+	raw, err := pub.Raw()
+	if err != nil {
+		return nil, err
+	}
+	key, err := crypto.UnmarshalPublicKey(crypto.KeyType(pub.Type()), raw)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.MarshalPublicKey(key)
+}
@@ -17,6 +17,9 @@ const (
 
 	// DNSAddrPrefix TXT 记录前缀
 	DNSAddrPrefix = "dnsaddr="
+
+	// DefaultRealmRotation Realm 标签盐与服务实例名的轮换间隔
+	DefaultRealmRotation = 30 * time.Minute
 )
 
 // Config MDNS 配置
@@ -29,14 +32,29 @@ type Config struct {
 
 	// Enabled 是否启用，默认 true
 	Enabled bool
+
+	// Protocols 在 TXT 记录中声明的协议，供对端拨号前筛选
+	Protocols []string
+
+	// Roles 节点角色（RoleRelay、RoleBootstrap）
+	Roles []string
+
+	// AppVersion 应用版本
+	AppVersion string
+
+	// RealmRotation Realm 服务实例轮换间隔，默认 30min，0 表示不轮换
+	//
+	// 每次轮换为 Realm 服务实例生成新的标签盐、实例名和主机名。
+	RealmRotation time.Duration
 }
 
 // DefaultConfig 返回默认配置
 func DefaultConfig() *Config {
 	return &Config{
-		ServiceTag: DefaultServiceTag,
-		Interval:   DefaultInterval,
-		Enabled:    true,
+		ServiceTag:    DefaultServiceTag,
+		Interval:      DefaultInterval,
+		Enabled:       true,
+		RealmRotation: DefaultRealmRotation,
 	}
 }
 
@@ -54,6 +72,10 @@ func (c *Config) Validate() error {
 		return errors.New("interval must be positive")
 	}
 
+	if c.RealmRotation < 0 {
+		return errors.New("realm rotation must not be negative")
+	}
+
 	return nil
 }
 
@@ -81,6 +103,27 @@ func WithInterval(interval time.Duration) ConfigOption {
 	}
 }
 
+// WithProtocols 设置广播的协议
+func WithProtocols(protocols ...string) ConfigOption {
+	return func(c *Config) {
+		c.Protocols = protocols
+	}
+}
+
+// WithRoles 设置广播的节点角色
+func WithRoles(roles ...string) ConfigOption {
+	return func(c *Config) {
+		c.Roles = roles
+	}
+}
+
+// WithAppVersion 设置广播的应用版本
+func WithAppVersion(version string) ConfigOption {
+	return func(c *Config) {
+		c.AppVersion = version
+	}
+}
+
 // WithEnabled 设置是否启用
 func WithEnabled(enabled bool) ConfigOption {
 	return func(c *Config) {
@@ -20,6 +20,11 @@
 //   - 排除不适合的协议（circuit relay, websocket, webrtc）
 //   - 符合 RFC 6762 数据包大小限制（1500 字节）
 //
+// 4. DNS-SD 属性
+//   - TXT 记录携带带盐的 Realm 标签、协议、角色、应用版本（txtvers=1）
+//   - 每个已加入的 Realm 一个独立命名的服务实例（AddRealm / RemoveRealm），定期轮换
+//   - FindPeers 支持 WithRealm、WithProtocols、WithRole、WithAppVersion 筛选
+//
 // # 使用示例
 //
 //	// 创建 MDNS 服务
@@ -67,6 +72,7 @@
 //   - ServiceTag: mDNS 服务标签，默认 "_dep2p._udp"
 //   - Interval: 广播间隔，默认 10s
 //   - Enabled: 是否启用，默认 true
+//   - Protocols / Roles / AppVersion: TXT 记录中广播的节点属性
 //
 // # v1.0 范围
 //
@@ -89,7 +95,7 @@
 //
 // mdns 是并发安全的：
 //   - atomic.Bool 保护 started/closed 状态
-//   - sync.RWMutex 保护 server 实例
+//   - sync.RWMutex 保护服务实例与 Realm 列表
 //   - sync.WaitGroup 同步 goroutine
 //   - context.Context 控制生命周期
 //
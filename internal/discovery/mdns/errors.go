@@ -30,6 +30,15 @@ var (
 
 	// ErrResolverStart 解析器启动失败
 	ErrResolverStart = errors.New("mdns: failed to start resolver")
+
+	// ErrEmptyRealm RealmID 为空
+	ErrEmptyRealm = errors.New("mdns: realm id is empty")
+
+	// ErrEmptyRealmKey Realm 发现密钥为空
+	ErrEmptyRealmKey = errors.New("mdns: realm discovery key is empty")
+
+	// ErrRealmNotJoined 未加入的 Realm（没有发现密钥，无法广播）
+	ErrRealmNotJoined = errors.New("mdns: realm not joined")
 )
 
 // MDNSError 自定义错误类型
@@ -2,6 +2,7 @@ package mdns
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
 	"math/rand"
 	"net"
@@ -30,8 +31,13 @@ type MDNS struct {
 	host   pkgif.Host
 	config *Config
 
-	server   *zeroconf.Server // mDNS 服务器
-	peerName string           // 随机服务实例名
+	servers  map[types.RealmID]*zeroconf.Server // 服务实例，键为 Realm（"" 为基础实例）
+	peerName string                             // 基础服务实例名（随机）
+	realms   map[types.RealmID]*realmInstance   // 已加入的 Realm -> 服务实例
+	ips      []string                           // 当前广播的 IP（运行时有效）
+	addrs    []string                           // 当前广播的 p2p 地址（运行时有效）
+
+	rotateOnce sync.Once // 首次加入 Realm 时启动轮换循环
 
 	started atomic.Bool
 	closed  atomic.Bool
@@ -40,6 +46,25 @@ type MDNS struct {
 	wg      sync.WaitGroup
 }
 
+// realmInstance Realm 服务实例
+//
+// 每个 Realm 使用独立的随机实例名和主机名，与基础实例及其他 Realm
+// 实例互不关联；标签盐、实例名和主机名按 Config.RealmRotation 轮换。
+type realmInstance struct {
+	secret []byte // 由 PSK 派生的发现密钥
+	name   string // 服务实例名，同时用作主机名
+	salt   []byte // 当前标签盐
+}
+
+// newRealmInstance 创建带新随机名与新盐的服务实例
+func newRealmInstance(secret []byte) *realmInstance {
+	return &realmInstance{
+		secret: secret,
+		name:   randomString(32 + rand.Intn(32)), //nolint:gosec // G404: 实例名不需要加密级随机
+		salt:   newRealmSalt(),
+	}
+}
+
 // New 创建 MDNS 服务
 func New(host pkgif.Host, config *Config) (*MDNS, error) {
 	if host == nil {
@@ -62,18 +87,23 @@ func New(host pkgif.Host, config *Config) (*MDNS, error) {
 		host:     host,
 		config:   config,
 		peerName: randomString(32 + rand.Intn(32)), //nolint:gosec // G404: peer name 不需要加密级随机
+		servers:  make(map[types.RealmID]*zeroconf.Server),
+		realms:   make(map[types.RealmID]*realmInstance),
 	}
 
 	return m, nil
 }
 
 // FindPeers 发现节点
-func (m *MDNS) FindPeers(ctx context.Context, ns string, _ ...pkgif.DiscoveryOption) (<-chan types.PeerInfo, error) {
+//
+// 支持按 TXT 属性筛选：WithRealm、WithProtocols、WithRole、WithAppVersion。
+func (m *MDNS) FindPeers(ctx context.Context, ns string, opts ...pkgif.DiscoveryOption) (<-chan types.PeerInfo, error) {
 	if m.closed.Load() {
 		return nil, ErrAlreadyClosed
 	}
 
 	ns = pkgif.NormalizeNamespace(ns)
+	filter := newPeerFilter(m.realmSecret, opts...)
 
 	peerCh := make(chan types.PeerInfo, 100)
 
@@ -81,7 +111,7 @@ func (m *MDNS) FindPeers(ctx context.Context, ns string, _ ...pkgif.DiscoveryOpt
 	m.wg.Add(1)
 	go func() {
 		defer m.wg.Done()
-		done, notifee, err := m.startResolver(ctx, peerCh)
+		done, notifee, err := m.startResolver(ctx, peerCh, filter)
 		if err != nil {
 			// Resolver 启动失败，channel 将被关闭
 			close(peerCh)
@@ -108,6 +138,8 @@ func (m *MDNS) FindPeers(ctx context.Context, ns string, _ ...pkgif.DiscoveryOpt
 }
 
 // Advertise 广播自身
+//
+// 指定 WithRealm 时该 Realm 必须已加入（AddRealm），其服务实例随服务器一并广播。
 func (m *MDNS) Advertise(_ context.Context, ns string, opts ...pkgif.DiscoveryOption) (time.Duration, error) {
 	if m.closed.Load() {
 		return 0, ErrAlreadyClosed
@@ -115,6 +147,14 @@ func (m *MDNS) Advertise(_ context.Context, ns string, opts ...pkgif.DiscoveryOp
 
 	ns = pkgif.NormalizeNamespace(ns)
 
+	options := &pkgif.DiscoveryOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.Realm != "" && m.realmSecret(options.Realm) == nil {
+		return 0, ErrRealmNotJoined
+	}
+
 	if err := m.startServer(); err != nil {
 		return 0, fmt.Errorf("%w: %v", ErrServerStart, err)
 	}
@@ -177,10 +217,7 @@ func (m *MDNS) Stop(_ context.Context) error {
 
 	// 关闭 Server
 	m.mu.Lock()
-	if m.server != nil {
-		m.server.Shutdown()
-		m.server = nil
-	}
+	m.shutdownServersLocked()
 	m.mu.Unlock()
 
 	// 等待所有 goroutine 结束（带超时）
@@ -207,10 +244,7 @@ func (m *MDNS) stopServer() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if m.server != nil {
-		m.server.Shutdown()
-		m.server = nil
-	}
+	m.shutdownServersLocked()
 
 	// 如果服务没有完全停止，进入等待状态
 	if m.state.Load() != StateStopped {
@@ -264,7 +298,7 @@ func (m *MDNS) startServer() error {
 	defer m.mu.Unlock()
 
 	// 如果已经启动，直接返回
-	if m.server != nil {
+	if len(m.servers) > 0 {
 		logger.Debug("服务器已启动，跳过")
 		return nil
 	}
@@ -336,10 +370,9 @@ func (m *MDNS) startServer() error {
 		return nil
 	}
 
-	// 构建 TXT 记录
-	var txts []string
+	addrs := make([]string, 0, len(suitableAddrs))
 	for _, addr := range suitableAddrs {
-		txts = append(txts, DNSAddrPrefix+addr.String())
+		addrs = append(addrs, addr.String())
 	}
 
 	// 提取 IP 地址
@@ -350,26 +383,187 @@ func (m *MDNS) startServer() error {
 		return nil
 	}
 
-	// 注册服务
+	m.ips = ips
+	m.addrs = addrs
+
+	// 注册基础服务实例和每个 Realm 的服务实例
+	if err := m.registerLocked(""); err != nil {
+		return err
+	}
+	for realmID := range m.realms {
+		if err := m.registerLocked(realmID); err != nil {
+			m.shutdownServersLocked()
+			return err
+		}
+	}
+
+	m.state.Store(StateRunning)
+	return nil
+}
+
+// registerLocked 注册一个服务实例（需持有 m.mu）
+//
+// realmID 为空时注册基础实例，否则注册该 Realm 的实例：使用该 Realm 独立的
+// 实例名与主机名，TXT 记录携带 Realm 标签及其盐。
+func (m *MDNS) registerLocked(realmID types.RealmID) error {
+	instance, hostName := m.peerName, m.peerName
+	attrs := m.attributes()
+	if realmID != "" {
+		r := m.realms[realmID]
+		instance, hostName = r.name, r.name
+		attrs.RealmTag = RealmTag(r.secret, r.salt)
+		attrs.RealmSalt = hex.EncodeToString(r.salt)
+	}
+
 	server, err := zeroconf.RegisterProxy(
-		m.peerName,
+		instance,
 		m.config.ServiceTag,
 		MDNSDomain,
 		4001, // 占位端口，实际不用
-		m.peerName,
-		ips,
-		txts,
+		hostName,
+		m.ips,
+		encodeTXT(m.addrs, attrs),
 		nil, // nil = 所有接口
 	)
 	if err != nil {
 		return err
 	}
 
-	m.server = server
-	m.state.Store(StateRunning)
+	m.servers[realmID] = server
+	return nil
+}
+
+// shutdownServersLocked 关闭所有服务实例（需持有 m.mu）
+func (m *MDNS) shutdownServersLocked() {
+	for realmID, server := range m.servers {
+		server.Shutdown()
+		delete(m.servers, realmID)
+	}
+}
+
+// attributes 返回配置的节点属性
+func (m *MDNS) attributes() Attributes {
+	return Attributes{
+		Protocols:  m.config.Protocols,
+		Roles:      m.config.Roles,
+		AppVersion: m.config.AppVersion,
+	}
+}
+
+// AddRealm 为 Realm 增加一个服务实例
+//
+// secret 为由 PSK 派生的发现密钥（EvtRealmJoined.DiscoveryKey），用于计算
+// Realm 标签与筛选同一 Realm 的节点。服务运行中时立即广播，否则在服务器
+// 启动时一并注册。重复添加是幂等的。
+func (m *MDNS) AddRealm(realmID types.RealmID, secret []byte) error {
+	if realmID == "" {
+		return ErrEmptyRealm
+	}
+	if len(secret) == 0 {
+		return ErrEmptyRealmKey
+	}
+	if m.closed.Load() {
+		return ErrAlreadyClosed
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.realms[realmID]; ok {
+		return nil
+	}
+	m.realms[realmID] = newRealmInstance(append([]byte(nil), secret...))
+
+	if m.config.RealmRotation > 0 {
+		m.rotateOnce.Do(func() {
+			m.wg.Add(1)
+			go m.rotateLoop()
+		})
+	}
+
+	if len(m.servers) == 0 {
+		return nil
+	}
+	if err := m.registerLocked(realmID); err != nil {
+		delete(m.realms, realmID)
+		return fmt.Errorf("%w: %v", ErrServerStart, err)
+	}
+	logger.Debug("已广播 Realm 服务实例")
+	return nil
+}
+
+// realmSecret 返回已加入 Realm 的发现密钥，未加入时返回 nil
+func (m *MDNS) realmSecret(realmID types.RealmID) []byte {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if r, ok := m.realms[realmID]; ok {
+		return r.secret
+	}
 	return nil
 }
 
+// rotateLoop 定期轮换 Realm 服务实例
+func (m *MDNS) rotateLoop() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.config.RealmRotation)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.rotateRealms()
+		case <-m.ctx.Done():
+			return
+		}
+	}
+}
+
+// rotateRealms 为每个 Realm 生成新的标签盐、实例名与主机名并重新广播
+func (m *MDNS) rotateRealms() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for realmID, r := range m.realms {
+		m.realms[realmID] = newRealmInstance(r.secret)
+
+		server, ok := m.servers[realmID]
+		if !ok {
+			continue
+		}
+		server.Shutdown()
+		delete(m.servers, realmID)
+		if err := m.registerLocked(realmID); err != nil {
+			logger.Warn("轮换 Realm 服务实例失败", "error", err)
+		}
+	}
+}
+
+// RemoveRealm 停止广播 Realm 的服务实例
+func (m *MDNS) RemoveRealm(realmID types.RealmID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.realms, realmID)
+	if server, ok := m.servers[realmID]; ok {
+		server.Shutdown()
+		delete(m.servers, realmID)
+	}
+}
+
+// Realms 返回正在广播的 Realm 列表
+func (m *MDNS) Realms() []types.RealmID {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	realms := make([]types.RealmID, 0, len(m.realms))
+	for realmID := range m.realms {
+		realms = append(realms, realmID)
+	}
+	return realms
+}
+
 // expandWildcardAddrs 将包含 0.0.0.0 的地址展开为实际的网络接口地址
 //
 // 例如 /ip4/0.0.0.0/udp/1234/quic-v1 会被展开为：
@@ -576,12 +770,12 @@ func isValidBroadcastIP(ip net.IP) bool {
 
 // startResolver 启动 mDNS 解析器（发现）
 // 返回 done channel 和 notifee（用于安全关闭）
-func (m *MDNS) startResolver(ctx context.Context, peerCh chan<- types.PeerInfo) (<-chan struct{}, *peerNotifee, error) {
+func (m *MDNS) startResolver(ctx context.Context, peerCh chan<- types.PeerInfo, filter *peerFilter) (<-chan struct{}, *peerNotifee, error) {
 	// 创建 ServiceEntry channel
 	entryChan := make(chan *zeroconf.ServiceEntry, 1000)
 
 	// 创建 notifee
-	notifee := newPeerNotifee(ctx, m.host.ID(), peerCh)
+	notifee := newPeerNotifee(ctx, m.host.ID(), peerCh, filter)
 
 	done := make(chan struct{})
 	var wg sync.WaitGroup
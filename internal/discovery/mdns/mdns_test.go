@@ -331,7 +331,7 @@ func (m *mockHost) NewStreamWithPriority(ctx context.Context, peerID string, pro
 	return m.NewStream(ctx, peerID, protocolID)
 }
 
-func (m *mockHost) SetStreamHandler(protocolID string, handler pkgif.StreamHandler) {
+func (m *mockHost) SetStreamHandler(protocolID string, handler pkgif.StreamHandler, _ ...pkgif.StreamHandlerOption) {
 }
 
 func (m *mockHost) RemoveStreamHandler(protocolID string) {
@@ -7,6 +7,7 @@ import (
 
 	"github.com/dep2p/go-dep2p/config"
 	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/types"
 	"go.uber.org/fx"
 )
 
@@ -31,10 +32,20 @@ func ConfigFromUnified(cfg *config.Config) *Config {
 	if cfg == nil || !cfg.Discovery.EnableMDNS {
 		return &Config{Enabled: false}
 	}
+	var roles []string
+	if cfg.Relay.EnableServer {
+		roles = append(roles, RoleRelay)
+	}
+	if cfg.Discovery.Bootstrap.EnableService {
+		roles = append(roles, RoleBootstrap)
+	}
 	return &Config{
 		ServiceTag: cfg.Discovery.MDNS.ServiceTag,
 		Interval:   cfg.Discovery.MDNS.Interval.Duration(),
 		Enabled:    cfg.Discovery.EnableMDNS,
+		Protocols:  cfg.Discovery.MDNS.Protocols,
+		Roles:      roles,
+		AppVersion: cfg.Discovery.MDNS.AppVersion,
 	}
 }
 
@@ -216,6 +227,7 @@ func registerLifecycle(input lifecycleInput) {
 
 	// 订阅地址变化事件
 	var subscription pkgif.Subscription
+	var realmSubscriptions []pkgif.Subscription
 	if input.EventBus != nil {
 		sub, err := input.EventBus.Subscribe(new(EvtLocalAddrsUpdated))
 		if err == nil {
@@ -223,6 +235,16 @@ func registerLifecycle(input lifecycleInput) {
 			// 启动地址变化监听协程
 			go handleAddressChanges(mdns, subscription)
 		}
+
+		// 订阅 Realm 加入/离开事件，为每个已加入的 Realm 广播服务实例
+		for _, evtType := range []interface{}{new(types.EvtRealmJoined), new(types.EvtRealmLeft)} {
+			realmSub, err := input.EventBus.Subscribe(evtType)
+			if err != nil {
+				continue
+			}
+			realmSubscriptions = append(realmSubscriptions, realmSub)
+			go handleRealmChanges(mdns, realmSub)
+		}
 	}
 
 	input.LC.Append(fx.Hook{
@@ -247,6 +269,9 @@ func registerLifecycle(input lifecycleInput) {
 			if subscription != nil {
 				subscription.Close()
 			}
+			for _, realmSub := range realmSubscriptions {
+				realmSub.Close()
+			}
 			// 停止 MDNS 服务
 			return mdns.Stop(ctx)
 		},
@@ -284,3 +309,17 @@ func handleAddressChanges(mdns *MDNS, sub pkgif.Subscription) {
 		}
 	}
 }
+
+// handleRealmChanges 处理 Realm 加入/离开事件
+func handleRealmChanges(mdns *MDNS, sub pkgif.Subscription) {
+	for evt := range sub.Out() {
+		switch e := evt.(type) {
+		case *types.EvtRealmJoined:
+			if err := mdns.AddRealm(e.RealmID, e.DiscoveryKey); err != nil {
+				logger.Warn("广播 Realm 服务实例失败", "error", err)
+			}
+		case *types.EvtRealmLeft:
+			mdns.RemoveRealm(e.RealmID)
+		}
+	}
+}
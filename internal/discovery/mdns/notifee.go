@@ -24,17 +24,19 @@ type peerNotifee struct {
 	ctx    context.Context
 	selfID string
 	peerCh chan<- types.PeerInfo
+	filter *peerFilter // 按 TXT 属性筛选，nil 表示不筛选
 	mu     sync.Mutex
 	seen   map[string]bool // 防止重复通知
 	closed bool            // 标记 channel 是否已关闭
 }
 
 // newPeerNotifee 创建 peerNotifee
-func newPeerNotifee(ctx context.Context, selfID string, peerCh chan<- types.PeerInfo) *peerNotifee {
+func newPeerNotifee(ctx context.Context, selfID string, peerCh chan<- types.PeerInfo, filter *peerFilter) *peerNotifee {
 	return &peerNotifee{
 		ctx:    ctx,
 		selfID: selfID,
 		peerCh: peerCh,
+		filter: filter,
 		seen:   make(map[string]bool),
 		closed: false,
 	}
@@ -87,14 +89,17 @@ func (n *peerNotifee) handleEntry(entry *zeroconf.ServiceEntry) error {
 		"domain", entry.Domain,
 		"txt", entry.Text)
 
-	// 解析 TXT 记录，提取 multiaddr
-	var addrs []types.Multiaddr
-	for _, txt := range entry.Text {
-		if !strings.HasPrefix(txt, DNSAddrPrefix) {
-			continue
-		}
+	// 解析 TXT 记录，提取 multiaddr 和节点属性
+	addrStrs, attrs := parseTXT(entry.Text)
 
-		addrStr := txt[len(DNSAddrPrefix):]
+	// 按属性筛选（在去重之前，同一节点的其他服务实例可能满足条件）
+	if !n.filter.match(attrs) {
+		logger.Debug("服务实例不满足筛选条件", "instance", entry.Instance)
+		return nil
+	}
+
+	var addrs []types.Multiaddr
+	for _, addrStr := range addrStrs {
 		addr, err := types.NewMultiaddr(addrStr)
 		if err != nil {
 			logger.Debug("无效地址", "addr", addrStr, "error", err)
@@ -0,0 +1,286 @@
+package mdns
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/zeroconf"
+	"github.com/dep2p/go-dep2p/pkg/types"
+)
+
+// DNS-SD TXT 记录键
+//
+// 除 dnsaddr 与 proto 外，每个键只出现一次；dnsaddr 每条地址一个，
+// proto 在超出单条 TXT 字符串长度（255 字节）时拆分为多条。
+const (
+	// TxtKeyVersion TXT 格式版本，放在首位（RFC 6763 6.7）
+	TxtKeyVersion = "txtvers"
+
+	// TxtKeyRealm Realm 标签，仅出现在 Realm 服务实例中
+	TxtKeyRealm = "realm"
+
+	// TxtKeyRealmSalt 计算 Realm 标签的盐（十六进制），随标签一起轮换
+	TxtKeyRealmSalt = "rsalt"
+
+	// TxtKeyProtocols 支持的协议，逗号分隔
+	TxtKeyProtocols = "proto"
+
+	// TxtKeyRoles 节点角色，逗号分隔
+	TxtKeyRoles = "role"
+
+	// TxtKeyAppVersion 应用版本
+	TxtKeyAppVersion = "ver"
+
+	// TxtVersion 当前 TXT 格式版本
+	TxtVersion = "1"
+)
+
+// 节点角色
+const (
+	// RoleRelay 中继服务节点
+	RoleRelay = "relay"
+
+	// RoleBootstrap 引导服务节点
+	RoleBootstrap = "bootstrap"
+)
+
+const (
+	// realmTagDomain Realm 标签域分隔前缀
+	realmTagDomain = "dep2p-mdns-realm-v2:"
+
+	// realmTagLen Realm 标签截断长度（字节）
+	realmTagLen = 8
+
+	// realmSaltLen Realm 标签盐长度（字节）
+	realmSaltLen = 8
+
+	// attrListSep 列表属性分隔符
+	attrListSep = ","
+)
+
+// Attributes 节点通过 TXT 记录广播的属性
+type Attributes struct {
+	// RealmTag Realm 标签（RealmTag 计算），基础服务实例为空
+	RealmTag string
+
+	// RealmSalt 计算 RealmTag 使用的盐（十六进制）
+	RealmSalt string
+
+	// Protocols 支持的协议
+	Protocols []string
+
+	// Roles 节点角色（RoleRelay、RoleBootstrap）
+	Roles []string
+
+	// AppVersion 应用版本
+	AppVersion string
+}
+
+// RealmTag 计算 Realm 在 mDNS 中广播的标签
+//
+// 标签是以 Realm 发现密钥（由 PSK 派生）为键、对盐做的 HMAC。不持有 PSK
+// 的观察者既无法计算标签，也无法把不同盐下的标签关联到同一 Realm；
+// 盐定期轮换，同一节点在不同轮换周期的 Realm 实例因此不可关联。
+func RealmTag(secret, salt []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(realmTagDomain))
+	mac.Write(salt)
+	return hex.EncodeToString(mac.Sum(nil)[:realmTagLen])
+}
+
+// matchRealmTag 检查 TXT 中的标签与盐是否属于持有 secret 的 Realm
+func matchRealmTag(secret []byte, attrs Attributes) bool {
+	if len(secret) == 0 || attrs.RealmTag == "" {
+		return false
+	}
+	salt, err := hex.DecodeString(attrs.RealmSalt)
+	if err != nil || len(salt) != realmSaltLen {
+		return false
+	}
+	return hmac.Equal([]byte(RealmTag(secret, salt)), []byte(attrs.RealmTag))
+}
+
+// newRealmSalt 生成 Realm 标签盐
+func newRealmSalt() []byte {
+	salt := make([]byte, realmSaltLen)
+	_, _ = rand.Read(salt)
+	return salt
+}
+
+// encodeTXT 构建 TXT 记录
+//
+// 包含格式版本、节点属性和 dnsaddr 地址。含分隔符或过长的协议会被跳过。
+func encodeTXT(addrs []string, attrs Attributes) []string {
+	txts := []string{TxtKeyVersion + "=" + TxtVersion}
+
+	if attrs.RealmTag != "" {
+		txts = append(txts, TxtKeyRealm+"="+attrs.RealmTag, TxtKeyRealmSalt+"="+attrs.RealmSalt)
+	}
+	txts = append(txts, encodeList(TxtKeyRoles, attrs.Roles)...)
+	if attrs.AppVersion != "" {
+		if attr, err := zeroconf.NewTXTAttr(TxtKeyAppVersion, attrs.AppVersion); err == nil {
+			txts = append(txts, attr.String())
+		}
+	}
+	txts = append(txts, encodeList(TxtKeyProtocols, attrs.Protocols)...)
+
+	for _, addr := range addrs {
+		txts = append(txts, DNSAddrPrefix+addr)
+	}
+	return txts
+}
+
+// encodeList 将列表编码为一条或多条 "key=a,b,c" 字符串
+func encodeList(key string, values []string) []string {
+	var (
+		txts    []string
+		current strings.Builder
+	)
+	limit := zeroconf.MaxTXTStringLen - len(key) - 1
+
+	for _, v := range values {
+		if v == "" || strings.Contains(v, attrListSep) || len(v) > limit {
+			logger.Debug("跳过无法编码的属性值", "key", key, "value", v)
+			continue
+		}
+		if current.Len() > 0 && current.Len()+len(attrListSep)+len(v) > limit {
+			txts = append(txts, key+"="+current.String())
+			current.Reset()
+		}
+		if current.Len() > 0 {
+			current.WriteString(attrListSep)
+		}
+		current.WriteString(v)
+	}
+	if current.Len() > 0 {
+		txts = append(txts, key+"="+current.String())
+	}
+	return txts
+}
+
+// parseTXT 解析 TXT 记录，返回 dnsaddr 地址和节点属性
+//
+// 未携带属性的旧版本节点返回空属性。
+func parseTXT(text []string) ([]string, Attributes) {
+	var (
+		addrs []string
+		attrs Attributes
+	)
+	seen := make(map[string]bool)
+	dnsaddrKey := strings.TrimSuffix(DNSAddrPrefix, "=")
+
+	for _, attr := range zeroconf.ParseTXT(text) {
+		if !attr.HasValue {
+			continue
+		}
+		switch attr.Key {
+		case dnsaddrKey:
+			addrs = append(addrs, attr.Value)
+			continue
+		case TxtKeyProtocols:
+			attrs.Protocols = append(attrs.Protocols, splitList(attr.Value)...)
+			continue
+		}
+
+		// 其余键只取首次出现的值（RFC 6763 6.4）
+		if seen[attr.Key] {
+			continue
+		}
+		seen[attr.Key] = true
+
+		switch attr.Key {
+		case TxtKeyRealm:
+			attrs.RealmTag = strings.ToLower(attr.Value)
+		case TxtKeyRealmSalt:
+			attrs.RealmSalt = strings.ToLower(attr.Value)
+		case TxtKeyRoles:
+			attrs.Roles = splitList(attr.Value)
+		case TxtKeyAppVersion:
+			attrs.AppVersion = attr.Value
+		}
+	}
+
+	return addrs, attrs
+}
+
+// splitList 拆分逗号分隔的列表，忽略空项
+func splitList(value string) []string {
+	var out []string
+	for _, v := range strings.Split(value, attrListSep) {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// peerFilter 按 TXT 属性筛选发现结果
+type peerFilter struct {
+	realm       types.RealmID
+	realmSecret []byte
+	protocols   []string
+	role        string
+	appVersion  string
+}
+
+// newPeerFilter 从发现选项创建筛选器
+//
+// secretFor 返回已加入 Realm 的发现密钥；未加入的 Realm 没有密钥，
+// 不会匹配任何服务实例。未设置任何属性条件时返回 nil，表示不筛选。
+func newPeerFilter(secretFor func(types.RealmID) []byte, opts ...pkgif.DiscoveryOption) *peerFilter {
+	options := &pkgif.DiscoveryOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.Realm == "" && len(options.Protocols) == 0 && options.Role == "" && options.AppVersion == "" {
+		return nil
+	}
+
+	f := &peerFilter{
+		realm:      options.Realm,
+		protocols:  options.Protocols,
+		role:       options.Role,
+		appVersion: options.AppVersion,
+	}
+	if options.Realm != "" && secretFor != nil {
+		f.realmSecret = secretFor(options.Realm)
+	}
+	return f
+}
+
+// match 检查属性是否满足筛选条件
+func (f *peerFilter) match(attrs Attributes) bool {
+	if f == nil {
+		return true
+	}
+	if f.realm != "" && !matchRealmTag(f.realmSecret, attrs) {
+		return false
+	}
+	for _, p := range f.protocols {
+		if !containsString(attrs.Protocols, p) {
+			return false
+		}
+	}
+	if f.role != "" && !containsString(attrs.Roles, f.role) {
+		return false
+	}
+	if f.appVersion != "" && f.appVersion != attrs.AppVersion {
+		return false
+	}
+	return true
+}
+
+// containsString 检查切片是否包含指定字符串
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,208 @@
+package mdns
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/zeroconf"
+	"github.com/dep2p/go-dep2p/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPeerID = "QmYyQSo1c1Ym7orWxLYvCrM2EmxFTANf8wXmmE7DWjhx5N"
+
+var (
+	testRealmKeyA = []byte("realm-a-discovery-key")
+	testRealmKeyB = []byte("realm-b-discovery-key")
+)
+
+// testRealmAttrs 返回 Realm 服务实例的标签属性
+func testRealmAttrs(secret []byte) Attributes {
+	salt := newRealmSalt()
+	return Attributes{RealmTag: RealmTag(secret, salt), RealmSalt: hex.EncodeToString(salt)}
+}
+
+// testSecrets 模拟已加入 realm-a
+func testSecrets(realmID types.RealmID) []byte {
+	if realmID == "realm-a" {
+		return testRealmKeyA
+	}
+	return nil
+}
+
+// TestTXT_RoundTrip 测试 TXT 记录编解码
+func TestTXT_RoundTrip(t *testing.T) {
+	attrs := testRealmAttrs(testRealmKeyA)
+	attrs.Protocols = []string{"/dep2p/sys/ping/1.0.0", "/dep2p/app/chat/1.0.0"}
+	attrs.Roles = []string{RoleRelay, RoleBootstrap}
+	attrs.AppVersion = "v1.2.3"
+	addrs := []string{"/ip4/192.168.1.2/tcp/4001/p2p/" + testPeerID}
+
+	txts := encodeTXT(addrs, attrs)
+	assert.Equal(t, TxtKeyVersion+"="+TxtVersion, txts[0])
+
+	gotAddrs, gotAttrs := parseTXT(txts)
+	assert.Equal(t, addrs, gotAddrs)
+	assert.Equal(t, attrs, gotAttrs)
+}
+
+// TestTXT_LongProtocolList 测试协议列表拆分为多条 TXT 字符串
+func TestTXT_LongProtocolList(t *testing.T) {
+	var protocols []string
+	for i := 0; i < 40; i++ {
+		protocols = append(protocols, "/dep2p/app/service-"+strings.Repeat("x", 10)+"/"+string(rune('a'+i%26))+"/1.0.0")
+	}
+	protocols = append(protocols, "bad,protocol")
+
+	txts := encodeTXT(nil, Attributes{Protocols: protocols})
+	n := 0
+	for _, txt := range txts {
+		assert.LessOrEqual(t, len(txt), zeroconf.MaxTXTStringLen)
+		if strings.HasPrefix(txt, TxtKeyProtocols+"=") {
+			n++
+		}
+	}
+	assert.Greater(t, n, 1)
+
+	_, attrs := parseTXT(txts)
+	assert.Equal(t, protocols[:40], attrs.Protocols)
+}
+
+// TestTXT_Legacy 测试解析不带属性的旧版本 TXT 记录
+func TestTXT_Legacy(t *testing.T) {
+	addrs, attrs := parseTXT([]string{
+		DNSAddrPrefix + "/ip4/192.168.1.2/tcp/4001/p2p/" + testPeerID,
+		"",
+		"=ignored",
+		"flag",
+		"ROLE=relay",
+		"role=bootstrap",
+	})
+	assert.Len(t, addrs, 1)
+	assert.Empty(t, attrs.RealmTag)
+	// 键不区分大小写，只取首次出现的值
+	assert.Equal(t, []string{RoleRelay}, attrs.Roles)
+}
+
+// TestRealmTag 测试 Realm 标签依赖发现密钥与盐
+func TestRealmTag(t *testing.T) {
+	salt := newRealmSalt()
+	tag := RealmTag(testRealmKeyA, salt)
+	assert.Len(t, tag, realmTagLen*2)
+	assert.Equal(t, tag, RealmTag(testRealmKeyA, salt))
+	assert.NotEqual(t, tag, RealmTag(testRealmKeyB, salt))
+
+	// 换盐后标签不同，观察者无法关联
+	assert.NotEqual(t, tag, RealmTag(testRealmKeyA, newRealmSalt()))
+
+	attrs := Attributes{RealmTag: tag, RealmSalt: hex.EncodeToString(salt)}
+	assert.True(t, matchRealmTag(testRealmKeyA, attrs))
+	assert.False(t, matchRealmTag(testRealmKeyB, attrs))
+	assert.False(t, matchRealmTag(nil, attrs))
+	assert.False(t, matchRealmTag(testRealmKeyA, Attributes{RealmTag: tag}), "tag without salt must not match")
+}
+
+// TestPeerFilter 测试属性筛选
+func TestPeerFilter(t *testing.T) {
+	attrs := testRealmAttrs(testRealmKeyA)
+	attrs.Protocols = []string{"/a/1.0.0", "/b/1.0.0"}
+	attrs.Roles = []string{RoleRelay}
+	attrs.AppVersion = "v1"
+
+	assert.Nil(t, newPeerFilter(testSecrets, pkgif.WithLimit(10)))
+	assert.True(t, newPeerFilter(testSecrets).match(attrs))
+
+	tests := []struct {
+		name string
+		opts []pkgif.DiscoveryOption
+		want bool
+	}{
+		{"realm", []pkgif.DiscoveryOption{pkgif.WithRealm("realm-a")}, true},
+		{"unjoined realm", []pkgif.DiscoveryOption{pkgif.WithRealm("realm-b")}, false},
+		{"protocols", []pkgif.DiscoveryOption{pkgif.WithProtocols("/a/1.0.0", "/b/1.0.0")}, true},
+		{"missing protocol", []pkgif.DiscoveryOption{pkgif.WithProtocols("/a/1.0.0", "/c/1.0.0")}, false},
+		{"role", []pkgif.DiscoveryOption{pkgif.WithRole(RoleRelay)}, true},
+		{"missing role", []pkgif.DiscoveryOption{pkgif.WithRole(RoleBootstrap)}, false},
+		{"version", []pkgif.DiscoveryOption{pkgif.WithAppVersion("v1")}, true},
+		{"other version", []pkgif.DiscoveryOption{pkgif.WithAppVersion("v2")}, false},
+		{"combined", []pkgif.DiscoveryOption{pkgif.WithRealm("realm-a"), pkgif.WithRole(RoleRelay), pkgif.WithAppVersion("v1")}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, newPeerFilter(testSecrets, tt.opts...).match(attrs))
+		})
+	}
+
+	// 旧版本节点不携带属性，不满足任何属性条件
+	assert.False(t, newPeerFilter(testSecrets, pkgif.WithRealm("realm-a")).match(Attributes{}))
+
+	// 其他 Realm 的标签不匹配
+	assert.False(t, newPeerFilter(testSecrets, pkgif.WithRealm("realm-a")).match(testRealmAttrs(testRealmKeyB)))
+}
+
+// TestPeerNotifee_Filter 测试按服务实例筛选，同一节点的其他实例仍可满足条件
+func TestPeerNotifee_Filter(t *testing.T) {
+	peerCh := make(chan types.PeerInfo, 4)
+	notifee := newPeerNotifee(context.Background(), "self", peerCh, newPeerFilter(testSecrets, pkgif.WithRealm("realm-a")))
+
+	addrs := []string{"/ip4/192.168.1.2/tcp/4001/p2p/" + testPeerID}
+	base := zeroconf.NewServiceEntry("base", DefaultServiceTag, MDNSDomain)
+	base.Text = encodeTXT(addrs, Attributes{})
+	realm := zeroconf.NewServiceEntry("realm", DefaultServiceTag, MDNSDomain)
+	realm.Text = encodeTXT(addrs, testRealmAttrs(testRealmKeyA))
+
+	require.NoError(t, notifee.handleEntry(base))
+	assert.Empty(t, peerCh)
+
+	require.NoError(t, notifee.handleEntry(realm))
+	require.Len(t, peerCh, 1)
+	info := <-peerCh
+	assert.Equal(t, types.PeerID(testPeerID), info.ID)
+
+	// 同一节点不重复通知
+	require.NoError(t, notifee.handleEntry(realm))
+	assert.Empty(t, peerCh)
+}
+
+// TestMDNS_Realms 测试 Realm 服务实例管理
+func TestMDNS_Realms(t *testing.T) {
+	m, err := New(&mockHost{id: "testhost"}, DefaultConfig())
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, m.AddRealm("", testRealmKeyA), ErrEmptyRealm)
+	assert.ErrorIs(t, m.AddRealm("realm-a", nil), ErrEmptyRealmKey)
+
+	// 服务器未运行时只记录，启动时一并注册
+	require.NoError(t, m.AddRealm("realm-a", testRealmKeyA))
+	require.NoError(t, m.AddRealm("realm-a", testRealmKeyA))
+	require.NoError(t, m.AddRealm("realm-b", testRealmKeyB))
+	assert.ElementsMatch(t, []types.RealmID{"realm-a", "realm-b"}, m.Realms())
+
+	// 每个 Realm 使用独立的实例名，与基础实例不同
+	a, b := m.realms["realm-a"], m.realms["realm-b"]
+	assert.NotEqual(t, a.name, b.name)
+	assert.NotEqual(t, m.peerName, a.name)
+	assert.NotEqual(t, a.salt, b.salt)
+
+	// 轮换更换实例名与盐，保留发现密钥
+	m.rotateRealms()
+	assert.NotEqual(t, a.name, m.realms["realm-a"].name)
+	assert.NotEqual(t, a.salt, m.realms["realm-a"].salt)
+	assert.Equal(t, testRealmKeyA, m.realms["realm-a"].secret)
+
+	m.RemoveRealm("realm-a")
+	assert.Equal(t, []types.RealmID{"realm-b"}, m.Realms())
+
+	// Advertise 只能广播已加入的 Realm
+	_, err = m.Advertise(context.Background(), "ns", pkgif.WithRealm("realm-c"))
+	assert.ErrorIs(t, err, ErrRealmNotJoined)
+	_, err = m.Advertise(context.Background(), "ns", pkgif.WithRealm("realm-b"))
+	require.NoError(t, err)
+
+	require.NoError(t, m.Stop(context.Background()))
+	assert.ErrorIs(t, m.AddRealm("realm-d", testRealmKeyA), ErrAlreadyClosed)
+}
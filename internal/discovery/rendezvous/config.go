@@ -31,8 +31,11 @@ type DiscovererConfig struct {
 	// MaxRetries 最大重试次数
 	MaxRetries int
 
-	// RetryInterval 重试间隔
+	// RetryInterval 重试间隔（订阅断开后的重连间隔同样使用此值）
 	RetryInterval time.Duration
+
+	// PollInterval Point 不支持订阅时回退轮询的间隔（0 = 默认值）
+	PollInterval time.Duration
 }
 
 // DefaultDiscovererConfig 默认配置
@@ -45,6 +48,7 @@ func DefaultDiscovererConfig() DiscovererConfig {
 		RegisterTimeout: 30 * time.Second,
 		MaxRetries:      3,
 		RetryInterval:   5 * time.Second,
+		PollInterval:    1 * time.Minute,
 	}
 }
 
@@ -103,6 +107,15 @@ type PointConfig struct {
 
 	// DefaultDiscoverLimit 默认发现限制
 	DefaultDiscoverLimit int
+
+	// WatchBacklog 每个命名空间保留的变更事件数（用于订阅续传，0 = 默认值）
+	WatchBacklog int
+
+	// MaxSubscribers 最大订阅者数（0 = 默认值）
+	MaxSubscribers int
+
+	// Replication Point 之间的注册复制
+	Replication ReplicationConfig
 }
 
 // DefaultPointConfig 默认配置
@@ -116,6 +129,9 @@ func DefaultPointConfig() PointConfig {
 		MaxRegistrationsPerNamespace: 1000,
 		MaxRegistrationsPerPeer:      100,
 		DefaultDiscoverLimit:         100,
+		WatchBacklog:                 256,
+		MaxSubscribers:               1000,
+		Replication:                  DefaultReplicationConfig(),
 	}
 }
 
@@ -148,6 +164,88 @@ func (c *PointConfig) Validate() error {
 	if c.DefaultDiscoverLimit <= 0 {
 		return errors.New("default discover limit must be positive")
 	}
+	if c.WatchBacklog < 0 {
+		return errors.New("watch backlog must be non-negative")
+	}
+	if c.MaxSubscribers < 0 {
+		return errors.New("max subscribers must be non-negative")
+	}
+	if c.Replication.Enabled {
+		if err := c.Replication.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ============================================================================
+//                              复制配置
+// ============================================================================
+
+// ReplicationConfig Point 复制配置
+type ReplicationConfig struct {
+	// Enabled 是否启用复制
+	Enabled bool
+
+	// Peers 静态配置的副本 Point（与 DHT 发现的 Point 合并）
+	Peers []types.PeerID
+
+	// ReplicationFactor 每个命名空间的副本数
+	//
+	// 命名空间的副本集合是候选 Point（含自身）中与命名空间键
+	// XOR 距离最近的 ReplicationFactor 个，只有集合内的 Point
+	// 之间互相复制该命名空间的注册。
+	ReplicationFactor int
+
+	// GossipInterval 增量推送间隔
+	GossipInterval time.Duration
+
+	// SyncInterval 反熵同步间隔（分区恢复后据此补齐缺失的注册）
+	SyncInterval time.Duration
+
+	// MaxBatch 单条复制消息携带的最大条目数
+	MaxBatch int
+
+	// MaxPending 待推送增量上限，超出后丢弃最旧的增量（由反熵同步补齐）
+	MaxPending int
+
+	// RequestTimeout 单次复制请求超时
+	RequestTimeout time.Duration
+}
+
+// DefaultReplicationConfig 默认复制配置
+func DefaultReplicationConfig() ReplicationConfig {
+	return ReplicationConfig{
+		Enabled:           false,
+		ReplicationFactor: 3,
+		GossipInterval:    1 * time.Second,
+		SyncInterval:      1 * time.Minute,
+		MaxBatch:          500,
+		MaxPending:        10000,
+		RequestTimeout:    10 * time.Second,
+	}
+}
+
+// Validate 验证配置
+func (c *ReplicationConfig) Validate() error {
+	if c.ReplicationFactor <= 0 {
+		return errors.New("replication factor must be positive")
+	}
+	if c.GossipInterval <= 0 {
+		return errors.New("replication gossip interval must be positive")
+	}
+	if c.SyncInterval <= 0 {
+		return errors.New("replication sync interval must be positive")
+	}
+	if c.MaxBatch <= 0 {
+		return errors.New("replication max batch must be positive")
+	}
+	if c.MaxPending <= 0 {
+		return errors.New("replication max pending must be positive")
+	}
+	if c.RequestTimeout <= 0 {
+		return errors.New("replication request timeout must be positive")
+	}
 	return nil
 }
 
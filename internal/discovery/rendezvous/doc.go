@@ -16,11 +16,19 @@
 //   - 发现命名空间内的节点
 //   - 分页查询
 //   - 异步发现
+//   - 订阅命名空间变更（DISCOVER_SUBSCRIBE，断线续传）
 //
 // 3. Rendezvous Point 服务端
 //   - 存储注册信息
 //   - 处理发现请求
 //   - 过期清理
+//   - 向订阅者推送注册变更事件
+//
+// 4. Point 间复制
+//   - 通过 DHT 或静态配置发现其他 Point
+//   - gossip 推送注册增量，签名记录端到端可验证
+//   - 基于版本向量的反熵同步，分区恢复后自动对账
+//   - 客户端在 Point 不可达时切换到其他副本
 //
 // # 使用场景
 //
@@ -109,6 +117,8 @@
 //   - UNREGISTER: 取消注册请求
 //   - DISCOVER: 发现请求
 //   - DISCOVER_RESPONSE: 发现响应
+//   - REPLICATE / REPLICATE_RESPONSE: Point 间推送注册增量
+//   - SYNC / SYNC_RESPONSE: Point 间反熵同步
 //
 // # 配置参数
 //
@@ -126,6 +136,8 @@
 //   - MaxNamespaces: 1000 - 最大命名空间数
 //   - MaxTTL: 72h - 最大 TTL
 //   - CleanupInterval: 5min - 清理间隔
+//   - Replication.GossipInterval: 1s - 增量推送间隔
+//   - Replication.SyncInterval: 1min - 反熵同步间隔
 //
 // # 生命周期
 //
@@ -136,6 +148,8 @@
 // ## Point
 //
 //  1. cleanupLoop(): 过期清理循环（CleanupInterval）
+//  2. gossipLoop(): 增量推送循环（启用复制时）
+//  3. syncLoop(): 反熵同步循环（启用复制时）
 //
 // # 并发安全
 //
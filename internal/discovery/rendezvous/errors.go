@@ -12,6 +12,9 @@ var (
 	// ErrInvalidTTL 无效的 TTL
 	ErrInvalidTTL = errors.New("rendezvous: invalid TTL")
 
+	// ErrInvalidSignedPeerRecord 无效的签名节点记录
+	ErrInvalidSignedPeerRecord = errors.New("rendezvous: invalid signed peer record")
+
 	// ErrInvalidCookie 无效的分页 cookie
 	ErrInvalidCookie = errors.New("rendezvous: invalid cookie")
 
@@ -57,6 +60,21 @@ var (
 	// ErrNilHost Host 为空
 	ErrNilHost = errors.New("rendezvous: host is nil")
 
+	// ErrTooManySubscribers 订阅者数量已达上限
+	ErrTooManySubscribers = errors.New("rendezvous: too many subscribers")
+
+	// ErrWatchUnsupported Rendezvous 点不支持订阅
+	ErrWatchUnsupported = errors.New("rendezvous: point does not support subscribe")
+
+	// ErrNoPrivateKey 未设置签名注册记录的私钥
+	ErrNoPrivateKey = errors.New("rendezvous: no private key to sign records")
+
+	// ErrNotReplica 对端不是已知的副本 Point
+	ErrNotReplica = errors.New("rendezvous: peer is not a known replica")
+
+	// ErrInvalidReplicaEntry 复制条目无效（签名记录无法验证等）
+	ErrInvalidReplicaEntry = errors.New("rendezvous: invalid replica entry")
+
 	// ErrStoreClosed 存储已关闭
 	ErrStoreClosed = errors.New("rendezvous: store is closed")
 )
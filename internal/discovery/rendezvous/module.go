@@ -20,6 +20,7 @@ type Params struct {
 
 	Host       pkgif.Host     // 移除 name 标签
 	UnifiedCfg *config.Config `optional:"true"`
+	Identity   pkgif.Identity `optional:"true"` // 用于签名注册记录
 }
 
 // Result Rendezvous 导出结果
@@ -44,6 +45,7 @@ func ConfigFromUnified(cfg *config.Config) DiscovererConfig {
 		RegisterTimeout: cfg.Discovery.Rendezvous.QueryInterval.Duration(),
 		MaxRetries:      3,
 		RetryInterval:   cfg.Discovery.Rendezvous.QueryInterval.Duration() / 6,
+		PollInterval:    cfg.Discovery.Rendezvous.QueryInterval.Duration(),
 	}
 }
 
@@ -52,6 +54,13 @@ func NewFromParams(p Params) (Result, error) {
 	cfg := ConfigFromUnified(p.UnifiedCfg)
 	discoverer := NewDiscoverer(p.Host, cfg)
 
+	// 使用 Identity 的私钥签名注册与取消注册记录
+	if p.Identity != nil {
+		if err := discoverer.SetPrivateKey(p.Identity.PrivateKey()); err != nil {
+			logger.Warn("Rendezvous 签名私钥设置失败", "error", err)
+		}
+	}
+
 	return Result{
 		Rendezvous: discoverer,
 		Discovery:  discoverer,
@@ -2,6 +2,7 @@ package rendezvous
 
 import (
 	"context"
+	"io"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -17,6 +18,12 @@ type Point struct {
 	store  *Store
 	host   pkgif.Host
 
+	// 命名空间变更订阅
+	watch *watchHub
+
+	// Point 之间的注册复制（未启用时为 nil）
+	replicator *replicator
+
 	// 统计
 	registersReceived uint64
 	discoversReceived uint64
@@ -42,15 +49,43 @@ func NewPoint(host pkgif.Host, config PointConfig) *Point {
 		CleanupInterval:              config.CleanupInterval,
 	}
 
+	defaults := DefaultPointConfig()
+	if config.WatchBacklog <= 0 {
+		config.WatchBacklog = defaults.WatchBacklog
+	}
+	if config.MaxSubscribers <= 0 {
+		config.MaxSubscribers = defaults.MaxSubscribers
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
-	return &Point{
+	store := NewStore(storeConfig)
+	watch := newWatchHub(config.WatchBacklog, config.MaxSubscribers)
+	store.SetNotifier(watch.publish)
+
+	p := &Point{
 		config:    config,
-		store:     NewStore(storeConfig),
+		store:     store,
 		host:      host,
+		watch:     watch,
 		ctx:       ctx,
 		ctxCancel: cancel,
 	}
+	if config.Replication.Enabled {
+		p.replicator = newReplicator(p, config.Replication)
+	}
+	return p
+}
+
+// NewPointWithDHT 创建通过 DHT 发现其他 Point 并互相复制注册的 Point
+//
+// Point 启动后在 DHT 上宣告自己，并与发现的其他 Point（以及
+// config.Replication.Peers 中的静态副本）同步注册。
+func NewPointWithDHT(host pkgif.Host, dht pkgif.DHT, config PointConfig) *Point {
+	config.Replication.Enabled = true
+	p := NewPoint(host, config)
+	p.replicator.discovery = NewPointDiscovery(dht, host, DefaultPointDiscoveryConfig())
+	return p
 }
 
 // Start 启动 Point
@@ -70,6 +105,10 @@ func (p *Point) Start(_ context.Context) error {
 	p.wg.Add(1)
 	go p.cleanupLoop()
 
+	if p.replicator != nil {
+		p.replicator.start()
+	}
+
 	return nil
 }
 
@@ -82,6 +121,10 @@ func (p *Point) Stop() error {
 	p.started.Store(false)
 	p.ctxCancel()
 
+	if p.replicator != nil {
+		p.replicator.stop()
+	}
+
 	// 等待后台循环结束
 	p.wg.Wait()
 
@@ -105,6 +148,16 @@ func (p *Point) handleStream(stream pkgif.Stream) {
 		return
 	}
 
+	// 订阅与同步使用多条消息应答，单独处理
+	switch req.Type {
+	case pb.Message_DISCOVER_SUBSCRIBE:
+		p.handleSubscribe(stream, req)
+		return
+	case pb.Message_SYNC:
+		p.handleSync(stream, req)
+		return
+	}
+
 	// 路由到对应处理函数
 	var resp *pb.Message
 	switch req.Type {
@@ -114,6 +167,8 @@ func (p *Point) handleStream(stream pkgif.Stream) {
 		resp = p.handleUnregister(req)
 	case pb.Message_DISCOVER:
 		resp = p.handleDiscover(req)
+	case pb.Message_REPLICATE:
+		resp = p.handleReplicate(stream, req)
 	default:
 		resp = NewRegisterResponse(pb.Message_E_INTERNAL_ERROR, "unknown message type", 0)
 	}
@@ -135,16 +190,30 @@ func (p *Point) handleRegister(req *pb.Message) *pb.Message {
 		return NewRegisterResponse(pb.Message_E_INVALID_NAMESPACE, err.Error(), 0)
 	}
 
-	// 提取 PeerInfo（简化版：从 SignedPeerRecord 解析）
-	peerID := types.PeerID(req.Register.SignedPeerRecord)
-	peerInfo := types.PeerInfo{
-		ID:    peerID,
-		Addrs: []types.Multiaddr{},
+	// 提取 PeerInfo（签名记录需通过验证，否则按简单 PeerID 处理）
+	peerInfo, err := peerInfoFromRecord(req.Register.SignedPeerRecord)
+	if err != nil {
+		return NewRegisterResponse(pb.Message_E_INVALID_SIGNED_PEER_RECORD, err.Error(), 0)
 	}
 
 	// 添加到存储
 	ttl := time.Duration(req.Register.Ttl) * time.Second
-	if err := p.store.Add(req.Register.Ns, peerInfo, ttl); err != nil {
+	if ttl <= 0 {
+		ttl = p.config.DefaultTTL
+	}
+	if ttl > p.config.MaxTTL {
+		ttl = p.config.MaxTTL
+	}
+	now := time.Now()
+	reg := &Registration{
+		Namespace:    req.Register.Ns,
+		PeerInfo:     peerInfo,
+		TTL:          ttl,
+		RegisteredAt: now,
+		ExpiresAt:    now.Add(ttl),
+		SignedRecord: req.Register.SignedPeerRecord,
+	}
+	if err := p.put(reg); err != nil {
 		return NewRegisterResponse(pb.Message_E_INTERNAL_ERROR, err.Error(), 0)
 	}
 
@@ -152,6 +221,9 @@ func (p *Point) handleRegister(req *pb.Message) *pb.Message {
 }
 
 // handleUnregister 处理取消注册请求
+//
+// 请求必须携带客户端签名的取消注册记录，记录中的节点与命名空间需与
+// 请求一致；早于当前注册记录的取消注册被忽略，防止重放旧请求。
 func (p *Point) handleUnregister(req *pb.Message) *pb.Message {
 	if req.Unregister == nil {
 		return NewRegisterResponse(pb.Message_E_INTERNAL_ERROR, "missing unregister field", 0)
@@ -162,9 +234,18 @@ func (p *Point) handleUnregister(req *pb.Message) *pb.Message {
 		return NewRegisterResponse(pb.Message_E_INVALID_NAMESPACE, err.Error(), 0)
 	}
 
+	// 验证签名记录
+	record, err := VerifyUnregisterRecord(req.Unregister.SignedRecord)
+	if err != nil {
+		return NewRegisterResponse(pb.Message_E_INVALID_SIGNED_PEER_RECORD, err.Error(), 0)
+	}
+	if record.Namespace != req.Unregister.Ns ||
+		(len(req.Unregister.Id) > 0 && record.PeerID != types.PeerID(req.Unregister.Id)) {
+		return NewRegisterResponse(pb.Message_E_INVALID_SIGNED_PEER_RECORD, "unregister record does not match request", 0)
+	}
+
 	// 移除注册
-	peerID := types.PeerID(req.Unregister.Id)
-	p.store.Remove(req.Unregister.Ns, peerID)
+	p.remove(record, req.Unregister.SignedRecord)
 
 	return NewRegisterResponse(pb.Message_OK, "", 0)
 }
@@ -196,12 +277,140 @@ func (p *Point) handleDiscover(req *pb.Message) *pb.Message {
 	// 转换为 protobuf
 	var pbRegs []*pb.Message_Registration
 	for _, reg := range regs {
-		pbRegs = append(pbRegs, PeerInfoToRegistration(reg.PeerInfo, reg.Namespace, reg.RemainingTTL()))
+		pbRegs = append(pbRegs, registrationToPB(reg, reg.RemainingTTL()))
 	}
 
 	return NewDiscoverResponse(pb.Message_OK, "", pbRegs, nextCookie)
 }
 
+// handleSubscribe 处理订阅请求
+//
+// 先返回快照（或续传确认），随后在同一条流上持续推送变更事件，
+// 直到客户端关闭流、Point 停止或订阅因消费过慢被断开。
+func (p *Point) handleSubscribe(stream pkgif.Stream, req *pb.Message) {
+	atomic.AddUint64(&p.discoversReceived, 1)
+
+	if req.DiscoverSubscribe == nil {
+		_ = WriteMessage(stream, NewDiscoverSubscribeResponse(pb.Message_E_INTERNAL_ERROR, "missing discover subscribe field", false, nil, nil))
+		return
+	}
+	ns := req.DiscoverSubscribe.Ns
+	if err := ValidateNamespace(ns); err != nil {
+		_ = WriteMessage(stream, NewDiscoverSubscribeResponse(pb.Message_E_INVALID_NAMESPACE, err.Error(), false, nil, nil))
+		return
+	}
+
+	var (
+		sub     *watchSubscriber
+		resumed bool
+		regs    []*Registration
+		cookie  []byte
+		err     error
+	)
+	p.store.view(ns, func(current []*Registration) {
+		sub, resumed, regs, cookie, err = p.watch.subscribe(ns, req.DiscoverSubscribe.Cookie, func() []*Registration {
+			return current
+		})
+	})
+	if err != nil {
+		_ = WriteMessage(stream, NewDiscoverSubscribeResponse(pb.Message_E_UNAVAILABLE, err.Error(), false, nil, nil))
+		return
+	}
+	defer p.watch.unsubscribe(sub)
+
+	pbRegs := make([]*pb.Message_Registration, 0, len(regs))
+	for _, reg := range regs {
+		pbRegs = append(pbRegs, registrationToPB(reg, reg.RemainingTTL()))
+	}
+	if err := WriteMessage(stream, NewDiscoverSubscribeResponse(pb.Message_OK, "", resumed, pbRegs, cookie)); err != nil {
+		return
+	}
+
+	// 客户端关闭流时结束推送
+	ctx, cancel := context.WithCancel(p.ctx)
+	defer cancel()
+	go func() {
+		defer cancel()
+		_, _ = io.Copy(io.Discard, stream)
+	}()
+
+	for {
+		select {
+		case event, ok := <-sub.ch:
+			if !ok {
+				return
+			}
+			if err := WriteMessage(stream, NewDiscoverEvent(event)); err != nil {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// handleReplicate 处理副本推送的增量
+func (p *Point) handleReplicate(stream pkgif.Stream, req *pb.Message) *pb.Message {
+	if p.replicator == nil {
+		return NewReplicateResponse(pb.Message_E_UNAVAILABLE, "replication disabled")
+	}
+	var remote types.PeerID
+	if conn := stream.Conn(); conn != nil {
+		remote = conn.RemotePeer()
+	}
+	return p.replicator.handleReplicate(remote, req)
+}
+
+// handleSync 处理反熵同步请求
+func (p *Point) handleSync(stream pkgif.Stream, req *pb.Message) {
+	if p.replicator == nil {
+		_ = WriteMessage(stream, NewSyncResponse(pb.Message_E_UNAVAILABLE, "replication disabled", nil, nil, true))
+		return
+	}
+	p.replicator.handleSync(stream, req)
+}
+
+// ============================================================================
+//                              存储写入
+// ============================================================================
+
+// put 写入本地注册（启用复制时同时记录增量）
+func (p *Point) put(reg *Registration) error {
+	if p.replicator != nil {
+		return p.replicator.put(reg)
+	}
+	return p.store.Put(reg)
+}
+
+// remove 按已验证的取消注册记录移除本地注册（启用复制时同时记录墓碑）
+func (p *Point) remove(record *UnregisterRecord, signed []byte) {
+	if p.replicator != nil {
+		p.replicator.remove(record, signed)
+		return
+	}
+	if at, ok := p.registeredAt(record.Namespace, record.PeerID); ok && at.After(record.Timestamp) {
+		return
+	}
+	p.store.Remove(record.Namespace, record.PeerID)
+}
+
+// registeredAt 返回当前注册的签名记录时间
+func (p *Point) registeredAt(namespace string, peer types.PeerID) (time.Time, bool) {
+	var (
+		at    time.Time
+		found bool
+	)
+	p.store.view(namespace, func(regs []*Registration) {
+		for _, reg := range regs {
+			if reg.PeerInfo.ID == peer {
+				at, found = recordTimestamp(reg.SignedRecord, false), true
+				return
+			}
+		}
+	})
+	return at, found
+}
+
 // ============================================================================
 //                              后台循环
 // ============================================================================
@@ -217,6 +426,9 @@ func (p *Point) cleanupLoop() {
 		select {
 		case <-ticker.C:
 			p.store.CleanupExpired()
+			if p.replicator != nil {
+				p.replicator.expire()
+			}
 
 		case <-p.ctx.Done():
 			return
@@ -232,3 +444,16 @@ func (p *Point) cleanupLoop() {
 func (p *Point) Stats() Stats {
 	return p.store.Stats()
 }
+
+// Replicas 返回当前的副本 Point（未启用复制时为 nil）
+func (p *Point) Replicas() []types.PeerID {
+	if p.replicator == nil {
+		return nil
+	}
+	return p.replicator.replicas()
+}
+
+// Subscribers 返回当前订阅者数量
+func (p *Point) Subscribers() int {
+	return p.watch.subscriberCount()
+}
@@ -56,6 +56,9 @@ type PointDiscovery struct {
 	points   []types.PeerID
 	pointsMu sync.RWMutex
 
+	// onUpdate Points 列表更新回调
+	onUpdate func([]types.PeerID)
+
 	// 生命周期
 	ctx       context.Context
 	ctxCancel context.CancelFunc
@@ -118,6 +121,11 @@ func (pd *PointDiscovery) GetPoints() []types.PeerID {
 	return result
 }
 
+// SetUpdateHandler 设置 Points 列表更新回调（需在 Start 之前调用）
+func (pd *PointDiscovery) SetUpdateHandler(fn func([]types.PeerID)) {
+	pd.onUpdate = fn
+}
+
 // AnnounceAsPoint 宣告自己为 Rendezvous Point
 func (pd *PointDiscovery) AnnounceAsPoint(ctx context.Context) error {
 	if pd.dht == nil {
@@ -187,6 +195,10 @@ func (pd *PointDiscovery) discoverPoints() {
 		pd.pointsMu.Lock()
 		pd.points = newPoints
 		pd.pointsMu.Unlock()
+
+		if pd.onUpdate != nil {
+			pd.onUpdate(append([]types.PeerID(nil), newPoints...))
+		}
 	}
 }
 
@@ -207,6 +219,12 @@ func NewDiscovererWithDHT(host pkgif.Host, dht pkgif.DHT, config DiscovererConfi
 	pointConfig := DefaultPointDiscoveryConfig()
 	pointDiscovery := NewPointDiscovery(dht, host, pointConfig)
 
+	// 发现的 Points 与静态配置合并后供 Discoverer 选择与故障切换
+	static := append([]types.PeerID(nil), config.Points...)
+	pointDiscovery.SetUpdateHandler(func(points []types.PeerID) {
+		discoverer.SetPoints(mergePoints(static, points))
+	})
+
 	return &DiscovererWithDHT{
 		Discoverer:     discoverer,
 		pointDiscovery: pointDiscovery,
@@ -239,3 +257,19 @@ func (d *DiscovererWithDHT) Stop(ctx context.Context) error {
 	// 停止 Point 发现
 	return d.pointDiscovery.Stop()
 }
+
+// mergePoints 合并 Point 列表（保持顺序并去重）
+func mergePoints(lists ...[]types.PeerID) []types.PeerID {
+	seen := make(map[types.PeerID]struct{})
+	var result []types.PeerID
+	for _, list := range lists {
+		for _, p := range list {
+			if _, ok := seen[p]; ok {
+				continue
+			}
+			seen[p] = struct{}{}
+			result = append(result, p)
+		}
+	}
+	return result
+}
@@ -9,6 +9,7 @@ import (
 
 	"google.golang.org/protobuf/proto"
 
+	"github.com/dep2p/go-dep2p/pkg/lib/crypto"
 	pb "github.com/dep2p/go-dep2p/pkg/lib/proto/rendezvous"
 	"github.com/dep2p/go-dep2p/pkg/protocol"
 	"github.com/dep2p/go-dep2p/pkg/types"
@@ -110,12 +111,15 @@ func NewRegisterRequest(namespace string, signedPeerRecord []byte, ttl time.Dura
 }
 
 // NewUnregisterRequest 创建取消注册请求
-func NewUnregisterRequest(namespace string, peerID []byte) *pb.Message {
+//
+// signedRecord 为 SignUnregisterRecord 生成的签名信封。
+func NewUnregisterRequest(namespace string, peerID []byte, signedRecord []byte) *pb.Message {
 	return &pb.Message{
 		Type: pb.Message_UNREGISTER,
 		Unregister: &pb.Message_Unregister{
-			Ns: namespace,
-			Id: peerID,
+			Ns:           namespace,
+			Id:           peerID,
+			SignedRecord: signedRecord,
 		},
 	}
 }
@@ -132,6 +136,37 @@ func NewDiscoverRequest(namespace string, limit int, cookie []byte) *pb.Message
 	}
 }
 
+// NewDiscoverSubscribeRequest 创建订阅请求
+func NewDiscoverSubscribeRequest(namespace string, cookie []byte) *pb.Message {
+	return &pb.Message{
+		Type: pb.Message_DISCOVER_SUBSCRIBE,
+		DiscoverSubscribe: &pb.Message_DiscoverSubscribe{
+			Ns:     namespace,
+			Cookie: cookie,
+		},
+	}
+}
+
+// NewReplicateRequest 创建增量推送请求
+func NewReplicateRequest(entries []*pb.Message_ReplicaEntry) *pb.Message {
+	return &pb.Message{
+		Type: pb.Message_REPLICATE,
+		Replicate: &pb.Message_Replicate{
+			Entries: entries,
+		},
+	}
+}
+
+// NewSyncRequest 创建反熵同步请求
+func NewSyncRequest(versions []*pb.Message_Version) *pb.Message {
+	return &pb.Message{
+		Type: pb.Message_SYNC,
+		Sync: &pb.Message_Sync{
+			Versions: versions,
+		},
+	}
+}
+
 // ============================================================================
 //                              响应构造器
 // ============================================================================
@@ -161,29 +196,61 @@ func NewDiscoverResponse(status pb.Message_ResponseStatus, statusText string, re
 	}
 }
 
+// NewDiscoverSubscribeResponse 创建订阅响应
+func NewDiscoverSubscribeResponse(status pb.Message_ResponseStatus, statusText string, resumed bool, registrations []*pb.Message_Registration, cookie []byte) *pb.Message {
+	return &pb.Message{
+		Type: pb.Message_DISCOVER_SUBSCRIBE_RESPONSE,
+		DiscoverSubscribeResponse: &pb.Message_DiscoverSubscribeResponse{
+			Status:        status,
+			StatusText:    statusText,
+			Resumed:       resumed,
+			Registrations: registrations,
+			Cookie:        cookie,
+		},
+	}
+}
+
+// NewDiscoverEvent 创建变更事件消息
+func NewDiscoverEvent(event *pb.Message_DiscoverEvent) *pb.Message {
+	return &pb.Message{
+		Type:          pb.Message_DISCOVER_EVENT,
+		DiscoverEvent: event,
+	}
+}
+
+// NewReplicateResponse 创建增量推送响应
+func NewReplicateResponse(status pb.Message_ResponseStatus, statusText string) *pb.Message {
+	return &pb.Message{
+		Type: pb.Message_REPLICATE_RESPONSE,
+		ReplicateResponse: &pb.Message_ReplicateResponse{
+			Status:     status,
+			StatusText: statusText,
+		},
+	}
+}
+
+// NewSyncResponse 创建反熵同步响应
+func NewSyncResponse(status pb.Message_ResponseStatus, statusText string, entries []*pb.Message_ReplicaEntry, versions []*pb.Message_Version, done bool) *pb.Message {
+	return &pb.Message{
+		Type: pb.Message_SYNC_RESPONSE,
+		SyncResponse: &pb.Message_SyncResponse{
+			Status:     status,
+			StatusText: statusText,
+			Entries:    entries,
+			Versions:   versions,
+			Done:       done,
+		},
+	}
+}
+
 // ============================================================================
 //                              类型转换
 // ============================================================================
 
 // RegistrationToPeerInfo 将 Registration 转换为 PeerInfo
-// 完整实现：解析并验证 SignedPeerRecord
+// 完整实现：解析并验证 SignedPeerRecord，无法验证的注册返回错误
 func RegistrationToPeerInfo(reg *pb.Message_Registration) (types.PeerInfo, error) {
-	if len(reg.SignedPeerRecord) == 0 {
-		return types.PeerInfo{}, errors.New("empty signed peer record")
-	}
-
-	// 尝试解析为 SignedPeerRecord
-	peerInfo, err := ExtractPeerInfoFromSignedRecord(reg.SignedPeerRecord)
-	if err != nil {
-		// 向后兼容：如果解析失败，尝试作为简单 PeerID
-		peerID := types.PeerID(reg.SignedPeerRecord)
-		return types.PeerInfo{
-			ID:    peerID,
-			Addrs: []types.Multiaddr{},
-		}, nil
-	}
-
-	return peerInfo, nil
+	return peerInfoFromRecord(reg.SignedPeerRecord)
 }
 
 // PeerInfoToRegistration 将 PeerInfo 转换为 Registration
@@ -197,6 +264,51 @@ func PeerInfoToRegistration(info types.PeerInfo, namespace string, ttl time.Dura
 	}
 }
 
+// registrationToPB 将存储中的注册转换为 protobuf
+//
+// 优先携带客户端提交的原始签名记录，使发现方可以独立验证。
+func registrationToPB(reg *Registration, ttl time.Duration) *pb.Message_Registration {
+	if len(reg.SignedRecord) == 0 {
+		return PeerInfoToRegistration(reg.PeerInfo, reg.Namespace, ttl)
+	}
+	return &pb.Message_Registration{
+		Ns:               reg.Namespace,
+		SignedPeerRecord: reg.SignedRecord,
+		Ttl:              uint64(ttl.Seconds()),
+	}
+}
+
+// peerInfoFromRecord 从注册记录解析 PeerInfo
+//
+// 记录必须是 SignedPeerRecord，签名有效且公钥与 PeerID 匹配。
+func peerInfoFromRecord(record []byte) (types.PeerInfo, error) {
+	if len(record) == 0 {
+		return types.PeerInfo{}, fmt.Errorf("%w: empty signed peer record", ErrInvalidSignedPeerRecord)
+	}
+
+	signed, err := UnmarshalSignedPeerRecord(record)
+	if err != nil {
+		return types.PeerInfo{}, fmt.Errorf("%w: %v", ErrInvalidSignedPeerRecord, err)
+	}
+
+	valid, err := VerifySignedPeerRecord(signed)
+	if err != nil {
+		return types.PeerInfo{}, fmt.Errorf("%w: %v", ErrInvalidSignedPeerRecord, err)
+	}
+	if !valid {
+		return types.PeerInfo{}, fmt.Errorf("%w: invalid signature", ErrInvalidSignedPeerRecord)
+	}
+	if ok, err := crypto.VerifyPeerID(signed.PublicKey, signed.PeerRecord.PeerID); err != nil || !ok {
+		return types.PeerInfo{}, fmt.Errorf("%w: public key does not match peer ID", ErrInvalidSignedPeerRecord)
+	}
+
+	return types.PeerInfo{
+		ID:    signed.PeerRecord.PeerID,
+		Addrs: signed.PeerRecord.Addrs,
+		Seq:   signed.PeerRecord.Seq,
+	}, nil
+}
+
 // CreateSignedRegistration 创建带签名的 Registration
 func CreateSignedRegistration(signedRecord *SignedPeerRecord, namespace string, ttl time.Duration) (*pb.Message_Registration, error) {
 	if signedRecord == nil {
@@ -224,6 +336,8 @@ func StatusToError(status pb.Message_ResponseStatus, statusText string) error {
 		return fmt.Errorf("%w: %s", ErrInvalidNamespace, statusText)
 	case pb.Message_E_INVALID_TTL:
 		return fmt.Errorf("%w: %s", ErrInvalidTTL, statusText)
+	case pb.Message_E_INVALID_SIGNED_PEER_RECORD:
+		return fmt.Errorf("%w: %s", ErrInvalidSignedPeerRecord, statusText)
 	case pb.Message_E_INVALID_COOKIE:
 		return fmt.Errorf("%w: %s", ErrInvalidCookie, statusText)
 	case pb.Message_E_NOT_AUTHORIZED:
@@ -11,6 +11,7 @@ import (
 	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
 	pb "github.com/dep2p/go-dep2p/pkg/lib/proto/rendezvous"
 	"github.com/dep2p/go-dep2p/pkg/types"
+	"github.com/dep2p/go-dep2p/pkg/lib/crypto"
 	"github.com/dep2p/go-dep2p/pkg/lib/log"
 )
 
@@ -61,6 +62,11 @@ type Discoverer struct {
 	// 本地 ID
 	localID types.PeerID
 
+	// 签名注册与取消注册记录的私钥（未设置时无法注册）
+	privKey crypto.PrivateKey
+	records *PeerRecordManager
+	keyMu   sync.RWMutex
+
 	// 已知的 Rendezvous 点
 	points   []types.PeerID
 	pointsMu sync.RWMutex
@@ -99,6 +105,96 @@ func NewDiscoverer(host pkgif.Host, config DiscovererConfig) *Discoverer {
 	}
 }
 
+// SetPrivateKey 设置签名注册记录的私钥
+//
+// Point 只接受客户端签名的节点记录与取消注册记录。未设置时使用
+// Host Peerstore 中的本地私钥，两者都没有时 Register 与 Unregister
+// 返回 ErrNoPrivateKey。
+//
+// 参数:
+//   - privKey: 节点私钥（支持 crypto.PrivateKey 或 pkgif.PrivateKey）
+func (d *Discoverer) SetPrivateKey(privKey interface{}) error {
+	var pk crypto.PrivateKey
+	switch k := privKey.(type) {
+	case crypto.PrivateKey:
+		pk = k
+	case pkgif.PrivateKey:
+		raw, err := k.Raw()
+		if err != nil {
+			return fmt.Errorf("failed to get raw private key: %w", err)
+		}
+		pk, err = crypto.UnmarshalPrivateKey(crypto.KeyType(k.Type()), raw)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal private key: %w", err)
+		}
+	default:
+		return fmt.Errorf("invalid private key type: %T (expected crypto.PrivateKey or pkgif.PrivateKey)", privKey)
+	}
+
+	if ok, err := crypto.VerifyPeerID(pk.GetPublic(), d.localID); err != nil || !ok {
+		return errors.New("private key does not match local peer ID")
+	}
+
+	d.keyMu.Lock()
+	d.privKey = pk
+	d.records = NewPeerRecordManager(pk, d.localID)
+	d.keyMu.Unlock()
+	return nil
+}
+
+// signer 返回签名私钥与节点记录管理器
+func (d *Discoverer) signer() (crypto.PrivateKey, *PeerRecordManager, error) {
+	d.keyMu.RLock()
+	privKey, records := d.privKey, d.records
+	d.keyMu.RUnlock()
+	if privKey != nil {
+		return privKey, records, nil
+	}
+
+	// 未显式设置时使用 Peerstore 中的本地私钥
+	if ps := d.host.Peerstore(); ps != nil {
+		if k, err := ps.PrivKey(d.localID); err == nil && k != nil {
+			if err := d.SetPrivateKey(k); err == nil {
+				return d.signer()
+			}
+		}
+	}
+	return nil, nil, ErrNoPrivateKey
+}
+
+// signedPeerRecord 签名包含当前监听地址的本地节点记录
+func (d *Discoverer) signedPeerRecord() ([]byte, error) {
+	_, records, err := d.signer()
+	if err != nil {
+		return nil, err
+	}
+
+	var addrs []types.Multiaddr
+	for _, s := range d.host.Addrs() {
+		if ma, err := types.NewMultiaddr(s); err == nil {
+			addrs = append(addrs, ma)
+		}
+	}
+	signed, err := records.CreateSignedRecord(addrs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign peer record: %w", err)
+	}
+	return signed.Marshal()
+}
+
+// signedUnregisterRecord 签名取消注册记录
+func (d *Discoverer) signedUnregisterRecord(namespace string) ([]byte, error) {
+	privKey, _, err := d.signer()
+	if err != nil {
+		return nil, err
+	}
+	return SignUnregisterRecord(privKey, &UnregisterRecord{
+		PeerID:    d.localID,
+		Namespace: namespace,
+		Timestamp: time.Now(),
+	})
+}
+
 // ============================================================================
 //                              生命周期
 // ============================================================================
@@ -159,6 +255,11 @@ func (d *Discoverer) FindPeers(ctx context.Context, ns string, opts ...pkgif.Dis
 		opt(options)
 	}
 
+	// 持续订阅：推送当前成员及后续新加入的节点，直到 ctx 结束
+	if options.Watch {
+		return d.watchPeers(ctx, ns)
+	}
+
 	ch := make(chan types.PeerInfo, options.Limit)
 
 	go func() {
@@ -225,21 +326,17 @@ func (d *Discoverer) Register(ctx context.Context, namespace string, ttl time.Du
 		return err
 	}
 
-	// 选择 Point
-	point, err := d.selectPoint()
+	// 构造请求（携带签名的本地节点记录）
+	signedPeerRecord, err := d.signedPeerRecord()
 	if err != nil {
 		return err
 	}
-
-	// 构造请求（简化：使用 PeerID 作为 SignedPeerRecord）
-	signedPeerRecord := []byte(d.localID)
 	req := NewRegisterRequest(namespace, signedPeerRecord, ttl)
 
-	// 发送请求
-	resp, err := d.sendRequest(ctx, point, req)
+	// 发送请求（失败时切换到其他 Point）
+	point, resp, err := d.request(ctx, "", req)
 	if err != nil {
-		d.recordFailure(point)
-		return fmt.Errorf("send request failed: %w", err)
+		return err
 	}
 
 	// 处理响应
@@ -273,16 +370,20 @@ func (d *Discoverer) Unregister(namespace string) error {
 		return nil // 未注册视为成功
 	}
 
-	// 构造请求
-	req := NewUnregisterRequest(namespace, []byte(d.localID))
+	// 构造请求（携带签名的取消注册记录）
+	record, err := d.signedUnregisterRecord(namespace)
+	if err != nil {
+		return err
+	}
+	req := NewUnregisterRequest(namespace, []byte(d.localID), record)
 
 	// 发送请求
 	ctx, cancel := context.WithTimeout(d.ctx, d.config.RegisterTimeout)
 	defer cancel()
 
-	_, err := d.sendRequest(ctx, reg.Point, req)
-	if err != nil {
-		return fmt.Errorf("send request failed: %w", err)
+	// 优先发往注册时的 Point，不可用时由副本代为处理
+	if _, _, err := d.request(ctx, reg.Point, req); err != nil {
+		return err
 	}
 
 	// 移除本地记录
@@ -306,20 +407,13 @@ func (d *Discoverer) Discover(ctx context.Context, namespace string, limit int)
 		return nil, err
 	}
 
-	// 选择 Point
-	point, err := d.selectPoint()
-	if err != nil {
-		return nil, err
-	}
-
 	// 构造请求
 	req := NewDiscoverRequest(namespace, limit, nil)
 
-	// 发送请求
-	resp, err := d.sendRequest(ctx, point, req)
+	// 发送请求（失败时切换到其他 Point）
+	_, resp, err := d.request(ctx, "", req)
 	if err != nil {
-		d.recordFailure(point)
-		return nil, fmt.Errorf("send request failed: %w", err)
+		return nil, err
 	}
 
 	// 处理响应
@@ -374,6 +468,52 @@ func (d *Discoverer) selectPoint() (types.PeerID, error) {
 	return "", ErrAllPointsFailed
 }
 
+// candidatePoints 返回按尝试顺序排列的健康 Point
+//
+// preferred 非空时排在首位，其余按轮询顺序排列。尝试次数不超过
+// MaxRetries+1，Point 之间互为副本时任一 Point 都能提供完整数据。
+func (d *Discoverer) candidatePoints(preferred types.PeerID) []types.PeerID {
+	d.pointsMu.RLock()
+	points := d.points
+	d.pointsMu.RUnlock()
+
+	if len(points) == 0 {
+		return nil
+	}
+
+	startIdx := int(atomic.AddUint64(&d.roundRobinIndex, 1) - 1)
+
+	result := make([]types.PeerID, 0, len(points))
+	if preferred != "" && d.isPointHealthy(preferred) {
+		result = append(result, preferred)
+	}
+	for i := 0; i < len(points); i++ {
+		point := points[(startIdx+i)%len(points)]
+		if point != preferred && d.isPointHealthy(point) {
+			result = append(result, point)
+		}
+	}
+
+	if limit := d.config.MaxRetries + 1; len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// SetPoints 替换已知的 Rendezvous 点
+func (d *Discoverer) SetPoints(points []types.PeerID) {
+	d.pointsMu.Lock()
+	defer d.pointsMu.Unlock()
+	d.points = append([]types.PeerID(nil), points...)
+}
+
+// Points 返回已知的 Rendezvous 点
+func (d *Discoverer) Points() []types.PeerID {
+	d.pointsMu.RLock()
+	defer d.pointsMu.RUnlock()
+	return append([]types.PeerID(nil), d.points...)
+}
+
 // isPointHealthy 检查 Point 是否健康
 func (d *Discoverer) isPointHealthy(point types.PeerID) bool {
 	d.healthMu.RLock()
@@ -417,6 +557,38 @@ func (d *Discoverer) recordFailure(point types.PeerID) {
 //                              网络通信
 // ============================================================================
 
+// request 发送请求，Point 不可达时依次切换到其他健康 Point
+//
+// 只有网络层失败才会切换；Point 返回的业务状态由调用方处理。
+func (d *Discoverer) request(ctx context.Context, preferred types.PeerID, req *pb.Message) (types.PeerID, *pb.Message, error) {
+	d.pointsMu.RLock()
+	havePoints := len(d.points) > 0
+	d.pointsMu.RUnlock()
+	if !havePoints {
+		return "", nil, ErrNoPoints
+	}
+
+	points := d.candidatePoints(preferred)
+	if len(points) == 0 {
+		return "", nil, ErrAllPointsFailed
+	}
+
+	var lastErr error
+	for _, point := range points {
+		resp, err := d.sendRequest(ctx, point, req)
+		if err == nil {
+			return point, resp, nil
+		}
+		d.recordFailure(point)
+		lastErr = fmt.Errorf("send request failed: %w", err)
+		if ctx.Err() != nil {
+			break
+		}
+		logger.Debug("Rendezvous 点请求失败，尝试其他点", "point", string(point), "error", err)
+	}
+	return "", nil, lastErr
+}
+
 // sendRequest 发送请求到 Point
 func (d *Discoverer) sendRequest(ctx context.Context, point types.PeerID, req *pb.Message) (*pb.Message, error) {
 	// 连接到 Point
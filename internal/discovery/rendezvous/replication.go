@@ -0,0 +1,868 @@
+package rendezvous
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	pb "github.com/dep2p/go-dep2p/pkg/lib/proto/rendezvous"
+	"github.com/dep2p/go-dep2p/pkg/types"
+)
+
+// ============================================================================
+//                              复制条目
+// ============================================================================
+
+// replicaKey 复制条目键
+type replicaKey struct {
+	namespace string
+	peer      types.PeerID
+}
+
+// replicaEntry 单条注册的复制状态
+//
+// deleted 为 true 时是取消注册留下的墓碑，保留到原注册的过期时间，
+// 以免尚未收到取消注册的副本把旧注册同步回来。record 是客户端签名
+// 的节点记录，墓碑则是客户端签名的取消注册记录。
+type replicaEntry struct {
+	namespace string
+	peer      types.PeerID
+	record    []byte
+	expiresAt time.Time
+	deleted   bool
+
+	// signedAt 签名记录中的时间，早于当前条目的记录视为重放
+	signedAt time.Time
+
+	// 版本：产生该变更的 Point 及其本地序号
+	origin  types.PeerID
+	counter uint64
+
+	// timestamp 变更产生时间（UnixNano），用于并发写入的最后写入者胜出
+	timestamp int64
+}
+
+// key 返回条目键
+func (e *replicaEntry) key() replicaKey {
+	return replicaKey{namespace: e.namespace, peer: e.peer}
+}
+
+// newerThan 判断 e 是否应覆盖 o（最后写入者胜出，按来源与序号打破平局）
+func (e *replicaEntry) newerThan(o *replicaEntry) bool {
+	if e.timestamp != o.timestamp {
+		return e.timestamp > o.timestamp
+	}
+	if e.origin != o.origin {
+		return e.origin > o.origin
+	}
+	return e.counter > o.counter
+}
+
+// registration 转换为存储注册
+func (e *replicaEntry) registration(info types.PeerInfo) *Registration {
+	registeredAt := time.Unix(0, e.timestamp)
+	return &Registration{
+		Namespace:    e.namespace,
+		PeerInfo:     info,
+		TTL:          e.expiresAt.Sub(registeredAt),
+		RegisteredAt: registeredAt,
+		ExpiresAt:    e.expiresAt,
+		SignedRecord: e.record,
+	}
+}
+
+// toPB 转换为 protobuf
+func (e *replicaEntry) toPB() *pb.Message_ReplicaEntry {
+	return &pb.Message_ReplicaEntry{
+		Ns:               e.namespace,
+		Peer:             []byte(e.peer),
+		SignedPeerRecord: e.record,
+		ExpiresAt:        e.expiresAt.UnixNano(),
+		Deleted:          e.deleted,
+		Origin:           []byte(e.origin),
+		Counter:          e.counter,
+		Timestamp:        e.timestamp,
+	}
+}
+
+// replicaEntryFromPB 从 protobuf 解析复制条目
+func replicaEntryFromPB(m *pb.Message_ReplicaEntry) (*replicaEntry, error) {
+	if m == nil {
+		return nil, ErrInvalidReplicaEntry
+	}
+	if err := ValidateNamespace(m.Ns); err != nil {
+		return nil, err
+	}
+	if len(m.Peer) == 0 || len(m.Origin) == 0 || m.Counter == 0 {
+		return nil, fmt.Errorf("%w: missing peer or version", ErrInvalidReplicaEntry)
+	}
+	if len(m.SignedPeerRecord) == 0 {
+		return nil, fmt.Errorf("%w: missing signed record", ErrInvalidReplicaEntry)
+	}
+	return &replicaEntry{
+		namespace: m.Ns,
+		peer:      types.PeerID(m.Peer),
+		record:    m.SignedPeerRecord,
+		expiresAt: time.Unix(0, m.ExpiresAt),
+		deleted:   m.Deleted,
+		origin:    types.PeerID(m.Origin),
+		counter:   m.Counter,
+		timestamp: m.Timestamp,
+	}, nil
+}
+
+// verifyReplicaRecord 验证复制条目中的签名记录属于条目声明的节点
+//
+// 签名记录由客户端产生，副本 Point 只负责转发，因此接收方无需信任
+// 转发它的 Point 即可确认注册（或取消注册）确实来自该节点。
+// 验证通过后设置条目的 signedAt。
+func verifyReplicaRecord(e *replicaEntry) (types.PeerInfo, error) {
+	if e.deleted {
+		record, err := VerifyUnregisterRecord(e.record)
+		if err != nil {
+			return types.PeerInfo{}, fmt.Errorf("%w: %v", ErrInvalidReplicaEntry, err)
+		}
+		if record.PeerID != e.peer || record.Namespace != e.namespace {
+			return types.PeerInfo{}, fmt.Errorf("%w: unregister record does not match entry", ErrInvalidReplicaEntry)
+		}
+		e.signedAt = record.Timestamp
+		return types.PeerInfo{}, nil
+	}
+
+	info, err := peerInfoFromRecord(e.record)
+	if err != nil {
+		return types.PeerInfo{}, err
+	}
+	if info.ID != e.peer {
+		return types.PeerInfo{}, fmt.Errorf("%w: record does not belong to peer", ErrInvalidReplicaEntry)
+	}
+	e.signedAt = recordTimestamp(e.record, false)
+	return info, nil
+}
+
+// recordTimestamp 返回签名记录中的时间，无法解析时返回零值
+func recordTimestamp(record []byte, deleted bool) time.Time {
+	if deleted {
+		if r, err := VerifyUnregisterRecord(record); err == nil {
+			return r.Timestamp
+		}
+		return time.Time{}
+	}
+	if signed, err := UnmarshalSignedPeerRecord(record); err == nil {
+		return signed.PeerRecord.Timestamp
+	}
+	return time.Time{}
+}
+
+// ============================================================================
+//                              replicaState 版本向量
+// ============================================================================
+
+// replicaState 复制状态
+//
+// 每个 (命名空间, 节点) 只保留最新的条目；版本向量记录每个来源 Point
+// 已连续收到的最大序号。反熵同步时请求方发送自己的版本向量，响应方
+// 返回所有序号超出该向量的条目及自己的版本向量，请求方合并后即覆盖了
+// 响应方已知的全部变更。分区恢复后的对账由此完成。
+//
+// replicaState 本身不加锁，由 replicator 串行访问。
+type replicaState struct {
+	self    types.PeerID
+	counter uint64
+	entries map[replicaKey]*replicaEntry
+	vector  map[types.PeerID]uint64
+}
+
+// newReplicaState 创建复制状态
+//
+// 本地序号从当前时间开始，Point 重启后不会复用旧序号，
+// 其他副本据此把重启后的变更识别为新变更。
+func newReplicaState(self types.PeerID) *replicaState {
+	counter := uint64(time.Now().UnixNano())
+	return &replicaState{
+		self:    self,
+		counter: counter,
+		entries: make(map[replicaKey]*replicaEntry),
+		vector:  map[types.PeerID]uint64{self: counter},
+	}
+}
+
+// local 记录本地产生的变更
+func (s *replicaState) local(namespace string, peer types.PeerID, record []byte, expiresAt time.Time, deleted bool) *replicaEntry {
+	s.counter++
+	e := &replicaEntry{
+		namespace: namespace,
+		peer:      peer,
+		record:    record,
+		expiresAt: expiresAt,
+		deleted:   deleted,
+		signedAt:  recordTimestamp(record, deleted),
+		origin:    s.self,
+		counter:   s.counter,
+		timestamp: time.Now().UnixNano(),
+	}
+	// 时钟回拨时保证本地变更仍覆盖当前条目
+	if cur, ok := s.entries[e.key()]; ok && !e.newerThan(cur) {
+		e.timestamp = cur.timestamp + 1
+	}
+	s.entries[e.key()] = e
+	s.vector[s.self] = s.counter
+	return e
+}
+
+// apply 合并远端条目，返回条目是否成为该键的最新状态
+func (s *replicaState) apply(e *replicaEntry) bool {
+	if e.counter == s.vector[e.origin]+1 {
+		s.vector[e.origin] = e.counter
+	}
+	// 自身产生的旧条目同样参与合并：Point 重启后据此从副本恢复注册；
+	// 签名时间早于当前条目的记录是重放，不生效
+	if cur, ok := s.entries[e.key()]; ok && (!e.newerThan(cur) || cur.signedAt.After(e.signedAt)) {
+		return false
+	}
+	s.entries[e.key()] = e
+	return true
+}
+
+// lookup 查询条目
+func (s *replicaState) lookup(namespace string, peer types.PeerID) *replicaEntry {
+	return s.entries[replicaKey{namespace: namespace, peer: peer}]
+}
+
+// since 返回序号超出给定版本向量的条目（按来源与序号排序）
+func (s *replicaState) since(vector map[types.PeerID]uint64) []*replicaEntry {
+	var result []*replicaEntry
+	for _, e := range s.entries {
+		if e.counter > vector[e.origin] {
+			result = append(result, e)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].origin != result[j].origin {
+			return result[i].origin < result[j].origin
+		}
+		return result[i].counter < result[j].counter
+	})
+	return result
+}
+
+// versions 返回版本向量副本
+func (s *replicaState) versions() map[types.PeerID]uint64 {
+	result := make(map[types.PeerID]uint64, len(s.vector))
+	for origin, counter := range s.vector {
+		result[origin] = counter
+	}
+	return result
+}
+
+// merge 合并对端的版本向量（仅在已应用对端全部条目后调用）
+func (s *replicaState) merge(vector map[types.PeerID]uint64) {
+	for origin, counter := range vector {
+		if counter > s.vector[origin] {
+			s.vector[origin] = counter
+		}
+	}
+}
+
+// expire 清理已过期的条目与墓碑
+func (s *replicaState) expire(now time.Time) int {
+	expired := 0
+	for key, e := range s.entries {
+		if now.After(e.expiresAt) {
+			delete(s.entries, key)
+			expired++
+		}
+	}
+	return expired
+}
+
+// versionsToPB 转换版本向量
+func versionsToPB(vector map[types.PeerID]uint64) []*pb.Message_Version {
+	result := make([]*pb.Message_Version, 0, len(vector))
+	for origin, counter := range vector {
+		result = append(result, &pb.Message_Version{Origin: []byte(origin), Counter: counter})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return bytes.Compare(result[i].Origin, result[j].Origin) < 0
+	})
+	return result
+}
+
+// versionsFromPB 解析版本向量
+func versionsFromPB(versions []*pb.Message_Version) map[types.PeerID]uint64 {
+	result := make(map[types.PeerID]uint64, len(versions))
+	for _, v := range versions {
+		if len(v.Origin) > 0 {
+			result[types.PeerID(v.Origin)] = v.Counter
+		}
+	}
+	return result
+}
+
+// ============================================================================
+//                              replicator 副本同步
+// ============================================================================
+
+// replicator Point 之间的注册复制
+//
+// 本地注册变更先写入 Store，再按 GossipInterval 批量推送给该命名空间
+// 的副本；推送失败不重试，由 SyncInterval 周期的反熵同步补齐。
+//
+// 候选 Point 由静态配置与 DHT 发现的 Point 合并而成，但每个命名空间
+// 只在候选中与命名空间键最近的 ReplicationFactor 个 Point 之间复制，
+// 接收方按同样的规则检查发送方。注册到集合外 Point 的节点只由该
+// Point 本地提供服务。
+type replicator struct {
+	point     *Point
+	config    ReplicationConfig
+	discovery *PointDiscovery
+
+	mu      sync.Mutex
+	state   *replicaState
+	pending []*pb.Message_ReplicaEntry
+
+	// synced 已完成过同步的副本
+	synced map[types.PeerID]bool
+
+	ctx       context.Context
+	ctxCancel context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+// newReplicator 创建副本同步器
+func newReplicator(point *Point, config ReplicationConfig) *replicator {
+	defaults := DefaultReplicationConfig()
+	if config.GossipInterval <= 0 {
+		config.GossipInterval = defaults.GossipInterval
+	}
+	if config.SyncInterval <= 0 {
+		config.SyncInterval = defaults.SyncInterval
+	}
+	if config.MaxBatch <= 0 {
+		config.MaxBatch = defaults.MaxBatch
+	}
+	if config.MaxPending <= 0 {
+		config.MaxPending = defaults.MaxPending
+	}
+	if config.RequestTimeout <= 0 {
+		config.RequestTimeout = defaults.RequestTimeout
+	}
+	if config.ReplicationFactor <= 0 {
+		config.ReplicationFactor = defaults.ReplicationFactor
+	}
+
+	return &replicator{
+		point:  point,
+		config: config,
+		state:  newReplicaState(types.PeerID(point.host.ID())),
+		synced: make(map[types.PeerID]bool),
+	}
+}
+
+// start 启动后台同步
+func (r *replicator) start() {
+	r.ctx, r.ctxCancel = context.WithCancel(context.Background())
+
+	if r.discovery != nil {
+		if err := r.discovery.Start(r.ctx); err != nil {
+			logger.Warn("启动 Rendezvous Point 发现失败", "error", err)
+		}
+		r.wg.Add(1)
+		go r.announceLoop()
+	}
+
+	r.wg.Add(2)
+	go r.gossipLoop()
+	go r.syncLoop()
+}
+
+// stop 停止后台同步
+func (r *replicator) stop() {
+	if r.ctxCancel == nil {
+		return
+	}
+	r.ctxCancel()
+	r.wg.Wait()
+	if r.discovery != nil {
+		_ = r.discovery.Stop()
+	}
+}
+
+// ----------------------------------------------------------------------------
+// 本地变更
+// ----------------------------------------------------------------------------
+
+// put 写入本地注册并记录增量
+//
+// 注册必须携带客户端签名的节点记录，副本据此独立验证。
+func (r *replicator) put(reg *Registration) error {
+	if len(reg.SignedRecord) == 0 {
+		return fmt.Errorf("%w: missing signed peer record", ErrInvalidSignedPeerRecord)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cur := r.state.lookup(reg.Namespace, reg.PeerInfo.ID)
+	if cur != nil && cur.signedAt.After(recordTimestamp(reg.SignedRecord, false)) {
+		return fmt.Errorf("%w: record is older than current state", ErrInvalidSignedPeerRecord)
+	}
+	if err := r.point.store.Put(reg); err != nil {
+		return err
+	}
+	e := r.state.local(reg.Namespace, reg.PeerInfo.ID, reg.SignedRecord, reg.ExpiresAt, false)
+	r.enqueueLocked(e)
+	return nil
+}
+
+// remove 按已验证的取消注册记录移除本地注册并记录墓碑
+//
+// 墓碑携带客户端签名的取消注册记录，副本据此独立验证。
+func (r *replicator) remove(record *UnregisterRecord, signed []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cur := r.state.lookup(record.Namespace, record.PeerID)
+	if cur != nil && (cur.deleted || cur.signedAt.After(record.Timestamp)) {
+		return
+	}
+	r.point.store.Remove(record.Namespace, record.PeerID)
+
+	// 墓碑保留到原注册过期；未知注册按最大 TTL 保留
+	expiresAt := time.Now().Add(r.point.config.MaxTTL)
+	if cur != nil {
+		expiresAt = cur.expiresAt
+	}
+	e := r.state.local(record.Namespace, record.PeerID, signed, expiresAt, true)
+	r.enqueueLocked(e)
+}
+
+// enqueueLocked 加入待推送队列（调用方需持有锁）
+func (r *replicator) enqueueLocked(e *replicaEntry) {
+	r.pending = append(r.pending, e.toPB())
+	if over := len(r.pending) - r.config.MaxPending; over > 0 {
+		r.pending = append([]*pb.Message_ReplicaEntry(nil), r.pending[over:]...)
+	}
+}
+
+// expire 清理过期的复制状态
+func (r *replicator) expire() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.state.expire(time.Now())
+}
+
+// ----------------------------------------------------------------------------
+// 远端变更
+// ----------------------------------------------------------------------------
+
+// applyRemote 应用来自副本 from 的远端条目，返回成功应用的数量
+//
+// 签名记录在加锁前验证；无法验证的条目、以及 from 或本 Point 不在
+// 命名空间副本集合中的条目被丢弃。过期时间不超过 now+MaxTTL。
+func (r *replicator) applyRemote(from types.PeerID, entries []*pb.Message_ReplicaEntry) int {
+	type verified struct {
+		entry *replicaEntry
+		info  types.PeerInfo
+	}
+	batch := make([]verified, 0, len(entries))
+	now := time.Now()
+	maxExpiresAt := now.Add(r.point.config.MaxTTL)
+	responsible := make(map[string]bool)
+	for _, m := range entries {
+		e, err := replicaEntryFromPB(m)
+		if err != nil {
+			logger.Debug("丢弃无效复制条目", "error", err)
+			continue
+		}
+		ok, checked := responsible[e.namespace]
+		if !checked {
+			ok = r.isReplicaFor(e.namespace, from)
+			responsible[e.namespace] = ok
+		}
+		if !ok {
+			logger.Debug("丢弃非副本发送的复制条目", "namespace", e.namespace, "from", string(from))
+			continue
+		}
+		info, err := verifyReplicaRecord(e)
+		if err != nil {
+			logger.Debug("丢弃无法验证的复制条目", "namespace", e.namespace, "peer", string(e.peer), "error", err)
+			continue
+		}
+		if e.expiresAt.After(maxExpiresAt) {
+			e.expiresAt = maxExpiresAt
+		}
+		batch = append(batch, verified{entry: e, info: info})
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	applied := 0
+	for _, v := range batch {
+		if !r.state.apply(v.entry) {
+			continue
+		}
+		applied++
+		switch {
+		case v.entry.deleted, !now.Before(v.entry.expiresAt):
+			r.point.store.Remove(v.entry.namespace, v.entry.peer)
+		default:
+			if err := r.point.store.Put(v.entry.registration(v.info)); err != nil {
+				logger.Debug("复制注册写入失败", "namespace", v.entry.namespace, "peer", string(v.entry.peer), "error", err)
+			}
+		}
+	}
+	return applied
+}
+
+// ----------------------------------------------------------------------------
+// 副本集合
+// ----------------------------------------------------------------------------
+
+// replicas 返回当前副本 Point（静态配置 + DHT 发现，去重且不含自身）
+func (r *replicator) replicas() []types.PeerID {
+	var discovered []types.PeerID
+	if r.discovery != nil {
+		discovered = r.discovery.GetPoints()
+	}
+
+	merged := mergePoints(r.config.Peers, discovered)
+	result := merged[:0]
+	for _, p := range merged {
+		if p != "" && p != r.state.self {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// isReplica 检查节点是否为已知副本
+func (r *replicator) isReplica(peer types.PeerID) bool {
+	for _, p := range r.replicas() {
+		if p == peer {
+			return true
+		}
+	}
+	return false
+}
+
+// replicasFor 返回命名空间的副本集合（可能包含自身）
+//
+// 候选 Point 按 sha256(PeerID) 与 sha256(命名空间) 的 XOR 距离排序，
+// 取最近的 ReplicationFactor 个。所有 Point 对同一候选集合得出相同
+// 的副本集合。
+func (r *replicator) replicasFor(namespace string) []types.PeerID {
+	candidates := append(r.replicas(), r.state.self)
+	key := sha256.Sum256([]byte(namespace))
+	distances := make(map[types.PeerID][sha256.Size]byte, len(candidates))
+	for _, p := range candidates {
+		h := sha256.Sum256([]byte(p))
+		for i := range h {
+			h[i] ^= key[i]
+		}
+		distances[p] = h
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		di, dj := distances[candidates[i]], distances[candidates[j]]
+		return bytes.Compare(di[:], dj[:]) < 0
+	})
+	if len(candidates) > r.config.ReplicationFactor {
+		candidates = candidates[:r.config.ReplicationFactor]
+	}
+	return candidates
+}
+
+// isReplicaFor 检查 peer 与本 Point 是否都在命名空间的副本集合中
+func (r *replicator) isReplicaFor(namespace string, peer types.PeerID) bool {
+	replicas := r.replicasFor(namespace)
+	return containsPeer(replicas, peer) && containsPeer(replicas, r.state.self)
+}
+
+// containsPeer 检查列表中是否包含节点
+func containsPeer(peers []types.PeerID, peer types.PeerID) bool {
+	for _, p := range peers {
+		if p == peer {
+			return true
+		}
+	}
+	return false
+}
+
+// ----------------------------------------------------------------------------
+// 后台循环
+// ----------------------------------------------------------------------------
+
+// gossipLoop 增量推送循环
+func (r *replicator) gossipLoop() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.config.GossipInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.flush()
+		case <-r.ctx.Done():
+			return
+		}
+	}
+}
+
+// syncLoop 反熵同步循环
+func (r *replicator) syncLoop() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.config.SyncInterval)
+	defer ticker.Stop()
+
+	// 启动后立即同步一次，尽快补齐重启期间错过的注册
+	r.syncAll()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.syncAll()
+		case <-r.ctx.Done():
+			return
+		}
+	}
+}
+
+// announceLoop 通过 DHT 宣告本 Point
+func (r *replicator) announceLoop() {
+	defer r.wg.Done()
+
+	announce := func() {
+		ctx, cancel := context.WithTimeout(r.ctx, r.discovery.config.DiscoverTimeout)
+		defer cancel()
+		if err := r.discovery.AnnounceAsPoint(ctx); err != nil && r.ctx.Err() == nil {
+			logger.Debug("宣告 Rendezvous Point 失败", "error", err)
+		}
+	}
+
+	announce()
+
+	ticker := time.NewTicker(r.discovery.config.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			announce()
+		case <-r.ctx.Done():
+			return
+		}
+	}
+}
+
+// flush 推送待发送的增量
+func (r *replicator) flush() {
+	r.mu.Lock()
+	pending := r.pending
+	r.pending = nil
+	r.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	// 按命名空间的副本集合分发；本 Point 不在集合中的命名空间不推送
+	targets := make(map[types.PeerID][]*pb.Message_ReplicaEntry)
+	var order []types.PeerID
+	for _, m := range pending {
+		replicas := r.replicasFor(m.Ns)
+		if !containsPeer(replicas, r.state.self) {
+			continue
+		}
+		for _, peer := range replicas {
+			if peer == r.state.self {
+				continue
+			}
+			if _, ok := targets[peer]; !ok {
+				order = append(order, peer)
+			}
+			targets[peer] = append(targets[peer], m)
+		}
+	}
+
+	for _, peer := range order {
+		entries := targets[peer]
+		for start := 0; start < len(entries); start += r.config.MaxBatch {
+			end := start + r.config.MaxBatch
+			if end > len(entries) {
+				end = len(entries)
+			}
+			if err := r.push(peer, entries[start:end]); err != nil {
+				if r.ctx.Err() == nil {
+					logger.Debug("推送复制增量失败", "replica", string(peer), "error", err)
+				}
+				break
+			}
+		}
+	}
+}
+
+// push 向单个副本推送一批增量
+func (r *replicator) push(peer types.PeerID, entries []*pb.Message_ReplicaEntry) error {
+	ctx, cancel := context.WithTimeout(r.ctx, r.config.RequestTimeout)
+	defer cancel()
+
+	stream, err := r.openStream(ctx, peer)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	if err := WriteMessage(stream, NewReplicateRequest(entries)); err != nil {
+		return fmt.Errorf("write message failed: %w", err)
+	}
+	resp, err := ReadMessage(stream)
+	if err != nil {
+		return fmt.Errorf("read message failed: %w", err)
+	}
+	if resp.ReplicateResponse == nil {
+		return errors.New("missing replicate response")
+	}
+	return StatusToError(resp.ReplicateResponse.Status, resp.ReplicateResponse.StatusText)
+}
+
+// syncAll 与所有副本执行一次反熵同步
+func (r *replicator) syncAll() {
+	for _, peer := range r.replicas() {
+		if err := r.syncWith(peer); err != nil && r.ctx.Err() == nil {
+			logger.Debug("复制同步失败", "replica", string(peer), "error", err)
+		}
+	}
+}
+
+// syncWith 从单个副本拉取本地缺失的条目
+func (r *replicator) syncWith(peer types.PeerID) error {
+	ctx, cancel := context.WithTimeout(r.ctx, r.config.RequestTimeout)
+	defer cancel()
+
+	stream, err := r.openStream(ctx, peer)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	// 首次与该副本同步时不声明自身分量，以便取回重启前由本 Point
+	// 产生、仍在副本中存活的注册
+	r.mu.Lock()
+	vector := r.state.versions()
+	if !r.synced[peer] {
+		delete(vector, r.state.self)
+	}
+	r.mu.Unlock()
+	versions := versionsToPB(vector)
+
+	if err := WriteMessage(stream, NewSyncRequest(versions)); err != nil {
+		return fmt.Errorf("write message failed: %w", err)
+	}
+
+	applied := 0
+	for {
+		resp, err := ReadMessage(stream)
+		if err != nil {
+			return fmt.Errorf("read message failed: %w", err)
+		}
+		if resp.SyncResponse == nil {
+			return errors.New("missing sync response")
+		}
+		if err := StatusToError(resp.SyncResponse.Status, resp.SyncResponse.StatusText); err != nil {
+			return err
+		}
+
+		applied += r.applyRemote(peer, resp.SyncResponse.Entries)
+
+		if resp.SyncResponse.Done {
+			// 已应用对端快照中的全部条目，可以采纳其版本向量
+			r.mu.Lock()
+			r.state.merge(versionsFromPB(resp.SyncResponse.Versions))
+			r.synced[peer] = true
+			r.mu.Unlock()
+			break
+		}
+	}
+
+	if applied > 0 {
+		logger.Debug("复制同步完成", "replica", string(peer), "applied", applied)
+	}
+	return nil
+}
+
+// openStream 打开到副本的协议流
+func (r *replicator) openStream(ctx context.Context, peer types.PeerID) (pkgif.Stream, error) {
+	host := r.point.host
+	if err := host.Connect(ctx, string(peer), nil); err != nil {
+		return nil, fmt.Errorf("connect failed: %w", err)
+	}
+	stream, err := host.NewStream(ctx, string(peer), ProtocolID)
+	if err != nil {
+		return nil, fmt.Errorf("create stream failed: %w", err)
+	}
+	if stream == nil {
+		return nil, errors.New("stream is nil")
+	}
+	return stream, nil
+}
+
+// ----------------------------------------------------------------------------
+// 服务端处理
+// ----------------------------------------------------------------------------
+
+// handleReplicate 处理副本推送的增量
+func (r *replicator) handleReplicate(remote types.PeerID, req *pb.Message) *pb.Message {
+	if req.Replicate == nil {
+		return NewReplicateResponse(pb.Message_E_INTERNAL_ERROR, "missing replicate field")
+	}
+	if !r.isReplica(remote) {
+		return NewReplicateResponse(pb.Message_E_NOT_AUTHORIZED, ErrNotReplica.Error())
+	}
+	r.applyRemote(remote, req.Replicate.Entries)
+	return NewReplicateResponse(pb.Message_OK, "")
+}
+
+// handleSync 处理反熵同步请求
+//
+// 同步是只读的，与 DISCOVER 一样不要求对端是已知副本，
+// 新加入的 Point 因此可以在被其他副本发现之前先行拉取。
+func (r *replicator) handleSync(stream pkgif.Stream, req *pb.Message) {
+	if req.Sync == nil {
+		_ = WriteMessage(stream, NewSyncResponse(pb.Message_E_INTERNAL_ERROR, "missing sync field", nil, nil, true))
+		return
+	}
+
+	r.mu.Lock()
+	entries := r.state.since(versionsFromPB(req.Sync.Versions))
+	versions := versionsToPB(r.state.versions())
+	r.mu.Unlock()
+
+	for start := 0; ; start += r.config.MaxBatch {
+		end := start + r.config.MaxBatch
+		if end > len(entries) {
+			end = len(entries)
+		}
+		batch := make([]*pb.Message_ReplicaEntry, 0, end-start)
+		for _, e := range entries[start:end] {
+			batch = append(batch, e.toPB())
+		}
+
+		done := end == len(entries)
+		var vs []*pb.Message_Version
+		if done {
+			vs = versions
+		}
+		if err := WriteMessage(stream, NewSyncResponse(pb.Message_OK, "", batch, vs, done)); err != nil || done {
+			return
+		}
+	}
+}
@@ -0,0 +1,501 @@
+package rendezvous
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/crypto"
+	pb "github.com/dep2p/go-dep2p/pkg/lib/proto/rendezvous"
+	"github.com/dep2p/go-dep2p/pkg/types"
+	"github.com/dep2p/go-dep2p/tests/mocks"
+)
+
+// ============================================================================
+//                              replicaState
+// ============================================================================
+
+// TestReplicaState_LastWriterWins 测试并发写入按最后写入者胜出
+func TestReplicaState_LastWriterWins(t *testing.T) {
+	a := newReplicaState("point-a")
+	b := newReplicaState("point-b")
+
+	exp := time.Now().Add(time.Hour)
+	first := a.local("ns", "peer-1", []byte("peer-1"), exp, false)
+	second := b.local("ns", "peer-1", nil, exp, true)
+
+	assert.True(t, a.apply(second), "later tombstone must win")
+	assert.False(t, b.apply(first), "older registration must not resurrect peer")
+	assert.True(t, a.lookup("ns", "peer-1").deleted)
+	assert.True(t, b.lookup("ns", "peer-1").deleted)
+}
+
+// TestReplicaState_SyncConverges 测试基于版本向量的反熵对账
+func TestReplicaState_SyncConverges(t *testing.T) {
+	a := newReplicaState("point-a")
+	b := newReplicaState("point-b")
+	exp := time.Now().Add(time.Hour)
+
+	// 分区期间两侧各自产生变更
+	a.local("ns", "peer-1", []byte("peer-1"), exp, false)
+	a.local("ns", "peer-2", []byte("peer-2"), exp, false)
+	b.local("ns", "peer-3", []byte("peer-3"), exp, false)
+
+	sync := func(from, to *replicaState) int {
+		entries := from.since(to.versions())
+		for _, e := range entries {
+			to.apply(e)
+		}
+		to.merge(from.versions())
+		return len(entries)
+	}
+
+	assert.Equal(t, 2, sync(a, b))
+	assert.Equal(t, 1, sync(b, a))
+	assert.Len(t, a.entries, 3)
+	assert.Len(t, b.entries, 3)
+
+	// 已对账后再次同步没有增量
+	assert.Equal(t, 0, sync(a, b))
+	assert.Equal(t, 0, sync(b, a))
+
+	// 新变更只传输增量
+	a.local("ns", "peer-1", nil, exp, true)
+	assert.Equal(t, 1, sync(a, b))
+	assert.True(t, b.lookup("ns", "peer-1").deleted)
+}
+
+// TestReplicaState_GapDoesNotAdvanceVector 测试推送乱序时不推进版本向量
+func TestReplicaState_GapDoesNotAdvanceVector(t *testing.T) {
+	a := newReplicaState("point-a")
+	b := newReplicaState("point-b")
+	b.merge(a.versions())
+	exp := time.Now().Add(time.Hour)
+
+	lost := a.local("ns", "peer-1", []byte("peer-1"), exp, false)
+	got := a.local("ns", "peer-2", []byte("peer-2"), exp, false)
+
+	b.apply(got)
+	assert.Less(t, b.versions()["point-a"], got.counter, "gap must keep vector behind")
+
+	entries := a.since(b.versions())
+	require.Len(t, entries, 2)
+	assert.Equal(t, lost.counter, entries[0].counter)
+}
+
+// TestReplicaState_Expire 测试过期清理
+func TestReplicaState_Expire(t *testing.T) {
+	s := newReplicaState("point-a")
+	s.local("ns", "peer-1", []byte("peer-1"), time.Now().Add(-time.Second), false)
+	s.local("ns", "peer-2", nil, time.Now().Add(time.Hour), true)
+
+	assert.Equal(t, 1, s.expire(time.Now()))
+	assert.Nil(t, s.lookup("ns", "peer-1"))
+	assert.NotNil(t, s.lookup("ns", "peer-2"))
+}
+
+// TestVerifyReplicaRecord 测试复制条目的签名记录验证
+func TestVerifyReplicaRecord(t *testing.T) {
+	priv, pub, err := crypto.GenerateKeyPair(crypto.KeyTypeEd25519)
+	require.NoError(t, err)
+	id, err := crypto.PeerIDFromPublicKey(pub)
+	require.NoError(t, err)
+
+	signed, err := SignPeerRecord(priv, &PeerRecord{PeerID: id, Seq: 1, Timestamp: time.Now()})
+	require.NoError(t, err)
+	record, err := signed.Marshal()
+	require.NoError(t, err)
+
+	e := &replicaEntry{namespace: "ns", peer: id, record: record}
+	info, err := verifyReplicaRecord(e)
+	require.NoError(t, err)
+	assert.Equal(t, id, info.ID)
+
+	// 记录属于其他节点
+	e.peer = "someone-else"
+	_, err = verifyReplicaRecord(e)
+	assert.ErrorIs(t, err, ErrInvalidReplicaEntry)
+
+	// 签名记录声明的 PeerID 与公钥不匹配
+	forged, err := SignPeerRecord(priv, &PeerRecord{PeerID: "victim", Seq: 1, Timestamp: time.Now()})
+	require.NoError(t, err)
+	record, err = forged.Marshal()
+	require.NoError(t, err)
+	_, err = verifyReplicaRecord(&replicaEntry{namespace: "ns", peer: "victim", record: record})
+	assert.ErrorIs(t, err, ErrInvalidSignedPeerRecord)
+}
+
+// TestVerifyReplicaRecord_Tombstone 测试墓碑必须携带客户端签名的取消注册记录
+func TestVerifyReplicaRecord_Tombstone(t *testing.T) {
+	peer := newTestPeer(t)
+
+	// 合法墓碑
+	e := &replicaEntry{namespace: "ns", peer: peer.id, record: peer.unregisterRecord(t, "ns"), deleted: true}
+	_, err := verifyReplicaRecord(e)
+	require.NoError(t, err)
+	assert.False(t, e.signedAt.IsZero())
+
+	// 未签名的墓碑
+	_, err = verifyReplicaRecord(&replicaEntry{namespace: "ns", peer: peer.id, record: []byte(peer.id), deleted: true})
+	assert.ErrorIs(t, err, ErrInvalidReplicaEntry)
+
+	// 节点记录不能充当墓碑
+	_, err = verifyReplicaRecord(&replicaEntry{namespace: "ns", peer: peer.id, record: peer.record(t, 1), deleted: true})
+	assert.ErrorIs(t, err, ErrInvalidReplicaEntry)
+
+	// 其他命名空间的取消注册记录
+	_, err = verifyReplicaRecord(&replicaEntry{namespace: "ns", peer: peer.id, record: peer.unregisterRecord(t, "other"), deleted: true})
+	assert.ErrorIs(t, err, ErrInvalidReplicaEntry)
+
+	// 缺少签名记录的条目在解析时即被拒绝
+	_, err = replicaEntryFromPB(&pb.Message_ReplicaEntry{Ns: "ns", Peer: []byte(peer.id), Deleted: true, Origin: []byte("point-b"), Counter: 1})
+	assert.ErrorIs(t, err, ErrInvalidReplicaEntry)
+}
+
+// ============================================================================
+//                              端到端复制
+// ============================================================================
+
+// pipeStream 基于 net.Pipe 的流
+type pipeStream struct {
+	pipe     net.Conn
+	conn     pkgif.Connection
+	protocol string
+}
+
+func (s *pipeStream) Read(p []byte) (int, error)         { return s.pipe.Read(p) }
+func (s *pipeStream) Write(p []byte) (int, error)        { return s.pipe.Write(p) }
+func (s *pipeStream) Close() error                       { return s.pipe.Close() }
+func (s *pipeStream) SetDeadline(t time.Time) error      { return s.pipe.SetDeadline(t) }
+func (s *pipeStream) SetReadDeadline(t time.Time) error  { return s.pipe.SetReadDeadline(t) }
+func (s *pipeStream) SetWriteDeadline(t time.Time) error { return s.pipe.SetWriteDeadline(t) }
+func (s *pipeStream) CloseWrite() error                  { return nil }
+func (s *pipeStream) CloseRead() error                   { return nil }
+func (s *pipeStream) Reset() error                       { return s.pipe.Close() }
+func (s *pipeStream) Protocol() string                   { return s.protocol }
+func (s *pipeStream) SetProtocol(p string)               { s.protocol = p }
+func (s *pipeStream) Conn() pkgif.Connection             { return s.conn }
+func (s *pipeStream) IsClosed() bool                     { return false }
+func (s *pipeStream) Stat() types.StreamStat             { return types.StreamStat{} }
+func (s *pipeStream) State() types.StreamState           { return types.StreamState(0) }
+
+// pipeNet 测试网络：节点之间通过 net.Pipe 打开流，可模拟节点宕机
+type pipeNet struct {
+	mu       sync.Mutex
+	handlers map[string]pkgif.StreamHandler
+	down     map[string]bool
+}
+
+func newPipeNet() *pipeNet {
+	return &pipeNet{
+		handlers: make(map[string]pkgif.StreamHandler),
+		down:     make(map[string]bool),
+	}
+}
+
+func (n *pipeNet) setDown(id string, down bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.down[id] = down
+}
+
+func (n *pipeNet) host(id string) *mocks.MockHost {
+	host := mocks.NewMockHost(id)
+	host.SetStreamHandlerFunc = func(_ string, handler pkgif.StreamHandler) {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		n.handlers[id] = handler
+	}
+	host.RemoveStreamHandlerFunc = func(string) {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		delete(n.handlers, id)
+	}
+	host.NewStreamFunc = func(_ context.Context, peerID string, protocolIDs ...string) (pkgif.Stream, error) {
+		n.mu.Lock()
+		handler := n.handlers[peerID]
+		unreachable := n.down[peerID] || n.down[id]
+		n.mu.Unlock()
+		if handler == nil || unreachable {
+			return nil, assert.AnError
+		}
+
+		local, remote := net.Pipe()
+		go handler(&pipeStream{pipe: remote, conn: mocks.NewMockConnection(types.PeerID(peerID), types.PeerID(id)), protocol: protocolIDs[0]})
+		return &pipeStream{pipe: local, conn: mocks.NewMockConnection(types.PeerID(id), types.PeerID(peerID)), protocol: protocolIDs[0]}, nil
+	}
+	return host
+}
+
+// startReplicatedPoint 启动参与复制的 Point
+func startReplicatedPoint(t *testing.T, n *pipeNet, id string, peers ...types.PeerID) *Point {
+	t.Helper()
+
+	config := DefaultPointConfig()
+	config.Replication.Enabled = true
+	config.Replication.Peers = peers
+	config.Replication.GossipInterval = 20 * time.Millisecond
+	config.Replication.SyncInterval = 50 * time.Millisecond
+
+	point := NewPoint(n.host(id), config)
+	require.NoError(t, point.Start(context.Background()))
+	t.Cleanup(func() { _ = point.Stop() })
+	return point
+}
+
+// testPeer 测试节点：ID 由密钥派生，可签名注册记录
+type testPeer struct {
+	id   types.PeerID
+	priv crypto.PrivateKey
+}
+
+// newTestPeer 生成测试节点
+func newTestPeer(t *testing.T) testPeer {
+	t.Helper()
+
+	priv, pub, err := crypto.GenerateKeyPair(crypto.KeyTypeEd25519)
+	require.NoError(t, err)
+	id, err := crypto.PeerIDFromPublicKey(pub)
+	require.NoError(t, err)
+	return testPeer{id: id, priv: priv}
+}
+
+// record 签名序号为 seq 的节点记录
+func (p testPeer) record(t *testing.T, seq uint64) []byte {
+	t.Helper()
+
+	signed, err := SignPeerRecord(p.priv, &PeerRecord{PeerID: p.id, Seq: seq, Timestamp: time.Now()})
+	require.NoError(t, err)
+	record, err := signed.Marshal()
+	require.NoError(t, err)
+	return record
+}
+
+// unregisterRecord 签名取消注册记录
+func (p testPeer) unregisterRecord(t *testing.T, ns string) []byte {
+	t.Helper()
+
+	record, err := SignUnregisterRecord(p.priv, &UnregisterRecord{PeerID: p.id, Namespace: ns, Timestamp: time.Now()})
+	require.NoError(t, err)
+	return record
+}
+
+// register 将签名注册直接写入存储
+func (p testPeer) register(t *testing.T, store *Store, ns string) {
+	t.Helper()
+
+	now := time.Now()
+	require.NoError(t, store.Put(&Registration{
+		Namespace:    ns,
+		PeerInfo:     types.PeerInfo{ID: p.id, Seq: 1},
+		TTL:          time.Hour,
+		RegisteredAt: now,
+		ExpiresAt:    now.Add(time.Hour),
+		SignedRecord: p.record(t, 1),
+	}))
+}
+
+// startClient 启动持有签名私钥的 Discoverer
+func startClient(t *testing.T, n *pipeNet, points ...types.PeerID) *Discoverer {
+	t.Helper()
+
+	peer := newTestPeer(t)
+	config := DefaultDiscovererConfig()
+	config.Points = points
+	d := NewDiscoverer(n.host(string(peer.id)), config)
+	require.NoError(t, d.SetPrivateKey(peer.priv))
+	require.NoError(t, d.Start(context.Background()))
+	t.Cleanup(func() { _ = d.Stop(context.Background()) })
+	return d
+}
+
+// hasPeer 检查 Point 存储中是否有指定注册
+func hasPeer(p *Point, ns string, peer types.PeerID) bool {
+	regs, _, _ := p.store.Get(ns, 0, nil)
+	for _, reg := range regs {
+		if reg.PeerInfo.ID == peer {
+			return true
+		}
+	}
+	return false
+}
+
+// TestReplication_GossipAndFailover 测试增量推送与客户端故障切换
+func TestReplication_GossipAndFailover(t *testing.T) {
+	n := newPipeNet()
+	a := startReplicatedPoint(t, n, "point-a", "point-b")
+	b := startReplicatedPoint(t, n, "point-b", "point-a")
+	ctx := context.Background()
+
+	alice := startClient(t, n, "point-a", "point-b")
+	require.NoError(t, alice.Register(ctx, "chat", time.Hour))
+
+	require.Eventually(t, func() bool {
+		return hasPeer(a, "chat", alice.localID) && hasPeer(b, "chat", alice.localID)
+	}, 2*time.Second, 10*time.Millisecond, "registration must replicate to both points")
+
+	// point-a 宕机后客户端切换到副本，数据不丢失
+	n.setDown("point-a", true)
+	bob := startClient(t, n, "point-a", "point-b")
+	peers, err := bob.Discover(ctx, "chat", 10)
+	require.NoError(t, err)
+	require.Len(t, peers, 1)
+	assert.Equal(t, alice.localID, peers[0].ID)
+
+	// 在副本上取消注册，恢复后同步到 point-a
+	require.NoError(t, alice.Unregister("chat"))
+	n.setDown("point-a", false)
+	require.Eventually(t, func() bool {
+		return !hasPeer(a, "chat", alice.localID)
+	}, 2*time.Second, 10*time.Millisecond, "unregister must replicate back to point-a")
+}
+
+// TestReplication_PartitionReconcile 测试分区恢复后的反熵对账
+func TestReplication_PartitionReconcile(t *testing.T) {
+	n := newPipeNet()
+	a := startReplicatedPoint(t, n, "point-a", "point-b")
+	b := startReplicatedPoint(t, n, "point-b", "point-a")
+	ctx := context.Background()
+
+	// 分区期间两侧各自接受注册；增量推送失败后被丢弃
+	n.setDown("point-b", true)
+	alice := startClient(t, n, "point-a")
+	require.NoError(t, alice.Register(ctx, "chat", time.Hour))
+	time.Sleep(100 * time.Millisecond)
+	n.setDown("point-b", false)
+	n.setDown("point-a", true)
+	bob := startClient(t, n, "point-b")
+	require.NoError(t, bob.Register(ctx, "chat", time.Hour))
+	time.Sleep(100 * time.Millisecond)
+	n.setDown("point-a", false)
+
+	require.Eventually(t, func() bool {
+		return hasPeer(a, "chat", bob.localID) && hasPeer(b, "chat", alice.localID)
+	}, 2*time.Second, 10*time.Millisecond, "points must reconcile after partition heals")
+}
+
+// TestReplication_RejectsUnknownReplica 测试拒绝非副本节点推送
+func TestReplication_RejectsUnknownReplica(t *testing.T) {
+	n := newPipeNet()
+	a := startReplicatedPoint(t, n, "point-a")
+
+	intruder := newReplicator(NewPoint(n.host("intruder"), DefaultPointConfig()), DefaultReplicationConfig())
+	intruder.ctx = context.Background()
+	e := intruder.state.local("chat", "mallory", []byte("mallory"), time.Now().Add(time.Hour), false)
+
+	err := intruder.push("point-a", toPBEntries(e))
+	assert.ErrorIs(t, err, ErrNotAuthorized)
+	assert.False(t, hasPeer(a, "chat", "mallory"))
+	assert.Empty(t, a.Replicas())
+}
+
+// TestReplication_ClampsRemoteExpiry 测试远端条目的过期时间不超过 now+MaxTTL
+func TestReplication_ClampsRemoteExpiry(t *testing.T) {
+	n := newPipeNet()
+	config := DefaultPointConfig()
+	config.Replication.Enabled = true
+	config.Replication.Peers = []types.PeerID{"point-b"}
+	point := NewPoint(n.host("point-a"), config)
+
+	peer := newTestPeer(t)
+	remote := newReplicaState("point-b")
+	e := remote.local("chat", peer.id, peer.record(t, 1), time.Now().Add(100*365*24*time.Hour), false)
+
+	require.Equal(t, 1, point.replicator.applyRemote("point-b", toPBEntries(e)))
+	regs, _, err := point.store.Get("chat", 0, nil)
+	require.NoError(t, err)
+	require.Len(t, regs, 1)
+	assert.WithinDuration(t, time.Now().Add(config.MaxTTL), regs[0].ExpiresAt, time.Minute)
+	assert.WithinDuration(t, time.Now().Add(config.MaxTTL), point.replicator.state.lookup("chat", peer.id).expiresAt, time.Minute)
+}
+
+// TestReplication_RejectsSenderOutsideNamespaceReplicas 测试只接受命名空间副本集合内 Point 的条目
+func TestReplication_RejectsSenderOutsideNamespaceReplicas(t *testing.T) {
+	n := newPipeNet()
+	config := DefaultPointConfig()
+	config.Replication.Enabled = true
+	config.Replication.Peers = []types.PeerID{"point-b", "point-c", "point-d", "point-e"}
+	config.Replication.ReplicationFactor = 2
+	point := NewPoint(n.host("point-a"), config)
+	r := point.replicator
+
+	// 找到本 Point 负责的命名空间，以及集合内外的发送方
+	var ns string
+	var insider, outsider types.PeerID
+	for i := 0; ns == "" && i < 1000; i++ {
+		candidate := "chat-" + strconv.Itoa(i)
+		replicas := r.replicasFor(candidate)
+		require.Len(t, replicas, 2)
+		if !containsPeer(replicas, "point-a") {
+			continue
+		}
+		ns = candidate
+		for _, p := range config.Replication.Peers {
+			if containsPeer(replicas, p) {
+				insider = p
+			} else {
+				outsider = p
+			}
+		}
+	}
+	require.NotEmpty(t, ns)
+
+	peer := newTestPeer(t)
+	remote := newReplicaState(outsider)
+	e := remote.local(ns, peer.id, peer.record(t, 1), time.Now().Add(time.Hour), false)
+	assert.Equal(t, 0, r.applyRemote(outsider, toPBEntries(e)), "sender outside the namespace replicas must be rejected")
+	assert.False(t, hasPeer(point, ns, peer.id))
+
+	assert.Equal(t, 1, r.applyRemote(insider, toPBEntries(e)))
+	assert.True(t, hasPeer(point, ns, peer.id))
+}
+
+// TestPoint_RequiresSignedRecords 测试 Point 拒绝未签名的注册与取消注册
+func TestPoint_RequiresSignedRecords(t *testing.T) {
+	n := newPipeNet()
+	config := DefaultPointConfig()
+	config.Replication.Enabled = true
+	point := NewPoint(n.host("point-a"), config)
+	peer := newTestPeer(t)
+
+	resp := point.handleRegister(NewRegisterRequest("chat", []byte(peer.id), time.Hour))
+	assert.Equal(t, pb.Message_E_INVALID_SIGNED_PEER_RECORD, resp.RegisterResponse.Status)
+	assert.False(t, hasPeer(point, "chat", peer.id))
+
+	oldUnregister := peer.unregisterRecord(t, "chat")
+	resp = point.handleRegister(NewRegisterRequest("chat", peer.record(t, 1), time.Hour))
+	require.Equal(t, pb.Message_OK, resp.RegisterResponse.Status)
+
+	// 未签名或签名不匹配的取消注册
+	resp = point.handleUnregister(NewUnregisterRequest("chat", []byte(peer.id), nil))
+	assert.Equal(t, pb.Message_E_INVALID_SIGNED_PEER_RECORD, resp.RegisterResponse.Status)
+	resp = point.handleUnregister(NewUnregisterRequest("chat", []byte(peer.id), newTestPeer(t).unregisterRecord(t, "chat")))
+	assert.Equal(t, pb.Message_E_INVALID_SIGNED_PEER_RECORD, resp.RegisterResponse.Status)
+	assert.True(t, hasPeer(point, "chat", peer.id))
+
+	// 早于当前注册的取消注册被忽略
+	resp = point.handleUnregister(NewUnregisterRequest("chat", []byte(peer.id), oldUnregister))
+	require.Equal(t, pb.Message_OK, resp.RegisterResponse.Status)
+	assert.True(t, hasPeer(point, "chat", peer.id), "replayed unregister must not remove a newer registration")
+
+	resp = point.handleUnregister(NewUnregisterRequest("chat", []byte(peer.id), peer.unregisterRecord(t, "chat")))
+	require.Equal(t, pb.Message_OK, resp.RegisterResponse.Status)
+	assert.False(t, hasPeer(point, "chat", peer.id))
+	assert.True(t, point.replicator.state.lookup("chat", peer.id).deleted)
+}
+
+// toPBEntries 转换条目列表
+func toPBEntries(entries ...*replicaEntry) []*pb.Message_ReplicaEntry {
+	result := make([]*pb.Message_ReplicaEntry, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, e.toPB())
+	}
+	return result
+}
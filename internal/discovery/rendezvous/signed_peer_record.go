@@ -416,5 +416,6 @@ func ExtractPeerInfoFromSignedRecord(data []byte) (types.PeerInfo, error) {
 	return types.PeerInfo{
 		ID:    signed.PeerRecord.PeerID,
 		Addrs: signed.PeerRecord.Addrs,
+		Seq:   signed.PeerRecord.Seq,
 	}, nil
 }
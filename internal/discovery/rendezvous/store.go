@@ -64,6 +64,9 @@ type Store struct {
 
 	mu sync.RWMutex
 
+	// notify 注册变更通知（在持有写锁时调用，不得阻塞）
+	notify func(WatchEventType, Registration)
+
 	// 统计
 	totalRegistrations   int
 	registrationsExpired uint64
@@ -78,15 +81,28 @@ func NewStore(config StoreConfig) *Store {
 	}
 }
 
+// SetNotifier 设置注册变更通知
+//
+// 回调在持有存储锁时同步调用，实现必须快速返回且不得回调 Store。
+func (s *Store) SetNotifier(fn func(WatchEventType, Registration)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.notify = fn
+}
+
+// emit 发送变更通知（调用方需持有写锁）
+func (s *Store) emit(typ WatchEventType, reg *Registration) {
+	if s.notify != nil {
+		s.notify(typ, *reg)
+	}
+}
+
 // ============================================================================
 //                              注册操作
 // ============================================================================
 
 // Add 添加或更新注册
 func (s *Store) Add(namespace string, peerInfo types.PeerInfo, ttl time.Duration) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
 	// 验证 TTL
 	if ttl <= 0 {
 		ttl = s.config.DefaultTTL
@@ -95,6 +111,27 @@ func (s *Store) Add(namespace string, peerInfo types.PeerInfo, ttl time.Duration
 		ttl = s.config.MaxTTL
 	}
 
+	now := time.Now()
+	return s.Put(&Registration{
+		Namespace:    namespace,
+		PeerInfo:     peerInfo,
+		TTL:          ttl,
+		RegisteredAt: now,
+		ExpiresAt:    now.Add(ttl),
+	})
+}
+
+// Put 写入完整的注册记录
+//
+// 与 Add 不同，Put 保留调用方给出的过期时间与签名记录，
+// 用于 Point 之间复制注册时保持各副本的过期时间一致。
+func (s *Store) Put(reg *Registration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	namespace := reg.Namespace
+	peerID := reg.PeerInfo.ID
+
 	// 检查命名空间数量限制
 	if _, exists := s.registrations[namespace]; !exists {
 		if len(s.registrations) >= s.config.MaxNamespaces {
@@ -106,7 +143,7 @@ func (s *Store) Add(namespace string, peerInfo types.PeerInfo, ttl time.Duration
 	nsRegs := s.registrations[namespace]
 
 	// 检查是否是更新
-	_, isUpdate := nsRegs[peerInfo.ID]
+	_, isUpdate := nsRegs[peerID]
 
 	// 检查命名空间内注册数限制
 	if !isUpdate && len(nsRegs) >= s.config.MaxRegistrationsPerNamespace {
@@ -120,35 +157,29 @@ func (s *Store) Add(namespace string, peerInfo types.PeerInfo, ttl time.Duration
 
 	// 检查单个节点注册数限制
 	if !isUpdate {
-		if namespaces, exists := s.peerNamespaces[peerInfo.ID]; exists {
+		if namespaces, exists := s.peerNamespaces[peerID]; exists {
 			if len(namespaces) >= s.config.MaxRegistrationsPerPeer {
 				return ErrMaxRegistrationsPerPeerExceeded
 			}
 		}
 	}
 
-	now := time.Now()
-
 	// 存储注册
-	nsRegs[peerInfo.ID] = &Registration{
-		Namespace:    namespace,
-		PeerInfo:     peerInfo,
-		TTL:          ttl,
-		RegisteredAt: now,
-		ExpiresAt:    now.Add(ttl),
-	}
+	nsRegs[peerID] = reg
 
 	// 更新 peer -> namespaces 索引
-	if _, exists := s.peerNamespaces[peerInfo.ID]; !exists {
-		s.peerNamespaces[peerInfo.ID] = make(map[string]struct{})
+	if _, exists := s.peerNamespaces[peerID]; !exists {
+		s.peerNamespaces[peerID] = make(map[string]struct{})
 	}
-	s.peerNamespaces[peerInfo.ID][namespace] = struct{}{}
+	s.peerNamespaces[peerID][namespace] = struct{}{}
 
 	// 更新统计
 	if !isUpdate {
 		s.totalRegistrations++
 	}
 
+	s.emit(WatchAdded, reg)
+
 	return nil
 }
 
@@ -162,12 +193,14 @@ func (s *Store) Remove(namespace string, peerID types.PeerID) {
 		return
 	}
 
-	if _, exists := nsRegs[peerID]; !exists {
+	reg, exists := nsRegs[peerID]
+	if !exists {
 		return
 	}
 
 	delete(nsRegs, peerID)
 	s.totalRegistrations--
+	s.emit(WatchRemoved, reg)
 
 	// 清理空的命名空间
 	if len(nsRegs) == 0 {
@@ -238,6 +271,22 @@ func (s *Store) Get(namespace string, limit int, cookie []byte) ([]*Registration
 	return results, nextCookie, nil
 }
 
+// view 在持有读锁时访问命名空间的有效注册
+//
+// 用于与变更通知保持一致的快照：fn 执行期间不会有新的通知产生。
+func (s *Store) view(namespace string, fn func(regs []*Registration)) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var regs []*Registration
+	for _, reg := range s.registrations[namespace] {
+		if !reg.IsExpired() {
+			regs = append(regs, reg)
+		}
+	}
+	fn(regs)
+}
+
 // GetPeerNamespaces 获取节点注册的所有命名空间
 func (s *Store) GetPeerNamespaces(peerID types.PeerID) []string {
 	s.mu.RLock()
@@ -273,6 +322,7 @@ func (s *Store) CleanupExpired() int {
 				delete(nsRegs, peerID)
 				s.totalRegistrations--
 				expired++
+				s.emit(WatchExpired, reg)
 
 				// 更新 peer -> namespaces 索引
 				if namespaces, exists := s.peerNamespaces[peerID]; exists {
@@ -0,0 +1,147 @@
+package rendezvous
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dep2p/go-dep2p/pkg/lib/crypto"
+	"github.com/dep2p/go-dep2p/pkg/types"
+)
+
+// UnregisterRecordPayloadType 取消注册记录的签名域，与节点记录区分，
+// 使节点记录的签名无法被当作取消注册使用
+var UnregisterRecordPayloadType = []byte("/dep2p/rendezvous/unregister-record")
+
+// UnregisterRecord 取消注册记录
+//
+// 由客户端签名，Point 据此确认取消注册确实来自该节点；复制时作为墓碑
+// 的凭证转发，副本无需信任转发它的 Point。
+type UnregisterRecord struct {
+	// PeerID 取消注册的节点
+	PeerID types.PeerID
+
+	// Namespace 命名空间
+	Namespace string
+
+	// Timestamp 记录创建时间，早于当前注册的取消注册记录不生效
+	Timestamp time.Time
+}
+
+// Marshal 序列化取消注册记录
+//
+// 格式: [peerID_len(2) | peerID | ns_len(2) | ns | timestamp(8)]
+func (r *UnregisterRecord) Marshal() []byte {
+	buf := make([]byte, 0, 2+len(r.PeerID)+2+len(r.Namespace)+8)
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(r.PeerID)))
+	buf = append(buf, r.PeerID...)
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(r.Namespace)))
+	buf = append(buf, r.Namespace...)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(r.Timestamp.UnixNano()))
+	return buf
+}
+
+// UnmarshalUnregisterRecord 反序列化取消注册记录
+func UnmarshalUnregisterRecord(data []byte) (*UnregisterRecord, error) {
+	peerID, rest, err := readChunk(data)
+	if err != nil {
+		return nil, fmt.Errorf("peer id: %w", err)
+	}
+	ns, rest, err := readChunk(rest)
+	if err != nil {
+		return nil, fmt.Errorf("namespace: %w", err)
+	}
+	if len(rest) != 8 {
+		return nil, errors.New("invalid timestamp")
+	}
+	return &UnregisterRecord{
+		PeerID:    types.PeerID(peerID),
+		Namespace: string(ns),
+		Timestamp: time.Unix(0, int64(binary.BigEndian.Uint64(rest))),
+	}, nil
+}
+
+// SignUnregisterRecord 签名取消注册记录，返回序列化的签名信封
+//
+// 信封格式与 SignedPeerRecord 相同，签名覆盖 UnregisterRecordPayloadType 与记录内容。
+func SignUnregisterRecord(privKey crypto.PrivateKey, record *UnregisterRecord) ([]byte, error) {
+	if privKey == nil {
+		return nil, errors.New("nil private key")
+	}
+	raw := record.Marshal()
+	sig, err := privKey.Sign(unregisterSigningBytes(raw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign: %w", err)
+	}
+	pub, err := privKey.GetPublic().Raw()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal public key: %w", err)
+	}
+
+	buf := make([]byte, 0, 1+2+len(pub)+2+len(raw)+2+len(sig))
+	buf = append(buf, byte(privKey.Type()))
+	for _, chunk := range [][]byte{pub, raw, sig} {
+		buf = binary.BigEndian.AppendUint16(buf, uint16(len(chunk)))
+		buf = append(buf, chunk...)
+	}
+	return buf, nil
+}
+
+// VerifyUnregisterRecord 验证签名信封并返回取消注册记录
+//
+// 签名必须有效，且签名公钥必须与记录中的节点 ID 对应。
+func VerifyUnregisterRecord(data []byte) (*UnregisterRecord, error) {
+	if len(data) < 1 {
+		return nil, fmt.Errorf("%w: empty unregister record", ErrInvalidSignedPeerRecord)
+	}
+	keyType := crypto.KeyType(data[0])
+	pubBytes, rest, err := readChunk(data[1:])
+	if err != nil {
+		return nil, fmt.Errorf("%w: public key: %v", ErrInvalidSignedPeerRecord, err)
+	}
+	raw, rest, err := readChunk(rest)
+	if err != nil {
+		return nil, fmt.Errorf("%w: record: %v", ErrInvalidSignedPeerRecord, err)
+	}
+	sig, rest, err := readChunk(rest)
+	if err != nil || len(rest) != 0 {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidSignedPeerRecord)
+	}
+
+	pub, err := crypto.UnmarshalPublicKey(keyType, pubBytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignedPeerRecord, err)
+	}
+	if ok, err := pub.Verify(unregisterSigningBytes(raw), sig); err != nil || !ok {
+		return nil, fmt.Errorf("%w: invalid signature", ErrInvalidSignedPeerRecord)
+	}
+
+	record, err := UnmarshalUnregisterRecord(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignedPeerRecord, err)
+	}
+	if ok, err := crypto.VerifyPeerID(pub, record.PeerID); err != nil || !ok {
+		return nil, fmt.Errorf("%w: public key does not match peer ID", ErrInvalidSignedPeerRecord)
+	}
+	return record, nil
+}
+
+// unregisterSigningBytes 返回签名覆盖的数据（签名域 + 记录）
+func unregisterSigningBytes(raw []byte) []byte {
+	buf := make([]byte, 0, len(UnregisterRecordPayloadType)+len(raw))
+	buf = append(buf, UnregisterRecordPayloadType...)
+	return append(buf, raw...)
+}
+
+// readChunk 读取 [len(2) | data] 格式的字段
+func readChunk(data []byte) (chunk, rest []byte, err error) {
+	if len(data) < 2 {
+		return nil, nil, errors.New("data too short")
+	}
+	n := int(binary.BigEndian.Uint16(data))
+	if len(data) < 2+n {
+		return nil, nil, errors.New("invalid length")
+	}
+	return data[2 : 2+n], data[2+n:], nil
+}
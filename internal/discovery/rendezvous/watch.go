@@ -0,0 +1,616 @@
+package rendezvous
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	pb "github.com/dep2p/go-dep2p/pkg/lib/proto/rendezvous"
+	"github.com/dep2p/go-dep2p/pkg/types"
+)
+
+// ============================================================================
+//                              订阅事件
+// ============================================================================
+
+// WatchEventType 命名空间变更类型
+type WatchEventType int
+
+const (
+	// WatchAdded 新增注册或续约
+	WatchAdded WatchEventType = iota
+	// WatchExpired 注册过期
+	WatchExpired
+	// WatchRemoved 节点主动取消注册
+	WatchRemoved
+)
+
+// String 返回事件类型名称
+func (t WatchEventType) String() string {
+	switch t {
+	case WatchAdded:
+		return "added"
+	case WatchExpired:
+		return "expired"
+	case WatchRemoved:
+		return "removed"
+	default:
+		return "unknown"
+	}
+}
+
+// WatchEvent 命名空间变更事件（客户端视图）
+type WatchEvent struct {
+	// Type 变更类型
+	Type WatchEventType
+
+	// Namespace 命名空间
+	Namespace string
+
+	// Peer 节点信息
+	Peer types.PeerInfo
+
+	// TTL 剩余有效期（仅 WatchAdded 有效）
+	TTL time.Duration
+}
+
+// watchEventToPB 转换事件类型
+func watchEventToPB(t WatchEventType) pb.Message_DiscoverEvent_EventType {
+	switch t {
+	case WatchExpired:
+		return pb.Message_DiscoverEvent_EXPIRED
+	case WatchRemoved:
+		return pb.Message_DiscoverEvent_REMOVED
+	default:
+		return pb.Message_DiscoverEvent_ADDED
+	}
+}
+
+// watchEventFromPB 转换事件类型
+func watchEventFromPB(t pb.Message_DiscoverEvent_EventType) WatchEventType {
+	switch t {
+	case pb.Message_DiscoverEvent_EXPIRED:
+		return WatchExpired
+	case pb.Message_DiscoverEvent_REMOVED:
+		return WatchRemoved
+	default:
+		return WatchAdded
+	}
+}
+
+// ============================================================================
+//                              续传 Cookie
+// ============================================================================
+
+// watchCookieSize 订阅 cookie 长度: [epoch(8)][seq(8)]
+//
+// epoch 在 Point 每次创建时随机生成，Point 重启后旧 cookie 自然失效，
+// 客户端会收到完整快照而不是错误的增量。
+const watchCookieSize = 16
+
+// encodeWatchCookie 编码订阅 cookie
+func encodeWatchCookie(epoch, seq uint64) []byte {
+	cookie := make([]byte, watchCookieSize)
+	binary.BigEndian.PutUint64(cookie[:8], epoch)
+	binary.BigEndian.PutUint64(cookie[8:], seq)
+	return cookie
+}
+
+// decodeWatchCookie 解码订阅 cookie
+func decodeWatchCookie(cookie []byte) (epoch, seq uint64, ok bool) {
+	if len(cookie) != watchCookieSize {
+		return 0, 0, false
+	}
+	return binary.BigEndian.Uint64(cookie[:8]), binary.BigEndian.Uint64(cookie[8:]), true
+}
+
+// ============================================================================
+//                              watchHub 服务端事件分发
+// ============================================================================
+
+// watchRecord 事件日志记录
+type watchRecord struct {
+	seq   uint64
+	event *pb.Message_DiscoverEvent
+}
+
+// watchLog 单个命名空间的事件日志
+type watchLog struct {
+	records []watchRecord
+
+	// trimmed 已被淘汰的最大序号，cookie 不小于它才能续传
+	trimmed uint64
+}
+
+// watchSubscriber 订阅者
+type watchSubscriber struct {
+	namespace string
+	ch        chan *pb.Message_DiscoverEvent
+	closed    bool
+}
+
+// watchHub 命名空间变更分发器
+//
+// 为每个命名空间保留最近 backlog 条事件，订阅者断线后携带最后收到的
+// cookie 重连即可续传；日志已被淘汰时回退为完整快照。
+// 订阅者消费过慢（队列满）时断开其订阅，由客户端续传追上。
+type watchHub struct {
+	mu sync.Mutex
+
+	epoch   uint64
+	seq     uint64
+	backlog int
+	maxSubs int
+
+	logs  map[string]*watchLog
+	subs  map[string]map[*watchSubscriber]struct{}
+	nsubs int
+}
+
+// newWatchHub 创建分发器
+func newWatchHub(backlog, maxSubs int) *watchHub {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return &watchHub{
+		epoch:   binary.BigEndian.Uint64(b[:]),
+		backlog: backlog,
+		maxSubs: maxSubs,
+		logs:    make(map[string]*watchLog),
+		subs:    make(map[string]map[*watchSubscriber]struct{}),
+	}
+}
+
+// publish 发布注册变更（由 Store 在持有写锁时调用）
+func (h *watchHub) publish(typ WatchEventType, reg Registration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.seq++
+	ttl := time.Duration(0)
+	if typ == WatchAdded {
+		ttl = reg.RemainingTTL()
+	}
+	event := &pb.Message_DiscoverEvent{
+		Type:         watchEventToPB(typ),
+		Registration: registrationToPB(&reg, ttl),
+		Cookie:       encodeWatchCookie(h.epoch, h.seq),
+	}
+
+	log := h.logs[reg.Namespace]
+	if log == nil {
+		log = &watchLog{}
+		h.logs[reg.Namespace] = log
+	}
+	log.records = append(log.records, watchRecord{seq: h.seq, event: event})
+	if over := len(log.records) - h.backlog; over > 0 {
+		log.trimmed = log.records[over-1].seq
+		log.records = append([]watchRecord(nil), log.records[over:]...)
+	}
+
+	for sub := range h.subs[reg.Namespace] {
+		select {
+		case sub.ch <- event:
+		default:
+			// 消费过慢：断开，由客户端续传
+			h.removeLocked(sub)
+		}
+	}
+}
+
+// subscribe 注册订阅者
+//
+// cookie 可续传时返回 resumed=true，并把错过的事件预先放入订阅队列；
+// 否则调用 snapshot 获取当前注册作为起点。调用方需保证 snapshot
+// 与 publish 互斥（在 Store 读锁内调用），以免快照与事件之间出现空档。
+func (h *watchHub) subscribe(namespace string, cookie []byte, snapshot func() []*Registration) (sub *watchSubscriber, resumed bool, regs []*Registration, next []byte, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.maxSubs > 0 && h.nsubs >= h.maxSubs {
+		return nil, false, nil, nil, ErrTooManySubscribers
+	}
+
+	var missed []*pb.Message_DiscoverEvent
+	if epoch, seq, ok := decodeWatchCookie(cookie); ok && epoch == h.epoch && seq <= h.seq {
+		log := h.logs[namespace]
+		if log == nil || log.trimmed <= seq {
+			resumed = true
+			if log != nil {
+				for _, rec := range log.records {
+					if rec.seq > seq {
+						missed = append(missed, rec.event)
+					}
+				}
+			}
+		}
+	}
+	if !resumed {
+		regs = snapshot()
+	}
+
+	sub = &watchSubscriber{
+		namespace: namespace,
+		ch:        make(chan *pb.Message_DiscoverEvent, h.backlog+len(missed)),
+	}
+	for _, event := range missed {
+		sub.ch <- event
+	}
+
+	if h.subs[namespace] == nil {
+		h.subs[namespace] = make(map[*watchSubscriber]struct{})
+	}
+	h.subs[namespace][sub] = struct{}{}
+	h.nsubs++
+
+	return sub, resumed, regs, encodeWatchCookie(h.epoch, h.seq), nil
+}
+
+// unsubscribe 取消订阅
+func (h *watchHub) unsubscribe(sub *watchSubscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.removeLocked(sub)
+}
+
+// removeLocked 移除订阅者并关闭其队列（调用方需持有锁）
+func (h *watchHub) removeLocked(sub *watchSubscriber) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	close(sub.ch)
+
+	if subs := h.subs[sub.namespace]; subs != nil {
+		delete(subs, sub)
+		if len(subs) == 0 {
+			delete(h.subs, sub.namespace)
+		}
+	}
+	h.nsubs--
+}
+
+// subscriberCount 返回当前订阅者数量
+func (h *watchHub) subscriberCount() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.nsubs
+}
+
+// ============================================================================
+//                              客户端订阅
+// ============================================================================
+
+// watchEventBuffer 客户端事件通道缓冲
+const watchEventBuffer = 64
+
+// Watch 订阅命名空间变更
+//
+// 先推送命名空间当前的全部注册（WatchAdded），随后持续推送新增、过期与
+// 取消注册事件，直到 ctx 结束时关闭通道。与 Point 的连接断开后按
+// RetryInterval 重连，并携带最后收到的 cookie 续传；Point 无法续传时
+// 以新快照为准，补发差异事件，因此订阅方看到的成员视图始终与 Point 一致。
+// Point 不支持订阅时按 PollInterval 轮询，推送方式不变。
+func (d *Discoverer) Watch(ctx context.Context, namespace string) (<-chan WatchEvent, error) {
+	if !d.started.Load() {
+		return nil, ErrNotStarted
+	}
+
+	namespace = pkgif.NormalizeNamespace(namespace)
+	if err := ValidateNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	ch := make(chan WatchEvent, watchEventBuffer)
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		defer close(ch)
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go func() {
+			select {
+			case <-d.ctx.Done():
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		w := &watchSession{
+			d:         d,
+			namespace: namespace,
+			out:       ch,
+			known:     make(map[types.PeerID]types.PeerInfo),
+		}
+		w.run(ctx)
+	}()
+
+	return ch, nil
+}
+
+// watchPeers 将订阅事件转换为 FindPeers 的节点通道
+//
+// 只转发新增或变更（记录版本或地址变化）的注册，续约不重复转发；
+// 已转发的节点注册过期或被取消时转发 Expired 标记的节点，调用方据此移除。
+func (d *Discoverer) watchPeers(ctx context.Context, namespace string) (<-chan types.PeerInfo, error) {
+	events, err := d.Watch(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan types.PeerInfo, watchEventBuffer)
+	go func() {
+		defer close(ch)
+
+		forwarded := make(map[types.PeerID]types.PeerInfo)
+		for event := range events {
+			peer := event.Peer
+			if peer.ID == d.localID {
+				continue
+			}
+			if event.Type == WatchAdded {
+				if prev, ok := forwarded[peer.ID]; ok && samePeerRecord(prev, peer) {
+					continue
+				}
+				forwarded[peer.ID] = peer
+			} else {
+				if _, ok := forwarded[peer.ID]; !ok {
+					continue
+				}
+				delete(forwarded, peer.ID)
+				peer.Expired = true
+			}
+
+			select {
+			case ch <- peer:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// samePeerRecord 判断两次注册是否携带同一版本的节点记录
+func samePeerRecord(a, b types.PeerInfo) bool {
+	if a.Seq != b.Seq || len(a.Addrs) != len(b.Addrs) {
+		return false
+	}
+	for i := range a.Addrs {
+		if !a.Addrs[i].Equal(b.Addrs[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// watchSession 单个订阅的客户端状态
+type watchSession struct {
+	d         *Discoverer
+	namespace string
+	out       chan<- WatchEvent
+
+	// cookie 最后收到的续传 cookie
+	cookie []byte
+
+	// known 当前已知成员，用于快照对账
+	known map[types.PeerID]types.PeerInfo
+}
+
+// run 订阅循环：断线重连直到 ctx 结束
+//
+// Point 正常关闭流（停止或因消费过慢断开订阅）只是续传，不计入 Point
+// 失败；Point 不支持订阅时改为轮询，不再重复发起订阅。
+func (w *watchSession) run(ctx context.Context) {
+	for {
+		point, err := w.d.selectPoint()
+		if err == nil {
+			err = w.subscribeOnce(ctx, point)
+			switch {
+			case errors.Is(err, ErrWatchUnsupported):
+				logger.Info("Rendezvous 点不支持订阅，改为轮询", "namespace", w.namespace, "point", string(point))
+				w.poll(ctx)
+				return
+			case err != nil && ctx.Err() == nil:
+				logger.Debug("Rendezvous 订阅中断", "namespace", w.namespace, "point", string(point), "error", err)
+				w.d.recordFailure(point)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.d.config.RetryInterval):
+		}
+	}
+}
+
+// subscribeOnce 建立一次订阅并消费事件直到流结束
+func (w *watchSession) subscribeOnce(ctx context.Context, point types.PeerID) error {
+	if err := w.d.host.Connect(ctx, string(point), nil); err != nil {
+		return fmt.Errorf("connect failed: %w", err)
+	}
+
+	stream, err := w.d.host.NewStream(ctx, string(point), ProtocolID)
+	if err != nil {
+		return fmt.Errorf("create stream failed: %w", err)
+	}
+	if stream == nil {
+		return errors.New("stream is nil")
+	}
+	defer stream.Close()
+
+	// ctx 结束时关闭流以解除阻塞读
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = stream.Reset()
+		case <-stop:
+		}
+	}()
+
+	if err := WriteMessage(stream, NewDiscoverSubscribeRequest(w.namespace, w.cookie)); err != nil {
+		return fmt.Errorf("write message failed: %w", err)
+	}
+
+	resp, err := ReadMessage(stream)
+	if err != nil {
+		return fmt.Errorf("read message failed: %w", err)
+	}
+	if resp.DiscoverSubscribeResponse == nil {
+		// 旧版本 Point 不识别订阅请求
+		return ErrWatchUnsupported
+	}
+	if err := StatusToError(resp.DiscoverSubscribeResponse.Status, resp.DiscoverSubscribeResponse.StatusText); err != nil {
+		return err
+	}
+
+	if !resp.DiscoverSubscribeResponse.Resumed {
+		if !w.reconcile(ctx, resp.DiscoverSubscribeResponse.Registrations) {
+			return ctx.Err()
+		}
+	}
+	w.cookie = resp.DiscoverSubscribeResponse.Cookie
+
+	for {
+		msg, err := ReadMessage(stream)
+		if errors.Is(err, io.EOF) {
+			// Point 在消息边界处关闭流，携带 cookie 重连即可
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read message failed: %w", err)
+		}
+		event := msg.DiscoverEvent
+		if event == nil || event.Registration == nil {
+			return ErrInvalidMessage
+		}
+
+		peer, err := RegistrationToPeerInfo(event.Registration)
+		if err == nil {
+			typ := watchEventFromPB(event.Type)
+			if typ == WatchAdded {
+				w.known[peer.ID] = peer
+			} else {
+				delete(w.known, peer.ID)
+			}
+			if !w.emit(ctx, WatchEvent{
+				Type:      typ,
+				Namespace: w.namespace,
+				Peer:      peer,
+				TTL:       time.Duration(event.Registration.Ttl) * time.Second,
+			}) {
+				return ctx.Err()
+			}
+		}
+		w.cookie = event.Cookie
+	}
+}
+
+// poll 轮询循环：定期拉取命名空间的全部注册并对账，直到 ctx 结束
+func (w *watchSession) poll(ctx context.Context) {
+	interval := w.d.config.PollInterval
+	if interval <= 0 {
+		interval = DefaultDiscovererConfig().PollInterval
+	}
+
+	for {
+		regs, err := w.fetch(ctx)
+		if err == nil {
+			if !w.reconcile(ctx, regs) {
+				return
+			}
+		} else if ctx.Err() == nil {
+			logger.Debug("Rendezvous 轮询失败", "namespace", w.namespace, "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// fetch 分页拉取命名空间的全部注册
+func (w *watchSession) fetch(ctx context.Context) ([]*pb.Message_Registration, error) {
+	var (
+		regs   []*pb.Message_Registration
+		cookie []byte
+		point  types.PeerID
+	)
+	for {
+		// 翻页优先留在同一个 Point 上，cookie 只对该 Point 有效
+		next, resp, err := w.d.request(ctx, point, NewDiscoverRequest(w.namespace, 0, cookie))
+		if err != nil {
+			return nil, err
+		}
+		point = next
+		if resp.DiscoverResponse == nil {
+			return nil, errors.New("missing discover response")
+		}
+		if err := StatusToError(resp.DiscoverResponse.Status, resp.DiscoverResponse.StatusText); err != nil {
+			return nil, err
+		}
+
+		regs = append(regs, resp.DiscoverResponse.Registrations...)
+		cookie = resp.DiscoverResponse.Cookie
+		if len(cookie) == 0 || len(resp.DiscoverResponse.Registrations) == 0 {
+			return regs, nil
+		}
+	}
+}
+
+// reconcile 以快照为准对账：新增或记录变更的成员发 WatchAdded，消失的成员发 WatchExpired
+func (w *watchSession) reconcile(ctx context.Context, regs []*pb.Message_Registration) bool {
+	current := make(map[types.PeerID]struct{}, len(regs))
+	for _, reg := range regs {
+		peer, err := RegistrationToPeerInfo(reg)
+		if err != nil {
+			continue
+		}
+		current[peer.ID] = struct{}{}
+		if prev, ok := w.known[peer.ID]; ok && samePeerRecord(prev, peer) {
+			continue
+		}
+		w.known[peer.ID] = peer
+		if !w.emit(ctx, WatchEvent{
+			Type:      WatchAdded,
+			Namespace: w.namespace,
+			Peer:      peer,
+			TTL:       time.Duration(reg.Ttl) * time.Second,
+		}) {
+			return false
+		}
+	}
+
+	for id, peer := range w.known {
+		if _, ok := current[id]; ok {
+			continue
+		}
+		delete(w.known, id)
+		if !w.emit(ctx, WatchEvent{Type: WatchExpired, Namespace: w.namespace, Peer: peer}) {
+			return false
+		}
+	}
+	return true
+}
+
+// emit 投递事件，ctx 结束时返回 false
+func (w *watchSession) emit(ctx context.Context, event WatchEvent) bool {
+	select {
+	case w.out <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
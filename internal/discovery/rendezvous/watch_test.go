@@ -0,0 +1,374 @@
+package rendezvous
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	pb "github.com/dep2p/go-dep2p/pkg/lib/proto/rendezvous"
+	"github.com/dep2p/go-dep2p/pkg/types"
+)
+
+// newWatchedStore 创建带订阅分发器的存储
+func newWatchedStore(backlog int) (*Store, *watchHub) {
+	store := NewStore(DefaultStoreConfig())
+	hub := newWatchHub(backlog, 10)
+	store.SetNotifier(hub.publish)
+	return store, hub
+}
+
+// subscribeStore 在存储读锁内订阅
+func subscribeStore(t *testing.T, store *Store, hub *watchHub, ns string, cookie []byte) (*watchSubscriber, bool, []*Registration, []byte) {
+	t.Helper()
+	var (
+		sub     *watchSubscriber
+		resumed bool
+		regs    []*Registration
+		next    []byte
+		err     error
+	)
+	store.view(ns, func(current []*Registration) {
+		sub, resumed, regs, next, err = hub.subscribe(ns, cookie, func() []*Registration { return current })
+	})
+	require.NoError(t, err)
+	return sub, resumed, regs, next
+}
+
+// TestWatchHub_SnapshotAndEvents 测试快照与后续事件
+func TestWatchHub_SnapshotAndEvents(t *testing.T) {
+	store, hub := newWatchedStore(16)
+	require.NoError(t, store.Add("ns", types.PeerInfo{ID: "peer-1"}, time.Hour))
+
+	sub, resumed, regs, _ := subscribeStore(t, store, hub, "ns", nil)
+	assert.False(t, resumed)
+	require.Len(t, regs, 1)
+	assert.Equal(t, types.PeerID("peer-1"), regs[0].PeerInfo.ID)
+
+	require.NoError(t, store.Add("ns", types.PeerInfo{ID: "peer-2"}, time.Hour))
+	require.NoError(t, store.Add("other", types.PeerInfo{ID: "peer-3"}, time.Hour))
+	store.Remove("ns", "peer-1")
+
+	event := <-sub.ch
+	assert.Equal(t, pb.Message_DiscoverEvent_ADDED, event.Type)
+	assert.Equal(t, "peer-2", string(event.Registration.SignedPeerRecord))
+
+	event = <-sub.ch
+	assert.Equal(t, pb.Message_DiscoverEvent_REMOVED, event.Type)
+	assert.Equal(t, "peer-1", string(event.Registration.SignedPeerRecord))
+
+	assert.Empty(t, sub.ch, "events of other namespaces must not be delivered")
+
+	hub.unsubscribe(sub)
+	assert.Equal(t, 0, hub.subscriberCount())
+}
+
+// TestWatchHub_Resume 测试携带 cookie 续传
+func TestWatchHub_Resume(t *testing.T) {
+	store, hub := newWatchedStore(16)
+
+	sub, _, _, cookie := subscribeStore(t, store, hub, "ns", nil)
+	require.NoError(t, store.Add("ns", types.PeerInfo{ID: "peer-1"}, time.Hour))
+	first := <-sub.ch
+	hub.unsubscribe(sub)
+
+	// 断线期间的变更
+	require.NoError(t, store.Add("ns", types.PeerInfo{ID: "peer-2"}, time.Hour))
+	require.NoError(t, store.Add("ns", types.PeerInfo{ID: "peer-3"}, time.Hour))
+
+	sub, resumed, regs, _ := subscribeStore(t, store, hub, "ns", first.Cookie)
+	defer hub.unsubscribe(sub)
+	assert.True(t, resumed)
+	assert.Empty(t, regs)
+	require.Len(t, sub.ch, 2)
+	assert.Equal(t, "peer-2", string((<-sub.ch).Registration.SignedPeerRecord))
+	assert.Equal(t, "peer-3", string((<-sub.ch).Registration.SignedPeerRecord))
+
+	// 订阅时返回的 cookie 同样可续传
+	sub2, resumed, _, _ := subscribeStore(t, store, hub, "ns", cookie)
+	defer hub.unsubscribe(sub2)
+	assert.True(t, resumed)
+	assert.Len(t, sub2.ch, 3)
+}
+
+// TestWatchHub_ResumeFallback 测试无法续传时回退为快照
+func TestWatchHub_ResumeFallback(t *testing.T) {
+	store, hub := newWatchedStore(2)
+
+	_, _, _, cookie := subscribeStore(t, store, hub, "ns", nil)
+	for _, id := range []types.PeerID{"peer-1", "peer-2", "peer-3"} {
+		require.NoError(t, store.Add("ns", types.PeerInfo{ID: id}, time.Hour))
+	}
+
+	// 日志已淘汰
+	sub, resumed, regs, _ := subscribeStore(t, store, hub, "ns", cookie)
+	assert.False(t, resumed)
+	assert.Len(t, regs, 3)
+	hub.unsubscribe(sub)
+
+	// 其他 Point 的 cookie（epoch 不同）
+	sub, resumed, regs, _ = subscribeStore(t, store, hub, "ns", encodeWatchCookie(hub.epoch+1, 1))
+	assert.False(t, resumed)
+	assert.Len(t, regs, 3)
+	hub.unsubscribe(sub)
+
+	// 格式错误的 cookie
+	sub, resumed, _, _ = subscribeStore(t, store, hub, "ns", []byte("bad"))
+	assert.False(t, resumed)
+	hub.unsubscribe(sub)
+}
+
+// TestWatchHub_SlowSubscriber 测试消费过慢的订阅者被断开
+func TestWatchHub_SlowSubscriber(t *testing.T) {
+	store, hub := newWatchedStore(2)
+
+	sub, _, _, _ := subscribeStore(t, store, hub, "ns", nil)
+	for _, id := range []types.PeerID{"peer-1", "peer-2", "peer-3"} {
+		require.NoError(t, store.Add("ns", types.PeerInfo{ID: id}, time.Hour))
+	}
+
+	assert.Equal(t, 0, hub.subscriberCount())
+	n := 0
+	for range sub.ch {
+		n++
+	}
+	assert.Equal(t, 2, n)
+
+	// 重复取消订阅是安全的
+	hub.unsubscribe(sub)
+	assert.Equal(t, 0, hub.subscriberCount())
+}
+
+// TestWatchHub_MaxSubscribers 测试订阅者上限
+func TestWatchHub_MaxSubscribers(t *testing.T) {
+	hub := newWatchHub(4, 1)
+	snapshot := func() []*Registration { return nil }
+
+	sub, _, _, _, err := hub.subscribe("ns", nil, snapshot)
+	require.NoError(t, err)
+
+	_, _, _, _, err = hub.subscribe("ns", nil, snapshot)
+	assert.ErrorIs(t, err, ErrTooManySubscribers)
+
+	hub.unsubscribe(sub)
+	sub, _, _, _, err = hub.subscribe("ns", nil, snapshot)
+	require.NoError(t, err)
+	hub.unsubscribe(sub)
+}
+
+// TestWatchHub_Expired 测试过期事件
+func TestWatchHub_Expired(t *testing.T) {
+	store, hub := newWatchedStore(4)
+	require.NoError(t, store.Add("ns", types.PeerInfo{ID: "peer-1"}, 50*time.Millisecond))
+
+	sub, _, _, _ := subscribeStore(t, store, hub, "ns", nil)
+	defer hub.unsubscribe(sub)
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, 1, store.CleanupExpired())
+
+	event := <-sub.ch
+	assert.Equal(t, pb.Message_DiscoverEvent_EXPIRED, event.Type)
+	assert.Equal(t, WatchExpired, watchEventFromPB(event.Type))
+}
+
+// TestWatch_MessageRoundTrip 测试订阅消息编解码
+func TestWatch_MessageRoundTrip(t *testing.T) {
+	cookie := encodeWatchCookie(7, 42)
+	epoch, seq, ok := decodeWatchCookie(cookie)
+	require.True(t, ok)
+	assert.Equal(t, uint64(7), epoch)
+	assert.Equal(t, uint64(42), seq)
+
+	msg := NewDiscoverEvent(&pb.Message_DiscoverEvent{
+		Type:         watchEventToPB(WatchRemoved),
+		Registration: PeerInfoToRegistration(types.PeerInfo{ID: "peer-1"}, "ns", time.Minute),
+		Cookie:       cookie,
+	})
+	data, err := proto.Marshal(msg)
+	require.NoError(t, err)
+
+	var decoded pb.Message
+	require.NoError(t, proto.Unmarshal(data, &decoded))
+	assert.Equal(t, pb.Message_DISCOVER_EVENT, decoded.Type)
+	require.NotNil(t, decoded.DiscoverEvent)
+	assert.Equal(t, WatchRemoved, watchEventFromPB(decoded.DiscoverEvent.Type))
+	assert.Equal(t, cookie, decoded.DiscoverEvent.Cookie)
+
+	req := NewDiscoverSubscribeRequest("ns", cookie)
+	data, err = proto.Marshal(req)
+	require.NoError(t, err)
+	decoded.Reset()
+	require.NoError(t, proto.Unmarshal(data, &decoded))
+	assert.Equal(t, "ns", decoded.DiscoverSubscribe.Ns)
+}
+
+// startWatchClient 启动重连与轮询间隔都很短的 Discoverer
+func startWatchClient(t *testing.T, n *pipeNet, id string, points ...types.PeerID) *Discoverer {
+	t.Helper()
+
+	config := DefaultDiscovererConfig()
+	config.Points = points
+	config.RetryInterval = 20 * time.Millisecond
+	config.PollInterval = 20 * time.Millisecond
+	d := NewDiscoverer(n.host(id), config)
+	require.NoError(t, d.Start(context.Background()))
+	t.Cleanup(func() { _ = d.Stop(context.Background()) })
+	return d
+}
+
+// nextWatchEvent 在超时内读取一个订阅事件
+func nextWatchEvent(t *testing.T, events <-chan WatchEvent) WatchEvent {
+	t.Helper()
+	select {
+	case event, ok := <-events:
+		require.True(t, ok, "watch channel closed unexpectedly")
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for watch event")
+		return WatchEvent{}
+	}
+}
+
+// TestWatch_CleanCloseReconnects 测试 Point 正常关闭订阅流时续传且不计入失败
+func TestWatch_CleanCloseReconnects(t *testing.T) {
+	n := newPipeNet()
+	point := NewPoint(n.host("point"), DefaultPointConfig())
+	require.NoError(t, point.Start(context.Background()))
+	t.Cleanup(func() { _ = point.Stop() })
+
+	client := startWatchClient(t, n, "client", "point")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := client.Watch(ctx, "chat")
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return point.Subscribers() == 1 }, 2*time.Second, 5*time.Millisecond)
+
+	// 模拟消费过慢被断开：Point 关闭订阅，流在消息边界处结束
+	for i := 0; i < maxFailCount+1; i++ {
+		point.watch.mu.Lock()
+		for _, subs := range point.watch.subs {
+			for sub := range subs {
+				point.watch.removeLocked(sub)
+			}
+		}
+		point.watch.mu.Unlock()
+		require.Eventually(t, func() bool { return point.Subscribers() == 1 }, 2*time.Second, 5*time.Millisecond,
+			"client must resubscribe after a clean close")
+	}
+
+	client.healthMu.RLock()
+	health := client.pointHealth["point"]
+	client.healthMu.RUnlock()
+	assert.Nil(t, health, "clean close must not count as a point failure")
+
+	alice := newTestPeer(t)
+	alice.register(t, point.store, "chat")
+	event := nextWatchEvent(t, events)
+	assert.Equal(t, WatchAdded, event.Type)
+	assert.Equal(t, alice.id, event.Peer.ID)
+}
+
+// TestWatch_FallbackToPolling 测试 Point 不支持订阅时回退为轮询
+func TestWatch_FallbackToPolling(t *testing.T) {
+	n := newPipeNet()
+
+	// 旧版 Point：只应答 DISCOVER，订阅请求按未知类型处理
+	legacy := NewPoint(n.host("legacy"), DefaultPointConfig())
+	var subscribes atomic.Int32
+	n.mu.Lock()
+	n.handlers["legacy"] = func(stream pkgif.Stream) {
+		defer stream.Close()
+		req, err := ReadMessage(stream)
+		if err != nil {
+			return
+		}
+		resp := NewRegisterResponse(pb.Message_E_INTERNAL_ERROR, "unknown message type", 0)
+		switch req.Type {
+		case pb.Message_DISCOVER:
+			resp = legacy.handleDiscover(req)
+		case pb.Message_DISCOVER_SUBSCRIBE:
+			subscribes.Add(1)
+		}
+		_ = WriteMessage(stream, resp)
+	}
+	n.mu.Unlock()
+
+	alice, bob := newTestPeer(t), newTestPeer(t)
+	alice.register(t, legacy.store, "chat")
+
+	client := startWatchClient(t, n, "client", "legacy")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := client.Watch(ctx, "chat")
+	require.NoError(t, err)
+
+	event := nextWatchEvent(t, events)
+	assert.Equal(t, WatchAdded, event.Type)
+	assert.Equal(t, alice.id, event.Peer.ID)
+
+	bob.register(t, legacy.store, "chat")
+	event = nextWatchEvent(t, events)
+	assert.Equal(t, WatchAdded, event.Type)
+	assert.Equal(t, bob.id, event.Peer.ID)
+
+	legacy.store.Remove("chat", alice.id)
+	event = nextWatchEvent(t, events)
+	assert.Equal(t, WatchExpired, event.Type)
+	assert.Equal(t, alice.id, event.Peer.ID)
+
+	assert.Equal(t, int32(1), subscribes.Load(), "unsupported point must not be asked to subscribe again")
+
+	cancel()
+	for range events {
+	}
+}
+
+// TestWatch_FindPeersForwardsChangesAndExpiry 测试持续 FindPeers 只转发新增或变更的注册并转发过期
+func TestWatch_FindPeersForwardsChangesAndExpiry(t *testing.T) {
+	n := newPipeNet()
+	point := NewPoint(n.host("point"), DefaultPointConfig())
+	require.NoError(t, point.Start(context.Background()))
+	t.Cleanup(func() { _ = point.Stop() })
+
+	client := startWatchClient(t, n, "client", "point")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	peers, err := client.FindPeers(ctx, "chat", pkgif.WithWatch())
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return point.Subscribers() == 1 }, 2*time.Second, 5*time.Millisecond)
+
+	next := func() types.PeerInfo {
+		t.Helper()
+		select {
+		case peer, ok := <-peers:
+			require.True(t, ok, "peer channel closed unexpectedly")
+			return peer
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for peer")
+			return types.PeerInfo{}
+		}
+	}
+
+	alice, bob := newTestPeer(t), newTestPeer(t)
+	alice.register(t, point.store, "chat")
+	assert.Equal(t, alice.id, next().ID)
+
+	// 续约不重复转发：下一个收到的是 bob
+	alice.register(t, point.store, "chat")
+	bob.register(t, point.store, "chat")
+	peer := next()
+	assert.Equal(t, bob.id, peer.ID)
+	assert.False(t, peer.Expired)
+
+	point.store.Remove("chat", alice.id)
+	peer = next()
+	assert.Equal(t, alice.id, peer.ID)
+	assert.True(t, peer.Expired, "expiry must be surfaced")
+}
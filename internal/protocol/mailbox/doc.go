@@ -0,0 +1,53 @@
+// Package mailbox 实现离线消息的存储转发（信箱）服务
+//
+// 协议标识: /dep2p/app/<realmID>/mailbox/1.0.0
+//
+// # 架构定位
+//
+// - 架构层: Protocol Layer (L4)
+// - 公共接口: pkg/interfaces/mailbox.go
+// - 依赖: internal/core/host, internal/core/storage, internal/realm
+//
+// # 工作方式
+//
+// Messaging.Send 要求目标节点在线。对于聊天、IoT 指令等需要向离线节点
+// 投递的场景，Realm 内的指定成员（通常为 Relay/Gateway 角色）可以运行
+// 信箱服务端：
+//
+//  1. 发送方使用接收方公钥封装消息（pkg/lib/crypto.SealEd25519），
+//     连同 TTL 投递（DEPOSIT）到信箱节点
+//  2. 信箱节点按接收方 NodeID 存储密文，持久化到存储引擎
+//  3. 接收方重新上线后拉取（FETCH）并解密，处理后确认（ACK），
+//     信箱节点随即删除
+//
+// 信箱节点只能看到密文。封装使用临时密钥，本身不能证明发送方身份，
+// 因此发送方在封装前用身份私钥对明文签名，公钥和签名随明文一起加密。
+// 接收方解密后校验公钥派生出的 NodeID 与 From 一致且签名有效，
+// 信箱节点或其他成员伪造的发送方会被拒绝。
+//
+// # 配额
+//
+// 服务端对每个发送方、每个接收方分别限制待投递消息数，
+// 并限制单条消息大小和最大 TTL。过期消息由后台任务定期清理。
+//
+// # 使用示例
+//
+//	mb := realm.Mailbox()
+//
+//	// 发送方：对方离线时投递到信箱
+//	id, err := mb.Deposit(ctx, mailboxPeer, recipient, []byte("hello"), time.Hour)
+//
+//	// 接收方：上线后拉取并确认
+//	msgs, _ := mb.Fetch(ctx, mailboxPeer)
+//	for _, m := range msgs {
+//	    fmt.Printf("%s: %s\n", m.From, m.Data)
+//	}
+//	mb.Ack(ctx, mailboxPeer, ids)
+//
+// 配置 WithMailboxPeers 并设置 SetMessageHandler 后，服务会在连接到
+// 信箱节点时自动拉取、投递并确认。
+//
+// # 存储键空间
+//
+//	mailbox/<realmID>/m/<recipient>/<msgID> -> JSON(envelope)
+package mailbox
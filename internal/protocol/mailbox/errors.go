@@ -0,0 +1,55 @@
+// Package mailbox 实现离线消息的存储转发（信箱）服务
+package mailbox
+
+import "errors"
+
+// 错误定义
+var (
+	// ErrNotStarted 服务未启动
+	ErrNotStarted = errors.New("mailbox: service not started")
+
+	// ErrAlreadyStarted 服务已启动
+	ErrAlreadyStarted = errors.New("mailbox: service already started")
+
+	// ErrNilHost Host 为 nil
+	ErrNilHost = errors.New("mailbox: host is nil")
+
+	// ErrNilRealm Realm 为 nil
+	ErrNilRealm = errors.New("mailbox: realm is nil")
+
+	// ErrEngineRequired 服务端模式需要存储引擎
+	ErrEngineRequired = errors.New("mailbox: storage engine required for server mode")
+
+	// ErrNotRealmMember 节点不是 Realm 成员
+	ErrNotRealmMember = errors.New("mailbox: peer is not realm member")
+
+	// ErrMessageTooLarge 消息过大
+	ErrMessageTooLarge = errors.New("mailbox: message too large")
+
+	// ErrInvalidRecipient 无效的接收方
+	ErrInvalidRecipient = errors.New("mailbox: invalid recipient")
+
+	// ErrSenderQuotaExceeded 发送方配额已满
+	ErrSenderQuotaExceeded = errors.New("mailbox: sender quota exceeded")
+
+	// ErrRecipientQuotaExceeded 接收方配额已满
+	ErrRecipientQuotaExceeded = errors.New("mailbox: recipient quota exceeded")
+
+	// ErrNoRecipientKey 本地没有接收方公钥，无法加密
+	ErrNoRecipientKey = errors.New("mailbox: recipient public key unknown")
+
+	// ErrNoLocalKey 无法获取本地私钥，无法解密
+	ErrNoLocalKey = errors.New("mailbox: local private key unavailable")
+
+	// ErrUnsupportedKeyType 不支持的密钥类型（仅支持 Ed25519）
+	ErrUnsupportedKeyType = errors.New("mailbox: unsupported key type")
+
+	// ErrInvalidMessage 无效的消息格式
+	ErrInvalidMessage = errors.New("mailbox: invalid message format")
+
+	// ErrInvalidSignature 发送方签名无效
+	ErrInvalidSignature = errors.New("mailbox: invalid sender signature")
+
+	// ErrRemote 信箱节点返回错误
+	ErrRemote = errors.New("mailbox: remote error")
+)
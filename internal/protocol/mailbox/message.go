@@ -0,0 +1,190 @@
+// Package mailbox 实现离线消息的存储转发（信箱）服务
+package mailbox
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/crypto"
+)
+
+// 请求类型
+const (
+	opDeposit = "deposit"
+	opFetch   = "fetch"
+	opAck     = "ack"
+)
+
+// maxFrameSize 单帧最大字节数（防止恶意长度字段耗尽内存）
+const maxFrameSize = 8 << 20
+
+// envelope 信箱中存储的消息（密文）
+type envelope struct {
+	// ID 消息 ID（信箱分配，按时间有序）
+	ID string `json:"id"`
+
+	// From 发送方节点 ID（信箱根据连接身份填写）
+	From string `json:"from"`
+
+	// To 接收方节点 ID
+	To string `json:"to"`
+
+	// Payload 端到端加密的消息
+	Payload []byte `json:"payload"`
+
+	// StoredAt 存入时间（Unix 纳秒）
+	StoredAt int64 `json:"stored_at"`
+
+	// ExpiresAt 过期时间（Unix 纳秒）
+	ExpiresAt int64 `json:"expires_at"`
+}
+
+// request 客户端请求
+type request struct {
+	// Op 请求类型：deposit / fetch / ack
+	Op string `json:"op"`
+
+	// To 接收方（deposit）
+	To string `json:"to,omitempty"`
+
+	// Payload 密文（deposit）
+	Payload []byte `json:"payload,omitempty"`
+
+	// TTLNanos 消息 TTL（deposit，0 表示默认）
+	TTLNanos int64 `json:"ttl_nanos,omitempty"`
+
+	// Limit 拉取数量上限（fetch）
+	Limit int `json:"limit,omitempty"`
+
+	// IDs 确认的消息 ID（ack）
+	IDs []string `json:"ids,omitempty"`
+}
+
+// response 服务端响应
+type response struct {
+	// Error 错误信息（为空表示成功）
+	Error string `json:"error,omitempty"`
+
+	// ID 新消息 ID（deposit）
+	ID string `json:"id,omitempty"`
+
+	// Envelopes 消息列表（fetch）
+	Envelopes []*envelope `json:"envelopes,omitempty"`
+
+	// Acked 实际删除的消息数（ack）
+	Acked int `json:"acked,omitempty"`
+}
+
+// writeFrame 写入帧（4 字节大端长度 + JSON）
+func writeFrame(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if len(data) > maxFrameSize {
+		return ErrMessageTooLarge
+	}
+
+	buf := make([]byte, 4+len(data))
+	binary.BigEndian.PutUint32(buf, uint32(len(data)))
+	copy(buf[4:], data)
+	_, err = w.Write(buf)
+	return err
+}
+
+// readFrame 读取帧并反序列化
+func readFrame(r io.Reader, v interface{}) error {
+	var lenBuf [4]byte
+	if _, err := io.ReadFull(r, lenBuf[:]); err != nil {
+		return err
+	}
+
+	length := binary.BigEndian.Uint32(lenBuf[:])
+	if length > maxFrameSize {
+		return fmt.Errorf("%w: frame size %d", ErrMessageTooLarge, length)
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+	}
+	return nil
+}
+
+// sealAAD 构造封装的附加认证数据
+//
+// 绑定 Realm、发送方和接收方，防止密文被挪用到其他接收方或 Realm。
+// 封装只使用临时密钥，AAD 本身不能证明发送方身份，身份由 signBody 中的签名保证。
+func sealAAD(realmID, from, to string) []byte {
+	return []byte(realmID + "\x00" + from + "\x00" + to)
+}
+
+// signDomain 发送方签名的域分隔前缀
+const signDomain = "dep2p-mailbox-v1\x00"
+
+// signedData 构造发送方签名覆盖的数据：域前缀 + AAD + 明文
+func signedData(aad, data []byte) []byte {
+	buf := make([]byte, 0, len(signDomain)+len(aad)+len(data))
+	buf = append(buf, signDomain...)
+	buf = append(buf, aad...)
+	return append(buf, data...)
+}
+
+// signBody 使用发送方身份私钥签名明文
+//
+// 返回的封装明文格式：发送方公钥（32 字节）|| 签名（64 字节）|| 明文。
+// 公钥和签名都在密文内部，信箱节点看不到。
+func signBody(priv interfaces.PrivateKey, aad, data []byte) ([]byte, error) {
+	pub, err := priv.PublicKey().Raw()
+	if err != nil {
+		return nil, err
+	}
+	if len(pub) != crypto.Ed25519PublicKeySize {
+		return nil, ErrUnsupportedKeyType
+	}
+	sig, err := priv.Sign(signedData(aad, data))
+	if err != nil {
+		return nil, err
+	}
+	if len(sig) != crypto.Ed25519SignatureSize {
+		return nil, ErrUnsupportedKeyType
+	}
+
+	body := make([]byte, 0, len(pub)+len(sig)+len(data))
+	body = append(body, pub...)
+	body = append(body, sig...)
+	return append(body, data...), nil
+}
+
+// verifyBody 校验封装明文中的发送方签名，返回原始明文
+//
+// 公钥必须派生出 from，签名必须覆盖 AAD 和明文，否则返回 ErrInvalidSignature。
+func verifyBody(from string, aad, body []byte) ([]byte, error) {
+	const header = crypto.Ed25519PublicKeySize + crypto.Ed25519SignatureSize
+	if len(body) < header {
+		return nil, ErrInvalidSignature
+	}
+
+	pub, err := crypto.UnmarshalEd25519PublicKey(body[:crypto.Ed25519PublicKeySize])
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	id, err := crypto.PeerIDFromPublicKey(pub)
+	if err != nil || string(id) != from {
+		return nil, ErrInvalidSignature
+	}
+
+	data := body[header:]
+	ok, err := pub.Verify(signedData(aad, data), body[crypto.Ed25519PublicKeySize:header])
+	if err != nil || !ok {
+		return nil, ErrInvalidSignature
+	}
+	return data, nil
+}
@@ -0,0 +1,106 @@
+// Package mailbox 实现离线消息的存储转发（信箱）服务
+package mailbox
+
+import "time"
+
+// Config 信箱服务配置
+type Config struct {
+	// EnableServer 是否运行信箱服务端（为其他成员存储消息）
+	EnableServer bool
+
+	// DefaultTTL 默认消息 TTL
+	DefaultTTL time.Duration
+
+	// MaxTTL 最大消息 TTL（超过时截断）
+	MaxTTL time.Duration
+
+	// MaxMessageSize 单条消息最大字节数（密文）
+	MaxMessageSize int
+
+	// MaxPerSender 每个发送方最多待投递的消息数
+	MaxPerSender int
+
+	// MaxPerRecipient 每个接收方最多待投递的消息数
+	MaxPerRecipient int
+
+	// FetchLimit 单次拉取的最大消息数
+	FetchLimit int
+
+	// GCInterval 过期消息清理间隔
+	GCInterval time.Duration
+
+	// RequestTimeout 客户端请求超时
+	RequestTimeout time.Duration
+
+	// MailboxPeers 本节点使用的信箱节点（连接后自动拉取）
+	MailboxPeers []string
+}
+
+// DefaultConfig 返回默认配置
+func DefaultConfig() *Config {
+	return &Config{
+		EnableServer:    false,
+		DefaultTTL:      24 * time.Hour,
+		MaxTTL:          7 * 24 * time.Hour,
+		MaxMessageSize:  64 * 1024,
+		MaxPerSender:    1000,
+		MaxPerRecipient: 1000,
+		FetchLimit:      100,
+		GCInterval:      5 * time.Minute,
+		RequestTimeout:  30 * time.Second,
+	}
+}
+
+// Option 配置选项函数
+type Option func(*Config)
+
+// WithServer 设置是否运行信箱服务端
+func WithServer(enable bool) Option {
+	return func(c *Config) {
+		c.EnableServer = enable
+	}
+}
+
+// WithTTL 设置默认和最大 TTL
+func WithTTL(defaultTTL, maxTTL time.Duration) Option {
+	return func(c *Config) {
+		c.DefaultTTL = defaultTTL
+		c.MaxTTL = maxTTL
+	}
+}
+
+// WithMaxMessageSize 设置单条消息最大字节数
+func WithMaxMessageSize(size int) Option {
+	return func(c *Config) {
+		c.MaxMessageSize = size
+	}
+}
+
+// WithQuotas 设置每个发送方和每个接收方的消息配额
+func WithQuotas(perSender, perRecipient int) Option {
+	return func(c *Config) {
+		c.MaxPerSender = perSender
+		c.MaxPerRecipient = perRecipient
+	}
+}
+
+// WithMailboxPeers 设置本节点使用的信箱节点
+func WithMailboxPeers(peers ...string) Option {
+	return func(c *Config) {
+		c.MailboxPeers = append([]string(nil), peers...)
+	}
+}
+
+// WithGCInterval 设置过期消息清理间隔
+func WithGCInterval(interval time.Duration) Option {
+	return func(c *Config) {
+		c.GCInterval = interval
+	}
+}
+
+// WithRequestTimeout 设置客户端请求超时
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.RequestTimeout = timeout
+	}
+}
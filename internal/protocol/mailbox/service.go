@@ -0,0 +1,625 @@
+// Package mailbox 实现离线消息的存储转发（信箱）服务
+package mailbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dep2p/go-dep2p/internal/core/storage/engine"
+	"github.com/dep2p/go-dep2p/internal/core/storage/kv"
+	"github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/crypto"
+	"github.com/dep2p/go-dep2p/pkg/lib/log"
+	"github.com/dep2p/go-dep2p/pkg/protocol"
+	"github.com/dep2p/go-dep2p/pkg/types"
+	"github.com/google/uuid"
+)
+
+var logger = log.Logger("protocol/mailbox")
+
+// Service 信箱服务
+//
+// 同一个服务同时提供客户端能力（Deposit/Fetch/Ack）和可选的服务端能力
+// （EnableServer 时为其他成员存储消息）。
+type Service struct {
+	host    interfaces.Host
+	realm   interfaces.Realm
+	realmID string
+	engine  engine.InternalEngine
+	config  *Config
+
+	// 服务端存储（仅 EnableServer 时非 nil）
+	store *Store
+
+	handlerMu sync.RWMutex
+	handler   interfaces.MailboxHandler
+
+	// 正在拉取的信箱节点（防止重复拉取）
+	draining sync.Map
+
+	mu      sync.RWMutex
+	started bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// 确保 Service 实现了 interfaces.Mailbox 接口
+var _ interfaces.Mailbox = (*Service)(nil)
+
+// NewForRealm 创建绑定到 Realm 的信箱服务
+//
+// eng 仅在服务端模式下必需，客户端模式可以传 nil。
+func NewForRealm(host interfaces.Host, realm interfaces.Realm, eng engine.InternalEngine, opts ...Option) (*Service, error) {
+	if host == nil {
+		return nil, ErrNilHost
+	}
+	if realm == nil {
+		return nil, ErrNilRealm
+	}
+
+	config := DefaultConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	if config.EnableServer && eng == nil {
+		return nil, ErrEngineRequired
+	}
+
+	return &Service{
+		host:    host,
+		realm:   realm,
+		realmID: realm.ID(),
+		engine:  eng,
+		config:  config,
+	}, nil
+}
+
+// protocolID 返回信箱协议 ID
+func (s *Service) protocolID() string {
+	return string(protocol.NewAppBuilder(s.realmID).Mailbox())
+}
+
+// Start 启动服务
+func (s *Service) Start(_ context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return ErrAlreadyStarted
+	}
+
+	// 使用 context.Background()，Fx OnStart 的 ctx 在返回后会被取消
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+
+	if s.config.EnableServer {
+		store, err := NewStore(kv.New(s.engine, []byte("mailbox/"+s.realmID+"/")), s.config)
+		if err != nil {
+			s.cancel()
+			return fmt.Errorf("mailbox: open store: %w", err)
+		}
+		s.store = store
+		s.host.SetStreamHandler(s.protocolID(), s.handleStream)
+
+		s.wg.Add(1)
+		go s.gcLoop()
+
+		logger.Info("信箱服务端已启动", "realmID", s.realmID)
+	}
+
+	if len(s.config.MailboxPeers) > 0 {
+		s.subscribeConnections()
+	}
+
+	s.started = true
+	return nil
+}
+
+// Stop 停止服务
+func (s *Service) Stop(_ context.Context) error {
+	s.mu.Lock()
+	if !s.started {
+		s.mu.Unlock()
+		return ErrNotStarted
+	}
+	s.started = false
+	if s.store != nil {
+		s.host.RemoveStreamHandler(s.protocolID())
+	}
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return nil
+}
+
+// Close 关闭服务
+func (s *Service) Close() error {
+	if err := s.Stop(context.Background()); err != nil && !errors.Is(err, ErrNotStarted) {
+		return err
+	}
+	return nil
+}
+
+// SetMessageHandler 设置自动投递处理器
+func (s *Service) SetMessageHandler(handler interfaces.MailboxHandler) {
+	s.handlerMu.Lock()
+	s.handler = handler
+	s.handlerMu.Unlock()
+
+	// 已连接的信箱节点立即拉取一次
+	if handler == nil || !s.isStarted() {
+		return
+	}
+	if network := s.host.Network(); network != nil {
+		for _, peer := range s.config.MailboxPeers {
+			if network.Connectedness(peer) == interfaces.Connected {
+				s.drainAsync(peer)
+			}
+		}
+	}
+}
+
+// ════════════════════════════════════════════════════════════════════════════
+//                              客户端
+// ════════════════════════════════════════════════════════════════════════════
+
+// Deposit 向信箱节点投递发往 recipient 的消息
+func (s *Service) Deposit(ctx context.Context, mailboxPeer, recipient string, data []byte, ttl time.Duration) (string, error) {
+	if !s.isStarted() {
+		return "", ErrNotStarted
+	}
+	if recipient == "" || recipient == s.host.ID() {
+		return "", ErrInvalidRecipient
+	}
+	if !s.realm.IsMember(mailboxPeer) {
+		return "", fmt.Errorf("%w: mailbox %s", ErrNotRealmMember, log.TruncateID(mailboxPeer, 8))
+	}
+
+	pub, err := s.recipientKey(recipient)
+	if err != nil {
+		return "", err
+	}
+	priv, err := s.localKey()
+	if err != nil {
+		return "", err
+	}
+	aad := sealAAD(s.realmID, s.host.ID(), recipient)
+	body, err := signBody(priv, aad, data)
+	if err != nil {
+		return "", fmt.Errorf("mailbox: sign: %w", err)
+	}
+	sealed, err := crypto.SealEd25519(pub, body, aad)
+	if err != nil {
+		return "", fmt.Errorf("mailbox: seal: %w", err)
+	}
+	if s.config.MaxMessageSize > 0 && len(sealed) > s.config.MaxMessageSize {
+		return "", ErrMessageTooLarge
+	}
+
+	resp, err := s.roundTrip(ctx, mailboxPeer, &request{
+		Op:       opDeposit,
+		To:       recipient,
+		Payload:  sealed,
+		TTLNanos: int64(ttl),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	logger.Debug("消息已投递到信箱",
+		"mailbox", log.TruncateID(mailboxPeer, 8),
+		"recipient", log.TruncateID(recipient, 8),
+		"id", resp.ID)
+	return resp.ID, nil
+}
+
+// Fetch 从信箱节点拉取发给本节点的消息
+//
+// 无法解密或发送方签名无效的消息（密钥不匹配、被篡改或伪造发送方）
+// 不会返回给调用方，并由 Fetch 直接确认删除，避免垃圾消息占满
+// 每次拉取的配额导致后续消息永远取不到。
+func (s *Service) Fetch(ctx context.Context, mailboxPeer string) ([]*interfaces.MailboxMessage, error) {
+	msgs, _, err := s.fetch(ctx, mailboxPeer)
+	return msgs, err
+}
+
+// fetch 拉取一批消息
+//
+// 同时返回本批已处理的条数：有效消息数加上成功丢弃的无效消息数，
+// 用于判断是否还有下一批。
+func (s *Service) fetch(ctx context.Context, mailboxPeer string) ([]*interfaces.MailboxMessage, int, error) {
+	if !s.isStarted() {
+		return nil, 0, ErrNotStarted
+	}
+
+	priv, err := s.localKey()
+	if err != nil {
+		return nil, 0, err
+	}
+	rawPriv, err := priv.Raw()
+	if err != nil {
+		return nil, 0, ErrNoLocalKey
+	}
+
+	resp, err := s.roundTrip(ctx, mailboxPeer, &request{Op: opFetch, Limit: s.config.FetchLimit})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	localID := s.host.ID()
+	msgs := make([]*interfaces.MailboxMessage, 0, len(resp.Envelopes))
+	var rejected []string
+	for _, env := range resp.Envelopes {
+		if env == nil || env.To != localID {
+			continue
+		}
+		aad := sealAAD(s.realmID, env.From, env.To)
+		data, err := crypto.OpenEd25519(rawPriv, env.Payload, aad)
+		if err == nil {
+			data, err = verifyBody(env.From, aad, data)
+		}
+		if err != nil {
+			logger.Warn("信箱消息校验失败，丢弃",
+				"mailbox", log.TruncateID(mailboxPeer, 8),
+				"from", log.TruncateID(env.From, 8),
+				"id", env.ID,
+				"error", err)
+			rejected = append(rejected, env.ID)
+			continue
+		}
+		msgs = append(msgs, &interfaces.MailboxMessage{
+			ID:        env.ID,
+			From:      env.From,
+			Data:      data,
+			StoredAt:  time.Unix(0, env.StoredAt),
+			ExpiresAt: time.Unix(0, env.ExpiresAt),
+		})
+	}
+
+	// 尽力删除无效消息，失败时下次拉取会重试
+	handled := len(msgs)
+	if len(rejected) > 0 {
+		if err := s.Ack(ctx, mailboxPeer, rejected); err != nil {
+			logger.Debug("丢弃无效信箱消息失败", "mailbox", log.TruncateID(mailboxPeer, 8), "error", err)
+		} else {
+			handled += len(rejected)
+		}
+	}
+
+	return msgs, handled, nil
+}
+
+// Ack 确认消息已处理
+func (s *Service) Ack(ctx context.Context, mailboxPeer string, ids []string) error {
+	if !s.isStarted() {
+		return ErrNotStarted
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err := s.roundTrip(ctx, mailboxPeer, &request{Op: opAck, IDs: ids})
+	return err
+}
+
+// roundTrip 向信箱节点发送一次请求并读取响应
+func (s *Service) roundTrip(ctx context.Context, mailboxPeer string, req *request) (*response, error) {
+	if s.config.RequestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.config.RequestTimeout)
+		defer cancel()
+	}
+
+	stream, err := s.host.NewStream(ctx, mailboxPeer, s.protocolID())
+	if err != nil {
+		return nil, fmt.Errorf("mailbox: open stream: %w", err)
+	}
+	defer stream.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = stream.SetDeadline(deadline)
+	}
+
+	if err := writeFrame(stream, req); err != nil {
+		return nil, fmt.Errorf("mailbox: write request: %w", err)
+	}
+
+	var resp response
+	if err := readFrame(stream, &resp); err != nil {
+		return nil, fmt.Errorf("mailbox: read response: %w", err)
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("%w: %s", ErrRemote, resp.Error)
+	}
+	return &resp, nil
+}
+
+// recipientKey 获取接收方 Ed25519 公钥
+func (s *Service) recipientKey(recipient string) ([]byte, error) {
+	ps := s.host.Peerstore()
+	if ps == nil {
+		return nil, ErrNoRecipientKey
+	}
+	pub, err := ps.PubKey(types.PeerID(recipient))
+	if err != nil || pub == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoRecipientKey, log.TruncateID(recipient, 8))
+	}
+	if pub.Type() != interfaces.KeyTypeEd25519 {
+		return nil, ErrUnsupportedKeyType
+	}
+	return pub.Raw()
+}
+
+// localKey 获取本地 Ed25519 身份私钥
+func (s *Service) localKey() (interfaces.PrivateKey, error) {
+	ps := s.host.Peerstore()
+	if ps == nil {
+		return nil, ErrNoLocalKey
+	}
+	priv, err := ps.PrivKey(types.PeerID(s.host.ID()))
+	if err != nil || priv == nil {
+		return nil, ErrNoLocalKey
+	}
+	if priv.Type() != interfaces.KeyTypeEd25519 {
+		return nil, ErrUnsupportedKeyType
+	}
+	return priv, nil
+}
+
+// ════════════════════════════════════════════════════════════════════════════
+//                              自动拉取
+// ════════════════════════════════════════════════════════════════════════════
+
+// subscribeConnections 订阅连接事件，连接到信箱节点时自动拉取
+func (s *Service) subscribeConnections() {
+	eb := s.host.EventBus()
+	if eb == nil {
+		logger.Debug("EventBus 不可用，信箱不会自动拉取")
+		return
+	}
+
+	sub, err := eb.Subscribe(new(types.EvtPeerConnected))
+	if err != nil {
+		logger.Warn("订阅连接事件失败", "error", err)
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer sub.Close()
+
+		for {
+			select {
+			case <-s.ctx.Done():
+				return
+			case evt, ok := <-sub.Out():
+				if !ok {
+					return
+				}
+				var peerID string
+				switch e := evt.(type) {
+				case *types.EvtPeerConnected:
+					peerID = string(e.PeerID)
+				case types.EvtPeerConnected:
+					peerID = string(e.PeerID)
+				}
+				if s.isMailboxPeer(peerID) {
+					s.drainAsync(peerID)
+				}
+			}
+		}
+	}()
+}
+
+// isMailboxPeer 检查是否为配置的信箱节点
+func (s *Service) isMailboxPeer(peerID string) bool {
+	if peerID == "" {
+		return false
+	}
+	for _, p := range s.config.MailboxPeers {
+		if p == peerID {
+			return true
+		}
+	}
+	return false
+}
+
+// drainAsync 异步拉取信箱节点上的全部消息，投递给处理器并确认
+func (s *Service) drainAsync(mailboxPeer string) {
+	s.handlerMu.RLock()
+	handler := s.handler
+	s.handlerMu.RUnlock()
+	if handler == nil {
+		return
+	}
+	if _, busy := s.draining.LoadOrStore(mailboxPeer, struct{}{}); busy {
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer s.draining.Delete(mailboxPeer)
+
+		for s.ctx.Err() == nil {
+			msgs, n, err := s.fetch(s.ctx, mailboxPeer)
+			if err != nil {
+				logger.Debug("拉取信箱失败", "mailbox", log.TruncateID(mailboxPeer, 8), "error", err)
+				return
+			}
+
+			if len(msgs) > 0 {
+				ids := make([]string, 0, len(msgs))
+				for _, msg := range msgs {
+					handler(msg)
+					ids = append(ids, msg.ID)
+				}
+				if err := s.Ack(s.ctx, mailboxPeer, ids); err != nil {
+					logger.Debug("确认信箱消息失败", "mailbox", log.TruncateID(mailboxPeer, 8), "error", err)
+					return
+				}
+				logger.Debug("已投递信箱消息", "mailbox", log.TruncateID(mailboxPeer, 8), "count", len(ids))
+			}
+
+			// 按本批处理条数判断是否还有下一批：
+			// 整批都是无效消息时 msgs 为空，但它们已被丢弃，仍需继续拉取
+			if n == 0 || n < s.config.FetchLimit {
+				return
+			}
+		}
+	}()
+}
+
+// ════════════════════════════════════════════════════════════════════════════
+//                              服务端
+// ════════════════════════════════════════════════════════════════════════════
+
+// handleStream 处理信箱请求
+func (s *Service) handleStream(stream interfaces.Stream) {
+	defer stream.Close()
+
+	// 非成员在读取请求前即被拒绝
+	remote := remotePeer(stream)
+	if remote == "" || !s.realm.IsMember(remote) {
+		logger.Debug("拒绝非成员的信箱请求", "from", log.TruncateID(remote, 8))
+		stream.Reset()
+		return
+	}
+
+	_ = stream.SetDeadline(time.Now().Add(s.config.RequestTimeout))
+
+	var req request
+	if err := readFrame(stream, &req); err != nil {
+		return
+	}
+
+	resp := s.serve(remote, &req)
+	_ = writeFrame(stream, resp)
+}
+
+// serve 处理单个请求
+//
+// remote 是经过安全握手认证的连接身份，用作发送方（deposit）
+// 或接收方（fetch/ack），客户端无法冒充其他节点。
+func (s *Service) serve(remote string, req *request) *response {
+	if remote == "" || !s.realm.IsMember(remote) {
+		return &response{Error: ErrNotRealmMember.Error()}
+	}
+
+	now := time.Now()
+	switch req.Op {
+	case opDeposit:
+		if req.To == "" || strings.Contains(req.To, "/") || req.To == remote {
+			return &response{Error: ErrInvalidRecipient.Error()}
+		}
+		if len(req.Payload) == 0 {
+			return &response{Error: ErrInvalidMessage.Error()}
+		}
+		if s.config.MaxMessageSize > 0 && len(req.Payload) > s.config.MaxMessageSize {
+			return &response{Error: ErrMessageTooLarge.Error()}
+		}
+
+		env := &envelope{
+			ID:        newMessageID(),
+			From:      remote,
+			To:        req.To,
+			Payload:   req.Payload,
+			StoredAt:  now.UnixNano(),
+			ExpiresAt: now.Add(s.clampTTL(time.Duration(req.TTLNanos))).UnixNano(),
+		}
+		if err := s.store.Put(env); err != nil {
+			return &response{Error: err.Error()}
+		}
+		return &response{ID: env.ID}
+
+	case opFetch:
+		limit := req.Limit
+		if limit <= 0 || limit > s.config.FetchLimit {
+			limit = s.config.FetchLimit
+		}
+		envs, err := s.store.List(remote, limit, now)
+		if err != nil {
+			return &response{Error: err.Error()}
+		}
+		return &response{Envelopes: envs}
+
+	case opAck:
+		acked, err := s.store.Ack(remote, req.IDs)
+		if err != nil {
+			return &response{Error: err.Error()}
+		}
+		return &response{Acked: acked}
+
+	default:
+		return &response{Error: ErrInvalidMessage.Error()}
+	}
+}
+
+// clampTTL 应用默认 TTL 和最大 TTL
+func (s *Service) clampTTL(ttl time.Duration) time.Duration {
+	if ttl <= 0 {
+		ttl = s.config.DefaultTTL
+	}
+	if s.config.MaxTTL > 0 && ttl > s.config.MaxTTL {
+		ttl = s.config.MaxTTL
+	}
+	return ttl
+}
+
+// gcLoop 定期清理过期消息
+func (s *Service) gcLoop() {
+	defer s.wg.Done()
+
+	interval := s.config.GCInterval
+	if interval <= 0 {
+		interval = DefaultConfig().GCInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case now := <-ticker.C:
+			if n, err := s.store.Expire(now); err != nil {
+				logger.Warn("清理过期信箱消息失败", "error", err)
+			} else if n > 0 {
+				logger.Debug("已清理过期信箱消息", "count", n)
+			}
+		}
+	}
+}
+
+// isStarted 检查服务是否已启动
+func (s *Service) isStarted() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.started
+}
+
+// remotePeer 从流获取远端节点 ID
+func remotePeer(stream interfaces.Stream) string {
+	conn := stream.Conn()
+	if conn == nil {
+		return ""
+	}
+	return string(conn.RemotePeer())
+}
+
+// newMessageID 生成按时间有序的消息 ID
+func newMessageID() string {
+	if id, err := uuid.NewV7(); err == nil {
+		return id.String()
+	}
+	return uuid.New().String()
+}
@@ -0,0 +1,368 @@
+package mailbox
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dep2p/go-dep2p/internal/core/identity"
+	"github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/crypto"
+	"github.com/dep2p/go-dep2p/pkg/types"
+	"github.com/dep2p/go-dep2p/tests/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pipeStream 基于 net.Pipe 的流，用于连接两个测试节点
+type pipeStream struct {
+	pipe     net.Conn
+	conn     interfaces.Connection
+	protocol string
+}
+
+func (s *pipeStream) Read(p []byte) (int, error)         { return s.pipe.Read(p) }
+func (s *pipeStream) Write(p []byte) (int, error)        { return s.pipe.Write(p) }
+func (s *pipeStream) Close() error                       { return s.pipe.Close() }
+func (s *pipeStream) SetDeadline(t time.Time) error      { return s.pipe.SetDeadline(t) }
+func (s *pipeStream) SetReadDeadline(t time.Time) error  { return s.pipe.SetReadDeadline(t) }
+func (s *pipeStream) SetWriteDeadline(t time.Time) error { return s.pipe.SetWriteDeadline(t) }
+func (s *pipeStream) CloseWrite() error                  { return nil }
+func (s *pipeStream) CloseRead() error                   { return nil }
+func (s *pipeStream) Reset() error                       { return s.pipe.Close() }
+func (s *pipeStream) Protocol() string                   { return s.protocol }
+func (s *pipeStream) SetProtocol(p string)               { s.protocol = p }
+func (s *pipeStream) Conn() interfaces.Connection        { return s.conn }
+func (s *pipeStream) IsClosed() bool                     { return false }
+func (s *pipeStream) Stat() types.StreamStat             { return types.StreamStat{} }
+func (s *pipeStream) State() types.StreamState           { return types.StreamState(0) }
+
+// testNet 测试网络：按 PeerID 路由流到目标节点的处理器
+type testNet struct {
+	mu       sync.Mutex
+	handlers map[string]map[string]interfaces.StreamHandler
+}
+
+type testNode struct {
+	id   string
+	host *mocks.MockHost
+	ps   *mocks.MockPeerstore
+}
+
+func (n *testNet) addNode(t *testing.T) *testNode {
+	t.Helper()
+
+	ident, err := identity.Generate()
+	require.NoError(t, err)
+	id := ident.PeerID()
+
+	ps := mocks.NewMockPeerstore()
+	require.NoError(t, ps.AddPrivKey(types.PeerID(id), ident.PrivateKey()))
+
+	host := mocks.NewMockHost(id)
+	host.PeerstoreFunc = func() interfaces.Peerstore { return ps }
+	host.SetStreamHandlerFunc = func(protocolID string, handler interfaces.StreamHandler) {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		if n.handlers[id] == nil {
+			n.handlers[id] = make(map[string]interfaces.StreamHandler)
+		}
+		n.handlers[id][protocolID] = handler
+	}
+	host.RemoveStreamHandlerFunc = func(protocolID string) {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		delete(n.handlers[id], protocolID)
+	}
+	host.NewStreamFunc = func(_ context.Context, peerID string, protocolIDs ...string) (interfaces.Stream, error) {
+		n.mu.Lock()
+		handler := n.handlers[peerID][protocolIDs[0]]
+		n.mu.Unlock()
+		if handler == nil {
+			return nil, assert.AnError
+		}
+
+		local, remote := net.Pipe()
+		go handler(&pipeStream{
+			pipe:     remote,
+			conn:     mocks.NewMockConnection(types.PeerID(peerID), types.PeerID(id)),
+			protocol: protocolIDs[0],
+		})
+		return &pipeStream{
+			pipe:     local,
+			conn:     mocks.NewMockConnection(types.PeerID(id), types.PeerID(peerID)),
+			protocol: protocolIDs[0],
+		}, nil
+	}
+
+	return &testNode{id: id, host: host, ps: ps}
+}
+
+// learnKey 让 a 获知 b 的公钥（模拟 identify 交换）
+func learnKey(t *testing.T, a, b *testNode) {
+	t.Helper()
+	priv, err := b.ps.PrivKey(types.PeerID(b.id))
+	require.NoError(t, err)
+	require.NoError(t, a.ps.AddPubKey(types.PeerID(b.id), priv.PublicKey()))
+}
+
+func TestService_DepositFetchAck(t *testing.T) {
+	tn := &testNet{handlers: make(map[string]map[string]interfaces.StreamHandler)}
+	server := tn.addNode(t)
+	alice := tn.addNode(t)
+	bob := tn.addNode(t)
+	learnKey(t, alice, bob)
+
+	realm := mocks.NewMockRealm("realm-1")
+	realm.MemberList = []string{server.id, alice.id, bob.id}
+
+	ctx := context.Background()
+	serverSvc, err := NewForRealm(server.host, realm, newTestEngine(t), WithServer(true))
+	require.NoError(t, err)
+	require.NoError(t, serverSvc.Start(ctx))
+	defer serverSvc.Stop(ctx)
+
+	aliceSvc, err := NewForRealm(alice.host, realm, nil)
+	require.NoError(t, err)
+	require.NoError(t, aliceSvc.Start(ctx))
+	defer aliceSvc.Stop(ctx)
+
+	bobSvc, err := NewForRealm(bob.host, realm, nil)
+	require.NoError(t, err)
+	require.NoError(t, bobSvc.Start(ctx))
+	defer bobSvc.Stop(ctx)
+
+	id, err := aliceSvc.Deposit(ctx, server.id, bob.id, []byte("hello bob"), time.Hour)
+	require.NoError(t, err)
+	require.NotEmpty(t, id)
+
+	// 信箱节点只保存密文
+	envs, err := serverSvc.store.List(bob.id, 0, time.Now())
+	require.NoError(t, err)
+	require.Len(t, envs, 1)
+	assert.NotContains(t, string(envs[0].Payload), "hello bob")
+	assert.Equal(t, alice.id, envs[0].From)
+
+	msgs, err := bobSvc.Fetch(ctx, server.id)
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	assert.Equal(t, id, msgs[0].ID)
+	assert.Equal(t, alice.id, msgs[0].From)
+	assert.Equal(t, []byte("hello bob"), msgs[0].Data)
+
+	// alice 拉取不到 bob 的消息，也无法替 bob 确认
+	aliceMsgs, err := aliceSvc.Fetch(ctx, server.id)
+	require.NoError(t, err)
+	assert.Empty(t, aliceMsgs)
+	require.NoError(t, aliceSvc.Ack(ctx, server.id, []string{id}))
+	assert.Equal(t, 1, serverSvc.store.Pending(bob.id))
+
+	require.NoError(t, bobSvc.Ack(ctx, server.id, []string{id}))
+	assert.Equal(t, 0, serverSvc.store.Pending(bob.id))
+}
+
+func TestService_RejectsNonMembersAndUnknownKeys(t *testing.T) {
+	tn := &testNet{handlers: make(map[string]map[string]interfaces.StreamHandler)}
+	server := tn.addNode(t)
+	alice := tn.addNode(t)
+	bob := tn.addNode(t)
+	mallory := tn.addNode(t)
+	learnKey(t, mallory, bob)
+
+	realm := mocks.NewMockRealm("realm-1")
+	realm.MemberList = []string{server.id, alice.id, bob.id}
+
+	ctx := context.Background()
+	serverSvc, err := NewForRealm(server.host, realm, newTestEngine(t), WithServer(true))
+	require.NoError(t, err)
+	require.NoError(t, serverSvc.Start(ctx))
+	defer serverSvc.Stop(ctx)
+
+	aliceSvc, err := NewForRealm(alice.host, realm, nil)
+	require.NoError(t, err)
+	require.NoError(t, aliceSvc.Start(ctx))
+	defer aliceSvc.Stop(ctx)
+
+	// 未知接收方公钥
+	_, err = aliceSvc.Deposit(ctx, server.id, bob.id, []byte("x"), 0)
+	assert.ErrorIs(t, err, ErrNoRecipientKey)
+
+	// 非成员被服务端拒绝（客户端使用另一个 Realm 视图绕过本地检查）
+	malloryRealm := mocks.NewMockRealm("realm-1")
+	malloryRealm.MemberList = []string{server.id, bob.id, mallory.id}
+	mallorySvc, err := NewForRealm(mallory.host, malloryRealm, nil)
+	require.NoError(t, err)
+	require.NoError(t, mallorySvc.Start(ctx))
+	defer mallorySvc.Stop(ctx)
+
+	// 服务端在读取请求前重置流
+	_, err = mallorySvc.Deposit(ctx, server.id, bob.id, []byte("spam"), 0)
+	assert.Error(t, err)
+	assert.Equal(t, 0, serverSvc.store.Pending(bob.id))
+
+	_, err = mallorySvc.Fetch(ctx, server.id)
+	assert.Error(t, err)
+
+	// serve 自身同样校验成员身份
+	resp := serverSvc.serve(mallory.id, &request{Op: opFetch})
+	assert.Equal(t, ErrNotRealmMember.Error(), resp.Error)
+}
+
+func TestService_ClampTTL(t *testing.T) {
+	realm := mocks.NewMockRealm("realm-1")
+	svc, err := NewForRealm(mocks.NewMockHost("a"), realm, nil, WithTTL(time.Hour, 2*time.Hour))
+	require.NoError(t, err)
+
+	assert.Equal(t, time.Hour, svc.clampTTL(0))
+	assert.Equal(t, 30*time.Minute, svc.clampTTL(30*time.Minute))
+	assert.Equal(t, 2*time.Hour, svc.clampTTL(48*time.Hour))
+}
+
+func TestNewForRealm_ServerRequiresEngine(t *testing.T) {
+	_, err := NewForRealm(mocks.NewMockHost("a"), mocks.NewMockRealm("r"), nil, WithServer(true))
+	assert.ErrorIs(t, err, ErrEngineRequired)
+
+	_, err = NewForRealm(nil, mocks.NewMockRealm("r"), nil)
+	assert.ErrorIs(t, err, ErrNilHost)
+}
+
+func TestService_RejectsForgedSender(t *testing.T) {
+	tn := &testNet{handlers: make(map[string]map[string]interfaces.StreamHandler)}
+	server := tn.addNode(t)
+	alice := tn.addNode(t)
+	bob := tn.addNode(t)
+	mallory := tn.addNode(t)
+
+	realm := mocks.NewMockRealm("realm-1")
+	realm.MemberList = []string{server.id, alice.id, bob.id, mallory.id}
+
+	ctx := context.Background()
+	serverSvc, err := NewForRealm(server.host, realm, newTestEngine(t), WithServer(true))
+	require.NoError(t, err)
+	require.NoError(t, serverSvc.Start(ctx))
+	defer serverSvc.Stop(ctx)
+
+	bobSvc, err := NewForRealm(bob.host, realm, nil)
+	require.NoError(t, err)
+	require.NoError(t, bobSvc.Start(ctx))
+	defer bobSvc.Stop(ctx)
+
+	bobPriv, err := bob.ps.PrivKey(types.PeerID(bob.id))
+	require.NoError(t, err)
+	bobPub, err := bobPriv.PublicKey().Raw()
+	require.NoError(t, err)
+	malloryPriv, err := mallory.ps.PrivKey(types.PeerID(mallory.id))
+	require.NoError(t, err)
+	alicePriv, err := alice.ps.PrivKey(types.PeerID(alice.id))
+	require.NoError(t, err)
+	alicePub, err := alicePriv.PublicKey().Raw()
+	require.NoError(t, err)
+
+	// 信箱节点（或与其合谋的成员）直接写入冒充 alice 的消息
+	aad := sealAAD("realm-1", alice.id, bob.id)
+	forge := func(body []byte) {
+		sealed, err := crypto.SealEd25519(bobPub, body, aad)
+		require.NoError(t, err)
+		env := newTestEnvelope(alice.id, bob.id, time.Hour)
+		env.Payload = sealed
+		require.NoError(t, serverSvc.store.Put(env))
+	}
+
+	// 1. 只加密不签名
+	forge([]byte("unsigned"))
+
+	// 2. mallory 用自己的身份签名
+	body, err := signBody(malloryPriv, aad, []byte("signed by mallory"))
+	require.NoError(t, err)
+	forge(body)
+
+	// 3. 附上 alice 的公钥，但签名来自 mallory
+	copy(body[:len(alicePub)], alicePub)
+	forge(body)
+
+	msgs, err := bobSvc.Fetch(ctx, server.id)
+	require.NoError(t, err)
+	assert.Empty(t, msgs)
+}
+
+func TestVerifyBody(t *testing.T) {
+	ident, err := identity.Generate()
+	require.NoError(t, err)
+
+	aad := sealAAD("realm-1", ident.PeerID(), "bob")
+	body, err := signBody(ident.PrivateKey(), aad, []byte("hello"))
+	require.NoError(t, err)
+
+	data, err := verifyBody(ident.PeerID(), aad, body)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("hello"), data)
+
+	// AAD 不一致（被挪用到其他接收方）
+	_, err = verifyBody(ident.PeerID(), sealAAD("realm-1", ident.PeerID(), "carol"), body)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	// From 与签名公钥不一致
+	_, err = verifyBody("mallory", aad, body)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	// 截断
+	_, err = verifyBody(ident.PeerID(), aad, body[:10])
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestService_DrainSkipsPastUndecryptable(t *testing.T) {
+	tn := &testNet{handlers: make(map[string]map[string]interfaces.StreamHandler)}
+	server := tn.addNode(t)
+	alice := tn.addNode(t)
+	bob := tn.addNode(t)
+	learnKey(t, alice, bob)
+
+	realm := mocks.NewMockRealm("realm-1")
+	realm.MemberList = []string{server.id, alice.id, bob.id}
+
+	ctx := context.Background()
+	serverSvc, err := NewForRealm(server.host, realm, newTestEngine(t), WithServer(true), WithQuotas(100, 100))
+	require.NoError(t, err)
+	require.NoError(t, serverSvc.Start(ctx))
+	defer serverSvc.Stop(ctx)
+
+	aliceSvc, err := NewForRealm(alice.host, realm, nil)
+	require.NoError(t, err)
+	require.NoError(t, aliceSvc.Start(ctx))
+	defer aliceSvc.Stop(ctx)
+
+	bobSvc, err := NewForRealm(bob.host, realm, nil)
+	require.NoError(t, err)
+	bobSvc.config.FetchLimit = 3
+	require.NoError(t, bobSvc.Start(ctx))
+	defer bobSvc.Stop(ctx)
+
+	// 两整批无法解密的垃圾消息排在有效消息之前
+	for i := 0; i < 2*bobSvc.config.FetchLimit; i++ {
+		require.NoError(t, serverSvc.store.Put(newTestEnvelope(alice.id, bob.id, time.Hour)))
+	}
+	id, err := aliceSvc.Deposit(ctx, server.id, bob.id, []byte("hello bob"), time.Hour)
+	require.NoError(t, err)
+
+	// 单次 Fetch 只拿到垃圾消息：不返回，但会从信箱删除
+	msgs, err := bobSvc.Fetch(ctx, server.id)
+	require.NoError(t, err)
+	assert.Empty(t, msgs)
+	assert.Equal(t, bobSvc.config.FetchLimit+1, serverSvc.store.Pending(bob.id))
+
+	// 自动拉取越过剩余垃圾消息，投递有效消息
+	got := make(chan *interfaces.MailboxMessage, 1)
+	bobSvc.SetMessageHandler(func(msg *interfaces.MailboxMessage) { got <- msg })
+	bobSvc.drainAsync(server.id)
+
+	select {
+	case msg := <-got:
+		assert.Equal(t, id, msg.ID)
+		assert.Equal(t, []byte("hello bob"), msg.Data)
+	case <-time.After(5 * time.Second):
+		t.Fatal("有效消息未投递")
+	}
+	require.Eventually(t, func() bool { return serverSvc.store.Pending(bob.id) == 0 }, 5*time.Second, 10*time.Millisecond)
+}
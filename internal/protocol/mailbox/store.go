@@ -0,0 +1,221 @@
+// Package mailbox 实现离线消息的存储转发（信箱）服务
+package mailbox
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dep2p/go-dep2p/internal/core/storage/kv"
+)
+
+// Store 信箱持久化存储
+//
+// 键格式: m/<recipient>/<msgID>
+// 值格式: JSON 序列化的 envelope
+//
+// 配额计数保存在内存中，启动时从存储重建。
+type Store struct {
+	kv     *kv.Store
+	config *Config
+
+	mu          sync.Mutex
+	bySender    map[string]int // sender -> 待投递数
+	byRecipient map[string]int // recipient -> 待投递数
+}
+
+// NewStore 创建信箱存储并从持久化数据重建计数
+//
+// 参数:
+//   - store: KV 存储实例（已带 Realm 前缀）
+//   - config: 配额配置
+func NewStore(store *kv.Store, config *Config) (*Store, error) {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	s := &Store{
+		kv:          store,
+		config:      config,
+		bySender:    make(map[string]int),
+		byRecipient: make(map[string]int),
+	}
+
+	if err := s.load(time.Now()); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// load 扫描存储，重建计数并删除已过期消息
+func (s *Store) load(now time.Time) error {
+	var expired [][]byte
+
+	err := s.kv.PrefixScan([]byte("m/"), func(key, value []byte) bool {
+		var env envelope
+		if err := json.Unmarshal(value, &env); err != nil {
+			expired = append(expired, append([]byte(nil), key...))
+			return true
+		}
+		if env.ExpiresAt <= now.UnixNano() {
+			expired = append(expired, append([]byte(nil), key...))
+			return true
+		}
+		s.bySender[env.From]++
+		s.byRecipient[env.To]++
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.deleteKeys(expired)
+}
+
+// Put 存入消息，超出配额时返回错误
+func (s *Store) Put(env *envelope) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.config.MaxPerSender > 0 && s.bySender[env.From] >= s.config.MaxPerSender {
+		return ErrSenderQuotaExceeded
+	}
+	if s.config.MaxPerRecipient > 0 && s.byRecipient[env.To] >= s.config.MaxPerRecipient {
+		return ErrRecipientQuotaExceeded
+	}
+
+	if err := s.kv.PutJSON(messageKey(env.To, env.ID), env); err != nil {
+		return err
+	}
+
+	s.bySender[env.From]++
+	s.byRecipient[env.To]++
+	return nil
+}
+
+// List 返回接收方未过期的消息（按 ID 即存入时间排序）
+func (s *Store) List(recipient string, limit int, now time.Time) ([]*envelope, error) {
+	var result []*envelope
+
+	err := s.kv.PrefixScan(recipientPrefix(recipient), func(_, value []byte) bool {
+		var env envelope
+		if err := json.Unmarshal(value, &env); err != nil {
+			return true
+		}
+		if env.ExpiresAt <= now.UnixNano() {
+			return true
+		}
+		result = append(result, &env)
+		return limit <= 0 || len(result) < limit
+	})
+
+	return result, err
+}
+
+// Ack 删除接收方已确认的消息，返回实际删除数
+//
+// 只删除属于 recipient 的消息，其他节点无法确认他人的消息。
+// 配额在删除写入成功后才释放，写入失败时消息和配额保持一致。
+func (s *Store) Ack(recipient string, ids []string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var acked []*envelope
+	batch := s.kv.NewBatch()
+	for _, id := range ids {
+		if id == "" || strings.Contains(id, "/") {
+			continue
+		}
+		key := messageKey(recipient, id)
+
+		var env envelope
+		if err := s.kv.GetJSON(key, &env); err != nil {
+			continue
+		}
+		batch.Delete(key)
+		acked = append(acked, &env)
+	}
+
+	if len(acked) == 0 {
+		return 0, nil
+	}
+	if err := batch.Write(); err != nil {
+		return 0, err
+	}
+	for _, env := range acked {
+		s.release(env)
+	}
+	return len(acked), nil
+}
+
+// Expire 删除所有已过期消息，返回删除数
+func (s *Store) Expire(now time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var keys [][]byte
+	var expired []*envelope
+	err := s.kv.PrefixScan([]byte("m/"), func(key, value []byte) bool {
+		var env envelope
+		if err := json.Unmarshal(value, &env); err != nil {
+			return true
+		}
+		if env.ExpiresAt <= now.UnixNano() {
+			keys = append(keys, append([]byte(nil), key...))
+			expired = append(expired, &env)
+		}
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// 删除成功后才释放配额
+	if err := s.deleteKeys(keys); err != nil {
+		return 0, err
+	}
+	for _, env := range expired {
+		s.release(env)
+	}
+	return len(keys), nil
+}
+
+// Pending 返回接收方待投递消息数
+func (s *Store) Pending(recipient string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.byRecipient[recipient]
+}
+
+// release 释放消息占用的配额（需持有锁）
+func (s *Store) release(env *envelope) {
+	if s.bySender[env.From]--; s.bySender[env.From] <= 0 {
+		delete(s.bySender, env.From)
+	}
+	if s.byRecipient[env.To]--; s.byRecipient[env.To] <= 0 {
+		delete(s.byRecipient, env.To)
+	}
+}
+
+// deleteKeys 批量删除键
+func (s *Store) deleteKeys(keys [][]byte) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	batch := s.kv.NewBatch()
+	for _, key := range keys {
+		batch.Delete(key)
+	}
+	return batch.Write()
+}
+
+// recipientPrefix 接收方消息键前缀
+func recipientPrefix(recipient string) []byte {
+	return []byte("m/" + recipient + "/")
+}
+
+// messageKey 消息键
+func messageKey(recipient, id string) []byte {
+	return []byte("m/" + recipient + "/" + id)
+}
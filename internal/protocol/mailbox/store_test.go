@@ -0,0 +1,172 @@
+package mailbox
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dep2p/go-dep2p/internal/core/storage/engine"
+	"github.com/dep2p/go-dep2p/internal/core/storage/engine/badger"
+	"github.com/dep2p/go-dep2p/internal/core/storage/kv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEngine(t *testing.T) engine.InternalEngine {
+	t.Helper()
+
+	cfg := engine.DefaultConfig(filepath.Join(t.TempDir(), "mailbox.db"))
+	eng, err := badger.New(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { eng.Close() })
+	return eng
+}
+
+func newTestEnvelope(from, to string, ttl time.Duration) *envelope {
+	now := time.Now()
+	return &envelope{
+		ID:        newMessageID(),
+		From:      from,
+		To:        to,
+		Payload:   []byte("sealed"),
+		StoredAt:  now.UnixNano(),
+		ExpiresAt: now.Add(ttl).UnixNano(),
+	}
+}
+
+func TestStore_PutListAck(t *testing.T) {
+	store, err := NewStore(kv.New(newTestEngine(t), []byte("mailbox/r1/")), DefaultConfig())
+	require.NoError(t, err)
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		env := newTestEnvelope("alice", "bob", time.Hour)
+		require.NoError(t, store.Put(env))
+		ids = append(ids, env.ID)
+	}
+	require.NoError(t, store.Put(newTestEnvelope("alice", "carol", time.Hour)))
+
+	envs, err := store.List("bob", 0, time.Now())
+	require.NoError(t, err)
+	require.Len(t, envs, 3)
+	for i, env := range envs {
+		assert.Equal(t, ids[i], env.ID, "messages should be ordered by ID")
+	}
+
+	limited, err := store.List("bob", 2, time.Now())
+	require.NoError(t, err)
+	assert.Len(t, limited, 2)
+
+	// carol 不能确认 bob 的消息
+	acked, err := store.Ack("carol", ids)
+	require.NoError(t, err)
+	assert.Equal(t, 0, acked)
+
+	acked, err = store.Ack("bob", []string{ids[0], ids[1], "missing", "../x"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, acked)
+	assert.Equal(t, 1, store.Pending("bob"))
+	assert.Equal(t, 1, store.Pending("carol"))
+}
+
+func TestStore_Quotas(t *testing.T) {
+	store, err := NewStore(kv.New(newTestEngine(t), []byte("mailbox/r1/")), &Config{
+		MaxPerSender:    2,
+		MaxPerRecipient: 3,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put(newTestEnvelope("alice", "bob", time.Hour)))
+	require.NoError(t, store.Put(newTestEnvelope("alice", "bob", time.Hour)))
+	assert.ErrorIs(t, store.Put(newTestEnvelope("alice", "bob", time.Hour)), ErrSenderQuotaExceeded)
+
+	require.NoError(t, store.Put(newTestEnvelope("dave", "bob", time.Hour)))
+	assert.ErrorIs(t, store.Put(newTestEnvelope("erin", "bob", time.Hour)), ErrRecipientQuotaExceeded)
+
+	// 确认后释放配额
+	envs, err := store.List("bob", 1, time.Now())
+	require.NoError(t, err)
+	_, err = store.Ack("bob", []string{envs[0].ID})
+	require.NoError(t, err)
+	assert.NoError(t, store.Put(newTestEnvelope("erin", "bob", time.Hour)))
+}
+
+func TestStore_ExpireAndReload(t *testing.T) {
+	eng := newTestEngine(t)
+	prefix := []byte("mailbox/r1/")
+
+	store, err := NewStore(kv.New(eng, prefix), DefaultConfig())
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, store.Put(newTestEnvelope(fmt.Sprintf("s%d", i), "bob", time.Hour)))
+	}
+	require.NoError(t, store.Put(newTestEnvelope("s9", "bob", time.Millisecond)))
+	time.Sleep(5 * time.Millisecond)
+
+	// 过期消息不会被列出
+	envs, err := store.List("bob", 0, time.Now())
+	require.NoError(t, err)
+	assert.Len(t, envs, 2)
+
+	// 重新打开：计数从存储重建，过期消息被清理
+	reopened, err := NewStore(kv.New(eng, prefix), DefaultConfig())
+	require.NoError(t, err)
+	assert.Equal(t, 2, reopened.Pending("bob"))
+
+	n, err := reopened.Expire(time.Now().Add(2 * time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, 0, reopened.Pending("bob"))
+}
+
+// failingEngine 批量写入总是失败的存储引擎
+type failingEngine struct {
+	engine.InternalEngine
+	fail bool
+}
+
+func (e *failingEngine) NewBatch() engine.Batch {
+	return &failingBatch{Batch: e.InternalEngine.NewBatch(), engine: e}
+}
+
+type failingBatch struct {
+	engine.Batch
+	engine *failingEngine
+}
+
+func (b *failingBatch) Write() error {
+	if b.engine.fail {
+		return errors.New("write failed")
+	}
+	return b.Batch.Write()
+}
+
+func TestStore_FailedWriteKeepsQuota(t *testing.T) {
+	eng := &failingEngine{InternalEngine: newTestEngine(t)}
+	store, err := NewStore(kv.New(eng, []byte("mailbox/r1/")), &Config{MaxPerRecipient: 1})
+	require.NoError(t, err)
+
+	env := newTestEnvelope("alice", "bob", time.Hour)
+	require.NoError(t, store.Put(env))
+
+	// 删除失败：消息仍在，配额不释放
+	eng.fail = true
+	_, err = store.Ack("bob", []string{env.ID})
+	assert.Error(t, err)
+	assert.Equal(t, 1, store.Pending("bob"))
+	assert.ErrorIs(t, store.Put(newTestEnvelope("carol", "bob", time.Hour)), ErrRecipientQuotaExceeded)
+
+	_, err = store.Expire(time.Now().Add(2 * time.Hour))
+	assert.Error(t, err)
+	assert.Equal(t, 1, store.Pending("bob"))
+
+	// 恢复后确认成功，配额释放
+	eng.fail = false
+	acked, err := store.Ack("bob", []string{env.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 1, acked)
+	assert.Equal(t, 0, store.Pending("bob"))
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/compress"
 	pb "github.com/dep2p/go-dep2p/pkg/lib/proto/messaging"
 	"google.golang.org/protobuf/proto"
 )
@@ -150,49 +151,70 @@ func (c *Codec) DecodeResponse(data []byte) (*interfaces.Response, error) {
 
 // WriteRequest 将请求写入流
 func (c *Codec) WriteRequest(w io.Writer, req *interfaces.Request) error {
+	return c.WriteCompressedRequest(w, req, compress.None)
+}
+
+// WriteCompressedRequest 将请求按指定压缩算法写入流
+func (c *Codec) WriteCompressedRequest(w io.Writer, req *interfaces.Request, alg compress.Algorithm) error {
 	data, err := c.EncodeRequest(req)
 	if err != nil {
 		return err
 	}
-
-	// 写入长度前缀 (varint)
-	if err := writeVarint(w, uint64(len(data))); err != nil {
-		return fmt.Errorf("failed to write length: %w", err)
-	}
-
-	// 写入数据
-	if _, err := w.Write(data); err != nil {
-		return fmt.Errorf("failed to write data: %w", err)
-	}
-
-	return nil
+	return writeFrame(w, data, alg)
 }
 
 // ReadRequest 从流中读取请求
 func (c *Codec) ReadRequest(r io.Reader) (*interfaces.Request, error) {
-	// 读取长度前缀
-	length, err := readVarint(r)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read length: %w", err)
-	}
+	return c.ReadCompressedRequest(r, compress.None, 0)
+}
 
-	// 读取数据
-	data := make([]byte, length)
-	if _, err := io.ReadFull(r, data); err != nil {
-		return nil, fmt.Errorf("failed to read data: %w", err)
+// ReadCompressedRequest 从流中读取按指定算法压缩的请求
+//
+// maxSize 为解压后大小上限（仅对压缩帧生效）。
+func (c *Codec) ReadCompressedRequest(r io.Reader, alg compress.Algorithm, maxSize int) (*interfaces.Request, error) {
+	data, err := readFrame(r, alg, maxSize)
+	if err != nil {
+		return nil, err
 	}
-
 	return c.DecodeRequest(data)
 }
 
 // WriteResponse 将响应写入流
 func (c *Codec) WriteResponse(w io.Writer, resp *interfaces.Response) error {
+	return c.WriteCompressedResponse(w, resp, compress.None)
+}
+
+// WriteCompressedResponse 将响应按指定压缩算法写入流
+func (c *Codec) WriteCompressedResponse(w io.Writer, resp *interfaces.Response, alg compress.Algorithm) error {
 	data, err := c.EncodeResponse(resp)
 	if err != nil {
 		return err
 	}
+	return writeFrame(w, data, alg)
+}
+
+// ReadResponse 从流中读取响应
+func (c *Codec) ReadResponse(r io.Reader) (*interfaces.Response, error) {
+	return c.ReadCompressedResponse(r, compress.None, 0)
+}
 
-	// 写入长度前缀
+// ReadCompressedResponse 从流中读取按指定算法压缩的响应
+func (c *Codec) ReadCompressedResponse(r io.Reader, alg compress.Algorithm, maxSize int) (*interfaces.Response, error) {
+	data, err := readFrame(r, alg, maxSize)
+	if err != nil {
+		return nil, err
+	}
+	return c.DecodeResponse(data)
+}
+
+// writeFrame 写入长度前缀帧，按需压缩
+func writeFrame(w io.Writer, data []byte, alg compress.Algorithm) error {
+	data, err := compress.Compress(alg, data)
+	if err != nil {
+		return err
+	}
+
+	// 写入长度前缀 (varint)
 	if err := writeVarint(w, uint64(len(data))); err != nil {
 		return fmt.Errorf("failed to write length: %w", err)
 	}
@@ -205,21 +227,35 @@ func (c *Codec) WriteResponse(w io.Writer, resp *interfaces.Response) error {
 	return nil
 }
 
-// ReadResponse 从流中读取响应
-func (c *Codec) ReadResponse(r io.Reader) (*interfaces.Response, error) {
+// readFrame 读取长度前缀帧，按需解压
+//
+// 压缩帧的长度同样受 maxSize 约束，避免按声明长度分配过大内存。
+func readFrame(r io.Reader, alg compress.Algorithm, maxSize int) ([]byte, error) {
 	// 读取长度前缀
 	length, err := readVarint(r)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read length: %w", err)
 	}
 
+	if alg != compress.None {
+		if maxSize <= 0 {
+			maxSize = compress.DefaultMaxDecompressedSize
+		}
+		if length > uint64(maxSize) {
+			return nil, fmt.Errorf("%w: frame too large", compress.ErrTooLarge)
+		}
+	}
+
 	// 读取数据
 	data := make([]byte, length)
 	if _, err := io.ReadFull(r, data); err != nil {
 		return nil, fmt.Errorf("failed to read data: %w", err)
 	}
 
-	return c.DecodeResponse(data)
+	if alg == compress.None {
+		return data, nil
+	}
+	return compress.Decompress(alg, data, maxSize)
 }
 
 // convertMetadataToProto 转换 metadata 到 protobuf 格式
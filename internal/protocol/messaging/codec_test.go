@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/compress"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -221,3 +222,39 @@ func TestCodec_LargePayload(t *testing.T) {
 	assert.Equal(t, len(req.Data), len(decoded.Data))
 	assert.Equal(t, req.Data, decoded.Data)
 }
+
+func TestCodec_CompressedRoundTrip(t *testing.T) {
+	codec := NewCodec()
+	data := bytes.Repeat([]byte("compressible payload "), 512)
+
+	for _, alg := range []compress.Algorithm{compress.Zstd, compress.Snappy} {
+		t.Run(alg.String(), func(t *testing.T) {
+			plain := &bytes.Buffer{}
+			require.NoError(t, codec.WriteRequest(plain, &interfaces.Request{ID: "req", Data: data}))
+
+			buf := &bytes.Buffer{}
+			require.NoError(t, codec.WriteCompressedRequest(buf, &interfaces.Request{ID: "req", Data: data}, alg))
+			assert.Less(t, buf.Len(), plain.Len())
+
+			req, err := codec.ReadCompressedRequest(buf, alg, 0)
+			require.NoError(t, err)
+			assert.Equal(t, data, req.Data)
+
+			buf.Reset()
+			require.NoError(t, codec.WriteCompressedResponse(buf, &interfaces.Response{ID: "req", Data: data}, alg))
+			resp, err := codec.ReadCompressedResponse(buf, alg, 0)
+			require.NoError(t, err)
+			assert.Equal(t, data, resp.Data)
+		})
+	}
+}
+
+func TestCodec_CompressedSizeLimit(t *testing.T) {
+	codec := NewCodec()
+	buf := &bytes.Buffer{}
+	req := &interfaces.Request{ID: "bomb", Data: make([]byte, 1<<20)}
+	require.NoError(t, codec.WriteCompressedRequest(buf, req, compress.Zstd))
+
+	_, err := codec.ReadCompressedRequest(buf, compress.Zstd, 64<<10)
+	assert.ErrorIs(t, err, compress.ErrTooLarge)
+}
@@ -0,0 +1,232 @@
+// Package messaging 实现点对点消息传递协议
+package messaging
+
+import (
+	"container/list"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dep2p/go-dep2p/internal/core/storage/kv"
+	"github.com/dep2p/go-dep2p/pkg/interfaces"
+)
+
+// dedupCache 接收方按消息 ID 去重的缓存
+//
+// 持久化投递是至少一次语义，发送方可能因响应丢失而重发。缓存以
+// "发送方/消息 ID" 为键记录处理器的响应：窗口期内的重复请求直接
+// 返回缓存的响应，不再调用处理器；处理中的重复请求等待首个请求完成。
+//
+// 关联存储（attach）后，已完成的条目同时写入存储引擎，接收方重启后
+// 恢复去重窗口，避免重启前已处理的消息被再次交给处理器。
+type dedupCache struct {
+	window   time.Duration
+	capacity int
+
+	// kv 持久化存储（可选）
+	kv *kv.Store
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // 按创建时间排序，队首最旧
+}
+
+// dedupEntry 去重缓存条目
+type dedupEntry struct {
+	key     string
+	created time.Time
+	done    chan struct{}
+	resp    *interfaces.Response
+}
+
+// dedupRecord 持久化的去重条目
+type dedupRecord struct {
+	Key      string            `json:"key"`
+	Created  int64             `json:"created"`
+	ID       string            `json:"id"`
+	From     string            `json:"from"`
+	Data     []byte            `json:"data,omitempty"`
+	Error    string            `json:"error,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+func newDedupCache(window time.Duration, capacity int) *dedupCache {
+	return &dedupCache{
+		window:   window,
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// attach 关联持久化存储并加载去重窗口内的条目
+//
+// 超出窗口的持久化条目在加载时删除。
+func (c *dedupCache) attach(store *kv.Store) error {
+	now := time.Now()
+	var records []*dedupRecord
+	var stale [][]byte
+	var loadErr error
+	err := store.PrefixScan([]byte("d/"), func(key, value []byte) bool {
+		rec := &dedupRecord{}
+		if err := json.Unmarshal(value, rec); err != nil {
+			loadErr = err
+			return false
+		}
+		if now.Sub(time.Unix(0, rec.Created)) > c.window {
+			stale = append(stale, append([]byte(nil), key...))
+			return true
+		}
+		records = append(records, rec)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if loadErr != nil {
+		return loadErr
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Created < records[j].Created })
+
+	c.mu.Lock()
+	c.kv = store
+	for _, rec := range records {
+		if _, ok := c.entries[rec.Key]; ok {
+			continue
+		}
+		for c.order.Len() > 0 && c.order.Len() >= c.capacity {
+			stale = append(stale, c.removeFront())
+		}
+		entry := &dedupEntry{
+			key:     rec.Key,
+			created: time.Unix(0, rec.Created),
+			done:    make(chan struct{}),
+			resp:    rec.response(),
+		}
+		close(entry.done)
+		c.entries[rec.Key] = c.order.PushBack(entry)
+	}
+	c.mu.Unlock()
+
+	c.deleteKeys(stale)
+	return nil
+}
+
+// begin 查找或创建条目
+//
+// 返回的 dup 为 true 时表示重复请求，调用方通过 entry.response() 获取首次处理的响应。
+func (c *dedupCache) begin(key string) (entry *dedupEntry, dup bool) {
+	now := time.Now()
+
+	c.mu.Lock()
+	evicted := c.expire(now)
+	if elem, ok := c.entries[key]; ok {
+		c.mu.Unlock()
+		c.deleteKeys(evicted)
+		return elem.Value.(*dedupEntry), true
+	}
+
+	// 超出容量时淘汰最旧的条目
+	for c.order.Len() > 0 && c.order.Len() >= c.capacity {
+		evicted = append(evicted, c.removeFront())
+	}
+
+	entry = &dedupEntry{key: key, created: now, done: make(chan struct{})}
+	c.entries[key] = c.order.PushBack(entry)
+	c.mu.Unlock()
+
+	c.deleteKeys(evicted)
+	return entry, false
+}
+
+// finish 记录处理结果并唤醒等待的重复请求
+//
+// 关联了存储时先持久化再返回，调用方随后才向发送方回复响应。
+func (c *dedupCache) finish(entry *dedupEntry, resp *interfaces.Response) {
+	c.mu.Lock()
+	entry.resp = resp
+	store := c.kv
+	c.mu.Unlock()
+	close(entry.done)
+
+	if store == nil || resp == nil {
+		return
+	}
+	rec := &dedupRecord{
+		Key:      entry.key,
+		Created:  entry.created.UnixNano(),
+		ID:       resp.ID,
+		From:     resp.From,
+		Data:     resp.Data,
+		Metadata: resp.Metadata,
+	}
+	if resp.Error != nil {
+		rec.Error = resp.Error.Error()
+	}
+	if err := store.PutJSON(dedupKey(entry.key), rec); err != nil {
+		logger.Warn("持久化去重条目失败", "key", entry.key, "error", err)
+	}
+}
+
+// expire 清理超出去重窗口的条目，返回被清理的存储键（调用方持有 c.mu）
+func (c *dedupCache) expire(now time.Time) [][]byte {
+	var evicted [][]byte
+	for c.order.Len() > 0 {
+		if now.Sub(c.order.Front().Value.(*dedupEntry).created) <= c.window {
+			break
+		}
+		evicted = append(evicted, c.removeFront())
+	}
+	return evicted
+}
+
+// removeFront 移除最旧的条目，返回其存储键（调用方持有 c.mu）
+func (c *dedupCache) removeFront() []byte {
+	front := c.order.Front()
+	c.order.Remove(front)
+	key := front.Value.(*dedupEntry).key
+	delete(c.entries, key)
+	return dedupKey(key)
+}
+
+// deleteKeys 从持久化存储中删除条目（未关联存储时忽略）
+func (c *dedupCache) deleteKeys(keys [][]byte) {
+	c.mu.Lock()
+	store := c.kv
+	c.mu.Unlock()
+	if store == nil {
+		return
+	}
+	for _, key := range keys {
+		if err := store.Delete(key); err != nil {
+			logger.Debug("删除去重条目失败", "key", string(key), "error", err)
+		}
+	}
+}
+
+func dedupKey(key string) []byte {
+	return []byte("d/" + key)
+}
+
+// response 还原持久化的响应
+func (r *dedupRecord) response() *interfaces.Response {
+	resp := &interfaces.Response{
+		ID:        r.ID,
+		From:      r.From,
+		Data:      r.Data,
+		Timestamp: time.Unix(0, r.Created),
+		Metadata:  r.Metadata,
+	}
+	if r.Error != "" {
+		resp.Error = errors.New(r.Error)
+	}
+	return resp
+}
+
+// response 返回缓存的响应（条目完成后调用）
+func (e *dedupEntry) response() *interfaces.Response {
+	<-e.done
+	return e.resp
+}
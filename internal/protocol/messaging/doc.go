@@ -81,6 +81,20 @@
 //	    messaging.WithRetryDelay(time.Second),  // 设置重试延迟
 //	)
 //
+// # 持久化发件箱
+//
+// 启用发件箱（WithOutbox）并设置存储引擎（SetStorageEngine）后，
+// SendDurable 先将消息写入存储引擎再异步投递：
+//
+//	svc.OnDelivery(func(r interfaces.DeliveryReport) { ... })
+//	id, err := svc.SendDurable(ctx, peerID, "order", data,
+//	    interfaces.DurableSendOptions{IdempotencyKey: orderID})
+//
+// 投递失败按指数退避重试，与目标节点建立连接时立即重试，服务重启后
+// 从存储引擎恢复。请求携带持久化标记，接收方按"发送方/消息 ID"去重，
+// 重复投递返回首次处理的响应而不再调用处理器。接收方设置了存储引擎时
+// 去重窗口同样持久化，重启后不会重复处理窗口内已处理的消息。
+//
 // # 协议格式
 //
 // 协议 ID 格式: /dep2p/app/<realmID>/<protocol>/1.0.0
@@ -110,6 +124,8 @@
 //   - ErrTimeout: 请求超时
 //   - ErrStreamClosed: 流已关闭
 //   - ErrInvalidMessage: 无效的消息格式
+//   - ErrOutboxDisabled: 发件箱未启用
+//   - ErrOutboxFull: 发件箱已满
 //
 // # 性能特性
 //
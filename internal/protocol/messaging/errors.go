@@ -37,4 +37,10 @@ var (
 
 	// ErrHandlerAlreadyRegistered 处理器已注册
 	ErrHandlerAlreadyRegistered = errors.New("messaging: handler already registered")
+
+	// ErrOutboxDisabled 发件箱未启用
+	ErrOutboxDisabled = errors.New("messaging: outbox disabled")
+
+	// ErrOutboxFull 发件箱已满
+	ErrOutboxFull = errors.New("messaging: outbox full")
 )
@@ -1,7 +1,11 @@
 // Package messaging 实现点对点消息传递协议
 package messaging
 
-import "time"
+import (
+	"time"
+
+	"github.com/dep2p/go-dep2p/pkg/lib/compress"
+)
 
 // Config Messaging 服务配置
 type Config struct {
@@ -13,6 +17,36 @@ type Config struct {
 
 	// RetryDelay 重试延迟
 	RetryDelay time.Duration
+
+	// Compression 按协议启用的压缩算法（按偏好排序）
+	//
+	// 键为用户协议名（如 "chat"），未配置的协议不压缩。
+	// 双方都需为该协议启用压缩，否则回退到未压缩传输。
+	Compression map[string][]compress.Algorithm
+
+	// MaxDecompressedSize 解压后消息大小上限
+	MaxDecompressedSize int
+
+	// EnableOutbox 启用持久化发件箱（需要存储引擎）
+	EnableOutbox bool
+
+	// OutboxTTL 发件箱消息默认有效期
+	OutboxTTL time.Duration
+
+	// OutboxMaxPending 发件箱最多待投递消息数
+	OutboxMaxPending int
+
+	// OutboxRetryBase 发件箱首次重试间隔（之后指数退避）
+	OutboxRetryBase time.Duration
+
+	// OutboxRetryMax 发件箱最大重试间隔
+	OutboxRetryMax time.Duration
+
+	// DedupWindow 接收方按消息 ID 去重的时间窗口
+	DedupWindow time.Duration
+
+	// DedupCapacity 接收方去重缓存的最大条目数
+	DedupCapacity int
 }
 
 // DefaultConfig 返回默认配置
@@ -21,6 +55,16 @@ func DefaultConfig() *Config {
 		Timeout:    30 * time.Second,
 		MaxRetries: 3,
 		RetryDelay: time.Second,
+
+		MaxDecompressedSize: compress.DefaultMaxDecompressedSize,
+
+		OutboxTTL:        24 * time.Hour,
+		OutboxMaxPending: 10000,
+		OutboxRetryBase:  time.Second,
+		OutboxRetryMax:   5 * time.Minute,
+
+		DedupWindow:   24 * time.Hour,
+		DedupCapacity: 10000,
 	}
 }
 
@@ -47,3 +91,64 @@ func WithRetryDelay(delay time.Duration) Option {
 		c.RetryDelay = delay
 	}
 }
+
+// WithProtocolCompression 为指定协议启用压缩
+//
+// algs 按偏好排序，例如 WithProtocolCompression("chat", compress.Zstd, compress.Snappy)。
+// 不传算法时禁用该协议的压缩。
+func WithProtocolCompression(protocol string, algs ...compress.Algorithm) Option {
+	return func(c *Config) {
+		if len(algs) == 0 {
+			delete(c.Compression, protocol)
+			return
+		}
+		if c.Compression == nil {
+			c.Compression = make(map[string][]compress.Algorithm)
+		}
+		c.Compression[protocol] = append([]compress.Algorithm(nil), algs...)
+	}
+}
+
+// WithMaxDecompressedSize 设置解压后消息大小上限
+func WithMaxDecompressedSize(size int) Option {
+	return func(c *Config) {
+		c.MaxDecompressedSize = size
+	}
+}
+
+// WithOutbox 启用或禁用持久化发件箱
+func WithOutbox(enable bool) Option {
+	return func(c *Config) {
+		c.EnableOutbox = enable
+	}
+}
+
+// WithOutboxTTL 设置发件箱消息默认有效期
+func WithOutboxTTL(ttl time.Duration) Option {
+	return func(c *Config) {
+		c.OutboxTTL = ttl
+	}
+}
+
+// WithOutboxMaxPending 设置发件箱最多待投递消息数
+func WithOutboxMaxPending(n int) Option {
+	return func(c *Config) {
+		c.OutboxMaxPending = n
+	}
+}
+
+// WithOutboxRetry 设置发件箱重试退避区间
+func WithOutboxRetry(base, max time.Duration) Option {
+	return func(c *Config) {
+		c.OutboxRetryBase = base
+		c.OutboxRetryMax = max
+	}
+}
+
+// WithDedup 设置接收方去重窗口和容量
+func WithDedup(window time.Duration, capacity int) Option {
+	return func(c *Config) {
+		c.DedupWindow = window
+		c.DedupCapacity = capacity
+	}
+}
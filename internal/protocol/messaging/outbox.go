@@ -0,0 +1,511 @@
+// Package messaging 实现点对点消息传递协议
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dep2p/go-dep2p/internal/core/storage/engine"
+	"github.com/dep2p/go-dep2p/internal/core/storage/kv"
+	"github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/log"
+	"github.com/dep2p/go-dep2p/pkg/types"
+	"github.com/google/uuid"
+)
+
+// durableMetaKey 请求元数据中的持久化投递标记
+//
+// 接收方只对带此标记的请求按消息 ID 去重。
+const durableMetaKey = "durable"
+
+// outboxIdleInterval 发件箱无待投递消息时的检查间隔
+const outboxIdleInterval = time.Minute
+
+// outboxEntry 发件箱中的一条消息
+type outboxEntry struct {
+	ID          string    `json:"id"`
+	To          string    `json:"to"`
+	Protocol    string    `json:"protocol"`
+	Data        []byte    `json:"data"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	Attempts    int       `json:"attempts"`
+	NextAttempt time.Time `json:"next_attempt"`
+}
+
+// outbox 持久化发件箱
+//
+// 消息以 JSON 形式保存在存储引擎中，内存中保留一份索引用于调度。
+type outbox struct {
+	kv *kv.Store
+
+	mu       sync.Mutex
+	entries  map[string]*outboxEntry
+	inflight map[string]bool
+
+	// wake 唤醒投递循环
+	wake chan struct{}
+}
+
+// openOutbox 打开发件箱并加载已持久化的消息
+//
+// 重启后网络状况已经变化，加载的消息立即重试而不沿用之前的退避。
+func openOutbox(store *kv.Store) (*outbox, error) {
+	o := &outbox{
+		kv:       store,
+		entries:  make(map[string]*outboxEntry),
+		inflight: make(map[string]bool),
+		wake:     make(chan struct{}, 1),
+	}
+
+	now := time.Now()
+	var loadErr error
+	err := store.PrefixScan([]byte("o/"), func(_, value []byte) bool {
+		entry := &outboxEntry{}
+		if err := json.Unmarshal(value, entry); err != nil {
+			loadErr = err
+			return false
+		}
+		entry.NextAttempt = now
+		o.entries[entry.ID] = entry
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if loadErr != nil {
+		return nil, loadErr
+	}
+	return o, nil
+}
+
+func outboxKey(id string) []byte {
+	return []byte("o/" + id)
+}
+
+// put 持久化并索引消息
+func (o *outbox) put(entry *outboxEntry) error {
+	if err := o.kv.PutJSON(outboxKey(entry.ID), entry); err != nil {
+		return err
+	}
+	o.entries[entry.ID] = entry
+	return nil
+}
+
+// remove 删除消息
+func (o *outbox) remove(id string) {
+	delete(o.entries, id)
+	if err := o.kv.Delete(outboxKey(id)); err != nil {
+		logger.Warn("删除发件箱消息失败", "id", id, "error", err)
+	}
+}
+
+// notify 唤醒投递循环
+func (o *outbox) notify() {
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+}
+
+// ════════════════════════════════════════════════════════════════════════════
+//                              持久化发送 API
+// ════════════════════════════════════════════════════════════════════════════
+
+// 确保 Service 实现了 interfaces.DurableMessaging 接口
+var _ interfaces.DurableMessaging = (*Service)(nil)
+
+// SetStorageEngine 设置存储引擎（用于持久化发件箱和去重窗口，需在 Start 前调用）
+func (s *Service) SetStorageEngine(eng engine.InternalEngine) {
+	s.engine = eng
+}
+
+// SendDurable 持久化消息并异步投递，返回消息 ID
+func (s *Service) SendDurable(_ context.Context, peerID, protocol string, data []byte, opts interfaces.DurableSendOptions) (string, error) {
+	s.mu.RLock()
+	started, box := s.started, s.outbox
+	s.mu.RUnlock()
+	if !started {
+		return "", ErrNotStarted
+	}
+	if box == nil {
+		return "", ErrOutboxDisabled
+	}
+
+	if err := validateProtocol(protocol); err != nil {
+		return "", err
+	}
+	if !s.isRealmMember(peerID) {
+		return "", fmt.Errorf("%w: peer %s", ErrNotRealmMember, peerID)
+	}
+
+	id := opts.IdempotencyKey
+	if id == "" {
+		id = uuid.New().String()
+	}
+	ttl := opts.TTL
+	if ttl <= 0 {
+		ttl = s.config.OutboxTTL
+	}
+	// 接收方去重记录超过 DedupWindow 后会被清理，此后的重试会被重复处理，
+	// 因此有效期不能超过去重窗口
+	if s.config.DedupWindow > 0 && ttl > s.config.DedupWindow {
+		ttl = s.config.DedupWindow
+	}
+
+	box.mu.Lock()
+	if _, exists := box.entries[id]; exists {
+		box.mu.Unlock()
+		return id, nil
+	}
+	if s.config.OutboxMaxPending > 0 && len(box.entries) >= s.config.OutboxMaxPending {
+		box.mu.Unlock()
+		return "", ErrOutboxFull
+	}
+
+	now := time.Now()
+	entry := &outboxEntry{
+		ID:          id,
+		To:          peerID,
+		Protocol:    protocol,
+		Data:        append([]byte(nil), data...),
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(ttl),
+		NextAttempt: now,
+	}
+	err := box.put(entry)
+	box.mu.Unlock()
+	if err != nil {
+		return "", fmt.Errorf("messaging: persist outbox entry: %w", err)
+	}
+
+	logger.Debug("消息已写入发件箱", "id", id, "peerID", log.TruncateID(peerID, 8), "protocol", protocol)
+	box.notify()
+	return id, nil
+}
+
+// OnDelivery 设置投递状态回调
+func (s *Service) OnDelivery(handler interfaces.DeliveryHandler) {
+	s.deliveryMu.Lock()
+	defer s.deliveryMu.Unlock()
+	s.onDelivery = handler
+}
+
+// Pending 返回发件箱中尚未投递的消息 ID（按入队时间排序）
+func (s *Service) Pending() []string {
+	s.mu.RLock()
+	box := s.outbox
+	s.mu.RUnlock()
+	if box == nil {
+		return nil
+	}
+
+	box.mu.Lock()
+	entries := make([]*outboxEntry, 0, len(box.entries))
+	for _, e := range box.entries {
+		entries = append(entries, e)
+	}
+	box.mu.Unlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+	})
+	ids := make([]string, len(entries))
+	for i, e := range entries {
+		ids[i] = e.ID
+	}
+	return ids
+}
+
+// ════════════════════════════════════════════════════════════════════════════
+//                              投递循环
+// ════════════════════════════════════════════════════════════════════════════
+
+// startOutbox 打开发件箱并启动投递循环（调用方持有 s.mu）
+func (s *Service) startOutbox() error {
+	if !s.config.EnableOutbox {
+		return nil
+	}
+	if s.engine == nil {
+		logger.Warn("未配置存储引擎，持久化发件箱不可用")
+		return nil
+	}
+
+	box, err := openOutbox(kv.New(s.engine, []byte("messaging/outbox/"+s.storageScope()+"/")))
+	if err != nil {
+		return fmt.Errorf("messaging: open outbox: %w", err)
+	}
+	s.outbox = box
+
+	s.wg.Add(1)
+	go s.outboxLoop(box)
+	s.subscribeConnections(box)
+
+	logger.Info("持久化发件箱已启动", "pending", len(box.entries))
+	return nil
+}
+
+// startDedup 关联去重缓存的持久化存储（调用方持有 s.mu）
+//
+// 去重属于接收方能力，不依赖是否启用发件箱。
+func (s *Service) startDedup() error {
+	if s.engine == nil {
+		return nil
+	}
+	if err := s.dedup.attach(kv.New(s.engine, []byte("messaging/dedup/"+s.storageScope()+"/"))); err != nil {
+		return fmt.Errorf("messaging: open dedup store: %w", err)
+	}
+	return nil
+}
+
+// storageScope 返回持久化数据的作用域（Realm ID，未绑定时为 global）
+func (s *Service) storageScope() string {
+	if s.realmID == "" {
+		return "global"
+	}
+	return s.realmID
+}
+
+// outboxLoop 按 NextAttempt 调度到期消息
+func (s *Service) outboxLoop(box *outbox) {
+	defer s.wg.Done()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-box.wake:
+		case <-timer.C:
+		}
+
+		next := s.dispatchDue(box)
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(time.Until(next))
+	}
+}
+
+// dispatchDue 启动到期消息的投递，返回下一次需要检查的时间
+//
+// 同一目标节点的消息按入队顺序在一个 goroutine 中依次投递。
+func (s *Service) dispatchDue(box *outbox) time.Time {
+	now := time.Now()
+	next := now.Add(outboxIdleInterval)
+	due := make(map[string][]*outboxEntry)
+
+	box.mu.Lock()
+	for id, e := range box.entries {
+		if box.inflight[id] {
+			continue
+		}
+		if e.NextAttempt.After(now) {
+			if e.NextAttempt.Before(next) {
+				next = e.NextAttempt
+			}
+			continue
+		}
+		box.inflight[id] = true
+		due[e.To] = append(due[e.To], e)
+	}
+	box.mu.Unlock()
+
+	for _, entries := range due {
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+		})
+		s.wg.Add(1)
+		go func(entries []*outboxEntry) {
+			defer s.wg.Done()
+			for _, e := range entries {
+				s.deliver(box, e)
+			}
+			box.notify()
+		}(entries)
+	}
+	return next
+}
+
+// deliver 尝试投递一条消息并更新发件箱
+func (s *Service) deliver(box *outbox, e *outboxEntry) {
+	if time.Now().After(e.ExpiresAt) {
+		box.mu.Lock()
+		delete(box.inflight, e.ID)
+		box.remove(e.ID)
+		box.mu.Unlock()
+		logger.Debug("发件箱消息已过期", "id", e.ID, "peerID", log.TruncateID(e.To, 8))
+		s.report(e, interfaces.DeliveryExpired, e.Attempts, nil, nil)
+		return
+	}
+
+	req := &interfaces.Request{
+		ID:        e.ID,
+		From:      s.host.ID(),
+		Protocol:  e.Protocol,
+		Data:      e.Data,
+		Timestamp: time.Now(),
+		Metadata:  map[string]string{durableMetaKey: "1"},
+	}
+
+	ctx := s.ctx
+	if s.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.config.Timeout)
+		defer cancel()
+	}
+	resp, err := s.trySendRequest(ctx, e.To, e.Protocol, req)
+
+	// 服务停止导致的失败不计入尝试次数
+	if err != nil && s.ctx.Err() != nil {
+		box.mu.Lock()
+		delete(box.inflight, e.ID)
+		box.mu.Unlock()
+		return
+	}
+
+	box.mu.Lock()
+	delete(box.inflight, e.ID)
+	e.Attempts++
+	attempts := e.Attempts
+	if err == nil {
+		box.remove(e.ID)
+		box.mu.Unlock()
+
+		logger.Debug("发件箱消息已送达", "id", e.ID, "peerID", log.TruncateID(e.To, 8), "attempts", attempts)
+		s.report(e, interfaces.DeliveryDelivered, attempts, resp.Data, resp.Error)
+		return
+	}
+
+	if time.Now().After(e.ExpiresAt) {
+		box.remove(e.ID)
+		box.mu.Unlock()
+		s.report(e, interfaces.DeliveryExpired, attempts, nil, err)
+		return
+	}
+
+	e.NextAttempt = time.Now().Add(s.retryBackoff(attempts))
+	if perr := box.put(e); perr != nil {
+		logger.Warn("更新发件箱消息失败", "id", e.ID, "error", perr)
+	}
+	box.mu.Unlock()
+
+	logger.Debug("发件箱消息投递失败，稍后重试",
+		"id", e.ID, "peerID", log.TruncateID(e.To, 8), "attempts", attempts, "error", err)
+	s.report(e, interfaces.DeliveryRetrying, attempts, nil, err)
+}
+
+// retryBackoff 返回第 attempts 次失败后的重试间隔（指数退避，带 ±20% 抖动）
+func (s *Service) retryBackoff(attempts int) time.Duration {
+	base, max := s.config.OutboxRetryBase, s.config.OutboxRetryMax
+	if base <= 0 {
+		base = time.Second
+	}
+	if max < base {
+		max = base
+	}
+
+	delay := base
+	for i := 1; i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+	jitter := time.Duration(rand.Int63n(int64(delay)/5 + 1))
+	if rand.Intn(2) == 0 {
+		return delay - jitter
+	}
+	return delay + jitter
+}
+
+// report 调用投递状态回调
+func (s *Service) report(e *outboxEntry, status interfaces.DeliveryStatus, attempts int, response []byte, err error) {
+	s.deliveryMu.RLock()
+	handler := s.onDelivery
+	s.deliveryMu.RUnlock()
+	if handler == nil {
+		return
+	}
+	handler(interfaces.DeliveryReport{
+		ID:       e.ID,
+		PeerID:   e.To,
+		Protocol: e.Protocol,
+		Status:   status,
+		Attempts: attempts,
+		Response: response,
+		Error:    err,
+	})
+}
+
+// subscribeConnections 订阅连接事件，连接到目标节点时立即重试其消息
+func (s *Service) subscribeConnections(box *outbox) {
+	eb := s.host.EventBus()
+	if eb == nil {
+		logger.Debug("EventBus 不可用，发件箱只按退避间隔重试")
+		return
+	}
+
+	sub, err := eb.Subscribe(new(types.EvtPeerConnected))
+	if err != nil {
+		logger.Warn("订阅连接事件失败", "error", err)
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer sub.Close()
+
+		for {
+			select {
+			case <-s.ctx.Done():
+				return
+			case evt, ok := <-sub.Out():
+				if !ok {
+					return
+				}
+				var peerID string
+				switch e := evt.(type) {
+				case *types.EvtPeerConnected:
+					peerID = string(e.PeerID)
+				case types.EvtPeerConnected:
+					peerID = string(e.PeerID)
+				}
+				if peerID != "" {
+					s.retryPeer(box, peerID)
+				}
+			}
+		}
+	}()
+}
+
+// retryPeer 将发往 peerID 的消息标记为立即重试
+func (s *Service) retryPeer(box *outbox, peerID string) {
+	now := time.Now()
+	found := false
+
+	box.mu.Lock()
+	for _, e := range box.entries {
+		if e.To == peerID && e.NextAttempt.After(now) {
+			e.NextAttempt = now
+			found = true
+		}
+	}
+	box.mu.Unlock()
+
+	if found {
+		logger.Debug("节点已连接，重试发件箱消息", "peerID", log.TruncateID(peerID, 8))
+		box.notify()
+	}
+}
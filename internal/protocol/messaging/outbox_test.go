@@ -0,0 +1,312 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dep2p/go-dep2p/internal/core/storage/engine"
+	"github.com/dep2p/go-dep2p/internal/core/storage/engine/badger"
+	"github.com/dep2p/go-dep2p/internal/core/storage/kv"
+	"github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/types"
+	"github.com/dep2p/go-dep2p/tests/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEngine(t *testing.T) engine.InternalEngine {
+	t.Helper()
+
+	eng, err := badger.New(engine.DefaultConfig(filepath.Join(t.TempDir(), "outbox.db")))
+	require.NoError(t, err)
+	t.Cleanup(func() { eng.Close() })
+	return eng
+}
+
+// startOutboxService 在内存网络上启动启用发件箱的服务，投递报告写入返回的 channel
+func startOutboxService(t *testing.T, host *mocks.MockHost, realm interfaces.Realm, eng engine.InternalEngine, opts ...Option) (*Service, chan interfaces.DeliveryReport) {
+	t.Helper()
+
+	opts = append([]Option{WithOutbox(true), WithOutboxRetry(time.Hour, time.Hour)}, opts...)
+	svc, err := NewForRealm(host, realm, opts...)
+	require.NoError(t, err)
+	svc.SetStorageEngine(eng)
+
+	reports := make(chan interfaces.DeliveryReport, 16)
+	svc.OnDelivery(func(r interfaces.DeliveryReport) { reports <- r })
+
+	require.NoError(t, svc.Start(context.Background()))
+	t.Cleanup(func() { svc.Stop(context.Background()) })
+	return svc, reports
+}
+
+// startCountingService 启动接收方服务，返回处理器调用计数
+func startCountingService(t *testing.T, pn *mocks.PipeNet, id string, realm interfaces.Realm) *atomic.Int32 {
+	t.Helper()
+
+	var calls atomic.Int32
+	svc, err := NewForRealm(pn.AddHost(id), realm)
+	require.NoError(t, err)
+	require.NoError(t, svc.Start(context.Background()))
+	t.Cleanup(func() { svc.Stop(context.Background()) })
+
+	require.NoError(t, svc.RegisterHandler("chat", func(_ context.Context, req *interfaces.Request) (*interfaces.Response, error) {
+		calls.Add(1)
+		return &interfaces.Response{Data: append([]byte("ack:"), req.Data...)}, nil
+	}))
+	return &calls
+}
+
+func waitReport(t *testing.T, reports <-chan interfaces.DeliveryReport) interfaces.DeliveryReport {
+	t.Helper()
+
+	select {
+	case r := <-reports:
+		return r
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for delivery report")
+		return interfaces.DeliveryReport{}
+	}
+}
+
+func TestService_Outbox_RetryOnPeerConnected(t *testing.T) {
+	realm := mocks.NewMockRealm("realm-1")
+	realm.MemberList = []string{"peer-a", "peer-b"}
+
+	pn := mocks.NewPipeNet()
+	bus := mocks.NewMockEventBus()
+	hostA := pn.AddHost("peer-a")
+	hostA.EventBusFunc = func() interfaces.EventBus { return bus }
+	a, reports := startOutboxService(t, hostA, realm, newTestEngine(t))
+
+	// peer-b 离线：首次投递失败，等待重试
+	id, err := a.SendDurable(context.Background(), "peer-b", "chat", []byte("hello"), interfaces.DurableSendOptions{})
+	require.NoError(t, err)
+
+	r := waitReport(t, reports)
+	assert.Equal(t, interfaces.DeliveryRetrying, r.Status)
+	assert.Equal(t, id, r.ID)
+	assert.Equal(t, 1, r.Attempts)
+	assert.Error(t, r.Error)
+	assert.Equal(t, []string{id}, a.Pending())
+
+	// peer-b 上线并连接：立即重试而不等待退避
+	calls := startCountingService(t, pn, "peer-b", realm)
+	require.Len(t, bus.SubscribeCalls, 1)
+	bus.EmitEvent(bus.SubscribeCalls[0], &types.EvtPeerConnected{PeerID: "peer-b"})
+
+	r = waitReport(t, reports)
+	assert.Equal(t, interfaces.DeliveryDelivered, r.Status)
+	assert.Equal(t, 2, r.Attempts)
+	assert.Equal(t, []byte("ack:hello"), r.Response)
+	assert.NoError(t, r.Error)
+	assert.Empty(t, a.Pending())
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestService_Outbox_SurvivesRestart(t *testing.T) {
+	realm := mocks.NewMockRealm("realm-1")
+	realm.MemberList = []string{"peer-a", "peer-b"}
+	eng := newTestEngine(t)
+	pn := mocks.NewPipeNet()
+
+	first, reports := startOutboxService(t, pn.AddHost("peer-a"), realm, eng)
+	id, err := first.SendDurable(context.Background(), "peer-b", "chat", []byte("persisted"), interfaces.DurableSendOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, interfaces.DeliveryRetrying, waitReport(t, reports).Status)
+	require.NoError(t, first.Stop(context.Background()))
+
+	// 重启后从存储引擎恢复并投递
+	calls := startCountingService(t, pn, "peer-b", realm)
+	second, reports := startOutboxService(t, pn.AddHost("peer-a"), realm, eng)
+	assert.Equal(t, []string{id}, second.Pending())
+
+	r := waitReport(t, reports)
+	assert.Equal(t, interfaces.DeliveryDelivered, r.Status)
+	assert.Equal(t, id, r.ID)
+	assert.Equal(t, []byte("ack:persisted"), r.Response)
+	assert.Empty(t, second.Pending())
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestService_Outbox_IdempotencyKey(t *testing.T) {
+	realm := mocks.NewMockRealm("realm-1")
+	realm.MemberList = []string{"peer-a", "peer-b"}
+	pn := mocks.NewPipeNet()
+	a, reports := startOutboxService(t, pn.AddHost("peer-a"), realm, newTestEngine(t))
+
+	opts := interfaces.DurableSendOptions{IdempotencyKey: "order-42"}
+	id, err := a.SendDurable(context.Background(), "peer-b", "chat", []byte("x"), opts)
+	require.NoError(t, err)
+	assert.Equal(t, "order-42", id)
+	waitReport(t, reports)
+
+	id, err = a.SendDurable(context.Background(), "peer-b", "chat", []byte("x"), opts)
+	require.NoError(t, err)
+	assert.Equal(t, "order-42", id)
+	assert.Equal(t, []string{"order-42"}, a.Pending())
+}
+
+func TestService_Outbox_Expired(t *testing.T) {
+	realm := mocks.NewMockRealm("realm-1")
+	realm.MemberList = []string{"peer-a", "peer-b"}
+	pn := mocks.NewPipeNet()
+	a, reports := startOutboxService(t, pn.AddHost("peer-a"), realm, newTestEngine(t),
+		WithOutboxRetry(10*time.Millisecond, 10*time.Millisecond))
+
+	id, err := a.SendDurable(context.Background(), "peer-b", "chat", []byte("x"),
+		interfaces.DurableSendOptions{TTL: 50 * time.Millisecond})
+	require.NoError(t, err)
+
+	for {
+		r := waitReport(t, reports)
+		require.Equal(t, id, r.ID)
+		if r.Status == interfaces.DeliveryExpired {
+			break
+		}
+		require.Equal(t, interfaces.DeliveryRetrying, r.Status)
+	}
+	assert.Empty(t, a.Pending())
+}
+
+func TestService_Outbox_TTLClampedToDedupWindow(t *testing.T) {
+	realm := mocks.NewMockRealm("realm-1")
+	realm.MemberList = []string{"peer-a", "peer-b"}
+	a, _ := startOutboxService(t, mocks.NewMockHost("peer-a"), realm, newTestEngine(t),
+		WithDedup(time.Minute, 16))
+
+	id, err := a.SendDurable(context.Background(), "peer-b", "chat", []byte("x"),
+		interfaces.DurableSendOptions{TTL: time.Hour})
+	require.NoError(t, err)
+
+	a.outbox.mu.Lock()
+	entry := a.outbox.entries[id]
+	a.outbox.mu.Unlock()
+	require.NotNil(t, entry)
+	assert.Equal(t, time.Minute, entry.ExpiresAt.Sub(entry.CreatedAt))
+}
+
+func TestService_Outbox_Errors(t *testing.T) {
+	realm := mocks.NewMockRealm("realm-1")
+	realm.MemberList = []string{"peer-a", "peer-b"}
+	pn := mocks.NewPipeNet()
+
+	// 未启用发件箱
+	plain := startPipeService(t, pn, "peer-c", realm)
+	_, err := plain.SendDurable(context.Background(), "peer-b", "chat", nil, interfaces.DurableSendOptions{})
+	assert.ErrorIs(t, err, ErrOutboxDisabled)
+
+	// 启用但没有存储引擎
+	noEngine, err := NewForRealm(pn.AddHost("peer-d"), realm, WithOutbox(true))
+	require.NoError(t, err)
+	require.NoError(t, noEngine.Start(context.Background()))
+	defer noEngine.Stop(context.Background())
+	_, err = noEngine.SendDurable(context.Background(), "peer-b", "chat", nil, interfaces.DurableSendOptions{})
+	assert.ErrorIs(t, err, ErrOutboxDisabled)
+
+	// 非成员、发件箱已满
+	a, _ := startOutboxService(t, pn.AddHost("peer-a"), realm, newTestEngine(t), WithOutboxMaxPending(1))
+	_, err = a.SendDurable(context.Background(), "peer-x", "chat", nil, interfaces.DurableSendOptions{})
+	assert.ErrorIs(t, err, ErrNotRealmMember)
+	_, err = a.SendDurable(context.Background(), "peer-b", "chat", nil, interfaces.DurableSendOptions{})
+	require.NoError(t, err)
+	_, err = a.SendDurable(context.Background(), "peer-b", "chat", nil, interfaces.DurableSendOptions{})
+	assert.ErrorIs(t, err, ErrOutboxFull)
+}
+
+func TestService_DurableDedup(t *testing.T) {
+	realm := mocks.NewMockRealm("realm-1")
+	realm.MemberList = []string{"peer-a", "peer-b"}
+	pn := mocks.NewPipeNet()
+	a := startPipeService(t, pn, "peer-a", realm)
+	calls := startCountingService(t, pn, "peer-b", realm)
+
+	send := func(id string, durable bool) *interfaces.Response {
+		req := &interfaces.Request{ID: id, From: "peer-a", Data: []byte("m"), Timestamp: time.Now(), Metadata: map[string]string{}}
+		if durable {
+			req.Metadata[durableMetaKey] = "1"
+		}
+		resp, err := a.trySendRequest(context.Background(), "peer-b", "chat", req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	// 同一 ID 的持久化投递只执行一次处理器
+	first := send("msg-1", true)
+	second := send("msg-1", true)
+	assert.Equal(t, []byte("ack:m"), first.Data)
+	assert.Equal(t, first.Data, second.Data)
+	assert.Equal(t, int32(1), calls.Load())
+
+	// 普通请求不去重
+	send("msg-2", false)
+	send("msg-2", false)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestDedupCache_Evict(t *testing.T) {
+	c := newDedupCache(time.Hour, 2)
+	for _, key := range []string{"a", "b", "c"} {
+		e, dup := c.begin(key)
+		require.False(t, dup)
+		c.finish(e, &interfaces.Response{ID: key})
+	}
+
+	// 超出容量时淘汰最旧的条目
+	_, dup := c.begin("a")
+	assert.False(t, dup)
+	e, dup := c.begin("c")
+	assert.True(t, dup)
+	assert.Equal(t, "c", e.response().ID)
+
+	// 过期条目被清理
+	c = newDedupCache(time.Millisecond, 10)
+	e, _ = c.begin("a")
+	c.finish(e, &interfaces.Response{})
+	time.Sleep(5 * time.Millisecond)
+	_, dup = c.begin("a")
+	assert.False(t, dup)
+}
+
+func TestDedupCache_SurvivesRestart(t *testing.T) {
+	store := kv.New(newTestEngine(t), []byte("messaging/dedup/realm-1/"))
+
+	c := newDedupCache(time.Hour, 2)
+	require.NoError(t, c.attach(store))
+	e, _ := c.begin("peer-a/msg-1")
+	c.finish(e, &interfaces.Response{ID: "msg-1", From: "peer-b", Data: []byte("ack")})
+	e, _ = c.begin("peer-a/msg-2")
+	c.finish(e, &interfaces.Response{ID: "msg-2", From: "peer-b", Error: errors.New("rejected")})
+
+	// 重启后恢复去重窗口
+	c = newDedupCache(time.Hour, 2)
+	require.NoError(t, c.attach(store))
+	e, dup := c.begin("peer-a/msg-1")
+	require.True(t, dup)
+	assert.Equal(t, []byte("ack"), e.response().Data)
+	e, dup = c.begin("peer-a/msg-2")
+	require.True(t, dup)
+	require.Error(t, e.response().Error)
+	assert.Equal(t, "rejected", e.response().Error.Error())
+
+	// 淘汰的条目同时从存储中删除
+	e, _ = c.begin("peer-a/msg-3")
+	c.finish(e, &interfaces.Response{ID: "msg-3"})
+	count, err := store.Count([]byte("d/"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	// 超出窗口的持久化条目加载时丢弃
+	c = newDedupCache(time.Nanosecond, 10)
+	time.Sleep(time.Millisecond)
+	require.NoError(t, c.attach(store))
+	_, dup = c.begin("peer-a/msg-3")
+	assert.False(t, dup)
+	count, err = store.Count([]byte("d/"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+}
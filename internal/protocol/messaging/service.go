@@ -7,8 +7,11 @@ import (
 	"sync"
 	"time"
 
+	"github.com/dep2p/go-dep2p/internal/core/storage/engine"
 	"github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/compress"
 	"github.com/dep2p/go-dep2p/pkg/lib/log"
+	"github.com/dep2p/go-dep2p/pkg/lib/tracing"
 	"github.com/dep2p/go-dep2p/pkg/types"
 	"github.com/google/uuid"
 )
@@ -28,12 +31,22 @@ type Service struct {
 	started bool
 	ctx     context.Context
 	cancel  context.CancelFunc
+	wg      sync.WaitGroup
 
 	// 配置
 	config *Config
 
 	// msgrate 集成：ConnManager 用于更新消息速率
 	connMgr interfaces.ConnManager
+
+	// 持久化发件箱（可选，需要存储引擎）
+	engine     engine.InternalEngine
+	outbox     *outbox
+	deliveryMu sync.RWMutex
+	onDelivery interfaces.DeliveryHandler
+
+	// dedup 接收方对持久化投递的去重缓存
+	dedup *dedupCache
 }
 
 // 确保 Service 实现了 interfaces.Messaging 接口
@@ -59,6 +72,7 @@ func New(host interfaces.Host, realmMgr interfaces.RealmManager, opts ...Option)
 		codec:    NewCodec(),
 		handlers: NewHandlerRegistry(),
 		config:   config,
+		dedup:    newDedupCache(config.DedupWindow, config.DedupCapacity),
 	}
 
 	return s, nil
@@ -91,6 +105,7 @@ func NewForRealm(host interfaces.Host, realm interfaces.Realm, opts ...Option) (
 		codec:    NewCodec(),
 		handlers: NewHandlerRegistry(),
 		config:   config,
+		dedup:    newDedupCache(config.DedupWindow, config.DedupCapacity),
 	}
 
 	return s, nil
@@ -110,6 +125,16 @@ func (s *Service) Start(_ context.Context) error {
 	// 使用 context.Background() 而不是传入的 ctx
 	// 因为 Fx OnStart 的 ctx 在返回后会被取消，导致后台循环提前退出
 	s.ctx, s.cancel = context.WithCancel(context.Background())
+
+	if err := s.startDedup(); err != nil {
+		s.cancel()
+		return err
+	}
+	if err := s.startOutbox(); err != nil {
+		s.cancel()
+		return err
+	}
+
 	s.started = true
 
 	logger.Info("Messaging 服务启动成功")
@@ -119,9 +144,9 @@ func (s *Service) Start(_ context.Context) error {
 // Stop 停止服务
 func (s *Service) Stop(_ context.Context) error {
 	s.mu.Lock()
-	defer s.mu.Unlock()
 
 	if !s.started {
+		s.mu.Unlock()
 		return ErrNotStarted
 	}
 
@@ -132,6 +157,11 @@ func (s *Service) Stop(_ context.Context) error {
 	}
 
 	s.started = false
+	s.outbox = nil
+	s.mu.Unlock()
+
+	// 等待发件箱投递循环退出
+	s.wg.Wait()
 	logger.Info("Messaging 服务已停止")
 	return nil
 }
@@ -258,19 +288,37 @@ func (s *Service) RegisterHandler(protocol string, handler interfaces.MessageHan
 	if s.realm != nil && s.realmID != "" {
 		// Realm-bound 模式：只为绑定的 Realm 注册
 		protocolID := buildProtocolID(s.realmID, protocol)
-		s.host.SetStreamHandler(string(protocolID), s.createStreamHandler(protocol, handler))
+		s.setHostHandlers(string(protocolID), protocol, handler)
 	} else if s.realmMgr != nil {
 		// 全局模式：为每个 Realm 注册到 Host
 		realms := s.realmMgr.ListRealms()
 		for _, realm := range realms {
 			protocolID := buildProtocolID(realm.ID(), protocol)
-			s.host.SetStreamHandler(string(protocolID), s.createStreamHandler(protocol, handler))
+			s.setHostHandlers(string(protocolID), protocol, handler)
 		}
 	}
 
 	return nil
 }
 
+// setHostHandlers 在 Host 注册协议及其压缩变体
+//
+// 注册压缩变体即向远端声明支持对应算法。
+func (s *Service) setHostHandlers(protocolID, protocol string, handler interfaces.MessageHandler) {
+	s.host.SetStreamHandler(protocolID, s.createStreamHandler(protocol, handler, compress.None))
+	for _, alg := range s.config.Compression[protocol] {
+		s.host.SetStreamHandler(compress.Variant(protocolID, alg), s.createStreamHandler(protocol, handler, alg))
+	}
+}
+
+// removeHostHandlers 从 Host 注销协议及其压缩变体
+func (s *Service) removeHostHandlers(protocolID, protocol string) {
+	s.host.RemoveStreamHandler(protocolID)
+	for _, alg := range s.config.Compression[protocol] {
+		s.host.RemoveStreamHandler(compress.Variant(protocolID, alg))
+	}
+}
+
 // UnregisterHandler 注销消息处理器
 func (s *Service) UnregisterHandler(protocol string) error {
 	s.mu.RLock()
@@ -285,13 +333,13 @@ func (s *Service) UnregisterHandler(protocol string) error {
 	if s.realm != nil && s.realmID != "" {
 		// Realm-bound 模式：只注销绑定的 Realm 的处理器
 		protocolID := buildProtocolID(s.realmID, protocol)
-		s.host.RemoveStreamHandler(string(protocolID))
+		s.removeHostHandlers(string(protocolID), protocol)
 	} else if s.realmMgr != nil {
 		// 全局模式：从 Host 注销所有 Realm 的处理器
 		realms := s.realmMgr.ListRealms()
 		for _, realm := range realms {
 			protocolID := buildProtocolID(realm.ID(), protocol)
-			s.host.RemoveStreamHandler(string(protocolID))
+			s.removeHostHandlers(string(protocolID), protocol)
 		}
 	}
 
@@ -583,42 +631,52 @@ func (s *Service) sendRequest(ctx context.Context, peerID, protocol string, req
 const MsgKindMessaging uint64 = 1
 
 // trySendRequest 尝试发送请求
-func (s *Service) trySendRequest(ctx context.Context, peerID, protocol string, req *interfaces.Request) (*interfaces.Response, error) {
+//
+// 每次尝试记录一个 dep2p.messaging.send Span，并把追踪上下文写入请求元数据。
+func (s *Service) trySendRequest(ctx context.Context, peerID, protocol string, req *interfaces.Request) (_ *interfaces.Response, err error) {
+	ctx, span := tracing.Start(ctx, "dep2p.messaging.send", tracing.KindClient,
+		tracing.Attr("peer.id", peerID),
+		tracing.Attr("messaging.protocol", protocol),
+		tracing.Attr("messaging.id", req.ID))
+	defer func() { span.End(err) }()
+	if req.Metadata == nil {
+		req.Metadata = make(map[string]string)
+	}
+	tracing.Inject(ctx, req.Metadata)
+
 	// 获取 Realm
 	realm, err := s.findRealmForPeer(peerID)
 	if err != nil {
 		return nil, err
 	}
 
-	// 构造协议 ID
+	// 构造协议 ID（启用压缩时先提议压缩变体，旧节点回退到原协议）
 	protocolID := buildProtocolID(realm.ID(), protocol)
+	protocolIDs := compress.Variants(string(protocolID), s.config.Compression[protocol]...)
 
 	// 记录开始时间（用于 msgrate）
 	startTime := time.Now()
 
-	// P0 修复：确保连接存在，如果没有则尝试自动拨号
-	if err := s.ensureConnected(ctx, peerID); err != nil {
-		s.updatePeerRate(peerID, startTime, 0)
-		return nil, fmt.Errorf("failed to ensure connection: %w", err)
-	}
-
-	// 打开流
-	stream, err := s.host.NewStream(ctx, peerID, string(protocolID))
+	// 打开流（直连/中继，必要时经成员转发）
+	stream, err := s.openStream(ctx, realm, peerID, protocolIDs)
 	if err != nil {
 		// msgrate：更新失败的速率测量
 		s.updatePeerRate(peerID, startTime, 0)
-		return nil, fmt.Errorf("failed to open stream: %w", err)
+		return nil, err
 	}
 	defer stream.Close()
 
+	// 协商结果决定压缩算法
+	alg := compress.FromProtocol(stream.Protocol())
+
 	// 发送请求
-	if err := s.codec.WriteRequest(stream, req); err != nil {
+	if err := s.codec.WriteCompressedRequest(stream, req, alg); err != nil {
 		s.updatePeerRate(peerID, startTime, 0)
 		return nil, fmt.Errorf("failed to write request: %w", err)
 	}
 
 	// 读取响应
-	resp, err := s.codec.ReadResponse(stream)
+	resp, err := s.codec.ReadCompressedResponse(stream, alg, s.config.MaxDecompressedSize)
 	if err != nil {
 		s.updatePeerRate(peerID, startTime, 0)
 		return nil, fmt.Errorf("failed to read response: %w", err)
@@ -633,6 +691,46 @@ func (s *Service) trySendRequest(ctx context.Context, peerID, protocol string, r
 	return resp, nil
 }
 
+// openStream 打开到目标节点的流
+//
+// protocolIDs 按偏好排序，最后一个为未压缩的原协议。
+// 优先走直连/中继路径；Realm 支持成员转发（interfaces.RouteDialer）时：
+//   - 转发路径延迟明显更低，则优先转发
+//   - 无法建立连接或打开流失败，则回退到转发
+//
+// 转发路径只协商原协议。
+func (s *Service) openStream(ctx context.Context, realm interfaces.Realm, peerID string, protocolIDs []string) (interfaces.Stream, error) {
+	protocolID := protocolIDs[len(protocolIDs)-1]
+	dialer, routable := realm.(interfaces.RouteDialer)
+	if routable && dialer.ShouldRoute(peerID) {
+		if stream, err := dialer.NewRoutedStream(ctx, peerID, protocolID); err == nil {
+			return stream, nil
+		}
+	}
+
+	// P0 修复：确保连接存在，如果没有则尝试自动拨号
+	if err := s.ensureConnected(ctx, peerID); err != nil {
+		if routable {
+			if stream, rerr := dialer.NewRoutedStream(ctx, peerID, protocolID); rerr == nil {
+				logger.Debug("直连不可用，经成员转发发送", "peerID", log.TruncateID(peerID, 8))
+				return stream, nil
+			}
+		}
+		return nil, fmt.Errorf("failed to ensure connection: %w", err)
+	}
+
+	stream, err := s.host.NewStream(ctx, peerID, protocolIDs...)
+	if err != nil {
+		if routable {
+			if stream, rerr := dialer.NewRoutedStream(ctx, peerID, protocolID); rerr == nil {
+				return stream, nil
+			}
+		}
+		return nil, fmt.Errorf("failed to open stream: %w", err)
+	}
+	return stream, nil
+}
+
 // SetConnManager 设置连接管理器（用于 msgrate 集成）
 func (s *Service) SetConnManager(connMgr interfaces.ConnManager) {
 	s.connMgr = connMgr
@@ -700,12 +798,14 @@ func (s *Service) updatePeerRate(peerID string, startTime time.Time, items int)
 }
 
 // createStreamHandler 创建流处理器
-func (s *Service) createStreamHandler(protocol string, handler interfaces.MessageHandler) interfaces.StreamHandler {
+//
+// alg 为该协议变体使用的压缩算法，请求与响应均按其编解码。
+func (s *Service) createStreamHandler(protocol string, handler interfaces.MessageHandler, alg compress.Algorithm) interfaces.StreamHandler {
 	return func(stream interfaces.Stream) {
 		defer stream.Close()
 
 		// 读取请求
-		req, err := s.codec.ReadRequest(stream)
+		req, err := s.codec.ReadCompressedRequest(stream, alg, s.config.MaxDecompressedSize)
 		if err != nil {
 			// 无法读取请求,直接返回
 			return
@@ -714,43 +814,79 @@ func (s *Service) createStreamHandler(protocol string, handler interfaces.Messag
 		// 设置协议
 		req.Protocol = protocol
 
-		// 创建上下文
-		ctx, cancel := context.WithTimeout(s.ctx, s.config.Timeout)
-		defer cancel()
-
-		// 调用处理器
-		resp, err := handler(ctx, req)
-		if err != nil {
-			// 构造错误响应
-			resp = &interfaces.Response{
-				ID:        req.ID,
-				From:      s.host.ID(),
-				Error:     err,
-				Timestamp: time.Now(),
+		// 持久化投递按消息 ID 去重：重复请求直接返回首次处理的响应
+		if req.Metadata[durableMetaKey] != "" && req.ID != "" {
+			sender := remotePeer(stream)
+			if sender == "" {
+				sender = req.From
 			}
-		} else if resp == nil {
-			// 构造空响应
-			resp = &interfaces.Response{
-				ID:        req.ID,
-				From:      s.host.ID(),
-				Data:      []byte{},
-				Timestamp: time.Now(),
+			entry, dup := s.dedup.begin(sender + "/" + req.ID)
+			if dup {
+				logger.Debug("忽略重复投递的消息", "id", req.ID, "from", log.TruncateID(sender, 8))
+				_ = s.codec.WriteCompressedResponse(stream, entry.response(), alg)
+				return
 			}
+			resp := s.handleRequest(handler, req)
+			s.dedup.finish(entry, resp)
+			_ = s.codec.WriteCompressedResponse(stream, resp, alg)
+			return
 		}
 
-		// 确保响应 ID 和 From 正确
-		resp.ID = req.ID
-		resp.From = s.host.ID()
-		resp.Timestamp = time.Now()
-
 		// 发送响应
-		if err := s.codec.WriteResponse(stream, resp); err != nil {
+		if err := s.codec.WriteCompressedResponse(stream, s.handleRequest(handler, req), alg); err != nil {
 			// 忽略写入错误
 			return
 		}
 	}
 }
 
+// handleRequest 调用处理器并构造响应
+func (s *Service) handleRequest(handler interfaces.MessageHandler, req *interfaces.Request) *interfaces.Response {
+	// 创建上下文（延续发送方的追踪上下文）
+	ctx, cancel := context.WithTimeout(s.ctx, s.config.Timeout)
+	defer cancel()
+	ctx, span := tracing.Start(tracing.Extract(ctx, req.Metadata), "dep2p.messaging.handle", tracing.KindServer,
+		tracing.Attr("peer.id", req.From),
+		tracing.Attr("messaging.protocol", req.Protocol),
+		tracing.Attr("messaging.id", req.ID))
+
+	// 调用处理器
+	resp, err := handler(ctx, req)
+	span.End(err)
+	if err != nil {
+		// 构造错误响应
+		resp = &interfaces.Response{
+			ID:        req.ID,
+			From:      s.host.ID(),
+			Error:     err,
+			Timestamp: time.Now(),
+		}
+	} else if resp == nil {
+		// 构造空响应
+		resp = &interfaces.Response{
+			ID:        req.ID,
+			From:      s.host.ID(),
+			Data:      []byte{},
+			Timestamp: time.Now(),
+		}
+	}
+
+	// 确保响应 ID 和 From 正确
+	resp.ID = req.ID
+	resp.From = s.host.ID()
+	resp.Timestamp = time.Now()
+	return resp
+}
+
+// remotePeer 从流获取远端节点 ID
+func remotePeer(stream interfaces.Stream) string {
+	conn := stream.Conn()
+	if conn == nil {
+		return ""
+	}
+	return string(conn.RemotePeer())
+}
+
 // isRealmMember 检查节点是否是 Realm 的成员
 func (s *Service) isRealmMember(peerID string) bool {
 	// Realm-bound 模式：检查绑定的 Realm
@@ -1,11 +1,15 @@
 package messaging
 
 import (
+	"bytes"
 	"context"
 	"testing"
 	"time"
 
 	"github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/compress"
+	"github.com/dep2p/go-dep2p/pkg/lib/tracing"
+	"github.com/dep2p/go-dep2p/tests/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -401,3 +405,132 @@ func TestShouldRetry(t *testing.T) {
 		})
 	}
 }
+
+// startPipeService 在内存网络上启动绑定 Realm 的服务并注册回显处理器
+func startPipeService(t *testing.T, pn *mocks.PipeNet, id string, realm interfaces.Realm, opts ...Option) *Service {
+	t.Helper()
+
+	svc, err := NewForRealm(pn.AddHost(id), realm, opts...)
+	require.NoError(t, err)
+	require.NoError(t, svc.Start(context.Background()))
+	t.Cleanup(func() { svc.Stop(context.Background()) })
+
+	require.NoError(t, svc.RegisterHandler("chat", func(_ context.Context, req *interfaces.Request) (*interfaces.Response, error) {
+		return &interfaces.Response{Data: req.Data}, nil
+	}))
+	return svc
+}
+
+func TestService_Compression_Negotiated(t *testing.T) {
+	realm := mocks.NewMockRealm("realm-1")
+	realm.MemberList = []string{"peer-a", "peer-b"}
+	payload := bytes.Repeat([]byte("compressible "), 4096)
+
+	// 基线：未启用压缩
+	plainNet := mocks.NewPipeNet()
+	plainA := startPipeService(t, plainNet, "peer-a", realm)
+	startPipeService(t, plainNet, "peer-b", realm)
+	resp, err := plainA.Send(context.Background(), "peer-b", "chat", payload)
+	require.NoError(t, err)
+	assert.Equal(t, payload, resp)
+
+	// 双方启用压缩
+	zstdNet := mocks.NewPipeNet()
+	zstdA := startPipeService(t, zstdNet, "peer-a", realm, WithProtocolCompression("chat", compress.Zstd))
+	startPipeService(t, zstdNet, "peer-b", realm, WithProtocolCompression("chat", compress.Snappy, compress.Zstd))
+	assert.Contains(t, zstdNet.Protocols("peer-b"), "/dep2p/app/realm-1/chat/1.0.0+zstd")
+
+	resp, err = zstdA.Send(context.Background(), "peer-b", "chat", payload)
+	require.NoError(t, err)
+	assert.Equal(t, payload, resp)
+	assert.Less(t, zstdNet.WireBytes()*4, plainNet.WireBytes())
+}
+
+func TestService_Compression_FallbackToPlain(t *testing.T) {
+	realm := mocks.NewMockRealm("realm-1")
+	realm.MemberList = []string{"peer-a", "peer-b"}
+	payload := bytes.Repeat([]byte("compressible "), 1024)
+
+	// peer-b 为不支持压缩的旧节点
+	pn := mocks.NewPipeNet()
+	a := startPipeService(t, pn, "peer-a", realm, WithProtocolCompression("chat", compress.Zstd, compress.Snappy))
+	startPipeService(t, pn, "peer-b", realm)
+
+	resp, err := a.Send(context.Background(), "peer-b", "chat", payload)
+	require.NoError(t, err)
+	assert.Equal(t, payload, resp)
+}
+
+func TestService_TracePropagation(t *testing.T) {
+	exp := tracing.NewMemoryExporter()
+	prev := tracing.SetExporter(exp)
+	t.Cleanup(func() { tracing.SetExporter(prev) })
+
+	realm := mocks.NewMockRealm("realm-1")
+	realm.MemberList = []string{"peer-a", "peer-b"}
+
+	pn := mocks.NewPipeNet()
+	a := startPipeService(t, pn, "peer-a", realm)
+	b, err := NewForRealm(pn.AddHost("peer-b"), realm)
+	require.NoError(t, err)
+	require.NoError(t, b.Start(context.Background()))
+	t.Cleanup(func() { b.Stop(context.Background()) })
+
+	handled := make(chan tracing.SpanContext, 1)
+	require.NoError(t, b.RegisterHandler("chat", func(ctx context.Context, req *interfaces.Request) (*interfaces.Response, error) {
+		handled <- tracing.SpanContextFromContext(ctx)
+		return &interfaces.Response{Data: req.Data}, nil
+	}))
+
+	ctx, root := tracing.Start(context.Background(), "app.op", tracing.KindInternal)
+	_, err = a.Send(ctx, "peer-b", "chat", []byte("hi"))
+	require.NoError(t, err)
+	root.End(nil)
+
+	remote := <-handled
+	assert.Equal(t, root.SpanContext().TraceID, remote.TraceID)
+
+	require.NoError(t, tracing.ForceFlush(context.Background()))
+	spans := map[string]tracing.SpanData{}
+	for _, s := range exp.Spans() {
+		spans[s.Name] = s
+	}
+	send, handle := spans["dep2p.messaging.send"], spans["dep2p.messaging.handle"]
+	require.Equal(t, "app.op", spans["app.op"].Name)
+	assert.Equal(t, root.SpanContext().SpanID, send.ParentSpanID)
+	assert.Equal(t, send.SpanID, handle.ParentSpanID)
+	assert.Equal(t, send.TraceID, handle.TraceID)
+	assert.True(t, handle.RemoteParent)
+	assert.Equal(t, remote.SpanID, handle.SpanID)
+}
+
+func TestService_TracePassthrough(t *testing.T) {
+	prev := tracing.SetExporter(nil)
+	t.Cleanup(func() { tracing.SetExporter(prev) })
+
+	realm := mocks.NewMockRealm("realm-1")
+	realm.MemberList = []string{"peer-a", "peer-b"}
+
+	pn := mocks.NewPipeNet()
+	a := startPipeService(t, pn, "peer-a", realm)
+	b, err := NewForRealm(pn.AddHost("peer-b"), realm)
+	require.NoError(t, err)
+	require.NoError(t, b.Start(context.Background()))
+	t.Cleanup(func() { b.Stop(context.Background()) })
+
+	handled := make(chan tracing.SpanContext, 1)
+	require.NoError(t, b.RegisterHandler("chat", func(ctx context.Context, req *interfaces.Request) (*interfaces.Response, error) {
+		handled <- tracing.SpanContextFromContext(ctx)
+		return &interfaces.Response{Data: req.Data}, nil
+	}))
+
+	// 未启用追踪的节点原样透传上游上下文
+	const tp = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	ctx := tracing.ContextWithTraceparent(context.Background(), tp, "vendor=abc")
+	_, err = a.Send(ctx, "peer-b", "chat", []byte("hi"))
+	require.NoError(t, err)
+
+	remote := <-handled
+	assert.Equal(t, tp, remote.Traceparent())
+	assert.Equal(t, "vendor=abc", remote.State)
+}
@@ -5,11 +5,14 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"math"
 	"sync"
 	"time"
 
 	"github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/compress"
 	pb "github.com/dep2p/go-dep2p/pkg/lib/proto/gossipsub"
+	"github.com/dep2p/go-dep2p/pkg/lib/tracing"
 	"github.com/dep2p/go-dep2p/pkg/types"
 	"google.golang.org/protobuf/proto"
 )
@@ -143,13 +146,13 @@ func (gs *gossipSub) Start(ctx context.Context) error {
 	if gs.realm != nil && gs.realmID != "" {
 		// Realm-bound 模式：只为绑定的 Realm 注册
 		protocolID := buildProtocolID(gs.realmID)
-		gs.host.SetStreamHandler(string(protocolID), gs.handleStream)
+		gs.setHostHandlers(string(protocolID))
 	} else if gs.realmMgr != nil {
 		// 全局模式：为所有 Realm 注册
 		realms := gs.realmMgr.ListRealms()
 		for _, realm := range realms {
 			protocolID := buildProtocolID(realm.ID())
-			gs.host.SetStreamHandler(string(protocolID), gs.handleStream)
+			gs.setHostHandlers(string(protocolID))
 		}
 	}
 
@@ -164,6 +167,16 @@ func (gs *gossipSub) Start(ctx context.Context) error {
 	return nil
 }
 
+// setHostHandlers 注册 GossipSub 协议及全部压缩变体
+//
+// 接收端始终支持所有压缩算法，是否压缩由发送端按主题决定。
+func (gs *gossipSub) setHostHandlers(protocolID string) {
+	gs.host.SetStreamHandler(protocolID, gs.handleStream)
+	for _, alg := range compress.Supported {
+		gs.host.SetStreamHandler(compress.Variant(protocolID, alg), gs.handleStream)
+	}
+}
+
 // Stop 停止 GossipSub
 func (gs *gossipSub) Stop() error {
 	gs.mu.Lock()
@@ -193,7 +206,10 @@ func (gs *gossipSub) Stop() error {
 }
 
 // Join 加入主题
-func (gs *gossipSub) Join(name string) (*topic, error) {
+//
+// compression 为发送该主题消息时提议的压缩算法，为空时使用配置中的
+// TopicCompression。
+func (gs *gossipSub) Join(name string, compression []compress.Algorithm) (*topic, error) {
 	// 先检查是否已存在(使用读锁)
 	gs.mu.RLock()
 	_, exists := gs.topics[name]
@@ -212,6 +228,10 @@ func (gs *gossipSub) Join(name string) (*topic, error) {
 	}
 
 	t := newTopic(name, nil, gs) // ps 会在 Service.Join 中设置
+	t.compression = compression
+	if len(t.compression) == 0 {
+		t.compression = gs.config.TopicCompression[name]
+	}
 	gs.topics[name] = t
 	gs.mu.Unlock()
 
@@ -349,7 +369,7 @@ func (gs *gossipSub) forwardToMesh(topicName string, msg *pb.Message) error {
 // sendMessage 发送消息给节点
 //
 // Phase 5.1: 发送结果会上报到 NetworkMonitor（如果设置了）
-func (gs *gossipSub) sendMessage(peerID, _ string, data []byte) error {
+func (gs *gossipSub) sendMessage(peerID, topicName string, data []byte) error {
 	// 查找所属 Realm
 	realm := gs.findRealmForPeer(peerID)
 	if realm == nil {
@@ -373,17 +393,24 @@ func (gs *gossipSub) sendMessage(peerID, _ string, data []byte) error {
 		}
 	}
 
-	// 构造协议 ID
+	// 构造协议 ID（主题启用压缩时先提议压缩变体）
 	protocolID := buildProtocolID(realm.ID())
+	protocolIDs := compress.Variants(string(protocolID), gs.topicCompression(topicName)...)
 
 	// 打开流
-	stream, err := gs.host.NewStream(gs.ctx, peerID, string(protocolID))
+	stream, err := gs.host.NewStream(gs.ctx, peerID, protocolIDs...)
 	if err != nil {
 		gs.reportSendError(peerID, err)
 		return err
 	}
 	defer stream.Close()
 
+	// 按协商结果压缩
+	data, err = compress.Compress(compress.FromProtocol(stream.Protocol()), data)
+	if err != nil {
+		return err
+	}
+
 	// 写入消息
 	_, err = stream.Write(data)
 	if err != nil {
@@ -396,6 +423,23 @@ func (gs *gossipSub) sendMessage(peerID, _ string, data []byte) error {
 	return nil
 }
 
+// topicCompression 返回主题发送时提议的压缩算法
+func (gs *gossipSub) topicCompression(topicName string) []compress.Algorithm {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	if t, ok := gs.topics[topicName]; ok {
+		return t.compression
+	}
+	return gs.config.TopicCompression[topicName]
+}
+
+// maxWireSize 返回单条消息在线路上的大小上限
+//
+// 在 MaxMessageSize 基础上预留签名、主题等字段的开销。
+func (gs *gossipSub) maxWireSize() int {
+	return gs.config.MaxMessageSize + messageEnvelopeOverhead
+}
+
 // SetHealthMonitor 设置网络健康监控器
 //
 // Phase 5.1: 用于支持发送错误上报和网络状态检测
@@ -431,12 +475,30 @@ func (gs *gossipSub) reportSendSuccess(peerID string) {
 func (gs *gossipSub) handleStream(stream interfaces.Stream) {
 	defer stream.Close()
 
+	// 压缩变体的读取长度同样受限
+	alg := compress.FromProtocol(stream.Protocol())
+	var reader io.Reader = stream
+	if alg != compress.None {
+		reader = io.LimitReader(stream, int64(gs.maxWireSize())+1)
+	}
+
 	// 读取消息
-	data, err := io.ReadAll(stream)
+	data, err := io.ReadAll(reader)
 	if err != nil {
 		return
 	}
 
+	// 压缩变体：解压并限制解压后大小
+	if alg != compress.None {
+		data, err = compress.Decompress(alg, data, gs.maxWireSize())
+		if err != nil {
+			logger.Debug("解压消息失败",
+				"peerID", string(stream.Conn().RemotePeer()),
+				"error", err)
+			return
+		}
+	}
+
 	// 解析消息
 	msg := &pb.Message{}
 	if err := proto.Unmarshal(data, msg); err != nil {
@@ -498,7 +560,16 @@ func (gs *gossipSub) handleMessage(peerID string, msg *pb.Message) {
 	if exists {
 		interfaceMsg := protoToInterface(msg)
 		interfaceMsg.ReceivedFrom = peerID
+
+		// 投递 Span 的父 Span 为发布方的 dep2p.pubsub.publish
+		_, span := tracing.Start(
+			tracing.ContextWithTraceparent(gs.ctx, msg.Traceparent, msg.Tracestate),
+			"dep2p.pubsub.deliver", tracing.KindConsumer,
+			tracing.Attr("pubsub.topic", msg.Topic),
+			tracing.Attr("pubsub.msg_id", msgID),
+			tracing.Attr("pubsub.received_from", peerID))
 		topic.deliverMessage(interfaceMsg)
+		span.End(nil)
 	}
 
 	// 转发给其他 Mesh 节点(除了发送者)
@@ -589,8 +660,12 @@ func (gs *gossipSub) graftPeers(topicName string) {
 		connectedCandidates = gs.filterConnectedPeers(candidates)
 	}
 
-	// 选择节点
-	toGraft := gs.mesh.SelectPeersToGraft(topicName, connectedCandidates, needed)
+	// 选择节点：优先在能力块中声明加入同一 Realm 的节点
+	hinted, others := gs.splitByRealmHint(connectedCandidates)
+	toGraft := gs.mesh.SelectPeersToGraft(topicName, hinted, needed)
+	if len(toGraft) < needed {
+		toGraft = append(toGraft, gs.mesh.SelectPeersToGraft(topicName, others, needed-len(toGraft))...)
+	}
 
 	// 没有可 graft 的节点时静默返回
 	if len(toGraft) == 0 {
@@ -758,6 +833,33 @@ func (gs *gossipSub) findRealmForPeer(peerID string) interfaces.Realm {
 	return nil
 }
 
+// splitByRealmHint 按 Identify 能力块中的 Realm 提示拆分候选节点
+//
+// 返回声明加入了对应 Realm 的节点和其余节点。Realm 提示只用于排序，
+// 成员资格仍以 findRealmForPeer 为准。
+func (gs *gossipSub) splitByRealmHint(candidates []string) (hinted, others []string) {
+	peerstore := gs.host.Peerstore()
+	if peerstore == nil {
+		return nil, candidates
+	}
+
+	for _, peerID := range candidates {
+		realm := gs.findRealmForPeer(peerID)
+		if realm != nil {
+			v, err := peerstore.Get(types.PeerID(peerID), types.PeerCapabilitiesKey)
+			if err == nil {
+				caps, ok := types.ParsePeerCapabilities(v)
+				if ok && caps.InRealm(types.PeerID(peerID), types.RealmID(realm.ID())) {
+					hinted = append(hinted, peerID)
+					continue
+				}
+			}
+		}
+		others = append(others, peerID)
+	}
+	return hinted, others
+}
+
 // cleanupSeenMessages 清理过期的已见消息
 func (gs *gossipSub) cleanupSeenMessages() {
 	gs.seenMessages.Cleanup()
@@ -775,6 +877,37 @@ func (gs *gossipSub) decayScores() {
 	}
 }
 
+// reportReputation 将评分以信誉信号的形式上报
+//
+// 评分按灰名单阈值归一化到 [-1, 1]，低于灰名单阈值的节点信号为 -1。
+func (gs *gossipSub) reportReputation() {
+	rep := gs.config.Reputation
+	if rep == nil || gs.scorer == nil {
+		return
+	}
+
+	gossip, publish, graylist, _ := gs.scorer.GetThresholds()
+	scale := math.Abs(graylist)
+	if scale == 0 {
+		return
+	}
+
+	for peerID, score := range gs.scorer.Scores() {
+		var reason string
+		switch {
+		case score < graylist:
+			reason = "graylisted"
+		case score < publish:
+			reason = "below_publish_threshold"
+		case score < gossip:
+			reason = "below_gossip_threshold"
+		default:
+			reason = "score"
+		}
+		rep.Observe(peerID, interfaces.ReputationSignalPubSub, math.Max(-1, math.Min(1, score/scale)), reason)
+	}
+}
+
 // GetScorer 获取评分器（供外部访问）
 func (gs *gossipSub) GetScorer() *PeerScorer {
 	return gs.scorer
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/types"
 	"github.com/dep2p/go-dep2p/tests/mocks"
 )
 
@@ -408,3 +409,35 @@ func TestGossipSub_FilterConnectedPeers_WithNilNetwork(t *testing.T) {
 	require.Len(t, filtered, 3)
 	assert.Equal(t, candidates, filtered)
 }
+
+// TestGossipSub_SplitByRealmHint 测试按能力块 Realm 提示优先选择节点
+func TestGossipSub_SplitByRealmHint(t *testing.T) {
+	ps := mocks.NewMockPeerstore()
+	mockHost := mocks.NewMockHost("local-peer")
+	mockHost.PeerstoreFunc = func() interfaces.Peerstore {
+		return ps
+	}
+
+	realm := mocks.NewMockRealm("realm-1")
+	realm.IsMemberFunc = func(peerID string) bool {
+		return true
+	}
+
+	// peer1 声明加入 realm-1，peer2 声明加入其他 Realm，peer3 没有能力块
+	caps1 := types.NewPeerCapabilities()
+	caps1.AddRealm("peer1", "realm-1")
+	require.NoError(t, ps.Put("peer1", types.PeerCapabilitiesKey, caps1))
+
+	caps2 := types.NewPeerCapabilities()
+	caps2.AddRealm("peer2", "realm-2")
+	require.NoError(t, ps.Put("peer2", types.PeerCapabilitiesKey, caps2))
+
+	gs := &gossipSub{
+		host:  mockHost,
+		realm: realm,
+	}
+
+	hinted, others := gs.splitByRealmHint([]string{"peer1", "peer2", "peer3"})
+	assert.Equal(t, []string{"peer1"}, hinted)
+	assert.Equal(t, []string{"peer2", "peer3"}, others)
+}
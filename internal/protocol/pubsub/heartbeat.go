@@ -5,6 +5,8 @@ import (
 	"context"
 	"sync"
 	"time"
+
+	"github.com/dep2p/go-dep2p/pkg/interfaces"
 )
 
 // heartbeat 心跳管理器
@@ -62,8 +64,10 @@ func (hb *heartbeat) run() {
 func (hb *heartbeat) tick() {
 	hb.tickCount++
 
-	// 维护 Mesh
-	hb.gossip.maintainMesh()
+	// 维护 Mesh（计费网络下按数据预算节流）
+	if hb.allowMaintenance() {
+		hb.gossip.maintainMesh()
+	}
 
 	// 清理过期的已见消息
 	hb.gossip.cleanupSeenMessages()
@@ -74,8 +78,19 @@ func (hb *heartbeat) tick() {
 	// P1 修复完成：执行评分衰减
 	hb.gossip.decayScores()
 
+	// 每 10 次心跳将评分同步到信誉服务
+	if hb.tickCount%10 == 0 {
+		hb.gossip.reportReputation()
+	}
+
 	// 每 60 次心跳（约 1 分钟）清理过期退避记录
 	if hb.tickCount%60 == 0 {
 		hb.gossip.cleanupConnectBackoff()
 	}
 }
+
+// allowMaintenance 检查数据预算是否允许本次 Mesh 维护
+func (hb *heartbeat) allowMaintenance() bool {
+	budget := hb.gossip.config.DataBudget
+	return budget == nil || budget.Allow(interfaces.BudgetSubsystemPubSub, "mesh", hb.interval)
+}
@@ -156,6 +156,8 @@ func protoToInterface(msg *pb.Message) *interfaces.Message {
 		ReceivedFrom: "", // 在接收时填充
 		// P1-1: 设置接收时间戳
 		RecvTimeNano: time.Now().UnixNano(),
+		TraceParent:  msg.Traceparent,
+		TraceState:   msg.Tracestate,
 	}
 }
 
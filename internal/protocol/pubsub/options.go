@@ -1,7 +1,12 @@
 // Package pubsub 实现发布订阅协议
 package pubsub
 
-import "time"
+import (
+	"time"
+
+	"github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/compress"
+)
 
 // Config PubSub 服务配置
 type Config struct {
@@ -43,6 +48,22 @@ type Config struct {
 
 	// PeerScoring 节点评分配置（P1 修复完成）
 	PeerScoring PeerScoringConfig
+
+	// TopicCompression 按主题启用的压缩算法（按偏好排序）
+	//
+	// 未配置的主题不压缩；Join 时通过 interfaces.WithTopicCompression
+	// 指定的算法优先。解压后大小受 MaxMessageSize 约束。
+	TopicCompression map[string][]compress.Algorithm
+
+	// DataBudget 数据预算（可选）
+	//
+	// 计费网络下按预算跳过部分心跳中的 Mesh 维护；本地清理与评分衰减照常执行。
+	DataBudget interfaces.DataBudget
+
+	// Reputation 节点信誉服务（可选）
+	//
+	// 心跳中定期将节点评分作为 pubsub 信号上报。
+	Reputation interfaces.Reputation
 }
 
 // PeerScoringConfig 节点评分配置
@@ -140,6 +161,22 @@ func WithDisableHeartbeat(disable bool) Option {
 	}
 }
 
+// WithTopicCompression 为指定主题启用压缩
+//
+// 不传算法时禁用该主题的压缩。
+func WithTopicCompression(topic string, algs ...compress.Algorithm) Option {
+	return func(c *Config) {
+		if len(algs) == 0 {
+			delete(c.TopicCompression, topic)
+			return
+		}
+		if c.TopicCompression == nil {
+			c.TopicCompression = make(map[string][]compress.Algorithm)
+		}
+		c.TopicCompression[topic] = append([]compress.Algorithm(nil), algs...)
+	}
+}
+
 // WithPeerScoring 启用节点评分
 func WithPeerScoring(enabled bool) Option {
 	return func(c *Config) {
@@ -163,3 +200,17 @@ func WithPeerScoringThresholds(gossip, publish, graylist, acceptPX float64) Opti
 		c.PeerScoring.AcceptPXThreshold = acceptPX
 	}
 }
+
+// WithDataBudget 设置数据预算
+func WithDataBudget(budget interfaces.DataBudget) Option {
+	return func(c *Config) {
+		c.DataBudget = budget
+	}
+}
+
+// WithReputation 设置节点信誉服务
+func WithReputation(rep interfaces.Reputation) Option {
+	return func(c *Config) {
+		c.Reputation = rep
+	}
+}
@@ -8,6 +8,9 @@ import (
 	"github.com/dep2p/go-dep2p/pkg/protocol"
 )
 
+// messageEnvelopeOverhead 消息除数据外的字段（签名、主题等）预留大小
+const messageEnvelopeOverhead = 64 << 10
+
 // buildProtocolID 构造协议 ID
 //
 // 生成格式: /dep2p/app/<realmID>/pubsub/1.0.0
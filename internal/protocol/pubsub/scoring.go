@@ -719,6 +719,18 @@ func (ps *PeerScorer) GetPeerScore(peerID string) (float64, map[string]float64)
 	return totalScore, topicScores
 }
 
+// Scores 返回所有已跟踪 peer 的当前评分
+func (ps *PeerScorer) Scores() map[string]float64 {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+
+	scores := make(map[string]float64, len(ps.peerStats))
+	for peerID, stats := range ps.peerStats {
+		scores[peerID] = ps.computeScore(peerID, stats)
+	}
+	return scores
+}
+
 // Reset 重置评分器
 func (ps *PeerScorer) Reset() {
 	ps.mu.Lock()
@@ -7,6 +7,9 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/dep2p/go-dep2p/internal/core/reputation"
+	"github.com/dep2p/go-dep2p/pkg/interfaces"
 )
 
 func TestNewPeerScorer(t *testing.T) {
@@ -288,3 +291,33 @@ func TestPeerScorer_DuplicateMessage(t *testing.T) {
 
 	assert.Equal(t, float64(1), tStats.meshMessageDeliveries)
 }
+
+func TestGossipSub_ReportReputation(t *testing.T) {
+	rep, err := reputation.New(interfaces.DefaultReputationConfig(), nil)
+	require.NoError(t, err)
+
+	appScores := map[string]float64{"bad": -3000, "poor": -600, "good": 0}
+	params := DefaultScoreParams()
+	params.AppSpecificScore = func(peerID string) float64 { return appScores[peerID] }
+
+	config := DefaultConfig()
+	config.Reputation = rep
+	gs := &gossipSub{config: config, scorer: NewPeerScorer(params)}
+	for peerID := range appScores {
+		gs.scorer.AddPeer(peerID, "")
+	}
+
+	gs.reportReputation()
+
+	bad := rep.Get("bad")
+	require.Len(t, bad.Reasons, 1)
+	assert.Equal(t, "graylisted", bad.Reasons[0].Reason)
+	assert.InDelta(t, -30, bad.Score, 0.01)
+
+	poor := rep.Get("poor")
+	require.Len(t, poor.Reasons, 1)
+	assert.Equal(t, "below_gossip_threshold", poor.Reasons[0].Reason)
+	assert.InDelta(t, -600.0/2500*30, poor.Score, 0.01)
+
+	assert.Zero(t, rep.Score("good"))
+}
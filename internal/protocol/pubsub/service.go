@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	"github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/compress"
 	"github.com/dep2p/go-dep2p/pkg/lib/log"
 )
 
@@ -171,9 +172,18 @@ func (s *Service) Stop(_ context.Context) error {
 }
 
 // Join 加入主题
-func (s *Service) Join(topicName string, _ ...interfaces.TopicOption) (interfaces.Topic, error) {
+func (s *Service) Join(topicName string, opts ...interfaces.TopicOption) (interfaces.Topic, error) {
 	logger.Debug("加入主题", "topic", topicName)
 
+	var options interfaces.TopicOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	compression, err := compress.ParseAlgorithms(options.Compression)
+	if err != nil {
+		return nil, err
+	}
+
 	s.mu.RLock()
 	if !s.started {
 		s.mu.RUnlock()
@@ -183,7 +193,7 @@ func (s *Service) Join(topicName string, _ ...interfaces.TopicOption) (interface
 	s.mu.RUnlock()
 
 	// 加入主题(内部会检查重复)
-	t, err := s.gossip.Join(topicName)
+	t, err := s.gossip.Join(topicName, compression)
 	if err != nil {
 		logger.Error("加入主题失败", "topic", topicName, "error", err)
 		return nil, err
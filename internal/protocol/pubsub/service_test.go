@@ -1,9 +1,15 @@
 package pubsub
 
 import (
+	"bytes"
 	"context"
 	"testing"
+	"time"
 
+	"github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/compress"
+	"github.com/dep2p/go-dep2p/pkg/lib/tracing"
+	"github.com/dep2p/go-dep2p/tests/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -182,3 +188,116 @@ func TestService_Close(t *testing.T) {
 	require.NoError(t, err)
 	assert.False(t, svc.started)
 }
+
+func TestService_TopicCompression(t *testing.T) {
+	realm := mocks.NewMockRealm("realm-1")
+	realm.MemberList = []string{"peer-a", "peer-b"}
+	payload := bytes.Repeat([]byte("compressible "), 4096)
+
+	// publish 在内存网络上由 peer-a 发布一条消息，返回 peer-b 收到的数据和线路字节数
+	publish := func(t *testing.T, opts ...interfaces.TopicOption) ([]byte, int64) {
+		pn := mocks.NewPipeNet()
+		var svcs []*Service
+		for _, id := range []string{"peer-b", "peer-a"} {
+			svc, err := NewForRealm(pn.AddHost(id), realm, WithDisableHeartbeat(true))
+			require.NoError(t, err)
+			require.NoError(t, svc.Start(context.Background()))
+			t.Cleanup(func() { svc.Stop(context.Background()) })
+			svcs = append(svcs, svc)
+		}
+
+		topicB, err := svcs[0].Join("blocks")
+		require.NoError(t, err)
+		sub, err := topicB.Subscribe()
+		require.NoError(t, err)
+
+		topicA, err := svcs[1].Join("blocks", opts...)
+		require.NoError(t, err)
+		require.NoError(t, topicA.Publish(context.Background(), payload))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		msg, err := sub.Next(ctx)
+		require.NoError(t, err)
+		return msg.Data, pn.WireBytes()
+	}
+
+	plain, plainBytes := publish(t)
+	assert.Equal(t, payload, plain)
+
+	compressed, compressedBytes := publish(t, interfaces.WithTopicCompression("zstd"))
+	assert.Equal(t, payload, compressed)
+	assert.Less(t, compressedBytes*4, plainBytes)
+}
+
+func TestService_TracePropagation(t *testing.T) {
+	exp := tracing.NewMemoryExporter()
+	prev := tracing.SetExporter(exp)
+	t.Cleanup(func() { tracing.SetExporter(prev) })
+
+	realm := mocks.NewMockRealm("realm-1")
+	realm.MemberList = []string{"peer-a", "peer-b"}
+
+	pn := mocks.NewPipeNet()
+	var svcs []*Service
+	for _, id := range []string{"peer-b", "peer-a"} {
+		svc, err := NewForRealm(pn.AddHost(id), realm, WithDisableHeartbeat(true))
+		require.NoError(t, err)
+		require.NoError(t, svc.Start(context.Background()))
+		t.Cleanup(func() { svc.Stop(context.Background()) })
+		svcs = append(svcs, svc)
+	}
+
+	topicB, err := svcs[0].Join("blocks")
+	require.NoError(t, err)
+	sub, err := topicB.Subscribe()
+	require.NoError(t, err)
+
+	topicA, err := svcs[1].Join("blocks")
+	require.NoError(t, err)
+	ctx, root := tracing.Start(context.Background(), "app.op", tracing.KindInternal)
+	require.NoError(t, topicA.Publish(ctx, []byte("block-1")))
+	root.End(nil)
+
+	recvCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	msg, err := sub.Next(recvCtx)
+	require.NoError(t, err)
+
+	// 消息携带发布 Span 的 traceparent
+	sc, err := tracing.ParseTraceparent(msg.TraceParent)
+	require.NoError(t, err)
+	assert.Equal(t, root.SpanContext().TraceID, sc.TraceID)
+
+	find := func(name string) (tracing.SpanData, bool) {
+		_ = tracing.ForceFlush(context.Background())
+		for _, s := range exp.Spans() {
+			if s.Name == name {
+				return s, true
+			}
+		}
+		return tracing.SpanData{}, false
+	}
+	assert.Eventually(t, func() bool {
+		_, ok := find("dep2p.pubsub.deliver")
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+
+	publish, ok := find("dep2p.pubsub.publish")
+	require.True(t, ok)
+	deliver, _ := find("dep2p.pubsub.deliver")
+	assert.Equal(t, sc.SpanID, publish.SpanID)
+	assert.Equal(t, root.SpanContext().SpanID, publish.ParentSpanID)
+	assert.Equal(t, publish.SpanID, deliver.ParentSpanID)
+	assert.True(t, deliver.RemoteParent)
+}
+
+func TestService_TopicCompression_UnknownAlgorithm(t *testing.T) {
+	svc, err := New(newMockHost("peer-1"), newMockRealmManager(), WithDisableHeartbeat(true))
+	require.NoError(t, err)
+	require.NoError(t, svc.Start(context.Background()))
+	defer svc.Stop(context.Background())
+
+	_, err = svc.Join("blocks", interfaces.WithTopicCompression("lz4"))
+	assert.ErrorIs(t, err, compress.ErrUnsupported)
+}
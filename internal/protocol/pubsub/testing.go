@@ -42,7 +42,7 @@ func (m *mockHost) Connect(_ context.Context, _ string, _ []string) error {
 	return nil
 }
 
-func (m *mockHost) SetStreamHandler(protocolID string, handler interfaces.StreamHandler) {
+func (m *mockHost) SetStreamHandler(protocolID string, handler interfaces.StreamHandler, _ ...interfaces.StreamHandlerOption) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.handlers[protocolID] = handler
@@ -8,7 +8,9 @@ import (
 	"time"
 
 	"github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/compress"
 	pb "github.com/dep2p/go-dep2p/pkg/lib/proto/gossipsub"
+	"github.com/dep2p/go-dep2p/pkg/lib/tracing"
 )
 
 // topic 实现 Topic 接口
@@ -23,6 +25,9 @@ type topic struct {
 	peers         map[string]bool // 主题中的节点
 	closed        bool
 
+	// compression 发送时提议的压缩算法（Join 时确定）
+	compression []compress.Algorithm
+
 	// 消息去重：防止同一消息被多次投递给订阅者
 	deliveredMsgs   map[string]struct{}
 	deliveredMsgsMu sync.Mutex
@@ -78,6 +83,9 @@ func (t *topic) Publish(ctx context.Context, data []byte, opts ...interfaces.Pub
 		}
 	}
 
+	ctx, span := tracing.Start(ctx, "dep2p.pubsub.publish", tracing.KindProducer,
+		tracing.Attr("pubsub.topic", t.name))
+
 	// 创建消息
 	msg := &pb.Message{
 		From:  []byte(t.ps.host.ID()),
@@ -85,10 +93,15 @@ func (t *topic) Publish(ctx context.Context, data []byte, opts ...interfaces.Pub
 		Topic: t.name,
 		Seqno: generateSeqno(),
 	}
+	if sc := tracing.SpanContextFromContext(ctx); sc.IsValid() {
+		msg.Traceparent = sc.Traceparent()
+		msg.Tracestate = sc.State
+	}
 
 	// 验证消息
 	if err := t.ps.validator.Validate(ctx, t.ps.host.ID(), msg); err != nil {
 		logger.Warn("消息验证失败", "topic", t.name, "error", err)
+		span.End(err)
 		return err
 	}
 
@@ -103,8 +116,11 @@ func (t *topic) Publish(ctx context.Context, data []byte, opts ...interfaces.Pub
 		"peerCount", peerCount,
 		"sendTimeNano", sendTimeNano)
 
+	span.SetAttributes(tracing.Attr("pubsub.msg_id", msgID))
+
 	// 通过 GossipSub 发布
 	err := t.gossip.Publish(ctx, t.name, msg)
+	span.End(err)
 	if err != nil {
 		logger.Warn("消息发布失败", "topic", t.name, "msgID", msgID[:16], "error", err)
 	} else {
@@ -1,7 +1,11 @@
 // Package streams 实现流协议
 package streams
 
-import "time"
+import (
+	"time"
+
+	"github.com/dep2p/go-dep2p/pkg/lib/compress"
+)
 
 // Config 流服务配置
 type Config struct {
@@ -16,6 +20,9 @@ type Config struct {
 
 	// DefaultRealmID 默认Realm ID (可选)
 	DefaultRealmID string
+
+	// MaxDecompressedSize 压缩流的解压窗口上限
+	MaxDecompressedSize int
 }
 
 // DefaultConfig 返回默认配置
@@ -24,6 +31,8 @@ func DefaultConfig() *Config {
 		ReadTimeout:     30 * time.Second,
 		WriteTimeout:    30 * time.Second,
 		MaxStreamBuffer: 4096,
+
+		MaxDecompressedSize: compress.DefaultMaxDecompressedSize,
 	}
 }
 
@@ -51,6 +60,13 @@ func WithMaxStreamBuffer(size int) Option {
 	}
 }
 
+// WithMaxDecompressedSize 设置压缩流的解压窗口上限
+func WithMaxDecompressedSize(size int) Option {
+	return func(c *Config) {
+		c.MaxDecompressedSize = size
+	}
+}
+
 // WithDefaultRealmID 设置默认Realm ID
 func WithDefaultRealmID(realmID string) Option {
 	return func(c *Config) {
@@ -8,7 +8,9 @@ import (
 	"time"
 
 	"github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/compress"
 	"github.com/dep2p/go-dep2p/pkg/lib/log"
+	"github.com/dep2p/go-dep2p/pkg/lib/tracing"
 )
 
 var logger = log.Logger("protocol/streams")
@@ -138,7 +140,7 @@ func (s *Service) Open(ctx context.Context, peerID string, protocol string) (int
 // 允许指定流选项，如优先级。
 // 在 QUIC 连接上，优先级会传递给底层传输层，实现流级别的 QoS。
 // 在 TCP 连接上，优先级选项会被忽略（优雅降级）。
-func (s *Service) OpenWithOptions(ctx context.Context, peerID string, protocol string, opts interfaces.StreamOptions) (interfaces.BiStream, error) {
+func (s *Service) OpenWithOptions(ctx context.Context, peerID string, protocol string, opts interfaces.StreamOptions) (_ interfaces.BiStream, err error) {
 	s.mu.RLock()
 	if !s.started {
 		s.mu.RUnlock()
@@ -161,6 +163,12 @@ func (s *Service) OpenWithOptions(ctx context.Context, peerID string, protocol s
 		return nil, ErrInvalidPeerID
 	}
 
+	// 解析压缩算法
+	compression, err := compress.ParseAlgorithms(opts.Compression)
+	if err != nil {
+		return nil, err
+	}
+
 	// 确定使用的协议
 	var fullProtocol string
 	var found bool
@@ -198,14 +206,37 @@ func (s *Service) OpenWithOptions(ctx context.Context, peerID string, protocol s
 		return nil, ErrNoRealm
 	}
 
-	// 打开流（使用优先级）
-	stream, err := s.host.NewStreamWithPriority(ctx, peerID, fullProtocol, int(opts.Priority))
+	ctx, span := tracing.Start(ctx, "dep2p.streams.open", tracing.KindClient,
+		tracing.Attr("peer.id", peerID),
+		tracing.Attr("streams.protocol", protocol))
+	defer func() { span.End(err) }()
+
+	// 打开流（使用优先级；Realm 支持成员转发时按需走转发路径）
+	// 有追踪上下文时优先协商追踪变体，旧节点回退到原协议
+	protocolIDs := compress.Variants(fullProtocol, compression...)
+	sc := tracing.SpanContextFromContext(ctx)
+	if sc.IsValid() {
+		protocolIDs = append(compress.Variants(tracing.Variant(fullProtocol), compression...), protocolIDs...)
+	}
+	stream, err := s.openStream(ctx, peerID, protocolIDs, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	// 包装为 BiStream
+	// 追踪头部写在原始流上，位于压缩数据之前
+	if tracing.IsVariant(stream.Protocol()) {
+		if err := tracing.WriteStreamHeader(stream, sc); err != nil {
+			stream.Reset()
+			return nil, err
+		}
+	}
+
+	// 包装为 BiStream，按协商结果启用压缩
 	wrapper := newStreamWrapper(stream, fullProtocol)
+	if err := wrapper.enableCompression(compress.FromProtocol(stream.Protocol()), s.config.MaxDecompressedSize); err != nil {
+		stream.Reset()
+		return nil, err
+	}
 
 	// 应用配置的超时
 	s.applyStreamTimeouts(wrapper)
@@ -213,6 +244,44 @@ func (s *Service) OpenWithOptions(ctx context.Context, peerID string, protocol s
 	return wrapper, nil
 }
 
+// priorityOneOfOpener 支持带优先级协商多个协议的 Host
+type priorityOneOfOpener interface {
+	NewStreamWithPriorityOneOf(ctx context.Context, peerID string, priority int, protocolIDs ...string) (interfaces.Stream, error)
+}
+
+// openStream 打开到目标节点的底层流
+//
+// protocolIDs 按偏好排序，最后一个为未压缩的原协议，转发路径只协商原协议。
+// 绑定的 Realm 实现 interfaces.RouteDialer 时，转发路径延迟明显更低
+// 则优先转发，直连打开失败则回退到转发。
+func (s *Service) openStream(ctx context.Context, peerID string, protocolIDs []string, opts interfaces.StreamOptions) (interfaces.Stream, error) {
+	fullProtocol := protocolIDs[len(protocolIDs)-1]
+	dialer, routable := s.realm.(interfaces.RouteDialer)
+	if routable && dialer.ShouldRoute(peerID) {
+		if stream, err := dialer.NewRoutedStreamWithPriority(ctx, peerID, fullProtocol, int(opts.Priority)); err == nil {
+			return stream, nil
+		}
+	}
+
+	var stream interfaces.Stream
+	var err error
+	if opener, ok := s.host.(priorityOneOfOpener); ok && len(protocolIDs) > 1 {
+		stream, err = opener.NewStreamWithPriorityOneOf(ctx, peerID, int(opts.Priority), protocolIDs...)
+	} else if len(protocolIDs) > 1 {
+		// Host 不支持带优先级的多协议协商时，优先保证压缩协商
+		stream, err = s.host.NewStream(ctx, peerID, protocolIDs...)
+	} else {
+		stream, err = s.host.NewStreamWithPriority(ctx, peerID, fullProtocol, int(opts.Priority))
+	}
+	if err != nil && routable {
+		if routed, rerr := dialer.NewRoutedStreamWithPriority(ctx, peerID, fullProtocol, int(opts.Priority)); rerr == nil {
+			logger.Debug("直连不可用，经成员转发打开流", "peerID", log.TruncateID(peerID, 8))
+			return routed, nil
+		}
+	}
+	return stream, err
+}
+
 // applyStreamTimeouts 应用配置的流超时
 //
 // 如果配置了 ReadTimeout 或 WriteTimeout，自动设置到流上。
@@ -286,16 +355,59 @@ func (s *Service) RegisterHandler(protocol string, handler interfaces.BiStreamHa
 }
 
 // registerHostHandler 在 Host 层注册处理器
+//
+// 同时注册所有压缩变体和追踪变体，是否压缩、是否携带追踪头部
+// 由打开流的一方决定。
 func (s *Service) registerHostHandler(fullProtocol string, handler interfaces.BiStreamHandler) {
-	// 将 BiStreamHandler 适配为 StreamHandler
-	s.host.SetStreamHandler(fullProtocol, func(stream interfaces.Stream) {
+	traced := tracing.Variant(fullProtocol)
+	s.host.SetStreamHandler(fullProtocol, s.adaptHandler(fullProtocol, handler, compress.None, false))
+	s.host.SetStreamHandler(traced, s.adaptHandler(fullProtocol, handler, compress.None, true))
+	for _, alg := range compress.Supported {
+		s.host.SetStreamHandler(compress.Variant(fullProtocol, alg), s.adaptHandler(fullProtocol, handler, alg, false))
+		s.host.SetStreamHandler(compress.Variant(traced, alg), s.adaptHandler(fullProtocol, handler, alg, true))
+	}
+}
+
+// adaptHandler 将 BiStreamHandler 适配为 StreamHandler
+//
+// traced 为 true 时先读取流首部的追踪头部，并记录 dep2p.streams.handle Span。
+func (s *Service) adaptHandler(fullProtocol string, handler interfaces.BiStreamHandler, alg compress.Algorithm, traced bool) interfaces.StreamHandler {
+	return func(stream interfaces.Stream) {
 		// 包装为 BiStream
 		wrapper := newStreamWrapper(stream, fullProtocol)
+		if traced {
+			sc, err := tracing.ReadStreamHeader(stream)
+			if err != nil {
+				stream.Reset()
+				return
+			}
+			ctx, span := tracing.Start(tracing.ContextWithSpanContext(s.ctx, sc),
+				"dep2p.streams.handle", tracing.KindServer,
+				tracing.Attr("peer.id", wrapper.RemotePeer()),
+				tracing.Attr("streams.protocol", fullProtocol))
+			defer span.End(nil)
+			wrapper.ctx = ctx
+		}
+		if err := wrapper.enableCompression(alg, s.config.MaxDecompressedSize); err != nil {
+			stream.Reset()
+			return
+		}
 		// 应用配置的超时（入站流也需要超时保护）
 		s.applyStreamTimeouts(wrapper)
 		// 调用用户处理器
 		handler(wrapper)
-	})
+	}
+}
+
+// removeHostHandler 在 Host 层注销处理器及其压缩变体、追踪变体
+func (s *Service) removeHostHandler(fullProtocol string) {
+	traced := tracing.Variant(fullProtocol)
+	s.host.RemoveStreamHandler(fullProtocol)
+	s.host.RemoveStreamHandler(traced)
+	for _, alg := range compress.Supported {
+		s.host.RemoveStreamHandler(compress.Variant(fullProtocol, alg))
+		s.host.RemoveStreamHandler(compress.Variant(traced, alg))
+	}
 }
 
 // UnregisterHandler 注销流处理器
@@ -324,13 +436,13 @@ func (s *Service) UnregisterHandler(protocol string) error {
 	if s.realm != nil && s.realmID != "" {
 		// Realm-bound 模式：只移除绑定的 Realm 的处理器
 		fullProtocol := buildProtocolID(s.realmID, protocol)
-		s.host.RemoveStreamHandler(fullProtocol)
+		s.removeHostHandler(fullProtocol)
 	} else if s.realmMgr != nil {
 		// 全局模式：从所有 Realm 移除处理器
 		realms := s.realmMgr.ListRealms()
 		for _, realm := range realms {
 			fullProtocol := buildProtocolID(realm.ID(), protocol)
-			s.host.RemoveStreamHandler(fullProtocol)
+			s.removeHostHandler(fullProtocol)
 		}
 
 		// 如果有默认Realm，也移除
@@ -338,7 +450,7 @@ func (s *Service) UnregisterHandler(protocol string) error {
 			realm, ok := s.realmMgr.GetRealm(s.config.DefaultRealmID)
 			if ok {
 				fullProtocol := buildProtocolID(realm.ID(), protocol)
-				s.host.RemoveStreamHandler(fullProtocol)
+				s.removeHostHandler(fullProtocol)
 			}
 		}
 	}
@@ -360,13 +472,13 @@ func (s *Service) Close() error {
 		if s.realm != nil && s.realmID != "" {
 			// Realm-bound 模式：只移除绑定的 Realm 的处理器
 			fullProtocol := buildProtocolID(s.realmID, protocol)
-			s.host.RemoveStreamHandler(fullProtocol)
+			s.removeHostHandler(fullProtocol)
 		} else if s.realmMgr != nil {
 			// 全局模式：从所有 Realm 移除处理器
 			realms := s.realmMgr.ListRealms()
 			for _, realm := range realms {
 				fullProtocol := buildProtocolID(realm.ID(), protocol)
-				s.host.RemoveStreamHandler(fullProtocol)
+				s.removeHostHandler(fullProtocol)
 			}
 		}
 	}
@@ -2,11 +2,17 @@
 package streams
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"io"
 	"testing"
 	"time"
 
 	"github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/compress"
+	"github.com/dep2p/go-dep2p/pkg/lib/tracing"
+	"github.com/dep2p/go-dep2p/tests/mocks"
 )
 
 func TestService_New(t *testing.T) {
@@ -276,3 +282,184 @@ func TestConfig_DefaultConfig(t *testing.T) {
 	}
 	t.Log("✅ DefaultConfig 测试通过")
 }
+
+// echoOverPipeNet 在内存网络上打开流并回显数据，返回协商的压缩算法和线路字节数
+func echoOverPipeNet(t *testing.T, payload []byte, opts interfaces.StreamOptions) (compress.Algorithm, int64) {
+	t.Helper()
+
+	realm := mocks.NewMockRealm("realm-1")
+	realm.MemberList = []string{"peer-a", "peer-b"}
+	pn := mocks.NewPipeNet()
+
+	server, _ := NewForRealm(pn.AddHost("peer-b"), realm)
+	server.Start(context.Background())
+	defer server.Stop(context.Background())
+	if err := server.RegisterHandler("echo", func(s interfaces.BiStream) {
+		defer s.Close()
+		buf := make([]byte, len(payload))
+		if _, err := io.ReadFull(s, buf); err != nil {
+			t.Errorf("server read: %v", err)
+			return
+		}
+		s.Write(buf)
+	}); err != nil {
+		t.Fatalf("RegisterHandler() failed: %v", err)
+	}
+
+	client, _ := NewForRealm(pn.AddHost("peer-a"), realm)
+	client.Start(context.Background())
+	defer client.Stop(context.Background())
+
+	stream, err := client.OpenWithOptions(context.Background(), "peer-b", "echo", opts)
+	if err != nil {
+		t.Fatalf("OpenWithOptions() failed: %v", err)
+	}
+	defer stream.Close()
+
+	if _, err := stream.Write(payload); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	echo := make([]byte, len(payload))
+	if _, err := io.ReadFull(stream, echo); err != nil {
+		t.Fatalf("Read() failed: %v", err)
+	}
+	if !bytes.Equal(echo, payload) {
+		t.Fatal("echo mismatch")
+	}
+	return stream.(*streamWrapper).Compression(), pn.WireBytes()
+}
+
+func TestService_OpenWithCompression(t *testing.T) {
+	payload := bytes.Repeat([]byte("compressible "), 4096)
+
+	alg, plainBytes := echoOverPipeNet(t, payload, interfaces.DefaultStreamOptions())
+	if alg != compress.None {
+		t.Errorf("Compression() = %s, want none", alg)
+	}
+
+	for _, want := range []compress.Algorithm{compress.Zstd, compress.Snappy} {
+		opts := interfaces.DefaultStreamOptions()
+		opts.Compression = []string{string(want)}
+
+		alg, wireBytes := echoOverPipeNet(t, payload, opts)
+		if alg != want {
+			t.Errorf("Compression() = %s, want %s", alg, want)
+		}
+		if wireBytes*4 > plainBytes {
+			t.Errorf("%s: wire bytes %d not much smaller than plain %d", want, wireBytes, plainBytes)
+		}
+	}
+}
+
+func TestService_OpenWithUnknownCompression(t *testing.T) {
+	svc, _ := NewForRealm(newMockHost("peer1"), mocks.NewMockRealm("realm-1"))
+	svc.Start(context.Background())
+	defer svc.Stop(context.Background())
+
+	opts := interfaces.DefaultStreamOptions()
+	opts.Compression = []string{"lz4"}
+	if _, err := svc.OpenWithOptions(context.Background(), "peer2", "echo", opts); !errors.Is(err, compress.ErrUnsupported) {
+		t.Errorf("OpenWithOptions() error = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestService_TracePropagation(t *testing.T) {
+	exp := tracing.NewMemoryExporter()
+	prev := tracing.SetExporter(exp)
+	defer tracing.SetExporter(prev)
+
+	realm := mocks.NewMockRealm("realm-1")
+	realm.MemberList = []string{"peer-a", "peer-b"}
+	pn := mocks.NewPipeNet()
+
+	server, _ := NewForRealm(pn.AddHost("peer-b"), realm)
+	server.Start(context.Background())
+	defer server.Stop(context.Background())
+
+	handled := make(chan tracing.SpanContext, 1)
+	if err := server.RegisterHandler("echo", func(s interfaces.BiStream) {
+		defer s.Close()
+		handled <- tracing.SpanContextFromContext(s.(interfaces.ContextStream).Context())
+		io.Copy(s, s)
+	}); err != nil {
+		t.Fatalf("RegisterHandler() failed: %v", err)
+	}
+
+	client, _ := NewForRealm(pn.AddHost("peer-a"), realm)
+	client.Start(context.Background())
+	defer client.Stop(context.Background())
+
+	// 追踪头部与压缩同时协商
+	opts := interfaces.DefaultStreamOptions()
+	opts.Compression = []string{string(compress.Zstd)}
+	ctx, root := tracing.Start(context.Background(), "app.op", tracing.KindInternal)
+	stream, err := client.OpenWithOptions(ctx, "peer-b", "echo", opts)
+	if err != nil {
+		t.Fatalf("OpenWithOptions() failed: %v", err)
+	}
+	if got := stream.(*streamWrapper).Compression(); got != compress.Zstd {
+		t.Errorf("Compression() = %s, want zstd", got)
+	}
+	if got := stream.Protocol(); got != buildProtocolID("realm-1", "echo") {
+		t.Errorf("Protocol() = %s, want base protocol", got)
+	}
+
+	stream.Write([]byte("hello"))
+	stream.CloseWrite()
+	echo, _ := io.ReadAll(stream)
+	stream.Close()
+	if string(echo) != "hello" {
+		t.Errorf("echo = %q, want hello", echo)
+	}
+
+	remote := <-handled
+	if remote.TraceID != root.SpanContext().TraceID {
+		t.Errorf("handler TraceID = %s, want %s", remote.TraceID, root.SpanContext().TraceID)
+	}
+	root.End(nil)
+	server.Stop(context.Background())
+
+	if err := tracing.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush() failed: %v", err)
+	}
+	var open tracing.SpanData
+	for _, s := range exp.Spans() {
+		if s.Name == "dep2p.streams.open" {
+			open = s
+		}
+	}
+	if open.ParentSpanID != root.SpanContext().SpanID {
+		t.Error("dep2p.streams.open should be a child of the caller span")
+	}
+}
+
+func TestService_TraceFallbackToPlain(t *testing.T) {
+	realm := mocks.NewMockRealm("realm-1")
+	realm.MemberList = []string{"peer-a", "peer-b"}
+	pn := mocks.NewPipeNet()
+
+	// peer-b 为不支持追踪变体的旧节点，只注册原协议
+	pn.AddHost("peer-b").SetStreamHandler(buildProtocolID("realm-1", "echo"), func(s interfaces.Stream) {
+		defer s.Close()
+		io.Copy(s, s)
+	})
+
+	client, _ := NewForRealm(pn.AddHost("peer-a"), realm)
+	client.Start(context.Background())
+	defer client.Stop(context.Background())
+
+	ctx := tracing.ContextWithTraceparent(context.Background(),
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "")
+	stream, err := client.Open(ctx, "peer-b", "echo")
+	if err != nil {
+		t.Fatalf("Open() failed: %v", err)
+	}
+	defer stream.Close()
+
+	stream.Write([]byte("hello"))
+	stream.CloseWrite()
+	echo, _ := io.ReadAll(stream)
+	if string(echo) != "hello" {
+		t.Errorf("echo = %q, want hello (no trace header on plain protocol)", echo)
+	}
+}
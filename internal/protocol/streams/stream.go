@@ -2,10 +2,13 @@
 package streams
 
 import (
+	"context"
+	"io"
 	"sync/atomic"
 	"time"
 
 	"github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/compress"
 	"github.com/dep2p/go-dep2p/pkg/types"
 )
 
@@ -22,10 +25,22 @@ type streamWrapper struct {
 	protocol atomic.Value      // 协议ID (string)
 	opened   int64             // 打开时间戳
 	closed   atomic.Bool       // 是否已关闭（使用 atomic 避免锁）
+
+	// 压缩（协商到压缩变体时设置，否则为 nil）
+	compression compress.Algorithm
+	reader      io.ReadCloser
+	writer      io.WriteCloser
+	writeDone   atomic.Bool // 压缩流是否已结束
+
+	// 追踪上下文（入站追踪变体时设置）
+	ctx context.Context
 }
 
 // 确保 streamWrapper 实现了 interfaces.BiStream 接口
-var _ interfaces.BiStream = (*streamWrapper)(nil)
+var (
+	_ interfaces.BiStream      = (*streamWrapper)(nil)
+	_ interfaces.ContextStream = (*streamWrapper)(nil)
+)
 
 // newStreamWrapper 创建流包装器
 func newStreamWrapper(stream interfaces.Stream, protocol string) *streamWrapper {
@@ -37,6 +52,33 @@ func newStreamWrapper(stream interfaces.Stream, protocol string) *streamWrapper
 	return w
 }
 
+// enableCompression 按协商的算法启用压缩
+//
+// 必须在流交给用户之前调用。
+func (w *streamWrapper) enableCompression(alg compress.Algorithm, maxSize int) error {
+	if alg == compress.None {
+		return nil
+	}
+	reader, err := compress.NewReader(alg, w.stream, maxSize)
+	if err != nil {
+		return err
+	}
+	writer, err := compress.NewWriter(alg, w.stream)
+	if err != nil {
+		reader.Close()
+		return err
+	}
+	w.compression = alg
+	w.reader = reader
+	w.writer = writer
+	return nil
+}
+
+// Compression 返回协商的压缩算法
+func (w *streamWrapper) Compression() compress.Algorithm {
+	return w.compression
+}
+
 // Read 从流读取数据
 //
 // 注意：不在 IO 期间持锁，以允许 Close() 中断阻塞的读取。
@@ -49,7 +91,13 @@ func (w *streamWrapper) Read(p []byte) (int, error) {
 
 	// 执行 IO（不持锁）
 	// 如果在 Read 期间调用 Close()，底层流的 Close 会中断 Read
-	n, err := w.stream.Read(p)
+	var n int
+	var err error
+	if w.reader != nil {
+		n, err = w.reader.Read(p)
+	} else {
+		n, err = w.stream.Read(p)
+	}
 
 	// 如果流在读取期间被关闭，将错误包装为更友好的形式
 	if err != nil && w.closed.Load() {
@@ -69,7 +117,13 @@ func (w *streamWrapper) Write(p []byte) (int, error) {
 	}
 
 	// 执行 IO（不持锁）
-	n, err := w.stream.Write(p)
+	var n int
+	var err error
+	if w.writer != nil {
+		n, err = w.writer.Write(p)
+	} else {
+		n, err = w.stream.Write(p)
+	}
 
 	// 如果流在写入期间被关闭，将错误包装为更友好的形式
 	if err != nil && w.closed.Load() {
@@ -89,9 +143,28 @@ func (w *streamWrapper) Close() error {
 		return nil // 已关闭
 	}
 
+	// 结束压缩流，使对端能读到完整数据
+	w.closeCompression()
+
 	return w.stream.Close()
 }
 
+// closeCompression 结束压缩流并释放解压器
+func (w *streamWrapper) closeCompression() {
+	w.finishWrite()
+	if w.reader != nil {
+		w.reader.Close()
+	}
+}
+
+// finishWrite 结束压缩流（只执行一次）
+func (w *streamWrapper) finishWrite() error {
+	if w.writer == nil || !w.writeDone.CompareAndSwap(false, true) {
+		return nil
+	}
+	return w.writer.Close()
+}
+
 // Reset 重置流（异常关闭）
 //
 // 强制关闭流，不等待优雅关闭。
@@ -101,6 +174,10 @@ func (w *streamWrapper) Reset() error {
 		return nil // 已关闭
 	}
 
+	if w.reader != nil {
+		w.reader.Close()
+	}
+
 	return w.stream.Reset()
 }
 
@@ -112,6 +189,16 @@ func (w *streamWrapper) Protocol() string {
 	return ""
 }
 
+// Context 返回流的上下文
+//
+// 入站流经追踪变体协商时包含打开方的追踪上下文。
+func (w *streamWrapper) Context() context.Context {
+	if w.ctx != nil {
+		return w.ctx
+	}
+	return context.Background()
+}
+
 // RemotePeer 返回远端节点ID
 //
 // 注意：如果连接已断开，可能返回空字符串。
@@ -151,6 +238,9 @@ func (w *streamWrapper) CloseWrite() error {
 	if w.closed.Load() {
 		return nil
 	}
+	if err := w.finishWrite(); err != nil {
+		return err
+	}
 	return w.stream.CloseWrite()
 }
 
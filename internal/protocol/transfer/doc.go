@@ -0,0 +1,51 @@
+// Package transfer 实现可校验、可续传的大文件传输服务
+//
+// 协议标识: /dep2p/app/<realmID>/streams/dep2p.transfer/1.0.0
+//
+// # 架构定位
+//
+// - 架构层: Protocol Layer (L4)
+// - 公共接口: pkg/interfaces/transfer.go
+// - 依赖: internal/protocol/streams, internal/discovery/dht
+//
+// # 内容寻址
+//
+// 文件按 ChunkSize 分块，每个分块的叶子哈希为 SHA-256(0x00 || chunk)，
+// 内部节点为 SHA-256(0x01 || left || right)，奇数节点直接提升。
+// 内容 ID（CID）绑定文件大小、分块大小和 Merkle 根：
+//
+//	CID = hex(SHA-256(0x02 || size || chunkSize || root))
+//
+// 下载方先向提供者获取清单（Manifest），重新计算 CID 校验清单，
+// 之后每个分块都与清单中的叶子哈希比对，校验失败的提供者会被弃用。
+//
+// # 多源下载
+//
+// 共享内容时通过 DHT Provide 宣告 "transfer/<cid>"，下载方通过
+// FindProviders 查找持有同一内容的 Realm 成员，并行地从多个提供者
+// 拉取不同分块。
+//
+// # 续传
+//
+// 数据写入 dest + ".part"。再次下载同一 CID 时，已存在的分块会被
+// 重新校验，只拉取缺失或损坏的分块；全部完成后原子地重命名为 dest。
+//
+// # 限速
+//
+// 上传和下载分别限速。消耗量优先从 Swarm 的带宽计数器中读取
+// （按传输协议统计），计数器不可用时退化为服务自身计数。
+//
+// # 使用示例
+//
+//	tr := realm.Transfer()
+//
+//	// 提供方
+//	cid, _ := tr.Share(ctx, "/data/video.mp4")
+//
+//	// 下载方
+//	err := tr.Download(ctx, cid, "/tmp/video.mp4", interfaces.DownloadOptions{
+//	    Progress: func(p interfaces.TransferProgress) {
+//	        fmt.Printf("%d/%d\n", p.DoneBytes, p.TotalBytes)
+//	    },
+//	})
+package transfer
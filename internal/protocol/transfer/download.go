@@ -0,0 +1,384 @@
+// Package transfer 实现可校验、可续传的大文件传输服务
+package transfer
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/log"
+)
+
+// partSuffix 下载中文件的后缀
+const partSuffix = ".part"
+
+// Download 下载内容到 dest
+func (s *Service) Download(ctx context.Context, cid string, dest string, opts interfaces.DownloadOptions) error {
+	svcCtx, err := s.serviceContext()
+	if err != nil {
+		return err
+	}
+	rawCID, err := parseCID(cid)
+	if err != nil {
+		return err
+	}
+
+	// 服务停止时中止下载
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(svcCtx, cancel)
+	defer stop()
+
+	providers := s.findProviders(ctx, cid, opts.Providers)
+	if len(providers) == 0 {
+		return ErrNoProviders
+	}
+
+	manifest, err := s.fetchManifest(ctx, rawCID, cid, providers)
+	if err != nil {
+		return err
+	}
+
+	dest, err = filepath.Abs(dest)
+	if err != nil {
+		return err
+	}
+	d, err := newDownload(s, rawCID, cid, manifest, dest+partSuffix, opts)
+	if err != nil {
+		return err
+	}
+
+	maxPeers := opts.MaxPeers
+	if maxPeers <= 0 {
+		maxPeers = s.config.MaxPeers
+	}
+	if len(providers) > maxPeers {
+		providers = providers[:maxPeers]
+	}
+
+	if err := d.run(ctx, providers); err != nil {
+		d.file.Close()
+		return err
+	}
+
+	if err := d.file.Sync(); err != nil {
+		d.file.Close()
+		return err
+	}
+	if err := d.file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(dest+partSuffix, dest); err != nil {
+		return err
+	}
+
+	logger.Info("下载完成", "cid", cid[:16], "size", manifest.Size)
+
+	if opts.Seed {
+		s.addShare(cid, dest, manifest)
+		s.announce(ctx, cid)
+	}
+	return nil
+}
+
+// findProviders 合并显式指定的提供者和 DHT 查询结果
+//
+// 只保留 Realm 成员，排除本节点。
+func (s *Service) findProviders(ctx context.Context, cid string, explicit []string) []string {
+	localID := s.host.ID()
+	seen := make(map[string]bool)
+	var providers []string
+	add := func(peerID string) {
+		if peerID == "" || peerID == localID || seen[peerID] || !s.realm.IsMember(peerID) {
+			return
+		}
+		seen[peerID] = true
+		providers = append(providers, peerID)
+	}
+
+	for _, p := range explicit {
+		add(p)
+	}
+
+	if s.routing == nil {
+		return providers
+	}
+
+	findCtx, cancel := context.WithTimeout(ctx, s.config.FindProvidersTimeout)
+	defer cancel()
+	ch, err := s.routing.FindProviders(findCtx, providerKey(cid))
+	if err != nil {
+		logger.Debug("查找内容提供者失败", "cid", cid[:16], "error", err)
+		return providers
+	}
+
+	// 收集到足够的提供者后即停止等待
+	want := len(providers) + s.config.MaxPeers
+	for len(providers) < want {
+		select {
+		case info, ok := <-ch:
+			if !ok {
+				return providers
+			}
+			add(string(info.ID))
+		case <-findCtx.Done():
+			return providers
+		}
+	}
+	return providers
+}
+
+// fetchManifest 依次向提供者请求清单，返回第一个校验通过的清单
+func (s *Service) fetchManifest(ctx context.Context, rawCID [sha256.Size]byte, cid string, providers []string) (*Manifest, error) {
+	lastErr := error(ErrNoProviders)
+	for _, peerID := range providers {
+		manifest, err := s.requestManifest(ctx, peerID, rawCID, cid)
+		if err == nil {
+			return manifest, nil
+		}
+		logger.Debug("获取清单失败", "peer", log.TruncateID(peerID, 8), "error", err)
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+func (s *Service) requestManifest(ctx context.Context, peerID string, rawCID [sha256.Size]byte, cid string) (*Manifest, error) {
+	stream, err := s.streams.Open(ctx, peerID, StreamProtocol)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	stream.SetDeadline(time.Now().Add(s.config.RequestTimeout))
+	if err := writeRequest(stream, request{typ: msgGetManifest, cid: rawCID}); err != nil {
+		return nil, err
+	}
+	data, err := readResponse(stream, maxManifestLen)
+	if err != nil {
+		return nil, err
+	}
+	return UnmarshalManifest(data, cid)
+}
+
+// ============================================================================
+//                              下载任务
+// ============================================================================
+
+// download 单次下载任务
+type download struct {
+	svc      *Service
+	rawCID   [sha256.Size]byte
+	cid      string
+	manifest *Manifest
+	file     *os.File
+	progress func(interfaces.TransferProgress)
+
+	// queue 待下载的分块
+	queue chan int
+
+	// reportMu 串行化进度回调
+	reportMu sync.Mutex
+
+	mu        sync.Mutex
+	remaining int
+	doneBytes int64
+	peers     int
+	lastErr   error
+	finished  chan struct{}
+}
+
+// newDownload 打开（或续传）下载文件，校验已存在的分块
+func newDownload(s *Service, rawCID [sha256.Size]byte, cid string, manifest *Manifest, partPath string, opts interfaces.DownloadOptions) (*download, error) {
+	_, statErr := os.Stat(partPath)
+	resuming := statErr == nil
+
+	file, err := os.OpenFile(partPath, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err := file.Truncate(manifest.Size); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	d := &download{
+		svc:      s,
+		rawCID:   rawCID,
+		cid:      cid,
+		manifest: manifest,
+		file:     file,
+		progress: opts.Progress,
+		queue:    make(chan int, manifest.NumChunks()),
+		finished: make(chan struct{}),
+	}
+
+	buf := make([]byte, manifest.ChunkSize)
+	for i := 0; i < manifest.NumChunks(); i++ {
+		if resuming {
+			data := buf[:manifest.ChunkLen(i)]
+			if _, err := file.ReadAt(data, manifest.ChunkOffset(i)); err == nil || errors.Is(err, io.EOF) {
+				if manifest.VerifyChunk(i, data) == nil {
+					d.doneBytes += int64(len(data))
+					continue
+				}
+			}
+		}
+		d.queue <- i
+		d.remaining++
+	}
+
+	if resuming {
+		logger.Info("续传下载", "cid", cid[:16],
+			"done", manifest.NumChunks()-d.remaining, "total", manifest.NumChunks())
+	}
+	if d.remaining == 0 {
+		close(d.finished)
+	}
+	return d, nil
+}
+
+// run 从多个提供者并行下载剩余分块
+func (d *download) run(ctx context.Context, providers []string) error {
+	if d.remaining == 0 {
+		d.report()
+		return nil
+	}
+
+	d.mu.Lock()
+	d.peers = len(providers)
+	d.mu.Unlock()
+	d.report()
+
+	var wg sync.WaitGroup
+	for _, peerID := range providers {
+		wg.Add(1)
+		go func(peerID string) {
+			defer wg.Done()
+			err := d.worker(ctx, peerID)
+
+			d.mu.Lock()
+			d.peers--
+			if err != nil {
+				d.lastErr = err
+			}
+			d.mu.Unlock()
+			if err != nil {
+				logger.Debug("提供者下载中止", "peer", log.TruncateID(peerID, 8), "error", err)
+			}
+		}(peerID)
+	}
+	wg.Wait()
+
+	select {
+	case <-d.finished:
+		return nil
+	default:
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.lastErr != nil {
+		return fmt.Errorf("%w: %d chunks missing: %v", ErrNoProviders, d.remaining, d.lastErr)
+	}
+	return fmt.Errorf("%w: %d chunks missing", ErrNoProviders, d.remaining)
+}
+
+// worker 通过一个流从单个提供者顺序拉取分块
+//
+// 出错或分块校验失败时把分块放回队列并放弃该提供者。
+func (d *download) worker(ctx context.Context, peerID string) error {
+	stream, err := d.svc.streams.Open(ctx, peerID, StreamProtocol)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	maxLen := d.manifest.ChunkSize
+	for {
+		var index int
+		select {
+		case <-d.finished:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		case index = <-d.queue:
+		}
+
+		data, err := d.fetchChunk(ctx, stream, index, maxLen)
+		if err == nil {
+			err = d.manifest.VerifyChunk(index, data)
+		}
+		if err != nil {
+			d.queue <- index
+			stream.Reset()
+			return err
+		}
+
+		if _, err := d.file.WriteAt(data, d.manifest.ChunkOffset(index)); err != nil {
+			d.queue <- index
+			return err
+		}
+		d.complete(len(data))
+	}
+}
+
+func (d *download) fetchChunk(ctx context.Context, stream interfaces.BiStream, index, maxLen int) ([]byte, error) {
+	if err := d.svc.download.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	stream.SetDeadline(time.Now().Add(d.svc.config.RequestTimeout))
+	if err := writeRequest(stream, request{typ: msgGetChunk, cid: d.rawCID, index: uint32(index)}); err != nil {
+		return nil, err
+	}
+	data, err := readResponse(stream, maxLen)
+	if err != nil {
+		return nil, err
+	}
+	d.svc.recvBytes.Add(int64(len(data)))
+	return data, nil
+}
+
+// complete 记录一个分块完成
+func (d *download) complete(n int) {
+	d.mu.Lock()
+	d.remaining--
+	d.doneBytes += int64(n)
+	if d.remaining == 0 {
+		close(d.finished)
+	}
+	d.mu.Unlock()
+
+	d.report()
+}
+
+// report 调用进度回调
+func (d *download) report() {
+	if d.progress == nil {
+		return
+	}
+	d.mu.Lock()
+	p := interfaces.TransferProgress{
+		CID:         d.cid,
+		TotalBytes:  d.manifest.Size,
+		DoneBytes:   d.doneBytes,
+		TotalChunks: d.manifest.NumChunks(),
+		DoneChunks:  d.manifest.NumChunks() - d.remaining,
+		Peers:       d.peers,
+	}
+	d.mu.Unlock()
+
+	d.reportMu.Lock()
+	defer d.reportMu.Unlock()
+	d.progress(p)
+}
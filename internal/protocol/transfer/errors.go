@@ -0,0 +1,43 @@
+// Package transfer 实现可校验、可续传的大文件传输服务
+package transfer
+
+import "errors"
+
+// 错误定义
+var (
+	// ErrNotStarted 服务未启动
+	ErrNotStarted = errors.New("transfer: service not started")
+
+	// ErrAlreadyStarted 服务已启动
+	ErrAlreadyStarted = errors.New("transfer: service already started")
+
+	// ErrNilHost Host 为 nil
+	ErrNilHost = errors.New("transfer: host is nil")
+
+	// ErrNilRealm Realm 为 nil
+	ErrNilRealm = errors.New("transfer: realm is nil")
+
+	// ErrNilStreams Streams 服务为 nil
+	ErrNilStreams = errors.New("transfer: streams service is nil")
+
+	// ErrInvalidCID 无效的内容 ID
+	ErrInvalidCID = errors.New("transfer: invalid content id")
+
+	// ErrInvalidManifest 清单格式错误或与内容 ID 不符
+	ErrInvalidManifest = errors.New("transfer: invalid manifest")
+
+	// ErrChunkMismatch 分块与清单中的哈希不符
+	ErrChunkMismatch = errors.New("transfer: chunk hash mismatch")
+
+	// ErrFileTooLarge 文件分块数超过上限
+	ErrFileTooLarge = errors.New("transfer: file too large")
+
+	// ErrNotFound 内容未共享
+	ErrNotFound = errors.New("transfer: content not found")
+
+	// ErrNoProviders 没有可用的提供者
+	ErrNoProviders = errors.New("transfer: no providers available")
+
+	// ErrRemote 提供者返回错误
+	ErrRemote = errors.New("transfer: remote error")
+)
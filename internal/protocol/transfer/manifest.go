@@ -0,0 +1,208 @@
+// Package transfer 实现可校验、可续传的大文件传输服务
+package transfer
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Merkle 哈希域分隔前缀
+const (
+	leafPrefix  = 0x00
+	innerPrefix = 0x01
+	cidPrefix   = 0x02
+)
+
+// manifestHeaderSize 清单头部长度：size(8) + chunkSize(4) + count(4)
+const manifestHeaderSize = 16
+
+// Manifest 内容清单
+//
+// 清单描述内容的大小、分块方式和每个分块的叶子哈希。
+// 清单本身由 CID 校验，因此可以从任意提供者获取。
+type Manifest struct {
+	// Size 内容总字节数
+	Size int64
+
+	// ChunkSize 分块大小（最后一块可能更短）
+	ChunkSize int
+
+	// Leaves 每个分块的叶子哈希
+	Leaves [][sha256.Size]byte
+}
+
+// BuildManifest 读取 r 的全部内容并生成清单
+func BuildManifest(r io.Reader, chunkSize int) (*Manifest, error) {
+	if chunkSize < MinChunkSize || chunkSize > MaxChunkSize {
+		return nil, fmt.Errorf("transfer: chunk size %d out of range", chunkSize)
+	}
+
+	m := &Manifest{ChunkSize: chunkSize}
+	buf := make([]byte, chunkSize)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			if len(m.Leaves) >= MaxChunks {
+				return nil, ErrFileTooLarge
+			}
+			m.Leaves = append(m.Leaves, leafHash(buf[:n]))
+			m.Size += int64(n)
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 空内容视为一个空分块，保证 Merkle 根有定义
+	if len(m.Leaves) == 0 {
+		m.Leaves = append(m.Leaves, leafHash(nil))
+	}
+	return m, nil
+}
+
+// NumChunks 返回分块数
+func (m *Manifest) NumChunks() int {
+	return len(m.Leaves)
+}
+
+// ChunkLen 返回第 index 个分块的长度
+func (m *Manifest) ChunkLen(index int) int {
+	offset := int64(index) * int64(m.ChunkSize)
+	if remain := m.Size - offset; remain < int64(m.ChunkSize) {
+		return int(remain)
+	}
+	return m.ChunkSize
+}
+
+// ChunkOffset 返回第 index 个分块在内容中的偏移
+func (m *Manifest) ChunkOffset(index int) int64 {
+	return int64(index) * int64(m.ChunkSize)
+}
+
+// VerifyChunk 校验第 index 个分块
+func (m *Manifest) VerifyChunk(index int, data []byte) error {
+	if index < 0 || index >= len(m.Leaves) {
+		return fmt.Errorf("%w: index %d out of range", ErrChunkMismatch, index)
+	}
+	if len(data) != m.ChunkLen(index) || leafHash(data) != m.Leaves[index] {
+		return fmt.Errorf("%w: chunk %d", ErrChunkMismatch, index)
+	}
+	return nil
+}
+
+// Root 计算 Merkle 根
+func (m *Manifest) Root() [sha256.Size]byte {
+	level := append([][sha256.Size]byte(nil), m.Leaves...)
+	for len(level) > 1 {
+		next := level[:0]
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				// 奇数节点直接提升
+				next = append(next, level[i])
+				continue
+			}
+			next = append(next, innerHash(level[i], level[i+1]))
+		}
+		level = next
+	}
+	return level[0]
+}
+
+// CID 返回内容 ID
+func (m *Manifest) CID() string {
+	root := m.Root()
+	var header [13]byte
+	header[0] = cidPrefix
+	binary.BigEndian.PutUint64(header[1:9], uint64(m.Size))
+	binary.BigEndian.PutUint32(header[9:13], uint32(m.ChunkSize))
+
+	h := sha256.New()
+	h.Write(header[:])
+	h.Write(root[:])
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Marshal 编码清单
+func (m *Manifest) Marshal() []byte {
+	buf := make([]byte, manifestHeaderSize, manifestHeaderSize+len(m.Leaves)*sha256.Size)
+	binary.BigEndian.PutUint64(buf[0:8], uint64(m.Size))
+	binary.BigEndian.PutUint32(buf[8:12], uint32(m.ChunkSize))
+	binary.BigEndian.PutUint32(buf[12:16], uint32(len(m.Leaves)))
+	for i := range m.Leaves {
+		buf = append(buf, m.Leaves[i][:]...)
+	}
+	return buf
+}
+
+// UnmarshalManifest 解码清单并校验是否与 cid 相符
+func UnmarshalManifest(data []byte, cid string) (*Manifest, error) {
+	if len(data) < manifestHeaderSize {
+		return nil, ErrInvalidManifest
+	}
+	m := &Manifest{
+		Size:      int64(binary.BigEndian.Uint64(data[0:8])),
+		ChunkSize: int(binary.BigEndian.Uint32(data[8:12])),
+	}
+	count := int(binary.BigEndian.Uint32(data[12:16]))
+
+	if m.Size < 0 || m.ChunkSize < MinChunkSize || m.ChunkSize > MaxChunkSize ||
+		count < 1 || count > MaxChunks || len(data) != manifestHeaderSize+count*sha256.Size {
+		return nil, ErrInvalidManifest
+	}
+	if expected := (m.Size + int64(m.ChunkSize) - 1) / int64(m.ChunkSize); expected != int64(count) && !(m.Size == 0 && count == 1) {
+		return nil, ErrInvalidManifest
+	}
+
+	m.Leaves = make([][sha256.Size]byte, count)
+	for i := range m.Leaves {
+		off := manifestHeaderSize + i*sha256.Size
+		copy(m.Leaves[i][:], data[off:off+sha256.Size])
+	}
+
+	if m.CID() != cid {
+		return nil, fmt.Errorf("%w: content id mismatch", ErrInvalidManifest)
+	}
+	return m, nil
+}
+
+// parseCID 解析内容 ID
+func parseCID(cid string) ([sha256.Size]byte, error) {
+	var out [sha256.Size]byte
+	raw, err := hex.DecodeString(cid)
+	if err != nil || len(raw) != sha256.Size {
+		return out, ErrInvalidCID
+	}
+	copy(out[:], raw)
+	return out, nil
+}
+
+// providerKey 返回内容在 DHT 中的提供者键
+func providerKey(cid string) string {
+	return "transfer/" + cid
+}
+
+func leafHash(data []byte) [sha256.Size]byte {
+	h := sha256.New()
+	h.Write([]byte{leafPrefix})
+	h.Write(data)
+	var out [sha256.Size]byte
+	h.Sum(out[:0])
+	return out
+}
+
+func innerHash(left, right [sha256.Size]byte) [sha256.Size]byte {
+	h := sha256.New()
+	h.Write([]byte{innerPrefix})
+	h.Write(left[:])
+	h.Write(right[:])
+	var out [sha256.Size]byte
+	h.Sum(out[:0])
+	return out
+}
@@ -0,0 +1,65 @@
+package transfer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManifest_RoundTrip(t *testing.T) {
+	data := make([]byte, 5*MinChunkSize+123)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+
+	m, err := BuildManifest(bytes.NewReader(data), MinChunkSize)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(data)), m.Size)
+	assert.Equal(t, 6, m.NumChunks())
+	assert.Equal(t, 123, m.ChunkLen(5))
+
+	cid := m.CID()
+	decoded, err := UnmarshalManifest(m.Marshal(), cid)
+	require.NoError(t, err)
+	assert.Equal(t, m, decoded)
+
+	for i := 0; i < m.NumChunks(); i++ {
+		off := m.ChunkOffset(i)
+		assert.NoError(t, m.VerifyChunk(i, data[off:off+int64(m.ChunkLen(i))]))
+	}
+	assert.ErrorIs(t, m.VerifyChunk(0, data[1:MinChunkSize+1]), ErrChunkMismatch)
+	assert.ErrorIs(t, m.VerifyChunk(5, data[:123]), ErrChunkMismatch)
+}
+
+func TestManifest_CIDBindsLayout(t *testing.T) {
+	data := bytes.Repeat([]byte{1}, 4*MinChunkSize)
+
+	a, err := BuildManifest(bytes.NewReader(data), MinChunkSize)
+	require.NoError(t, err)
+	b, err := BuildManifest(bytes.NewReader(data), 2*MinChunkSize)
+	require.NoError(t, err)
+	assert.NotEqual(t, a.CID(), b.CID())
+
+	// 篡改任意叶子哈希或头部都会导致 CID 不符
+	encoded := a.Marshal()
+	encoded[len(encoded)-1] ^= 0xff
+	_, err = UnmarshalManifest(encoded, a.CID())
+	assert.ErrorIs(t, err, ErrInvalidManifest)
+
+	encoded = a.Marshal()
+	encoded[7]--
+	_, err = UnmarshalManifest(encoded, a.CID())
+	assert.ErrorIs(t, err, ErrInvalidManifest)
+}
+
+func TestManifest_Empty(t *testing.T) {
+	m, err := BuildManifest(bytes.NewReader(nil), MinChunkSize)
+	require.NoError(t, err)
+	assert.Equal(t, 1, m.NumChunks())
+	assert.NoError(t, m.VerifyChunk(0, nil))
+
+	_, err = UnmarshalManifest(m.Marshal(), m.CID())
+	assert.NoError(t, err)
+}
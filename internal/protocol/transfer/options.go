@@ -0,0 +1,97 @@
+// Package transfer 实现可校验、可续传的大文件传输服务
+package transfer
+
+import "time"
+
+const (
+	// MinChunkSize 最小分块大小
+	MinChunkSize = 16 << 10
+
+	// MaxChunkSize 最大分块大小
+	MaxChunkSize = 4 << 20
+
+	// MaxChunks 单个内容的最大分块数
+	MaxChunks = 1 << 20
+)
+
+// Config 传输服务配置
+type Config struct {
+	// ChunkSize 共享文件时的分块大小
+	ChunkSize int
+
+	// MaxPeers 单次下载同时使用的最大提供者数
+	MaxPeers int
+
+	// MaxUploadRate 上传限速（字节/秒，0 表示不限速）
+	MaxUploadRate int64
+
+	// MaxDownloadRate 下载限速（字节/秒，0 表示不限速）
+	MaxDownloadRate int64
+
+	// RequestTimeout 单个请求（清单或分块）的超时
+	RequestTimeout time.Duration
+
+	// FindProvidersTimeout DHT 查找提供者的超时
+	FindProvidersTimeout time.Duration
+
+	// ReprovideInterval 重新宣告共享内容的间隔
+	ReprovideInterval time.Duration
+}
+
+// DefaultConfig 返回默认配置
+func DefaultConfig() *Config {
+	return &Config{
+		ChunkSize:            256 << 10,
+		MaxPeers:             4,
+		RequestTimeout:       30 * time.Second,
+		FindProvidersTimeout: 10 * time.Second,
+		ReprovideInterval:    12 * time.Hour,
+	}
+}
+
+// Option 配置选项函数
+type Option func(*Config)
+
+// WithChunkSize 设置分块大小（限制在 MinChunkSize 与 MaxChunkSize 之间）
+func WithChunkSize(size int) Option {
+	return func(c *Config) {
+		if size < MinChunkSize {
+			size = MinChunkSize
+		}
+		if size > MaxChunkSize {
+			size = MaxChunkSize
+		}
+		c.ChunkSize = size
+	}
+}
+
+// WithMaxPeers 设置单次下载同时使用的最大提供者数
+func WithMaxPeers(n int) Option {
+	return func(c *Config) {
+		if n > 0 {
+			c.MaxPeers = n
+		}
+	}
+}
+
+// WithRateLimit 设置上传和下载限速（字节/秒，0 表示不限速）
+func WithRateLimit(upload, download int64) Option {
+	return func(c *Config) {
+		c.MaxUploadRate = upload
+		c.MaxDownloadRate = download
+	}
+}
+
+// WithRequestTimeout 设置单个请求的超时
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.RequestTimeout = timeout
+	}
+}
+
+// WithReprovideInterval 设置重新宣告共享内容的间隔
+func WithReprovideInterval(interval time.Duration) Option {
+	return func(c *Config) {
+		c.ReprovideInterval = interval
+	}
+}
@@ -0,0 +1,398 @@
+// Package transfer 实现可校验、可续传的大文件传输服务
+package transfer
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/log"
+	"github.com/dep2p/go-dep2p/pkg/protocol"
+	"github.com/dep2p/go-dep2p/pkg/types"
+)
+
+var logger = log.Logger("protocol/transfer")
+
+// ContentRouting 内容提供者路由（由 DHT 实现）
+type ContentRouting interface {
+	// Provide 宣告本节点提供 key 对应的内容
+	Provide(ctx context.Context, key string, broadcast bool) error
+
+	// FindProviders 查找 key 对应内容的提供者
+	FindProviders(ctx context.Context, key string) (<-chan types.PeerInfo, error)
+}
+
+// share 本地共享的内容
+type share struct {
+	path     string
+	manifest *Manifest
+	encoded  []byte
+}
+
+// Service 文件传输服务
+//
+// 同一个服务既响应其他成员的下载请求，也负责本节点的下载。
+type Service struct {
+	host       interfaces.Host
+	realm      interfaces.Realm
+	realmID    string
+	streams    interfaces.Streams
+	routing    ContentRouting
+	config     *Config
+	protocolID string
+
+	// 服务自身的字节计数（带宽计数器不可用时用于限速）
+	sentBytes atomic.Int64
+	recvBytes atomic.Int64
+
+	upload   *throttle
+	download *throttle
+
+	sharesMu sync.RWMutex
+	shares   map[string]*share
+
+	mu      sync.RWMutex
+	started bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// 确保 Service 实现了 interfaces.Transfer 接口
+var _ interfaces.Transfer = (*Service)(nil)
+
+// NewForRealm 创建绑定到 Realm 的传输服务
+//
+// streams 为该 Realm 的 Streams 服务；routing 可以为 nil，
+// 此时不宣告也不查找提供者，下载需要显式指定 Providers。
+func NewForRealm(host interfaces.Host, realm interfaces.Realm, streams interfaces.Streams, routing ContentRouting, opts ...Option) (*Service, error) {
+	if host == nil {
+		return nil, ErrNilHost
+	}
+	if realm == nil {
+		return nil, ErrNilRealm
+	}
+	if streams == nil {
+		return nil, ErrNilStreams
+	}
+
+	config := DefaultConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return &Service{
+		host:       host,
+		realm:      realm,
+		realmID:    realm.ID(),
+		streams:    streams,
+		routing:    routing,
+		config:     config,
+		protocolID: fmt.Sprintf("/dep2p/app/%s/%s/%s/1.0.0", realm.ID(), protocol.AppProtocolStreams, StreamProtocol),
+		shares:     make(map[string]*share),
+	}, nil
+}
+
+// Start 启动服务
+func (s *Service) Start(_ context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return ErrAlreadyStarted
+	}
+
+	if err := s.streams.RegisterHandler(StreamProtocol, s.handleStream); err != nil {
+		return fmt.Errorf("transfer: register handler: %w", err)
+	}
+
+	// 使用 context.Background()，Fx OnStart 的 ctx 在返回后会被取消
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.upload = newThrottle(s.config.MaxUploadRate, s.consumed(false))
+	s.download = newThrottle(s.config.MaxDownloadRate, s.consumed(true))
+
+	if s.routing != nil && s.config.ReprovideInterval > 0 {
+		s.wg.Add(1)
+		go s.reprovideLoop()
+	}
+
+	s.started = true
+	return nil
+}
+
+// Stop 停止服务
+func (s *Service) Stop(_ context.Context) error {
+	s.mu.Lock()
+	if !s.started {
+		s.mu.Unlock()
+		return ErrNotStarted
+	}
+	s.started = false
+	_ = s.streams.UnregisterHandler(StreamProtocol)
+	s.cancel()
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return nil
+}
+
+// serviceContext 返回服务上下文，未启动时返回错误
+func (s *Service) serviceContext() (context.Context, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if !s.started {
+		return nil, ErrNotStarted
+	}
+	return s.ctx, nil
+}
+
+// ============================================================================
+//                              共享
+// ============================================================================
+
+// Share 共享本地文件，返回内容 ID
+func (s *Service) Share(ctx context.Context, path string) (string, error) {
+	if _, err := s.serviceContext(); err != nil {
+		return "", err
+	}
+
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	manifest, err := BuildManifest(f, s.config.ChunkSize)
+	f.Close()
+	if err != nil {
+		return "", err
+	}
+
+	cid := manifest.CID()
+	s.addShare(cid, path, manifest)
+	s.announce(ctx, cid)
+
+	logger.Info("共享内容", "cid", cid[:16], "size", manifest.Size, "chunks", manifest.NumChunks())
+	return cid, nil
+}
+
+// Unshare 停止共享内容
+//
+// DHT 中已发布的提供者记录会在 TTL 到期后失效，期间的请求会收到未找到。
+func (s *Service) Unshare(cid string) error {
+	s.sharesMu.Lock()
+	defer s.sharesMu.Unlock()
+	if _, ok := s.shares[cid]; !ok {
+		return ErrNotFound
+	}
+	delete(s.shares, cid)
+	return nil
+}
+
+// Shared 返回本节点正在共享的内容 ID
+func (s *Service) Shared() []string {
+	s.sharesMu.RLock()
+	defer s.sharesMu.RUnlock()
+	cids := make([]string, 0, len(s.shares))
+	for cid := range s.shares {
+		cids = append(cids, cid)
+	}
+	sort.Strings(cids)
+	return cids
+}
+
+func (s *Service) addShare(cid, path string, manifest *Manifest) {
+	s.sharesMu.Lock()
+	defer s.sharesMu.Unlock()
+	s.shares[cid] = &share{path: path, manifest: manifest, encoded: manifest.Marshal()}
+}
+
+func (s *Service) getShare(cid string) *share {
+	s.sharesMu.RLock()
+	defer s.sharesMu.RUnlock()
+	return s.shares[cid]
+}
+
+// announce 通过 DHT 宣告本节点为内容提供者（失败只记录日志）
+func (s *Service) announce(ctx context.Context, cid string) {
+	if s.routing == nil {
+		return
+	}
+	if err := s.routing.Provide(ctx, providerKey(cid), true); err != nil {
+		logger.Warn("宣告内容提供者失败", "cid", cid[:16], "error", err)
+	}
+}
+
+// reprovideLoop 定期重新宣告共享内容，防止提供者记录过期
+func (s *Service) reprovideLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.config.ReprovideInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			for _, cid := range s.Shared() {
+				s.announce(s.ctx, cid)
+			}
+		}
+	}
+}
+
+// ============================================================================
+//                              上传（服务端）
+// ============================================================================
+
+// handleStream 处理下载方的请求
+//
+// 一个流上可以顺序处理多个请求，直到对端关闭。
+func (s *Service) handleStream(stream interfaces.BiStream) {
+	defer stream.Close()
+
+	remote := stream.RemotePeer()
+	if !s.realm.IsMember(remote) {
+		logger.Debug("拒绝非成员的传输请求", "from", log.TruncateID(remote, 8))
+		stream.Reset()
+		return
+	}
+
+	ctx, err := s.serviceContext()
+	if err != nil {
+		stream.Reset()
+		return
+	}
+
+	files := make(map[string]*os.File)
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+
+	for {
+		stream.SetDeadline(time.Now().Add(s.config.RequestTimeout))
+		req, err := readRequest(stream)
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				logger.Debug("读取传输请求失败", "from", log.TruncateID(remote, 8), "error", err)
+			}
+			return
+		}
+
+		cid := hex.EncodeToString(req.cid[:])
+		sh := s.getShare(cid)
+		if sh == nil {
+			if err := writeResponse(stream, statusNotFound, nil); err != nil {
+				return
+			}
+			continue
+		}
+
+		switch req.typ {
+		case msgGetManifest:
+			err = writeResponse(stream, statusOK, sh.encoded)
+
+		case msgGetChunk:
+			err = s.serveChunk(ctx, stream, sh, int(req.index), files)
+		}
+		if err != nil {
+			logger.Debug("响应传输请求失败", "from", log.TruncateID(remote, 8), "error", err)
+			return
+		}
+	}
+}
+
+// serveChunk 读取并发送一个分块
+//
+// 发送前重新校验分块，共享后文件被修改时拒绝发送。
+func (s *Service) serveChunk(ctx context.Context, stream interfaces.BiStream, sh *share, index int, files map[string]*os.File) error {
+	if index >= sh.manifest.NumChunks() {
+		return writeResponse(stream, statusInvalid, []byte("chunk index out of range"))
+	}
+
+	f := files[sh.path]
+	if f == nil {
+		var err error
+		if f, err = os.Open(sh.path); err != nil {
+			logger.Warn("打开共享文件失败", "path", sh.path, "error", err)
+			return writeResponse(stream, statusNotFound, nil)
+		}
+		files[sh.path] = f
+	}
+
+	data := make([]byte, sh.manifest.ChunkLen(index))
+	if _, err := f.ReadAt(data, sh.manifest.ChunkOffset(index)); err != nil && !errors.Is(err, io.EOF) {
+		return writeResponse(stream, statusInvalid, []byte("read failed"))
+	}
+	if err := sh.manifest.VerifyChunk(index, data); err != nil {
+		logger.Warn("共享文件已被修改", "path", sh.path, "chunk", index)
+		return writeResponse(stream, statusInvalid, []byte("content changed"))
+	}
+
+	if err := s.upload.wait(ctx); err != nil {
+		return err
+	}
+	stream.SetWriteDeadline(time.Now().Add(s.config.RequestTimeout))
+	if err := writeResponse(stream, statusOK, data); err != nil {
+		return err
+	}
+	s.sentBytes.Add(int64(len(data)))
+	return nil
+}
+
+// ============================================================================
+//                              带宽统计
+// ============================================================================
+
+// bandwidthProvider 提供带宽计数器的 Swarm
+type bandwidthProvider interface {
+	BandwidthCounter() interfaces.BandwidthCounter
+}
+
+// consumed 返回传输协议的累计字节数读取函数（in 为入站方向）
+//
+// 优先使用 Swarm 带宽计数器按协议统计的值（包含帧开销）；计数器
+// 未启用或未按协议统计时，取服务自身计数，两者取较大值。
+func (s *Service) consumed(in bool) func() int64 {
+	local := &s.sentBytes
+	if in {
+		local = &s.recvBytes
+	}
+
+	var counter interfaces.BandwidthCounter
+	if swarm := s.host.Network(); swarm != nil {
+		if provider, ok := swarm.(bandwidthProvider); ok {
+			counter = provider.BandwidthCounter()
+		}
+	}
+
+	return func() int64 {
+		n := local.Load()
+		if counter == nil {
+			return n
+		}
+		stats := counter.GetForProtocol(s.protocolID)
+		total := stats.TotalOut
+		if in {
+			total = stats.TotalIn
+		}
+		if total > n {
+			return total
+		}
+		return n
+	}
+}
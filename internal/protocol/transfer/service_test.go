@@ -0,0 +1,265 @@
+package transfer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dep2p/go-dep2p/internal/protocol/streams"
+	"github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/types"
+	"github.com/dep2p/go-dep2p/tests/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memRouting 内存中的内容路由
+type memRouting struct {
+	mu        sync.Mutex
+	providers map[string][]string
+}
+
+func newMemRouting() *memRouting {
+	return &memRouting{providers: make(map[string][]string)}
+}
+
+// forPeer 返回以 peerID 身份宣告的路由视图
+func (r *memRouting) forPeer(peerID string) ContentRouting {
+	return &peerRouting{r: r, peerID: peerID}
+}
+
+type peerRouting struct {
+	r      *memRouting
+	peerID string
+}
+
+func (p *peerRouting) Provide(_ context.Context, key string, _ bool) error {
+	p.r.mu.Lock()
+	defer p.r.mu.Unlock()
+	p.r.providers[key] = append(p.r.providers[key], p.peerID)
+	return nil
+}
+
+func (p *peerRouting) FindProviders(_ context.Context, key string) (<-chan types.PeerInfo, error) {
+	p.r.mu.Lock()
+	defer p.r.mu.Unlock()
+	ch := make(chan types.PeerInfo, len(p.r.providers[key]))
+	for _, id := range p.r.providers[key] {
+		ch <- types.PeerInfo{ID: types.PeerID(id)}
+	}
+	close(ch)
+	return ch, nil
+}
+
+type testNet struct {
+	pn      *mocks.PipeNet
+	realm   *mocks.MockRealm
+	routing *memRouting
+}
+
+func newTestNet(members ...string) *testNet {
+	realm := mocks.NewMockRealm("realm-1")
+	realm.MemberList = members
+	return &testNet{pn: mocks.NewPipeNet(), realm: realm, routing: newMemRouting()}
+}
+
+func (n *testNet) start(t *testing.T, id string, opts ...Option) *Service {
+	t.Helper()
+	host := n.pn.AddHost(id)
+
+	st, err := streams.NewForRealm(host, n.realm)
+	require.NoError(t, err)
+	require.NoError(t, st.Start(context.Background()))
+	t.Cleanup(func() { st.Stop(context.Background()) })
+
+	opts = append([]Option{WithChunkSize(MinChunkSize)}, opts...)
+	svc, err := NewForRealm(host, n.realm, st, n.routing.forPeer(id), opts...)
+	require.NoError(t, err)
+	require.NoError(t, svc.Start(context.Background()))
+	t.Cleanup(func() { svc.Stop(context.Background()) })
+	return svc
+}
+
+func writeRandomFile(t *testing.T, dir string, size int) (string, []byte) {
+	t.Helper()
+	data := make([]byte, size)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+	path := filepath.Join(dir, "source.bin")
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+	return path, data
+}
+
+func TestService_DownloadViaDHT(t *testing.T) {
+	tn := newTestNet("peer-a", "peer-b")
+	provider := tn.start(t, "peer-a")
+	downloader := tn.start(t, "peer-b")
+
+	src, data := writeRandomFile(t, t.TempDir(), 10*MinChunkSize+77)
+	cid, err := provider.Share(context.Background(), src)
+	require.NoError(t, err)
+	assert.Equal(t, []string{cid}, provider.Shared())
+
+	var last interfaces.TransferProgress
+	dest := filepath.Join(t.TempDir(), "out.bin")
+	err = downloader.Download(context.Background(), cid, dest, interfaces.DownloadOptions{
+		Progress: func(p interfaces.TransferProgress) { last = p },
+	})
+	require.NoError(t, err)
+
+	got, err := os.ReadFile(dest)
+	require.NoError(t, err)
+	assert.True(t, bytes.Equal(data, got))
+	assert.Equal(t, int64(len(data)), last.DoneBytes)
+	assert.Equal(t, 11, last.DoneChunks)
+
+	_, err = os.Stat(dest + partSuffix)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestService_MultiSourceFailover(t *testing.T) {
+	tn := newTestNet("peer-a", "peer-b", "peer-c")
+	good := tn.start(t, "peer-a")
+	bad := tn.start(t, "peer-b")
+	downloader := tn.start(t, "peer-c")
+
+	src, data := writeRandomFile(t, t.TempDir(), 32*MinChunkSize)
+	cid, err := good.Share(context.Background(), src)
+	require.NoError(t, err)
+
+	// 第二个提供者共享后文件被修改，只能提供损坏的分块
+	badSrc := filepath.Join(t.TempDir(), "copy.bin")
+	require.NoError(t, os.WriteFile(badSrc, data, 0o644))
+	badCID, err := bad.Share(context.Background(), badSrc)
+	require.NoError(t, err)
+	require.Equal(t, cid, badCID)
+	require.NoError(t, os.WriteFile(badSrc, make([]byte, len(data)), 0o644))
+
+	dest := filepath.Join(t.TempDir(), "out.bin")
+	err = downloader.Download(context.Background(), cid, dest, interfaces.DownloadOptions{})
+	require.NoError(t, err)
+
+	got, err := os.ReadFile(dest)
+	require.NoError(t, err)
+	assert.True(t, bytes.Equal(data, got))
+	assert.Equal(t, int64(len(data)), good.sentBytes.Load())
+	assert.Zero(t, bad.sentBytes.Load())
+}
+
+func TestService_MultiSourceThrottled(t *testing.T) {
+	tn := newTestNet("peer-a", "peer-b", "peer-c")
+
+	// 每个提供者每秒只上传一个分块，下载必须同时使用两个提供者
+	p1 := tn.start(t, "peer-a", WithRateLimit(MinChunkSize, 0))
+	p2 := tn.start(t, "peer-b", WithRateLimit(MinChunkSize, 0))
+	downloader := tn.start(t, "peer-c")
+
+	dir := t.TempDir()
+	src, data := writeRandomFile(t, dir, 4*MinChunkSize)
+	cid, err := p1.Share(context.Background(), src)
+	require.NoError(t, err)
+	_, err = p2.Share(context.Background(), src)
+	require.NoError(t, err)
+
+	var first interfaces.TransferProgress
+	var once sync.Once
+	dest := filepath.Join(dir, "out.bin")
+	err = downloader.Download(context.Background(), cid, dest, interfaces.DownloadOptions{
+		Progress: func(p interfaces.TransferProgress) { once.Do(func() { first = p }) },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, first.Peers)
+
+	got, err := os.ReadFile(dest)
+	require.NoError(t, err)
+	assert.True(t, bytes.Equal(data, got))
+	assert.Positive(t, p1.sentBytes.Load())
+	assert.Positive(t, p2.sentBytes.Load())
+	assert.Equal(t, int64(len(data)), p1.sentBytes.Load()+p2.sentBytes.Load())
+}
+
+func TestService_Resume(t *testing.T) {
+	tn := newTestNet("peer-a", "peer-b")
+	provider := tn.start(t, "peer-a")
+	downloader := tn.start(t, "peer-b")
+
+	const chunks = 8
+	src, data := writeRandomFile(t, t.TempDir(), chunks*MinChunkSize)
+	cid, err := provider.Share(context.Background(), src)
+	require.NoError(t, err)
+
+	// 模拟中断：前一半分块已写入，后一半是垃圾数据
+	dest := filepath.Join(t.TempDir(), "out.bin")
+	partial := append([]byte(nil), data[:chunks/2*MinChunkSize]...)
+	partial = append(partial, bytes.Repeat([]byte{0xee}, MinChunkSize)...)
+	require.NoError(t, os.WriteFile(dest+partSuffix, partial, 0o644))
+
+	var first *interfaces.TransferProgress
+	err = downloader.Download(context.Background(), cid, dest, interfaces.DownloadOptions{
+		Providers: []string{"peer-a"},
+		Progress: func(p interfaces.TransferProgress) {
+			if first == nil {
+				first = &p
+			}
+		},
+	})
+	require.NoError(t, err)
+
+	require.NotNil(t, first)
+	assert.Equal(t, chunks/2, first.DoneChunks)
+	assert.Equal(t, int64(chunks/2*MinChunkSize), downloader.recvBytes.Load())
+
+	got, err := os.ReadFile(dest)
+	require.NoError(t, err)
+	assert.True(t, bytes.Equal(data, got))
+}
+
+func TestService_DownloadErrors(t *testing.T) {
+	tn := newTestNet("peer-a", "peer-b")
+	tn.start(t, "peer-a")
+	downloader := tn.start(t, "peer-b")
+	dest := filepath.Join(t.TempDir(), "out.bin")
+
+	err := downloader.Download(context.Background(), "not-a-cid", dest, interfaces.DownloadOptions{})
+	assert.ErrorIs(t, err, ErrInvalidCID)
+
+	unknown := bytes.Repeat([]byte("ab"), 32)
+	err = downloader.Download(context.Background(), string(unknown), dest, interfaces.DownloadOptions{})
+	assert.ErrorIs(t, err, ErrNoProviders)
+
+	err = downloader.Download(context.Background(), string(unknown), dest, interfaces.DownloadOptions{
+		Providers: []string{"peer-a"},
+	})
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestThrottle_LimitsRate(t *testing.T) {
+	var mu sync.Mutex
+	var used int64
+	consumed := func() int64 {
+		mu.Lock()
+		defer mu.Unlock()
+		return used
+	}
+
+	const rate = 100 << 10
+	th := newThrottle(rate, consumed)
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		require.NoError(t, th.wait(context.Background()))
+		mu.Lock()
+		used += rate / 2
+		mu.Unlock()
+	}
+	require.NoError(t, th.wait(context.Background()))
+
+	// 初始 1 秒额度之外的 1 秒流量必须等待
+	assert.GreaterOrEqual(t, time.Since(start), 900*time.Millisecond)
+	assert.Nil(t, newThrottle(0, consumed))
+}
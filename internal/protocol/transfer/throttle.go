@@ -0,0 +1,70 @@
+// Package transfer 实现可校验、可续传的大文件传输服务
+package transfer
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// throttle 基于已消耗字节数的限速器
+//
+// 消耗量由 consumed 读取（通常来自带宽计数器的协议累计值），
+// 限速器按 rate 积累额度，额度为负时等待。额度上限为 1 秒的流量，
+// 避免长时间空闲后突发。
+type throttle struct {
+	rate     int64
+	consumed func() int64
+
+	mu        sync.Mutex
+	credit    float64
+	last      time.Time
+	lastBytes int64
+}
+
+// newThrottle 创建限速器，rate <= 0 时返回 nil（不限速）
+func newThrottle(rate int64, consumed func() int64) *throttle {
+	if rate <= 0 {
+		return nil
+	}
+	return &throttle{
+		rate:      rate,
+		consumed:  consumed,
+		credit:    float64(rate),
+		last:      time.Now(),
+		lastBytes: consumed(),
+	}
+}
+
+// wait 等待直到额度非负
+func (t *throttle) wait(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+
+	for {
+		t.mu.Lock()
+		now := time.Now()
+		used := t.consumed()
+		t.credit += now.Sub(t.last).Seconds()*float64(t.rate) - float64(used-t.lastBytes)
+		if t.credit > float64(t.rate) {
+			t.credit = float64(t.rate)
+		}
+		t.last, t.lastBytes = now, used
+		debt := -t.credit
+		t.mu.Unlock()
+
+		if debt <= 0 {
+			return nil
+		}
+
+		delay := time.Duration(debt / float64(t.rate) * float64(time.Second))
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
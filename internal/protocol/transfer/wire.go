@@ -0,0 +1,115 @@
+// Package transfer 实现可校验、可续传的大文件传输服务
+package transfer
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// StreamProtocol 传输服务使用的 Streams 协议名
+const StreamProtocol = "dep2p.transfer"
+
+// 请求类型
+const (
+	msgGetManifest byte = 1
+	msgGetChunk    byte = 2
+)
+
+// 响应状态
+const (
+	statusOK       byte = 0
+	statusNotFound byte = 1
+	statusInvalid  byte = 2
+)
+
+// request 传输请求
+//
+// 线路格式：type(1) + cid(32) [+ index(4)]
+type request struct {
+	typ   byte
+	cid   [sha256.Size]byte
+	index uint32
+}
+
+func writeRequest(w io.Writer, req request) error {
+	buf := make([]byte, 0, 1+sha256.Size+4)
+	buf = append(buf, req.typ)
+	buf = append(buf, req.cid[:]...)
+	if req.typ == msgGetChunk {
+		buf = binary.BigEndian.AppendUint32(buf, req.index)
+	}
+	_, err := w.Write(buf)
+	return err
+}
+
+func readRequest(r io.Reader) (request, error) {
+	var req request
+	var typ [1]byte
+	if _, err := io.ReadFull(r, typ[:]); err != nil {
+		return req, err
+	}
+	req.typ = typ[0]
+	if _, err := io.ReadFull(r, req.cid[:]); err != nil {
+		return req, err
+	}
+
+	switch req.typ {
+	case msgGetManifest:
+	case msgGetChunk:
+		var idx [4]byte
+		if _, err := io.ReadFull(r, idx[:]); err != nil {
+			return req, err
+		}
+		req.index = binary.BigEndian.Uint32(idx[:])
+	default:
+		return req, fmt.Errorf("transfer: unknown request type %d", req.typ)
+	}
+	return req, nil
+}
+
+// writeResponse 写入响应
+//
+// 线路格式：status(1) + len(4) + payload
+func writeResponse(w io.Writer, status byte, payload []byte) error {
+	var header [5]byte
+	header[0] = status
+	binary.BigEndian.PutUint32(header[1:], uint32(len(payload)))
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+	if len(payload) == 0 {
+		return nil
+	}
+	_, err := w.Write(payload)
+	return err
+}
+
+// readResponse 读取响应，负载超过 maxLen 时返回错误
+func readResponse(r io.Reader, maxLen int) ([]byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(header[1:])
+	if int64(n) > int64(maxLen) {
+		return nil, fmt.Errorf("transfer: response too large (%d bytes)", n)
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+
+	switch header[0] {
+	case statusOK:
+		return payload, nil
+	case statusNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrRemote, payload)
+	}
+}
+
+// maxManifestLen 清单响应的最大长度
+const maxManifestLen = manifestHeaderSize + MaxChunks*sha256.Size
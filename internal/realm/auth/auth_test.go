@@ -953,6 +953,21 @@ func TestDeriveAuthKey_Empty(t *testing.T) {
 	t.Log("✅ 空参数正确返回nil")
 }
 
+// TestDeriveDiscoveryKey 测试发现密钥与认证密钥相互独立
+func TestDeriveDiscoveryKey(t *testing.T) {
+	psk := []byte("test-psk-12345678")
+	realmID := DeriveRealmID(psk)
+
+	key := DeriveDiscoveryKey(psk, realmID)
+	assert.Len(t, key, keyLength)
+	assert.Equal(t, key, DeriveDiscoveryKey(psk, realmID))
+	assert.NotEqual(t, key, DeriveAuthKey(psk, realmID))
+	assert.NotEqual(t, key, DeriveDiscoveryKey([]byte("other-psk-1234567"), realmID))
+
+	assert.Nil(t, DeriveDiscoveryKey(nil, realmID))
+	assert.Nil(t, DeriveDiscoveryKey(psk, ""))
+}
+
 // TestAuthConfig_Validate 测试配置验证
 func TestAuthConfig_Validate(t *testing.T) {
 	tests := []struct {
@@ -27,6 +27,9 @@ const (
 	// 认证密钥派生 salt
 	authKeySalt = "dep2p-auth-key-v1"
 
+	// 局域网发现密钥派生 salt
+	discoveryKeySalt = "dep2p-discovery-key-v1"
+
 	// 密钥长度（32 字节 = 256 位）
 	keyLength = 32
 )
@@ -77,6 +80,25 @@ func DeriveAuthKey(psk []byte, realmID string) []byte {
 	return authKey
 }
 
+// DeriveDiscoveryKey 从 PSK 派生局域网发现密钥
+//
+// mDNS 用它计算 Realm 服务实例的带盐标签，只有持有 PSK 的节点能识别。
+// 与认证密钥使用不同的 salt，两者不可互相推导。
+func DeriveDiscoveryKey(psk []byte, realmID string) []byte {
+	if len(psk) == 0 || realmID == "" {
+		return nil
+	}
+
+	kdf := hkdf.New(sha256.New, psk, []byte(discoveryKeySalt), []byte(realmID))
+
+	key := make([]byte, keyLength)
+	if _, err := io.ReadFull(kdf, key); err != nil {
+		return nil
+	}
+
+	return key
+}
+
 // ============================================================================
 //                              PSK 认证器
 // ============================================================================
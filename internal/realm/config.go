@@ -3,6 +3,14 @@ package realm
 import (
 	"fmt"
 	"time"
+
+	"github.com/dep2p/go-dep2p/internal/protocol/mailbox"
+	"github.com/dep2p/go-dep2p/internal/protocol/messaging"
+	"github.com/dep2p/go-dep2p/internal/protocol/pubsub"
+	"github.com/dep2p/go-dep2p/internal/protocol/streams"
+	"github.com/dep2p/go-dep2p/internal/protocol/transfer"
+	"github.com/dep2p/go-dep2p/internal/realm/topology"
+	"github.com/dep2p/go-dep2p/pkg/lib/compress"
 )
 
 // ============================================================================
@@ -30,6 +38,21 @@ type ManagerConfig struct {
 	// 用于 Relay 地址簿回退查询
 	RelayPeers []string
 
+	// Mailbox 离线信箱配置（nil 表示使用默认配置，仅客户端）
+	Mailbox *MailboxConfig
+
+	// Compression 负载压缩配置（nil 表示不压缩）
+	Compression *CompressionConfig
+
+	// Transfer 文件传输配置（nil 表示使用默认配置）
+	Transfer *TransferConfig
+
+	// Outbox 持久化发件箱配置（nil 表示不启用）
+	Outbox *OutboxConfig
+
+	// Topology 拓扑协议配置（nil 或未启用时不创建拓扑服务）
+	Topology *TopologyConfig
+
 	// 子模块配置（简化实现：使用接口类型避免循环依赖）
 	// AuthConfig    interface{}
 	// MemberConfig  interface{}
@@ -99,7 +122,237 @@ func (c *ManagerConfig) Clone() *ManagerConfig {
 		copy(cloned.RelayPeers, c.RelayPeers)
 	}
 
+	// 克隆信箱配置
+	if c.Mailbox != nil {
+		mb := *c.Mailbox
+		mb.MailboxPeers = append([]string(nil), c.Mailbox.MailboxPeers...)
+		cloned.Mailbox = &mb
+	}
+
+	// 克隆压缩配置
+	if c.Compression != nil {
+		cloned.Compression = c.Compression.clone()
+	}
+
+	// 克隆传输配置
+	if c.Transfer != nil {
+		tr := *c.Transfer
+		cloned.Transfer = &tr
+	}
+
+	// 克隆发件箱配置
+	if c.Outbox != nil {
+		ob := *c.Outbox
+		cloned.Outbox = &ob
+	}
+
+	// 克隆拓扑配置
+	if c.Topology != nil {
+		tp := *c.Topology
+		cloned.Topology = &tp
+	}
+
 	// 简化实现：子模块配置已注释
 
 	return cloned
 }
+
+// CompressionConfig 负载压缩配置
+type CompressionConfig struct {
+	// Protocols 按 Messaging 协议启用的压缩算法
+	Protocols map[string][]compress.Algorithm
+
+	// Topics 按 PubSub 主题启用的压缩算法
+	Topics map[string][]compress.Algorithm
+
+	// MaxDecompressedSize 解压后大小上限（0 表示使用默认值）
+	MaxDecompressedSize int
+}
+
+// clone 深拷贝压缩配置
+func (c *CompressionConfig) clone() *CompressionConfig {
+	cloned := &CompressionConfig{MaxDecompressedSize: c.MaxDecompressedSize}
+	cloneMap := func(m map[string][]compress.Algorithm) map[string][]compress.Algorithm {
+		if m == nil {
+			return nil
+		}
+		out := make(map[string][]compress.Algorithm, len(m))
+		for k, v := range m {
+			out[k] = append([]compress.Algorithm(nil), v...)
+		}
+		return out
+	}
+	cloned.Protocols = cloneMap(c.Protocols)
+	cloned.Topics = cloneMap(c.Topics)
+	return cloned
+}
+
+// messagingOptions 转换为 messaging 服务选项
+func (c *CompressionConfig) messagingOptions() []messaging.Option {
+	var opts []messaging.Option
+	for proto, algs := range c.Protocols {
+		opts = append(opts, messaging.WithProtocolCompression(proto, algs...))
+	}
+	if c.MaxDecompressedSize > 0 {
+		opts = append(opts, messaging.WithMaxDecompressedSize(c.MaxDecompressedSize))
+	}
+	return opts
+}
+
+// pubsubOptions 转换为 pubsub 服务选项
+//
+// PubSub 的解压上限由其 MaxMessageSize 决定。
+func (c *CompressionConfig) pubsubOptions() []pubsub.Option {
+	var opts []pubsub.Option
+	for topic, algs := range c.Topics {
+		opts = append(opts, pubsub.WithTopicCompression(topic, algs...))
+	}
+	return opts
+}
+
+// streamsOptions 转换为 streams 服务选项
+func (c *CompressionConfig) streamsOptions() []streams.Option {
+	if c.MaxDecompressedSize > 0 {
+		return []streams.Option{streams.WithMaxDecompressedSize(c.MaxDecompressedSize)}
+	}
+	return nil
+}
+
+// MailboxConfig 离线信箱配置
+type MailboxConfig struct {
+	// EnableServer 是否为其他成员存储离线消息（需要存储引擎）
+	EnableServer bool
+
+	// DefaultTTL 默认消息 TTL
+	DefaultTTL time.Duration
+
+	// MaxTTL 最大消息 TTL
+	MaxTTL time.Duration
+
+	// MaxMessageSize 单条消息最大字节数
+	MaxMessageSize int
+
+	// MaxPerSender 每个发送方最多待投递消息数
+	MaxPerSender int
+
+	// MaxPerRecipient 每个接收方最多待投递消息数
+	MaxPerRecipient int
+
+	// MailboxPeers 本节点使用的信箱节点
+	MailboxPeers []string
+}
+
+// options 转换为 mailbox 服务选项（零值字段保留服务默认值）
+func (c *MailboxConfig) options() []mailbox.Option {
+	opts := []mailbox.Option{mailbox.WithServer(c.EnableServer)}
+	if c.DefaultTTL > 0 || c.MaxTTL > 0 {
+		def := mailbox.DefaultConfig()
+		defaultTTL, maxTTL := def.DefaultTTL, def.MaxTTL
+		if c.DefaultTTL > 0 {
+			defaultTTL = c.DefaultTTL
+		}
+		if c.MaxTTL > 0 {
+			maxTTL = c.MaxTTL
+		}
+		opts = append(opts, mailbox.WithTTL(defaultTTL, maxTTL))
+	}
+	if c.MaxMessageSize > 0 {
+		opts = append(opts, mailbox.WithMaxMessageSize(c.MaxMessageSize))
+	}
+	if c.MaxPerSender > 0 || c.MaxPerRecipient > 0 {
+		def := mailbox.DefaultConfig()
+		perSender, perRecipient := def.MaxPerSender, def.MaxPerRecipient
+		if c.MaxPerSender > 0 {
+			perSender = c.MaxPerSender
+		}
+		if c.MaxPerRecipient > 0 {
+			perRecipient = c.MaxPerRecipient
+		}
+		opts = append(opts, mailbox.WithQuotas(perSender, perRecipient))
+	}
+	if len(c.MailboxPeers) > 0 {
+		opts = append(opts, mailbox.WithMailboxPeers(c.MailboxPeers...))
+	}
+	return opts
+}
+
+// TransferConfig 文件传输配置
+type TransferConfig struct {
+	// ChunkSize 分块大小
+	ChunkSize int
+
+	// MaxPeers 单次下载同时使用的最大提供者数
+	MaxPeers int
+
+	// MaxUploadRate 上传限速（字节/秒，0 表示不限速）
+	MaxUploadRate int64
+
+	// MaxDownloadRate 下载限速（字节/秒，0 表示不限速）
+	MaxDownloadRate int64
+}
+
+// options 转换为 transfer 服务选项（零值字段保留服务默认值）
+func (c *TransferConfig) options() []transfer.Option {
+	opts := []transfer.Option{transfer.WithRateLimit(c.MaxUploadRate, c.MaxDownloadRate)}
+	if c.ChunkSize > 0 {
+		opts = append(opts, transfer.WithChunkSize(c.ChunkSize))
+	}
+	if c.MaxPeers > 0 {
+		opts = append(opts, transfer.WithMaxPeers(c.MaxPeers))
+	}
+	return opts
+}
+
+// OutboxConfig 持久化发件箱配置
+type OutboxConfig struct {
+	// Enable 是否启用发件箱（需要存储引擎）
+	Enable bool
+
+	// TTL 消息默认有效期
+	TTL time.Duration
+
+	// MaxPending 最多待投递消息数
+	MaxPending int
+}
+
+// options 转换为 messaging 服务选项（零值字段保留服务默认值）
+func (c *OutboxConfig) options() []messaging.Option {
+	opts := []messaging.Option{messaging.WithOutbox(c.Enable)}
+	if c.TTL > 0 {
+		opts = append(opts, messaging.WithOutboxTTL(c.TTL))
+	}
+	if c.MaxPending > 0 {
+		opts = append(opts, messaging.WithOutboxMaxPending(c.MaxPending))
+	}
+	return opts
+}
+
+// TopologyConfig 拓扑协议配置
+type TopologyConfig struct {
+	// Enable 是否启用拓扑协议
+	Enable bool
+
+	// Interval 摘要交换间隔
+	Interval time.Duration
+
+	// ReportTTL 摘要有效期
+	ReportTTL time.Duration
+
+	// HotRelayShare 热点中继判定占比
+	HotRelayShare float64
+}
+
+// options 转换为 topology 服务选项（零值字段保留服务默认值）
+func (c *TopologyConfig) options() []topology.Option {
+	var opts []topology.Option
+	if c.Interval > 0 {
+		opts = append(opts, topology.WithInterval(c.Interval))
+	}
+	if c.ReportTTL > 0 {
+		opts = append(opts, topology.WithReportTTL(c.ReportTTL))
+	}
+	if c.HotRelayShare > 0 {
+		opts = append(opts, topology.WithHotRelay(c.HotRelayShare, topology.DefaultConfig().HotRelayMinCircuits))
+	}
+	return opts
+}
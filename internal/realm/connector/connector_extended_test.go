@@ -312,10 +312,11 @@ func (m *mockHost) ShareableAddrs() []string                                 { r
 func (m *mockHost) HolePunchAddrs() []string                                 { return nil }
 func (m *mockHost) Peerstore() pkgif.Peerstore                               { return nil }
 func (m *mockHost) EventBus() pkgif.EventBus                                 { return nil }
-func (m *mockHost) SetStreamHandler(string, pkgif.StreamHandler)             {}
 func (m *mockHost) RemoveStreamHandler(string)                               {}
 func (m *mockHost) SetReachabilityCoordinator(pkgif.ReachabilityCoordinator) {}
 
+func (m *mockHost) SetStreamHandler(string, pkgif.StreamHandler, ...pkgif.StreamHandlerOption) {}
+
 func (m *mockHost) Network() pkgif.Swarm { return nil }
 
 func (m *mockHost) HandleInboundStream(stream pkgif.Stream) {
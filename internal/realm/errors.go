@@ -48,6 +48,9 @@ var (
 
 	// ErrRealmClosed Realm 已关闭
 	ErrRealmClosed = errors.New("realm closed")
+
+	// ErrTopologyDisabled 未启用拓扑协议
+	ErrTopologyDisabled = errors.New("realm topology disabled")
 )
 
 // ============================================================================
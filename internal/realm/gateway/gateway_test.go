@@ -661,8 +661,9 @@ func (m *mockHostForGateway) Connect(ctx context.Context, peerID string, addrs [
 	m.conns[peerID] = true
 	return nil
 }
-func (m *mockHostForGateway) SetStreamHandler(protocolID string, handler pkgif.StreamHandler) {}
-func (m *mockHostForGateway) RemoveStreamHandler(protocolID string)                           {}
+func (m *mockHostForGateway) SetStreamHandler(protocolID string, handler pkgif.StreamHandler, _ ...pkgif.StreamHandlerOption) {
+}
+func (m *mockHostForGateway) RemoveStreamHandler(protocolID string) {}
 func (m *mockHostForGateway) NewStream(ctx context.Context, peerID string, protocolIDs ...string) (pkgif.Stream, error) {
 	m.newStreamCalls = append(m.newStreamCalls, peerID)
 	if m.stream != nil {
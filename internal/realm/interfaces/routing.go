@@ -162,6 +162,12 @@ type RouteNode struct {
 	LastSeen    time.Time
 	Load        *NodeLoad
 	IsReachable bool
+
+	// Links 链路状态：该节点直连的成员及其 RTT
+	//
+	// 非 nil 时路径查找使用显式链路作为邻接关系；
+	// nil 时回退到 XOR 距离最近的可达节点。
+	Links map[string]time.Duration
 }
 
 // Route 路由信息
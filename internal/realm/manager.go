@@ -19,6 +19,7 @@ import (
 	"github.com/dep2p/go-dep2p/internal/realm/member"
 	"github.com/dep2p/go-dep2p/internal/realm/protocol"
 	"github.com/dep2p/go-dep2p/internal/realm/routing"
+	"github.com/dep2p/go-dep2p/internal/realm/topology"
 	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
 	"github.com/dep2p/go-dep2p/pkg/types"
 )
@@ -55,6 +56,12 @@ type Manager struct {
 	// Phase 8 修复：添加可选的 ConnectionHealthMonitor（用于 PubSub 错误上报）
 	healthMonitor pkgif.ConnectionHealthMonitor
 
+	// 数据预算（可选，传递给 PubSub 心跳）
+	dataBudget pkgif.DataBudget
+
+	// 节点信誉（可选，PubSub 评分作为信誉信号上报）
+	reputation pkgif.Reputation
+
 	// P0 修复：NAT 服务（用于 Capability 广播）
 	nat pkgif.NATService
 
@@ -127,6 +134,12 @@ type ManagerDeps struct {
 	// 可选依赖（Phase 8 修复：支持网络健康监控）
 	HealthMonitor pkgif.ConnectionHealthMonitor
 
+	// 可选数据预算（计费网络下放慢后台流量）
+	DataBudget pkgif.DataBudget
+
+	// 可选节点信誉服务（接收 PubSub 评分信号）
+	Reputation pkgif.Reputation
+
 	// P0 修复：可选 NAT 服务（用于 Capability 广播）
 	NATService pkgif.NATService
 
@@ -178,6 +191,8 @@ func NewManager(deps ManagerDeps) (*Manager, error) {
 		storageEngine: deps.StorageEngine,
 		holePuncher:   deps.HolePuncher,
 		healthMonitor: deps.HealthMonitor, // Phase 8 修复：设置可选的健康监控器
+		dataBudget:    deps.DataBudget,
+		reputation:    deps.Reputation,
 		nat:           deps.NATService,    // P0 修复：NAT 服务
 		realms:        make(map[string]*realmImpl),
 	}, nil
@@ -248,7 +263,10 @@ func (m *Manager) Join(ctx context.Context, realmID string, psk []byte) (interfa
 	// 4. 检查是否已存在
 	if realm, ok := m.realms[realmID]; ok {
 		logger.Debug("Realm 已存在，切换到该 Realm", "realmID", realmID)
-		m.current = realm
+		if m.current != realm {
+			m.current = realm
+			m.publishRealmJoined(realmID, realm.PSK())
+		}
 		return realm, nil
 	}
 
@@ -271,6 +289,7 @@ func (m *Manager) Join(ctx context.Context, realmID string, psk []byte) (interfa
 	// 7. 注册并设置为 current
 	m.realms[realmID] = realm
 	m.current = realm
+	m.publishRealmJoined(realmID, realm.PSK())
 
 	logger.Info("成功加入 Realm", "realmID", realmID)
 	return realm, nil
@@ -591,6 +610,8 @@ func (m *Manager) leaveCurrentRealm(ctx context.Context) error {
 		return ErrNotInRealm
 	}
 
+	m.publishRealmLeft(m.current.id)
+
 	// 停止并清理 Realm
 	if err := m.current.stop(ctx); err != nil {
 		// 即使失败也要清理
@@ -604,6 +625,53 @@ func (m *Manager) leaveCurrentRealm(ctx context.Context) error {
 	return nil
 }
 
+// publishRealmJoined 发布加入 Realm 事件（需持有锁）
+func (m *Manager) publishRealmJoined(realmID string, psk []byte) {
+	if m.eventBus == nil {
+		return
+	}
+
+	emitter, err := m.eventBus.Emitter(&types.EvtRealmJoined{})
+	if err != nil {
+		return
+	}
+	defer emitter.Close()
+
+	emitter.Emit(&types.EvtRealmJoined{
+		BaseEvent: types.NewBaseEvent(types.EventTypeRealmJoined),
+		RealmID:      types.RealmID(realmID),
+		PeerID:       m.localPeerID(),
+		DiscoveryKey: auth.DeriveDiscoveryKey(psk, realmID),
+	})
+}
+
+// publishRealmLeft 发布离开 Realm 事件（需持有锁）
+func (m *Manager) publishRealmLeft(realmID string) {
+	if m.eventBus == nil {
+		return
+	}
+
+	emitter, err := m.eventBus.Emitter(&types.EvtRealmLeft{})
+	if err != nil {
+		return
+	}
+	defer emitter.Close()
+
+	emitter.Emit(&types.EvtRealmLeft{
+		BaseEvent: types.NewBaseEvent(types.EventTypeRealmLeft),
+		RealmID:   types.RealmID(realmID),
+		PeerID:    m.localPeerID(),
+	})
+}
+
+// localPeerID 返回本地节点 ID
+func (m *Manager) localPeerID() types.PeerID {
+	if m.host == nil {
+		return ""
+	}
+	return types.PeerID(m.host.ID())
+}
+
 // ============================================================================
 //                              查询 Realm
 // ============================================================================
@@ -622,6 +690,24 @@ func (m *Manager) Current() pkgif.Realm {
 	return m.current
 }
 
+// TopologyGraph 组装当前 Realm 的拓扑图
+//
+// 未加入 Realm 时返回 ErrNotInRealm，未启用拓扑协议时返回 ErrTopologyDisabled。
+func (m *Manager) TopologyGraph() (*topology.Graph, error) {
+	m.mu.RLock()
+	current := m.current
+	m.mu.RUnlock()
+
+	if current == nil {
+		return nil, ErrNotInRealm
+	}
+	svc := current.Topology()
+	if svc == nil {
+		return nil, ErrTopologyDisabled
+	}
+	return svc.Graph(), nil
+}
+
 // Get 获取指定 Realm
 func (m *Manager) Get(realmID string) (interfaces.Realm, bool) {
 	m.mu.RLock()
@@ -750,6 +836,20 @@ func (m *Manager) ListRealms() []pkgif.Realm {
 	return realms
 }
 
+// FillCapabilities 填充已加入 Realm 的哈希
+//
+// 以本节点 PeerID 加盐，对端只能验证自己已知的 Realm，
+// 用于 Gossip 候选选择等场景的快速预筛。
+func (m *Manager) FillCapabilities(caps *types.PeerCapabilities) {
+	if m.host == nil {
+		return
+	}
+	local := types.PeerID(m.host.ID())
+	for _, r := range m.ListRealms() {
+		caps.AddRealm(local, types.RealmID(r.ID()))
+	}
+}
+
 // Close 关闭 Manager
 func (m *Manager) Close() error {
 	if m.closed.Load() {
@@ -816,7 +916,7 @@ func (m *Manager) defaultMemberFactory(realmID string) (interfaces.MemberManager
 func (m *Manager) defaultRoutingFactory(realmID string) (interfaces.Router, error) {
 	// 创建真实的 Router 实例
 	// DHT 参数可选，传 nil 时路由器仍可工作（仅使用本地路由表）
-	router := routing.NewRouter(realmID, nil, nil)
+	router := routing.NewRouter(realmID, nil, m.routingConfig())
 	return router, nil
 }
 
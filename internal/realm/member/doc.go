@@ -63,6 +63,7 @@
 // 特性：
 //   - 定期心跳发送（15 秒）
 //   - 超时检测（3 次失败）
+//   - 计费网络下按数据预算放慢心跳（SetDataBudget）
 //   - 自动重连
 //   - 健康检查
 //
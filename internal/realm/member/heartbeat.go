@@ -47,6 +47,9 @@ type HeartbeatMonitor struct {
 	lastHeartbeat map[string]time.Time
 	failedCount   map[string]int
 
+	// dataBudget 数据预算（可选，计费网络下放慢心跳）
+	dataBudget pkgif.DataBudget
+
 	// 控制
 	ctx     context.Context
 	cancel  context.CancelFunc
@@ -80,6 +83,40 @@ func NewHeartbeatMonitor(
 	}
 }
 
+// SetDataBudget 设置数据预算（可选）
+//
+// 计费网络下按预算跳过部分心跳轮次，在线判断的超时按节流倍数放大。
+func (m *HeartbeatMonitor) SetDataBudget(budget pkgif.DataBudget) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dataBudget = budget
+}
+
+// budget 返回数据预算
+func (m *HeartbeatMonitor) budget() pkgif.DataBudget {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.dataBudget
+}
+
+// allowHeartbeat 检查数据预算是否允许本轮心跳
+func (m *HeartbeatMonitor) allowHeartbeat() bool {
+	budget := m.budget()
+	return budget == nil || budget.Allow(pkgif.BudgetSubsystemRealm, "heartbeat", m.interval)
+}
+
+// statusTimeout 返回在线判断的超时
+//
+// 节流时心跳间隔被放大，超时按同样的倍数放大，避免把在线成员判为离线。
+func (m *HeartbeatMonitor) statusTimeout() time.Duration {
+	if budget := m.budget(); budget != nil {
+		if scale := budget.Status().Scale; scale > 1 {
+			return m.timeout * time.Duration(scale)
+		}
+	}
+	return m.timeout
+}
+
 // Start 启动心跳监控
 func (m *HeartbeatMonitor) Start(_ context.Context) error {
 	if m.started.Load() {
@@ -288,7 +325,7 @@ func (m *HeartbeatMonitor) GetStatus(peerID string) (bool, error) {
 	}
 
 	// 检查是否超时
-	if time.Since(lastTime) > m.timeout {
+	if time.Since(lastTime) > m.statusTimeout() {
 		return false, nil
 	}
 
@@ -300,7 +337,7 @@ func (m *HeartbeatMonitor) heartbeatLoop() {
 	for {
 		select {
 		case <-m.ticker.C:
-			m.checkHeartbeats()
+			m.tick()
 
 		case <-m.ctx.Done():
 			return
@@ -308,6 +345,13 @@ func (m *HeartbeatMonitor) heartbeatLoop() {
 	}
 }
 
+// tick 执行一轮心跳（计费网络下按数据预算节流）
+func (m *HeartbeatMonitor) tick() {
+	if m.allowHeartbeat() {
+		m.checkHeartbeats()
+	}
+}
+
 // checkHeartbeats 检查所有成员的心跳状态
 func (m *HeartbeatMonitor) checkHeartbeats() {
 	if m.manager == nil {
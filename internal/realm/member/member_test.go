@@ -3,6 +3,7 @@ package member
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -468,22 +469,87 @@ func TestHeartbeat_StartWithHost(t *testing.T) {
 	require.NoError(t, err)
 }
 
+// TestHeartbeat_DataBudget 测试心跳按数据预算节流
+func TestHeartbeat_DataBudget(t *testing.T) {
+	ctx := context.Background()
+	manager := NewManager("realm-test", nil, nil, nil)
+	manager.Start(ctx)
+	manager.Add(ctx, &interfaces.MemberInfo{
+		PeerID:   "peer1",
+		RealmID:  "realm-test",
+		Online:   true,
+		LastSeen: time.Now(),
+	})
+
+	host := &localMockHost{}
+	budget := &mockDataBudget{scale: 4}
+	monitor := NewHeartbeatMonitor(manager, host, 100*time.Millisecond, 3)
+	monitor.SetDataBudget(budget)
+
+	// 记录首次检查时间，使下一轮需要发送心跳
+	monitor.mu.Lock()
+	monitor.lastHeartbeat["peer1"] = time.Now().Add(-time.Second)
+	monitor.mu.Unlock()
+
+	// 预算不允许时跳过本轮
+	monitor.tick()
+	assert.Equal(t, int32(0), host.newStreamCalls.Load(), "节流时不应发送心跳")
+	require.Len(t, budget.calls, 1)
+	assert.Equal(t, pkgif.BudgetSubsystemRealm+"/heartbeat", budget.calls[0])
+
+	budget.allow = true
+	monitor.tick()
+	assert.Equal(t, int32(1), host.newStreamCalls.Load(), "预算允许时应发送心跳")
+
+	// 节流时在线判断的超时按倍数放大（超时 300ms，放大后 1.2s）
+	monitor.mu.Lock()
+	monitor.lastHeartbeat["peer1"] = time.Now().Add(-time.Second)
+	monitor.mu.Unlock()
+	online, err := monitor.GetStatus("peer1")
+	require.NoError(t, err)
+	assert.True(t, online)
+
+	budget.scale = 1
+	online, err = monitor.GetStatus("peer1")
+	require.NoError(t, err)
+	assert.False(t, online)
+}
+
+// mockDataBudget 记录 Allow 调用的数据预算 mock
+type mockDataBudget struct {
+	allow bool
+	scale int
+	calls []string
+}
+
+func (b *mockDataBudget) Allow(subsystem string, task string, _ time.Duration) bool {
+	b.calls = append(b.calls, subsystem+"/"+task)
+	return b.allow
+}
+func (b *mockDataBudget) SetMode(pkgif.DataBudgetMode) {}
+func (b *mockDataBudget) SetDailyBudget(int64)         {}
+func (b *mockDataBudget) Status() pkgif.DataBudgetStatus {
+	return pkgif.DataBudgetStatus{Scale: b.scale}
+}
+
 // localMockHost 用于心跳测试的本地 mock
 type localMockHost struct {
 	setStreamHandlerFunc func(protocolID string, handler pkgif.StreamHandler)
+	newStreamCalls       atomic.Int32
 }
 
 func (h *localMockHost) ID() string                                                       { return "test-host" }
 func (h *localMockHost) Addrs() []string                                                  { return nil }
 func (h *localMockHost) Listen(addrs ...string) error                                     { return nil }
 func (h *localMockHost) Connect(ctx context.Context, peerID string, addrs []string) error { return nil }
-func (h *localMockHost) SetStreamHandler(protocolID string, handler pkgif.StreamHandler) {
+func (h *localMockHost) SetStreamHandler(protocolID string, handler pkgif.StreamHandler, _ ...pkgif.StreamHandlerOption) {
 	if h.setStreamHandlerFunc != nil {
 		h.setStreamHandlerFunc(protocolID, handler)
 	}
 }
 func (h *localMockHost) RemoveStreamHandler(protocolID string) {}
 func (h *localMockHost) NewStream(ctx context.Context, peerID string, protocolIDs ...string) (pkgif.Stream, error) {
+	h.newStreamCalls.Add(1)
 	return nil, fmt.Errorf("not implemented")
 }
 func (h *localMockHost) NewStreamWithPriority(ctx context.Context, peerID string, protocolID string, priority int) (pkgif.Stream, error) {
@@ -1999,7 +2065,7 @@ func (m *mockHostForDisconnectTest) Listen(addrs ...string) error { return nil }
 func (m *mockHostForDisconnectTest) Connect(ctx context.Context, peerID string, addrs []string) error {
 	return nil
 }
-func (m *mockHostForDisconnectTest) SetStreamHandler(protocolID string, handler pkgif.StreamHandler) {
+func (m *mockHostForDisconnectTest) SetStreamHandler(protocolID string, handler pkgif.StreamHandler, _ ...pkgif.StreamHandlerOption) {
 }
 func (m *mockHostForDisconnectTest) RemoveStreamHandler(protocolID string) {}
 func (m *mockHostForDisconnectTest) NewStream(ctx context.Context, peerID string, protocolIDs ...string) (pkgif.Stream, error) {
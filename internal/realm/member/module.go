@@ -117,9 +117,10 @@ type SynchronizerResult struct {
 type HeartbeatMonitorParams struct {
 	fx.In
 
-	Manager    *Manager       `optional:"true"`
-	Host       pkgif.Host     `optional:"true"`
-	UnifiedCfg *config.Config `optional:"true"`
+	Manager    *Manager         `optional:"true"`
+	Host       pkgif.Host       `optional:"true"`
+	UnifiedCfg *config.Config   `optional:"true"`
+	DataBudget pkgif.DataBudget `optional:"true"` // 数据预算，计费网络下放慢心跳
 }
 
 // HeartbeatMonitorResult HeartbeatMonitor 导出结果
@@ -185,6 +186,9 @@ func NewHeartbeatMonitorFromParams(p HeartbeatMonitorParams) (HeartbeatMonitorRe
 		cfg.HeartbeatInterval,
 		cfg.HeartbeatRetries,
 	)
+	if p.DataBudget != nil {
+		monitor.SetDataBudget(p.DataBudget)
+	}
 
 	return HeartbeatMonitorResult{
 		Monitor: monitor,
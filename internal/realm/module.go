@@ -12,6 +12,7 @@ import (
 	"github.com/dep2p/go-dep2p/internal/core/storage/engine"
 	"github.com/dep2p/go-dep2p/internal/realm/interfaces"
 	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/compress"
 	"github.com/dep2p/go-dep2p/pkg/lib/multiaddr"
 )
 
@@ -29,9 +30,25 @@ func ManagerModule() fx.Option {
 	return fx.Module("realm_manager",
 		fx.Provide(provideManager),
 		fx.Invoke(registerLifecycle),
+		fx.Invoke(registerCapabilities),
 	)
 }
 
+// capabilityInput 能力注册参数
+type capabilityInput struct {
+	fx.In
+
+	Manager      *Manager
+	Capabilities pkgif.CapabilityRegistry `optional:"true"`
+}
+
+// registerCapabilities 通过 Identify 通告已加入 Realm 的哈希
+func registerCapabilities(input capabilityInput) {
+	if input.Capabilities != nil {
+		input.Capabilities.AddCapabilityProvider(input.Manager.FillCapabilities)
+	}
+}
+
 // ManagerResult Manager 提供结果
 type ManagerResult struct {
 	fx.Out
@@ -74,6 +91,8 @@ type ManagerParams struct {
 	HolePuncher          *holepunch.HolePuncher          `optional:"true"` // NAT 打洞器（可选，无 NAT 时降级）
 	UnifiedCfg           *config.Config                  `optional:"true"` // 统一配置
 	HealthMonitor        pkgif.ConnectionHealthMonitor   `optional:"true"` // Phase 8 修复：网络健康监控器（用于 PubSub 错误上报）
+	DataBudget           pkgif.DataBudget                `optional:"true"` // 数据预算（计费网络下放慢 PubSub 心跳维护）
+	Reputation           pkgif.Reputation                `optional:"true"` // 节点信誉（接收 PubSub 评分信号）
 	LifecycleCoordinator *lifecycle.Coordinator          `optional:"true"` // 生命周期协调器
 
 	// 子模块工厂（可选，有默认实现）
@@ -108,9 +127,66 @@ func ConfigFromUnified(cfg *config.Config) *ManagerConfig {
 	mgrCfg.InfrastructurePeers = extractInfrastructurePeers(cfg)
 	mgrCfg.RelayPeers = extractRelayPeers(cfg)
 
+	mb := cfg.Messaging.Mailbox
+	mgrCfg.Mailbox = &MailboxConfig{
+		EnableServer:    mb.EnableServer,
+		DefaultTTL:      mb.DefaultTTL,
+		MaxTTL:          mb.MaxTTL,
+		MaxMessageSize:  mb.MaxMessageSize,
+		MaxPerSender:    mb.MaxPerSender,
+		MaxPerRecipient: mb.MaxPerRecipient,
+		MailboxPeers:    append([]string(nil), mb.MailboxPeers...),
+	}
+
+	mgrCfg.Compression = compressionConfig(cfg.Messaging.Compression)
+
+	tr := cfg.Messaging.Transfer
+	mgrCfg.Transfer = &TransferConfig{
+		ChunkSize:       tr.ChunkSize,
+		MaxPeers:        tr.MaxPeers,
+		MaxUploadRate:   tr.MaxUploadRate,
+		MaxDownloadRate: tr.MaxDownloadRate,
+	}
+
+	ob := cfg.Messaging.Outbox
+	mgrCfg.Outbox = &OutboxConfig{
+		Enable:     ob.Enable,
+		TTL:        ob.TTL,
+		MaxPending: ob.MaxPending,
+	}
+
+	tp := cfg.Realm.Topology
+	mgrCfg.Topology = &TopologyConfig{
+		Enable:        tp.Enable,
+		Interval:      tp.Interval,
+		ReportTTL:     tp.ReportTTL,
+		HotRelayShare: tp.HotRelayShare,
+	}
+
 	return mgrCfg
 }
 
+// compressionConfig 转换压缩配置（算法名称已由 config.Validate 校验，无效名称被忽略）
+func compressionConfig(cc config.CompressionConfig) *CompressionConfig {
+	parse := func(m map[string][]string) map[string][]compress.Algorithm {
+		if len(m) == 0 {
+			return nil
+		}
+		out := make(map[string][]compress.Algorithm, len(m))
+		for name, names := range m {
+			if algs, err := compress.ParseAlgorithms(names); err == nil && len(algs) > 0 {
+				out[name] = algs
+			}
+		}
+		return out
+	}
+	return &CompressionConfig{
+		Protocols:           parse(cc.Protocols),
+		Topics:              parse(cc.Topics),
+		MaxDecompressedSize: cc.MaxDecompressedSize,
+	}
+}
+
 // extractInfrastructurePeers 从配置中提取基础设施节点 PeerID
 //
 // 基础设施节点包括：
@@ -250,6 +326,8 @@ func NewManagerFromParams(p ManagerParams) (*Manager, error) {
 		StorageEngine: p.StorageEngine,
 		HolePuncher:   p.HolePuncher,
 		HealthMonitor: p.HealthMonitor, // Phase 8 修复：传递可选的健康监控器
+		DataBudget:    p.DataBudget,
+		Reputation:    p.Reputation,
 		Config:        mgrConfig,
 	})
 	if err != nil {
@@ -6,9 +6,13 @@ import (
 	"fmt"
 
 	"github.com/dep2p/go-dep2p/internal/protocol/liveness"
+	"github.com/dep2p/go-dep2p/internal/protocol/mailbox"
 	"github.com/dep2p/go-dep2p/internal/protocol/messaging"
 	"github.com/dep2p/go-dep2p/internal/protocol/pubsub"
 	"github.com/dep2p/go-dep2p/internal/protocol/streams"
+	"github.com/dep2p/go-dep2p/internal/protocol/transfer"
+	"github.com/dep2p/go-dep2p/internal/realm/routing"
+	"github.com/dep2p/go-dep2p/internal/realm/topology"
 	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
 )
 
@@ -52,6 +56,38 @@ func (m *Manager) createProtocolServices(_ context.Context, realm *realmImpl) er
 	}
 	realm.liveness = livenessSvc
 
+	// 5. 创建 Mailbox 服务
+	mailboxSvc, err := m.createMailboxService(realm)
+	if err != nil {
+		return fmt.Errorf("failed to create mailbox service: %w", err)
+	}
+	realm.mailbox = mailboxSvc
+
+	// 6. 创建 Transfer 服务（基于 Streams）
+	transferSvc, err := m.createTransferService(realm)
+	if err != nil {
+		return fmt.Errorf("failed to create transfer service: %w", err)
+	}
+	realm.transfer = transferSvc
+
+	// 7. 创建成员转发器（多跳路由）
+	if realm.routing != nil {
+		forwarder, err := routing.NewForwarder(m.host, realm, realm.routing, m.routingConfig())
+		if err != nil {
+			return fmt.Errorf("failed to create route forwarder: %w", err)
+		}
+		realm.forwarder = forwarder
+	}
+
+	// 8. 创建拓扑服务（可选）
+	if m.config != nil && m.config.Topology != nil && m.config.Topology.Enable {
+		topo, err := topology.NewService(m.host, realm, m.config.Topology.options()...)
+		if err != nil {
+			return fmt.Errorf("failed to create topology service: %w", err)
+		}
+		realm.topology = topo
+	}
+
 	//
 	// 用于检测 QUIC 直连的健康状态，加速离线检测
 	if swarm := m.host.Network(); swarm != nil {
@@ -67,13 +103,39 @@ func (m *Manager) createProtocolServices(_ context.Context, realm *realmImpl) er
 // createMessagingService 创建绑定到 Realm 的 Messaging 服务
 func (m *Manager) createMessagingService(realm *realmImpl) (pkgif.Messaging, error) {
 	// 使用 NewForRealm 创建绑定到特定 Realm 的服务
-	return messaging.NewForRealm(m.host, realm)
+	var opts []messaging.Option
+	if m.config != nil && m.config.Compression != nil {
+		opts = m.config.Compression.messagingOptions()
+	}
+	if m.config != nil && m.config.Outbox != nil {
+		opts = append(opts, m.config.Outbox.options()...)
+	}
+	svc, err := messaging.NewForRealm(m.host, realm, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// 持久化发件箱使用 Manager 的存储引擎
+	if m.storageEngine != nil {
+		svc.SetStorageEngine(m.storageEngine)
+	}
+	return svc, nil
 }
 
 // createPubSubService 创建绑定到 Realm 的 PubSub 服务
 func (m *Manager) createPubSubService(realm *realmImpl) (pkgif.PubSub, error) {
 	// 使用 NewForRealm 创建绑定到特定 Realm 的服务
-	svc, err := pubsub.NewForRealm(m.host, realm)
+	var opts []pubsub.Option
+	if m.config != nil && m.config.Compression != nil {
+		opts = m.config.Compression.pubsubOptions()
+	}
+	if m.dataBudget != nil {
+		opts = append(opts, pubsub.WithDataBudget(m.dataBudget))
+	}
+	if m.reputation != nil {
+		opts = append(opts, pubsub.WithReputation(m.reputation))
+	}
+	svc, err := pubsub.NewForRealm(m.host, realm, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -89,7 +151,11 @@ func (m *Manager) createPubSubService(realm *realmImpl) (pkgif.PubSub, error) {
 // createStreamsService 创建绑定到 Realm 的 Streams 服务
 func (m *Manager) createStreamsService(realm *realmImpl) (pkgif.Streams, error) {
 	// 使用 NewForRealm 创建绑定到特定 Realm 的服务
-	return streams.NewForRealm(m.host, realm)
+	var opts []streams.Option
+	if m.config != nil && m.config.Compression != nil {
+		opts = m.config.Compression.streamsOptions()
+	}
+	return streams.NewForRealm(m.host, realm, opts...)
 }
 
 // createLivenessService 创建绑定到 Realm 的 Liveness 服务
@@ -98,6 +164,45 @@ func (m *Manager) createLivenessService(realm *realmImpl) (pkgif.Liveness, error
 	return liveness.NewForRealm(m.host, realm)
 }
 
+// createMailboxService 创建绑定到 Realm 的离线信箱服务
+//
+// 服务端模式需要存储引擎；未配置存储引擎时降级为仅客户端。
+func (m *Manager) createMailboxService(realm *realmImpl) (*mailbox.Service, error) {
+	var opts []mailbox.Option
+	if m.config != nil && m.config.Mailbox != nil {
+		opts = append(opts, m.config.Mailbox.options()...)
+		if m.config.Mailbox.EnableServer && m.storageEngine == nil {
+			logger.Warn("未配置存储引擎，信箱服务端已禁用", "realmID", realm.id)
+			opts = append(opts, mailbox.WithServer(false))
+		}
+	}
+	return mailbox.NewForRealm(m.host, realm, m.storageEngine, opts...)
+}
+
+// createTransferService 创建绑定到 Realm 的文件传输服务
+//
+// DHT 不可用时仍可下载，但需要显式指定提供者。
+func (m *Manager) createTransferService(realm *realmImpl) (*transfer.Service, error) {
+	var opts []transfer.Option
+	if m.config != nil && m.config.Transfer != nil {
+		opts = m.config.Transfer.options()
+	}
+	var contentRouting transfer.ContentRouting
+	if m.dht != nil {
+		contentRouting = m.dht
+	}
+	return transfer.NewForRealm(m.host, realm, realm.streams, contentRouting, opts...)
+}
+
+// routingConfig 返回 Realm 路由配置（以本地节点为路径起点）
+func (m *Manager) routingConfig() *routing.Config {
+	cfg := routing.DefaultConfig()
+	if m.host != nil {
+		cfg.LocalPeerID = m.host.ID()
+	}
+	return cfg
+}
+
 // ============================================================================
 //                              Protocol 服务生命周期
 // ============================================================================
@@ -140,6 +245,38 @@ func startProtocolServices(ctx context.Context, realm *realmImpl) error {
 		}
 	}
 
+	// 启动 Mailbox
+	if realm.mailbox != nil {
+		if starter, ok := realm.mailbox.(interface{ Start(context.Context) error }); ok {
+			if err := starter.Start(ctx); err != nil {
+				return fmt.Errorf("failed to start mailbox: %w", err)
+			}
+		}
+	}
+
+	// 启动 Transfer（依赖 Streams）
+	if realm.transfer != nil {
+		if starter, ok := realm.transfer.(interface{ Start(context.Context) error }); ok {
+			if err := starter.Start(ctx); err != nil {
+				return fmt.Errorf("failed to start transfer: %w", err)
+			}
+		}
+	}
+
+	// 启动成员转发器
+	if realm.forwarder != nil {
+		if err := realm.forwarder.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start route forwarder: %w", err)
+		}
+	}
+
+	// 启动拓扑服务
+	if realm.topology != nil {
+		if err := realm.topology.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start topology: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -147,6 +284,38 @@ func startProtocolServices(ctx context.Context, realm *realmImpl) error {
 func stopProtocolServices(ctx context.Context, realm *realmImpl) error {
 	var lastErr error
 
+	// 停止拓扑服务
+	if realm.topology != nil {
+		if err := realm.topology.Stop(ctx); err != nil {
+			lastErr = err
+		}
+	}
+
+	// 停止成员转发器
+	if realm.forwarder != nil {
+		if err := realm.forwarder.Stop(ctx); err != nil {
+			lastErr = err
+		}
+	}
+
+	// 停止 Transfer
+	if realm.transfer != nil {
+		if stopper, ok := realm.transfer.(interface{ Stop(context.Context) error }); ok {
+			if err := stopper.Stop(ctx); err != nil {
+				lastErr = err
+			}
+		}
+	}
+
+	// 停止 Mailbox
+	if realm.mailbox != nil {
+		if stopper, ok := realm.mailbox.(interface{ Stop(context.Context) error }); ok {
+			if err := stopper.Stop(ctx); err != nil {
+				lastErr = err
+			}
+		}
+	}
+
 	// 停止 Liveness（先停止监控）
 	if realm.liveness != nil {
 		if stopper, ok := realm.liveness.(interface{ Stop(context.Context) error }); ok {
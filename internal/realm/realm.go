@@ -17,6 +17,8 @@ import (
 	"github.com/dep2p/go-dep2p/internal/realm/interfaces"
 	"github.com/dep2p/go-dep2p/internal/realm/member"
 	realmprotocol "github.com/dep2p/go-dep2p/internal/realm/protocol"
+	"github.com/dep2p/go-dep2p/internal/realm/routing"
+	"github.com/dep2p/go-dep2p/internal/realm/topology"
 	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
 	memberleavepb "github.com/dep2p/go-dep2p/pkg/lib/proto/realm/memberleave"
 	"github.com/dep2p/go-dep2p/pkg/protocol"
@@ -50,6 +52,12 @@ type realmImpl struct {
 	routing interfaces.Router
 	gateway interfaces.Gateway
 
+	// 成员转发器（多跳路由，由 protocol_factory 创建）
+	forwarder *routing.Forwarder
+
+	// 拓扑服务（可选，由 protocol_factory 创建）
+	topology *topology.Service
+
 	// Manager 引用
 	manager *Manager
 
@@ -61,6 +69,8 @@ type realmImpl struct {
 	pubsub    pkgif.PubSub
 	streams   pkgif.Streams
 	liveness  pkgif.Liveness
+	mailbox   pkgif.Mailbox
+	transfer  pkgif.Transfer
 
 	// 连接器（"仅 ID 连接"支持）
 	connector *connector.Connector
@@ -577,6 +587,36 @@ func (r *realmImpl) GetRouteTable() interfaces.RouteTable {
 	return r.routing.GetRouteTable()
 }
 
+// NewRoutedStream 经由成员转发路径打开到目标成员的流
+//
+// 实现 pkgif.RouteDialer，供 Messaging/Streams 在无直连或中继路径、
+// 或转发路径更优时使用。
+func (r *realmImpl) NewRoutedStream(ctx context.Context, peerID string, protocolID string) (pkgif.Stream, error) {
+	if r.forwarder == nil {
+		return nil, ErrRoutingFailed
+	}
+
+	return r.forwarder.NewRoutedStream(ctx, peerID, protocolID)
+}
+
+// NewRoutedStreamWithPriority 经由成员转发路径打开到目标成员的流（指定优先级）
+func (r *realmImpl) NewRoutedStreamWithPriority(ctx context.Context, peerID string, protocolID string, priority int) (pkgif.Stream, error) {
+	if r.forwarder == nil {
+		return nil, ErrRoutingFailed
+	}
+
+	return r.forwarder.NewRoutedStreamWithPriority(ctx, peerID, protocolID, priority)
+}
+
+// ShouldRoute 判断成员转发路径是否优于直连路径
+func (r *realmImpl) ShouldRoute(peerID string) bool {
+	if r.forwarder == nil {
+		return false
+	}
+
+	return r.forwarder.ShouldRoute(peerID)
+}
+
 // ============================================================================
 //                              网关
 // ============================================================================
@@ -654,6 +694,21 @@ func (r *realmImpl) Liveness() pkgif.Liveness {
 	return r.liveness
 }
 
+// Mailbox 返回离线信箱服务
+func (r *realmImpl) Mailbox() pkgif.Mailbox {
+	return r.mailbox
+}
+
+// Transfer 返回文件传输服务
+func (r *realmImpl) Transfer() pkgif.Transfer {
+	return r.transfer
+}
+
+// Topology 返回拓扑服务（未启用时为 nil）
+func (r *realmImpl) Topology() *topology.Service {
+	return r.topology
+}
+
 // newTicker 创建 Ticker
 func newTicker(d time.Duration) *time.Ticker {
 	return time.NewTicker(d)
@@ -3282,3 +3337,4 @@ func (r *realmImpl) Close() error {
 // 确保实现接口
 var _ interfaces.Realm = (*realmImpl)(nil)
 var _ pkgif.Realm = (*realmImpl)(nil)
+var _ pkgif.RouteDialer = (*realmImpl)(nil)
@@ -13,6 +13,9 @@ import (
 
 // Config 路由器配置
 type Config struct {
+	// LocalPeerID 本地节点 ID（路径查找起点）
+	LocalPeerID string
+
 	// 路由策略
 	DefaultPolicy interfaces.RoutingPolicy
 
@@ -43,6 +46,12 @@ type Config struct {
 	// Gateway 配置
 	EnableGatewayRouting bool          // 启用 Gateway 路由
 	GatewaySyncInterval  time.Duration // Gateway 同步间隔
+
+	// 成员转发配置
+	LinkRefreshInterval time.Duration // 链路状态刷新间隔
+	ForwardTimeout      time.Duration // 建立转发路径超时
+	RoutedLatencyRatio  float64       // 转发路径延迟低于直连延迟的该比例时优先转发
+	MaxForwardedStreams int           // 作为中间节点同时转发的最大流数
 }
 
 // PathScoreWeights 路径评分权重
@@ -89,6 +98,12 @@ func DefaultConfig() *Config {
 		// Gateway
 		EnableGatewayRouting: true,
 		GatewaySyncInterval:  1 * time.Minute,
+
+		// 成员转发
+		LinkRefreshInterval: 1 * time.Minute,
+		ForwardTimeout:      10 * time.Second,
+		RoutedLatencyRatio:  0.7,
+		MaxForwardedStreams: 128,
 	}
 }
 
@@ -114,6 +129,10 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("%w: OverloadThreshold must be between 0 and 1", ErrInvalidConfig)
 	}
 
+	if c.RoutedLatencyRatio < 0 || c.RoutedLatencyRatio > 1 {
+		return fmt.Errorf("%w: RoutedLatencyRatio must be between 0 and 1", ErrInvalidConfig)
+	}
+
 	// 验证评分权重和为 1.0
 	totalWeight := c.PathScoreWeight.Latency + c.PathScoreWeight.Hops + c.PathScoreWeight.Load
 	if totalWeight < 0.99 || totalWeight > 1.01 {
@@ -126,6 +145,7 @@ func (c *Config) Validate() error {
 // Clone 克隆配置
 func (c *Config) Clone() *Config {
 	return &Config{
+		LocalPeerID:          c.LocalPeerID,
 		DefaultPolicy:        c.DefaultPolicy,
 		CacheSize:            c.CacheSize,
 		CacheTTL:             c.CacheTTL,
@@ -143,5 +163,9 @@ func (c *Config) Clone() *Config {
 		NodeExpireTime:       c.NodeExpireTime,
 		EnableGatewayRouting: c.EnableGatewayRouting,
 		GatewaySyncInterval:  c.GatewaySyncInterval,
+		LinkRefreshInterval:  c.LinkRefreshInterval,
+		ForwardTimeout:       c.ForwardTimeout,
+		RoutedLatencyRatio:   c.RoutedLatencyRatio,
+		MaxForwardedStreams:  c.MaxForwardedStreams,
 	}
 }
@@ -11,6 +11,7 @@
 //   - 负载均衡（加权轮询）
 //   - 路由缓存（LRU + TTL）
 //   - Gateway 协作（中继路由）
+//   - 成员转发（洋葱式封装 + 端到端加密的多跳流）
 //
 // # 核心组件
 //
@@ -54,6 +55,18 @@
 //   - 过载保护
 //   - 负载报告
 //
+// ## Forwarder（成员转发器）
+//
+// Forwarder 让 Realm 流量经由其他成员逐跳转发（协议 /dep2p/realm/<realmID>/route/1.0.0）。
+//
+// 特性：
+//   - 链路状态交换：成员签名的直连成员及 RTT 写入路由表（RouteNode.Links），供 PathFinder 使用；
+//     直连成员声明的 RTT 与本节点实测值比对，虚报的报告不予采纳
+//   - 路径选择：复用 FindRoutes + SelectBestRoute 的路由策略
+//   - 洋葱式封装：中间成员只知道上一跳和下一跳
+//   - 端到端加密：目标验证发起方签名后，全部数据以会话密钥加密
+//   - Messaging/Streams 在无直连/中继路径或转发更快时自动使用（pkgif.RouteDialer）
+//
 // ## LatencyProber（延迟探测器）
 //
 // LatencyProber 负责网络延迟测量与预测。
@@ -50,4 +50,40 @@ var (
 
 	// ErrNoDHT DHT 不可用
 	ErrNoDHT = errors.New("routing: DHT is not available")
+
+	// ErrNilHost Host 为空
+	ErrNilHost = errors.New("routing: host is nil")
+
+	// ErrNilRealm Realm 为空
+	ErrNilRealm = errors.New("routing: realm is nil")
+
+	// ErrNotMember 节点不是 Realm 成员
+	ErrNotMember = errors.New("routing: peer is not a realm member")
+
+	// ErrNoHopKey 缺少转发节点公钥
+	ErrNoHopKey = errors.New("routing: hop public key unavailable")
+
+	// ErrNoLocalKey 缺少本地私钥
+	ErrNoLocalKey = errors.New("routing: local private key unavailable")
+
+	// ErrInvalidOnion 无效的洋葱层
+	ErrInvalidOnion = errors.New("routing: invalid onion layer")
+
+	// ErrInvalidHeader 无效的路由头（签名、时间戳或重放）
+	ErrInvalidHeader = errors.New("routing: invalid route header")
+
+	// ErrInvalidLinkState 无效的链路状态（签名、时间戳或 RTT 抽查失败）
+	ErrInvalidLinkState = errors.New("routing: invalid link state")
+
+	// ErrFrameTooLarge 帧过大
+	ErrFrameTooLarge = errors.New("routing: frame too large")
+
+	// ErrFrameAuth 帧认证失败
+	ErrFrameAuth = errors.New("routing: frame authentication failed")
+
+	// ErrRoutedConnStream 转发虚拟连接不支持创建新流
+	ErrRoutedConnStream = errors.New("routing: routed connection does not support new streams")
+
+	// ErrForwardBusy 转发并发已达上限
+	ErrForwardBusy = errors.New("routing: too many forwarded streams")
 )
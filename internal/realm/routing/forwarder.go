@@ -0,0 +1,828 @@
+package routing
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dep2p/go-dep2p/internal/realm/interfaces"
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/log"
+	"github.com/dep2p/go-dep2p/pkg/protocol"
+	"github.com/dep2p/go-dep2p/pkg/types"
+	mss "github.com/multiformats/go-multistream"
+)
+
+// ============================================================================
+//                              成员转发器
+// ============================================================================
+
+// Forwarder 成员转发器
+//
+// Forwarder 让 Realm 流量可以经由其他成员逐跳转发：
+//   - 链路状态：周期性向直连成员交换"我直连了谁、RTT 多少"，
+//     写入路由表供 PathFinder 计算多跳路径
+//   - 路径选择：复用 Router.FindRoutes 与 SelectBestRoute 的路由策略
+//   - 转发：洋葱式分层封装，中间成员只知道上一跳和下一跳
+//   - 端到端加密：目标验证发起方签名后，双方以会话密钥加密全部数据
+//
+// 协议 ID: /dep2p/realm/<realmID>/route/1.0.0
+type Forwarder struct {
+	host       pkgif.Host
+	realm      pkgif.Realm
+	router     interfaces.Router
+	config     *Config
+	protocolID string
+
+	mu sync.Mutex
+	// states 从其他成员学到的链路状态
+	states map[string]*learnedLinks
+	// tableNodes 由转发器写入路由表的节点
+	tableNodes map[string]struct{}
+	// seen 已接受的路由头签名（防重放）
+	seen map[string]time.Time
+
+	forwarding atomic.Int32
+
+	started atomic.Bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// learnedLinks 学到的链路状态
+type learnedLinks struct {
+	links   map[string]time.Duration
+	updated time.Time
+	// report 成员签名的原始报告，转发给其他成员时原样发送
+	report *linkState
+}
+
+// NewForwarder 创建成员转发器
+func NewForwarder(host pkgif.Host, realm pkgif.Realm, router interfaces.Router, config *Config) (*Forwarder, error) {
+	if host == nil {
+		return nil, ErrNilHost
+	}
+	if realm == nil {
+		return nil, ErrNilRealm
+	}
+	if router == nil {
+		return nil, ErrInvalidConfig
+	}
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	return &Forwarder{
+		host:       host,
+		realm:      realm,
+		router:     router,
+		config:     config,
+		protocolID: string(protocol.NewRealmBuilder(realm.ID()).Route()),
+		states:     make(map[string]*learnedLinks),
+		tableNodes: make(map[string]struct{}),
+		seen:       make(map[string]time.Time),
+	}, nil
+}
+
+// ============================================================================
+//                              生命周期
+// ============================================================================
+
+// Start 启动转发器
+func (f *Forwarder) Start(_ context.Context) error {
+	if !f.started.CompareAndSwap(false, true) {
+		return ErrAlreadyStarted
+	}
+
+	// 使用 context.Background() 保证后台循环不受上层 ctx 取消的影响
+	f.ctx, f.cancel = context.WithCancel(context.Background())
+	f.host.SetStreamHandler(f.protocolID, f.handleStream)
+
+	f.wg.Add(1)
+	go f.refreshLoop()
+
+	logger.Debug("成员转发器已启动", "realmID", f.realm.ID())
+	return nil
+}
+
+// Stop 停止转发器
+func (f *Forwarder) Stop(_ context.Context) error {
+	if !f.started.CompareAndSwap(true, false) {
+		return ErrNotStarted
+	}
+
+	f.host.RemoveStreamHandler(f.protocolID)
+	f.cancel()
+	f.wg.Wait()
+	return nil
+}
+
+// refreshLoop 链路状态刷新循环
+func (f *Forwarder) refreshLoop() {
+	defer f.wg.Done()
+
+	interval := f.config.LinkRefreshInterval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	// 启动后尽快完成首次交换
+	timer := time.NewTimer(time.Second)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			f.RefreshLinks(f.ctx)
+			timer.Reset(interval)
+		case <-f.ctx.Done():
+			return
+		}
+	}
+}
+
+// ============================================================================
+//                              链路状态
+// ============================================================================
+
+// RefreshLinks 与直连成员交换链路状态并更新路由表
+//
+// 每份链路状态都必须带有对应成员的有效签名；直连成员的自身报告
+// 还会与本节点实测的 RTT 比对，虚报链路质量的报告不予采纳。
+func (f *Forwarder) RefreshLinks(ctx context.Context) {
+	own := f.localLinks()
+
+	type result struct {
+		peer string
+		resp *linksResponse
+	}
+	results := make(chan result, len(own))
+
+	var wg sync.WaitGroup
+	for peer := range own {
+		wg.Add(1)
+		go func(peer string) {
+			defer wg.Done()
+			resp, err := f.queryLinks(ctx, peer)
+			if err != nil {
+				logger.Debug("查询链路状态失败", "peer", log.TruncateID(peer, 8), "error", err)
+				return
+			}
+			results <- result{peer: peer, resp: resp}
+		}(peer)
+	}
+	wg.Wait()
+	close(results)
+
+	now := time.Now()
+	self := f.host.ID()
+
+	f.mu.Lock()
+	for r := range results {
+		if err := f.checkDirectLinks(r.peer, own[r.peer], r.resp.Self, now); err != nil {
+			logger.Debug("拒绝直连成员链路状态", "peer", log.TruncateID(r.peer, 8), "error", err)
+		} else {
+			f.learn(r.resp.Self)
+		}
+		for _, st := range r.resp.States {
+			if st == nil || st.PeerID == self {
+				continue
+			}
+			// 直连成员以其自身报告为准
+			if _, direct := own[st.PeerID]; direct {
+				continue
+			}
+			if err := f.verifyLinkState(st, now); err != nil {
+				logger.Debug("拒绝转发的链路状态", "peer", log.TruncateID(st.PeerID, 8), "error", err)
+				continue
+			}
+			if cur, ok := f.states[st.PeerID]; ok && !cur.updated.Before(time.Unix(0, st.Issued)) {
+				continue
+			}
+			f.learn(st)
+		}
+	}
+	for peer, st := range f.states {
+		if now.Sub(st.updated) > f.config.NodeExpireTime || !f.realm.IsMember(peer) {
+			delete(f.states, peer)
+		}
+	}
+	f.mu.Unlock()
+
+	f.syncTable(own)
+}
+
+// learn 记录已验证的链路状态（调用方持有 f.mu）
+func (f *Forwarder) learn(st *linkState) {
+	f.states[st.PeerID] = &learnedLinks{
+		links:   f.filterMembers(st.Links),
+		updated: time.Unix(0, st.Issued),
+		report:  st,
+	}
+}
+
+// checkDirectLinks 验证直连成员的自身报告并抽查其声明的 RTT
+//
+// RTT 是对称的：成员声明的到本节点的 RTT 明显低于本节点实测值时，
+// 说明它在虚报链路质量以吸引转发流量，整份报告不予采纳。
+func (f *Forwarder) checkDirectLinks(peer string, measured time.Duration, st *linkState, now time.Time) error {
+	if st == nil || st.PeerID != peer {
+		return fmt.Errorf("%w: report not from %s", ErrInvalidLinkState, log.TruncateID(peer, 8))
+	}
+	if err := f.verifyLinkState(st, now); err != nil {
+		return err
+	}
+	claimed, ok := st.Links[f.host.ID()]
+	if ok && measured > 0 && float64(claimed) < float64(measured)*linkRTTTolerance {
+		return fmt.Errorf("%w: claimed rtt %v, measured %v", ErrInvalidLinkState, claimed, measured)
+	}
+	return nil
+}
+
+// verifyLinkState 验证链路状态的成员身份、时效和签名
+func (f *Forwarder) verifyLinkState(st *linkState, now time.Time) error {
+	if !f.realm.IsMember(st.PeerID) {
+		return ErrNotMember
+	}
+	issued := time.Unix(0, st.Issued)
+	if issued.After(now.Add(maxClockSkew)) || now.Sub(issued) > f.config.NodeExpireTime {
+		return fmt.Errorf("%w: stale timestamp", ErrInvalidLinkState)
+	}
+
+	pub, err := f.host.Peerstore().PubKey(types.PeerID(st.PeerID))
+	if err != nil || pub == nil {
+		return ErrNoHopKey
+	}
+	ok, err := pub.Verify(st.signingBytes(f.realm.ID()), st.Sig)
+	if err != nil || !ok {
+		return fmt.Errorf("%w: bad signature", ErrInvalidLinkState)
+	}
+	return nil
+}
+
+// signLinks 为本节点的链路状态签名
+func (f *Forwarder) signLinks(links map[string]time.Duration) (*linkState, error) {
+	priv, err := f.host.Peerstore().PrivKey(types.PeerID(f.host.ID()))
+	if err != nil || priv == nil {
+		return nil, ErrNoLocalKey
+	}
+	st := &linkState{
+		PeerID: f.host.ID(),
+		Links:  links,
+		Issued: time.Now().UnixNano(),
+	}
+	if st.Sig, err = priv.Sign(st.signingBytes(f.realm.ID())); err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// localLinks 返回本节点直连的成员及 RTT
+func (f *Forwarder) localLinks() map[string]time.Duration {
+	links := make(map[string]time.Duration)
+	network := f.host.Network()
+	if network == nil {
+		return links
+	}
+
+	self := f.host.ID()
+	liveness := f.realm.Liveness()
+	for _, member := range f.realm.Members() {
+		if member == self || network.Connectedness(member) != pkgif.Connected {
+			continue
+		}
+		var rtt time.Duration
+		if liveness != nil {
+			status := liveness.GetStatus(member)
+			rtt = status.AvgRTT
+			if rtt <= 0 {
+				rtt = status.LastRTT
+			}
+		}
+		links[member] = rtt
+	}
+	return links
+}
+
+// filterMembers 过滤掉非成员链路
+func (f *Forwarder) filterMembers(links map[string]time.Duration) map[string]time.Duration {
+	out := make(map[string]time.Duration, len(links))
+	for peer, rtt := range links {
+		if f.realm.IsMember(peer) {
+			out[peer] = rtt
+		}
+	}
+	return out
+}
+
+// syncTable 将链路状态写入路由表
+func (f *Forwarder) syncTable(own map[string]time.Duration) {
+	table := f.router.GetRouteTable()
+	if table == nil {
+		return
+	}
+
+	now := time.Now()
+	self := f.host.ID()
+	nodes := make(map[string]*interfaces.RouteNode)
+
+	node := func(peer string) *interfaces.RouteNode {
+		n, ok := nodes[peer]
+		if !ok {
+			rtt, direct := own[peer]
+			// 未知链路的节点只作为终点，不向外扩展
+			n = &interfaces.RouteNode{
+				PeerID:      peer,
+				Latency:     rtt,
+				LastSeen:    now,
+				IsReachable: direct,
+				Links:       map[string]time.Duration{},
+			}
+			nodes[peer] = n
+		}
+		return n
+	}
+
+	node(self).Links = own
+	node(self).IsReachable = false
+	for peer := range own {
+		node(peer)
+	}
+
+	f.mu.Lock()
+	for peer, st := range f.states {
+		n := node(peer)
+		n.Links = st.links
+		n.LastSeen = st.updated
+		for next := range st.links {
+			if next != self {
+				node(next)
+			}
+		}
+	}
+	previous := f.tableNodes
+	f.tableNodes = make(map[string]struct{}, len(nodes))
+	for peer := range nodes {
+		f.tableNodes[peer] = struct{}{}
+	}
+	f.mu.Unlock()
+
+	for peer := range previous {
+		if _, ok := nodes[peer]; !ok {
+			_ = table.RemoveNode(peer)
+		}
+	}
+	for _, n := range nodes {
+		if err := table.AddNode(n); err != nil {
+			logger.Debug("写入路由表失败", "peer", log.TruncateID(n.PeerID, 8), "error", err)
+		}
+	}
+
+	if inv, ok := f.router.(interface{ InvalidateAll() }); ok {
+		inv.InvalidateAll()
+	}
+}
+
+// queryLinks 查询直连成员的链路状态
+func (f *Forwarder) queryLinks(ctx context.Context, peer string) (*linksResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, f.forwardTimeout())
+	defer cancel()
+
+	stream, err := f.host.NewStream(ctx, peer, f.protocolID)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = stream.SetDeadline(deadline)
+	}
+	if err := writeControl(stream, &forwardRequest{Op: forwardOpLinks}); err != nil {
+		return nil, err
+	}
+	var resp linksResponse
+	if err := readControl(stream, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// serveLinks 返回本节点签名的链路状态及已知的其他成员报告
+func (f *Forwarder) serveLinks(stream pkgif.Stream) {
+	self, err := f.signLinks(f.localLinks())
+	if err != nil {
+		logger.Debug("签名链路状态失败", "error", err)
+		return
+	}
+	resp := &linksResponse{Self: self}
+
+	f.mu.Lock()
+	for _, st := range f.states {
+		if len(resp.States) >= maxLinkStates {
+			break
+		}
+		resp.States = append(resp.States, st.report)
+	}
+	f.mu.Unlock()
+
+	if err := writeControl(stream, resp); err != nil {
+		logger.Debug("发送链路状态失败", "error", err)
+	}
+}
+
+// ============================================================================
+//                              发起转发
+// ============================================================================
+
+// NewRoutedStream 经由成员转发路径打开到 peerID 的流
+//
+// 路径由 Router 按配置的路由策略选出，且至少经过一个中间成员。
+// 返回的流已完成协议协商，可直接按 protocolID 的语义读写。
+func (f *Forwarder) NewRoutedStream(ctx context.Context, peerID string, protocolID string) (pkgif.Stream, error) {
+	return f.NewRoutedStreamWithPriority(ctx, peerID, protocolID, int(pkgif.StreamPriorityNormal))
+}
+
+// NewRoutedStreamWithPriority 经由成员转发路径打开到 peerID 的流（指定优先级）
+//
+// 优先级随转发请求传递给每一跳，路径上的每段流都使用同一优先级。
+func (f *Forwarder) NewRoutedStreamWithPriority(ctx context.Context, peerID string, protocolID string, priority int) (pkgif.Stream, error) {
+	if !f.started.Load() {
+		return nil, ErrNotStarted
+	}
+	if !f.realm.IsMember(peerID) {
+		return nil, fmt.Errorf("%w: %s", ErrNotMember, log.TruncateID(peerID, 8))
+	}
+
+	route, err := f.selectRoute(ctx, peerID)
+	if err != nil {
+		// 拓扑可能已变化，刷新一次后重试
+		f.RefreshLinks(ctx)
+		if route, err = f.selectRoute(ctx, peerID); err != nil {
+			return nil, err
+		}
+	}
+
+	stream, err := f.openPath(ctx, route, protocolID, priority)
+	if err != nil {
+		f.router.InvalidateRoute(peerID)
+		return nil, err
+	}
+	return stream, nil
+}
+
+// ShouldRoute 判断成员转发路径是否明显优于直连路径
+//
+// 仅当直连延迟已知，且最佳转发路径延迟低于直连延迟的
+// RoutedLatencyRatio 倍时返回 true。
+func (f *Forwarder) ShouldRoute(peerID string) bool {
+	if !f.started.Load() || f.config.RoutedLatencyRatio <= 0 {
+		return false
+	}
+
+	table := f.router.GetRouteTable()
+	if table == nil {
+		return false
+	}
+	node, err := table.GetNode(peerID)
+	if err != nil || !node.IsReachable || node.Latency <= 0 {
+		return false
+	}
+
+	route, err := f.selectRoute(context.Background(), peerID)
+	if err != nil || route.Latency <= 0 {
+		return false
+	}
+	threshold := time.Duration(float64(node.Latency) * f.config.RoutedLatencyRatio)
+	return route.Latency < threshold
+}
+
+// selectRoute 按路由策略选择多跳路径
+func (f *Forwarder) selectRoute(ctx context.Context, peerID string) (*interfaces.Route, error) {
+	count := f.config.MaxPaths
+	if count <= 0 {
+		count = 1
+	}
+	routes, err := f.router.FindRoutes(ctx, peerID, count)
+	if err != nil {
+		return nil, err
+	}
+
+	self := f.host.ID()
+	candidates := make([]*interfaces.Route, 0, len(routes))
+	for _, route := range routes {
+		// 直连路径不需要转发
+		if route.Hops < 2 || len(route.Path) != route.Hops+1 || route.Path[0] != self {
+			continue
+		}
+		if f.config.MaxHops > 0 && route.Hops > f.config.MaxHops {
+			continue
+		}
+		candidates = append(candidates, route)
+	}
+	if len(candidates) == 0 {
+		return nil, ErrNoViablePath
+	}
+	return f.router.SelectBestRoute(ctx, candidates, f.config.DefaultPolicy)
+}
+
+// openPath 沿路径建立端到端加密流
+func (f *Forwarder) openPath(ctx context.Context, route *interfaces.Route, protocolID string, priority int) (pkgif.Stream, error) {
+	ctx, cancel := context.WithTimeout(ctx, f.forwardTimeout())
+	defer cancel()
+
+	self := f.host.ID()
+	hops := route.Path[1:]
+	target := hops[len(hops)-1]
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	header := &routeHeader{
+		Origin:    self,
+		Target:    target,
+		Protocol:  protocolID,
+		Key:       key,
+		Timestamp: time.Now().UnixNano(),
+	}
+	priv, err := f.host.Peerstore().PrivKey(types.PeerID(self))
+	if err != nil || priv == nil {
+		return nil, ErrNoLocalKey
+	}
+	if header.Sig, err = priv.Sign(header.signingBytes(f.realm.ID())); err != nil {
+		return nil, err
+	}
+
+	onion, err := buildOnion(f.realm.ID(), hops, header, f.peerPubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := f.host.NewStreamWithPriority(ctx, hops[0], f.protocolID, priority)
+	if err != nil {
+		return nil, fmt.Errorf("open first hop %s: %w", log.TruncateID(hops[0], 8), err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = raw.SetDeadline(deadline)
+	}
+
+	conn := &routedConn{
+		local:  types.PeerID(self),
+		remote: types.PeerID(target),
+		path:   append([]string(nil), route.Path...),
+		opened: time.Now(),
+		dir:    pkgif.DirOutbound,
+	}
+	stream, err := newRoutedStream(raw, conn, key, true, protocolID)
+	if err != nil {
+		raw.Reset()
+		return nil, err
+	}
+	conn.stream.Store(stream)
+
+	if err := writeControl(raw, &forwardRequest{Op: forwardOpRelay, Onion: onion, Priority: priority}); err != nil {
+		raw.Reset()
+		return nil, err
+	}
+	if err := stream.awaitAccept(); err != nil {
+		raw.Reset()
+		return nil, fmt.Errorf("route not accepted: %w", err)
+	}
+
+	// 与直连流一致，在端到端加密流上做 multistream-select 协商
+	if _, err := mss.SelectOneOf([]string{protocolID}, stream); err != nil {
+		raw.Reset()
+		return nil, fmt.Errorf("protocol negotiation failed: %w", err)
+	}
+	_ = raw.SetDeadline(time.Time{})
+
+	logger.Debug("成员转发路径已建立",
+		"target", log.TruncateID(target, 8),
+		"hops", route.Hops,
+		"latency", route.Latency)
+	return stream, nil
+}
+
+// ============================================================================
+//                              处理转发请求
+// ============================================================================
+
+// handleStream 处理转发协议流
+func (f *Forwarder) handleStream(stream pkgif.Stream) {
+	remote := string(stream.Conn().RemotePeer())
+	if !f.realm.IsMember(remote) {
+		stream.Reset()
+		return
+	}
+
+	_ = stream.SetDeadline(time.Now().Add(f.forwardTimeout()))
+
+	var req forwardRequest
+	if err := readControl(stream, &req); err != nil {
+		stream.Reset()
+		return
+	}
+
+	switch req.Op {
+	case forwardOpLinks:
+		f.serveLinks(stream)
+		stream.Close()
+	case forwardOpRelay:
+		f.serveRelay(stream, remote, &req)
+	default:
+		stream.Reset()
+	}
+}
+
+// serveRelay 解开本层洋葱，转发给下一跳或作为目标接收
+func (f *Forwarder) serveRelay(stream pkgif.Stream, prev string, req *forwardRequest) {
+	self := f.host.ID()
+	priv, err := f.localPrivKey()
+	if err != nil {
+		stream.Reset()
+		return
+	}
+
+	layer, err := peelOnion(f.realm.ID(), self, priv, req.Onion)
+	if err != nil {
+		logger.Debug("解开洋葱层失败", "prev", log.TruncateID(prev, 8), "error", err)
+		stream.Reset()
+		return
+	}
+
+	if layer.Final != nil {
+		f.acceptRoute(stream, layer.Final)
+		return
+	}
+	f.relayTo(stream, prev, layer.Next, layer.Inner, req.Priority)
+}
+
+// relayTo 作为中间成员转发到下一跳
+func (f *Forwarder) relayTo(stream pkgif.Stream, prev, next string, inner []byte, priority int) {
+	if next == f.host.ID() || next == prev || !f.realm.IsMember(next) {
+		stream.Reset()
+		return
+	}
+
+	limit := int32(f.config.MaxForwardedStreams)
+	if limit > 0 && f.forwarding.Add(1) > limit {
+		f.forwarding.Add(-1)
+		logger.Debug("转发并发已达上限", "error", ErrForwardBusy)
+		stream.Reset()
+		return
+	}
+	if limit > 0 {
+		defer f.forwarding.Add(-1)
+	}
+
+	ctx, cancel := context.WithTimeout(f.ctx, f.forwardTimeout())
+	out, err := f.host.NewStreamWithPriority(ctx, next, f.protocolID, priority)
+	cancel()
+	if err != nil {
+		logger.Debug("打开下一跳失败", "next", log.TruncateID(next, 8), "error", err)
+		stream.Reset()
+		return
+	}
+	if err := writeControl(out, &forwardRequest{Op: forwardOpRelay, Onion: inner, Priority: priority}); err != nil {
+		out.Reset()
+		stream.Reset()
+		return
+	}
+
+	_ = stream.SetDeadline(time.Time{})
+	logger.Debug("转发成员流量", "prev", log.TruncateID(prev, 8), "next", log.TruncateID(next, 8))
+	pipeStreams(stream, out)
+}
+
+// acceptRoute 作为目标验证路由头并把流交给 Host 协议路由
+func (f *Forwarder) acceptRoute(stream pkgif.Stream, header *routeHeader) {
+	if err := f.verifyHeader(header); err != nil {
+		logger.Debug("拒绝转发路径", "origin", log.TruncateID(header.Origin, 8), "error", err)
+		stream.Reset()
+		return
+	}
+
+	conn := &routedConn{
+		local:  types.PeerID(f.host.ID()),
+		remote: types.PeerID(header.Origin),
+		opened: time.Now(),
+		dir:    pkgif.DirInbound,
+	}
+	secure, err := newRoutedStream(stream, conn, header.Key, false, "")
+	if err != nil {
+		stream.Reset()
+		return
+	}
+	conn.stream.Store(secure)
+
+	if err := secure.sendAccept(); err != nil {
+		stream.Reset()
+		return
+	}
+	_ = stream.SetDeadline(time.Time{})
+
+	// 交给 Host 做协议协商与路由，处理器看到的远端为发起方
+	f.host.HandleInboundStream(secure)
+}
+
+// verifyHeader 验证路由头
+func (f *Forwarder) verifyHeader(h *routeHeader) error {
+	if h.Target != f.host.ID() || len(h.Key) != 32 {
+		return ErrInvalidHeader
+	}
+	if !f.realm.IsMember(h.Origin) {
+		return ErrNotMember
+	}
+
+	now := time.Now()
+	ts := time.Unix(0, h.Timestamp)
+	if ts.Before(now.Add(-maxClockSkew)) || ts.After(now.Add(maxClockSkew)) {
+		return fmt.Errorf("%w: stale timestamp", ErrInvalidHeader)
+	}
+
+	pub, err := f.host.Peerstore().PubKey(types.PeerID(h.Origin))
+	if err != nil || pub == nil {
+		return ErrNoHopKey
+	}
+	ok, err := pub.Verify(h.signingBytes(f.realm.ID()), h.Sig)
+	if err != nil || !ok {
+		return fmt.Errorf("%w: bad signature", ErrInvalidHeader)
+	}
+
+	// 重放检查：时间窗口内同一签名只接受一次
+	sig := string(h.Sig)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for s, at := range f.seen {
+		if now.Sub(at) > 2*maxClockSkew {
+			delete(f.seen, s)
+		}
+	}
+	if _, dup := f.seen[sig]; dup {
+		return fmt.Errorf("%w: replayed", ErrInvalidHeader)
+	}
+	f.seen[sig] = now
+	return nil
+}
+
+// ============================================================================
+//                              辅助方法
+// ============================================================================
+
+// forwardTimeout 返回建立转发路径的超时
+func (f *Forwarder) forwardTimeout() time.Duration {
+	if f.config.ForwardTimeout > 0 {
+		return f.config.ForwardTimeout
+	}
+	return 10 * time.Second
+}
+
+// peerPubKey 获取成员的 Ed25519 原始公钥
+func (f *Forwarder) peerPubKey(peerID string) ([]byte, error) {
+	pub, err := f.host.Peerstore().PubKey(types.PeerID(peerID))
+	if err != nil {
+		return nil, err
+	}
+	if pub == nil || pub.Type() != pkgif.KeyTypeEd25519 {
+		return nil, ErrNoHopKey
+	}
+	return pub.Raw()
+}
+
+// localPrivKey 获取本地 Ed25519 原始私钥
+func (f *Forwarder) localPrivKey() ([]byte, error) {
+	priv, err := f.host.Peerstore().PrivKey(types.PeerID(f.host.ID()))
+	if err != nil || priv == nil {
+		return nil, ErrNoLocalKey
+	}
+	if priv.Type() != pkgif.KeyTypeEd25519 {
+		return nil, ErrNoLocalKey
+	}
+	return priv.Raw()
+}
+
+// pipeStreams 双向拷贝两条流，单向结束时传递半关闭
+func pipeStreams(a, b pkgif.Stream) {
+	var wg sync.WaitGroup
+	wg.Add(2)
+	copyHalf := func(dst, src pkgif.Stream) {
+		defer wg.Done()
+		if _, err := io.Copy(dst, src); err != nil {
+			dst.Reset()
+			src.Reset()
+			return
+		}
+		_ = dst.CloseWrite()
+	}
+	go copyHalf(a, b)
+	go copyHalf(b, a)
+	wg.Wait()
+
+	a.Close()
+	b.Close()
+}
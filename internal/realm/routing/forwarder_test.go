@@ -0,0 +1,435 @@
+package routing
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dep2p/go-dep2p/internal/core/identity"
+	"github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/types"
+	"github.com/dep2p/go-dep2p/tests/mocks"
+	mss "github.com/multiformats/go-multistream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pipeStream 基于 net.Pipe 的流，写入的数据会被记录到测试网络
+type pipeStream struct {
+	pipe     net.Conn
+	conn     interfaces.Connection
+	protocol string
+	tap      func(p []byte)
+}
+
+func (s *pipeStream) Read(p []byte) (int, error) { return s.pipe.Read(p) }
+func (s *pipeStream) Write(p []byte) (int, error) {
+	if s.tap != nil {
+		s.tap(p)
+	}
+	return s.pipe.Write(p)
+}
+func (s *pipeStream) Close() error                       { return s.pipe.Close() }
+func (s *pipeStream) SetDeadline(t time.Time) error      { return s.pipe.SetDeadline(t) }
+func (s *pipeStream) SetReadDeadline(t time.Time) error  { return s.pipe.SetReadDeadline(t) }
+func (s *pipeStream) SetWriteDeadline(t time.Time) error { return s.pipe.SetWriteDeadline(t) }
+func (s *pipeStream) CloseWrite() error                  { return nil }
+func (s *pipeStream) CloseRead() error                   { return nil }
+func (s *pipeStream) Reset() error                       { return s.pipe.Close() }
+func (s *pipeStream) Protocol() string                   { return s.protocol }
+func (s *pipeStream) SetProtocol(p string)               { s.protocol = p }
+func (s *pipeStream) Conn() interfaces.Connection        { return s.conn }
+func (s *pipeStream) IsClosed() bool                     { return false }
+func (s *pipeStream) Stat() types.StreamStat             { return types.StreamStat{} }
+func (s *pipeStream) State() types.StreamState           { return types.StreamState(0) }
+
+// testNet 测试网络：只允许相邻节点之间打开流，并记录线路上的字节
+type testNet struct {
+	mu       sync.Mutex
+	handlers map[string]map[string]interfaces.StreamHandler
+	links    map[string]map[string]bool
+	wire     bytes.Buffer
+}
+
+type testNode struct {
+	id   string
+	host *mocks.MockHost
+	ps   *mocks.MockPeerstore
+}
+
+func newTestNet() *testNet {
+	return &testNet{
+		handlers: make(map[string]map[string]interfaces.StreamHandler),
+		links:    make(map[string]map[string]bool),
+	}
+}
+
+func (n *testNet) connect(a, b *testNode) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, pair := range [][2]string{{a.id, b.id}, {b.id, a.id}} {
+		if n.links[pair[0]] == nil {
+			n.links[pair[0]] = make(map[string]bool)
+		}
+		n.links[pair[0]][pair[1]] = true
+	}
+}
+
+func (n *testNet) connected(a, b string) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.links[a][b]
+}
+
+func (n *testNet) addNode(t *testing.T) *testNode {
+	t.Helper()
+
+	ident, err := identity.Generate()
+	require.NoError(t, err)
+	id := ident.PeerID()
+
+	ps := mocks.NewMockPeerstore()
+	require.NoError(t, ps.AddPrivKey(types.PeerID(id), ident.PrivateKey()))
+
+	swarm := mocks.NewMockSwarm(id)
+	swarm.ConnectednessFunc = func(peerID string) interfaces.Connectedness {
+		if n.connected(id, peerID) {
+			return interfaces.Connected
+		}
+		return interfaces.NotConnected
+	}
+
+	host := mocks.NewMockHost(id)
+	host.PeerstoreFunc = func() interfaces.Peerstore { return ps }
+	host.NetworkFunc = func() interfaces.Swarm { return swarm }
+	host.SetStreamHandlerFunc = func(protocolID string, handler interfaces.StreamHandler) {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		if n.handlers[id] == nil {
+			n.handlers[id] = make(map[string]interfaces.StreamHandler)
+		}
+		n.handlers[id][protocolID] = handler
+	}
+	host.RemoveStreamHandlerFunc = func(protocolID string) {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		delete(n.handlers[id], protocolID)
+	}
+	host.HandleInboundStreamFunc = func(stream interfaces.Stream) {
+		n.mu.Lock()
+		mux := mss.NewMultistreamMuxer[string]()
+		handlers := make(map[string]interfaces.StreamHandler)
+		for proto, h := range n.handlers[id] {
+			mux.AddHandler(proto, nil)
+			handlers[proto] = h
+		}
+		n.mu.Unlock()
+
+		proto, _, err := mux.Negotiate(stream)
+		if err != nil {
+			stream.Reset()
+			return
+		}
+		stream.SetProtocol(proto)
+		handlers[proto](stream)
+	}
+	host.NewStreamFunc = func(_ context.Context, peerID string, protocolIDs ...string) (interfaces.Stream, error) {
+		if !n.connected(id, peerID) {
+			return nil, assert.AnError
+		}
+		n.mu.Lock()
+		handler := n.handlers[peerID][protocolIDs[0]]
+		n.mu.Unlock()
+		if handler == nil {
+			return nil, assert.AnError
+		}
+
+		tap := func(p []byte) {
+			n.mu.Lock()
+			n.wire.Write(p)
+			n.mu.Unlock()
+		}
+		local, remote := net.Pipe()
+		go handler(&pipeStream{
+			pipe:     remote,
+			conn:     mocks.NewMockConnection(types.PeerID(peerID), types.PeerID(id)),
+			protocol: protocolIDs[0],
+			tap:      tap,
+		})
+		return &pipeStream{
+			pipe:     local,
+			conn:     mocks.NewMockConnection(types.PeerID(id), types.PeerID(peerID)),
+			protocol: protocolIDs[0],
+			tap:      tap,
+		}, nil
+	}
+
+	return &testNode{id: id, host: host, ps: ps}
+}
+
+// shareKeys 让所有节点互相获知公钥（模拟成员同步）
+func shareKeys(t *testing.T, nodes ...*testNode) {
+	t.Helper()
+	for _, a := range nodes {
+		for _, b := range nodes {
+			if a == b {
+				continue
+			}
+			priv, err := b.ps.PrivKey(types.PeerID(b.id))
+			require.NoError(t, err)
+			require.NoError(t, a.ps.AddPubKey(types.PeerID(b.id), priv.PublicKey()))
+		}
+	}
+}
+
+func startForwarder(t *testing.T, node *testNode, realm interfaces.Realm) *Forwarder {
+	t.Helper()
+
+	cfg := DefaultConfig()
+	cfg.LocalPeerID = node.id
+	cfg.LinkRefreshInterval = time.Hour
+
+	router := NewRouter(realm.ID(), nil, cfg)
+	require.NoError(t, router.Start(context.Background()))
+	t.Cleanup(func() { router.Close() })
+
+	f, err := NewForwarder(node.host, realm, router, cfg)
+	require.NoError(t, err)
+	require.NoError(t, f.Start(context.Background()))
+	t.Cleanup(func() { f.Stop(context.Background()) })
+	return f
+}
+
+func TestForwarder_MultiHopEndToEnd(t *testing.T) {
+	tn := newTestNet()
+	a, b, c, d := tn.addNode(t), tn.addNode(t), tn.addNode(t), tn.addNode(t)
+	shareKeys(t, a, b, c, d)
+
+	// 链式拓扑：a - b - c - d
+	tn.connect(a, b)
+	tn.connect(b, c)
+	tn.connect(c, d)
+
+	realm := mocks.NewMockRealm("realm-1")
+	realm.MemberList = []string{a.id, b.id, c.id, d.id}
+
+	fa := startForwarder(t, a, realm)
+	fb := startForwarder(t, b, realm)
+	fc := startForwarder(t, c, realm)
+	fd := startForwarder(t, d, realm)
+
+	// 从远端开始交换，使链路状态逐跳传播到 a
+	ctx := context.Background()
+	fd.RefreshLinks(ctx)
+	fc.RefreshLinks(ctx)
+	fb.RefreshLinks(ctx)
+	fa.RefreshLinks(ctx)
+
+	const echoProto = "/test/echo/1.0.0"
+	secret := []byte("top secret payload")
+	gotFrom := make(chan string, 1)
+	d.host.SetStreamHandler(echoProto, func(s interfaces.Stream) {
+		gotFrom <- string(s.Conn().RemotePeer())
+		buf := make([]byte, len(secret))
+		if _, err := io.ReadFull(s, buf); err != nil {
+			s.Reset()
+			return
+		}
+		s.Write(buf)
+		s.Close()
+	})
+
+	stream, err := fa.NewRoutedStream(ctx, d.id, echoProto)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	assert.Equal(t, types.PeerID(d.id), stream.Conn().RemotePeer())
+	assert.Equal(t, []string{a.id, b.id, c.id, d.id}, stream.Conn().(*routedConn).Path())
+
+	_, err = stream.Write(secret)
+	require.NoError(t, err)
+	echo := make([]byte, len(secret))
+	_, err = io.ReadFull(stream, echo)
+	require.NoError(t, err)
+	assert.Equal(t, secret, echo)
+
+	// 目标看到的远端是发起方
+	assert.Equal(t, a.id, <-gotFrom)
+
+	// 中间成员转发的只有密文
+	tn.mu.Lock()
+	wire := tn.wire.Bytes()
+	tn.mu.Unlock()
+	assert.False(t, bytes.Contains(wire, secret))
+	assert.False(t, bytes.Contains(wire, []byte(echoProto)))
+}
+
+func TestForwarder_RoutedStreamCarriesPriority(t *testing.T) {
+	tn := newTestNet()
+	a, b, c := tn.addNode(t), tn.addNode(t), tn.addNode(t)
+	shareKeys(t, a, b, c)
+	tn.connect(a, b)
+	tn.connect(b, c)
+
+	realm := mocks.NewMockRealm("realm-1")
+	realm.MemberList = []string{a.id, b.id, c.id}
+
+	fa := startForwarder(t, a, realm)
+	fb := startForwarder(t, b, realm)
+	fc := startForwarder(t, c, realm)
+
+	ctx := context.Background()
+	fc.RefreshLinks(ctx)
+	fb.RefreshLinks(ctx)
+	fa.RefreshLinks(ctx)
+
+	const proto = "/test/sink/1.0.0"
+	c.host.SetStreamHandler(proto, func(s interfaces.Stream) { s.Close() })
+
+	priority := int(interfaces.StreamPriorityCritical)
+	stream, err := fa.NewRoutedStreamWithPriority(ctx, c.id, proto, priority)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	// 每一跳都以指定优先级打开到下一跳的转发流
+	hopPriority := func(host *mocks.MockHost, next string) int {
+		calls := host.StreamCalls()
+		// 最后一次调用为转发流（之前的是链路状态查询）
+		for i := len(calls) - 1; i >= 0; i-- {
+			if calls[i].PeerID == next && calls[i].ProtocolIDs[0] == fa.protocolID {
+				return calls[i].Priority
+			}
+		}
+		return -1
+	}
+	assert.Equal(t, priority, hopPriority(a.host, b.id))
+	assert.Equal(t, priority, hopPriority(b.host, c.id))
+}
+
+func TestForwarder_RejectsNonMemberTarget(t *testing.T) {
+	tn := newTestNet()
+	a, b, c := tn.addNode(t), tn.addNode(t), tn.addNode(t)
+	shareKeys(t, a, b, c)
+	tn.connect(a, b)
+	tn.connect(b, c)
+
+	realm := mocks.NewMockRealm("realm-1")
+	realm.MemberList = []string{a.id, b.id}
+
+	fa := startForwarder(t, a, realm)
+	startForwarder(t, b, realm)
+
+	_, err := fa.NewRoutedStream(context.Background(), c.id, "/test/echo/1.0.0")
+	assert.ErrorIs(t, err, ErrNotMember)
+}
+
+func TestForwarder_NoPathWithoutIntermediate(t *testing.T) {
+	tn := newTestNet()
+	a, b := tn.addNode(t), tn.addNode(t)
+	shareKeys(t, a, b)
+
+	realm := mocks.NewMockRealm("realm-1")
+	realm.MemberList = []string{a.id, b.id}
+
+	fa := startForwarder(t, a, realm)
+	startForwarder(t, b, realm)
+
+	_, err := fa.NewRoutedStream(context.Background(), b.id, "/test/echo/1.0.0")
+	assert.Error(t, err)
+	assert.False(t, fa.ShouldRoute(b.id))
+}
+
+func TestForwarder_RejectsForgedLinkStates(t *testing.T) {
+	tn := newTestNet()
+	a, b, c, d := tn.addNode(t), tn.addNode(t), tn.addNode(t), tn.addNode(t)
+	shareKeys(t, a, b, c, d)
+	tn.connect(a, b)
+
+	realm := mocks.NewMockRealm("realm-1")
+	realm.MemberList = []string{a.id, b.id, c.id, d.id}
+
+	fa := startForwarder(t, a, realm)
+	fb := startForwarder(t, b, realm)
+	fc := startForwarder(t, c, realm)
+	fd := startForwarder(t, d, realm)
+
+	// c 的真实报告，b 篡改其中的 RTT 后转发
+	genuine, err := fc.signLinks(map[string]time.Duration{d.id: 80 * time.Millisecond})
+	require.NoError(t, err)
+	tampered := *genuine
+	tampered.Links = map[string]time.Duration{d.id: time.Millisecond}
+
+	// d 的报告原样转发
+	relayed, err := fd.signLinks(map[string]time.Duration{c.id: 80 * time.Millisecond})
+	require.NoError(t, err)
+
+	fb.mu.Lock()
+	fb.learn(&tampered)
+	fb.learn(relayed)
+	fb.mu.Unlock()
+
+	fa.RefreshLinks(context.Background())
+
+	fa.mu.Lock()
+	defer fa.mu.Unlock()
+	assert.Contains(t, fa.states, b.id)
+	assert.NotContains(t, fa.states, c.id)
+	require.Contains(t, fa.states, d.id)
+	assert.Equal(t, 80*time.Millisecond, fa.states[d.id].links[c.id])
+}
+
+func TestForwarder_SpotChecksClaimedRTT(t *testing.T) {
+	tn := newTestNet()
+	a, b := tn.addNode(t), tn.addNode(t)
+	shareKeys(t, a, b)
+
+	realm := mocks.NewMockRealm("realm-1")
+	realm.MemberList = []string{a.id, b.id}
+
+	fa := startForwarder(t, a, realm)
+	fb := startForwarder(t, b, realm)
+	now := time.Now()
+
+	honest, err := fb.signLinks(map[string]time.Duration{a.id: 30 * time.Millisecond})
+	require.NoError(t, err)
+	assert.NoError(t, fa.checkDirectLinks(b.id, 40*time.Millisecond, honest, now))
+
+	// 声明的 RTT 远低于实测值
+	inflated, err := fb.signLinks(map[string]time.Duration{a.id: 5 * time.Millisecond})
+	require.NoError(t, err)
+	assert.ErrorIs(t, fa.checkDirectLinks(b.id, 40*time.Millisecond, inflated, now), ErrInvalidLinkState)
+
+	// 报告必须来自直连成员本身
+	assert.ErrorIs(t, fa.checkDirectLinks(a.id, 40*time.Millisecond, honest, now), ErrInvalidLinkState)
+
+	// 过期报告
+	assert.ErrorIs(t, fa.checkDirectLinks(b.id, 40*time.Millisecond, honest, now.Add(time.Hour)), ErrInvalidLinkState)
+}
+
+func TestRoutedStream_DetectsTruncation(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	left, right := net.Pipe()
+
+	sender, err := newRoutedStream(&pipeStream{pipe: left}, &routedConn{}, key, true, "")
+	require.NoError(t, err)
+	receiver, err := newRoutedStream(&pipeStream{pipe: right}, &routedConn{}, key, false, "")
+	require.NoError(t, err)
+
+	go func() {
+		sender.Write([]byte("partial"))
+		// 不发送 FIN 直接关闭底层流
+		left.Close()
+	}()
+
+	buf := make([]byte, 7)
+	_, err = io.ReadFull(receiver, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "partial", string(buf))
+
+	_, err = receiver.Read(buf)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,242 @@
+package routing
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/dep2p/go-dep2p/pkg/lib/crypto"
+)
+
+// ============================================================================
+//                              洋葱封装
+// ============================================================================
+//
+// 成员转发使用洋葱式分层封装：发起方为路径上每一跳各封装一层，
+// 每层只能由对应跳的身份私钥解开。中间节点解开本层后只知道
+// 上一跳和下一跳，最内层（路由头）只有目标成员能解开。
+//
+// 对路径 [self, h1, h2, target]：
+//
+//	L3 = Seal(target, {final: header})
+//	L2 = Seal(h2, {next: target, inner: L3})
+//	L1 = Seal(h1, {next: h2, inner: L2})
+
+const (
+	// forwardOpLinks 查询链路状态
+	forwardOpLinks = "links"
+
+	// forwardOpRelay 建立转发路径
+	forwardOpRelay = "relay"
+
+	// routeSigDomain 路由头签名域
+	routeSigDomain = "dep2p-route-v1"
+
+	// linkSigDomain 链路状态签名域
+	linkSigDomain = "dep2p-links-v1"
+
+	// maxForwardFrame 控制帧最大字节数
+	maxForwardFrame = 256 << 10
+
+	// maxClockSkew 路由头允许的时钟偏差
+	maxClockSkew = 2 * time.Minute
+
+	// maxLinkStates 单次交换的链路状态条目上限
+	maxLinkStates = 256
+
+	// linkRTTTolerance 抽查链路 RTT 时允许的偏差
+	//
+	// 成员声明的到本节点 RTT 低于本节点实测值的该比例时，视为虚报。
+	linkRTTTolerance = 0.5
+)
+
+// forwardRequest 转发协议请求
+type forwardRequest struct {
+	Op    string `json:"op"`
+	Onion []byte `json:"onion,omitempty"`
+
+	// Priority 流优先级（relay），每一跳以该优先级打开到下一跳的流
+	Priority int `json:"priority"`
+}
+
+// linksResponse 链路状态响应
+type linksResponse struct {
+	// Self 应答方自身的链路状态
+	Self *linkState `json:"self"`
+
+	// States 应答方已知的其他成员链路状态（原样转发各成员的签名报告）
+	States []*linkState `json:"states,omitempty"`
+}
+
+// linkState 单个成员签名的链路状态报告
+//
+// 报告由成员自身签名，转发方无法篡改其他成员的链路声明。
+type linkState struct {
+	PeerID string                   `json:"peer"`
+	Links  map[string]time.Duration `json:"links"`
+	Issued int64                    `json:"issued"`
+	Sig    []byte                   `json:"sig"`
+}
+
+// signingBytes 返回链路状态的待签名数据
+//
+// 链路按成员 ID 排序，保证签名与 JSON 字段顺序无关。
+func (st *linkState) signingBytes(realmID string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(linkSigDomain)
+	buf.WriteByte(0)
+	buf.WriteString(realmID)
+	buf.WriteByte(0)
+	buf.WriteString(st.PeerID)
+	var num [8]byte
+	binary.BigEndian.PutUint64(num[:], uint64(st.Issued))
+	buf.Write(num[:])
+
+	peers := make([]string, 0, len(st.Links))
+	for peer := range st.Links {
+		peers = append(peers, peer)
+	}
+	sort.Strings(peers)
+	for _, peer := range peers {
+		buf.WriteByte(0)
+		buf.WriteString(peer)
+		binary.BigEndian.PutUint64(num[:], uint64(st.Links[peer]))
+		buf.Write(num[:])
+	}
+	return buf.Bytes()
+}
+
+// onionLayer 单层洋葱
+type onionLayer struct {
+	// Next 下一跳（中间层）
+	Next string `json:"next,omitempty"`
+
+	// Inner 下一跳的封装数据（中间层）
+	Inner []byte `json:"inner,omitempty"`
+
+	// Final 路由头（最内层）
+	Final *routeHeader `json:"final,omitempty"`
+}
+
+// routeHeader 路由头，仅目标成员可见
+type routeHeader struct {
+	Origin    string `json:"origin"`
+	Target    string `json:"target"`
+	Protocol  string `json:"protocol"`
+	Key       []byte `json:"key"`
+	Timestamp int64  `json:"ts"`
+	Sig       []byte `json:"sig"`
+}
+
+// signingBytes 返回路由头的待签名数据
+//
+// 签名绑定 Realm、发起方、目标、协议和会话密钥，
+// 目标据此确认会话确实由发起方建立。
+func (h *routeHeader) signingBytes(realmID string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(routeSigDomain)
+	for _, s := range []string{realmID, h.Origin, h.Target, h.Protocol} {
+		buf.WriteByte(0)
+		buf.WriteString(s)
+	}
+	buf.WriteByte(0)
+	buf.Write(h.Key)
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], uint64(h.Timestamp))
+	buf.Write(ts[:])
+	return buf.Bytes()
+}
+
+// layerAAD 构造每层封装的附加认证数据（绑定 Realm 与本跳）
+func layerAAD(realmID, hop string) []byte {
+	return []byte(realmID + "/" + hop)
+}
+
+// buildOnion 为路径构造洋葱
+//
+// hops 不含发起方本身，最后一个元素为目标成员。
+func buildOnion(realmID string, hops []string, header *routeHeader, pubKey func(peerID string) ([]byte, error)) ([]byte, error) {
+	if len(hops) == 0 {
+		return nil, ErrInvalidPath
+	}
+
+	layer := &onionLayer{Final: header}
+	var sealed []byte
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := hops[i]
+		data, err := json.Marshal(layer)
+		if err != nil {
+			return nil, err
+		}
+		pub, err := pubKey(hop)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrNoHopKey, hop, err)
+		}
+		sealed, err = crypto.SealEd25519(pub, data, layerAAD(realmID, hop))
+		if err != nil {
+			return nil, err
+		}
+		layer = &onionLayer{Next: hop, Inner: sealed}
+	}
+	return sealed, nil
+}
+
+// peelOnion 解开本跳的洋葱层
+func peelOnion(realmID, self string, priv, onion []byte) (*onionLayer, error) {
+	data, err := crypto.OpenEd25519(priv, onion, layerAAD(realmID, self))
+	if err != nil {
+		return nil, err
+	}
+	var layer onionLayer
+	if err := json.Unmarshal(data, &layer); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidOnion, err)
+	}
+	if (layer.Final == nil) == (layer.Next == "") {
+		return nil, ErrInvalidOnion
+	}
+	if layer.Next != "" && len(layer.Inner) == 0 {
+		return nil, ErrInvalidOnion
+	}
+	return &layer, nil
+}
+
+// ============================================================================
+//                              控制帧
+// ============================================================================
+
+// writeControl 写入长度前缀的 JSON 控制帧
+func writeControl(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if len(data) > maxForwardFrame {
+		return ErrFrameTooLarge
+	}
+	buf := make([]byte, 4+len(data))
+	binary.BigEndian.PutUint32(buf, uint32(len(data)))
+	copy(buf[4:], data)
+	_, err = w.Write(buf)
+	return err
+}
+
+// readControl 读取长度前缀的 JSON 控制帧
+func readControl(r io.Reader, v interface{}) error {
+	var lenBuf [4]byte
+	if _, err := io.ReadFull(r, lenBuf[:]); err != nil {
+		return err
+	}
+	length := binary.BigEndian.Uint32(lenBuf[:])
+	if length > maxForwardFrame {
+		return ErrFrameTooLarge
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
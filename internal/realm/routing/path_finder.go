@@ -112,26 +112,20 @@ func (pf *PathFinder) dijkstra(source, target string) (*interfaces.Path, error)
 		}
 
 		// 遍历邻居节点
-		neighbors := pf.table.NearestPeers(currentID, 20)
-		for _, neighbor := range neighbors {
-			if visited[neighbor.PeerID] {
+		for _, e := range pf.edges(currentID) {
+			if visited[e.peerID] {
 				continue
 			}
 
 			// 计算新距离
-			edgeWeight := neighbor.Latency
-			if edgeWeight == 0 {
-				edgeWeight = 10 * time.Millisecond // 默认延迟
-			}
-
-			newDist := dist[currentID] + edgeWeight
+			newDist := dist[currentID] + e.weight
 
-			if newDist < dist[neighbor.PeerID] {
-				dist[neighbor.PeerID] = newDist
-				prev[neighbor.PeerID] = currentID
+			if d, ok := dist[e.peerID]; !ok || newDist < d {
+				dist[e.peerID] = newDist
+				prev[e.peerID] = currentID
 
 				heap.Push(&pq, &Item{
-					peerID:   neighbor.PeerID,
+					peerID:   e.peerID,
 					priority: int64(newDist),
 				})
 			}
@@ -330,27 +324,21 @@ func (pf *PathFinder) dijkstraWithRemovedEdges(source, target string, removedEdg
 			break
 		}
 
-		neighbors := pf.table.NearestPeers(currentID, 20)
-		for _, neighbor := range neighbors {
-			if visited[neighbor.PeerID] {
+		for _, e := range pf.edges(currentID) {
+			if visited[e.peerID] {
 				continue
 			}
 
 			// 检查边是否被移除
-			if removedEdges[currentID] != nil && removedEdges[currentID][neighbor.PeerID] {
+			if removedEdges[currentID] != nil && removedEdges[currentID][e.peerID] {
 				continue
 			}
 
-			edgeWeight := neighbor.Latency
-			if edgeWeight == 0 {
-				edgeWeight = 10 * time.Millisecond
-			}
-
-			newDist := dist[currentID] + edgeWeight
-			if newDist < dist[neighbor.PeerID] {
-				dist[neighbor.PeerID] = newDist
-				prev[neighbor.PeerID] = currentID
-				heap.Push(&pq, &Item{peerID: neighbor.PeerID, priority: int64(newDist)})
+			newDist := dist[currentID] + e.weight
+			if d, ok := dist[e.peerID]; !ok || newDist < d {
+				dist[e.peerID] = newDist
+				prev[e.peerID] = currentID
+				heap.Push(&pq, &Item{peerID: e.peerID, priority: int64(newDist)})
 			}
 		}
 	}
@@ -375,6 +363,46 @@ func (pf *PathFinder) dijkstraWithRemovedEdges(source, target string, removedEdg
 	}, nil
 }
 
+// edge 路径图中的一条边
+type edge struct {
+	peerID string
+	weight time.Duration
+}
+
+// defaultEdgeWeight 未知延迟时的默认边权重
+const defaultEdgeWeight = 10 * time.Millisecond
+
+// edges 返回节点的出边
+//
+// 节点带有链路状态（Links 非 nil）时使用显式链路；
+// 否则回退到 XOR 距离最近的可达节点，边权重为邻居的延迟。
+func (pf *PathFinder) edges(peerID string) []edge {
+	if node, err := pf.table.GetNode(peerID); err == nil && node.Links != nil {
+		out := make([]edge, 0, len(node.Links))
+		for id, rtt := range node.Links {
+			if id == peerID {
+				continue
+			}
+			if rtt <= 0 {
+				rtt = defaultEdgeWeight
+			}
+			out = append(out, edge{peerID: id, weight: rtt})
+		}
+		return out
+	}
+
+	neighbors := pf.table.NearestPeers(peerID, 20)
+	out := make([]edge, 0, len(neighbors))
+	for _, neighbor := range neighbors {
+		weight := neighbor.Latency
+		if weight == 0 {
+			weight = defaultEdgeWeight
+		}
+		out = append(out, edge{peerID: neighbor.PeerID, weight: weight})
+	}
+	return out
+}
+
 // pathToKey 将路径转换为字符串 key（用于去重）
 func pathToKey(nodes []string) string {
 	key := ""
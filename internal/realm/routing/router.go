@@ -105,7 +105,7 @@ func (r *Router) FindRoute(ctx context.Context, targetPeerID string) (*interface
 		route := &interfaces.Route{
 			TargetPeerID: targetPeerID,
 			NextHop:      targetPeerID,
-			Path:         []string{r.realmID, targetPeerID},
+			Path:         []string{r.source(), targetPeerID},
 			Latency:      node.Latency,
 			Hops:         1,
 			Score:        r.calculateScore(node.Latency, 1, 0),
@@ -120,7 +120,7 @@ func (r *Router) FindRoute(ctx context.Context, targetPeerID string) (*interface
 	}
 
 	// 3. 查找多跳路径
-	path, err := r.pathFinder.FindShortestPath(ctx, r.realmID, targetPeerID)
+	path, err := r.pathFinder.FindShortestPath(ctx, r.source(), targetPeerID)
 	if err != nil {
 		r.metrics.RecordFailure()
 		logger.Warn("查找路由失败", "targetPeerID", targetPeerID[:8], "error", err)
@@ -157,7 +157,7 @@ func (r *Router) FindRoutes(ctx context.Context, targetPeerID string, count int)
 	}
 
 	// 查找多条路径
-	paths, err := r.pathFinder.FindMultiplePaths(ctx, r.realmID, targetPeerID, count)
+	paths, err := r.pathFinder.FindMultiplePaths(ctx, r.source(), targetPeerID, count)
 	if err != nil {
 		return nil, err
 	}
@@ -220,6 +220,26 @@ func (r *Router) GetRouteTable() interfaces.RouteTable {
 	return r.table
 }
 
+// InvalidateAll 使所有缓存的路由和路径失效
+//
+// 链路状态变化后调用，保证后续查找基于最新拓扑。
+func (r *Router) InvalidateAll() {
+	r.cache.Clear()
+	for _, node := range r.table.GetAllNodes() {
+		r.pathFinder.InvalidatePath(node.PeerID)
+	}
+}
+
+// source 返回路径查找的起点
+//
+// 配置了本地节点 ID 时以本地节点为起点，否则沿用 RealmID。
+func (r *Router) source() string {
+	if r.config.LocalPeerID != "" {
+		return r.config.LocalPeerID
+	}
+	return r.realmID
+}
+
 // ============================================================================
 //                              路由选择策略
 // ============================================================================
@@ -601,8 +601,9 @@ func (m *mockHostForRouting) Listen(addrs ...string) error { return nil }
 func (m *mockHostForRouting) Connect(ctx context.Context, peerID string, addrs []string) error {
 	return nil
 }
-func (m *mockHostForRouting) SetStreamHandler(protocolID string, handler pkgif.StreamHandler) {}
-func (m *mockHostForRouting) RemoveStreamHandler(protocolID string)                           {}
+func (m *mockHostForRouting) SetStreamHandler(protocolID string, handler pkgif.StreamHandler, _ ...pkgif.StreamHandlerOption) {
+}
+func (m *mockHostForRouting) RemoveStreamHandler(protocolID string) {}
 func (m *mockHostForRouting) NewStream(ctx context.Context, peerID string, protocolIDs ...string) (pkgif.Stream, error) {
 	if m.stream != nil {
 		return m.stream, nil
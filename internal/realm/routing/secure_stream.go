@@ -0,0 +1,425 @@
+package routing
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/types"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
+)
+
+// ============================================================================
+//                              端到端加密流
+// ============================================================================
+//
+// 转发路径建立后，发起方与目标之间的所有数据以加密帧传输：
+//
+//	[4 字节长度][ChaCha20-Poly1305 密文(1 字节类型 + 数据)]
+//
+// 两个方向使用从会话密钥派生的独立密钥，nonce 为递增序号。
+// 写端关闭时先发送加密的 FIN 帧，未收到 FIN 即遇到 EOF 视为路径被截断。
+
+const (
+	// frameData 数据帧
+	frameData byte = 0
+
+	// frameFin 结束帧
+	frameFin byte = 1
+
+	// frameAccept 目标确认路径建立
+	frameAccept byte = 2
+
+	// maxSecureChunk 单帧最大明文字节数
+	maxSecureChunk = 16 << 10
+
+	// routeKeyInfoI2R 发起方→目标方向的密钥派生信息
+	routeKeyInfoI2R = "dep2p-route-v1 i2r"
+
+	// routeKeyInfoR2I 目标→发起方方向的密钥派生信息
+	routeKeyInfoR2I = "dep2p-route-v1 r2i"
+)
+
+// deriveStreamKeys 从会话密钥派生双向 AEAD
+func deriveStreamKeys(key []byte, initiator bool) (send, recv cipher.AEAD, err error) {
+	derive := func(info string) (cipher.AEAD, error) {
+		k := make([]byte, chacha20poly1305.KeySize)
+		if _, err := io.ReadFull(hkdf.New(sha256.New, key, nil, []byte(info)), k); err != nil {
+			return nil, err
+		}
+		return chacha20poly1305.New(k)
+	}
+
+	i2r, err := derive(routeKeyInfoI2R)
+	if err != nil {
+		return nil, nil, err
+	}
+	r2i, err := derive(routeKeyInfoR2I)
+	if err != nil {
+		return nil, nil, err
+	}
+	if initiator {
+		return i2r, r2i, nil
+	}
+	return r2i, i2r, nil
+}
+
+// routedStream 经成员转发的端到端加密流
+type routedStream struct {
+	raw  pkgif.Stream
+	conn *routedConn
+
+	send cipher.AEAD
+	recv cipher.AEAD
+
+	wmu     sync.Mutex
+	sendSeq uint64
+	wClosed bool
+
+	rmu     sync.Mutex
+	recvSeq uint64
+	rbuf    []byte
+	rDone   bool
+
+	protoMu  sync.RWMutex
+	protocol string
+
+	direction    types.Direction
+	opened       time.Time
+	bytesRead    atomic.Int64
+	bytesWritten atomic.Int64
+}
+
+var _ pkgif.Stream = (*routedStream)(nil)
+
+// newRoutedStream 在原始转发流上创建加密流
+func newRoutedStream(raw pkgif.Stream, conn *routedConn, key []byte, initiator bool, protocol string) (*routedStream, error) {
+	send, recv, err := deriveStreamKeys(key, initiator)
+	if err != nil {
+		return nil, err
+	}
+	dir := types.DirInbound
+	if initiator {
+		dir = types.DirOutbound
+	}
+	return &routedStream{
+		raw:       raw,
+		conn:      conn,
+		send:      send,
+		recv:      recv,
+		protocol:  protocol,
+		direction: dir,
+		opened:    time.Now(),
+	}, nil
+}
+
+// seqNonce 由序号构造 nonce
+func seqNonce(seq uint64) []byte {
+	nonce := make([]byte, chacha20poly1305.NonceSize)
+	binary.BigEndian.PutUint64(nonce[chacha20poly1305.NonceSize-8:], seq)
+	return nonce
+}
+
+// writeFrame 加密并写入一帧（调用方持有 wmu）
+func (s *routedStream) writeFrame(kind byte, data []byte) error {
+	plain := make([]byte, 0, 1+len(data))
+	plain = append(plain, kind)
+	plain = append(plain, data...)
+
+	out := make([]byte, 4, 4+len(plain)+s.send.Overhead())
+	out = s.send.Seal(out, seqNonce(s.sendSeq), plain, nil)
+	s.sendSeq++
+	binary.BigEndian.PutUint32(out[:4], uint32(len(out)-4))
+	_, err := s.raw.Write(out)
+	return err
+}
+
+// readFrame 读取并解密一帧（调用方持有 rmu）
+func (s *routedStream) readFrame() (byte, []byte, error) {
+	var lenBuf [4]byte
+	if _, err := io.ReadFull(s.raw, lenBuf[:]); err != nil {
+		if err == io.EOF {
+			// 未收到 FIN 即结束，路径被截断
+			return 0, nil, io.ErrUnexpectedEOF
+		}
+		return 0, nil, err
+	}
+	length := binary.BigEndian.Uint32(lenBuf[:])
+	if length > maxSecureChunk+1+uint32(s.recv.Overhead()) {
+		return 0, nil, ErrFrameTooLarge
+	}
+	ct := make([]byte, length)
+	if _, err := io.ReadFull(s.raw, ct); err != nil {
+		return 0, nil, err
+	}
+	plain, err := s.recv.Open(ct[:0], seqNonce(s.recvSeq), ct, nil)
+	if err != nil || len(plain) == 0 {
+		return 0, nil, ErrFrameAuth
+	}
+	s.recvSeq++
+	return plain[0], plain[1:], nil
+}
+
+// sendAccept 目标确认路径建立（在交给上层之前调用）
+func (s *routedStream) sendAccept() error {
+	s.wmu.Lock()
+	defer s.wmu.Unlock()
+	return s.writeFrame(frameAccept, nil)
+}
+
+// awaitAccept 发起方等待目标确认
+//
+// 确认帧经端到端密钥认证，收到即说明目标已验证路由头。
+func (s *routedStream) awaitAccept() error {
+	s.rmu.Lock()
+	defer s.rmu.Unlock()
+
+	kind, _, err := s.readFrame()
+	if err != nil {
+		return err
+	}
+	if kind != frameAccept {
+		return ErrFrameAuth
+	}
+	return nil
+}
+
+// Read 读取解密后的数据
+func (s *routedStream) Read(p []byte) (int, error) {
+	s.rmu.Lock()
+	defer s.rmu.Unlock()
+
+	for len(s.rbuf) == 0 {
+		if s.rDone {
+			return 0, io.EOF
+		}
+		kind, data, err := s.readFrame()
+		if err != nil {
+			return 0, err
+		}
+		switch kind {
+		case frameData:
+			s.rbuf = data
+		case frameFin:
+			s.rDone = true
+		default:
+			return 0, ErrFrameAuth
+		}
+	}
+
+	n := copy(p, s.rbuf)
+	s.rbuf = s.rbuf[n:]
+	s.bytesRead.Add(int64(n))
+	return n, nil
+}
+
+// Write 加密并写入数据
+func (s *routedStream) Write(p []byte) (int, error) {
+	s.wmu.Lock()
+	defer s.wmu.Unlock()
+
+	if s.wClosed {
+		return 0, io.ErrClosedPipe
+	}
+
+	written := 0
+	for written < len(p) {
+		end := written + maxSecureChunk
+		if end > len(p) {
+			end = len(p)
+		}
+		if err := s.writeFrame(frameData, p[written:end]); err != nil {
+			return written, err
+		}
+		written = end
+	}
+	s.bytesWritten.Add(int64(written))
+	return written, nil
+}
+
+// CloseWrite 发送 FIN 并关闭写端
+func (s *routedStream) CloseWrite() error {
+	s.wmu.Lock()
+	defer s.wmu.Unlock()
+
+	if s.wClosed {
+		return nil
+	}
+	s.wClosed = true
+	if err := s.writeFrame(frameFin, nil); err != nil {
+		return err
+	}
+	return s.raw.CloseWrite()
+}
+
+// CloseRead 关闭读端
+func (s *routedStream) CloseRead() error {
+	return s.raw.CloseRead()
+}
+
+// Close 关闭流
+func (s *routedStream) Close() error {
+	_ = s.CloseWrite()
+	return s.raw.Close()
+}
+
+// Reset 重置流
+func (s *routedStream) Reset() error {
+	return s.raw.Reset()
+}
+
+// SetDeadline 设置读写超时
+func (s *routedStream) SetDeadline(t time.Time) error {
+	return s.raw.SetDeadline(t)
+}
+
+// SetReadDeadline 设置读超时
+func (s *routedStream) SetReadDeadline(t time.Time) error {
+	return s.raw.SetReadDeadline(t)
+}
+
+// SetWriteDeadline 设置写超时
+func (s *routedStream) SetWriteDeadline(t time.Time) error {
+	return s.raw.SetWriteDeadline(t)
+}
+
+// Protocol 返回协议 ID
+func (s *routedStream) Protocol() string {
+	s.protoMu.RLock()
+	defer s.protoMu.RUnlock()
+	return s.protocol
+}
+
+// SetProtocol 设置协议 ID
+func (s *routedStream) SetProtocol(protocol string) {
+	s.protoMu.Lock()
+	defer s.protoMu.Unlock()
+	s.protocol = protocol
+}
+
+// Conn 返回虚拟连接（远端为路径另一端的成员）
+func (s *routedStream) Conn() pkgif.Connection {
+	return s.conn
+}
+
+// IsClosed 检查流是否已关闭
+func (s *routedStream) IsClosed() bool {
+	return s.raw.IsClosed()
+}
+
+// Stat 返回流统计信息
+func (s *routedStream) Stat() types.StreamStat {
+	return types.StreamStat{
+		Direction:    s.direction,
+		Opened:       s.opened,
+		Protocol:     types.ProtocolID(s.Protocol()),
+		BytesRead:    s.bytesRead.Load(),
+		BytesWritten: s.bytesWritten.Load(),
+	}
+}
+
+// State 返回流状态
+func (s *routedStream) State() types.StreamState {
+	return s.raw.State()
+}
+
+// ============================================================================
+//                              虚拟连接
+// ============================================================================
+
+// routedConn 转发路径对应的虚拟连接
+//
+// 仅用于向上层暴露端到端身份（RemotePeer 为路径另一端的成员），
+// 不支持在其上创建新流。
+type routedConn struct {
+	local  types.PeerID
+	remote types.PeerID
+	path   []string
+	opened time.Time
+	dir    pkgif.Direction
+
+	stream atomic.Pointer[routedStream]
+}
+
+var _ pkgif.Connection = (*routedConn)(nil)
+
+// LocalPeer 返回本地节点 ID
+func (c *routedConn) LocalPeer() types.PeerID { return c.local }
+
+// LocalMultiaddr 返回本地多地址（虚拟连接无地址）
+func (c *routedConn) LocalMultiaddr() types.Multiaddr { return nil }
+
+// RemotePeer 返回路径另一端的节点 ID
+func (c *routedConn) RemotePeer() types.PeerID { return c.remote }
+
+// RemoteMultiaddr 返回远端多地址（虚拟连接无地址）
+func (c *routedConn) RemoteMultiaddr() types.Multiaddr { return nil }
+
+// NewStream 虚拟连接不支持创建新流
+func (c *routedConn) NewStream(_ context.Context) (pkgif.Stream, error) {
+	return nil, ErrRoutedConnStream
+}
+
+// NewStreamWithPriority 虚拟连接不支持创建新流
+func (c *routedConn) NewStreamWithPriority(_ context.Context, _ int) (pkgif.Stream, error) {
+	return nil, ErrRoutedConnStream
+}
+
+// AcceptStream 虚拟连接不支持接受新流
+func (c *routedConn) AcceptStream() (pkgif.Stream, error) {
+	return nil, ErrRoutedConnStream
+}
+
+// GetStreams 返回虚拟连接上的流
+func (c *routedConn) GetStreams() []pkgif.Stream {
+	if s := c.stream.Load(); s != nil {
+		return []pkgif.Stream{s}
+	}
+	return nil
+}
+
+// Stat 返回连接统计
+func (c *routedConn) Stat() pkgif.ConnectionStat {
+	return pkgif.ConnectionStat{
+		Direction:  c.dir,
+		Opened:     c.opened.Unix(),
+		Transient:  true,
+		NumStreams: len(c.GetStreams()),
+	}
+}
+
+// ConnType 成员转发视为中继类连接
+func (c *routedConn) ConnType() pkgif.ConnectionType {
+	return pkgif.ConnectionTypeRelay
+}
+
+// SupportsStreamPriority 虚拟连接不支持流优先级
+func (c *routedConn) SupportsStreamPriority() bool { return false }
+
+// Close 关闭虚拟连接（关闭其上的流）
+func (c *routedConn) Close() error {
+	if s := c.stream.Load(); s != nil {
+		return s.Close()
+	}
+	return nil
+}
+
+// IsClosed 检查虚拟连接是否已关闭
+func (c *routedConn) IsClosed() bool {
+	if s := c.stream.Load(); s != nil {
+		return s.IsClosed()
+	}
+	return true
+}
+
+// Path 返回转发路径（含两端）
+func (c *routedConn) Path() []string {
+	return append([]string(nil), c.path...)
+}
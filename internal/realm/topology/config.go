@@ -0,0 +1,92 @@
+package topology
+
+import (
+	"fmt"
+	"time"
+)
+
+// Config 拓扑服务配置
+type Config struct {
+	// Interval 邻居摘要交换间隔
+	Interval time.Duration
+
+	// ReportTTL 摘要有效期，超过后不再参与拓扑组装
+	ReportTTL time.Duration
+
+	// MaxReports 最多保存的成员摘要数（含转述的摘要）
+	MaxReports int
+
+	// MaxNeighbors 单份摘要最多携带的邻居数
+	MaxNeighbors int
+
+	// RequestTimeout 单次交换超时
+	RequestTimeout time.Duration
+
+	// HotRelayShare 中继承载的电路占比达到该值时标记为热点中继
+	HotRelayShare float64
+
+	// HotRelayMinCircuits 热点中继的最少电路数（避免小样本误报）
+	HotRelayMinCircuits int
+}
+
+// DefaultConfig 返回默认配置
+func DefaultConfig() *Config {
+	return &Config{
+		Interval:            time.Minute,
+		ReportTTL:           5 * time.Minute,
+		MaxReports:          1000,
+		MaxNeighbors:        256,
+		RequestTimeout:      10 * time.Second,
+		HotRelayShare:       0.5,
+		HotRelayMinCircuits: 2,
+	}
+}
+
+// Validate 验证配置
+func (c *Config) Validate() error {
+	if c.Interval <= 0 {
+		return fmt.Errorf("%w: Interval must be positive", ErrInvalidConfig)
+	}
+	if c.ReportTTL < c.Interval {
+		return fmt.Errorf("%w: ReportTTL must not be less than Interval", ErrInvalidConfig)
+	}
+	if c.MaxReports <= 0 || c.MaxNeighbors <= 0 {
+		return fmt.Errorf("%w: MaxReports and MaxNeighbors must be positive", ErrInvalidConfig)
+	}
+	if c.HotRelayShare <= 0 || c.HotRelayShare > 1 {
+		return fmt.Errorf("%w: HotRelayShare must be in (0, 1]", ErrInvalidConfig)
+	}
+	return nil
+}
+
+// Option 配置选项函数
+type Option func(*Config)
+
+// WithInterval 设置摘要交换间隔
+func WithInterval(interval time.Duration) Option {
+	return func(c *Config) {
+		c.Interval = interval
+	}
+}
+
+// WithReportTTL 设置摘要有效期
+func WithReportTTL(ttl time.Duration) Option {
+	return func(c *Config) {
+		c.ReportTTL = ttl
+	}
+}
+
+// WithHotRelay 设置热点中继判定阈值
+func WithHotRelay(share float64, minCircuits int) Option {
+	return func(c *Config) {
+		c.HotRelayShare = share
+		c.HotRelayMinCircuits = minCircuits
+	}
+}
+
+// WithRequestTimeout 设置单次交换超时
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.RequestTimeout = timeout
+	}
+}
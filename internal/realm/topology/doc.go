@@ -0,0 +1,62 @@
+// Package topology 实现 Realm 连接拓扑的被动采集与导出
+//
+// # 模块概述
+//
+// 成员周期性地与已连接的成员交换邻居摘要（Report），摘要记录该成员当前
+// 连接了哪些 Realm 成员、连接类型（直连 / 中继）、经由的中继以及 RTT。
+// 摘要在 Realm 内逐跳扩散，任一启用拓扑的成员都能组装出全 Realm 的连接图。
+//
+// 服务只使用已有连接，不会为交换摘要主动建立连接，也不做主动探测。
+//
+// 协议 ID: /dep2p/realm/<realmID>/topology/1.0.0
+//
+// # 交换流程
+//
+//	发起方 ── exchange{Reports: [自己的摘要]} ──► 响应方
+//	发起方 ◄── exchange{Reports: [已知全部摘要]} ── 响应方
+//
+// 帧格式为 4 字节大端长度前缀 + JSON。响应方只接受发起方自己的摘要；
+// 二手摘要只接受 Realm 成员的、未过期（ReportTTL）且时间戳不超前的版本，
+// 同一成员保留最新的一份。
+//
+// # 签名
+//
+// 每份摘要由报告者的身份私钥签名，签名数据为域分隔编码
+// （"dep2p-realm-topology-report-v1" || realmID || peer || ts || 邻居列表）。
+// 合并前用 Peerstore 中报告者的公钥校验签名，未签名、伪造或转发途中被
+// 篡改的摘要一律丢弃。摘要原样保存和转发，以便下一跳同样能够校验。
+//
+// # 拓扑分析
+//
+// BuildGraph 将摘要组装为 Graph：
+//   - 中继作为独立节点（KindRelay）建模，中继连接拆为 成员—中继—成员 两段
+//   - Partitions：连通分量多于一个时列出各分区（未上报且孤立的成员不计入）
+//   - CutVertices：割点，即移除后会使 Realm 分裂的成员或中继（单点故障）
+//   - RelayLoads：各中继承载的电路数及占比，超过阈值标记为热点
+//   - Unreported：没有有效摘要的成员
+//
+// # 导出
+//
+//	data, _ := graph.JSON()   // JSON
+//	graph.WriteDOT(w)         // Graphviz DOT
+//
+// Introspect 服务通过 /debug/introspect/topology 暴露拓扑图，
+// ?format=dot 返回 DOT 格式。
+//
+// # 使用示例
+//
+//	svc, err := topology.NewService(host, realm,
+//	    topology.WithInterval(30*time.Second),
+//	    topology.WithHotRelay(0.5, 2),
+//	)
+//	if err != nil {
+//	    return err
+//	}
+//	svc.Start(ctx)
+//	defer svc.Stop(ctx)
+//
+//	graph := svc.Graph()
+//	for _, id := range graph.CutVertices {
+//	    fmt.Println("单点故障:", id)
+//	}
+package topology
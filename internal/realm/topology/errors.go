@@ -0,0 +1,36 @@
+package topology
+
+import "errors"
+
+// 错误定义
+var (
+	// ErrNilHost Host 为 nil
+	ErrNilHost = errors.New("topology: host is nil")
+
+	// ErrNilRealm Realm 为 nil
+	ErrNilRealm = errors.New("topology: realm is nil")
+
+	// ErrNotStarted 服务未启动
+	ErrNotStarted = errors.New("topology: service not started")
+
+	// ErrAlreadyStarted 服务已启动
+	ErrAlreadyStarted = errors.New("topology: service already started")
+
+	// ErrInvalidConfig 无效配置
+	ErrInvalidConfig = errors.New("topology: invalid config")
+
+	// ErrFrameTooLarge 帧过大
+	ErrFrameTooLarge = errors.New("topology: frame too large")
+
+	// ErrNotRealmMember 对端不是 Realm 成员
+	ErrNotRealmMember = errors.New("topology: peer is not realm member")
+
+	// ErrNoLocalKey 缺少本地私钥
+	ErrNoLocalKey = errors.New("topology: local private key unavailable")
+
+	// ErrNoMemberKey 缺少报告者公钥
+	ErrNoMemberKey = errors.New("topology: member public key unavailable")
+
+	// ErrInvalidReport 无效的邻居摘要（签名或邻居列表不合法）
+	ErrInvalidReport = errors.New("topology: invalid report")
+)
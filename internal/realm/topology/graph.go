@@ -0,0 +1,440 @@
+package topology
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dep2p/go-dep2p/pkg/lib/log"
+)
+
+// 节点类型
+const (
+	// KindMember Realm 成员
+	KindMember = "member"
+
+	// KindRelay 中继节点（由成员摘要中的中继连接推断）
+	KindRelay = "relay"
+)
+
+// Node 拓扑节点
+type Node struct {
+	// ID 节点 ID
+	ID string `json:"id"`
+
+	// Kind 节点类型：member / relay
+	Kind string `json:"kind"`
+
+	// Reported 是否有有效摘要（仅成员）
+	Reported bool `json:"reported"`
+
+	// ReportedAt 摘要生成时间
+	ReportedAt time.Time `json:"reported_at,omitempty"`
+}
+
+// Edge 拓扑边（无向，A < B）
+type Edge struct {
+	// A、B 两端成员
+	A string `json:"a"`
+	B string `json:"b"`
+
+	// Conn 连接类型：direct / relay
+	Conn string `json:"conn"`
+
+	// Relay 中继节点 ID（仅 relay 连接）
+	Relay string `json:"relay,omitempty"`
+
+	// RTT 两端报告的平均 RTT（0 表示未知）
+	RTT time.Duration `json:"rtt,omitempty"`
+
+	// Confirmed 两端均报告了该连接
+	Confirmed bool `json:"confirmed"`
+}
+
+// RelayLoad 中继承载的电路
+type RelayLoad struct {
+	// Relay 中继节点 ID
+	Relay string `json:"relay"`
+
+	// Circuits 经该中继连接的成员对数
+	Circuits int `json:"circuits"`
+
+	// Share 占全部中继电路的比例
+	Share float64 `json:"share"`
+
+	// Hot 是否为热点中继
+	Hot bool `json:"hot"`
+}
+
+// Graph Realm 拓扑图
+type Graph struct {
+	// RealmID Realm ID
+	RealmID string `json:"realm_id"`
+
+	// GeneratedAt 生成时间
+	GeneratedAt time.Time `json:"generated_at"`
+
+	// Nodes 节点（成员与推断出的中继）
+	Nodes []Node `json:"nodes"`
+
+	// Edges 成员间连接
+	Edges []Edge `json:"edges"`
+
+	// Partitions 成员分区（仅在多于一个分区时设置）
+	Partitions [][]string `json:"partitions,omitempty"`
+
+	// CutVertices 单点故障：移除后会使成员分区的节点（成员或中继）
+	CutVertices []string `json:"cut_vertices,omitempty"`
+
+	// RelayLoads 中继负载，按电路数降序
+	RelayLoads []RelayLoad `json:"relay_loads,omitempty"`
+
+	// Unreported 无摘要且无人报告与其连接的成员（未启用拓扑或状态未知）
+	Unreported []string `json:"unreported,omitempty"`
+}
+
+// BuildGraph 由成员摘要组装拓扑图
+//
+// members 为已知成员列表，reports 为有效摘要。只统计成员之间的连接；
+// 中继连接在连通性分析中视为 成员-中继-成员 两段，使中继可以被识别为单点故障。
+func BuildGraph(realmID string, members []string, reports []*Report, config *Config, now time.Time) *Graph {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	g := &Graph{RealmID: realmID, GeneratedAt: now}
+
+	memberSet := make(map[string]bool, len(members))
+	for _, m := range members {
+		if m != "" {
+			memberSet[m] = true
+		}
+	}
+	reported := make(map[string]*Report, len(reports))
+	for _, r := range reports {
+		if r == nil || r.Peer == "" {
+			continue
+		}
+		memberSet[r.Peer] = true
+		if cur, ok := reported[r.Peer]; !ok || cur.Timestamp < r.Timestamp {
+			reported[r.Peer] = r
+		}
+	}
+
+	// 合并两端的报告
+	type edgeAcc struct {
+		edge    Edge
+		rttSum  time.Duration
+		rttN    int
+		sources map[string]bool
+	}
+	edges := make(map[string]*edgeAcc)
+	for _, r := range reported {
+		for _, n := range r.Neighbors {
+			if n.Peer == r.Peer || !memberSet[n.Peer] {
+				continue
+			}
+			a, b := r.Peer, n.Peer
+			if b < a {
+				a, b = b, a
+			}
+			relay := ""
+			if n.Conn == ConnRelay {
+				relay = n.Relay
+			}
+			key := a + "|" + b + "|" + n.Conn + "|" + relay
+			acc, ok := edges[key]
+			if !ok {
+				acc = &edgeAcc{
+					edge:    Edge{A: a, B: b, Conn: n.Conn, Relay: relay},
+					sources: make(map[string]bool),
+				}
+				edges[key] = acc
+			}
+			acc.sources[r.Peer] = true
+			if n.RTT > 0 {
+				acc.rttSum += n.RTT
+				acc.rttN++
+			}
+		}
+	}
+
+	// 连通性邻接表
+	adj := make(map[string]map[string]bool)
+	link := func(a, b string) {
+		if adj[a] == nil {
+			adj[a] = make(map[string]bool)
+		}
+		if adj[b] == nil {
+			adj[b] = make(map[string]bool)
+		}
+		adj[a][b] = true
+		adj[b][a] = true
+	}
+
+	circuits := make(map[string]int)
+	relays := make(map[string]bool)
+	for _, acc := range edges {
+		e := acc.edge
+		if acc.rttN > 0 {
+			e.RTT = acc.rttSum / time.Duration(acc.rttN)
+		}
+		e.Confirmed = acc.sources[e.A] && acc.sources[e.B]
+		g.Edges = append(g.Edges, e)
+
+		if e.Conn == ConnRelay && e.Relay != "" && !memberSet[e.Relay] {
+			relays[e.Relay] = true
+			circuits[e.Relay]++
+			link(e.A, e.Relay)
+			link(e.Relay, e.B)
+		} else {
+			link(e.A, e.B)
+		}
+	}
+	sort.Slice(g.Edges, func(i, j int) bool {
+		x, y := g.Edges[i], g.Edges[j]
+		if x.A != y.A {
+			return x.A < y.A
+		}
+		if x.B != y.B {
+			return x.B < y.B
+		}
+		if x.Conn != y.Conn {
+			return x.Conn < y.Conn
+		}
+		return x.Relay < y.Relay
+	})
+
+	// 节点
+	for m := range memberSet {
+		node := Node{ID: m, Kind: KindMember}
+		if r, ok := reported[m]; ok {
+			node.Reported = true
+			node.ReportedAt = r.Time()
+		}
+		g.Nodes = append(g.Nodes, node)
+		if !node.Reported && len(adj[m]) == 0 {
+			g.Unreported = append(g.Unreported, m)
+		}
+	}
+	for r := range relays {
+		g.Nodes = append(g.Nodes, Node{ID: r, Kind: KindRelay})
+	}
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		if g.Nodes[i].Kind != g.Nodes[j].Kind {
+			return g.Nodes[i].Kind == KindMember
+		}
+		return g.Nodes[i].ID < g.Nodes[j].ID
+	})
+	sort.Strings(g.Unreported)
+
+	// 分区：忽略状态未知的孤立成员
+	unknown := make(map[string]bool, len(g.Unreported))
+	for _, m := range g.Unreported {
+		unknown[m] = true
+	}
+	var partitions [][]string
+	visited := make(map[string]bool)
+	for _, node := range g.Nodes {
+		if node.Kind != KindMember || visited[node.ID] || unknown[node.ID] {
+			continue
+		}
+		var part []string
+		stack := []string{node.ID}
+		visited[node.ID] = true
+		for len(stack) > 0 {
+			cur := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if memberSet[cur] {
+				part = append(part, cur)
+			}
+			for next := range adj[cur] {
+				if !visited[next] {
+					visited[next] = true
+					stack = append(stack, next)
+				}
+			}
+		}
+		sort.Strings(part)
+		partitions = append(partitions, part)
+	}
+	if len(partitions) > 1 {
+		sort.Slice(partitions, func(i, j int) bool {
+			if len(partitions[i]) != len(partitions[j]) {
+				return len(partitions[i]) > len(partitions[j])
+			}
+			return partitions[i][0] < partitions[j][0]
+		})
+		g.Partitions = partitions
+	}
+
+	g.CutVertices = cutVertices(adj)
+
+	// 中继负载
+	total := 0
+	for _, n := range circuits {
+		total += n
+	}
+	for relay, n := range circuits {
+		share := float64(n) / float64(total)
+		g.RelayLoads = append(g.RelayLoads, RelayLoad{
+			Relay:    relay,
+			Circuits: n,
+			Share:    share,
+			Hot:      n >= config.HotRelayMinCircuits && share >= config.HotRelayShare,
+		})
+	}
+	sort.Slice(g.RelayLoads, func(i, j int) bool {
+		if g.RelayLoads[i].Circuits != g.RelayLoads[j].Circuits {
+			return g.RelayLoads[i].Circuits > g.RelayLoads[j].Circuits
+		}
+		return g.RelayLoads[i].Relay < g.RelayLoads[j].Relay
+	})
+
+	return g
+}
+
+// cutVertices 计算无向图的割点（Tarjan 算法）
+func cutVertices(adj map[string]map[string]bool) []string {
+	ids := make([]string, 0, len(adj))
+	for id := range adj {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var (
+		timer int
+		disc  = make(map[string]int, len(ids))
+		low   = make(map[string]int, len(ids))
+		cuts  = make(map[string]bool)
+		visit func(u, parent string)
+	)
+	visit = func(u, parent string) {
+		timer++
+		disc[u], low[u] = timer, timer
+		children := 0
+		for v := range adj[u] {
+			if v == parent {
+				continue
+			}
+			if disc[v] != 0 {
+				if disc[v] < low[u] {
+					low[u] = disc[v]
+				}
+				continue
+			}
+			children++
+			visit(v, u)
+			if low[v] < low[u] {
+				low[u] = low[v]
+			}
+			if parent != "" && low[v] >= disc[u] {
+				cuts[u] = true
+			}
+		}
+		if parent == "" && children > 1 {
+			cuts[u] = true
+		}
+	}
+	for _, id := range ids {
+		if disc[id] == 0 {
+			visit(id, "")
+		}
+	}
+
+	out := make([]string, 0, len(cuts))
+	for id := range cuts {
+		out = append(out, id)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// ============================================================================
+//                              导出
+// ============================================================================
+
+// JSON 导出为 JSON
+func (g *Graph) JSON() ([]byte, error) {
+	return json.MarshalIndent(g, "", "  ")
+}
+
+// WriteDOT 导出为 GraphViz DOT
+//
+// 直连为实线，中继连接画成经中继节点（方框）的虚线；单点故障标红，
+// 热点中继填充橙色，无摘要的成员为灰色。
+func (g *Graph) WriteDOT(w io.Writer) error {
+	cuts := make(map[string]bool, len(g.CutVertices))
+	for _, id := range g.CutVertices {
+		cuts[id] = true
+	}
+	loads := make(map[string]RelayLoad, len(g.RelayLoads))
+	for _, l := range g.RelayLoads {
+		loads[l.Relay] = l
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "graph %s {\n", dotQuote("realm "+g.RealmID))
+	b.WriteString("  node [shape=ellipse];\n")
+
+	for _, n := range g.Nodes {
+		attrs := []string{"label=" + dotQuote(log.TruncateID(n.ID, 8))}
+		switch n.Kind {
+		case KindRelay:
+			l := loads[n.ID]
+			attrs[0] = "label=" + dotQuote(fmt.Sprintf("%s\\n%d circuits", log.TruncateID(n.ID, 8), l.Circuits))
+			attrs = append(attrs, "shape=box")
+			if l.Hot {
+				attrs = append(attrs, "style=filled", "fillcolor=orange")
+			}
+		case KindMember:
+			if !n.Reported {
+				attrs = append(attrs, "style=dashed", "fontcolor=gray")
+			}
+		}
+		if cuts[n.ID] {
+			attrs = append(attrs, "color=red", "penwidth=2")
+		}
+		fmt.Fprintf(&b, "  %s [%s];\n", dotQuote(n.ID), strings.Join(attrs, ", "))
+	}
+
+	// 中继段去重
+	drawn := make(map[string]bool)
+	for _, e := range g.Edges {
+		if e.Conn == ConnRelay && e.Relay != "" {
+			for _, end := range []string{e.A, e.B} {
+				key := end + "|" + e.Relay
+				if drawn[key] {
+					continue
+				}
+				drawn[key] = true
+				fmt.Fprintf(&b, "  %s -- %s [style=dashed];\n", dotQuote(end), dotQuote(e.Relay))
+			}
+			continue
+		}
+		attrs := []string{}
+		if e.RTT > 0 {
+			attrs = append(attrs, "label="+dotQuote(e.RTT.Round(time.Millisecond).String()))
+		}
+		if !e.Confirmed {
+			attrs = append(attrs, "color=gray")
+		}
+		suffix := ""
+		if len(attrs) > 0 {
+			suffix = " [" + strings.Join(attrs, ", ") + "]"
+		}
+		fmt.Fprintf(&b, "  %s -- %s%s;\n", dotQuote(e.A), dotQuote(e.B), suffix)
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// dotQuote 生成 DOT 字符串（保留 \n 等 DOT 转义）
+func dotQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
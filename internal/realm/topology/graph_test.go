@@ -0,0 +1,132 @@
+package topology
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// report 构造摘要
+func report(peer string, neighbors ...Neighbor) *Report {
+	return &Report{Peer: peer, Timestamp: time.Now().UnixNano(), Neighbors: neighbors}
+}
+
+func direct(peer string, rtt time.Duration) Neighbor {
+	return Neighbor{Peer: peer, Conn: ConnDirect, RTT: rtt}
+}
+
+func viaRelay(peer, relay string) Neighbor {
+	return Neighbor{Peer: peer, Conn: ConnRelay, Relay: relay}
+}
+
+// TestBuildGraph_Edges 测试两端报告合并
+func TestBuildGraph_Edges(t *testing.T) {
+	g := BuildGraph("realm", []string{"a", "b", "c"}, []*Report{
+		report("a", direct("b", 10*time.Millisecond), direct("c", 0)),
+		report("b", direct("a", 20*time.Millisecond)),
+	}, nil, time.Now())
+
+	require.Len(t, g.Edges, 2)
+	assert.Equal(t, Edge{A: "a", B: "b", Conn: ConnDirect, RTT: 15 * time.Millisecond, Confirmed: true}, g.Edges[0])
+	assert.Equal(t, Edge{A: "a", B: "c", Conn: ConnDirect, Confirmed: false}, g.Edges[1])
+
+	assert.Empty(t, g.Partitions)
+	assert.Empty(t, g.Unreported)
+	// a 连接 b 和 c，是唯一的桥接点
+	assert.Equal(t, []string{"a"}, g.CutVertices)
+}
+
+// TestBuildGraph_Partitions 测试分区检测
+func TestBuildGraph_Partitions(t *testing.T) {
+	g := BuildGraph("realm", []string{"a", "b", "c", "d", "e"}, []*Report{
+		report("a", direct("b", 0)),
+		report("c", direct("d", 0)),
+		report("d", direct("c", 0)),
+	}, nil, time.Now())
+
+	assert.Equal(t, [][]string{{"a", "b"}, {"c", "d"}}, g.Partitions)
+	// e 无摘要也无人报告与其连接，状态未知，不视为分区
+	assert.Equal(t, []string{"e"}, g.Unreported)
+}
+
+// TestBuildGraph_RelaySPOF 测试热点中继与中继单点故障
+func TestBuildGraph_RelaySPOF(t *testing.T) {
+	g := BuildGraph("realm", []string{"a", "b", "c", "d"}, []*Report{
+		report("a", direct("b", 0), viaRelay("c", "r1"), viaRelay("d", "r1")),
+		report("b", direct("a", 0)),
+		report("c", viaRelay("a", "r1")),
+		report("d", viaRelay("a", "r1"), viaRelay("c", "r2")),
+	}, nil, time.Now())
+
+	require.Len(t, g.RelayLoads, 2)
+	assert.Equal(t, "r1", g.RelayLoads[0].Relay)
+	assert.Equal(t, 2, g.RelayLoads[0].Circuits)
+	assert.InDelta(t, 2.0/3.0, g.RelayLoads[0].Share, 1e-9)
+	assert.True(t, g.RelayLoads[0].Hot)
+	assert.False(t, g.RelayLoads[1].Hot)
+
+	// 中继出现在节点中
+	var relays []string
+	for _, n := range g.Nodes {
+		if n.Kind == KindRelay {
+			relays = append(relays, n.ID)
+		}
+	}
+	assert.Equal(t, []string{"r1", "r2"}, relays)
+
+	// c、d 只能经 r1 到达 a、b，r1 与 a 都是单点故障
+	assert.Equal(t, []string{"a", "r1"}, g.CutVertices)
+	assert.Empty(t, g.Partitions)
+}
+
+// TestBuildGraph_IgnoresNonMembers 测试非成员邻居被忽略，最新摘要优先
+func TestBuildGraph_IgnoresNonMembers(t *testing.T) {
+	old := report("a", direct("b", 0))
+	old.Timestamp -= int64(time.Minute)
+	g := BuildGraph("realm", []string{"a", "b"}, []*Report{
+		old,
+		report("a", direct("x", 0)),
+	}, nil, time.Now())
+
+	assert.Empty(t, g.Edges)
+	assert.Len(t, g.Nodes, 2)
+}
+
+// TestGraph_Export 测试 JSON 与 DOT 导出
+func TestGraph_Export(t *testing.T) {
+	g := BuildGraph("realm", []string{"a", "b", "c"}, []*Report{
+		report("a", direct("b", 12*time.Millisecond), viaRelay("c", "r1")),
+		report("b", direct("a", 12*time.Millisecond)),
+	}, nil, time.Now())
+
+	data, err := g.JSON()
+	require.NoError(t, err)
+	var decoded Graph
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, g.Edges, decoded.Edges)
+	assert.Equal(t, g.CutVertices, decoded.CutVertices)
+
+	var buf bytes.Buffer
+	require.NoError(t, g.WriteDOT(&buf))
+	dot := buf.String()
+	assert.True(t, strings.HasPrefix(dot, `graph "realm realm" {`))
+	assert.Contains(t, dot, `"a" -- "b" [label="12ms"];`)
+	assert.Contains(t, dot, `"a" -- "r1" [style=dashed];`)
+	assert.Contains(t, dot, `"c" -- "r1" [style=dashed];`)
+	assert.Contains(t, dot, `shape=box`)
+	assert.Contains(t, dot, `color=red`)
+	assert.True(t, strings.HasSuffix(dot, "}\n"))
+}
+
+// TestRelayFromAddr 测试从中继地址提取中继节点
+func TestRelayFromAddr(t *testing.T) {
+	assert.Equal(t, "QmRelay", relayFromAddr("/ip4/1.2.3.4/udp/4001/quic-v1/p2p/QmRelay/p2p-circuit/p2p/QmRemote"))
+	assert.Equal(t, "QmRelay", relayFromAddr("/p2p/QmRelay/p2p-circuit"))
+	assert.Empty(t, relayFromAddr("/ip4/1.2.3.4/tcp/4001/p2p/QmRemote"))
+	assert.Empty(t, relayFromAddr("/p2p-circuit/p2p/QmRemote"))
+}
@@ -0,0 +1,141 @@
+package topology
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+)
+
+// 连接类型
+const (
+	// ConnDirect 直连
+	ConnDirect = "direct"
+
+	// ConnRelay 经中继连接
+	ConnRelay = "relay"
+)
+
+// reportSigDomain 邻居摘要签名域
+const reportSigDomain = "dep2p-realm-topology-report-v1"
+
+// maxFrameSize 单帧最大字节数
+const maxFrameSize = 4 << 20
+
+// Neighbor 邻居摘要
+type Neighbor struct {
+	// Peer 邻居节点 ID
+	Peer string `json:"peer"`
+
+	// Conn 连接类型：direct / relay（同时存在时为 direct）
+	Conn string `json:"conn"`
+
+	// Relay 中继节点 ID（仅 relay 连接）
+	Relay string `json:"relay,omitempty"`
+
+	// RTT 往返时延（0 表示未知）
+	RTT time.Duration `json:"rtt,omitempty"`
+}
+
+// Report 成员的邻居摘要
+type Report struct {
+	// Peer 报告者节点 ID
+	Peer string `json:"peer"`
+
+	// Timestamp 生成时间（Unix 纳秒）
+	Timestamp int64 `json:"ts"`
+
+	// Neighbors 当前连接的 Realm 成员
+	Neighbors []Neighbor `json:"neighbors"`
+
+	// Sig 报告者身份私钥对 signingBytes 的签名
+	Sig []byte `json:"sig,omitempty"`
+}
+
+// Time 返回摘要生成时间
+func (r *Report) Time() time.Time {
+	return time.Unix(0, r.Timestamp)
+}
+
+// signingBytes 返回摘要的待签名数据
+//
+// 签名绑定 Realm、报告者、时间戳和完整的邻居列表，
+// 摘要经其他成员转发时任何改动都会使签名失效。
+func (r *Report) signingBytes(realmID string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(reportSigDomain)
+	buf.WriteByte(0)
+	buf.WriteString(realmID)
+	buf.WriteByte(0)
+	buf.WriteString(r.Peer)
+	var num [8]byte
+	binary.BigEndian.PutUint64(num[:], uint64(r.Timestamp))
+	buf.Write(num[:])
+
+	for _, n := range r.Neighbors {
+		for _, s := range []string{n.Peer, n.Conn, n.Relay} {
+			buf.WriteByte(0)
+			buf.WriteString(s)
+		}
+		binary.BigEndian.PutUint64(num[:], uint64(n.RTT))
+		buf.Write(num[:])
+	}
+	return buf.Bytes()
+}
+
+// exchange 交换帧：发起方携带自己的摘要，响应方返回已知的全部摘要
+type exchange struct {
+	// Reports 摘要列表（请求中只有发起方自己的摘要）
+	Reports []*Report `json:"reports"`
+}
+
+// writeFrame 写入长度前缀的 JSON 帧
+func writeFrame(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if len(data) > maxFrameSize {
+		return ErrFrameTooLarge
+	}
+	buf := make([]byte, 4+len(data))
+	binary.BigEndian.PutUint32(buf, uint32(len(data)))
+	copy(buf[4:], data)
+	_, err = w.Write(buf)
+	return err
+}
+
+// readFrame 读取长度前缀的 JSON 帧
+func readFrame(r io.Reader, v interface{}) error {
+	var lenBuf [4]byte
+	if _, err := io.ReadFull(r, lenBuf[:]); err != nil {
+		return err
+	}
+	length := binary.BigEndian.Uint32(lenBuf[:])
+	if length > maxFrameSize {
+		return ErrFrameTooLarge
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// relayFromAddr 从中继地址中提取中继节点 ID
+//
+// 地址格式：<transport>/p2p/<relay>/p2p-circuit/p2p/<remote>
+func relayFromAddr(addr string) string {
+	idx := strings.Index(addr, "/p2p-circuit")
+	if idx < 0 {
+		return ""
+	}
+	head := addr[:idx]
+	p := strings.LastIndex(head, "/p2p/")
+	if p < 0 {
+		return ""
+	}
+	return head[p+len("/p2p/"):]
+}
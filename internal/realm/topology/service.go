@@ -0,0 +1,392 @@
+package topology
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/log"
+	"github.com/dep2p/go-dep2p/pkg/protocol"
+	"github.com/dep2p/go-dep2p/pkg/types"
+)
+
+var logger = log.Logger("realm/topology")
+
+// maxClockSkew 允许的摘要时间戳超前量
+const maxClockSkew = time.Minute
+
+// Service Realm 拓扑服务
+//
+// 成员周期性地与已连接的成员交换邻居摘要：发起方发送自己的摘要，
+// 响应方返回它已知的全部有效摘要。摘要因此在 Realm 内逐跳扩散，
+// 任一启用拓扑的成员都能组装出全 Realm 的连接图。
+//
+// 服务只使用已有连接，不会为交换摘要主动建立连接。
+//
+// 协议 ID: /dep2p/realm/<realmID>/topology/1.0.0
+type Service struct {
+	host       pkgif.Host
+	realm      pkgif.Realm
+	config     *Config
+	protocolID string
+
+	mu      sync.RWMutex
+	reports map[string]*Report
+
+	started atomic.Bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewService 创建拓扑服务
+func NewService(host pkgif.Host, realm pkgif.Realm, opts ...Option) (*Service, error) {
+	if host == nil {
+		return nil, ErrNilHost
+	}
+	if realm == nil {
+		return nil, ErrNilRealm
+	}
+
+	config := DefaultConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		host:       host,
+		realm:      realm,
+		config:     config,
+		protocolID: string(protocol.NewRealmBuilder(realm.ID()).Topology()),
+		reports:    make(map[string]*Report),
+	}, nil
+}
+
+// ============================================================================
+//                              生命周期
+// ============================================================================
+
+// Start 启动服务
+func (s *Service) Start(_ context.Context) error {
+	if !s.started.CompareAndSwap(false, true) {
+		return ErrAlreadyStarted
+	}
+
+	// 使用 context.Background()，Fx OnStart 的 ctx 在返回后会被取消
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.host.SetStreamHandler(s.protocolID, s.handleStream)
+
+	s.wg.Add(1)
+	go s.exchangeLoop()
+
+	logger.Debug("拓扑服务已启动", "realmID", s.realm.ID())
+	return nil
+}
+
+// Stop 停止服务
+func (s *Service) Stop(_ context.Context) error {
+	if !s.started.CompareAndSwap(true, false) {
+		return ErrNotStarted
+	}
+
+	s.host.RemoveStreamHandler(s.protocolID)
+	s.cancel()
+	s.wg.Wait()
+	return nil
+}
+
+// exchangeLoop 摘要交换循环
+func (s *Service) exchangeLoop() {
+	defer s.wg.Done()
+
+	// 启动后尽快完成首次交换
+	timer := time.NewTimer(time.Second)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			s.Refresh(s.ctx)
+			timer.Reset(s.config.Interval)
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// ============================================================================
+//                              摘要
+// ============================================================================
+
+// LocalReport 生成本节点的邻居摘要
+//
+// 只包含当前已连接的 Realm 成员。同时存在直连和中继连接时记为直连。
+// 摘要由本节点身份私钥签名；缺少私钥时返回未签名摘要，其他成员会拒绝它。
+func (s *Service) LocalReport() *Report {
+	report := s.neighbors()
+	if err := s.signReport(report); err != nil {
+		logger.Debug("签名拓扑摘要失败", "error", err)
+	}
+	return report
+}
+
+// neighbors 收集本节点的邻居摘要（未签名）
+func (s *Service) neighbors() *Report {
+	report := &Report{Peer: s.host.ID(), Timestamp: time.Now().UnixNano()}
+
+	network := s.host.Network()
+	if network == nil {
+		return report
+	}
+
+	self := s.host.ID()
+	liveness := s.realm.Liveness()
+	for _, member := range s.realm.Members() {
+		if member == self {
+			continue
+		}
+		conns := network.ConnsToPeer(member)
+		if len(conns) == 0 {
+			continue
+		}
+
+		n := Neighbor{Peer: member}
+		for _, conn := range conns {
+			if conn == nil || conn.IsClosed() {
+				continue
+			}
+			if conn.ConnType().IsDirect() {
+				n.Conn, n.Relay = ConnDirect, ""
+				break
+			}
+			if n.Conn == "" {
+				n.Conn = ConnRelay
+				if addr := conn.RemoteMultiaddr(); addr != nil {
+					n.Relay = relayFromAddr(addr.String())
+				}
+			}
+		}
+		if n.Conn == "" {
+			continue
+		}
+
+		if liveness != nil {
+			status := liveness.GetStatus(member)
+			n.RTT = status.AvgRTT
+			if n.RTT <= 0 {
+				n.RTT = status.LastRTT
+			}
+		}
+		report.Neighbors = append(report.Neighbors, n)
+	}
+
+	sort.Slice(report.Neighbors, func(i, j int) bool {
+		return report.Neighbors[i].Peer < report.Neighbors[j].Peer
+	})
+	if len(report.Neighbors) > s.config.MaxNeighbors {
+		report.Neighbors = report.Neighbors[:s.config.MaxNeighbors]
+	}
+	return report
+}
+
+// signReport 用本节点身份私钥为摘要签名
+func (s *Service) signReport(r *Report) error {
+	ps := s.host.Peerstore()
+	if ps == nil {
+		return ErrNoLocalKey
+	}
+	priv, err := ps.PrivKey(types.PeerID(s.host.ID()))
+	if err != nil || priv == nil {
+		return ErrNoLocalKey
+	}
+	r.Sig, err = priv.Sign(r.signingBytes(s.realm.ID()))
+	return err
+}
+
+// verifyReport 校验摘要的邻居列表及报告者签名
+func (s *Service) verifyReport(r *Report) error {
+	for _, n := range r.Neighbors {
+		if n.Peer == "" || n.Peer == r.Peer || (n.Conn != ConnDirect && n.Conn != ConnRelay) {
+			return fmt.Errorf("%w: bad neighbor", ErrInvalidReport)
+		}
+	}
+	if len(r.Sig) == 0 {
+		return fmt.Errorf("%w: missing signature", ErrInvalidReport)
+	}
+
+	ps := s.host.Peerstore()
+	if ps == nil {
+		return ErrNoMemberKey
+	}
+	pub, err := ps.PubKey(types.PeerID(r.Peer))
+	if err != nil || pub == nil {
+		return ErrNoMemberKey
+	}
+	ok, err := pub.Verify(r.signingBytes(s.realm.ID()), r.Sig)
+	if err != nil || !ok {
+		return fmt.Errorf("%w: bad signature", ErrInvalidReport)
+	}
+	return nil
+}
+
+// Reports 返回本节点摘要及所有有效的成员摘要
+func (s *Service) Reports() []*Report {
+	reports := []*Report{s.LocalReport()}
+
+	cutoff := time.Now().Add(-s.config.ReportTTL).UnixNano()
+	s.mu.RLock()
+	for _, r := range s.reports {
+		if r.Timestamp >= cutoff {
+			reports = append(reports, r)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Peer < reports[j].Peer })
+	return reports
+}
+
+// Graph 组装当前 Realm 拓扑图
+func (s *Service) Graph() *Graph {
+	members := append([]string{s.host.ID()}, s.realm.Members()...)
+	return BuildGraph(s.realm.ID(), members, s.Reports(), s.config, time.Now())
+}
+
+// Refresh 与已连接的成员交换摘要
+func (s *Service) Refresh(ctx context.Context) {
+	own := s.LocalReport()
+
+	var wg sync.WaitGroup
+	for _, n := range own.Neighbors {
+		wg.Add(1)
+		go func(peer string) {
+			defer wg.Done()
+			reports, err := s.exchange(ctx, peer, own)
+			if err != nil {
+				logger.Debug("交换拓扑摘要失败", "peer", log.TruncateID(peer, 8), "error", err)
+				return
+			}
+			s.merge(peer, reports)
+		}(n.Peer)
+	}
+	wg.Wait()
+
+	s.prune()
+}
+
+// exchange 与成员交换一次摘要
+func (s *Service) exchange(ctx context.Context, peer string, own *Report) ([]*Report, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.config.RequestTimeout)
+	defer cancel()
+
+	stream, err := s.host.NewStream(ctx, peer, s.protocolID)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = stream.SetDeadline(deadline)
+	}
+	if err := writeFrame(stream, &exchange{Reports: []*Report{own}}); err != nil {
+		return nil, err
+	}
+
+	var resp exchange
+	if err := readFrame(stream, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Reports, nil
+}
+
+// handleStream 处理摘要交换请求
+func (s *Service) handleStream(stream pkgif.Stream) {
+	defer stream.Close()
+
+	remote := ""
+	if conn := stream.Conn(); conn != nil {
+		remote = string(conn.RemotePeer())
+	}
+	if remote == "" || !s.realm.IsMember(remote) {
+		logger.Debug("拒绝非成员的拓扑请求", "peer", log.TruncateID(remote, 8))
+		_ = stream.Reset()
+		return
+	}
+
+	_ = stream.SetDeadline(time.Now().Add(s.config.RequestTimeout))
+
+	var req exchange
+	if err := readFrame(stream, &req); err != nil {
+		logger.Debug("读取拓扑请求失败", "peer", log.TruncateID(remote, 8), "error", err)
+		return
+	}
+
+	// 请求中只接受发起方自己的摘要
+	for _, r := range req.Reports {
+		if r != nil && r.Peer == remote {
+			s.merge(remote, []*Report{r})
+		}
+	}
+
+	if err := writeFrame(stream, &exchange{Reports: s.Reports()}); err != nil {
+		logger.Debug("发送拓扑摘要失败", "peer", log.TruncateID(remote, 8), "error", err)
+	}
+}
+
+// merge 合并从 from 收到的摘要
+//
+// 只接受 Realm 成员签名有效的摘要，同一成员保留时间戳最新的一份。
+// 摘要原样保存以便继续转发（签名覆盖完整邻居列表），
+// 邻居中的非成员在组装拓扑图时被忽略。
+func (s *Service) merge(from string, reports []*Report) {
+	self := s.host.ID()
+	now := time.Now()
+	cutoff := now.Add(-s.config.ReportTTL).UnixNano()
+	future := now.Add(maxClockSkew).UnixNano()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, r := range reports {
+		if r == nil || r.Peer == "" || r.Peer == self || !s.realm.IsMember(r.Peer) {
+			continue
+		}
+		if r.Timestamp < cutoff || r.Timestamp > future || len(r.Neighbors) > s.config.MaxNeighbors {
+			continue
+		}
+		cur, ok := s.reports[r.Peer]
+		if ok && cur.Timestamp >= r.Timestamp {
+			continue
+		}
+		if !ok && len(s.reports) >= s.config.MaxReports {
+			continue
+		}
+		if err := s.verifyReport(r); err != nil {
+			logger.Debug("丢弃无效的拓扑摘要", "peer", log.TruncateID(r.Peer, 8), "from", log.TruncateID(from, 8), "error", err)
+			continue
+		}
+		s.reports[r.Peer] = r
+	}
+
+	logger.Debug("已合并拓扑摘要", "from", log.TruncateID(from, 8), "count", len(reports))
+}
+
+// prune 清理过期摘要和已离开成员的摘要
+func (s *Service) prune() {
+	cutoff := time.Now().Add(-s.config.ReportTTL).UnixNano()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for peer, r := range s.reports {
+		if r.Timestamp < cutoff || !s.realm.IsMember(peer) {
+			delete(s.reports, peer)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package topology
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dep2p/go-dep2p/internal/core/identity"
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/types"
+	"github.com/dep2p/go-dep2p/tests/mocks"
+)
+
+// topoNode 测试节点
+type topoNode struct {
+	host  *mocks.MockHost
+	svc   *Service
+	links map[string]pkgif.Connection
+}
+
+// newTopoNet 在内存网络上创建一组同一 Realm 的拓扑服务
+func newTopoNet(t *testing.T, ids ...string) map[string]*topoNode {
+	t.Helper()
+
+	// 每个成员持有自己的私钥和其他成员的公钥（模拟成员同步）
+	keys := make(map[string]pkgif.PrivateKey, len(ids))
+	for _, id := range ids {
+		ident, err := identity.Generate()
+		require.NoError(t, err)
+		keys[id] = ident.PrivateKey()
+	}
+
+	pn := mocks.NewPipeNet()
+	nodes := make(map[string]*topoNode, len(ids))
+	for _, id := range ids {
+		ps := mocks.NewMockPeerstore()
+		for peer, key := range keys {
+			if peer == id {
+				require.NoError(t, ps.AddPrivKey(types.PeerID(peer), key))
+			} else {
+				require.NoError(t, ps.AddPubKey(types.PeerID(peer), key.PublicKey()))
+			}
+		}
+
+		node := &topoNode{host: pn.AddHost(id), links: make(map[string]pkgif.Connection)}
+		node.host.PeerstoreFunc = func() pkgif.Peerstore { return ps }
+		node.host.Network().(*mocks.MockSwarm).ConnsToPeerFunc = func(peer string) []pkgif.Connection {
+			if conn, ok := node.links[peer]; ok {
+				return []pkgif.Connection{conn}
+			}
+			return nil
+		}
+
+		realm := mocks.NewMockRealm("realm-1")
+		for _, member := range ids {
+			if member != id {
+				realm.AddMember(member)
+			}
+		}
+
+		svc, err := NewService(node.host, realm)
+		require.NoError(t, err)
+		node.host.SetStreamHandler(svc.protocolID, svc.handleStream)
+		node.svc = svc
+		nodes[id] = node
+	}
+	return nodes
+}
+
+// link 在两个节点之间建立连接；relay 非空时为中继连接
+func link(nodes map[string]*topoNode, a, b, relay string) {
+	for _, pair := range [][2]string{{a, b}, {b, a}} {
+		conn := mocks.NewMockConnection(types.PeerID(pair[0]), types.PeerID(pair[1]))
+		if relay != "" {
+			conn.ConnTypeValue = pkgif.ConnectionTypeRelay
+			conn.RemoteAddr, _ = types.NewMultiaddr("/ip4/1.2.3.4/tcp/4001/p2p/" + relay + "/p2p-circuit/p2p/" + pair[1])
+		}
+		nodes[pair[0]].links[pair[1]] = conn
+	}
+}
+
+// signed 用节点 peer 的私钥为摘要签名
+func signed(t *testing.T, nodes map[string]*topoNode, r *Report) *Report {
+	t.Helper()
+	require.NoError(t, nodes[r.Peer].svc.signReport(r))
+	return r
+}
+
+// TestService_LocalReport 测试邻居摘要生成
+func TestService_LocalReport(t *testing.T) {
+	nodes := newTopoNet(t, "a", "b", "c", "d")
+	link(nodes, "a", "c", "")
+	link(nodes, "a", "b", "relay-1")
+
+	r := nodes["a"].svc.LocalReport()
+	assert.Equal(t, "a", r.Peer)
+	assert.Equal(t, []Neighbor{
+		{Peer: "b", Conn: ConnRelay, Relay: "relay-1"},
+		{Peer: "c", Conn: ConnDirect},
+	}, r.Neighbors)
+}
+
+// TestService_Gossip 测试摘要逐跳扩散
+func TestService_Gossip(t *testing.T) {
+	nodes := newTopoNet(t, "a", "b", "c")
+	link(nodes, "a", "b", "")
+	link(nodes, "b", "c", "")
+
+	ctx := context.Background()
+	// c 先把摘要交给 b，a 再从 b 处取回全部摘要
+	nodes["c"].svc.Refresh(ctx)
+	nodes["a"].svc.Refresh(ctx)
+
+	g := nodes["a"].svc.Graph()
+	assert.Empty(t, g.Unreported)
+	assert.Empty(t, g.Partitions)
+	assert.Equal(t, []string{"b"}, g.CutVertices)
+	require.Len(t, g.Edges, 2)
+	for _, e := range g.Edges {
+		assert.True(t, e.Confirmed, "%s-%s", e.A, e.B)
+	}
+}
+
+// TestService_Merge 测试摘要合并规则
+func TestService_Merge(t *testing.T) {
+	nodes := newTopoNet(t, "a", "b", "c")
+	svc := nodes["a"].svc
+
+	stale := report("b", direct("a", 0))
+	stale.Timestamp = time.Now().Add(-2 * svc.config.ReportTTL).UnixNano()
+	future := report("c", direct("a", 0))
+	future.Timestamp = time.Now().Add(2 * maxClockSkew).UnixNano()
+	svc.merge("b", []*Report{
+		signed(t, nodes, stale),
+		signed(t, nodes, future),
+		report("x", direct("a", 0)),
+		signed(t, nodes, report("a", direct("b", 0))),
+	})
+	assert.Empty(t, svc.reports, "过期、超前、非成员和本节点的摘要都应被丢弃")
+
+	older := signed(t, nodes, report("b", direct("a", 0)))
+	older.Timestamp -= int64(time.Second)
+	newer := signed(t, nodes, report("b", direct("a", 0), direct("c", 0), direct("x", 0)))
+	svc.merge("b", []*Report{newer, older})
+
+	require.Contains(t, svc.reports, "b")
+	assert.Same(t, newer, svc.reports["b"], "摘要应原样保存以便继续转发")
+
+	for _, e := range svc.Graph().Edges {
+		assert.NotEqual(t, "x", e.B, "非成员邻居不应出现在拓扑图中")
+	}
+}
+
+// TestService_MergeRejectsForgedReports 测试拒绝未签名、伪造和被篡改的摘要
+func TestService_MergeRejectsForgedReports(t *testing.T) {
+	nodes := newTopoNet(t, "a", "b", "c")
+	svc := nodes["a"].svc
+
+	unsigned := report("b", direct("c", 0))
+
+	// c 冒充 b 签名
+	forged := report("b", direct("c", 0))
+	require.NoError(t, nodes["c"].svc.signReport(forged))
+	forged.Peer = "b"
+
+	// 转发途中被篡改的邻居列表
+	tampered := signed(t, nodes, report("b", direct("c", 0)))
+	tampered.Neighbors = append(tampered.Neighbors, viaRelay("a", "c"))
+
+	// 签名有效但邻居不合法
+	selfLoop := signed(t, nodes, report("b", direct("b", 0)))
+
+	svc.merge("c", []*Report{unsigned, forged, tampered, selfLoop})
+	assert.Empty(t, svc.reports)
+
+	valid := signed(t, nodes, report("b", direct("c", 0)))
+	svc.merge("c", []*Report{valid})
+	assert.Contains(t, svc.reports, "b")
+}
+
+// TestService_ForwardedReportsVerify 测试逐跳转发的摘要在第三方仍能通过校验
+func TestService_ForwardedReportsVerify(t *testing.T) {
+	nodes := newTopoNet(t, "a", "b", "c")
+	link(nodes, "a", "b", "")
+	link(nodes, "b", "c", "")
+
+	ctx := context.Background()
+	nodes["c"].svc.Refresh(ctx)
+	nodes["a"].svc.Refresh(ctx)
+
+	require.Contains(t, nodes["a"].svc.reports, "c")
+	assert.NoError(t, nodes["a"].svc.verifyReport(nodes["a"].svc.reports["c"]))
+}
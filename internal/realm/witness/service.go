@@ -49,6 +49,9 @@ const (
 
 	// WitnessProcessedCacheExpiry 已处理报告缓存过期时间
 	WitnessProcessedCacheExpiry = 60 * time.Second
+
+	// witnessPenalty 投票结果对应的信誉信号增量
+	witnessPenalty = -0.3
 )
 
 // ============================================================================
@@ -88,10 +91,11 @@ type Service struct {
 	realmID string
 
 	// 依赖注入
-	member    MemberManager
-	topic     TopicPublisher
-	peerstore pkgif.Peerstore
-	host      pkgif.Host
+	member     MemberManager
+	topic      TopicPublisher
+	peerstore  pkgif.Peerstore
+	host       pkgif.Host
+	reputation pkgif.Reputation
 
 	// 报告状态跟踪
 	pendingReports   map[string]*PendingReport // report_id -> PendingReport
@@ -151,6 +155,15 @@ func (s *Service) SetHost(host pkgif.Host) {
 	s.host = host
 }
 
+// SetReputation 设置信誉服务
+//
+// 投票确认断开时降低目标的信誉，报告被否决时降低报告者的信誉。
+func (s *Service) SetReputation(rep pkgif.Reputation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reputation = rep
+}
+
 // Start 启动服务
 func (s *Service) Start(ctx context.Context) error {
 	s.mu.Lock()
@@ -534,13 +547,16 @@ func (s *Service) broadcastConfirmation(confirmation *witnesspb.WitnessConfirmat
 func (s *Service) createVotingSession(report *witnesspb.WitnessReport) *VotingSession {
 	reportID := string(report.ReportId)
 	targetID := string(report.TargetId)
+	reporterID := string(report.ReporterId)
 
 	memberCount := 0
 	if s.member != nil {
 		memberCount = s.member.GetTotalCount()
 	}
 
-	session := NewVotingSession(reportID, targetID, memberCount, s.onVotingComplete)
+	session := NewVotingSession(reportID, targetID, memberCount, func(result *witnesspb.WitnessVotingResult) {
+		s.onVotingComplete(result, reporterID)
+	})
 
 	s.mu.Lock()
 	s.votingSessions[reportID] = session
@@ -566,10 +582,14 @@ func (s *Service) getOrCreateVotingSession(report *witnesspb.WitnessReport) *Vot
 }
 
 // onVotingComplete 投票完成回调
-func (s *Service) onVotingComplete(result *witnesspb.WitnessVotingResult) {
+func (s *Service) onVotingComplete(result *witnesspb.WitnessVotingResult, reporterID string) {
 	targetID := string(result.TargetId)
 	reportID := string(result.ReportId)
 
+	s.mu.RLock()
+	rep := s.reputation
+	s.mu.RUnlock()
+
 	if result.Confirmed {
 		logger.Info("见证投票确认成员断开",
 			"reportID", truncateID(reportID),
@@ -578,6 +598,10 @@ func (s *Service) onVotingComplete(result *witnesspb.WitnessVotingResult) {
 			"disagree", result.DisagreeCount,
 			"abstain", result.AbstainCount)
 
+		if rep != nil {
+			rep.Report(targetID, pkgif.ReputationSignalWitness, witnessPenalty, "confirmed_offline")
+		}
+
 		// 移除成员
 		if s.member != nil {
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -595,6 +619,11 @@ func (s *Service) onVotingComplete(result *witnesspb.WitnessVotingResult) {
 			"agree", result.AgreeCount,
 			"disagree", result.DisagreeCount,
 			"abstain", result.AbstainCount)
+
+		// 报告被否决，降低报告者的信誉（本节点的报告除外）
+		if rep != nil && reporterID != "" && reporterID != s.localID {
+			rep.Report(reporterID, pkgif.ReputationSignalWitness, witnessPenalty, "rejected_report")
+		}
 	}
 
 	// 清理投票会话
@@ -6,6 +6,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/dep2p/go-dep2p/internal/core/reputation"
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
 	witnesspb "github.com/dep2p/go-dep2p/pkg/lib/proto/realm/witness"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -93,6 +95,41 @@ func TestWitnessService_StartStop(t *testing.T) {
 	}
 }
 
+// TestWitnessService_VotingReputation 测试投票结果上报信誉服务
+func TestWitnessService_VotingReputation(t *testing.T) {
+	rep, err := reputation.New(pkgif.DefaultReputationConfig(), nil)
+	require.NoError(t, err)
+
+	member := newMockMemberManager()
+	service := NewService("self-peer", "test-realm", member)
+	service.SetReputation(rep)
+
+	// 确认断开：降低目标的信誉
+	service.onVotingComplete(&witnesspb.WitnessVotingResult{
+		ReportId:  []byte("report-1"),
+		TargetId:  []byte("target-peer"),
+		Confirmed: true,
+	}, "reporter-peer")
+	assert.Equal(t, "target-peer", member.removedPeer)
+	assert.Less(t, rep.Score("target-peer"), 0.0)
+	assert.Zero(t, rep.Score("reporter-peer"))
+
+	// 报告被否决：降低报告者的信誉
+	service.onVotingComplete(&witnesspb.WitnessVotingResult{
+		ReportId: []byte("report-2"),
+		TargetId: []byte("other-peer"),
+	}, "reporter-peer")
+	assert.Equal(t, "rejected_report", rep.Get("reporter-peer").Reasons[0].Reason)
+	assert.Zero(t, rep.Score("other-peer"))
+
+	// 本节点的报告被否决时不记录
+	service.onVotingComplete(&witnesspb.WitnessVotingResult{
+		ReportId: []byte("report-3"),
+		TargetId: []byte("other-peer"),
+	}, "self-peer")
+	assert.Zero(t, rep.Score("self-peer"))
+}
+
 // TestRateLimiter_AllowReport 测试限速器允许报告
 func TestRateLimiter_AllowReport(t *testing.T) {
 	limiter := NewRateLimiter(2, 100*time.Millisecond)
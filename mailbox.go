@@ -0,0 +1,86 @@
+package dep2p
+
+import (
+	"context"
+	"time"
+
+	"github.com/dep2p/go-dep2p/pkg/interfaces"
+)
+
+// ════════════════════════════════════════════════════════════════════════════
+//                              用户 API: Mailbox
+// ════════════════════════════════════════════════════════════════════════════
+
+// Mailbox 用户级离线信箱服务 API
+//
+// Mailbox 提供面向离线成员的存储转发：消息使用接收方公钥端到端加密后
+// 存放在信箱节点，接收方上线后拉取并确认。信箱节点只能看到密文。
+//
+// 使用示例：
+//
+//	mailbox := realm.Mailbox()
+//
+//	// 发送方：投递给离线成员
+//	id, _ := mailbox.Deposit(ctx, mailboxPeer, recipient, []byte("hello"), time.Hour)
+//
+//	// 接收方：上线后拉取并确认
+//	msgs, _ := mailbox.Fetch(ctx, mailboxPeer)
+//	for _, msg := range msgs {
+//	    process(msg.From, msg.Data)
+//	}
+//	mailbox.Ack(ctx, mailboxPeer, ids)
+type Mailbox struct {
+	internal interfaces.Mailbox
+}
+
+// MailboxMessage 离线消息
+type MailboxMessage = interfaces.MailboxMessage
+
+// Deposit 向信箱节点投递发往 recipient 的消息
+//
+// 参数：
+//   - ctx: 上下文
+//   - mailboxPeer: 信箱节点 ID
+//   - recipient: 接收方节点 ID（必须是 Realm 成员且公钥已知）
+//   - data: 消息数据
+//   - ttl: 存储时长（0 表示使用信箱默认值，超过上限会被截断）
+//
+// 返回：
+//   - string: 信箱分配的消息 ID
+//   - error: 错误信息（如配额超限、消息过大）
+func (m *Mailbox) Deposit(ctx context.Context, mailboxPeer, recipient string, data []byte, ttl time.Duration) (string, error) {
+	if m.internal == nil {
+		return "", ErrMailboxUnavailable
+	}
+	return m.internal.Deposit(ctx, mailboxPeer, recipient, data, ttl)
+}
+
+// Fetch 从信箱节点拉取发给本节点的消息
+//
+// 返回的消息已解密；处理完成后应调用 Ack 删除。
+func (m *Mailbox) Fetch(ctx context.Context, mailboxPeer string) ([]*MailboxMessage, error) {
+	if m.internal == nil {
+		return nil, ErrMailboxUnavailable
+	}
+	return m.internal.Fetch(ctx, mailboxPeer)
+}
+
+// Ack 确认消息已处理，信箱节点随即删除这些消息
+func (m *Mailbox) Ack(ctx context.Context, mailboxPeer string, ids []string) error {
+	if m.internal == nil {
+		return ErrMailboxUnavailable
+	}
+	return m.internal.Ack(ctx, mailboxPeer, ids)
+}
+
+// OnMessage 设置离线消息自动投递处理器
+//
+// 设置后，每当与配置的信箱节点（WithMailboxPeers）建立连接，
+// 会自动拉取消息、调用处理器并确认。
+func (m *Mailbox) OnMessage(handler func(msg *MailboxMessage)) error {
+	if m.internal == nil {
+		return ErrMailboxUnavailable
+	}
+	m.internal.SetMessageHandler(handler)
+	return nil
+}
@@ -116,6 +116,43 @@ func (m *Messaging) UnregisterHandler(protocol string) error {
 	return m.internal.UnregisterHandler(protocol)
 }
 
+// ════════════════════════════════════════════════════════════════════════════
+//                              持久化发送
+// ════════════════════════════════════════════════════════════════════════════
+
+// SendDurable 持久化消息并异步投递，返回消息 ID
+//
+// 需要启用发件箱（WithMessagingOutbox）并配置数据目录。消息在送达或
+// 过期前会跨重启、断线重连持续重试（至少一次语义），接收方按消息 ID
+// 去重。投递结果通过 OnDelivery 回调报告。
+//
+// 示例：
+//
+//	id, err := messaging.SendDurable(ctx, peerID, "order", data,
+//	    dep2p.DurableSendOptions{IdempotencyKey: orderID})
+func (m *Messaging) SendDurable(ctx context.Context, peerID string, protocol string, data []byte, opts DurableSendOptions) (string, error) {
+	durable, ok := m.internal.(interfaces.DurableMessaging)
+	if !ok {
+		return "", ErrOutboxUnavailable
+	}
+	return durable.SendDurable(ctx, peerID, protocol, data, opts)
+}
+
+// OnDelivery 设置持久化消息的投递状态回调
+func (m *Messaging) OnDelivery(handler DeliveryHandler) {
+	if durable, ok := m.internal.(interfaces.DurableMessaging); ok {
+		durable.OnDelivery(handler)
+	}
+}
+
+// Pending 返回发件箱中尚未投递的消息 ID
+func (m *Messaging) Pending() []string {
+	if durable, ok := m.internal.(interfaces.DurableMessaging); ok {
+		return durable.Pending()
+	}
+	return nil
+}
+
 // ════════════════════════════════════════════════════════════════════════════
 //                              生命周期
 // ════════════════════════════════════════════════════════════════════════════
@@ -137,3 +174,12 @@ type Request = interfaces.Request
 
 // Response 消息响应
 type Response = interfaces.Response
+
+// DurableSendOptions 持久化发送选项
+type DurableSendOptions = interfaces.DurableSendOptions
+
+// DeliveryReport 投递状态报告
+type DeliveryReport = interfaces.DeliveryReport
+
+// DeliveryHandler 投递状态回调函数类型
+type DeliveryHandler = interfaces.DeliveryHandler
@@ -19,7 +19,7 @@ import (
 //   - NAT 类型
 //   - 端口映射协议可用性（UPnP、NAT-PMP、PCP）
 //   - 是否存在强制门户
-//   - 中继服务器延迟
+//   - 中继服务器延迟与负载（通过中继协议探测）
 //
 // 示例：
 //
@@ -49,6 +49,7 @@ func (n *Node) GetNetworkDiagnostics(ctx context.Context) (*NetworkDiagnosticRep
 		PCPAvailable:    report.PCPAvailable,
 		Duration:        report.Duration.Milliseconds(),
 		RelayLatencies:  make(map[string]int64),
+		PreferredRelay:  report.PreferredRelay,
 	}
 
 	// CaptivePortal 是指针类型
@@ -73,6 +74,19 @@ func (n *Node) GetNetworkDiagnostics(ctx context.Context) (*NetworkDiagnosticRep
 		result.RelayLatencies[url] = latency.Milliseconds()
 	}
 
+	// 中继探测详情
+	if len(report.RelayProbes) > 0 {
+		result.RelayProbes = make(map[string]RelayProbeInfo, len(report.RelayProbes))
+		for url, probe := range report.RelayProbes {
+			result.RelayProbes[url] = RelayProbeInfo{
+				LatencyMs:              probe.Latency.Milliseconds(),
+				LoadKnown:              probe.LoadKnown,
+				ReservationUtilization: probe.ReservationUtilization,
+				CircuitUtilization:     probe.CircuitUtilization,
+			}
+		}
+	}
+
 	return result, nil
 }
 
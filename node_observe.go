@@ -382,6 +382,202 @@ func (n *Node) IsBandwidthStatsEnabled() bool {
 	return n.getBandwidthCounter() != nil
 }
 
+// SetBandwidthLimits 调整带宽限速
+//
+// 替换全部限速配置，立即对所有流生效。带宽统计未启用时返回 ErrBandwidthLimiterUnavailable。
+//
+// 示例：
+//
+//	// 按流量套餐限制某个 Realm 的上传速率
+//	limits := node.BandwidthLimits()
+//	limits.Realms = map[string]dep2p.BandwidthLimit{realmID: {Out: 64 << 10}}
+//	node.SetBandwidthLimits(limits)
+func (n *Node) SetBandwidthLimits(limits BandwidthLimits) error {
+	limiter := n.getBandwidthLimiter()
+	if limiter == nil {
+		return ErrBandwidthLimiterUnavailable
+	}
+	limiter.SetLimits(limits)
+	return nil
+}
+
+// BandwidthLimits 返回当前带宽限速配置
+//
+// 带宽统计未启用时返回空配置。
+func (n *Node) BandwidthLimits() BandwidthLimits {
+	limiter := n.getBandwidthLimiter()
+	if limiter == nil {
+		return BandwidthLimits{}
+	}
+	return limiter.Limits()
+}
+
+// BandwidthLimitStatus 返回各限速范围的状态
+//
+// 包括当前限速、等待次数和累计等待时间。未启用时返回 nil。
+func (n *Node) BandwidthLimitStatus() []BandwidthLimitStatus {
+	limiter := n.getBandwidthLimiter()
+	if limiter == nil {
+		return nil
+	}
+	return limiter.Status()
+}
+
+// getBandwidthLimiter 获取带宽限速器
+//
+// 通过 Swarm 类型断言获取，未启用时返回 nil。
+func (n *Node) getBandwidthLimiter() pkgif.BandwidthLimiter {
+	if n.host == nil {
+		return nil
+	}
+
+	swarm := n.host.Network()
+	if swarm == nil {
+		return nil
+	}
+
+	type limiterProvider interface {
+		BandwidthLimiter() pkgif.BandwidthLimiter
+	}
+
+	if provider, ok := swarm.(limiterProvider); ok {
+		return provider.BandwidthLimiter()
+	}
+
+	return nil
+}
+
+// SetDataBudgetMode 设置计费网络模式
+//
+// DataBudgetMetered 开启省流模式，DataBudgetUnmetered 关闭，
+// DataBudgetAuto 根据系统提示（如蜂窝网络接口）判断。
+// 带宽统计未启用时返回 ErrDataBudgetUnavailable。
+func (n *Node) SetDataBudgetMode(mode DataBudgetMode) error {
+	budget := n.getDataBudget()
+	if budget == nil {
+		return ErrDataBudgetUnavailable
+	}
+	budget.SetMode(mode)
+	return nil
+}
+
+// SetDailyDataBudget 设置每日流量预算（字节，入+出）
+//
+// 计费网络下后台流量按今日用量逐级放慢：超过 80% 放慢 16 倍，用完放慢 64 倍。
+// 0 表示不设预算。带宽统计未启用时返回 ErrDataBudgetUnavailable。
+//
+// 示例：
+//
+//	// 卫星终端：每日 20MB
+//	node.SetDataBudgetMode(dep2p.DataBudgetMetered)
+//	node.SetDailyDataBudget(20 << 20)
+func (n *Node) SetDailyDataBudget(bytes int64) error {
+	if bytes < 0 {
+		return fmt.Errorf("daily data budget must be non-negative")
+	}
+	budget := n.getDataBudget()
+	if budget == nil {
+		return ErrDataBudgetUnavailable
+	}
+	budget.SetDailyBudget(bytes)
+	return nil
+}
+
+// DataBudgetStatus 返回数据预算状态
+//
+// 包括是否按计费网络处理、今日用量、后台任务节流倍数以及被节流的子系统。
+// 未启用时返回零值。
+func (n *Node) DataBudgetStatus() DataBudgetStatus {
+	budget := n.getDataBudget()
+	if budget == nil {
+		return DataBudgetStatus{}
+	}
+	return budget.Status()
+}
+
+// getDataBudget 获取数据预算
+//
+// 通过 Swarm 类型断言获取，未启用时返回 nil。
+func (n *Node) getDataBudget() pkgif.DataBudget {
+	if n.host == nil {
+		return nil
+	}
+
+	swarm := n.host.Network()
+	if swarm == nil {
+		return nil
+	}
+
+	type budgetProvider interface {
+		DataBudget() pkgif.DataBudget
+	}
+
+	if provider, ok := swarm.(budgetProvider); ok {
+		return provider.DataBudget()
+	}
+
+	return nil
+}
+
+// PeerReputation 返回节点的信誉详情
+//
+// 包括统一信誉分、是否被临时封禁以及各信号的贡献原因。
+// 信誉服务未启用或节点没有记录时返回仅含 PeerID 的零值。
+func (n *Node) PeerReputation(peerID string) PeerReputation {
+	rep := n.getReputation()
+	if rep == nil {
+		return PeerReputation{PeerID: peerID}
+	}
+	return rep.Get(peerID)
+}
+
+// PeerReputations 返回所有有记录节点的信誉（按信誉分升序）
+//
+// 未启用时返回 nil。
+func (n *Node) PeerReputations() []PeerReputation {
+	rep := n.getReputation()
+	if rep == nil {
+		return nil
+	}
+	return rep.List()
+}
+
+// UnbanPeer 解除节点的临时封禁并清空其信誉记录
+//
+// 信誉服务未启用时返回 ErrReputationUnavailable。
+func (n *Node) UnbanPeer(peerID string) error {
+	rep := n.getReputation()
+	if rep == nil {
+		return ErrReputationUnavailable
+	}
+	rep.Unban(peerID)
+	return nil
+}
+
+// getReputation 获取信誉服务
+//
+// 通过 Swarm 类型断言获取，未启用时返回 nil。
+func (n *Node) getReputation() pkgif.Reputation {
+	if n.host == nil {
+		return nil
+	}
+
+	swarm := n.host.Network()
+	if swarm == nil {
+		return nil
+	}
+
+	type reputationProvider interface {
+		Reputation() pkgif.Reputation
+	}
+
+	if provider, ok := swarm.(reputationProvider); ok {
+		return provider.Reputation()
+	}
+
+	return nil
+}
+
 // getBandwidthCounter 获取带宽计数器
 //
 // 通过 Swarm 类型断言获取内部的 BandwidthCounter。
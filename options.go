@@ -7,6 +7,8 @@ import (
 	"go.uber.org/fx"
 
 	"github.com/dep2p/go-dep2p/config"
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/lib/tracing"
 )
 
 // Option 配置选项函数
@@ -54,6 +56,12 @@ type nodeConfig struct {
 	// userFxOptions 用户自定义 Fx 选项
 	// 允许用户注入自定义模块到依赖注入容器
 	userFxOptions []fx.Option
+
+	// spanExporter 自定义 Span 导出器（优先于配置的文件导出器）
+	spanExporter tracing.Exporter
+
+	// dialRanker 自定义拨号地址排序器（优先于配置的错峰延迟）
+	dialRanker pkgif.DialRanker
 }
 
 // newNodeConfig 创建默认的 nodeConfig
@@ -154,6 +162,54 @@ func WithDialTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithDialStagger 设置错峰拨号延迟（Happy Eyeballs）
+//
+// 拨号时地址按类别依次发起：局域网 QUIC、局域网 TCP、公网 QUIC、
+// 公网 TCP、中继。同类地址间隔 addrDelay（IPv6/IPv4 交错），
+// 进入下一类间隔 classDelay；任一地址成功即取消其余拨号。
+// 两个延迟都为 0 时所有地址同时拨号。
+//
+// 示例：
+//
+//	dep2p.New(ctx, dep2p.WithDialStagger(250*time.Millisecond, 300*time.Millisecond))
+func WithDialStagger(addrDelay, classDelay time.Duration) Option {
+	return func(cfg *nodeConfig) error {
+		if addrDelay < 0 || classDelay < 0 {
+			return fmt.Errorf("dial stagger delays cannot be negative")
+		}
+		cfg.config.Transport.DialAddrDelay = config.Duration(addrDelay)
+		cfg.config.Transport.DialClassDelay = config.Duration(classDelay)
+		return nil
+	}
+}
+
+// WithDialRanker 设置自定义拨号地址排序器
+//
+// ranker 收到已按路径健康度排序的直连地址，返回每个地址的拨号启动延迟；
+// 设置后 WithDialStagger 的延迟不再生效。
+//
+// 示例：
+//
+//	dep2p.New(ctx, dep2p.WithDialRanker(func(addrs []string) []interfaces.AddrDelay {
+//	    // 只拨号 QUIC 地址，同时发起
+//	    var out []interfaces.AddrDelay
+//	    for _, a := range addrs {
+//	        if strings.Contains(a, "/quic") {
+//	            out = append(out, interfaces.AddrDelay{Addr: a})
+//	        }
+//	    }
+//	    return out
+//	}))
+func WithDialRanker(ranker pkgif.DialRanker) Option {
+	return func(cfg *nodeConfig) error {
+		if ranker == nil {
+			return fmt.Errorf("dial ranker cannot be nil")
+		}
+		cfg.dialRanker = ranker
+		return nil
+	}
+}
+
 // ════════════════════════════════════════════════════════════════════════════
 //
 //	传输选项
@@ -196,6 +252,53 @@ func WithWebSocket(enable bool) Option {
 	}
 }
 
+// WithQUICZeroRTT 启用或禁用 QUIC 0-RTT 会话恢复（默认启用）
+//
+// 重连已知节点时，经 WithEarlyData 标记的流可以在 0-RTT 中发送，
+// 服务端只对声明为幂等的协议提前处理，其他流等待握手确认。
+// 禁用后仍会恢复会话，但不再发送早期数据。
+//
+// 示例：
+//
+//	dep2p.New(ctx, dep2p.WithQUICZeroRTT(false))
+func WithQUICZeroRTT(enable bool) Option {
+	return func(cfg *nodeConfig) error {
+		cfg.config.Transport.QUIC.ZeroRTT = enable
+		return nil
+	}
+}
+
+// WithQUICMigration 启用或禁用 QUIC 连接迁移（默认启用）
+//
+// 网络变化时把出站 QUIC 连接迁移到新的本地地址，连接和流不中断。
+// 禁用后网络变化时重建 socket，由恢复流程重新连接。
+//
+// 示例：
+//
+//	dep2p.New(ctx, dep2p.WithQUICMigration(false))
+func WithQUICMigration(enable bool) Option {
+	return func(cfg *nodeConfig) error {
+		cfg.config.Transport.QUIC.Migration = enable
+		return nil
+	}
+}
+
+// WithQUICMultipath 启用或禁用 QUIC 备用路径（默认禁用）
+//
+// 启用后每个出站 QUIC 连接在第二个网络接口（如 Wi-Fi 之外的蜂窝网络）上
+// 维持一条已验证的备用路径，当前接口断开时由路径健康管理器决定切换，
+// Realm 等上层流不受影响。
+//
+// 示例：
+//
+//	dep2p.New(ctx, dep2p.WithQUICMultipath(true))
+func WithQUICMultipath(enable bool) Option {
+	return func(cfg *nodeConfig) error {
+		cfg.config.Transport.QUIC.Multipath = enable
+		return nil
+	}
+}
+
 // ════════════════════════════════════════════════════════════════════════════
 //
 //	安全选项
@@ -226,6 +329,21 @@ func WithNoise(enable bool) Option {
 	}
 }
 
+// WithPostQuantum 启用或禁用 X25519 + ML-KEM-768 混合后量子密钥交换
+//
+// 启用后 TLS/Noise 在安全协商中优先提议混合变体，QUIC 通过 ALPN 协商，
+// 对端不支持时回退到经典 X25519。协商结果见连接统计的 Security/KeyExchange。
+//
+// 示例：
+//
+//	dep2p.New(ctx, dep2p.WithPostQuantum(true))
+func WithPostQuantum(enable bool) Option {
+	return func(cfg *nodeConfig) error {
+		cfg.config.Security.PostQuantum = enable
+		return nil
+	}
+}
+
 // ════════════════════════════════════════════════════════════════════════════
 //
 //	身份选项
@@ -461,6 +579,20 @@ func WithNATPMP(enable bool) Option {
 	}
 }
 
+// WithPCP 启用或禁用 PCP（Port Control Protocol，RFC 6887）
+//
+// PCP 是 NAT-PMP 的后继协议，支持 IPv6 和 NAT64，许多新路由器只支持 PCP。
+//
+// 示例：
+//
+//	dep2p.New(ctx, dep2p.WithPCP(true))
+func WithPCP(enable bool) Option {
+	return func(cfg *nodeConfig) error {
+		cfg.config.NAT.EnablePCP = enable
+		return nil
+	}
+}
+
 // WithHolePunch 启用或禁用 Hole Punching
 //
 // 示例：
@@ -485,6 +617,7 @@ func WithNAT(enable bool) Option {
 		cfg.config.NAT.EnableAutoNAT = enable
 		cfg.config.NAT.EnableUPnP = enable
 		cfg.config.NAT.EnableNATPMP = enable
+		cfg.config.NAT.EnablePCP = enable
 		cfg.config.NAT.EnableHolePunch = enable
 		return nil
 	}
@@ -686,6 +819,70 @@ func WithBandwidth(enabled, perPeer, perProtocol bool) Option {
 	}
 }
 
+// WithBandwidthLimits 设置带宽限速
+//
+// 限速在流读写层按令牌桶生效，需启用带宽统计。
+// 运行时可通过 node.SetBandwidthLimits() 调整。
+//
+// 示例：
+//
+//	// 上传 1MB/s、下载 4MB/s，每个节点上传不超过 256KB/s
+//	dep2p.New(ctx, dep2p.WithBandwidthLimits(dep2p.BandwidthLimits{
+//	    Total:   dep2p.BandwidthLimit{In: 4 << 20, Out: 1 << 20},
+//	    PerPeer: dep2p.BandwidthLimit{Out: 256 << 10},
+//	}))
+func WithBandwidthLimits(limits BandwidthLimits) Option {
+	return func(cfg *nodeConfig) error {
+		convert := func(m map[string]BandwidthLimit) map[string]config.RateLimitConfig {
+			if len(m) == 0 {
+				return nil
+			}
+			out := make(map[string]config.RateLimitConfig, len(m))
+			for k, v := range m {
+				out[k] = config.RateLimitConfig(v)
+			}
+			return out
+		}
+		cfg.config.Bandwidth.Limits = config.BandwidthLimitsConfig{
+			Total:     config.RateLimitConfig(limits.Total),
+			PerPeer:   config.RateLimitConfig(limits.PerPeer),
+			Peers:     convert(limits.Peers),
+			Protocols: convert(limits.Protocols),
+			Realms:    convert(limits.Realms),
+		}
+		return nil
+	}
+}
+
+// WithDataBudget 设置计费网络的数据预算
+//
+// 计费网络（移动、卫星）下按每日流量预算放慢后台流量：DHT 刷新、
+// GossipSub 心跳、存活检测、中继发现、网络诊断和 Realm 成员心跳。需启用带宽统计。
+// dailyBytes 为 0 表示不设预算（计费网络下固定放慢 4 倍）。
+// 运行时可通过 node.SetDataBudgetMode() / node.SetDailyDataBudget() 调整。
+//
+// 示例：
+//
+//	// 自动检测计费网络，每日预算 50MB
+//	dep2p.New(ctx, dep2p.WithDataBudget(dep2p.DataBudgetAuto, 50<<20))
+func WithDataBudget(mode DataBudgetMode, dailyBytes int64) Option {
+	return func(cfg *nodeConfig) error {
+		if dailyBytes < 0 {
+			return fmt.Errorf("data budget daily bytes must be non-negative")
+		}
+		switch mode {
+		case DataBudgetMetered:
+			cfg.config.Bandwidth.DataBudget.Mode = config.DataBudgetModeMetered
+		case DataBudgetUnmetered:
+			cfg.config.Bandwidth.DataBudget.Mode = config.DataBudgetModeUnmetered
+		default:
+			cfg.config.Bandwidth.DataBudget.Mode = config.DataBudgetModeAuto
+		}
+		cfg.config.Bandwidth.DataBudget.DailyBytes = dailyBytes
+		return nil
+	}
+}
+
 // ════════════════════════════════════════════════════════════════════════════
 //
 //	连接管理选项
@@ -731,6 +928,58 @@ func WithGracePeriod(period time.Duration) Option {
 	}
 }
 
+// WithReputation 启用或禁用节点信誉服务
+//
+// 信誉服务汇总 GossipSub 评分、连接标签、抖动、路径健康、见证报告和拨号结果，
+// 供连接裁剪、DHT 查询、中继选择和连接门控参考。默认启用。
+// 可通过 node.PeerReputation() 查看节点的信誉分及原因。
+//
+// 示例：
+//
+//	dep2p.New(ctx, dep2p.WithReputation(false))
+func WithReputation(enable bool) Option {
+	return func(cfg *nodeConfig) error {
+		cfg.config.Reputation.Enabled = enable
+		return nil
+	}
+}
+
+// WithReputationPersistence 设置是否持久化信誉记录
+//
+// 启用后信誉记录与临时封禁跨重启保留，信号按保存时间继续衰减。
+//
+// 示例：
+//
+//	dep2p.New(ctx, dep2p.WithReputationPersistence(true))
+func WithReputationPersistence(enable bool) Option {
+	return func(cfg *nodeConfig) error {
+		cfg.config.Reputation.Persist = enable
+		return nil
+	}
+}
+
+// WithReputationBan 设置信誉临时封禁
+//
+// threshold: 封禁阈值，信誉分低于此值的节点被临时封禁（必须 <= 0，0 表示不封禁）
+// duration: 封禁时长，封禁期间拒绝与该节点的连接
+//
+// 示例：
+//
+//	dep2p.New(ctx, dep2p.WithReputationBan(-40, time.Hour))
+func WithReputationBan(threshold float64, duration time.Duration) Option {
+	return func(cfg *nodeConfig) error {
+		if threshold > 0 {
+			return fmt.Errorf("reputation ban threshold must be <= 0")
+		}
+		if threshold < 0 && duration <= 0 {
+			return fmt.Errorf("reputation ban duration must be positive")
+		}
+		cfg.config.Reputation.BanThreshold = threshold
+		cfg.config.Reputation.BanDuration = config.Duration(duration)
+		return nil
+	}
+}
+
 // ════════════════════════════════════════════════════════════════════════════
 //
 //	资源管理选项
@@ -836,6 +1085,116 @@ func WithLiveness(enable bool) Option {
 	}
 }
 
+// WithMailboxServer 启用或禁用离线信箱服务端
+//
+// 启用后本节点为 Realm 内其他成员存储离线消息（仅密文），
+// 需要同时配置数据目录（WithDataDir）。
+func WithMailboxServer(enable bool) Option {
+	return func(cfg *nodeConfig) error {
+		cfg.config.Messaging.Mailbox.EnableServer = enable
+		return nil
+	}
+}
+
+// WithMailboxPeers 设置本节点使用的信箱节点
+//
+// 与信箱节点建立连接时，若已设置消息处理器，将自动拉取离线消息。
+func WithMailboxPeers(peers ...string) Option {
+	return func(cfg *nodeConfig) error {
+		cfg.config.Messaging.Mailbox.MailboxPeers = append([]string(nil), peers...)
+		return nil
+	}
+}
+
+// WithTransferRateLimit 设置文件传输的上传和下载限速
+//
+// 单位为字节/秒，0 表示不限速。消耗量优先从带宽计数器中按传输协议读取。
+//
+// 示例：
+//
+//	dep2p.WithTransferRateLimit(1<<20, 4<<20) // 上传 1 MB/s，下载 4 MB/s
+func WithTransferRateLimit(upload, download int64) Option {
+	return func(cfg *nodeConfig) error {
+		if upload < 0 || download < 0 {
+			return fmt.Errorf("transfer rate limits must not be negative")
+		}
+		cfg.config.Messaging.Transfer.MaxUploadRate = upload
+		cfg.config.Messaging.Transfer.MaxDownloadRate = download
+		return nil
+	}
+}
+
+// WithMessagingOutbox 启用 Messaging 持久化发件箱
+//
+// 启用后可使用 Messaging.SendDurable，消息保存在存储引擎中（需要配置
+// 数据目录），ttl 为消息默认有效期，0 表示使用默认值（24 小时）。
+//
+// 示例：
+//
+//	dep2p.WithMessagingOutbox(true, 12*time.Hour)
+func WithMessagingOutbox(enable bool, ttl time.Duration) Option {
+	return func(cfg *nodeConfig) error {
+		if ttl < 0 {
+			return fmt.Errorf("outbox TTL must not be negative")
+		}
+		cfg.config.Messaging.Outbox.Enable = enable
+		if ttl > 0 {
+			cfg.config.Messaging.Outbox.TTL = ttl
+		}
+		return nil
+	}
+}
+
+// WithMessagingCompression 为 Messaging 协议启用负载压缩
+//
+// algs 按偏好排序，可选 "zstd"、"snappy"。双方都启用时才压缩，
+// 否则回退到未压缩传输。不传算法时禁用该协议的压缩。
+//
+// 示例：
+//
+//	dep2p.WithMessagingCompression("chat", "zstd", "snappy")
+func WithMessagingCompression(protocol string, algs ...string) Option {
+	return func(cfg *nodeConfig) error {
+		cfg.config.Messaging.Compression.Protocols = setCompression(cfg.config.Messaging.Compression.Protocols, protocol, algs)
+		return nil
+	}
+}
+
+// WithPubSubCompression 为 PubSub 主题启用负载压缩
+//
+// 接收方始终支持解压；发送方仅对支持压缩的节点压缩。
+// 不传算法时禁用该主题的压缩。
+func WithPubSubCompression(topic string, algs ...string) Option {
+	return func(cfg *nodeConfig) error {
+		cfg.config.Messaging.Compression.Topics = setCompression(cfg.config.Messaging.Compression.Topics, topic, algs)
+		return nil
+	}
+}
+
+// WithMaxDecompressedSize 设置解压后大小上限（字节）
+func WithMaxDecompressedSize(size int) Option {
+	return func(cfg *nodeConfig) error {
+		if size <= 0 {
+			return fmt.Errorf("max decompressed size must be positive")
+		}
+		cfg.config.Messaging.Compression.MaxDecompressedSize = size
+		return nil
+	}
+}
+
+// setCompression 设置或删除单个名称的压缩算法
+func setCompression(m map[string][]string, name string, algs []string) map[string][]string {
+	if len(algs) == 0 {
+		delete(m, name)
+		return m
+	}
+	if m == nil {
+		m = make(map[string][]string)
+	}
+	m[name] = append([]string(nil), algs...)
+	return m
+}
+
 // ════════════════════════════════════════════════════════════════════════════
 //
 //	Realm 选项
@@ -881,6 +1240,99 @@ func WithRealmAuth(enable bool) Option {
 	}
 }
 
+// WithRealmTopology 启用 Realm 拓扑协议
+//
+// 启用后成员周期性地与已连接成员交换邻居摘要，可通过 Realm.Topology
+// 或自省服务的 /debug/introspect/topology 查看全 Realm 连接图。
+// interval 为摘要交换间隔，0 表示使用默认值（1 分钟）。
+//
+// 示例：
+//
+//	dep2p.New(ctx, dep2p.WithRealmTopology(true, 30*time.Second))
+func WithRealmTopology(enable bool, interval time.Duration) Option {
+	return func(cfg *nodeConfig) error {
+		if interval < 0 {
+			return fmt.Errorf("topology interval must not be negative")
+		}
+		cfg.config.Realm.Topology.Enable = enable
+		if interval > 0 {
+			cfg.config.Realm.Topology.Interval = interval
+			if cfg.config.Realm.Topology.ReportTTL < 5*interval {
+				cfg.config.Realm.Topology.ReportTTL = 5 * interval
+			}
+		}
+		return nil
+	}
+}
+
+// ════════════════════════════════════════════════════════════════════════════
+//
+//	追踪与录制选项
+//
+// ════════════════════════════════════════════════════════════════════════════
+
+// WithTracing 启用分布式追踪，Span 以 OTLP/JSON 行格式写入文件
+//
+// 追踪上下文随 Messaging、PubSub、Streams 跨节点传播，文件可由
+// OpenTelemetry Collector 的 otlpjsonfile receiver 读取后转发到
+// Jaeger/Tempo 等后端。
+//
+// 示例：
+//
+//	dep2p.New(ctx, dep2p.WithTracing("/var/log/dep2p-spans.jsonl"))
+func WithTracing(file string) Option {
+	return func(cfg *nodeConfig) error {
+		if file == "" {
+			return fmt.Errorf("tracing file path cannot be empty")
+		}
+		cfg.config.Diagnostics.Tracing.Enabled = true
+		cfg.config.Diagnostics.Tracing.File = file
+		return nil
+	}
+}
+
+// WithSpanExporter 使用自定义 Span 导出器启用分布式追踪
+//
+// 导出器的生命周期由调用方负责，节点停止时不会调用其 Shutdown。
+//
+// 示例：
+//
+//	exporter := tracing.NewMemoryExporter()
+//	dep2p.New(ctx, dep2p.WithSpanExporter(exporter))
+func WithSpanExporter(exporter tracing.Exporter) Option {
+	return func(cfg *nodeConfig) error {
+		if exporter == nil {
+			return fmt.Errorf("span exporter cannot be nil")
+		}
+		cfg.spanExporter = exporter
+		return nil
+	}
+}
+
+// WithTrafficCapture 启用协议流量录制器
+//
+// 录制器记录协议协商后每条流解密后的读写数据，写入按大小轮转的
+// JSON 行文件，可用 pkg/lib/capture 读取并回放到处理器。
+// autoStart 为 false 时只创建录制器，通过自省服务的
+// /debug/capture/start 端点按需开始录制。
+//
+// 示例：
+//
+//	dep2p.New(ctx,
+//	    dep2p.WithTrafficCapture("/tmp/dep2p.capture", false),
+//	)
+func WithTrafficCapture(file string, autoStart bool) Option {
+	return func(cfg *nodeConfig) error {
+		if file == "" {
+			return fmt.Errorf("capture file path cannot be empty")
+		}
+		cfg.config.Diagnostics.Capture.Enabled = true
+		cfg.config.Diagnostics.Capture.AutoStart = autoStart
+		cfg.config.Diagnostics.Capture.File = file
+		return nil
+	}
+}
+
 // ════════════════════════════════════════════════════════════════════════════
 //
 //	日志选项
@@ -909,6 +1361,7 @@ func WithLogFile(path string) Option {
 //
 // 数据目录用于存放 BadgerDB 数据库和其他持久化数据。
 // 所有组件统一使用此目录，通过 Key 前缀隔离数据。
+// 节点缓存（种子节点、拨号历史）也保存在此目录，重启后 RecoverSeeds 仍可用。
 //
 // 目录结构：
 //
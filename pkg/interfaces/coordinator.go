@@ -54,6 +54,25 @@ type DiscoveryOptions struct {
 
 	// TTL 广播 TTL
 	TTL time.Duration
+
+	// Watch 持续订阅：通道不在首轮结果后关闭，而是持续推送新发现或记录
+	// 变更的节点，直到 ctx 结束。节点注册过期或取消时推送 Expired 为 true
+	// 的 PeerInfo。不支持订阅的发现组件忽略此选项。
+	Watch bool
+
+	// 以下属性过滤仅对能携带节点元数据的发现组件（如 mDNS）生效，其他组件忽略。
+
+	// Realm 仅返回广播了该 Realm 的节点（按 Realm 哈希匹配）
+	Realm types.RealmID
+
+	// Protocols 仅返回声明支持全部这些协议的节点
+	Protocols []string
+
+	// Role 仅返回具有该角色的节点（如 "relay"、"bootstrap"）
+	Role string
+
+	// AppVersion 仅返回应用版本一致的节点
+	AppVersion string
 }
 
 // WithLimit 设置发现数量限制
@@ -63,6 +82,41 @@ func WithLimit(limit int) DiscoveryOption {
 	}
 }
 
+// WithWatch 持续订阅命名空间中节点的加入、变更与离开
+func WithWatch() DiscoveryOption {
+	return func(o *DiscoveryOptions) {
+		o.Watch = true
+	}
+}
+
+// WithRealm 仅发现广播了指定 Realm 的节点
+func WithRealm(realmID types.RealmID) DiscoveryOption {
+	return func(o *DiscoveryOptions) {
+		o.Realm = realmID
+	}
+}
+
+// WithProtocols 仅发现支持全部指定协议的节点
+func WithProtocols(protocols ...string) DiscoveryOption {
+	return func(o *DiscoveryOptions) {
+		o.Protocols = append(o.Protocols, protocols...)
+	}
+}
+
+// WithRole 仅发现具有指定角色的节点（如 "relay"、"bootstrap"）
+func WithRole(role string) DiscoveryOption {
+	return func(o *DiscoveryOptions) {
+		o.Role = role
+	}
+}
+
+// WithAppVersion 仅发现应用版本一致的节点
+func WithAppVersion(version string) DiscoveryOption {
+	return func(o *DiscoveryOptions) {
+		o.AppVersion = version
+	}
+}
+
 // WithTTL 设置广播 TTL
 func WithTTL(ttl time.Duration) DiscoveryOption {
 	return func(o *DiscoveryOptions) {
@@ -67,12 +67,20 @@ type Host interface {
 	Connect(ctx context.Context, peerID string, addrs []string) error
 
 	// SetStreamHandler 为指定协议设置流处理器
-	SetStreamHandler(protocolID string, handler StreamHandler)
+	//
+	// 通过 WithIdempotent 声明协议幂等后，对端在 0-RTT 早期数据中打开的流
+	// 立即交给处理器；其他协议的入站流在握手确认后才交给处理器。
+	SetStreamHandler(protocolID string, handler StreamHandler, opts ...StreamHandlerOption)
 
 	// RemoveStreamHandler 移除指定协议的流处理器
 	RemoveStreamHandler(protocolID string)
 
 	// NewStream 创建到指定节点的新流（默认优先级）
+	//
+	// 多个协议 ID 按顺序协商，使用远端支持的第一个；Peerstore 中有
+	// identify 记录的对端协议时直接选择该协议，省去被拒绝变体的往返。
+	// 只有 ctx 经 WithEarlyData 标记时流才会在 0-RTT 早期数据中发送，
+	// 否则等待握手确认。
 	NewStream(ctx context.Context, peerID string, protocolIDs ...string) (Stream, error)
 
 	// NewStreamWithPriority 创建到指定节点的新流（指定优先级）(v1.2 新增)
@@ -120,6 +128,46 @@ type Host interface {
 // StreamHandler 定义流处理函数类型
 type StreamHandler func(Stream)
 
+// StreamHandlerOption 流处理器注册选项
+type StreamHandlerOption func(*StreamHandlerOptions)
+
+// StreamHandlerOptions 流处理器注册选项集合
+type StreamHandlerOptions struct {
+	// Idempotent 协议是否幂等
+	//
+	// 0-RTT 早期数据可能被攻击者重放，只有重复执行不会产生副作用的协议
+	// （如查询类协议）才应声明为幂等。
+	Idempotent bool
+}
+
+// WithIdempotent 声明协议幂等，允许在 0-RTT 早期数据中收发
+//
+// 这是服务端规则，只决定入站流是否需要等待握手确认，
+// 不影响本节点作为客户端打开的流（见 WithEarlyData）。
+func WithIdempotent() StreamHandlerOption {
+	return func(o *StreamHandlerOptions) {
+		o.Idempotent = true
+	}
+}
+
+// earlyDataKey WithEarlyData 使用的上下文键
+type earlyDataKey struct{}
+
+// WithEarlyData 标记在 ctx 中创建的流可以使用 0-RTT 早期数据
+//
+// 这是客户端规则：调用方确认本次请求可以安全重放时才应使用。
+// 对端未将协议声明为 WithIdempotent 时仍会等待握手确认后再处理，
+// 因此该标记只影响延迟，不会绕过服务端的重放保护。
+func WithEarlyData(ctx context.Context) context.Context {
+	return context.WithValue(ctx, earlyDataKey{}, true)
+}
+
+// EarlyDataAllowed 返回 ctx 是否经 WithEarlyData 标记
+func EarlyDataAllowed(ctx context.Context) bool {
+	allowed, _ := ctx.Value(earlyDataKey{}).(bool)
+	return allowed
+}
+
 // Stream 定义双向流接口
 type Stream interface {
 	// Read 从流中读取数据
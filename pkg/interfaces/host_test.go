@@ -45,7 +45,7 @@ func (m *MockHost) Connect(ctx context.Context, peerID string, addrs []string) e
 	return nil
 }
 
-func (m *MockHost) SetStreamHandler(protocolID string, handler interfaces.StreamHandler) {
+func (m *MockHost) SetStreamHandler(protocolID string, handler interfaces.StreamHandler, _ ...interfaces.StreamHandlerOption) {
 	// Mock implementation
 }
 
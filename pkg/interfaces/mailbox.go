@@ -0,0 +1,55 @@
+// Package interfaces 定义 DeP2P 公共接口
+//
+// 本文件定义 Mailbox 接口，提供离线消息的存储转发。
+package interfaces
+
+import (
+	"context"
+	"time"
+)
+
+// Mailbox 定义离线信箱服务接口
+//
+// Mailbox 允许向当前离线的 Realm 成员投递消息：发送方把端到端加密的
+// 消息存放到信箱节点（通常是 Relay/Gateway 角色的成员），接收方重新上线后
+// 从信箱节点拉取并确认。信箱节点只能看到密文。
+type Mailbox interface {
+	// Deposit 向信箱节点投递发往 recipient 的消息
+	//
+	// 消息使用 recipient 的公钥加密，ttl 为 0 时使用默认 TTL。
+	// 返回信箱分配的消息 ID。
+	Deposit(ctx context.Context, mailboxPeer, recipient string, data []byte, ttl time.Duration) (string, error)
+
+	// Fetch 从信箱节点拉取发给本节点的消息（已解密）
+	Fetch(ctx context.Context, mailboxPeer string) ([]*MailboxMessage, error)
+
+	// Ack 确认消息已处理，信箱节点随即删除这些消息
+	Ack(ctx context.Context, mailboxPeer string, ids []string) error
+
+	// SetMessageHandler 设置自动投递处理器
+	//
+	// 设置后，每当与配置的信箱节点建立连接，服务会自动拉取消息、
+	// 调用处理器并确认。
+	SetMessageHandler(handler MailboxHandler)
+}
+
+// MailboxHandler 离线消息处理函数类型
+type MailboxHandler func(msg *MailboxMessage)
+
+// MailboxMessage 离线消息
+type MailboxMessage struct {
+	// ID 信箱分配的消息 ID
+	ID string
+
+	// From 发送方节点 ID（已通过发送方身份签名校验）
+	From string
+
+	// Data 解密后的消息数据
+	Data []byte
+
+	// StoredAt 存入信箱的时间
+	StoredAt time.Time
+
+	// ExpiresAt 过期时间
+	ExpiresAt time.Time
+}
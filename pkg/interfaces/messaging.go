@@ -80,6 +80,96 @@ type Messaging interface {
 	Close() error
 }
 
+// ════════════════════════════════════════════════════════════════════════════
+// 持久化发件箱（可选能力）
+// ════════════════════════════════════════════════════════════════════════════
+
+// DurableMessaging 持久化发件箱接口
+//
+// 由启用了发件箱的 Messaging 服务实现（通过类型断言获取）。
+// 消息先写入存储引擎再异步投递，节点重启、对端离线都不会丢失：
+// 服务按退避间隔重试，并在与目标节点建立连接时立即重试，直到
+// 投递成功或过期（至少一次语义）。接收方按消息 ID 去重，
+// 重复投递不会再次调用处理器。
+type DurableMessaging interface {
+	// SendDurable 持久化消息并异步投递，返回消息 ID
+	//
+	// opts.IdempotencyKey 非空时作为消息 ID；同一 ID 的消息尚在发件箱中时
+	// 不会重复入队。
+	SendDurable(ctx context.Context, peerID string, protocol string, data []byte, opts DurableSendOptions) (string, error)
+
+	// OnDelivery 设置投递状态回调
+	OnDelivery(handler DeliveryHandler)
+
+	// Pending 返回发件箱中尚未投递的消息 ID
+	Pending() []string
+}
+
+// DurableSendOptions 持久化发送选项
+type DurableSendOptions struct {
+	// IdempotencyKey 幂等键（为空时自动生成）
+	IdempotencyKey string
+
+	// TTL 消息有效期（0 表示使用服务默认值），过期后不再重试；
+	// 超过服务的去重窗口时按去重窗口截断，保证窗口内恰好一次
+	TTL time.Duration
+}
+
+// DeliveryStatus 投递状态
+type DeliveryStatus int
+
+const (
+	// DeliveryRetrying 本次尝试失败，稍后重试
+	DeliveryRetrying DeliveryStatus = iota
+
+	// DeliveryDelivered 已送达（对端处理器已执行）
+	DeliveryDelivered
+
+	// DeliveryExpired 超过有效期仍未送达，已放弃
+	DeliveryExpired
+)
+
+// String 返回状态名称
+func (s DeliveryStatus) String() string {
+	switch s {
+	case DeliveryRetrying:
+		return "retrying"
+	case DeliveryDelivered:
+		return "delivered"
+	case DeliveryExpired:
+		return "expired"
+	default:
+		return "unknown"
+	}
+}
+
+// DeliveryReport 投递状态报告
+type DeliveryReport struct {
+	// ID 消息 ID
+	ID string
+
+	// PeerID 目标节点 ID
+	PeerID string
+
+	// Protocol 协议标识
+	Protocol string
+
+	// Status 投递状态
+	Status DeliveryStatus
+
+	// Attempts 已尝试次数
+	Attempts int
+
+	// Response 对端响应数据（仅 DeliveryDelivered）
+	Response []byte
+
+	// Error 本次尝试的错误；已送达时为对端处理器返回的错误
+	Error error
+}
+
+// DeliveryHandler 投递状态回调函数类型
+type DeliveryHandler func(report DeliveryReport)
+
 // ════════════════════════════════════════════════════════════════════════════
 // 批量发送结果类型 (v1.1 新增)
 // ════════════════════════════════════════════════════════════════════════════
@@ -35,6 +35,50 @@ type MuxedConn interface {
 	IsClosed() bool
 }
 
+// PriorityMuxedConn 支持流优先级调度的多路复用连接（可选接口）
+//
+// 实现方在底层连接拥塞时按流优先级调度写入，使 StreamPriority 在
+// TCP 和中继连接上同样生效。调用方通过类型断言检测。
+type PriorityMuxedConn interface {
+	MuxedConn
+
+	// OpenStreamWithPriority 打开指定优先级的新流
+	OpenStreamWithPriority(ctx context.Context, priority StreamPriority) (MuxedStream, error)
+
+	// SupportsStreamPriority 检查是否启用了优先级调度
+	SupportsStreamPriority() bool
+
+	// StreamPriorityStats 返回各优先级写队列的统计（未启用调度时返回 nil）
+	StreamPriorityStats() []StreamPriorityStats
+}
+
+// StreamPriorityStats 单个优先级写队列的统计
+type StreamPriorityStats struct {
+	// Priority 优先级
+	Priority StreamPriority
+
+	// QueuedFrames 当前排队的帧数
+	QueuedFrames int
+
+	// QueuedBytes 当前排队（含正在写入）的字节数
+	QueuedBytes int64
+
+	// SentFrames 累计发送帧数
+	SentFrames uint64
+
+	// SentBytes 累计发送字节数（含帧头）
+	SentBytes uint64
+
+	// Throttled 流写入因队列已满而等待的次数
+	Throttled uint64
+
+	// AvgQueueDelay 帧平均排队时间
+	AvgQueueDelay time.Duration
+
+	// MaxQueueDelay 帧最长排队时间
+	MaxQueueDelay time.Duration
+}
+
 // MuxedStream 定义多路复用流接口
 type MuxedStream interface {
 	// Read 从流中读取数据
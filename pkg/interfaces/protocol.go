@@ -5,6 +5,8 @@ package interfaces
 
 import (
 	"context"
+
+	"github.com/dep2p/go-dep2p/pkg/types"
 )
 
 // ProtocolID 协议标识符类型
@@ -48,6 +50,24 @@ type ProtocolNegotiator interface {
 	Handle(ctx context.Context, conn Connection) (ProtocolID, error)
 }
 
+// CapabilityProvider 本节点能力提供者
+//
+// 在生成 Identify 消息时调用，向能力块中填充所在子系统的能力。
+type CapabilityProvider func(caps *types.PeerCapabilities)
+
+// CapabilityRegistry 定义本节点能力注册接口
+//
+// 由 Identify 服务实现。各子系统（NAT、Relay、Realm、Bootstrap 等）
+// 注册能力提供者，能力通过 Identify 和 Identify Push 通告给对端；
+// 对端能力保存在 Peerstore 元数据 types.PeerCapabilitiesKey 中。
+type CapabilityRegistry interface {
+	// AddCapabilityProvider 注册能力提供者
+	AddCapabilityProvider(provider CapabilityProvider)
+
+	// LocalCapabilities 返回本节点当前能力
+	LocalCapabilities() *types.PeerCapabilities
+}
+
 // RealmProtocolID 生成 Realm 协议 ID
 func RealmProtocolID(realmID, protocol, version string) ProtocolID {
 	return ProtocolID("/dep2p/realm/" + realmID + "/" + protocol + "/" + version)
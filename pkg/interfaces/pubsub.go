@@ -92,6 +92,12 @@ type Message struct {
 	// RecvTimeNano 接收时间戳（纳秒，Unix 时间）
 	// 由接收方设置，用于计算 E2E 延迟
 	RecvTimeNano int64
+
+	// TraceParent 发布方的 W3C traceparent（未启用追踪时为空）
+	TraceParent string
+
+	// TraceState 发布方的 W3C tracestate
+	TraceState string
 }
 
 // PeerEvent 节点事件
@@ -120,6 +126,19 @@ type TopicOption func(*TopicOptions)
 type TopicOptions struct {
 	// WithRelay 是否启用中继
 	WithRelay bool
+
+	// Compression 发送该主题消息时提议的压缩算法（按偏好排序）
+	//
+	// 可选值 "zstd"、"snappy"。仅对支持压缩的节点生效，
+	// 其余节点回退到未压缩传输。
+	Compression []string
+}
+
+// WithTopicCompression 为主题启用压缩
+func WithTopicCompression(algs ...string) TopicOption {
+	return func(o *TopicOptions) {
+		o.Compression = append([]string(nil), algs...)
+	}
 }
 
 // PublishOption 发布选项
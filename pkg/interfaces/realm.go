@@ -7,6 +7,26 @@ import (
 	"context"
 )
 
+// RouteDialer 定义经由 Realm 成员多跳转发打开流的能力
+//
+// 当目标成员既无直连也无中继路径，或成员转发路径延迟更低时，
+// Messaging/Streams 通过此接口建立端到端加密的多跳流。
+// Realm 实现可选择实现此接口（通过类型断言检测）。
+type RouteDialer interface {
+	// NewRoutedStream 经由成员转发路径打开到 peerID 的流
+	//
+	// 中间成员只负责转发密文，无法读取流内容。
+	NewRoutedStream(ctx context.Context, peerID string, protocolID string) (Stream, error)
+
+	// NewRoutedStreamWithPriority 经由成员转发路径打开到 peerID 的流（指定优先级）
+	//
+	// 优先级随转发请求逐跳传递，每一跳都以该优先级打开到下一跳的流。
+	NewRoutedStreamWithPriority(ctx context.Context, peerID string, protocolID string, priority int) (Stream, error)
+
+	// ShouldRoute 判断成员转发路径是否优于直连路径
+	ShouldRoute(peerID string) bool
+}
+
 // Realm 定义隔离域接口
 //
 // Realm 是 DeP2P 的核心创新，提供独立的 P2P 子网络。
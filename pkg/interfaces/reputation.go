@@ -0,0 +1,144 @@
+// Package interfaces 定义 DeP2P 公共接口
+//
+// 本文件定义 Reputation 组件接口，对应 internal/core/reputation/ 实现。
+// 信誉服务汇总各子系统的节点质量信号，供连接裁剪、DHT 查询、中继选择和连接门控共同参考。
+package interfaces
+
+import "time"
+
+// ReputationSignal 信誉信号来源
+type ReputationSignal string
+
+const (
+	// ReputationSignalPubSub GossipSub 节点评分
+	ReputationSignalPubSub ReputationSignal = "pubsub"
+	// ReputationSignalConnMgr 连接管理器标签
+	ReputationSignalConnMgr ReputationSignal = "connmgr"
+	// ReputationSignalJitter 连接抖动（频繁断连、重连失败）
+	ReputationSignalJitter ReputationSignal = "jitter"
+	// ReputationSignalPathHealth 路径健康状态
+	ReputationSignalPathHealth ReputationSignal = "pathhealth"
+	// ReputationSignalWitness 见证报告（确认离线、被否决的报告）
+	ReputationSignalWitness ReputationSignal = "witness"
+	// ReputationSignalDial 拨号成功与失败
+	ReputationSignalDial ReputationSignal = "dial"
+)
+
+// ReputationReason 信誉分的组成项
+type ReputationReason struct {
+	// Signal 信号来源
+	Signal ReputationSignal `json:"signal"`
+
+	// Reason 最近一次更新的原因
+	Reason string `json:"reason"`
+
+	// Value 衰减后的信号值，范围 [-1, 1]
+	Value float64 `json:"value"`
+
+	// Contribution 加权后对总分的贡献
+	Contribution float64 `json:"contribution"`
+
+	// UpdatedAt 最近一次更新时间
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PeerReputation 节点信誉快照
+type PeerReputation struct {
+	// PeerID 节点 ID
+	PeerID string `json:"peer_id"`
+
+	// Score 统一信誉分（各信号加权和，未知节点为 0）
+	Score float64 `json:"score"`
+
+	// Banned 是否处于临时封禁
+	Banned bool `json:"banned"`
+
+	// BannedUntil 封禁到期时间（未封禁时为零值）
+	BannedUntil time.Time `json:"banned_until,omitempty"`
+
+	// Reasons 分数组成，按贡献绝对值降序
+	Reasons []ReputationReason `json:"reasons,omitempty"`
+}
+
+// Reputation 节点信誉服务接口
+//
+// 各子系统通过 Report（事件增量）或 Observe（当前水平）上报信号，
+// 信号值限制在 [-1, 1] 并按半衰期向 0 衰减，统一分为各信号按权重加权之和。
+// 分数低于封禁阈值的节点被临时封禁，到期自动解除；受保护节点和
+// 基础设施节点（引导节点、静态中继）只记录信号，不封禁。
+type Reputation interface {
+	// Report 上报事件信号，delta 累加到衰减后的当前值
+	Report(peerID string, signal ReputationSignal, delta float64, reason string)
+
+	// Observe 上报水平信号，value 替换该信号的当前值
+	Observe(peerID string, signal ReputationSignal, value float64, reason string)
+
+	// Score 返回节点的统一信誉分
+	Score(peerID string) float64
+
+	// IsBanned 检查节点是否处于临时封禁
+	IsBanned(peerID string) bool
+
+	// Get 返回节点的信誉快照
+	Get(peerID string) PeerReputation
+
+	// List 返回所有已记录节点的信誉快照，按分数升序
+	List() []PeerReputation
+
+	// Unban 解除节点封禁并清空其信号
+	Unban(peerID string)
+}
+
+// ReputationConfig 信誉服务配置
+type ReputationConfig struct {
+	// HalfLife 信号衰减半衰期
+	HalfLife time.Duration
+
+	// Weights 各信号权重（缺省的信号使用默认权重）
+	Weights map[ReputationSignal]float64
+
+	// BanThreshold 封禁阈值，分数低于该值时临时封禁（0 表示不封禁）
+	BanThreshold float64
+
+	// BanDuration 临时封禁时长
+	BanDuration time.Duration
+
+	// Persist 是否持久化到存储引擎
+	Persist bool
+
+	// MaxPeers 记录的节点数上限，超过时先淘汰分数最接近 0 的节点（0 表示使用默认值）
+	MaxPeers int
+
+	// ExemptPeers 不封禁的节点（引导节点、静态中继等基础设施节点）
+	ExemptPeers []string
+}
+
+// DefaultReputationMaxPeers 默认记录的节点数上限
+const DefaultReputationMaxPeers = 10000
+
+// DefaultReputationWeights 返回默认信号权重
+//
+// 权重之和为 100，统一分范围为 [-100, 100]。单纯离线的节点
+// （只有拨号失败）不会被封禁，需要多个子系统同时给出负面信号。
+func DefaultReputationWeights() map[ReputationSignal]float64 {
+	return map[ReputationSignal]float64{
+		ReputationSignalPubSub:     30,
+		ReputationSignalDial:       20,
+		ReputationSignalPathHealth: 15,
+		ReputationSignalWitness:    15,
+		ReputationSignalJitter:     10,
+		ReputationSignalConnMgr:    10,
+	}
+}
+
+// DefaultReputationConfig 返回默认信誉服务配置
+func DefaultReputationConfig() ReputationConfig {
+	return ReputationConfig{
+		HalfLife:     30 * time.Minute,
+		Weights:      DefaultReputationWeights(),
+		BanThreshold: -50,
+		BanDuration:  30 * time.Minute,
+		Persist:      false,
+		MaxPeers:     DefaultReputationMaxPeers,
+	}
+}
@@ -63,8 +63,20 @@ type SecureConnState struct {
 
 	// Opened 是否已完成握手
 	Opened bool
+
+	// KeyExchange 握手使用的密钥交换算法（KeyExchangeX25519 等）
+	KeyExchange string
 }
 
+// 密钥交换算法标识
+const (
+	// KeyExchangeX25519 经典 X25519 密钥交换
+	KeyExchangeX25519 = "x25519"
+
+	// KeyExchangeX25519MLKEM768 X25519 + ML-KEM-768 混合后量子密钥交换
+	KeyExchangeX25519MLKEM768 = "x25519mlkem768"
+)
+
 // SecurityMultiplexer 安全协议多路复用器
 type SecurityMultiplexer interface {
 	// SecureInbound 使用多路复用安全协议保护入站连接
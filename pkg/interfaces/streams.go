@@ -16,6 +16,7 @@ import (
 // StreamPriority 流优先级
 //
 // QUIC (RFC 9000) 原生支持流优先级，用于在网络拥塞时优先调度重要流。
+// 基于 yamux 的连接（TCP、中继电路）由多路复用器的写调度器实现同样的语义。
 // 优先级数值越小，优先级越高。
 type StreamPriority int
 
@@ -64,8 +65,14 @@ type StreamOptions struct {
 	// Priority 流优先级
 	//
 	// 默认为 StreamPriorityNormal。
-	// 仅在 QUIC 连接上生效，TCP 连接会忽略此选项。
+	// QUIC 连接使用传输层的流优先级；TCP 和中继连接由 yamux 写调度器
+	// 在底层连接拥塞时按优先级发送。
 	Priority StreamPriority
+
+	// Compression 提议的压缩算法（按偏好排序）
+	//
+	// 可选值 "zstd"、"snappy"。对端不支持时回退到未压缩传输。
+	Compression []string
 }
 
 // DefaultStreamOptions 返回默认流选项
@@ -160,6 +167,19 @@ type BiStream interface {
 	Stat() StreamStat
 }
 
+// ContextStream 携带追踪上下文的流（可选接口）
+//
+// 入站流经追踪变体协商时，Context 含打开方传来的追踪上下文，
+// 处理器可将其传给后续调用使调用链延续：
+//
+//	if cs, ok := stream.(interfaces.ContextStream); ok {
+//	    ctx = cs.Context()
+//	}
+type ContextStream interface {
+	// Context 返回流的上下文
+	Context() context.Context
+}
+
 // StreamStat 流统计信息
 type StreamStat struct {
 	// Direction 流方向
@@ -1,7 +1,7 @@
 // Package interfaces 定义 DeP2P 公共接口
 //
 // 本文件定义 Swarm 组件接口，对应 internal/core/swarm/ 实现。
-// 包括：Swarm（连接群管理）、BandwidthCounter（带宽统计）、PathHealthManager（路径健康）
+// 包括：Swarm（连接群管理）、DialRanker（拨号排序）、BandwidthCounter（带宽统计）、PathHealthManager（路径健康）
 package interfaces
 
 import (
@@ -107,6 +107,24 @@ type SwarmNotifier interface {
 	Disconnected(conn Connection)
 }
 
+// ════════════════════════════════════════════════════════════════════════════
+// DialRanker（Swarm 拨号策略）
+// 默认实现：internal/core/swarm/dial_ranker.go
+// ════════════════════════════════════════════════════════════════════════════
+
+// AddrDelay 带启动延迟的拨号地址
+type AddrDelay struct {
+	Addr  string
+	Delay time.Duration // 相对本次拨号开始的延迟
+}
+
+// DialRanker 拨号地址排序器
+//
+// 输入为已按路径健康度排序、过滤死亡路径后的直连地址，返回每个地址的
+// 拨号启动延迟。Swarm 按延迟错峰发起拨号，任一地址成功即取消其余拨号；
+// 返回列表中未出现的地址不会被拨号。
+type DialRanker func(addrs []string) []AddrDelay
+
 // ════════════════════════════════════════════════════════════════════════════
 // BandwidthCounter 接口（Swarm 子能力）
 // 实现位置：internal/core/swarm/bandwidth/
@@ -161,6 +179,188 @@ func DefaultBandwidthConfig() BandwidthConfig {
 	}
 }
 
+// ════════════════════════════════════════════════════════════════════════════
+// BandwidthLimiter 接口（Swarm 子能力）
+// 实现位置：internal/core/swarm/bandwidth/
+// ════════════════════════════════════════════════════════════════════════════
+
+// BandwidthLimit 单个范围的限速（令牌桶）
+type BandwidthLimit struct {
+	// In 下载（入站）速率上限，字节/秒，0 表示不限制
+	In int64 `json:"in"`
+
+	// Out 上传（出站）速率上限，字节/秒，0 表示不限制
+	Out int64 `json:"out"`
+
+	// Burst 突发容量（字节），0 时取 1 秒的速率
+	Burst int64 `json:"burst,omitempty"`
+}
+
+// IsZero 检查是否未设置任何限制
+func (l BandwidthLimit) IsZero() bool { return l.In <= 0 && l.Out <= 0 }
+
+// BandwidthLimits 带宽限速配置
+//
+// 一次读写需要同时满足所有适用范围的配额：节点总量、对端节点、协议、Realm。
+type BandwidthLimits struct {
+	// Total 节点总上传/下载上限
+	Total BandwidthLimit `json:"total"`
+
+	// PerPeer 每个对端节点的默认上限
+	PerPeer BandwidthLimit `json:"per_peer"`
+
+	// Peers 指定节点的上限（覆盖 PerPeer）
+	Peers map[string]BandwidthLimit `json:"peers,omitempty"`
+
+	// Protocols 按协议 ID 的上限
+	Protocols map[string]BandwidthLimit `json:"protocols,omitempty"`
+
+	// Realms 按 RealmID 的配额（作用于该 Realm 的所有 Realm/App 协议）
+	Realms map[string]BandwidthLimit `json:"realms,omitempty"`
+}
+
+// BandwidthLimitStatus 单个限速范围的状态
+type BandwidthLimitStatus struct {
+	// Scope 范围："total"、"peer"、"protocol" 或 "realm"
+	Scope string `json:"scope"`
+
+	// Key 范围内的键（节点 ID、协议 ID 或 RealmID，总量为空）
+	Key string `json:"key,omitempty"`
+
+	// Limit 当前限速
+	Limit BandwidthLimit `json:"limit"`
+
+	// Throttled 读写因配额不足而等待的次数
+	Throttled uint64 `json:"throttled"`
+
+	// Waited 累计等待时间
+	Waited time.Duration `json:"waited"`
+}
+
+// BandwidthLimiter 带宽限速器接口
+//
+// 在流读写层按令牌桶限速，运行时可调整。
+type BandwidthLimiter interface {
+	// WaitN 等待 dir 方向 size 字节的配额
+	//
+	// DirOutbound 为上传，DirInbound 为下载。
+	WaitN(ctx context.Context, dir Direction, size int, proto string, peer string) error
+
+	// SetLimits 替换全部限速配置
+	SetLimits(limits BandwidthLimits)
+
+	// Limits 返回当前限速配置
+	Limits() BandwidthLimits
+
+	// Status 返回各限速范围的状态
+	Status() []BandwidthLimitStatus
+
+	// TrimIdle 清理空闲的节点限速桶
+	TrimIdle(since time.Time)
+}
+
+// ════════════════════════════════════════════════════════════════════════════
+// DataBudget 接口（Swarm 子能力）
+// 实现位置：internal/core/swarm/bandwidth/
+// ════════════════════════════════════════════════════════════════════════════
+
+// DataBudgetMode 计费网络模式
+type DataBudgetMode int
+
+const (
+	// DataBudgetAuto 根据系统提示判断当前网络是否按流量计费（默认）
+	DataBudgetAuto DataBudgetMode = iota
+	// DataBudgetMetered 始终按计费网络处理（省流模式）
+	DataBudgetMetered
+	// DataBudgetUnmetered 始终按非计费网络处理
+	DataBudgetUnmetered
+)
+
+// String 返回模式名称
+func (m DataBudgetMode) String() string {
+	switch m {
+	case DataBudgetMetered:
+		return "metered"
+	case DataBudgetUnmetered:
+		return "unmetered"
+	default:
+		return "auto"
+	}
+}
+
+// 受数据预算约束的后台子系统
+const (
+	BudgetSubsystemDHT       = "dht"       // DHT 路由表刷新、地址变化续期
+	BudgetSubsystemPubSub    = "pubsub"    // GossipSub 心跳中的 Mesh 维护
+	BudgetSubsystemLiveness  = "liveness"  // 连接健康检测 Ping
+	BudgetSubsystemRelay     = "relay"     // 中继发现、发布与候选探测
+	BudgetSubsystemNetReport = "netreport" // 网络诊断的完整探测
+	BudgetSubsystemRealm     = "realm"     // Realm 成员心跳
+)
+
+// DataBudgetConfig 数据预算配置
+type DataBudgetConfig struct {
+	// Mode 计费网络模式
+	Mode DataBudgetMode
+
+	// DailyBudget 每日流量预算（字节，入+出），0 表示不设预算
+	DailyBudget int64
+}
+
+// DataBudgetThrottle 单个子系统的节流统计
+type DataBudgetThrottle struct {
+	// Subsystem 子系统名称
+	Subsystem string `json:"subsystem"`
+
+	// Skipped 被跳过的后台任务次数
+	Skipped uint64 `json:"skipped"`
+
+	// LastSkipped 最近一次跳过的时间
+	LastSkipped time.Time `json:"last_skipped"`
+}
+
+// DataBudgetStatus 数据预算状态
+type DataBudgetStatus struct {
+	// Mode 计费网络模式
+	Mode DataBudgetMode `json:"mode"`
+
+	// Metered 当前是否按计费网络处理
+	Metered bool `json:"metered"`
+
+	// DailyBudget 每日流量预算（字节），0 表示不设预算
+	DailyBudget int64 `json:"daily_budget"`
+
+	// UsedToday 今日已用流量（字节，入+出）
+	UsedToday int64 `json:"used_today"`
+
+	// Scale 后台任务间隔的放大倍数，1 表示未节流
+	Scale int `json:"scale"`
+
+	// Throttled 各子系统的节流统计（仅包含被跳过过的子系统）
+	Throttled []DataBudgetThrottle `json:"throttled,omitempty"`
+}
+
+// DataBudget 数据预算接口
+//
+// 计费网络（移动、卫星）下按每日流量预算放慢后台流量：DHT 刷新、
+// GossipSub 心跳、存活检测、中继发现、网络诊断和 Realm 成员心跳。用户流量不受影响。
+type DataBudget interface {
+	// Allow 报告子系统的后台任务本次是否执行
+	//
+	// task 区分同一子系统内的不同任务，interval 为任务的正常执行间隔。
+	// 节流时间隔按预算使用情况放大，未到期的调用返回 false 并计为一次跳过。
+	Allow(subsystem string, task string, interval time.Duration) bool
+
+	// SetMode 设置计费网络模式
+	SetMode(mode DataBudgetMode)
+
+	// SetDailyBudget 设置每日流量预算（字节），0 表示不设预算
+	SetDailyBudget(bytes int64)
+
+	// Status 返回数据预算状态
+	Status() DataBudgetStatus
+}
+
 // ════════════════════════════════════════════════════════════════════════════
 // PathHealthManager 接口（Swarm 子能力）
 // 实现位置：internal/core/swarm/pathhealth/
@@ -0,0 +1,67 @@
+// Package interfaces 定义 DeP2P 公共接口
+//
+// 本文件定义 Transfer 接口，提供可校验、可续传的大文件传输。
+package interfaces
+
+import "context"
+
+// Transfer 定义文件传输服务接口
+//
+// Transfer 构建在 Streams 之上，以内容寻址的方式在 Realm 成员之间传输文件：
+// 文件按固定大小分块，内容 ID（CID）由分块哈希的 Merkle 根派生，
+// 每个分块在写入磁盘前都会校验。持有同一内容的多个成员可以同时提供下载。
+type Transfer interface {
+	// Share 共享本地文件，返回内容 ID
+	//
+	// 文件会被分块并计算 Merkle 根，随后通过 DHT 宣告本节点为提供者。
+	// 共享期间文件内容不应被修改。
+	Share(ctx context.Context, path string) (string, error)
+
+	// Unshare 停止共享内容
+	Unshare(cid string) error
+
+	// Download 下载内容到 dest
+	//
+	// 数据先写入 dest + ".part"，全部分块校验通过后才重命名为 dest。
+	// 中断后以相同参数再次调用会从已校验的分块处继续。
+	Download(ctx context.Context, cid string, dest string, opts DownloadOptions) error
+
+	// Shared 返回本节点正在共享的内容 ID
+	Shared() []string
+}
+
+// DownloadOptions 下载选项
+type DownloadOptions struct {
+	// Providers 额外的提供者节点（与 DHT 查询结果合并，优先使用）
+	Providers []string
+
+	// MaxPeers 同时下载的最大提供者数（0 表示使用服务默认值）
+	MaxPeers int
+
+	// Progress 进度回调（每个分块校验通过后调用，需快速返回）
+	Progress func(TransferProgress)
+
+	// Seed 下载完成后是否继续共享该内容
+	Seed bool
+}
+
+// TransferProgress 传输进度
+type TransferProgress struct {
+	// CID 内容 ID
+	CID string
+
+	// TotalBytes 内容总字节数
+	TotalBytes int64
+
+	// DoneBytes 已校验的字节数（包含续传前已完成的部分）
+	DoneBytes int64
+
+	// TotalChunks 分块总数
+	TotalChunks int
+
+	// DoneChunks 已校验的分块数
+	DoneChunks int
+
+	// Peers 当前参与下载的提供者数
+	Peers int
+}
@@ -63,14 +63,14 @@ type Connection interface {
 
 	// NewStreamWithPriority 在此连接上创建新流（指定优先级）(v1.2 新增)
 	//
-	// 允许指定流优先级。在 QUIC 连接上，优先级会传递给底层传输层。
-	// 在 TCP 连接上，优先级会被忽略（优雅降级）。
+	// 允许指定流优先级。在 QUIC 连接上，优先级会传递给底层传输层；
+	// 在 TCP 和中继连接上，由 yamux 写调度器按优先级发送。
 	//
 	// 参数:
 	//   - ctx: 上下文
 	//   - priority: 流优先级 (0=Critical, 1=High, 2=Normal, 3=Low)
 	//
-	// 注意：大多数实现会调用 NewStream 并忽略优先级，仅 QUIC 实现会使用优先级。
+	// 注意：不支持优先级的实现会调用 NewStream 并忽略优先级。
 	NewStreamWithPriority(ctx context.Context, priority int) (Stream, error)
 
 	// AcceptStream 接受对方创建的流
@@ -90,8 +90,8 @@ type Connection interface {
 
 	// SupportsStreamPriority 检查连接是否支持流优先级 (v1.2 新增)
 	//
-	// 返回 true 表示此连接支持流优先级（如 QUIC 连接）。
-	// 返回 false 表示优先级会被忽略（如 TCP 连接）。
+	// 返回 true 表示此连接支持流优先级（如 QUIC 连接、启用写调度的 TCP 和中继连接）。
+	// 返回 false 表示优先级会被忽略。
 	SupportsStreamPriority() bool
 
 	// Close 关闭连接
@@ -101,6 +101,18 @@ type Connection interface {
 	IsClosed() bool
 }
 
+// EarlyDataConnection 支持 0-RTT 早期数据的连接（可选接口）
+//
+// 会话恢复时连接可在握手确认前收发数据，这些数据可能被重放，
+// 非幂等协议必须等待握手确认后再使用连接。
+type EarlyDataConnection interface {
+	// HandshakeConfirmed 握手是否已确认
+	HandshakeConfirmed() bool
+
+	// AwaitHandshake 阻塞直到握手确认，连接关闭或 ctx 结束时返回错误
+	AwaitHandshake(ctx context.Context) error
+}
+
 // ConnectionType 连接类型（v2.0 新增）
 type ConnectionType int
 
@@ -146,6 +158,18 @@ type ConnectionStat struct {
 
 	// NumStreams 流数量
 	NumStreams int
+
+	// Security 协商的安全协议（如 "/noise/1.0.0"、"/tls-pq/1.0.0"）
+	Security types.ProtocolID
+
+	// KeyExchange 握手使用的密钥交换算法（如 "x25519mlkem768"）
+	KeyExchange string
+
+	// Resumed 是否通过 TLS 会话票据恢复
+	Resumed bool
+
+	// EarlyData 是否使用了 0-RTT 早期数据
+	EarlyData bool
 }
 
 // TransportUpgrader 定义传输升级器接口
@@ -62,6 +62,10 @@ type UpgradedConn interface {
 	// Muxer 返回协商的多路复用器
 	// 例如："/yamux/1.0.0"
 	Muxer() string
+
+	// KeyExchange 返回握手使用的密钥交换算法
+	// 例如："x25519"、"x25519mlkem768"
+	KeyExchange() string
 }
 
 // UpgraderConfig 升级器配置
@@ -0,0 +1,188 @@
+package capture
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRecorder 创建并启动录制器
+func newTestRecorder(t *testing.T, cfg Config) *Recorder {
+	t.Helper()
+	if cfg.Path == "" {
+		cfg.Path = filepath.Join(t.TempDir(), "dep2p.capture")
+	}
+	r, err := NewRecorder(cfg)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(nil))
+	t.Cleanup(func() { r.Stop() })
+	return r
+}
+
+// echoHandler 读取全部输入后加前缀回写
+func echoHandler(s pkgif.Stream) {
+	defer s.Close()
+	data, _ := io.ReadAll(s)
+	s.Write(append([]byte("ack:"), data...))
+}
+
+func TestFilter_Match(t *testing.T) {
+	var none *Filter
+	assert.True(t, none.Match("a", "/x"))
+
+	f := &Filter{Peers: []string{"peer-a"}, Protocols: []string{"/dep2p/app/"}}
+	assert.True(t, f.Match("peer-a", "/dep2p/app/r1/chat/1.0.0"))
+	assert.False(t, f.Match("peer-b", "/dep2p/app/r1/chat/1.0.0"))
+	assert.False(t, f.Match("peer-a", "/dep2p/sys/identify/1.0.0"))
+}
+
+func TestRecorder_RecordAndRead(t *testing.T) {
+	r := newTestRecorder(t, Config{Filter: Filter{Protocols: []string{"/chat"}}})
+
+	s := r.Open("peer-a", "/chat/1.0.0", true)
+	require.NotNil(t, s)
+	s.Record(DirIn, []byte("hel"))
+	s.Record(DirIn, []byte("lo"))
+	s.Record(DirOut, []byte("ack:hello"))
+	s.Close(nil)
+	s.Close(nil)
+
+	// 不匹配过滤条件的流不录制
+	assert.Nil(t, r.Open("peer-a", "/other/1.0.0", true))
+
+	st := r.Status()
+	assert.True(t, st.Active)
+	assert.Equal(t, uint64(1), st.Streams)
+	assert.Equal(t, uint64(5), st.Frames)
+	require.NoError(t, r.Stop())
+	assert.False(t, r.Active())
+
+	// 停止后不再录制
+	assert.Nil(t, r.Open("peer-a", "/chat/1.0.0", true))
+
+	recs, err := ReadFile(r.cfg.Path)
+	require.NoError(t, err)
+	require.Len(t, recs, 1)
+	rec := recs[0]
+	assert.Equal(t, s.ID(), rec.ID)
+	assert.Equal(t, "peer-a", rec.Peer)
+	assert.Equal(t, "/chat/1.0.0", rec.Protocol)
+	assert.True(t, rec.Inbound)
+	assert.True(t, rec.Closed)
+	assert.Len(t, rec.Frames, 3)
+
+	in, err := rec.Payload(DirIn)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(in))
+}
+
+func TestRecorder_RedactAndTruncate(t *testing.T) {
+	r := newTestRecorder(t, Config{MaxPayload: 4, Redact: func(protocol string, _ Direction, p []byte) []byte {
+		if strings.HasPrefix(protocol, "/secret") {
+			return nil
+		}
+		return p
+	}})
+
+	secret := r.Open("peer-a", "/secret/1.0.0", true)
+	secret.Record(DirIn, []byte("password"))
+	long := r.Open("peer-a", "/chat/1.0.0", true)
+	long.Record(DirIn, []byte("0123456789"))
+	require.NoError(t, r.Stop())
+
+	recs, err := ReadFile(r.cfg.Path)
+	require.NoError(t, err)
+	require.Len(t, recs, 2)
+
+	f := recs[0].Frames[0]
+	assert.True(t, f.Redacted)
+	assert.Empty(t, f.Data)
+	assert.Equal(t, 8, f.Len)
+
+	f = recs[1].Frames[0]
+	assert.False(t, f.Redacted)
+	assert.Equal(t, "0123", string(f.Data))
+	assert.Equal(t, 10, recs[1].Bytes(DirIn))
+
+	for _, rec := range recs {
+		_, err := rec.Payload(DirIn)
+		assert.ErrorIs(t, err, ErrIncomplete)
+		_, err = Replay(context.Background(), rec, echoHandler)
+		assert.ErrorIs(t, err, ErrIncomplete)
+	}
+}
+
+func TestRecorder_Rotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dep2p.capture")
+	r := newTestRecorder(t, Config{Path: path, MaxFileSize: 512, MaxFiles: 2})
+
+	payload := bytes.Repeat([]byte("x"), 100)
+	for i := 0; i < 20; i++ {
+		s := r.Open("peer-a", "/chat/1.0.0", true)
+		s.Record(DirIn, payload)
+		s.Close(nil)
+	}
+	require.NoError(t, r.Stop())
+
+	files := Files(path)
+	assert.Equal(t, []string{path + ".2", path + ".1", path}, files)
+
+	// 只保留最近的流，最新的流在最后
+	recs, err := ReadFile(path)
+	require.NoError(t, err)
+	assert.Less(t, len(recs), 20)
+	assert.True(t, strings.HasSuffix(recs[len(recs)-1].ID, "-20"))
+}
+
+func TestReplay(t *testing.T) {
+	r := newTestRecorder(t, Config{})
+	s := r.Open("peer-a", "/chat/1.0.0", true)
+	s.Record(DirIn, []byte("hello"))
+	s.Record(DirOut, []byte("ack:hello"))
+	s.Close(nil)
+	out := r.Open("peer-b", "/chat/1.0.0", false)
+	out.Record(DirOut, []byte("hi"))
+	require.NoError(t, r.Stop())
+
+	recs, err := ReadFile(r.cfg.Path)
+	require.NoError(t, err)
+	require.Len(t, recs, 2)
+
+	var peer string
+	res, err := Replay(context.Background(), recs[0], func(s pkgif.Stream) {
+		peer = string(s.Conn().RemotePeer())
+		echoHandler(s)
+	})
+	require.NoError(t, err)
+	assert.True(t, res.Matches())
+	assert.Equal(t, "peer-a", peer)
+
+	// 处理器行为改变后输出不一致
+	res, err = Replay(context.Background(), recs[0], func(s pkgif.Stream) {
+		io.ReadAll(s)
+		s.Write([]byte("nack"))
+	})
+	require.NoError(t, err)
+	assert.False(t, res.Matches())
+	assert.Equal(t, "nack", string(res.Output))
+
+	// 出站流不能回放
+	_, err = Replay(context.Background(), recs[1], echoHandler)
+	assert.ErrorIs(t, err, ErrNotInbound)
+
+	// 处理器阻塞时按 ctx 结束
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	block := make(chan struct{})
+	defer close(block)
+	_, err = Replay(ctx, recs[0], func(pkgif.Stream) { <-block })
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
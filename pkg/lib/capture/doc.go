@@ -0,0 +1,38 @@
+// Package capture 录制与回放协议流量
+//
+// 录制器挂在 Host 的流层，在协议协商完成后记录每条流解密后的读写数据，
+// 用于在现场复现协议问题。录制默认关闭，可通过配置、自省服务或
+// Recorder.Start 按需开启。
+//
+// # 录制文件
+//
+// JSON 行格式，每行一个 Frame：
+//
+//	{"stream":"s1a2b-1","event":"open","ts":...,"peer":"12D3...","protocol":"/dep2p/app/r1/chat/1.0.0","inbound":true}
+//	{"stream":"s1a2b-1","event":"data","ts":...,"dir":"in","len":5,"data":"aGVsbG8="}
+//	{"stream":"s1a2b-1","event":"data","ts":...,"dir":"out","len":5,"data":"aGVsbG8="}
+//	{"stream":"s1a2b-1","event":"close","ts":...}
+//
+// 文件超过 MaxFileSize 后轮转为 path.1、path.2…，最多保留 MaxFiles 个。
+// 单帧超过 MaxPayload 的部分截断，Redact 可对数据脱敏（如 RedactAll
+// 只记录长度）。截断或脱敏的录制只能用于分析，不能回放。
+//
+// # 过滤
+//
+// Filter 按节点和协议前缀选择要录制的流。未录制或不匹配的流只有一次
+// 原子读取和一次过滤判断的开销。
+//
+// # 回放
+//
+//	recs, _ := capture.ReadFile("/tmp/dep2p.capture")
+//	for _, rec := range capture.Select(recs, &capture.Filter{Protocols: []string{"/dep2p/app/r1/chat/"}}) {
+//	    res, err := capture.Replay(ctx, rec, myHandler)
+//	    if err == nil && !res.Matches() {
+//	        fmt.Printf("%s: 响应不一致\n", rec.ID)
+//	    }
+//	}
+//
+// Replay 把入站录制中远端发来的数据按原分帧交给处理器，并收集处理器
+// 的输出与录制时的响应比较。回放流的 Conn 只提供 RemotePeer，其余
+// 连接操作返回错误。
+package capture
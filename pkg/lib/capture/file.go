@@ -0,0 +1,108 @@
+package capture
+
+import (
+	"fmt"
+	"os"
+)
+
+// rotatingFile 按大小轮转的追加写文件
+//
+// 当前文件为 path，轮转后依次为 path.1（最新）… path.N（最旧），
+// 超出 maxFiles 的旧文件被删除。
+type rotatingFile struct {
+	path     string
+	maxSize  int64
+	maxFiles int
+
+	file *os.File
+	size int64
+}
+
+// openRotating 以追加模式打开录制文件
+func openRotating(path string, maxSize int64, maxFiles int) (*rotatingFile, error) {
+	rf := &rotatingFile{path: path, maxSize: maxSize, maxFiles: maxFiles}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+// open 打开当前文件
+func (rf *rotatingFile) open() error {
+	f, err := os.OpenFile(rf.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	rf.file = f
+	rf.size = info.Size()
+	return nil
+}
+
+// Write 写入一条记录，写入前超过大小上限则先轮转
+//
+// 单条记录不会跨文件。
+func (rf *rotatingFile) Write(p []byte) (int, error) {
+	if rf.maxSize > 0 && rf.size > 0 && rf.size+int64(len(p)) > rf.maxSize {
+		if err := rf.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := rf.file.Write(p)
+	rf.size += int64(n)
+	return n, err
+}
+
+// rotate 关闭当前文件并依次重命名旧文件
+func (rf *rotatingFile) rotate() error {
+	if err := rf.file.Close(); err != nil {
+		return err
+	}
+	if rf.maxFiles <= 0 {
+		if err := os.Remove(rf.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return rf.open()
+	}
+
+	os.Remove(rotatedName(rf.path, rf.maxFiles))
+	for i := rf.maxFiles - 1; i >= 1; i-- {
+		os.Rename(rotatedName(rf.path, i), rotatedName(rf.path, i+1))
+	}
+	if err := os.Rename(rf.path, rotatedName(rf.path, 1)); err != nil {
+		return err
+	}
+	return rf.open()
+}
+
+// Close 关闭文件
+func (rf *rotatingFile) Close() error {
+	return rf.file.Close()
+}
+
+// rotatedName 返回第 i 个轮转文件名
+func rotatedName(path string, i int) string {
+	return fmt.Sprintf("%s.%d", path, i)
+}
+
+// Files 返回录制文件及其轮转文件，按时间从旧到新排列
+//
+// 不存在的文件被跳过。
+func Files(path string) []string {
+	var files []string
+	for i := 1; ; i++ {
+		name := rotatedName(path, i)
+		if _, err := os.Stat(name); err != nil {
+			break
+		}
+		files = append([]string{name}, files...)
+	}
+	if _, err := os.Stat(path); err == nil {
+		files = append(files, path)
+	}
+	return files
+}
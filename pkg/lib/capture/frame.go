@@ -0,0 +1,95 @@
+package capture
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrNoPath 未指定录制文件
+	ErrNoPath = errors.New("capture: file path is required")
+
+	// ErrIncomplete 录制内容不完整（已脱敏或被截断），无法回放
+	ErrIncomplete = errors.New("capture: recording is redacted or truncated")
+
+	// ErrNotInbound 只有入站流的录制可以回放到处理器
+	ErrNotInbound = errors.New("capture: only inbound recordings can be replayed")
+
+	// ErrNoHandler 录制的协议没有注册处理器
+	ErrNoHandler = errors.New("capture: no handler for protocol")
+)
+
+// Direction 数据帧方向
+type Direction string
+
+const (
+	// DirIn 从远端读取的数据
+	DirIn Direction = "in"
+
+	// DirOut 写往远端的数据
+	DirOut Direction = "out"
+)
+
+// Event 帧事件类型
+type Event string
+
+const (
+	// EventOpen 流开始录制（协议协商完成）
+	EventOpen Event = "open"
+
+	// EventData 读写数据
+	EventData Event = "data"
+
+	// EventClose 流关闭或重置
+	EventClose Event = "close"
+)
+
+// Frame 录制文件中的一条记录
+//
+// 录制文件为 JSON 行格式，每行一个 Frame，Data 以 base64 编码。
+// Peer、Protocol、Inbound 只出现在 open 帧上，Dir、Len、Data 只出现在
+// data 帧上。
+type Frame struct {
+	// Stream 流标识，在同一录制文件内唯一
+	Stream string `json:"stream"`
+
+	// Event 事件类型
+	Event Event `json:"event"`
+
+	// Time 时间戳（Unix 纳秒）
+	Time int64 `json:"ts"`
+
+	// Peer 远端节点 ID
+	Peer string `json:"peer,omitempty"`
+
+	// Protocol 协商后的协议 ID
+	Protocol string `json:"protocol,omitempty"`
+
+	// Inbound 是否为入站流（远端打开）
+	Inbound bool `json:"inbound,omitempty"`
+
+	// Dir 数据方向
+	Dir Direction `json:"dir,omitempty"`
+
+	// Len 原始数据长度
+	Len int `json:"len,omitempty"`
+
+	// Data 记录的数据（脱敏时为空，截断时短于 Len）
+	Data []byte `json:"data,omitempty"`
+
+	// Redacted 数据已脱敏
+	Redacted bool `json:"redacted,omitempty"`
+
+	// Error 流异常关闭原因（仅 close 帧）
+	Error string `json:"error,omitempty"`
+}
+
+// Timestamp 返回帧时间
+func (f *Frame) Timestamp() time.Time {
+	return time.Unix(0, f.Time)
+}
+
+// Complete 数据帧是否完整记录了原始数据
+func (f *Frame) Complete() bool {
+	return !f.Redacted && len(f.Data) == f.Len
+}
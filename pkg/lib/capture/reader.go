@@ -0,0 +1,148 @@
+package capture
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// maxLineSize 单行记录上限（base64 后的最大帧加余量）
+const maxLineSize = 4 << 20
+
+// Recording 一条流的完整录制
+type Recording struct {
+	// ID 流标识
+	ID string
+
+	// Peer 远端节点 ID（open 帧已轮转删除时为空）
+	Peer string
+
+	// Protocol 协商后的协议 ID
+	Protocol string
+
+	// Inbound 是否为入站流
+	Inbound bool
+
+	// Opened 开始录制时间
+	Opened time.Time
+
+	// Frames 数据帧（按录制顺序）
+	Frames []Frame
+
+	// Closed 是否记录到关闭
+	Closed bool
+
+	// Error 异常关闭原因
+	Error string
+}
+
+// Payload 返回指定方向的全部数据
+//
+// 存在脱敏或截断的帧时返回 ErrIncomplete。
+func (r *Recording) Payload(dir Direction) ([]byte, error) {
+	var out []byte
+	for i := range r.Frames {
+		f := &r.Frames[i]
+		if f.Dir != dir {
+			continue
+		}
+		if !f.Complete() {
+			return nil, ErrIncomplete
+		}
+		out = append(out, f.Data...)
+	}
+	return out, nil
+}
+
+// Bytes 返回指定方向的原始字节数（含未记录部分）
+func (r *Recording) Bytes(dir Direction) int {
+	n := 0
+	for i := range r.Frames {
+		if r.Frames[i].Dir == dir {
+			n += r.Frames[i].Len
+		}
+	}
+	return n
+}
+
+// Decode 从 JSON 行流中读取录制，按流分组
+//
+// 返回顺序为各流第一次出现的顺序。
+func Decode(r io.Reader) ([]*Recording, error) {
+	return decode(r, nil, nil)
+}
+
+// decode 解码并合并到已有结果
+func decode(r io.Reader, recs []*Recording, byID map[string]*Recording) ([]*Recording, error) {
+	if byID == nil {
+		byID = make(map[string]*Recording)
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), maxLineSize)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var f Frame
+		if err := json.Unmarshal(scanner.Bytes(), &f); err != nil {
+			return recs, fmt.Errorf("capture: line %d: %w", line, err)
+		}
+		rec, ok := byID[f.Stream]
+		if !ok {
+			rec = &Recording{ID: f.Stream}
+			byID[f.Stream] = rec
+			recs = append(recs, rec)
+		}
+		switch f.Event {
+		case EventOpen:
+			rec.Peer = f.Peer
+			rec.Protocol = f.Protocol
+			rec.Inbound = f.Inbound
+			rec.Opened = f.Timestamp()
+		case EventData:
+			rec.Frames = append(rec.Frames, f)
+		case EventClose:
+			rec.Closed = true
+			rec.Error = f.Error
+		}
+	}
+	return recs, scanner.Err()
+}
+
+// ReadFile 读取录制文件及其轮转文件（按时间从旧到新）
+func ReadFile(path string) ([]*Recording, error) {
+	files := Files(path)
+	if len(files) == 0 {
+		return nil, fmt.Errorf("capture: %s: %w", path, os.ErrNotExist)
+	}
+	var recs []*Recording
+	byID := make(map[string]*Recording)
+	for _, name := range files {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		recs, err = decode(f, recs, byID)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return recs, nil
+}
+
+// Select 按过滤条件筛选录制
+func Select(recs []*Recording, filter *Filter) []*Recording {
+	var out []*Recording
+	for _, rec := range recs {
+		if filter.Match(rec.Peer, rec.Protocol) {
+			out = append(out, rec)
+		}
+	}
+	return out
+}
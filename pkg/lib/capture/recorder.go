@@ -0,0 +1,330 @@
+package capture
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dep2p/go-dep2p/pkg/lib/log"
+)
+
+var logger = log.Logger("lib/capture")
+
+// 默认值
+const (
+	// DefaultMaxFileSize 单个录制文件的默认大小上限（64 MiB）
+	DefaultMaxFileSize = 64 << 20
+
+	// DefaultMaxFiles 默认保留的轮转文件数
+	DefaultMaxFiles = 3
+
+	// DefaultMaxPayload 单帧默认最多记录的字节数（64 KiB）
+	DefaultMaxPayload = 64 << 10
+)
+
+// RedactFunc 脱敏函数
+//
+// 返回要记录的数据；返回 nil 表示只记录长度，不记录内容。
+// 返回内容与原始数据不同时该帧标记为已脱敏，不能用于回放。
+// 函数不得修改 p。
+type RedactFunc func(protocol string, dir Direction, p []byte) []byte
+
+// RedactAll 不记录任何数据内容，只记录长度
+func RedactAll(string, Direction, []byte) []byte {
+	return nil
+}
+
+// Filter 录制过滤条件
+//
+// Peers 和 Protocols 为空表示不限制。Protocols 按前缀匹配，
+// 因此 "/dep2p/app/" 可匹配所有应用协议。
+type Filter struct {
+	Peers     []string `json:"peers,omitempty"`
+	Protocols []string `json:"protocols,omitempty"`
+}
+
+// Match 判断流是否需要录制
+func (f *Filter) Match(peer, protocol string) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.Peers) > 0 {
+		found := false
+		for _, p := range f.Peers {
+			if p == peer {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(f.Protocols) > 0 {
+		for _, prefix := range f.Protocols {
+			if strings.HasPrefix(protocol, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Config 录制器配置
+type Config struct {
+	// Path 录制文件路径
+	Path string
+
+	// MaxFileSize 单个文件大小上限，超过后轮转（字节）
+	MaxFileSize int64
+
+	// MaxFiles 保留的轮转文件数
+	MaxFiles int
+
+	// MaxPayload 单帧最多记录的字节数，超出部分截断（0 表示使用默认值）
+	MaxPayload int
+
+	// Filter 默认过滤条件
+	Filter Filter
+
+	// Redact 脱敏函数（nil 表示记录原始数据）
+	Redact RedactFunc
+}
+
+// Status 录制器状态
+type Status struct {
+	Active    bool      `json:"active"`
+	Path      string    `json:"path"`
+	Filter    Filter    `json:"filter"`
+	StartedAt time.Time `json:"started_at,omitempty"`
+	Streams   uint64    `json:"streams"`
+	Frames    uint64    `json:"frames"`
+	Bytes     uint64    `json:"bytes"`
+	Errors    uint64    `json:"errors"`
+}
+
+// Recorder 协议流量录制器
+//
+// 录制器挂在 Host 的流层：协议协商完成后，匹配过滤条件的流的每次
+// 读写都被记录为一帧。未启动时 Open 只做一次原子读取，开销可忽略。
+type Recorder struct {
+	cfg Config
+
+	active atomic.Bool
+	filter atomic.Pointer[Filter]
+	run    atomic.Int64
+	seq    atomic.Uint64
+
+	mu        sync.Mutex
+	out       *rotatingFile
+	startedAt time.Time
+
+	streams atomic.Uint64
+	frames  atomic.Uint64
+	bytes   atomic.Uint64
+	errors  atomic.Uint64
+}
+
+// NewRecorder 创建录制器（未启动）
+func NewRecorder(cfg Config) (*Recorder, error) {
+	if cfg.Path == "" {
+		return nil, ErrNoPath
+	}
+	if cfg.MaxFileSize <= 0 {
+		cfg.MaxFileSize = DefaultMaxFileSize
+	}
+	if cfg.MaxFiles < 0 {
+		cfg.MaxFiles = 0
+	}
+	if cfg.MaxPayload <= 0 {
+		cfg.MaxPayload = DefaultMaxPayload
+	}
+	r := &Recorder{cfg: cfg}
+	filter := cfg.Filter
+	r.filter.Store(&filter)
+	return r, nil
+}
+
+// Start 开始录制
+//
+// filter 非 nil 时替换当前过滤条件。已在录制时只更新过滤条件。
+func (r *Recorder) Start(filter *Filter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if filter != nil {
+		f := *filter
+		r.filter.Store(&f)
+	}
+	if r.out != nil {
+		return nil
+	}
+
+	out, err := openRotating(r.cfg.Path, r.cfg.MaxFileSize, r.cfg.MaxFiles)
+	if err != nil {
+		return err
+	}
+	r.out = out
+	r.startedAt = time.Now()
+	r.run.Store(r.startedAt.UnixNano())
+	r.active.Store(true)
+	logger.Info("开始录制协议流量", "path", r.cfg.Path)
+	return nil
+}
+
+// Stop 停止录制并关闭文件
+//
+// 正在录制的流的后续读写不再记录。
+func (r *Recorder) Stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.out == nil {
+		return nil
+	}
+	r.active.Store(false)
+	err := r.out.Close()
+	r.out = nil
+	logger.Info("停止录制协议流量", "path", r.cfg.Path, "frames", r.frames.Load())
+	return err
+}
+
+// Active 是否正在录制
+func (r *Recorder) Active() bool {
+	return r.active.Load()
+}
+
+// Status 返回录制器状态
+func (r *Recorder) Status() Status {
+	r.mu.Lock()
+	startedAt := r.startedAt
+	r.mu.Unlock()
+
+	st := Status{
+		Active:  r.active.Load(),
+		Path:    r.cfg.Path,
+		Streams: r.streams.Load(),
+		Frames:  r.frames.Load(),
+		Bytes:   r.bytes.Load(),
+		Errors:  r.errors.Load(),
+	}
+	if f := r.filter.Load(); f != nil {
+		st.Filter = *f
+	}
+	if st.Active {
+		st.StartedAt = startedAt
+	}
+	return st
+}
+
+// Open 开始录制一条流
+//
+// 未在录制或流不匹配过滤条件时返回 nil（nil *Session 的方法均为空操作）。
+func (r *Recorder) Open(peer, protocol string, inbound bool) *Session {
+	if r == nil || !r.active.Load() {
+		return nil
+	}
+	if !r.filter.Load().Match(peer, protocol) {
+		return nil
+	}
+
+	id := strconv.FormatInt(r.run.Load(), 36) + "-" + strconv.FormatUint(r.seq.Add(1), 10)
+	s := &Session{recorder: r, id: id, protocol: protocol}
+	r.streams.Add(1)
+	r.write(&Frame{
+		Stream:   id,
+		Event:    EventOpen,
+		Time:     time.Now().UnixNano(),
+		Peer:     peer,
+		Protocol: protocol,
+		Inbound:  inbound,
+	})
+	return s
+}
+
+// write 编码并写入一帧
+func (r *Recorder) write(f *Frame) {
+	line, err := json.Marshal(f)
+	if err != nil {
+		r.errors.Add(1)
+		return
+	}
+	line = append(line, '\n')
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.out == nil {
+		return
+	}
+	if _, err := r.out.Write(line); err != nil {
+		r.errors.Add(1)
+		logger.Debug("写入录制文件失败", "error", err)
+		return
+	}
+	r.frames.Add(1)
+	r.bytes.Add(uint64(len(line)))
+}
+
+// Session 一条正在录制的流
+type Session struct {
+	recorder *Recorder
+	id       string
+	protocol string
+	closed   atomic.Bool
+}
+
+// ID 返回流标识
+func (s *Session) ID() string {
+	if s == nil {
+		return ""
+	}
+	return s.id
+}
+
+// Record 记录一次读写
+func (s *Session) Record(dir Direction, p []byte) {
+	if s == nil || len(p) == 0 || s.closed.Load() || !s.recorder.active.Load() {
+		return
+	}
+	cfg := &s.recorder.cfg
+	f := &Frame{
+		Stream: s.id,
+		Event:  EventData,
+		Time:   time.Now().UnixNano(),
+		Dir:    dir,
+		Len:    len(p),
+	}
+	data := p
+	if cfg.Redact != nil {
+		data = cfg.Redact(s.protocol, dir, p)
+		f.Redacted = data == nil || !bytes.Equal(data, p)
+	}
+	if len(data) > cfg.MaxPayload {
+		data = data[:cfg.MaxPayload]
+	}
+	// 编码在 write 内同步完成，无需复制
+	f.Data = data
+	s.recorder.write(f)
+}
+
+// Close 记录流关闭，err 非空表示异常关闭
+//
+// 重复调用只有第一次生效。
+func (s *Session) Close(err error) {
+	if s == nil || !s.closed.CompareAndSwap(false, true) {
+		return
+	}
+	if !s.recorder.active.Load() {
+		return
+	}
+	f := &Frame{Stream: s.id, Event: EventClose, Time: time.Now().UnixNano()}
+	if err != nil {
+		f.Error = err.Error()
+	}
+	s.recorder.write(f)
+}
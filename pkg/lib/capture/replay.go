@@ -0,0 +1,258 @@
+package capture
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/types"
+)
+
+// errReplayReset 回放流已重置
+var errReplayReset = errors.New("capture: replay stream reset")
+
+// ReplayResult 回放结果
+type ReplayResult struct {
+	// Recording 回放的录制
+	Recording *Recording
+
+	// Output 处理器写出的数据
+	Output []byte
+
+	// Expected 录制时写出的数据（录制不完整时为 nil）
+	Expected []byte
+}
+
+// Matches 处理器输出是否与录制时一致
+func (r *ReplayResult) Matches() bool {
+	return r.Expected != nil && bytes.Equal(r.Output, r.Expected)
+}
+
+// Replay 把入站录制回放到处理器
+//
+// 处理器从流中按录制时的分帧读到远端发来的数据，读完后返回 io.EOF；
+// 处理器写出的数据收集到 Output，可与录制时的响应比较。
+// 处理器返回或 ctx 结束时回放结束，ctx 结束时流被重置并返回 ctx 的错误。
+func Replay(ctx context.Context, rec *Recording, handler pkgif.StreamHandler) (*ReplayResult, error) {
+	if !rec.Inbound {
+		return nil, ErrNotInbound
+	}
+	if handler == nil {
+		return nil, ErrNoHandler
+	}
+	var chunks [][]byte
+	for i := range rec.Frames {
+		f := &rec.Frames[i]
+		if f.Dir != DirIn {
+			continue
+		}
+		if !f.Complete() {
+			return nil, ErrIncomplete
+		}
+		chunks = append(chunks, f.Data)
+	}
+	expected, err := rec.Payload(DirOut)
+	if err != nil {
+		expected = nil
+	} else if expected == nil {
+		expected = []byte{}
+	}
+
+	stream := newReplayStream(rec, chunks)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler(stream)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		stream.Reset()
+		err = ctx.Err()
+	}
+	return &ReplayResult{Recording: rec, Output: stream.output(), Expected: expected}, err
+}
+
+// ============================================================================
+//                              回放流
+// ============================================================================
+
+// replayStream 以录制数据为输入的内存流
+type replayStream struct {
+	conn   *replayConn
+	opened time.Time
+
+	mu       sync.Mutex
+	protocol string
+	chunks   [][]byte
+	out      bytes.Buffer
+	read     int64
+	state    types.StreamState
+}
+
+var _ pkgif.Stream = (*replayStream)(nil)
+
+func newReplayStream(rec *Recording, chunks [][]byte) *replayStream {
+	return &replayStream{
+		conn:     &replayConn{peer: types.PeerID(rec.Peer)},
+		opened:   time.Now(),
+		protocol: rec.Protocol,
+		chunks:   chunks,
+	}
+}
+
+// output 返回处理器写出的数据副本
+func (s *replayStream) output() []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]byte{}, s.out.Bytes()...)
+}
+
+func (s *replayStream) Read(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == types.StreamStateReset {
+		return 0, errReplayReset
+	}
+	if len(s.chunks) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, s.chunks[0])
+	if n == len(s.chunks[0]) {
+		s.chunks = s.chunks[1:]
+	} else {
+		s.chunks[0] = s.chunks[0][n:]
+	}
+	s.read += int64(n)
+	return n, nil
+}
+
+func (s *replayStream) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch s.state {
+	case types.StreamStateReset:
+		return 0, errReplayReset
+	case types.StreamStateWriteClosed, types.StreamStateClosed:
+		return 0, io.ErrClosedPipe
+	}
+	return s.out.Write(p)
+}
+
+func (s *replayStream) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != types.StreamStateReset {
+		s.state = types.StreamStateClosed
+	}
+	return nil
+}
+
+func (s *replayStream) CloseWrite() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == types.StreamStateOpen {
+		s.state = types.StreamStateWriteClosed
+	} else if s.state == types.StreamStateReadClosed {
+		s.state = types.StreamStateClosed
+	}
+	return nil
+}
+
+func (s *replayStream) CloseRead() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == types.StreamStateOpen {
+		s.state = types.StreamStateReadClosed
+	} else if s.state == types.StreamStateWriteClosed {
+		s.state = types.StreamStateClosed
+	}
+	return nil
+}
+
+func (s *replayStream) Reset() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = types.StreamStateReset
+	return nil
+}
+
+func (s *replayStream) SetDeadline(time.Time) error      { return nil }
+func (s *replayStream) SetReadDeadline(time.Time) error  { return nil }
+func (s *replayStream) SetWriteDeadline(time.Time) error { return nil }
+
+func (s *replayStream) Protocol() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.protocol
+}
+
+func (s *replayStream) SetProtocol(protocol string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.protocol = protocol
+}
+
+func (s *replayStream) Conn() pkgif.Connection { return s.conn }
+
+func (s *replayStream) IsClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state == types.StreamStateClosed || s.state == types.StreamStateReset
+}
+
+func (s *replayStream) Stat() types.StreamStat {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return types.StreamStat{
+		Direction:    types.DirInbound,
+		Opened:       s.opened,
+		Protocol:     types.ProtocolID(s.protocol),
+		BytesRead:    s.read,
+		BytesWritten: int64(s.out.Len()),
+	}
+}
+
+func (s *replayStream) State() types.StreamState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
+// replayConn 回放流的连接，只提供远端节点 ID
+type replayConn struct {
+	peer types.PeerID
+}
+
+var _ pkgif.Connection = (*replayConn)(nil)
+
+// errReplayConn 回放连接不支持的操作
+var errReplayConn = errors.New("capture: operation not supported on replay connection")
+
+func (c *replayConn) LocalPeer() types.PeerID             { return "" }
+func (c *replayConn) LocalMultiaddr() types.Multiaddr     { return nil }
+func (c *replayConn) RemotePeer() types.PeerID            { return c.peer }
+func (c *replayConn) RemoteMultiaddr() types.Multiaddr    { return nil }
+func (c *replayConn) AcceptStream() (pkgif.Stream, error) { return nil, errReplayConn }
+func (c *replayConn) GetStreams() []pkgif.Stream          { return nil }
+func (c *replayConn) ConnType() pkgif.ConnectionType      { return pkgif.ConnectionTypeDirect }
+func (c *replayConn) SupportsStreamPriority() bool        { return false }
+func (c *replayConn) Close() error                        { return nil }
+func (c *replayConn) IsClosed() bool                      { return false }
+
+func (c *replayConn) NewStream(context.Context) (pkgif.Stream, error) {
+	return nil, errReplayConn
+}
+
+func (c *replayConn) NewStreamWithPriority(context.Context, int) (pkgif.Stream, error) {
+	return nil, errReplayConn
+}
+
+func (c *replayConn) Stat() pkgif.ConnectionStat {
+	return pkgif.ConnectionStat{Direction: pkgif.DirInbound}
+}
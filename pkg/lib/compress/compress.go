@@ -0,0 +1,304 @@
+package compress
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Algorithm 压缩算法
+type Algorithm string
+
+const (
+	// None 不压缩
+	None Algorithm = ""
+
+	// Zstd zstd 压缩（压缩率高）
+	Zstd Algorithm = "zstd"
+
+	// Snappy snappy 压缩（速度快）
+	Snappy Algorithm = "snappy"
+)
+
+const (
+	// DefaultMaxDecompressedSize 默认解压后大小上限（16MB）
+	DefaultMaxDecompressedSize = 16 << 20
+
+	// variantSep 变体协议分隔符
+	variantSep = "+"
+
+	// streamWindowSize 流式 zstd 压缩窗口
+	streamWindowSize = 1 << 20
+)
+
+// 错误定义
+var (
+	// ErrUnsupported 不支持的压缩算法
+	ErrUnsupported = errors.New("compress: unsupported algorithm")
+
+	// ErrTooLarge 解压后数据超过上限
+	ErrTooLarge = errors.New("compress: decompressed size exceeds limit")
+)
+
+// Supported 本节点支持的压缩算法（按默认偏好排序）
+var Supported = []Algorithm{Zstd, Snappy}
+
+// String 返回算法名称
+func (a Algorithm) String() string {
+	if a == None {
+		return "none"
+	}
+	return string(a)
+}
+
+// ParseAlgorithm 解析算法名称
+//
+// 空字符串和 "none" 解析为 None。
+func ParseAlgorithm(name string) (Algorithm, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "none":
+		return None, nil
+	case string(Zstd):
+		return Zstd, nil
+	case string(Snappy):
+		return Snappy, nil
+	default:
+		return None, fmt.Errorf("%w: %s", ErrUnsupported, name)
+	}
+}
+
+// ParseAlgorithms 解析算法名称列表，忽略 None
+func ParseAlgorithms(names []string) ([]Algorithm, error) {
+	algs := make([]Algorithm, 0, len(names))
+	for _, name := range names {
+		alg, err := ParseAlgorithm(name)
+		if err != nil {
+			return nil, err
+		}
+		if alg != None {
+			algs = append(algs, alg)
+		}
+	}
+	return algs, nil
+}
+
+// ============================================================================
+//                              协议协商
+// ============================================================================
+
+// Variant 返回协议的压缩变体
+func Variant(protocolID string, alg Algorithm) string {
+	if alg == None {
+		return protocolID
+	}
+	return protocolID + variantSep + string(alg)
+}
+
+// Variants 返回按偏好排序的待协商协议列表
+//
+// 列表以各压缩变体开始，以原协议结束，保证旧节点可以回退。
+func Variants(protocolID string, algs ...Algorithm) []string {
+	protos := make([]string, 0, len(algs)+1)
+	for _, alg := range algs {
+		if alg != None {
+			protos = append(protos, Variant(protocolID, alg))
+		}
+	}
+	return append(protos, protocolID)
+}
+
+// FromProtocol 从协商结果中解析压缩算法
+func FromProtocol(protocolID string) Algorithm {
+	idx := strings.LastIndex(protocolID, variantSep)
+	if idx < 0 || strings.LastIndex(protocolID, "/") > idx {
+		return None
+	}
+	alg, err := ParseAlgorithm(protocolID[idx+1:])
+	if err != nil {
+		return None
+	}
+	return alg
+}
+
+// ============================================================================
+//                              整块压缩
+// ============================================================================
+
+var (
+	zstdEncOnce sync.Once
+	zstdEnc     *zstd.Encoder
+
+	zstdDecPool = sync.Pool{
+		New: func() interface{} {
+			dec, _ := zstd.NewReader(nil, zstd.WithDecoderConcurrency(1))
+			return dec
+		},
+	}
+)
+
+// encoder 返回共享的 zstd 编码器（EncodeAll 可并发调用）
+func encoder() *zstd.Encoder {
+	zstdEncOnce.Do(func() {
+		zstdEnc, _ = zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+	})
+	return zstdEnc
+}
+
+// Compress 压缩整块数据
+func Compress(alg Algorithm, data []byte) ([]byte, error) {
+	switch alg {
+	case None:
+		return data, nil
+	case Zstd:
+		return encoder().EncodeAll(data, nil), nil
+	case Snappy:
+		return snappy.Encode(nil, data), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupported, alg)
+	}
+}
+
+// Decompress 解压整块数据
+//
+// maxSize 为解压后大小上限，<= 0 时使用 DefaultMaxDecompressedSize。
+func Decompress(alg Algorithm, data []byte, maxSize int) ([]byte, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxDecompressedSize
+	}
+
+	switch alg {
+	case None:
+		if len(data) > maxSize {
+			return nil, ErrTooLarge
+		}
+		return data, nil
+
+	case Zstd:
+		dec, _ := zstdDecPool.Get().(*zstd.Decoder)
+		if dec == nil {
+			return nil, fmt.Errorf("%w: zstd decoder unavailable", ErrUnsupported)
+		}
+		defer zstdDecPool.Put(dec)
+
+		if err := dec.Reset(bytes.NewReader(data)); err != nil {
+			return nil, err
+		}
+		out, err := io.ReadAll(io.LimitReader(dec, int64(maxSize)+1))
+		if err != nil {
+			return nil, err
+		}
+		if len(out) > maxSize {
+			return nil, ErrTooLarge
+		}
+		return out, nil
+
+	case Snappy:
+		n, err := snappy.DecodedLen(data)
+		if err != nil {
+			return nil, err
+		}
+		if n > maxSize {
+			return nil, ErrTooLarge
+		}
+		return snappy.Decode(nil, data)
+
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupported, alg)
+	}
+}
+
+// ============================================================================
+//                              流式压缩
+// ============================================================================
+
+// flushWriter 每次写入后立即刷新的压缩写入器
+//
+// 流是交互式的，数据不能滞留在压缩缓冲区中。
+type flushWriter struct {
+	w interface {
+		io.WriteCloser
+		Flush() error
+	}
+}
+
+func (f *flushWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+	if err != nil {
+		return n, err
+	}
+	return n, f.w.Flush()
+}
+
+func (f *flushWriter) Close() error {
+	return f.w.Close()
+}
+
+// NewWriter 创建流式压缩写入器
+//
+// 每次 Write 的数据都会立即刷新到 w。Close 只结束压缩流，不关闭 w。
+func NewWriter(alg Algorithm, w io.Writer) (io.WriteCloser, error) {
+	switch alg {
+	case None:
+		return nopWriteCloser{w}, nil
+	case Zstd:
+		enc, err := zstd.NewWriter(w,
+			zstd.WithEncoderConcurrency(1),
+			zstd.WithWindowSize(streamWindowSize))
+		if err != nil {
+			return nil, err
+		}
+		return &flushWriter{w: enc}, nil
+	case Snappy:
+		return &flushWriter{w: snappy.NewBufferedWriter(w)}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupported, alg)
+	}
+}
+
+// NewReader 创建流式解压读取器
+//
+// maxSize 限制解压所需的窗口/块内存，<= 0 时使用 DefaultMaxDecompressedSize。
+// 流的总长度不受限制。
+func NewReader(alg Algorithm, r io.Reader, maxSize int) (io.ReadCloser, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxDecompressedSize
+	}
+
+	switch alg {
+	case None:
+		return io.NopCloser(r), nil
+	case Zstd:
+		window := uint64(maxSize)
+		if window < zstd.MinWindowSize {
+			window = zstd.MinWindowSize
+		}
+		if window > zstd.MaxWindowSize {
+			window = zstd.MaxWindowSize
+		}
+		dec, err := zstd.NewReader(r,
+			zstd.WithDecoderConcurrency(1),
+			zstd.WithDecoderMaxWindow(window),
+			zstd.WithDecoderMaxMemory(uint64(maxSize)))
+		if err != nil {
+			return nil, err
+		}
+		return dec.IOReadCloser(), nil
+	case Snappy:
+		return io.NopCloser(snappy.NewReader(r)), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupported, alg)
+	}
+}
+
+// nopWriteCloser 不做任何事的 Close
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
@@ -0,0 +1,111 @@
+package compress
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompress_RoundTrip(t *testing.T) {
+	data := bytes.Repeat([]byte("dep2p compress "), 1000)
+
+	for _, alg := range []Algorithm{None, Zstd, Snappy} {
+		t.Run(alg.String(), func(t *testing.T) {
+			packed, err := Compress(alg, data)
+			require.NoError(t, err)
+			if alg != None {
+				assert.Less(t, len(packed), len(data))
+			}
+
+			out, err := Decompress(alg, packed, 0)
+			require.NoError(t, err)
+			assert.Equal(t, data, out)
+		})
+	}
+}
+
+func TestDecompress_Limit(t *testing.T) {
+	// 高压缩率数据模拟压缩炸弹
+	data := make([]byte, 1<<20)
+
+	for _, alg := range []Algorithm{None, Zstd, Snappy} {
+		t.Run(alg.String(), func(t *testing.T) {
+			packed, err := Compress(alg, data)
+			require.NoError(t, err)
+
+			_, err = Decompress(alg, packed, 64<<10)
+			assert.ErrorIs(t, err, ErrTooLarge)
+		})
+	}
+}
+
+func TestParseAlgorithm(t *testing.T) {
+	alg, err := ParseAlgorithm("ZSTD")
+	require.NoError(t, err)
+	assert.Equal(t, Zstd, alg)
+
+	alg, err = ParseAlgorithm("none")
+	require.NoError(t, err)
+	assert.Equal(t, None, alg)
+
+	_, err = ParseAlgorithm("lz4")
+	assert.ErrorIs(t, err, ErrUnsupported)
+
+	algs, err := ParseAlgorithms([]string{"snappy", "", "zstd"})
+	require.NoError(t, err)
+	assert.Equal(t, []Algorithm{Snappy, Zstd}, algs)
+}
+
+func TestVariants(t *testing.T) {
+	const proto = "/dep2p/app/realm-1/chat/1.0.0"
+
+	protos := Variants(proto, Zstd, Snappy)
+	assert.Equal(t, []string{proto + "+zstd", proto + "+snappy", proto}, protos)
+
+	assert.Equal(t, Zstd, FromProtocol(protos[0]))
+	assert.Equal(t, Snappy, FromProtocol(protos[1]))
+	assert.Equal(t, None, FromProtocol(proto))
+	assert.Equal(t, None, FromProtocol("/dep2p/app/a+zstd/chat/1.0.0"))
+	assert.Equal(t, None, FromProtocol(proto+"+lz4"))
+}
+
+func TestStream_RoundTrip(t *testing.T) {
+	for _, alg := range []Algorithm{None, Zstd, Snappy} {
+		t.Run(alg.String(), func(t *testing.T) {
+			pr, pw := io.Pipe()
+
+			w, err := NewWriter(alg, pw)
+			require.NoError(t, err)
+			r, err := NewReader(alg, pr, 0)
+			require.NoError(t, err)
+			defer r.Close()
+
+			// 每次写入都应立即可读（交互式）
+			for _, msg := range []string{"hello", "world"} {
+				done := make(chan struct{})
+				go func() {
+					defer close(done)
+					_, werr := w.Write([]byte(msg))
+					assert.NoError(t, werr)
+				}()
+
+				buf := make([]byte, len(msg))
+				_, err := io.ReadFull(r, buf)
+				require.NoError(t, err)
+				assert.Equal(t, msg, string(buf))
+				<-done
+			}
+
+			go func() {
+				w.Close()
+				pw.Close()
+			}()
+			rest, err := io.ReadAll(r)
+			require.NoError(t, err)
+			assert.Empty(t, rest)
+		})
+	}
+}
@@ -0,0 +1,27 @@
+// Package compress 提供协议负载压缩工具
+//
+// 本包封装 zstd 与 snappy 两种压缩算法，供 Messaging、PubSub、
+// Streams 等协议透明压缩负载使用。
+//
+// # 协商方式
+//
+// 压缩通过 multistream-select 协商：在协议版本后追加 "+<算法>"
+// 作为变体协议，例如
+//
+//	/dep2p/app/<realmID>/chat/1.0.0        未压缩
+//	/dep2p/app/<realmID>/chat/1.0.0+zstd   zstd 压缩
+//	/dep2p/app/<realmID>/chat/1.0.0+snappy snappy 压缩
+//
+// 接收方注册变体协议即表示支持对应算法（同时会出现在 Identify
+// 的协议列表中）。发送方按偏好顺序提议变体，最后提议原协议，
+// 不支持压缩的旧节点自然回退到未压缩传输：
+//
+//	protos := compress.Variants(protocolID, compress.Zstd, compress.Snappy)
+//	stream, err := host.NewStream(ctx, peerID, protos...)
+//	alg := compress.FromProtocol(stream.Protocol())
+//
+// # 解压上限
+//
+// 所有解压操作都受解压后大小上限约束，超出时返回 ErrTooLarge，
+// 防止压缩炸弹耗尽内存。
+package compress
@@ -0,0 +1,172 @@
+// Package crypto 提供 DeP2P 密码学工具
+package crypto
+
+import (
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"io"
+
+	"filippo.io/edwards25519"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
+)
+
+// ============================================================================
+//                              端到端封装（Sealed Box）
+// ============================================================================
+//
+// 封装格式（版本 1）：
+//
+//	[1 字节版本][32 字节临时 X25519 公钥][ChaCha20-Poly1305 密文]
+//
+// 发送方为每条消息生成临时 X25519 密钥，与接收方身份公钥（Ed25519 转换为
+// X25519）做 ECDH，经 HKDF-SHA256 派生一次性对称密钥。由于密钥一次性使用，
+// AEAD 使用全零 nonce 是安全的。中间节点只能看到密文，无法解密。
+
+const (
+	// sealVersion 封装格式版本
+	sealVersion byte = 1
+
+	// sealInfo HKDF 上下文信息
+	sealInfo = "dep2p-seal-v1"
+
+	// SealOverhead 封装相对明文增加的字节数
+	SealOverhead = 1 + 32 + chacha20poly1305.Overhead
+)
+
+// 封装相关错误
+var (
+	// ErrSealedTooShort 封装数据过短
+	ErrSealedTooShort = errors.New("sealed data too short")
+
+	// ErrSealVersion 不支持的封装版本
+	ErrSealVersion = errors.New("unsupported seal version")
+
+	// ErrOpenFailed 解封失败（密钥不匹配或数据被篡改）
+	ErrOpenFailed = errors.New("failed to open sealed data")
+)
+
+// SealEd25519 使用接收方 Ed25519 公钥封装数据
+//
+// 参数：
+//   - recipientPub: 接收方 Ed25519 原始公钥（32 字节）
+//   - plaintext: 明文
+//   - aad: 附加认证数据（可为 nil，解封时必须一致）
+func SealEd25519(recipientPub, plaintext, aad []byte) ([]byte, error) {
+	remote, err := ed25519PublicToX25519(recipientPub)
+	if err != nil {
+		return nil, err
+	}
+
+	eph, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := eph.ECDH(remote)
+	if err != nil {
+		return nil, ErrInvalidPublicKey
+	}
+
+	ephPub := eph.PublicKey().Bytes()
+	aead, err := sealAEAD(shared, ephPub, remote.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, SealOverhead+len(plaintext))
+	out = append(out, sealVersion)
+	out = append(out, ephPub...)
+	nonce := make([]byte, chacha20poly1305.NonceSize)
+	return aead.Seal(out, nonce, plaintext, aad), nil
+}
+
+// OpenEd25519 使用本地 Ed25519 私钥解封数据
+//
+// 参数：
+//   - priv: 本地 Ed25519 私钥（64 字节私钥或 32 字节种子）
+//   - sealed: SealEd25519 的输出
+//   - aad: 附加认证数据（必须与封装时一致）
+func OpenEd25519(priv, sealed, aad []byte) ([]byte, error) {
+	if len(sealed) < SealOverhead {
+		return nil, ErrSealedTooShort
+	}
+	if sealed[0] != sealVersion {
+		return nil, ErrSealVersion
+	}
+
+	local, err := ed25519PrivateToX25519(priv)
+	if err != nil {
+		return nil, err
+	}
+
+	ephPub := sealed[1:33]
+	remote, err := ecdh.X25519().NewPublicKey(ephPub)
+	if err != nil {
+		return nil, ErrOpenFailed
+	}
+	shared, err := local.ECDH(remote)
+	if err != nil {
+		return nil, ErrOpenFailed
+	}
+
+	aead, err := sealAEAD(shared, ephPub, local.PublicKey().Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, chacha20poly1305.NonceSize)
+	plaintext, err := aead.Open(nil, nonce, sealed[33:], aad)
+	if err != nil {
+		return nil, ErrOpenFailed
+	}
+	return plaintext, nil
+}
+
+// sealAEAD 从共享密钥派生 AEAD
+//
+// salt 绑定临时公钥和接收方公钥，防止密钥被挪用到其他会话。
+func sealAEAD(shared, ephPub, recipientX []byte) (cipher.AEAD, error) {
+	salt := make([]byte, 0, len(ephPub)+len(recipientX))
+	salt = append(salt, ephPub...)
+	salt = append(salt, recipientX...)
+
+	key := make([]byte, chacha20poly1305.KeySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, shared, salt, []byte(sealInfo)), key); err != nil {
+		return nil, err
+	}
+	return chacha20poly1305.New(key)
+}
+
+// ed25519PublicToX25519 将 Ed25519 公钥转换为 X25519 公钥
+func ed25519PublicToX25519(pub []byte) (*ecdh.PublicKey, error) {
+	if len(pub) != ed25519.PublicKeySize {
+		return nil, ErrInvalidKeySize
+	}
+	point, err := new(edwards25519.Point).SetBytes(pub)
+	if err != nil {
+		return nil, ErrInvalidPublicKey
+	}
+	return ecdh.X25519().NewPublicKey(point.BytesMontgomery())
+}
+
+// ed25519PrivateToX25519 将 Ed25519 私钥转换为 X25519 私钥
+//
+// 按 RFC 8032 从种子派生标量（SHA-512 前 32 字节），钳位由 crypto/ecdh 完成。
+func ed25519PrivateToX25519(priv []byte) (*ecdh.PrivateKey, error) {
+	var seed []byte
+	switch len(priv) {
+	case ed25519.PrivateKeySize:
+		seed = priv[:ed25519.SeedSize]
+	case ed25519.SeedSize:
+		seed = priv
+	default:
+		return nil, ErrInvalidKeySize
+	}
+	h := sha512.Sum512(seed)
+	return ecdh.X25519().NewPrivateKey(h[:32])
+}
@@ -0,0 +1,81 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+)
+
+func TestSealEd25519_RoundTrip(t *testing.T) {
+	priv, pub, _ := GenerateEd25519Key(rand.Reader)
+	pubRaw, _ := pub.Raw()
+	privRaw, _ := priv.Raw()
+
+	plaintext := []byte("offline message")
+	aad := []byte("realm-1")
+
+	sealed, err := SealEd25519(pubRaw, plaintext, aad)
+	if err != nil {
+		t.Fatalf("SealEd25519() error = %v", err)
+	}
+	if len(sealed) != len(plaintext)+SealOverhead {
+		t.Errorf("sealed len = %d, want %d", len(sealed), len(plaintext)+SealOverhead)
+	}
+	if bytes.Contains(sealed, plaintext) {
+		t.Error("sealed data contains plaintext")
+	}
+
+	opened, err := OpenEd25519(privRaw, sealed, aad)
+	if err != nil {
+		t.Fatalf("OpenEd25519() error = %v", err)
+	}
+	if !bytes.Equal(opened, plaintext) {
+		t.Errorf("OpenEd25519() = %q, want %q", opened, plaintext)
+	}
+
+	// 种子形式的私钥同样可以解封
+	seed := priv.(*Ed25519PrivateKey).Seed()
+	if _, err := OpenEd25519(seed, sealed, aad); err != nil {
+		t.Errorf("OpenEd25519(seed) error = %v", err)
+	}
+}
+
+func TestSealEd25519_WrongKeyOrAAD(t *testing.T) {
+	_, pub, _ := GenerateEd25519Key(rand.Reader)
+	other, _, _ := GenerateEd25519Key(rand.Reader)
+	pubRaw, _ := pub.Raw()
+	otherRaw, _ := other.Raw()
+
+	sealed, err := SealEd25519(pubRaw, []byte("secret"), []byte("a"))
+	if err != nil {
+		t.Fatalf("SealEd25519() error = %v", err)
+	}
+
+	if _, err := OpenEd25519(otherRaw, sealed, []byte("a")); err != ErrOpenFailed {
+		t.Errorf("OpenEd25519(wrong key) error = %v, want %v", err, ErrOpenFailed)
+	}
+
+	tampered := append([]byte(nil), sealed...)
+	tampered[len(tampered)-1] ^= 0xff
+	if _, err := OpenEd25519(otherRaw, tampered, []byte("a")); err != ErrOpenFailed {
+		t.Errorf("OpenEd25519(tampered) error = %v, want %v", err, ErrOpenFailed)
+	}
+}
+
+func TestSealEd25519_InvalidInput(t *testing.T) {
+	if _, err := SealEd25519([]byte("short"), []byte("x"), nil); err != ErrInvalidKeySize {
+		t.Errorf("SealEd25519(short key) error = %v, want %v", err, ErrInvalidKeySize)
+	}
+
+	priv, _, _ := GenerateEd25519Key(rand.Reader)
+	privRaw, _ := priv.Raw()
+	if _, err := OpenEd25519(privRaw, []byte{1, 2, 3}, nil); err != ErrSealedTooShort {
+		t.Errorf("OpenEd25519(short) error = %v, want %v", err, ErrSealedTooShort)
+	}
+
+	bad := make([]byte, SealOverhead)
+	bad[0] = 9
+	if _, err := OpenEd25519(privRaw, bad, nil); err != ErrSealVersion {
+		t.Errorf("OpenEd25519(bad version) error = %v, want %v", err, ErrSealVersion)
+	}
+}
@@ -240,13 +240,17 @@ func (x *SubOpts) GetTopicId() string {
 
 // Message 消息
 type Message struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	From          []byte                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
-	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
-	Seqno         []byte                 `protobuf:"bytes,3,opt,name=seqno,proto3" json:"seqno,omitempty"`
-	Topic         string                 `protobuf:"bytes,4,opt,name=topic,proto3" json:"topic,omitempty"`
-	Signature     []byte                 `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
-	Key           []byte                 `protobuf:"bytes,6,opt,name=key,proto3" json:"key,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	From      []byte                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	Data      []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	Seqno     []byte                 `protobuf:"bytes,3,opt,name=seqno,proto3" json:"seqno,omitempty"`
+	Topic     string                 `protobuf:"bytes,4,opt,name=topic,proto3" json:"topic,omitempty"`
+	Signature []byte                 `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	Key       []byte                 `protobuf:"bytes,6,opt,name=key,proto3" json:"key,omitempty"`
+	// traceparent W3C 追踪上下文（可选）
+	Traceparent string `protobuf:"bytes,7,opt,name=traceparent,proto3" json:"traceparent,omitempty"`
+	// tracestate W3C 追踪状态（可选）
+	Tracestate    string `protobuf:"bytes,8,opt,name=tracestate,proto3" json:"tracestate,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -323,6 +327,20 @@ func (x *Message) GetKey() []byte {
 	return nil
 }
 
+func (x *Message) GetTraceparent() string {
+	if x != nil {
+		return x.Traceparent
+	}
+	return ""
+}
+
+func (x *Message) GetTracestate() string {
+	if x != nil {
+		return x.Tracestate
+	}
+	return ""
+}
+
 // ControlMessage 控制消息
 type ControlMessage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -827,14 +845,18 @@ const file_gossipsub_gossipsub_proto_rawDesc = "" +
 	"\acontrol\x18\x03 \x01(\v2\x1f.dep2p.gossipsub.ControlMessageR\acontrol\"B\n" +
 	"\aSubOpts\x12\x1c\n" +
 	"\tsubscribe\x18\x01 \x01(\bR\tsubscribe\x12\x19\n" +
-	"\btopic_id\x18\x02 \x01(\tR\atopicId\"\x8d\x01\n" +
+	"\btopic_id\x18\x02 \x01(\tR\atopicId\"\xcf\x01\n" +
 	"\aMessage\x12\x12\n" +
 	"\x04from\x18\x01 \x01(\fR\x04from\x12\x12\n" +
 	"\x04data\x18\x02 \x01(\fR\x04data\x12\x14\n" +
 	"\x05seqno\x18\x03 \x01(\fR\x05seqno\x12\x14\n" +
 	"\x05topic\x18\x04 \x01(\tR\x05topic\x12\x1c\n" +
 	"\tsignature\x18\x05 \x01(\fR\tsignature\x12\x10\n" +
-	"\x03key\x18\x06 \x01(\fR\x03key\"\xe4\x01\n" +
+	"\x03key\x18\x06 \x01(\fR\x03key\x12 \n" +
+	"\vtraceparent\x18\a \x01(\tR\vtraceparent\x12\x1e\n" +
+	"\n" +
+	"tracestate\x18\b \x01(\tR\n" +
+	"tracestate\"\xe4\x01\n" +
 	"\x0eControlMessage\x123\n" +
 	"\x05ihave\x18\x01 \x03(\v2\x1d.dep2p.gossipsub.ControlIHaveR\x05ihave\x123\n" +
 	"\x05iwant\x18\x02 \x03(\v2\x1d.dep2p.gossipsub.ControlIWantR\x05iwant\x123\n" +
@@ -42,8 +42,10 @@ type Identify struct {
 	Protocols []string `protobuf:"bytes,6,rep,name=protocols,proto3" json:"protocols,omitempty"`
 	// signed_peer_record 签名的节点记录
 	SignedPeerRecord []byte `protobuf:"bytes,8,opt,name=signed_peer_record,json=signedPeerRecord,proto3" json:"signed_peer_record,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	// capabilities 节点能力块
+	Capabilities  *Capabilities `protobuf:"bytes,9,opt,name=capabilities,proto3" json:"capabilities,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Identify) Reset() {
@@ -125,6 +127,13 @@ func (x *Identify) GetSignedPeerRecord() []byte {
 	return nil
 }
 
+func (x *Identify) GetCapabilities() *Capabilities {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
 // Push 身份推送消息
 // 用于通知对端地址变更
 type Push struct {
@@ -133,8 +142,10 @@ type Push struct {
 	Protocols []string `protobuf:"bytes,1,rep,name=protocols,proto3" json:"protocols,omitempty"`
 	// signed_peer_record 更新的签名节点记录
 	SignedPeerRecord []byte `protobuf:"bytes,2,opt,name=signed_peer_record,json=signedPeerRecord,proto3" json:"signed_peer_record,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	// capabilities 更新的节点能力块
+	Capabilities  *Capabilities `protobuf:"bytes,3,opt,name=capabilities,proto3" json:"capabilities,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Push) Reset() {
@@ -181,11 +192,201 @@ func (x *Push) GetSignedPeerRecord() []byte {
 	return nil
 }
 
+func (x *Push) GetCapabilities() *Capabilities {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+// Capabilities 节点能力块
+// 版本化且可扩展，接收方应忽略未知字段
+type Capabilities struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// version 能力块版本（当前为 1）
+	Version uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	// roles 节点角色（relay、bootstrap、gateway、witness）
+	Roles []string `protobuf:"bytes,2,rep,name=roles,proto3" json:"roles,omitempty"`
+	// nat_type NAT 类型（取值同 types.NATType）
+	NatType uint32 `protobuf:"varint,3,opt,name=nat_type,json=natType,proto3" json:"nat_type,omitempty"`
+	// reachability 可达性（取值同 types.Reachability）
+	Reachability uint32 `protobuf:"varint,4,opt,name=reachability,proto3" json:"reachability,omitempty"`
+	// hole_punching 是否支持打洞
+	HolePunching bool `protobuf:"varint,5,opt,name=hole_punching,json=holePunching,proto3" json:"hole_punching,omitempty"`
+	// transports 支持的传输协议（如 tcp、quic-v1）
+	Transports []string `protobuf:"bytes,6,rep,name=transports,proto3" json:"transports,omitempty"`
+	// load 负载提示
+	Load *LoadHint `protobuf:"bytes,7,opt,name=load,proto3" json:"load,omitempty"`
+	// realm_hashes 已加入 Realm 的哈希列表（以 PeerID 加盐）
+	RealmHashes   [][]byte `protobuf:"bytes,8,rep,name=realm_hashes,json=realmHashes,proto3" json:"realm_hashes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Capabilities) Reset() {
+	*x = Capabilities{}
+	mi := &file_identify_identify_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Capabilities) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Capabilities) ProtoMessage() {}
+
+func (x *Capabilities) ProtoReflect() protoreflect.Message {
+	mi := &file_identify_identify_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Capabilities.ProtoReflect.Descriptor instead.
+func (*Capabilities) Descriptor() ([]byte, []int) {
+	return file_identify_identify_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Capabilities) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Capabilities) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *Capabilities) GetNatType() uint32 {
+	if x != nil {
+		return x.NatType
+	}
+	return 0
+}
+
+func (x *Capabilities) GetReachability() uint32 {
+	if x != nil {
+		return x.Reachability
+	}
+	return 0
+}
+
+func (x *Capabilities) GetHolePunching() bool {
+	if x != nil {
+		return x.HolePunching
+	}
+	return false
+}
+
+func (x *Capabilities) GetTransports() []string {
+	if x != nil {
+		return x.Transports
+	}
+	return nil
+}
+
+func (x *Capabilities) GetLoad() *LoadHint {
+	if x != nil {
+		return x.Load
+	}
+	return nil
+}
+
+func (x *Capabilities) GetRealmHashes() [][]byte {
+	if x != nil {
+		return x.RealmHashes
+	}
+	return nil
+}
+
+// LoadHint 负载提示
+type LoadHint struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// conns 当前连接数
+	Conns uint32 `protobuf:"varint,1,opt,name=conns,proto3" json:"conns,omitempty"`
+	// max_conns 连接数上限（0 表示未知）
+	MaxConns uint32 `protobuf:"varint,2,opt,name=max_conns,json=maxConns,proto3" json:"max_conns,omitempty"`
+	// relay_circuits 当前中继电路数
+	RelayCircuits uint32 `protobuf:"varint,3,opt,name=relay_circuits,json=relayCircuits,proto3" json:"relay_circuits,omitempty"`
+	// max_relay_circuits 中继电路上限（0 表示未知）
+	MaxRelayCircuits uint32 `protobuf:"varint,4,opt,name=max_relay_circuits,json=maxRelayCircuits,proto3" json:"max_relay_circuits,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *LoadHint) Reset() {
+	*x = LoadHint{}
+	mi := &file_identify_identify_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoadHint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadHint) ProtoMessage() {}
+
+func (x *LoadHint) ProtoReflect() protoreflect.Message {
+	mi := &file_identify_identify_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadHint.ProtoReflect.Descriptor instead.
+func (*LoadHint) Descriptor() ([]byte, []int) {
+	return file_identify_identify_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *LoadHint) GetConns() uint32 {
+	if x != nil {
+		return x.Conns
+	}
+	return 0
+}
+
+func (x *LoadHint) GetMaxConns() uint32 {
+	if x != nil {
+		return x.MaxConns
+	}
+	return 0
+}
+
+func (x *LoadHint) GetRelayCircuits() uint32 {
+	if x != nil {
+		return x.RelayCircuits
+	}
+	return 0
+}
+
+func (x *LoadHint) GetMaxRelayCircuits() uint32 {
+	if x != nil {
+		return x.MaxRelayCircuits
+	}
+	return 0
+}
+
 var File_identify_identify_proto protoreflect.FileDescriptor
 
 const file_identify_identify_proto_rawDesc = "" +
 	"\n" +
-	"\x17identify/identify.proto\x12\x0edep2p.identify\"\x8d\x02\n" +
+	"\x17identify/identify.proto\x12\x0edep2p.identify\"\xcf\x02\n" +
 	"\bIdentify\x12)\n" +
 	"\x10protocol_version\x18\x01 \x01(\fR\x0fprotocolVersion\x12#\n" +
 	"\ragent_version\x18\x02 \x01(\fR\fagentVersion\x12\x1d\n" +
@@ -194,10 +395,28 @@ const file_identify_identify_proto_rawDesc = "" +
 	"\flisten_addrs\x18\x04 \x03(\fR\vlistenAddrs\x12#\n" +
 	"\robserved_addr\x18\x05 \x01(\fR\fobservedAddr\x12\x1c\n" +
 	"\tprotocols\x18\x06 \x03(\tR\tprotocols\x12,\n" +
-	"\x12signed_peer_record\x18\b \x01(\fR\x10signedPeerRecord\"R\n" +
+	"\x12signed_peer_record\x18\b \x01(\fR\x10signedPeerRecord\x12@\n" +
+	"\fcapabilities\x18\t \x01(\v2\x1c.dep2p.identify.CapabilitiesR\fcapabilities\"\x94\x01\n" +
 	"\x04Push\x12\x1c\n" +
 	"\tprotocols\x18\x01 \x03(\tR\tprotocols\x12,\n" +
-	"\x12signed_peer_record\x18\x02 \x01(\fR\x10signedPeerRecordB+Z)github.com/dep2p/dep2p/pkg/proto/identifyb\x06proto3"
+	"\x12signed_peer_record\x18\x02 \x01(\fR\x10signedPeerRecord\x12@\n" +
+	"\fcapabilities\x18\x03 \x01(\v2\x1c.dep2p.identify.CapabilitiesR\fcapabilities\"\x93\x02\n" +
+	"\fCapabilities\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\rR\aversion\x12\x14\n" +
+	"\x05roles\x18\x02 \x03(\tR\x05roles\x12\x19\n" +
+	"\bnat_type\x18\x03 \x01(\rR\anatType\x12\"\n" +
+	"\freachability\x18\x04 \x01(\rR\freachability\x12#\n" +
+	"\rhole_punching\x18\x05 \x01(\bR\fholePunching\x12\x1e\n" +
+	"\n" +
+	"transports\x18\x06 \x03(\tR\n" +
+	"transports\x12,\n" +
+	"\x04load\x18\a \x01(\v2\x18.dep2p.identify.LoadHintR\x04load\x12!\n" +
+	"\frealm_hashes\x18\b \x03(\fR\vrealmHashes\"\x92\x01\n" +
+	"\bLoadHint\x12\x14\n" +
+	"\x05conns\x18\x01 \x01(\rR\x05conns\x12\x1b\n" +
+	"\tmax_conns\x18\x02 \x01(\rR\bmaxConns\x12%\n" +
+	"\x0erelay_circuits\x18\x03 \x01(\rR\rrelayCircuits\x12,\n" +
+	"\x12max_relay_circuits\x18\x04 \x01(\rR\x10maxRelayCircuitsB+Z)github.com/dep2p/dep2p/pkg/proto/identifyb\x06proto3"
 
 var (
 	file_identify_identify_proto_rawDescOnce sync.Once
@@ -211,17 +430,22 @@ func file_identify_identify_proto_rawDescGZIP() []byte {
 	return file_identify_identify_proto_rawDescData
 }
 
-var file_identify_identify_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_identify_identify_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_identify_identify_proto_goTypes = []any{
-	(*Identify)(nil), // 0: dep2p.identify.Identify
-	(*Push)(nil),     // 1: dep2p.identify.Push
+	(*Identify)(nil),     // 0: dep2p.identify.Identify
+	(*Push)(nil),         // 1: dep2p.identify.Push
+	(*Capabilities)(nil), // 2: dep2p.identify.Capabilities
+	(*LoadHint)(nil),     // 3: dep2p.identify.LoadHint
 }
 var file_identify_identify_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	2, // 0: dep2p.identify.Identify.capabilities:type_name -> dep2p.identify.Capabilities
+	2, // 1: dep2p.identify.Push.capabilities:type_name -> dep2p.identify.Capabilities
+	3, // 2: dep2p.identify.Capabilities.load:type_name -> dep2p.identify.LoadHint
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_identify_identify_proto_init() }
@@ -235,7 +459,7 @@ func file_identify_identify_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_identify_identify_proto_rawDesc), len(file_identify_identify_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
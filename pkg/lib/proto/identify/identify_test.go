@@ -77,3 +77,36 @@ func TestPush(t *testing.T) {
 		t.Error("Protocols mismatch")
 	}
 }
+
+func TestIdentify_Capabilities(t *testing.T) {
+	original := &identify.Identify{
+		ProtocolVersion: []byte("dep2p/1.0.0"),
+		Capabilities: &identify.Capabilities{
+			Version:      1,
+			Roles:        []string{"relay", "bootstrap"},
+			NatType:      5,
+			Reachability: 1,
+			HolePunching: true,
+			Transports:   []string{"quic-v1", "tcp"},
+			Load:         &identify.LoadHint{Conns: 10, MaxConns: 100},
+			RealmHashes:  [][]byte{[]byte("12345678")},
+		},
+	}
+
+	data, err := proto.Marshal(original)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var decoded identify.Identify
+	if err := proto.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if !proto.Equal(original, &decoded) {
+		t.Error("Round trip failed")
+	}
+	if decoded.GetCapabilities().GetLoad().GetMaxConns() != 100 {
+		t.Error("LoadHint mismatch")
+	}
+}
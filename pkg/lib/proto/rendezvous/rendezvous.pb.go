@@ -30,28 +30,49 @@ const (
 type Message_MessageType int32
 
 const (
-	Message_REGISTER          Message_MessageType = 0
-	Message_REGISTER_RESPONSE Message_MessageType = 1
-	Message_UNREGISTER        Message_MessageType = 2
-	Message_DISCOVER          Message_MessageType = 3
-	Message_DISCOVER_RESPONSE Message_MessageType = 4
+	Message_REGISTER                    Message_MessageType = 0
+	Message_REGISTER_RESPONSE           Message_MessageType = 1
+	Message_UNREGISTER                  Message_MessageType = 2
+	Message_DISCOVER                    Message_MessageType = 3
+	Message_DISCOVER_RESPONSE           Message_MessageType = 4
+	Message_DISCOVER_SUBSCRIBE          Message_MessageType = 5
+	Message_DISCOVER_SUBSCRIBE_RESPONSE Message_MessageType = 6
+	Message_DISCOVER_EVENT              Message_MessageType = 7
+	Message_REPLICATE                   Message_MessageType = 8
+	Message_REPLICATE_RESPONSE          Message_MessageType = 9
+	Message_SYNC                        Message_MessageType = 10
+	Message_SYNC_RESPONSE               Message_MessageType = 11
 )
 
 // Enum value maps for Message_MessageType.
 var (
 	Message_MessageType_name = map[int32]string{
-		0: "REGISTER",
-		1: "REGISTER_RESPONSE",
-		2: "UNREGISTER",
-		3: "DISCOVER",
-		4: "DISCOVER_RESPONSE",
+		0:  "REGISTER",
+		1:  "REGISTER_RESPONSE",
+		2:  "UNREGISTER",
+		3:  "DISCOVER",
+		4:  "DISCOVER_RESPONSE",
+		5:  "DISCOVER_SUBSCRIBE",
+		6:  "DISCOVER_SUBSCRIBE_RESPONSE",
+		7:  "DISCOVER_EVENT",
+		8:  "REPLICATE",
+		9:  "REPLICATE_RESPONSE",
+		10: "SYNC",
+		11: "SYNC_RESPONSE",
 	}
 	Message_MessageType_value = map[string]int32{
-		"REGISTER":          0,
-		"REGISTER_RESPONSE": 1,
-		"UNREGISTER":        2,
-		"DISCOVER":          3,
-		"DISCOVER_RESPONSE": 4,
+		"REGISTER":                    0,
+		"REGISTER_RESPONSE":           1,
+		"UNREGISTER":                  2,
+		"DISCOVER":                    3,
+		"DISCOVER_RESPONSE":           4,
+		"DISCOVER_SUBSCRIBE":          5,
+		"DISCOVER_SUBSCRIBE_RESPONSE": 6,
+		"DISCOVER_EVENT":              7,
+		"REPLICATE":                   8,
+		"REPLICATE_RESPONSE":          9,
+		"SYNC":                        10,
+		"SYNC_RESPONSE":               11,
 	}
 )
 
@@ -147,17 +168,74 @@ func (Message_ResponseStatus) EnumDescriptor() ([]byte, []int) {
 	return file_rendezvous_rendezvous_proto_rawDescGZIP(), []int{0, 1}
 }
 
+// EventType 事件类型
+type Message_DiscoverEvent_EventType int32
+
+const (
+	Message_DiscoverEvent_ADDED   Message_DiscoverEvent_EventType = 0
+	Message_DiscoverEvent_EXPIRED Message_DiscoverEvent_EventType = 1
+	Message_DiscoverEvent_REMOVED Message_DiscoverEvent_EventType = 2
+)
+
+// Enum value maps for Message_DiscoverEvent_EventType.
+var (
+	Message_DiscoverEvent_EventType_name = map[int32]string{
+		0: "ADDED",
+		1: "EXPIRED",
+		2: "REMOVED",
+	}
+	Message_DiscoverEvent_EventType_value = map[string]int32{
+		"ADDED":   0,
+		"EXPIRED": 1,
+		"REMOVED": 2,
+	}
+)
+
+func (x Message_DiscoverEvent_EventType) Enum() *Message_DiscoverEvent_EventType {
+	p := new(Message_DiscoverEvent_EventType)
+	*p = x
+	return p
+}
+
+func (x Message_DiscoverEvent_EventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Message_DiscoverEvent_EventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_rendezvous_rendezvous_proto_enumTypes[2].Descriptor()
+}
+
+func (Message_DiscoverEvent_EventType) Type() protoreflect.EnumType {
+	return &file_rendezvous_rendezvous_proto_enumTypes[2]
+}
+
+func (x Message_DiscoverEvent_EventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Message_DiscoverEvent_EventType.Descriptor instead.
+func (Message_DiscoverEvent_EventType) EnumDescriptor() ([]byte, []int) {
+	return file_rendezvous_rendezvous_proto_rawDescGZIP(), []int{0, 8, 0}
+}
+
 // Message Rendezvous 消息
 type Message struct {
-	state            protoimpl.MessageState    `protogen:"open.v1"`
-	Type             Message_MessageType       `protobuf:"varint,1,opt,name=type,proto3,enum=dep2p.rendezvous.Message_MessageType" json:"type,omitempty"`
-	Register         *Message_Register         `protobuf:"bytes,2,opt,name=register,proto3" json:"register,omitempty"`
-	RegisterResponse *Message_RegisterResponse `protobuf:"bytes,3,opt,name=register_response,json=registerResponse,proto3" json:"register_response,omitempty"`
-	Unregister       *Message_Unregister       `protobuf:"bytes,4,opt,name=unregister,proto3" json:"unregister,omitempty"`
-	Discover         *Message_Discover         `protobuf:"bytes,5,opt,name=discover,proto3" json:"discover,omitempty"`
-	DiscoverResponse *Message_DiscoverResponse `protobuf:"bytes,6,opt,name=discover_response,json=discoverResponse,proto3" json:"discover_response,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	state                     protoimpl.MessageState             `protogen:"open.v1"`
+	Type                      Message_MessageType                `protobuf:"varint,1,opt,name=type,proto3,enum=dep2p.rendezvous.Message_MessageType" json:"type,omitempty"`
+	Register                  *Message_Register                  `protobuf:"bytes,2,opt,name=register,proto3" json:"register,omitempty"`
+	RegisterResponse          *Message_RegisterResponse          `protobuf:"bytes,3,opt,name=register_response,json=registerResponse,proto3" json:"register_response,omitempty"`
+	Unregister                *Message_Unregister                `protobuf:"bytes,4,opt,name=unregister,proto3" json:"unregister,omitempty"`
+	Discover                  *Message_Discover                  `protobuf:"bytes,5,opt,name=discover,proto3" json:"discover,omitempty"`
+	DiscoverResponse          *Message_DiscoverResponse          `protobuf:"bytes,6,opt,name=discover_response,json=discoverResponse,proto3" json:"discover_response,omitempty"`
+	DiscoverSubscribe         *Message_DiscoverSubscribe         `protobuf:"bytes,7,opt,name=discover_subscribe,json=discoverSubscribe,proto3" json:"discover_subscribe,omitempty"`
+	DiscoverSubscribeResponse *Message_DiscoverSubscribeResponse `protobuf:"bytes,8,opt,name=discover_subscribe_response,json=discoverSubscribeResponse,proto3" json:"discover_subscribe_response,omitempty"`
+	DiscoverEvent             *Message_DiscoverEvent             `protobuf:"bytes,9,opt,name=discover_event,json=discoverEvent,proto3" json:"discover_event,omitempty"`
+	Replicate                 *Message_Replicate                 `protobuf:"bytes,10,opt,name=replicate,proto3" json:"replicate,omitempty"`
+	ReplicateResponse         *Message_ReplicateResponse         `protobuf:"bytes,11,opt,name=replicate_response,json=replicateResponse,proto3" json:"replicate_response,omitempty"`
+	Sync                      *Message_Sync                      `protobuf:"bytes,12,opt,name=sync,proto3" json:"sync,omitempty"`
+	SyncResponse              *Message_SyncResponse              `protobuf:"bytes,13,opt,name=sync_response,json=syncResponse,proto3" json:"sync_response,omitempty"`
+	unknownFields             protoimpl.UnknownFields
+	sizeCache                 protoimpl.SizeCache
 }
 
 func (x *Message) Reset() {
@@ -232,6 +310,55 @@ func (x *Message) GetDiscoverResponse() *Message_DiscoverResponse {
 	return nil
 }
 
+func (x *Message) GetDiscoverSubscribe() *Message_DiscoverSubscribe {
+	if x != nil {
+		return x.DiscoverSubscribe
+	}
+	return nil
+}
+
+func (x *Message) GetDiscoverSubscribeResponse() *Message_DiscoverSubscribeResponse {
+	if x != nil {
+		return x.DiscoverSubscribeResponse
+	}
+	return nil
+}
+
+func (x *Message) GetDiscoverEvent() *Message_DiscoverEvent {
+	if x != nil {
+		return x.DiscoverEvent
+	}
+	return nil
+}
+
+func (x *Message) GetReplicate() *Message_Replicate {
+	if x != nil {
+		return x.Replicate
+	}
+	return nil
+}
+
+func (x *Message) GetReplicateResponse() *Message_ReplicateResponse {
+	if x != nil {
+		return x.ReplicateResponse
+	}
+	return nil
+}
+
+func (x *Message) GetSync() *Message_Sync {
+	if x != nil {
+		return x.Sync
+	}
+	return nil
+}
+
+func (x *Message) GetSyncResponse() *Message_SyncResponse {
+	if x != nil {
+		return x.SyncResponse
+	}
+	return nil
+}
+
 // Register 注册请求
 type Message_Register struct {
 	state            protoimpl.MessageState `protogen:"open.v1"`
@@ -355,10 +482,12 @@ func (x *Message_RegisterResponse) GetTtl() uint64 {
 }
 
 // Unregister 取消注册
+// signed_record 为客户端签名的取消注册记录，Point 与副本据此验证请求来自该节点
 type Message_Unregister struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Ns            string                 `protobuf:"bytes,1,opt,name=ns,proto3" json:"ns,omitempty"`
 	Id            []byte                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	SignedRecord  []byte                 `protobuf:"bytes,3,opt,name=signed_record,json=signedRecord,proto3" json:"signed_record,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -407,6 +536,13 @@ func (x *Message_Unregister) GetId() []byte {
 	return nil
 }
 
+func (x *Message_Unregister) GetSignedRecord() []byte {
+	if x != nil {
+		return x.SignedRecord
+	}
+	return nil
+}
+
 // Discover 发现请求
 type Message_Discover struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -598,11 +734,579 @@ func (x *Message_Registration) GetTtl() uint64 {
 	return 0
 }
 
+// DiscoverSubscribe 订阅命名空间变更（长连接流）
+// cookie 为上次收到的事件 cookie，用于断线后续传
+type Message_DiscoverSubscribe struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ns            string                 `protobuf:"bytes,1,opt,name=ns,proto3" json:"ns,omitempty"`
+	Cookie        []byte                 `protobuf:"bytes,2,opt,name=cookie,proto3" json:"cookie,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Message_DiscoverSubscribe) Reset() {
+	*x = Message_DiscoverSubscribe{}
+	mi := &file_rendezvous_rendezvous_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Message_DiscoverSubscribe) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message_DiscoverSubscribe) ProtoMessage() {}
+
+func (x *Message_DiscoverSubscribe) ProtoReflect() protoreflect.Message {
+	mi := &file_rendezvous_rendezvous_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message_DiscoverSubscribe.ProtoReflect.Descriptor instead.
+func (*Message_DiscoverSubscribe) Descriptor() ([]byte, []int) {
+	return file_rendezvous_rendezvous_proto_rawDescGZIP(), []int{0, 6}
+}
+
+func (x *Message_DiscoverSubscribe) GetNs() string {
+	if x != nil {
+		return x.Ns
+	}
+	return ""
+}
+
+func (x *Message_DiscoverSubscribe) GetCookie() []byte {
+	if x != nil {
+		return x.Cookie
+	}
+	return nil
+}
+
+// DiscoverSubscribeResponse 订阅响应
+// resumed 为 false 时 registrations 为命名空间当前完整快照
+type Message_DiscoverSubscribeResponse struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Status        Message_ResponseStatus  `protobuf:"varint,1,opt,name=status,proto3,enum=dep2p.rendezvous.Message_ResponseStatus" json:"status,omitempty"`
+	StatusText    string                  `protobuf:"bytes,2,opt,name=status_text,json=statusText,proto3" json:"status_text,omitempty"`
+	Resumed       bool                    `protobuf:"varint,3,opt,name=resumed,proto3" json:"resumed,omitempty"`
+	Registrations []*Message_Registration `protobuf:"bytes,4,rep,name=registrations,proto3" json:"registrations,omitempty"`
+	Cookie        []byte                  `protobuf:"bytes,5,opt,name=cookie,proto3" json:"cookie,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Message_DiscoverSubscribeResponse) Reset() {
+	*x = Message_DiscoverSubscribeResponse{}
+	mi := &file_rendezvous_rendezvous_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Message_DiscoverSubscribeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message_DiscoverSubscribeResponse) ProtoMessage() {}
+
+func (x *Message_DiscoverSubscribeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rendezvous_rendezvous_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message_DiscoverSubscribeResponse.ProtoReflect.Descriptor instead.
+func (*Message_DiscoverSubscribeResponse) Descriptor() ([]byte, []int) {
+	return file_rendezvous_rendezvous_proto_rawDescGZIP(), []int{0, 7}
+}
+
+func (x *Message_DiscoverSubscribeResponse) GetStatus() Message_ResponseStatus {
+	if x != nil {
+		return x.Status
+	}
+	return Message_OK
+}
+
+func (x *Message_DiscoverSubscribeResponse) GetStatusText() string {
+	if x != nil {
+		return x.StatusText
+	}
+	return ""
+}
+
+func (x *Message_DiscoverSubscribeResponse) GetResumed() bool {
+	if x != nil {
+		return x.Resumed
+	}
+	return false
+}
+
+func (x *Message_DiscoverSubscribeResponse) GetRegistrations() []*Message_Registration {
+	if x != nil {
+		return x.Registrations
+	}
+	return nil
+}
+
+func (x *Message_DiscoverSubscribeResponse) GetCookie() []byte {
+	if x != nil {
+		return x.Cookie
+	}
+	return nil
+}
+
+// DiscoverEvent 命名空间变更事件
+type Message_DiscoverEvent struct {
+	state         protoimpl.MessageState          `protogen:"open.v1"`
+	Type          Message_DiscoverEvent_EventType `protobuf:"varint,1,opt,name=type,proto3,enum=dep2p.rendezvous.Message_DiscoverEvent_EventType" json:"type,omitempty"`
+	Registration  *Message_Registration           `protobuf:"bytes,2,opt,name=registration,proto3" json:"registration,omitempty"`
+	Cookie        []byte                          `protobuf:"bytes,3,opt,name=cookie,proto3" json:"cookie,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Message_DiscoverEvent) Reset() {
+	*x = Message_DiscoverEvent{}
+	mi := &file_rendezvous_rendezvous_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Message_DiscoverEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message_DiscoverEvent) ProtoMessage() {}
+
+func (x *Message_DiscoverEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_rendezvous_rendezvous_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message_DiscoverEvent.ProtoReflect.Descriptor instead.
+func (*Message_DiscoverEvent) Descriptor() ([]byte, []int) {
+	return file_rendezvous_rendezvous_proto_rawDescGZIP(), []int{0, 8}
+}
+
+func (x *Message_DiscoverEvent) GetType() Message_DiscoverEvent_EventType {
+	if x != nil {
+		return x.Type
+	}
+	return Message_DiscoverEvent_ADDED
+}
+
+func (x *Message_DiscoverEvent) GetRegistration() *Message_Registration {
+	if x != nil {
+		return x.Registration
+	}
+	return nil
+}
+
+func (x *Message_DiscoverEvent) GetCookie() []byte {
+	if x != nil {
+		return x.Cookie
+	}
+	return nil
+}
+
+// ReplicaEntry 复制条目（Point 之间同步的单条注册状态）
+// signed_peer_record 为客户端提交的原始记录（墓碑为客户端签名的取消注册记录），接收方可独立验证
+type Message_ReplicaEntry struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Ns               string                 `protobuf:"bytes,1,opt,name=ns,proto3" json:"ns,omitempty"`
+	Peer             []byte                 `protobuf:"bytes,2,opt,name=peer,proto3" json:"peer,omitempty"`
+	SignedPeerRecord []byte                 `protobuf:"bytes,3,opt,name=signed_peer_record,json=signedPeerRecord,proto3" json:"signed_peer_record,omitempty"`
+	ExpiresAt        int64                  `protobuf:"varint,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Deleted          bool                   `protobuf:"varint,5,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	Origin           []byte                 `protobuf:"bytes,6,opt,name=origin,proto3" json:"origin,omitempty"`
+	Counter          uint64                 `protobuf:"varint,7,opt,name=counter,proto3" json:"counter,omitempty"`
+	Timestamp        int64                  `protobuf:"varint,8,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Message_ReplicaEntry) Reset() {
+	*x = Message_ReplicaEntry{}
+	mi := &file_rendezvous_rendezvous_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Message_ReplicaEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message_ReplicaEntry) ProtoMessage() {}
+
+func (x *Message_ReplicaEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_rendezvous_rendezvous_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message_ReplicaEntry.ProtoReflect.Descriptor instead.
+func (*Message_ReplicaEntry) Descriptor() ([]byte, []int) {
+	return file_rendezvous_rendezvous_proto_rawDescGZIP(), []int{0, 9}
+}
+
+func (x *Message_ReplicaEntry) GetNs() string {
+	if x != nil {
+		return x.Ns
+	}
+	return ""
+}
+
+func (x *Message_ReplicaEntry) GetPeer() []byte {
+	if x != nil {
+		return x.Peer
+	}
+	return nil
+}
+
+func (x *Message_ReplicaEntry) GetSignedPeerRecord() []byte {
+	if x != nil {
+		return x.SignedPeerRecord
+	}
+	return nil
+}
+
+func (x *Message_ReplicaEntry) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *Message_ReplicaEntry) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+func (x *Message_ReplicaEntry) GetOrigin() []byte {
+	if x != nil {
+		return x.Origin
+	}
+	return nil
+}
+
+func (x *Message_ReplicaEntry) GetCounter() uint64 {
+	if x != nil {
+		return x.Counter
+	}
+	return 0
+}
+
+func (x *Message_ReplicaEntry) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+// Version 版本向量中的单个分量
+type Message_Version struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Origin        []byte                 `protobuf:"bytes,1,opt,name=origin,proto3" json:"origin,omitempty"`
+	Counter       uint64                 `protobuf:"varint,2,opt,name=counter,proto3" json:"counter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Message_Version) Reset() {
+	*x = Message_Version{}
+	mi := &file_rendezvous_rendezvous_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Message_Version) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message_Version) ProtoMessage() {}
+
+func (x *Message_Version) ProtoReflect() protoreflect.Message {
+	mi := &file_rendezvous_rendezvous_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message_Version.ProtoReflect.Descriptor instead.
+func (*Message_Version) Descriptor() ([]byte, []int) {
+	return file_rendezvous_rendezvous_proto_rawDescGZIP(), []int{0, 10}
+}
+
+func (x *Message_Version) GetOrigin() []byte {
+	if x != nil {
+		return x.Origin
+	}
+	return nil
+}
+
+func (x *Message_Version) GetCounter() uint64 {
+	if x != nil {
+		return x.Counter
+	}
+	return 0
+}
+
+// Replicate 推送本地产生的注册增量
+type Message_Replicate struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Entries       []*Message_ReplicaEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Message_Replicate) Reset() {
+	*x = Message_Replicate{}
+	mi := &file_rendezvous_rendezvous_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Message_Replicate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message_Replicate) ProtoMessage() {}
+
+func (x *Message_Replicate) ProtoReflect() protoreflect.Message {
+	mi := &file_rendezvous_rendezvous_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message_Replicate.ProtoReflect.Descriptor instead.
+func (*Message_Replicate) Descriptor() ([]byte, []int) {
+	return file_rendezvous_rendezvous_proto_rawDescGZIP(), []int{0, 11}
+}
+
+func (x *Message_Replicate) GetEntries() []*Message_ReplicaEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+// ReplicateResponse 推送响应
+type Message_ReplicateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        Message_ResponseStatus `protobuf:"varint,1,opt,name=status,proto3,enum=dep2p.rendezvous.Message_ResponseStatus" json:"status,omitempty"`
+	StatusText    string                 `protobuf:"bytes,2,opt,name=status_text,json=statusText,proto3" json:"status_text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Message_ReplicateResponse) Reset() {
+	*x = Message_ReplicateResponse{}
+	mi := &file_rendezvous_rendezvous_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Message_ReplicateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message_ReplicateResponse) ProtoMessage() {}
+
+func (x *Message_ReplicateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rendezvous_rendezvous_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message_ReplicateResponse.ProtoReflect.Descriptor instead.
+func (*Message_ReplicateResponse) Descriptor() ([]byte, []int) {
+	return file_rendezvous_rendezvous_proto_rawDescGZIP(), []int{0, 12}
+}
+
+func (x *Message_ReplicateResponse) GetStatus() Message_ResponseStatus {
+	if x != nil {
+		return x.Status
+	}
+	return Message_OK
+}
+
+func (x *Message_ReplicateResponse) GetStatusText() string {
+	if x != nil {
+		return x.StatusText
+	}
+	return ""
+}
+
+// Sync 反熵同步请求，携带请求方的版本向量
+type Message_Sync struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Versions      []*Message_Version     `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Message_Sync) Reset() {
+	*x = Message_Sync{}
+	mi := &file_rendezvous_rendezvous_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Message_Sync) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message_Sync) ProtoMessage() {}
+
+func (x *Message_Sync) ProtoReflect() protoreflect.Message {
+	mi := &file_rendezvous_rendezvous_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message_Sync.ProtoReflect.Descriptor instead.
+func (*Message_Sync) Descriptor() ([]byte, []int) {
+	return file_rendezvous_rendezvous_proto_rawDescGZIP(), []int{0, 13}
+}
+
+func (x *Message_Sync) GetVersions() []*Message_Version {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+// SyncResponse 反熵同步响应（分批发送，done 为 true 的最后一批携带版本向量）
+type Message_SyncResponse struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Status        Message_ResponseStatus  `protobuf:"varint,1,opt,name=status,proto3,enum=dep2p.rendezvous.Message_ResponseStatus" json:"status,omitempty"`
+	StatusText    string                  `protobuf:"bytes,2,opt,name=status_text,json=statusText,proto3" json:"status_text,omitempty"`
+	Entries       []*Message_ReplicaEntry `protobuf:"bytes,3,rep,name=entries,proto3" json:"entries,omitempty"`
+	Versions      []*Message_Version      `protobuf:"bytes,4,rep,name=versions,proto3" json:"versions,omitempty"`
+	Done          bool                    `protobuf:"varint,5,opt,name=done,proto3" json:"done,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Message_SyncResponse) Reset() {
+	*x = Message_SyncResponse{}
+	mi := &file_rendezvous_rendezvous_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Message_SyncResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message_SyncResponse) ProtoMessage() {}
+
+func (x *Message_SyncResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rendezvous_rendezvous_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message_SyncResponse.ProtoReflect.Descriptor instead.
+func (*Message_SyncResponse) Descriptor() ([]byte, []int) {
+	return file_rendezvous_rendezvous_proto_rawDescGZIP(), []int{0, 14}
+}
+
+func (x *Message_SyncResponse) GetStatus() Message_ResponseStatus {
+	if x != nil {
+		return x.Status
+	}
+	return Message_OK
+}
+
+func (x *Message_SyncResponse) GetStatusText() string {
+	if x != nil {
+		return x.StatusText
+	}
+	return ""
+}
+
+func (x *Message_SyncResponse) GetEntries() []*Message_ReplicaEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *Message_SyncResponse) GetVersions() []*Message_Version {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+func (x *Message_SyncResponse) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
 var File_rendezvous_rendezvous_proto protoreflect.FileDescriptor
 
 const file_rendezvous_rendezvous_proto_rawDesc = "" +
 	"\n" +
-	"\x1brendezvous/rendezvous.proto\x12\x10dep2p.rendezvous\"\x82\v\n" +
+	"\x1brendezvous/rendezvous.proto\x12\x10dep2p.rendezvous\"\xe7\x1b\n" +
 	"\aMessage\x129\n" +
 	"\x04type\x18\x01 \x01(\x0e2%.dep2p.rendezvous.Message.MessageTypeR\x04type\x12>\n" +
 	"\bregister\x18\x02 \x01(\v2\".dep2p.rendezvous.Message.RegisterR\bregister\x12W\n" +
@@ -611,7 +1315,15 @@ const file_rendezvous_rendezvous_proto_rawDesc = "" +
 	"unregister\x18\x04 \x01(\v2$.dep2p.rendezvous.Message.UnregisterR\n" +
 	"unregister\x12>\n" +
 	"\bdiscover\x18\x05 \x01(\v2\".dep2p.rendezvous.Message.DiscoverR\bdiscover\x12W\n" +
-	"\x11discover_response\x18\x06 \x01(\v2*.dep2p.rendezvous.Message.DiscoverResponseR\x10discoverResponse\x1aZ\n" +
+	"\x11discover_response\x18\x06 \x01(\v2*.dep2p.rendezvous.Message.DiscoverResponseR\x10discoverResponse\x12Z\n" +
+	"\x12discover_subscribe\x18\a \x01(\v2+.dep2p.rendezvous.Message.DiscoverSubscribeR\x11discoverSubscribe\x12s\n" +
+	"\x1bdiscover_subscribe_response\x18\b \x01(\v23.dep2p.rendezvous.Message.DiscoverSubscribeResponseR\x19discoverSubscribeResponse\x12N\n" +
+	"\x0ediscover_event\x18\t \x01(\v2'.dep2p.rendezvous.Message.DiscoverEventR\rdiscoverEvent\x12A\n" +
+	"\treplicate\x18\n" +
+	" \x01(\v2#.dep2p.rendezvous.Message.ReplicateR\treplicate\x12Z\n" +
+	"\x12replicate_response\x18\v \x01(\v2+.dep2p.rendezvous.Message.ReplicateResponseR\x11replicateResponse\x122\n" +
+	"\x04sync\x18\f \x01(\v2\x1e.dep2p.rendezvous.Message.SyncR\x04sync\x12K\n" +
+	"\rsync_response\x18\r \x01(\v2&.dep2p.rendezvous.Message.SyncResponseR\fsyncResponse\x1aZ\n" +
 	"\bRegister\x12\x0e\n" +
 	"\x02ns\x18\x01 \x01(\tR\x02ns\x12,\n" +
 	"\x12signed_peer_record\x18\x02 \x01(\fR\x10signedPeerRecord\x12\x10\n" +
@@ -620,11 +1332,12 @@ const file_rendezvous_rendezvous_proto_rawDesc = "" +
 	"\x06status\x18\x01 \x01(\x0e2(.dep2p.rendezvous.Message.ResponseStatusR\x06status\x12\x1f\n" +
 	"\vstatus_text\x18\x02 \x01(\tR\n" +
 	"statusText\x12\x10\n" +
-	"\x03ttl\x18\x03 \x01(\x04R\x03ttl\x1a,\n" +
+	"\x03ttl\x18\x03 \x01(\x04R\x03ttl\x1aQ\n" +
 	"\n" +
 	"Unregister\x12\x0e\n" +
 	"\x02ns\x18\x01 \x01(\tR\x02ns\x12\x0e\n" +
-	"\x02id\x18\x02 \x01(\fR\x02id\x1aH\n" +
+	"\x02id\x18\x02 \x01(\fR\x02id\x12#\n" +
+	"\rsigned_record\x18\x03 \x01(\fR\fsignedRecord\x1aH\n" +
 	"\bDiscover\x12\x0e\n" +
 	"\x02ns\x18\x01 \x01(\tR\x02ns\x12\x14\n" +
 	"\x05limit\x18\x02 \x01(\x04R\x05limit\x12\x16\n" +
@@ -638,14 +1351,68 @@ const file_rendezvous_rendezvous_proto_rawDesc = "" +
 	"\fRegistration\x12\x0e\n" +
 	"\x02ns\x18\x01 \x01(\tR\x02ns\x12,\n" +
 	"\x12signed_peer_record\x18\x02 \x01(\fR\x10signedPeerRecord\x12\x10\n" +
-	"\x03ttl\x18\x03 \x01(\x04R\x03ttl\"g\n" +
+	"\x03ttl\x18\x03 \x01(\x04R\x03ttl\x1a;\n" +
+	"\x11DiscoverSubscribe\x12\x0e\n" +
+	"\x02ns\x18\x01 \x01(\tR\x02ns\x12\x16\n" +
+	"\x06cookie\x18\x02 \x01(\fR\x06cookie\x1a\xfe\x01\n" +
+	"\x19DiscoverSubscribeResponse\x12@\n" +
+	"\x06status\x18\x01 \x01(\x0e2(.dep2p.rendezvous.Message.ResponseStatusR\x06status\x12\x1f\n" +
+	"\vstatus_text\x18\x02 \x01(\tR\n" +
+	"statusText\x12\x18\n" +
+	"\aresumed\x18\x03 \x01(\bR\aresumed\x12L\n" +
+	"\rregistrations\x18\x04 \x03(\v2&.dep2p.rendezvous.Message.RegistrationR\rregistrations\x12\x16\n" +
+	"\x06cookie\x18\x05 \x01(\fR\x06cookie\x1a\xec\x01\n" +
+	"\rDiscoverEvent\x12E\n" +
+	"\x04type\x18\x01 \x01(\x0e21.dep2p.rendezvous.Message.DiscoverEvent.EventTypeR\x04type\x12J\n" +
+	"\fregistration\x18\x02 \x01(\v2&.dep2p.rendezvous.Message.RegistrationR\fregistration\x12\x16\n" +
+	"\x06cookie\x18\x03 \x01(\fR\x06cookie\"0\n" +
+	"\tEventType\x12\t\n" +
+	"\x05ADDED\x10\x00\x12\v\n" +
+	"\aEXPIRED\x10\x01\x12\v\n" +
+	"\aREMOVED\x10\x02\x1a\xe9\x01\n" +
+	"\fReplicaEntry\x12\x0e\n" +
+	"\x02ns\x18\x01 \x01(\tR\x02ns\x12\x12\n" +
+	"\x04peer\x18\x02 \x01(\fR\x04peer\x12,\n" +
+	"\x12signed_peer_record\x18\x03 \x01(\fR\x10signedPeerRecord\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\x03R\texpiresAt\x12\x18\n" +
+	"\adeleted\x18\x05 \x01(\bR\adeleted\x12\x16\n" +
+	"\x06origin\x18\x06 \x01(\fR\x06origin\x12\x18\n" +
+	"\acounter\x18\a \x01(\x04R\acounter\x12\x1c\n" +
+	"\ttimestamp\x18\b \x01(\x03R\ttimestamp\x1a;\n" +
+	"\aVersion\x12\x16\n" +
+	"\x06origin\x18\x01 \x01(\fR\x06origin\x12\x18\n" +
+	"\acounter\x18\x02 \x01(\x04R\acounter\x1aM\n" +
+	"\tReplicate\x12@\n" +
+	"\aentries\x18\x01 \x03(\v2&.dep2p.rendezvous.Message.ReplicaEntryR\aentries\x1av\n" +
+	"\x11ReplicateResponse\x12@\n" +
+	"\x06status\x18\x01 \x01(\x0e2(.dep2p.rendezvous.Message.ResponseStatusR\x06status\x12\x1f\n" +
+	"\vstatus_text\x18\x02 \x01(\tR\n" +
+	"statusText\x1aE\n" +
+	"\x04Sync\x12=\n" +
+	"\bversions\x18\x01 \x03(\v2!.dep2p.rendezvous.Message.VersionR\bversions\x1a\x86\x02\n" +
+	"\fSyncResponse\x12@\n" +
+	"\x06status\x18\x01 \x01(\x0e2(.dep2p.rendezvous.Message.ResponseStatusR\x06status\x12\x1f\n" +
+	"\vstatus_text\x18\x02 \x01(\tR\n" +
+	"statusText\x12@\n" +
+	"\aentries\x18\x03 \x03(\v2&.dep2p.rendezvous.Message.ReplicaEntryR\aentries\x12=\n" +
+	"\bversions\x18\x04 \x03(\v2!.dep2p.rendezvous.Message.VersionR\bversions\x12\x12\n" +
+	"\x04done\x18\x05 \x01(\bR\x04done\"\xf8\x01\n" +
 	"\vMessageType\x12\f\n" +
 	"\bREGISTER\x10\x00\x12\x15\n" +
 	"\x11REGISTER_RESPONSE\x10\x01\x12\x0e\n" +
 	"\n" +
 	"UNREGISTER\x10\x02\x12\f\n" +
 	"\bDISCOVER\x10\x03\x12\x15\n" +
-	"\x11DISCOVER_RESPONSE\x10\x04\"\xbe\x01\n" +
+	"\x11DISCOVER_RESPONSE\x10\x04\x12\x16\n" +
+	"\x12DISCOVER_SUBSCRIBE\x10\x05\x12\x1f\n" +
+	"\x1bDISCOVER_SUBSCRIBE_RESPONSE\x10\x06\x12\x12\n" +
+	"\x0eDISCOVER_EVENT\x10\a\x12\r\n" +
+	"\tREPLICATE\x10\b\x12\x16\n" +
+	"\x12REPLICATE_RESPONSE\x10\t\x12\b\n" +
+	"\x04SYNC\x10\n" +
+	"\x12\x11\n" +
+	"\rSYNC_RESPONSE\x10\v\"\xbe\x01\n" +
 	"\x0eResponseStatus\x12\x06\n" +
 	"\x02OK\x10\x00\x12\x17\n" +
 	"\x13E_INVALID_NAMESPACE\x10d\x12 \n" +
@@ -668,34 +1435,61 @@ func file_rendezvous_rendezvous_proto_rawDescGZIP() []byte {
 	return file_rendezvous_rendezvous_proto_rawDescData
 }
 
-var file_rendezvous_rendezvous_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_rendezvous_rendezvous_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_rendezvous_rendezvous_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_rendezvous_rendezvous_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
 var file_rendezvous_rendezvous_proto_goTypes = []any{
-	(Message_MessageType)(0),         // 0: dep2p.rendezvous.Message.MessageType
-	(Message_ResponseStatus)(0),      // 1: dep2p.rendezvous.Message.ResponseStatus
-	(*Message)(nil),                  // 2: dep2p.rendezvous.Message
-	(*Message_Register)(nil),         // 3: dep2p.rendezvous.Message.Register
-	(*Message_RegisterResponse)(nil), // 4: dep2p.rendezvous.Message.RegisterResponse
-	(*Message_Unregister)(nil),       // 5: dep2p.rendezvous.Message.Unregister
-	(*Message_Discover)(nil),         // 6: dep2p.rendezvous.Message.Discover
-	(*Message_DiscoverResponse)(nil), // 7: dep2p.rendezvous.Message.DiscoverResponse
-	(*Message_Registration)(nil),     // 8: dep2p.rendezvous.Message.Registration
+	(Message_MessageType)(0),                  // 0: dep2p.rendezvous.Message.MessageType
+	(Message_ResponseStatus)(0),               // 1: dep2p.rendezvous.Message.ResponseStatus
+	(Message_DiscoverEvent_EventType)(0),      // 2: dep2p.rendezvous.Message.DiscoverEvent.EventType
+	(*Message)(nil),                           // 3: dep2p.rendezvous.Message
+	(*Message_Register)(nil),                  // 4: dep2p.rendezvous.Message.Register
+	(*Message_RegisterResponse)(nil),          // 5: dep2p.rendezvous.Message.RegisterResponse
+	(*Message_Unregister)(nil),                // 6: dep2p.rendezvous.Message.Unregister
+	(*Message_Discover)(nil),                  // 7: dep2p.rendezvous.Message.Discover
+	(*Message_DiscoverResponse)(nil),          // 8: dep2p.rendezvous.Message.DiscoverResponse
+	(*Message_Registration)(nil),              // 9: dep2p.rendezvous.Message.Registration
+	(*Message_DiscoverSubscribe)(nil),         // 10: dep2p.rendezvous.Message.DiscoverSubscribe
+	(*Message_DiscoverSubscribeResponse)(nil), // 11: dep2p.rendezvous.Message.DiscoverSubscribeResponse
+	(*Message_DiscoverEvent)(nil),             // 12: dep2p.rendezvous.Message.DiscoverEvent
+	(*Message_ReplicaEntry)(nil),              // 13: dep2p.rendezvous.Message.ReplicaEntry
+	(*Message_Version)(nil),                   // 14: dep2p.rendezvous.Message.Version
+	(*Message_Replicate)(nil),                 // 15: dep2p.rendezvous.Message.Replicate
+	(*Message_ReplicateResponse)(nil),         // 16: dep2p.rendezvous.Message.ReplicateResponse
+	(*Message_Sync)(nil),                      // 17: dep2p.rendezvous.Message.Sync
+	(*Message_SyncResponse)(nil),              // 18: dep2p.rendezvous.Message.SyncResponse
 }
 var file_rendezvous_rendezvous_proto_depIdxs = []int32{
-	0, // 0: dep2p.rendezvous.Message.type:type_name -> dep2p.rendezvous.Message.MessageType
-	3, // 1: dep2p.rendezvous.Message.register:type_name -> dep2p.rendezvous.Message.Register
-	4, // 2: dep2p.rendezvous.Message.register_response:type_name -> dep2p.rendezvous.Message.RegisterResponse
-	5, // 3: dep2p.rendezvous.Message.unregister:type_name -> dep2p.rendezvous.Message.Unregister
-	6, // 4: dep2p.rendezvous.Message.discover:type_name -> dep2p.rendezvous.Message.Discover
-	7, // 5: dep2p.rendezvous.Message.discover_response:type_name -> dep2p.rendezvous.Message.DiscoverResponse
-	1, // 6: dep2p.rendezvous.Message.RegisterResponse.status:type_name -> dep2p.rendezvous.Message.ResponseStatus
-	8, // 7: dep2p.rendezvous.Message.DiscoverResponse.registrations:type_name -> dep2p.rendezvous.Message.Registration
-	1, // 8: dep2p.rendezvous.Message.DiscoverResponse.status:type_name -> dep2p.rendezvous.Message.ResponseStatus
-	9, // [9:9] is the sub-list for method output_type
-	9, // [9:9] is the sub-list for method input_type
-	9, // [9:9] is the sub-list for extension type_name
-	9, // [9:9] is the sub-list for extension extendee
-	0, // [0:9] is the sub-list for field type_name
+	0,  // 0: dep2p.rendezvous.Message.type:type_name -> dep2p.rendezvous.Message.MessageType
+	4,  // 1: dep2p.rendezvous.Message.register:type_name -> dep2p.rendezvous.Message.Register
+	5,  // 2: dep2p.rendezvous.Message.register_response:type_name -> dep2p.rendezvous.Message.RegisterResponse
+	6,  // 3: dep2p.rendezvous.Message.unregister:type_name -> dep2p.rendezvous.Message.Unregister
+	7,  // 4: dep2p.rendezvous.Message.discover:type_name -> dep2p.rendezvous.Message.Discover
+	8,  // 5: dep2p.rendezvous.Message.discover_response:type_name -> dep2p.rendezvous.Message.DiscoverResponse
+	10, // 6: dep2p.rendezvous.Message.discover_subscribe:type_name -> dep2p.rendezvous.Message.DiscoverSubscribe
+	11, // 7: dep2p.rendezvous.Message.discover_subscribe_response:type_name -> dep2p.rendezvous.Message.DiscoverSubscribeResponse
+	12, // 8: dep2p.rendezvous.Message.discover_event:type_name -> dep2p.rendezvous.Message.DiscoverEvent
+	15, // 9: dep2p.rendezvous.Message.replicate:type_name -> dep2p.rendezvous.Message.Replicate
+	16, // 10: dep2p.rendezvous.Message.replicate_response:type_name -> dep2p.rendezvous.Message.ReplicateResponse
+	17, // 11: dep2p.rendezvous.Message.sync:type_name -> dep2p.rendezvous.Message.Sync
+	18, // 12: dep2p.rendezvous.Message.sync_response:type_name -> dep2p.rendezvous.Message.SyncResponse
+	1,  // 13: dep2p.rendezvous.Message.RegisterResponse.status:type_name -> dep2p.rendezvous.Message.ResponseStatus
+	9,  // 14: dep2p.rendezvous.Message.DiscoverResponse.registrations:type_name -> dep2p.rendezvous.Message.Registration
+	1,  // 15: dep2p.rendezvous.Message.DiscoverResponse.status:type_name -> dep2p.rendezvous.Message.ResponseStatus
+	1,  // 16: dep2p.rendezvous.Message.DiscoverSubscribeResponse.status:type_name -> dep2p.rendezvous.Message.ResponseStatus
+	9,  // 17: dep2p.rendezvous.Message.DiscoverSubscribeResponse.registrations:type_name -> dep2p.rendezvous.Message.Registration
+	2,  // 18: dep2p.rendezvous.Message.DiscoverEvent.type:type_name -> dep2p.rendezvous.Message.DiscoverEvent.EventType
+	9,  // 19: dep2p.rendezvous.Message.DiscoverEvent.registration:type_name -> dep2p.rendezvous.Message.Registration
+	13, // 20: dep2p.rendezvous.Message.Replicate.entries:type_name -> dep2p.rendezvous.Message.ReplicaEntry
+	1,  // 21: dep2p.rendezvous.Message.ReplicateResponse.status:type_name -> dep2p.rendezvous.Message.ResponseStatus
+	14, // 22: dep2p.rendezvous.Message.Sync.versions:type_name -> dep2p.rendezvous.Message.Version
+	1,  // 23: dep2p.rendezvous.Message.SyncResponse.status:type_name -> dep2p.rendezvous.Message.ResponseStatus
+	13, // 24: dep2p.rendezvous.Message.SyncResponse.entries:type_name -> dep2p.rendezvous.Message.ReplicaEntry
+	14, // 25: dep2p.rendezvous.Message.SyncResponse.versions:type_name -> dep2p.rendezvous.Message.Version
+	26, // [26:26] is the sub-list for method output_type
+	26, // [26:26] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
 }
 
 func init() { file_rendezvous_rendezvous_proto_init() }
@@ -708,8 +1502,8 @@ func file_rendezvous_rendezvous_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rendezvous_rendezvous_proto_rawDesc), len(file_rendezvous_rendezvous_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   7,
+			NumEnums:      3,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
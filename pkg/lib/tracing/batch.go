@@ -0,0 +1,151 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 批量导出参数
+const (
+	// defaultQueueSize 待导出 Span 队列容量，队列满时丢弃新 Span
+	defaultQueueSize = 2048
+
+	// defaultBatchSize 单次导出的最大 Span 数
+	defaultBatchSize = 256
+
+	// defaultBatchTimeout 未攒满一批时的最长等待时间
+	defaultBatchTimeout = time.Second
+)
+
+// droppedSpans 因队列满或处理器已停止而丢弃的 Span 总数
+var droppedSpans atomic.Uint64
+
+// DroppedSpans 返回因导出队列满而丢弃的 Span 总数
+func DroppedSpans() uint64 {
+	return droppedSpans.Load()
+}
+
+// batchProcessor 后台批量导出器
+//
+// Span.End 只把快照放入有界队列后立即返回，不会因 Exporter 变慢而阻塞
+// 业务路径；后台 goroutine 按 batchSize 或 batchTimeout 批量调用
+// ExportSpans。队列满时直接丢弃并计数。
+type batchProcessor struct {
+	exporter  Exporter
+	batchSize int
+	timeout   time.Duration
+
+	queue   chan SpanData
+	flushCh chan chan struct{}
+	stopCh  chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+// newBatchProcessor 创建并启动批量导出器
+func newBatchProcessor(e Exporter, queueSize, batchSize int, timeout time.Duration) *batchProcessor {
+	p := &batchProcessor{
+		exporter:  e,
+		batchSize: batchSize,
+		timeout:   timeout,
+		queue:     make(chan SpanData, queueSize),
+		flushCh:   make(chan chan struct{}),
+		stopCh:    make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go p.loop()
+	return p
+}
+
+// enqueue 放入待导出队列，队列满或已停止时丢弃
+func (p *batchProcessor) enqueue(data SpanData) {
+	select {
+	case <-p.stopCh:
+		droppedSpans.Add(1)
+		return
+	default:
+	}
+	select {
+	case p.queue <- data:
+	default:
+		droppedSpans.Add(1)
+	}
+}
+
+// flush 导出队列中已有的全部 Span
+func (p *batchProcessor) flush(ctx context.Context) error {
+	ack := make(chan struct{})
+	select {
+	case p.flushCh <- ack:
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-ack:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// stop 导出剩余 Span 后退出后台 goroutine（不关闭 Exporter）
+func (p *batchProcessor) stop() {
+	p.once.Do(func() { close(p.stopCh) })
+	<-p.done
+}
+
+// loop 后台导出循环
+func (p *batchProcessor) loop() {
+	defer close(p.done)
+
+	batch := make([]SpanData, 0, p.batchSize)
+	export := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := p.exporter.ExportSpans(context.Background(), batch); err != nil {
+			logger.Debug("导出 Span 失败", "count", len(batch), "error", err)
+		}
+		batch = make([]SpanData, 0, p.batchSize)
+	}
+	drain := func() {
+		for {
+			select {
+			case data := <-p.queue:
+				batch = append(batch, data)
+				if len(batch) >= p.batchSize {
+					export()
+				}
+			default:
+				export()
+				return
+			}
+		}
+	}
+
+	timer := time.NewTimer(p.timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case data := <-p.queue:
+			batch = append(batch, data)
+			if len(batch) >= p.batchSize {
+				export()
+			}
+		case <-timer.C:
+			export()
+			timer.Reset(p.timeout)
+		case ack := <-p.flushCh:
+			drain()
+			close(ack)
+		case <-p.stopCh:
+			drain()
+			return
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// W3C trace-context 头部名称
+const (
+	// HeaderTraceparent traceparent 头部
+	HeaderTraceparent = "traceparent"
+
+	// HeaderTracestate tracestate 头部
+	HeaderTracestate = "tracestate"
+)
+
+// maxTracestateLen tracestate 最大长度（W3C 规定 32 个成员，按 512 字节截断）
+const maxTracestateLen = 512
+
+// 错误定义
+var (
+	// ErrInvalidTraceparent traceparent 格式无效
+	ErrInvalidTraceparent = errors.New("tracing: invalid traceparent")
+
+	// ErrHeaderTooLarge 流头部超过上限
+	ErrHeaderTooLarge = errors.New("tracing: stream header too large")
+)
+
+// TraceID 追踪 ID（16 字节）
+type TraceID [16]byte
+
+// String 返回 32 位小写十六进制
+func (t TraceID) String() string { return hex.EncodeToString(t[:]) }
+
+// IsValid 全零 ID 无效
+func (t TraceID) IsValid() bool { return t != TraceID{} }
+
+// SpanID Span ID（8 字节）
+type SpanID [8]byte
+
+// String 返回 16 位小写十六进制
+func (s SpanID) String() string { return hex.EncodeToString(s[:]) }
+
+// IsValid 全零 ID 无效
+func (s SpanID) IsValid() bool { return s != SpanID{} }
+
+// FlagSampled 采样标志位
+const FlagSampled byte = 0x01
+
+// SpanContext 跨节点传播的追踪上下文
+type SpanContext struct {
+	// TraceID 追踪 ID，同一业务操作的所有 Span 共享
+	TraceID TraceID
+
+	// SpanID 当前 Span ID，远端据此设置父 Span
+	SpanID SpanID
+
+	// Flags trace-flags（目前只使用采样位）
+	Flags byte
+
+	// State tracestate 原文（透传，不解析）
+	State string
+
+	// Remote 是否来自远端
+	Remote bool
+}
+
+// IsValid TraceID 与 SpanID 均非零
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// IsSampled 是否采样
+func (sc SpanContext) IsSampled() bool {
+	return sc.Flags&FlagSampled != 0
+}
+
+// Traceparent 返回 W3C traceparent 头部值（version 00）
+func (sc SpanContext) Traceparent() string {
+	if !sc.IsValid() {
+		return ""
+	}
+	var b strings.Builder
+	b.Grow(55)
+	b.WriteString("00-")
+	b.WriteString(sc.TraceID.String())
+	b.WriteByte('-')
+	b.WriteString(sc.SpanID.String())
+	b.WriteByte('-')
+	b.WriteString(hex.EncodeToString([]byte{sc.Flags}))
+	return b.String()
+}
+
+// ParseTraceparent 解析 W3C traceparent 头部
+//
+// 格式：version-traceid-parentid-flags。未知的更高版本按 version 00
+// 的前缀解析；version ff 与全零 ID 视为无效。
+func ParseTraceparent(s string) (SpanContext, error) {
+	var sc SpanContext
+	s = strings.TrimSpace(s)
+	if len(s) < 55 || s[2] != '-' || s[35] != '-' || s[52] != '-' {
+		return sc, ErrInvalidTraceparent
+	}
+	version, err := decodeLowerHex(s[:2])
+	if err != nil || version[0] == 0xff {
+		return sc, ErrInvalidTraceparent
+	}
+	// version 00 必须恰好 55 字节；更高版本允许以 '-' 追加字段
+	if version[0] == 0 && len(s) != 55 {
+		return sc, ErrInvalidTraceparent
+	}
+	if len(s) > 55 && s[55] != '-' {
+		return sc, ErrInvalidTraceparent
+	}
+
+	traceID, err := decodeLowerHex(s[3:35])
+	if err != nil {
+		return sc, ErrInvalidTraceparent
+	}
+	spanID, err := decodeLowerHex(s[36:52])
+	if err != nil {
+		return sc, ErrInvalidTraceparent
+	}
+	flags, err := decodeLowerHex(s[53:55])
+	if err != nil {
+		return sc, ErrInvalidTraceparent
+	}
+
+	copy(sc.TraceID[:], traceID)
+	copy(sc.SpanID[:], spanID)
+	sc.Flags = flags[0]
+	if !sc.IsValid() {
+		return SpanContext{}, ErrInvalidTraceparent
+	}
+	return sc, nil
+}
+
+// decodeLowerHex 解码小写十六进制（W3C 不允许大写）
+func decodeLowerHex(s string) ([]byte, error) {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return nil, ErrInvalidTraceparent
+		}
+	}
+	return hex.DecodeString(s)
+}
+
+// ============================================================================
+//                              Context 传递
+// ============================================================================
+
+type spanContextKey struct{}
+
+type spanKey struct{}
+
+// ContextWithSpanContext 返回携带追踪上下文的 Context
+func ContextWithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, sc)
+}
+
+// ContextWithTraceparent 解析 traceparent/tracestate 并写入 Context
+//
+// traceparent 为空或无效时原样返回 ctx。
+func ContextWithTraceparent(ctx context.Context, traceparent, tracestate string) context.Context {
+	if traceparent == "" {
+		return ctx
+	}
+	sc, err := ParseTraceparent(traceparent)
+	if err != nil {
+		return ctx
+	}
+	if len(tracestate) <= maxTracestateLen {
+		sc.State = tracestate
+	}
+	sc.Remote = true
+	return ContextWithSpanContext(ctx, sc)
+}
+
+// SpanContextFromContext 返回 Context 中的追踪上下文
+//
+// 有活动 Span 时即为该 Span 的上下文；没有时返回零值。
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	if ctx == nil {
+		return SpanContext{}
+	}
+	sc, _ := ctx.Value(spanContextKey{}).(SpanContext)
+	return sc
+}
+
+// SpanFromContext 返回 Context 中的活动 Span（可能为 nil）
+func SpanFromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// ============================================================================
+//                              载体传播
+// ============================================================================
+
+// Inject 将 Context 中的追踪上下文写入载体（如 Request.Metadata）
+//
+// 没有有效追踪上下文时不修改载体。
+func Inject(ctx context.Context, carrier map[string]string) {
+	if carrier == nil {
+		return
+	}
+	sc := SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+	carrier[HeaderTraceparent] = sc.Traceparent()
+	if sc.State != "" {
+		carrier[HeaderTracestate] = sc.State
+	} else {
+		delete(carrier, HeaderTracestate)
+	}
+}
+
+// Extract 从载体读取追踪上下文并写入 Context
+func Extract(ctx context.Context, carrier map[string]string) context.Context {
+	if carrier == nil {
+		return ctx
+	}
+	return ContextWithTraceparent(ctx, carrier[HeaderTraceparent], carrier[HeaderTracestate])
+}
+
+// AppendTraceContext 在二进制载荷后追加追踪上下文
+//
+// 格式："<payload>\n<traceparent>[\n<tracestate>]"，用于载荷本身不含换行的
+// 消息（如中继 CONNECT/STOP 中的节点 ID）。sc 无效时原样返回 payload，
+// 不参与追踪的节点发出的消息与旧格式一致。
+func AppendTraceContext(payload []byte, sc SpanContext) []byte {
+	if !sc.IsValid() {
+		return payload
+	}
+	out := make([]byte, 0, len(payload)+1+55+1+len(sc.State))
+	out = append(out, payload...)
+	out = append(out, '\n')
+	out = append(out, sc.Traceparent()...)
+	if sc.State != "" && len(sc.State) <= maxTracestateLen {
+		out = append(out, '\n')
+		out = append(out, sc.State...)
+	}
+	return out
+}
+
+// SplitTraceContext 拆分 AppendTraceContext 生成的数据
+//
+// 返回原始载荷及远端追踪上下文；没有追踪上下文或 traceparent 无效时
+// 返回零值 SpanContext。
+func SplitTraceContext(data []byte) ([]byte, SpanContext) {
+	payload, rest, found := strings.Cut(string(data), "\n")
+	if !found {
+		return data, SpanContext{}
+	}
+	traceparent, tracestate, _ := strings.Cut(rest, "\n")
+	sc := SpanContextFromContext(ContextWithTraceparent(context.Background(), traceparent, tracestate))
+	return []byte(payload), sc
+}
+
+// ============================================================================
+//                              ID 生成
+// ============================================================================
+
+// newTraceID 生成随机 TraceID
+func newTraceID() TraceID {
+	var id TraceID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
+
+// newSpanID 生成随机 SpanID
+func newSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
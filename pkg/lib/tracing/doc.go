@@ -0,0 +1,50 @@
+// Package tracing 提供跨节点的分布式追踪
+//
+// 本包实现 W3C trace-context（traceparent / tracestate）传播与轻量级
+// Span 记录，使一次业务操作在多个节点上产生的 Span 能按同一 TraceID
+// 串联起来。
+//
+// # 传播方式
+//
+// 追踪上下文随 Context 在本地传递，跨节点时按协议写入：
+//
+//	Messaging  Request.Metadata["traceparent"] / ["tracestate"]
+//	PubSub     Message.TraceParent / Message.TraceState（protobuf 字段 7、8）
+//	Streams    协议追踪变体 "+trace"，流首部携带 WriteStreamHeader 头部
+//	Relay      CONNECT / STOP 消息体在节点 ID 后追加 traceparent（AppendTraceContext）
+//
+// Streams 的追踪变体与压缩变体一样通过 multistream-select 协商，
+// 旧节点不注册变体，打开方自然回退到无追踪的原协议。
+//
+// # Span
+//
+// 内置记录的 Span：
+//
+//	dep2p.swarm.dial                  拨号（Swarm）
+//	dep2p.host.negotiate              出站流协议协商（Host）
+//	dep2p.relay.connect               经中继建立电路（中继客户端）
+//	dep2p.relay.hop                   中继转发电路（中继服务端）
+//	dep2p.relay.stop                  接受中继电路（目标节点）
+//	dep2p.messaging.send / handle     请求发送与处理
+//	dep2p.pubsub.publish / deliver    消息发布与投递
+//	dep2p.streams.open / handle       流打开与处理
+//
+// Span 名称统一为 dep2p.<组件>.<操作>。
+//
+// 应用可用 Start 记录自己的 Span，子 Span 自动继承追踪上下文：
+//
+//	ctx, span := tracing.Start(ctx, "order.create", tracing.KindInternal)
+//	defer span.End(err)
+//	realm.Messaging().Send(ctx, peer, "order", data) // 远端 handle Span 的父 Span 为 dep2p.messaging.send
+//
+// # 导出
+//
+// Span 结束时放入有界队列，由后台 goroutine 批量交给全局 Exporter
+// （SetExporter）；队列满时丢弃新 Span（DroppedSpans 计数），导出变慢不会
+// 阻塞业务路径。ForceFlush 立即导出队列中的 Span。未设置 Exporter 时 Start
+// 返回 nil Span（所有方法为空操作），远端传来的上下文仍原样透传，
+// 不参与追踪的节点不会打断调用链。
+//
+// FileExporter 以 OTLP/JSON 行格式写入本地文件，无需网络；
+// MemoryExporter 用于测试。
+package tracing
@@ -0,0 +1,234 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// ErrExporterClosed Exporter 已关闭
+var ErrExporterClosed = errors.New("tracing: exporter closed")
+
+// Exporter Span 导出器
+//
+// ExportSpans 由后台批量导出 goroutine 调用，每次传入一批已结束的 Span，
+// 不会并发调用。实现变慢只会使导出队列积压，队列满时新 Span 被丢弃，
+// 不会阻塞业务路径。
+type Exporter interface {
+	// ExportSpans 导出已结束的 Span
+	ExportSpans(ctx context.Context, spans []SpanData) error
+
+	// Shutdown 刷新并释放资源，之后的导出返回错误
+	Shutdown(ctx context.Context) error
+}
+
+// ============================================================================
+//                              文件导出器
+// ============================================================================
+
+// FileExporter 以 OTLP/JSON 格式把 Span 写入本地文件
+//
+// 每次导出写一行 ExportTraceServiceRequest JSON（与 OpenTelemetry
+// Collector file exporter 的格式一致），可直接被 otelcol 的 otlpjsonfile
+// receiver 读取后转发到 Jaeger/Tempo 等后端，无需节点访问网络。
+type FileExporter struct {
+	mu       sync.Mutex
+	file     *os.File
+	resource []Attribute
+}
+
+// NewFileExporter 创建文件导出器，文件以追加模式打开
+//
+// resource 为资源属性（如 service.name、节点 ID），附加在每一行上。
+func NewFileExporter(path string, resource ...Attribute) (*FileExporter, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &FileExporter{file: f, resource: resource}, nil
+}
+
+// ExportSpans 写入一行 OTLP/JSON
+func (e *FileExporter) ExportSpans(_ context.Context, spans []SpanData) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	line, err := json.Marshal(toOTLP(e.resource, spans))
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.file == nil {
+		return ErrExporterClosed
+	}
+	_, err = e.file.Write(line)
+	return err
+}
+
+// Shutdown 关闭文件
+func (e *FileExporter) Shutdown(_ context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.file == nil {
+		return nil
+	}
+	err := e.file.Close()
+	e.file = nil
+	return err
+}
+
+// ============================================================================
+//                              内存导出器
+// ============================================================================
+
+// MemoryExporter 把 Span 保存在内存中，用于测试和调试
+type MemoryExporter struct {
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+// NewMemoryExporter 创建内存导出器
+func NewMemoryExporter() *MemoryExporter {
+	return &MemoryExporter{}
+}
+
+// ExportSpans 保存 Span
+func (e *MemoryExporter) ExportSpans(_ context.Context, spans []SpanData) error {
+	e.mu.Lock()
+	e.spans = append(e.spans, spans...)
+	e.mu.Unlock()
+	return nil
+}
+
+// Shutdown 无操作
+func (e *MemoryExporter) Shutdown(_ context.Context) error {
+	return nil
+}
+
+// Spans 返回已导出 Span 的副本
+func (e *MemoryExporter) Spans() []SpanData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]SpanData(nil), e.spans...)
+}
+
+// Reset 清空已保存的 Span
+func (e *MemoryExporter) Reset() {
+	e.mu.Lock()
+	e.spans = nil
+	e.mu.Unlock()
+}
+
+// ============================================================================
+//                              OTLP/JSON 编码
+// ============================================================================
+
+// instrumentationScope OTLP scope 名称
+const instrumentationScope = "github.com/dep2p/go-dep2p"
+
+// OTLP 状态码
+const (
+	otlpStatusOK    = 1
+	otlpStatusError = 2
+)
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	TraceState        string         `json:"traceState,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// toOTLP 将 Span 编码为 ExportTraceServiceRequest
+func toOTLP(resource []Attribute, spans []SpanData) *otlpRequest {
+	out := make([]otlpSpan, 0, len(spans))
+	for i := range spans {
+		s := &spans[i]
+		span := otlpSpan{
+			TraceID:           s.TraceID.String(),
+			SpanID:            s.SpanID.String(),
+			TraceState:        s.TraceState,
+			Name:              s.Name,
+			Kind:              int(s.Kind),
+			StartTimeUnixNano: strconv.FormatInt(s.StartTime.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.EndTime.UnixNano(), 10),
+			Attributes:        toKeyValues(s.Attributes),
+			Status:            otlpStatus{Code: otlpStatusOK},
+		}
+		if s.ParentSpanID.IsValid() {
+			span.ParentSpanID = s.ParentSpanID.String()
+		}
+		if s.Error != "" {
+			span.Status = otlpStatus{Code: otlpStatusError, Message: s.Error}
+		}
+		out = append(out, span)
+	}
+
+	return &otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource: otlpResource{Attributes: toKeyValues(resource)},
+		ScopeSpans: []otlpScopeSpans{{
+			Scope: otlpScope{Name: instrumentationScope},
+			Spans: out,
+		}},
+	}}}
+}
+
+// toKeyValues 转换属性列表
+func toKeyValues(attrs []Attribute) []otlpKeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+	kvs := make([]otlpKeyValue, len(attrs))
+	for i, attr := range attrs {
+		kvs[i] = otlpKeyValue{Key: attr.Key, Value: otlpAnyValue{StringValue: attr.Value}}
+	}
+	return kvs
+}
@@ -0,0 +1,264 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dep2p/go-dep2p/pkg/lib/log"
+)
+
+var logger = log.Logger("lib/tracing")
+
+// SpanKind Span 类型（取值与 OTLP 一致）
+type SpanKind int
+
+const (
+	// KindInternal 本地内部操作
+	KindInternal SpanKind = 1
+
+	// KindServer 处理远端请求
+	KindServer SpanKind = 2
+
+	// KindClient 向远端发起请求
+	KindClient SpanKind = 3
+
+	// KindProducer 异步发布（如 PubSub 发布）
+	KindProducer SpanKind = 4
+
+	// KindConsumer 异步接收（如 PubSub 投递）
+	KindConsumer SpanKind = 5
+)
+
+// String 返回类型名称
+func (k SpanKind) String() string {
+	switch k {
+	case KindInternal:
+		return "internal"
+	case KindServer:
+		return "server"
+	case KindClient:
+		return "client"
+	case KindProducer:
+		return "producer"
+	case KindConsumer:
+		return "consumer"
+	default:
+		return "unspecified"
+	}
+}
+
+// Attribute Span 属性
+type Attribute struct {
+	Key   string
+	Value string
+}
+
+// Attr 构造属性
+func Attr(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// SpanData 已结束 Span 的只读快照，交给 Exporter 导出
+type SpanData struct {
+	Name         string
+	Kind         SpanKind
+	TraceID      TraceID
+	SpanID       SpanID
+	ParentSpanID SpanID
+	TraceState   string
+
+	// RemoteParent 父 Span 是否来自远端节点
+	RemoteParent bool
+
+	StartTime  time.Time
+	EndTime    time.Time
+	Attributes []Attribute
+
+	// Error 失败原因（为空表示成功）
+	Error string
+}
+
+// Duration 返回 Span 耗时
+func (d *SpanData) Duration() time.Duration {
+	return d.EndTime.Sub(d.StartTime)
+}
+
+// Span 一次被追踪的操作
+//
+// nil *Span 的所有方法都是空操作：未配置 Exporter 时 Start 返回 nil，
+// 调用方无需判空。
+type Span struct {
+	processor *batchProcessor
+
+	mu    sync.Mutex
+	data  SpanData
+	ended bool
+}
+
+// SpanContext 返回 Span 的传播上下文
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return SpanContext{
+		TraceID: s.data.TraceID,
+		SpanID:  s.data.SpanID,
+		Flags:   FlagSampled,
+		State:   s.data.TraceState,
+	}
+}
+
+// SetAttributes 设置属性（同名属性覆盖）
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ended {
+		return
+	}
+	for _, attr := range attrs {
+		s.setAttribute(attr)
+	}
+}
+
+// setAttribute 设置单个属性（调用方持锁）
+func (s *Span) setAttribute(attr Attribute) {
+	for i := range s.data.Attributes {
+		if s.data.Attributes[i].Key == attr.Key {
+			s.data.Attributes[i].Value = attr.Value
+			return
+		}
+	}
+	s.data.Attributes = append(s.data.Attributes, attr)
+}
+
+// End 结束 Span 并放入后台导出队列；err 非空时记为失败
+//
+// 重复调用只有第一次生效。End 不等待导出完成，导出队列满时丢弃该 Span。
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.EndTime = time.Now()
+	if err != nil {
+		s.data.Error = err.Error()
+	}
+	data := s.data
+	s.mu.Unlock()
+
+	s.processor.enqueue(data)
+}
+
+// ============================================================================
+//                              Span 创建
+// ============================================================================
+
+// exporterHolder 包装 Exporter 及其批量导出器以便存入 atomic.Pointer
+type exporterHolder struct {
+	exporter  Exporter
+	processor *batchProcessor
+}
+
+// globalExporter 全局 Exporter（nil 表示未启用追踪）
+var globalExporter atomic.Pointer[exporterHolder]
+
+// SetExporter 设置全局 Exporter，返回之前的 Exporter
+//
+// 传入 nil 关闭追踪。关闭后 Start 不再记录 Span，但远端传来的
+// 追踪上下文仍会原样透传。
+//
+// Span 经有界队列由后台 goroutine 批量交给 Exporter。替换时先把旧队列中
+// 的 Span 导出到旧 Exporter 再返回，旧 Exporter 的 Shutdown 由调用方负责。
+func SetExporter(e Exporter) Exporter {
+	var prev *exporterHolder
+	if e == nil {
+		prev = globalExporter.Swap(nil)
+	} else {
+		prev = globalExporter.Swap(&exporterHolder{
+			exporter:  e,
+			processor: newBatchProcessor(e, defaultQueueSize, defaultBatchSize, defaultBatchTimeout),
+		})
+	}
+	if prev == nil {
+		return nil
+	}
+	prev.processor.stop()
+	return prev.exporter
+}
+
+// ForceFlush 把导出队列中已结束的 Span 立即交给当前 Exporter
+//
+// 用于关闭前和测试中等待导出完成。
+func ForceFlush(ctx context.Context) error {
+	h := globalExporter.Load()
+	if h == nil {
+		return nil
+	}
+	return h.processor.flush(ctx)
+}
+
+// CurrentExporter 返回当前全局 Exporter
+func CurrentExporter() Exporter {
+	if h := globalExporter.Load(); h != nil {
+		return h.exporter
+	}
+	return nil
+}
+
+// Enabled 是否已启用追踪
+func Enabled() bool {
+	return globalExporter.Load() != nil
+}
+
+// Start 开始一个 Span
+//
+// Context 中有追踪上下文时作为子 Span，否则开启新的追踪。
+// 未配置 Exporter 或父上下文未采样时返回原 Context 和 nil Span，
+// 此时父上下文（若有）仍可通过 Inject 透传给下一跳。
+func Start(ctx context.Context, name string, kind SpanKind, attrs ...Attribute) (context.Context, *Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	h := globalExporter.Load()
+	if h == nil {
+		return ctx, nil
+	}
+
+	parent := SpanContextFromContext(ctx)
+	if parent.IsValid() && !parent.IsSampled() {
+		return ctx, nil
+	}
+
+	span := &Span{
+		processor: h.processor,
+		data: SpanData{
+			Name:      name,
+			Kind:      kind,
+			SpanID:    newSpanID(),
+			StartTime: time.Now(),
+		},
+	}
+	if parent.IsValid() {
+		span.data.TraceID = parent.TraceID
+		span.data.ParentSpanID = parent.SpanID
+		span.data.TraceState = parent.State
+		span.data.RemoteParent = parent.Remote
+	} else {
+		span.data.TraceID = newTraceID()
+	}
+	for _, attr := range attrs {
+		span.setAttribute(attr)
+	}
+	ctx = ContextWithSpanContext(ctx, span.SpanContext())
+	return context.WithValue(ctx, spanKey{}, span), span
+}
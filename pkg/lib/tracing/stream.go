@@ -0,0 +1,83 @@
+package tracing
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"strings"
+)
+
+// variantName 追踪变体名称
+const variantName = "trace"
+
+// maxStreamHeaderSize 流头部最大字节数（traceparent + 分隔符 + tracestate）
+const maxStreamHeaderSize = 55 + 1 + maxTracestateLen
+
+// Variant 返回协议的追踪变体
+//
+// 与压缩变体一样通过 multistream-select 协商：
+//
+//	/dep2p/app/<realmID>/file/1.0.0             无追踪头部
+//	/dep2p/app/<realmID>/file/1.0.0+trace       流首部携带追踪头部
+//	/dep2p/app/<realmID>/file/1.0.0+trace+zstd  追踪头部 + zstd 压缩
+//
+// 追踪变体在压缩变体之前追加，压缩算法仍由最后一个后缀决定。
+func Variant(protocolID string) string {
+	return protocolID + "+" + variantName
+}
+
+// IsVariant 协商结果是否为追踪变体
+func IsVariant(protocolID string) bool {
+	name := protocolID[strings.LastIndex(protocolID, "/")+1:]
+	parts := strings.Split(name, "+")
+	for _, p := range parts[1:] {
+		if p == variantName {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteStreamHeader 写入流追踪头部
+//
+// 格式：2 字节大端长度 + "traceparent\ntracestate"。
+// sc 无效时写入空头部，接收方按无追踪处理。
+func WriteStreamHeader(w io.Writer, sc SpanContext) error {
+	var payload string
+	if sc.IsValid() {
+		payload = sc.Traceparent()
+		if sc.State != "" && len(sc.State) <= maxTracestateLen {
+			payload += "\n" + sc.State
+		}
+	}
+	buf := make([]byte, 2+len(payload))
+	binary.BigEndian.PutUint16(buf, uint16(len(payload)))
+	copy(buf[2:], payload)
+	_, err := w.Write(buf)
+	return err
+}
+
+// ReadStreamHeader 读取流追踪头部
+//
+// 头部为空或 traceparent 无效时返回零值 SpanContext（不视为错误）。
+func ReadStreamHeader(r io.Reader) (SpanContext, error) {
+	var lenBuf [2]byte
+	if _, err := io.ReadFull(r, lenBuf[:]); err != nil {
+		return SpanContext{}, err
+	}
+	n := int(binary.BigEndian.Uint16(lenBuf[:]))
+	if n == 0 {
+		return SpanContext{}, nil
+	}
+	if n > maxStreamHeaderSize {
+		return SpanContext{}, ErrHeaderTooLarge
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return SpanContext{}, err
+	}
+
+	traceparent, tracestate, _ := strings.Cut(string(payload), "\n")
+	sc := SpanContextFromContext(ContextWithTraceparent(context.Background(), traceparent, tracestate))
+	return sc, nil
+}
@@ -0,0 +1,278 @@
+package tracing
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withExporter 在测试期间安装全局 Exporter
+func withExporter(t *testing.T, e Exporter) {
+	t.Helper()
+	prev := SetExporter(e)
+	t.Cleanup(func() { SetExporter(prev) })
+}
+
+func TestParseTraceparent(t *testing.T) {
+	const tp = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	sc, err := ParseTraceparent(tp)
+	require.NoError(t, err)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID.String())
+	assert.Equal(t, "00f067aa0ba902b7", sc.SpanID.String())
+	assert.True(t, sc.IsSampled())
+	assert.Equal(t, tp, sc.Traceparent())
+
+	// 更高版本允许追加字段
+	_, err = ParseTraceparent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-extra")
+	assert.NoError(t, err)
+
+	for _, bad := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00_4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	} {
+		_, err := ParseTraceparent(bad)
+		assert.ErrorIs(t, err, ErrInvalidTraceparent, bad)
+	}
+}
+
+func TestInjectExtract(t *testing.T) {
+	ctx := ContextWithTraceparent(context.Background(),
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "vendor=abc")
+
+	carrier := map[string]string{}
+	Inject(ctx, carrier)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", carrier[HeaderTraceparent])
+	assert.Equal(t, "vendor=abc", carrier[HeaderTracestate])
+
+	sc := SpanContextFromContext(Extract(context.Background(), carrier))
+	assert.True(t, sc.Remote)
+	assert.Equal(t, "vendor=abc", sc.State)
+
+	// 无追踪上下文时不修改载体
+	empty := map[string]string{"k": "v"}
+	Inject(context.Background(), empty)
+	assert.Equal(t, map[string]string{"k": "v"}, empty)
+}
+
+func TestStart_Disabled(t *testing.T) {
+	withExporter(t, nil)
+
+	parent := ContextWithTraceparent(context.Background(),
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "")
+	ctx, span := Start(parent, "op", KindClient)
+	assert.Nil(t, span)
+	span.SetAttributes(Attr("k", "v"))
+	span.End(nil)
+
+	// 未启用时透传上游上下文
+	assert.Equal(t, SpanContextFromContext(parent), SpanContextFromContext(ctx))
+}
+
+func TestStart_ParentChild(t *testing.T) {
+	exp := NewMemoryExporter()
+	withExporter(t, exp)
+
+	ctx, root := Start(context.Background(), "root", KindInternal, Attr("a", "1"))
+	require.NotNil(t, root)
+	_, child := Start(ctx, "child", KindClient)
+	child.SetAttributes(Attr("b", "2"), Attr("b", "3"))
+	child.End(errors.New("boom"))
+	child.End(nil)
+	root.End(nil)
+	require.NoError(t, ForceFlush(context.Background()))
+
+	spans := exp.Spans()
+	require.Len(t, spans, 2)
+	c, r := spans[0], spans[1]
+	assert.Equal(t, "child", c.Name)
+	assert.Equal(t, r.TraceID, c.TraceID)
+	assert.Equal(t, r.SpanID, c.ParentSpanID)
+	assert.False(t, r.ParentSpanID.IsValid())
+	assert.Equal(t, []Attribute{{Key: "b", Value: "3"}}, c.Attributes)
+	assert.Equal(t, "boom", c.Error)
+	assert.Equal(t, []Attribute{{Key: "a", Value: "1"}}, r.Attributes)
+
+	// 未采样的远端上下文不记录
+	unsampled := ContextWithTraceparent(context.Background(),
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", "")
+	_, span := Start(unsampled, "skip", KindServer)
+	assert.Nil(t, span)
+}
+
+func TestStreamHeader(t *testing.T) {
+	sc, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	require.NoError(t, err)
+	sc.State = "vendor=abc"
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteStreamHeader(&buf, sc))
+	buf.WriteString("payload")
+
+	got, err := ReadStreamHeader(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, sc.TraceID, got.TraceID)
+	assert.Equal(t, sc.SpanID, got.SpanID)
+	assert.Equal(t, "vendor=abc", got.State)
+	assert.Equal(t, "payload", buf.String())
+
+	// 空头部
+	buf.Reset()
+	require.NoError(t, WriteStreamHeader(&buf, SpanContext{}))
+	got, err = ReadStreamHeader(&buf)
+	require.NoError(t, err)
+	assert.False(t, got.IsValid())
+
+	// 超长头部
+	_, err = ReadStreamHeader(bytes.NewReader([]byte{0xff, 0xff}))
+	assert.ErrorIs(t, err, ErrHeaderTooLarge)
+}
+
+func TestVariant(t *testing.T) {
+	p := "/dep2p/app/realm/file/1.0.0"
+	assert.Equal(t, p+"+trace", Variant(p))
+	assert.True(t, IsVariant(Variant(p)))
+	assert.True(t, IsVariant(Variant(p)+"+zstd"))
+	assert.False(t, IsVariant(p))
+	assert.False(t, IsVariant(p+"+zstd"))
+	assert.False(t, IsVariant("/dep2p/app/realm+trace/file/1.0.0"))
+}
+
+func TestFileExporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spans.jsonl")
+	exp, err := NewFileExporter(path, Attr("service.name", "dep2p"))
+	require.NoError(t, err)
+	withExporter(t, exp)
+
+	ctx, parent := Start(context.Background(), "dep2p.messaging.send", KindClient, Attr("peer.id", "QmPeer"))
+	_, child := Start(ctx, "dep2p.swarm.dial", KindClient)
+	child.End(errors.New("unreachable"))
+	parent.End(nil)
+	require.NoError(t, ForceFlush(context.Background()))
+	require.NoError(t, exp.Shutdown(context.Background()))
+	assert.ErrorIs(t, exp.ExportSpans(context.Background(), []SpanData{{}}), ErrExporterClosed)
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var lines []otlpRequest
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var req otlpRequest
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &req))
+		lines = append(lines, req)
+	}
+	require.Len(t, lines, 1, "同一批 Span 写为一行")
+
+	rs := lines[0].ResourceSpans[0]
+	assert.Equal(t, "service.name", rs.Resource.Attributes[0].Key)
+	assert.Equal(t, instrumentationScope, rs.ScopeSpans[0].Scope.Name)
+
+	require.Len(t, rs.ScopeSpans[0].Spans, 2)
+	dial := rs.ScopeSpans[0].Spans[0]
+	send := rs.ScopeSpans[0].Spans[1]
+	assert.Equal(t, "dep2p.swarm.dial", dial.Name)
+	assert.Equal(t, int(KindClient), dial.Kind)
+	assert.Equal(t, send.SpanID, dial.ParentSpanID)
+	assert.Equal(t, send.TraceID, dial.TraceID)
+	assert.Len(t, dial.TraceID, 32)
+	assert.Equal(t, otlpStatus{Code: otlpStatusError, Message: "unreachable"}, dial.Status)
+	assert.Equal(t, otlpStatusOK, send.Status.Code)
+	assert.Empty(t, send.ParentSpanID)
+	assert.NotEmpty(t, send.StartTimeUnixNano)
+}
+
+// blockingExporter 在 release 关闭前阻塞导出
+type blockingExporter struct {
+	*MemoryExporter
+	release chan struct{}
+}
+
+func (e *blockingExporter) ExportSpans(ctx context.Context, spans []SpanData) error {
+	<-e.release
+	return e.MemoryExporter.ExportSpans(ctx, spans)
+}
+
+func TestBatchProcessor_Batches(t *testing.T) {
+	exp := NewMemoryExporter()
+	p := newBatchProcessor(exp, 16, 4, time.Hour)
+	defer p.stop()
+
+	for i := 0; i < 10; i++ {
+		p.enqueue(SpanData{Name: "op"})
+	}
+	// 攒满的两批已导出，剩余两个等待超时或 flush
+	assert.Eventually(t, func() bool { return len(exp.Spans()) == 8 }, time.Second, time.Millisecond)
+
+	require.NoError(t, p.flush(context.Background()))
+	assert.Len(t, exp.Spans(), 10)
+}
+
+func TestBatchProcessor_DropsOnOverflow(t *testing.T) {
+	exp := &blockingExporter{MemoryExporter: NewMemoryExporter(), release: make(chan struct{})}
+	p := newBatchProcessor(exp, 4, 1, time.Hour)
+
+	before := DroppedSpans()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		// 导出被阻塞时 enqueue 仍立即返回
+		for i := 0; i < 20; i++ {
+			p.enqueue(SpanData{Name: "op"})
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("enqueue blocked on slow exporter")
+	}
+	assert.GreaterOrEqual(t, DroppedSpans()-before, uint64(20-4-1))
+
+	close(exp.release)
+	p.stop()
+	assert.LessOrEqual(t, len(exp.Spans()), 5)
+	assert.Equal(t, uint64(20), uint64(len(exp.Spans()))+DroppedSpans()-before)
+
+	// 停止后的 Span 直接丢弃
+	p.enqueue(SpanData{Name: "late"})
+	assert.Equal(t, uint64(20-len(exp.Spans())+1), DroppedSpans()-before)
+}
+
+func TestTraceContextPayload(t *testing.T) {
+	sc, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	require.NoError(t, err)
+	sc.State = "vendor=abc"
+
+	payload, got := SplitTraceContext(AppendTraceContext([]byte("QmTarget"), sc))
+	assert.Equal(t, "QmTarget", string(payload))
+	assert.Equal(t, sc.TraceID, got.TraceID)
+	assert.Equal(t, sc.SpanID, got.SpanID)
+	assert.Equal(t, "vendor=abc", got.State)
+	assert.True(t, got.Remote)
+
+	// 无追踪上下文时与旧格式一致
+	assert.Equal(t, []byte("QmTarget"), AppendTraceContext([]byte("QmTarget"), SpanContext{}))
+	payload, got = SplitTraceContext([]byte("QmTarget"))
+	assert.Equal(t, "QmTarget", string(payload))
+	assert.False(t, got.IsValid())
+
+	// traceparent 无效时只取载荷
+	payload, got = SplitTraceContext([]byte("QmTarget\nbogus"))
+	assert.Equal(t, "QmTarget", string(payload))
+	assert.False(t, got.IsValid())
+}
@@ -415,8 +415,8 @@ func (c *client) query(params *LookupParams) error {
 	m := new(dns.Msg)
 	if serviceInstanceName != "" {
 		m.Question = []dns.Question{
-			{serviceInstanceName, dns.TypeSRV, dns.ClassINET},
-			{serviceInstanceName, dns.TypeTXT, dns.ClassINET},
+			{Name: serviceInstanceName, Qtype: dns.TypeSRV, Qclass: dns.ClassINET},
+			{Name: serviceInstanceName, Qtype: dns.TypeTXT, Qclass: dns.ClassINET},
 		}
 		m.RecursionDesired = false
 	} else {
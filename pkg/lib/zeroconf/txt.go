@@ -0,0 +1,102 @@
+package zeroconf
+
+import (
+	"errors"
+	"strings"
+)
+
+// Limits of a DNS-SD TXT record string (RFC 6763 section 6).
+const (
+	// MaxTXTStringLen is the maximum length of a single TXT string
+	MaxTXTStringLen = 255
+
+	// MaxTXTKeyLen is the recommended maximum length of a key
+	MaxTXTKeyLen = 9
+)
+
+var (
+	// ErrInvalidTXTKey is returned when a key violates RFC 6763 section 6.4
+	ErrInvalidTXTKey = errors.New("zeroconf: invalid txt key")
+
+	// ErrTXTTooLong is returned when a key/value pair exceeds MaxTXTStringLen
+	ErrTXTTooLong = errors.New("zeroconf: txt string too long")
+)
+
+// TXTAttr is a single DNS-SD key/value attribute carried in a TXT record.
+//
+// An attribute without '=' is a boolean attribute (HasValue is false); an
+// attribute with '=' but no bytes after it has an empty value.
+type TXTAttr struct {
+	Key      string
+	Value    string
+	HasValue bool
+}
+
+// NewTXTAttr builds a key/value attribute and validates it.
+func NewTXTAttr(key, value string) (TXTAttr, error) {
+	attr := TXTAttr{Key: key, Value: value, HasValue: true}
+	if err := attr.validate(); err != nil {
+		return TXTAttr{}, err
+	}
+	return attr, nil
+}
+
+// String encodes the attribute as a TXT string.
+func (a TXTAttr) String() string {
+	if !a.HasValue {
+		return a.Key
+	}
+	return a.Key + "=" + a.Value
+}
+
+// validate checks the key characters and the encoded length.
+//
+// Keys must be at least one printable US-ASCII character, excluding '='.
+func (a TXTAttr) validate() error {
+	if a.Key == "" {
+		return ErrInvalidTXTKey
+	}
+	for i := 0; i < len(a.Key); i++ {
+		c := a.Key[i]
+		if c < 0x20 || c > 0x7E || c == '=' {
+			return ErrInvalidTXTKey
+		}
+	}
+	if len(a.String()) > MaxTXTStringLen {
+		return ErrTXTTooLong
+	}
+	return nil
+}
+
+// ParseTXT decodes TXT strings into attributes, preserving their order.
+//
+// Keys are lower-cased since they are case insensitive. Empty strings and
+// strings starting with '=' are skipped. Unlike a strict RFC 6763 reader,
+// repeated keys are all returned so callers can use multi-valued keys such as
+// "dnsaddr"; use Lookup for first-occurrence semantics.
+func ParseTXT(text []string) []TXTAttr {
+	attrs := make([]TXTAttr, 0, len(text))
+	for _, s := range text {
+		if s == "" || s[0] == '=' {
+			continue
+		}
+		key, value, hasValue := strings.Cut(s, "=")
+		attrs = append(attrs, TXTAttr{
+			Key:      strings.ToLower(key),
+			Value:    value,
+			HasValue: hasValue,
+		})
+	}
+	return attrs
+}
+
+// Lookup returns the first attribute with the given key.
+func Lookup(attrs []TXTAttr, key string) (TXTAttr, bool) {
+	key = strings.ToLower(key)
+	for _, a := range attrs {
+		if a.Key == key {
+			return a, true
+		}
+	}
+	return TXTAttr{}, false
+}
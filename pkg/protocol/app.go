@@ -15,6 +15,7 @@ const (
 	AppProtocolPubSub    = "pubsub"
 	AppProtocolStreams   = "streams"
 	AppProtocolLiveness  = "liveness"
+	AppProtocolMailbox   = "mailbox"
 )
 
 // AppBuilder App 协议构建器
@@ -56,6 +57,12 @@ func (b *AppBuilder) Liveness() ID {
 	return ID(fmt.Sprintf("/dep2p/app/%s/liveness/1.0.0", b.realmID))
 }
 
+// Mailbox 返回离线信箱协议 ID
+// 用于向离线节点存储转发消息
+func (b *AppBuilder) Mailbox() ID {
+	return ID(fmt.Sprintf("/dep2p/app/%s/mailbox/1.0.0", b.realmID))
+}
+
 // Custom 返回自定义协议 ID
 func (b *AppBuilder) Custom(name, version string) ID {
 	return ID(fmt.Sprintf("/dep2p/app/%s/%s/%s", b.realmID, name, version))
@@ -68,6 +68,7 @@ func TestRealmBuilder(t *testing.T) {
 		{"Addressbook", builder.Addressbook(), "/dep2p/realm/my-realm/addressbook/1.0.0"},
 		{"Join", builder.Join(), "/dep2p/realm/my-realm/join/1.0.0"},
 		{"Route", builder.Route(), "/dep2p/realm/my-realm/route/1.0.0"},
+		{"Topology", builder.Topology(), "/dep2p/realm/my-realm/topology/1.0.0"},
 		{"Custom", builder.Custom("test", "2.0.0"), "/dep2p/realm/my-realm/test/2.0.0"},
 	}
 
@@ -98,6 +99,7 @@ func TestAppBuilder(t *testing.T) {
 		{"PubSub", builder.PubSub(), "/dep2p/app/test-realm/pubsub/1.0.0"},
 		{"Streams", builder.Streams(), "/dep2p/app/test-realm/streams/1.0.0"},
 		{"Liveness", builder.Liveness(), "/dep2p/app/test-realm/liveness/1.0.0"},
+		{"Mailbox", builder.Mailbox(), "/dep2p/app/test-realm/mailbox/1.0.0"},
 		{"Custom", builder.Custom("rpc", "1.5.0"), "/dep2p/app/test-realm/rpc/1.5.0"},
 	}
 
@@ -70,6 +70,12 @@ func (b *RealmBuilder) Route() ID {
 	return ID(fmt.Sprintf("/dep2p/realm/%s/route/1.0.0", b.realmID))
 }
 
+// Topology 返回拓扑协议 ID
+// 用于 Realm 成员交换邻居摘要
+func (b *RealmBuilder) Topology() ID {
+	return ID(fmt.Sprintf("/dep2p/realm/%s/topology/1.0.0", b.realmID))
+}
+
 // Custom 返回自定义协议 ID
 func (b *RealmBuilder) Custom(name, version string) ID {
 	return ID(fmt.Sprintf("/dep2p/realm/%s/%s/%s", b.realmID, name, version))
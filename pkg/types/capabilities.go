@@ -0,0 +1,258 @@
+// Package types 定义 DeP2P 公共类型
+//
+// 本文件定义节点能力类型，通过 Identify 协议交换。
+package types
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"strings"
+)
+
+// ============================================================================
+//                              PeerCapabilities - 节点能力
+// ============================================================================
+
+// CapabilitiesVersion 当前能力块版本
+//
+// 新增字段时保持向后兼容，只有语义变化时才递增版本。
+const CapabilitiesVersion = 1
+
+// PeerCapabilitiesKey Peerstore 中保存节点能力的元数据键
+const PeerCapabilitiesKey = "identify/capabilities"
+
+// realmHashSize Realm 哈希长度（字节）
+const realmHashSize = 8
+
+// 节点角色
+const (
+	// PeerRoleRelay 中继服务器
+	PeerRoleRelay = "relay"
+	// PeerRoleBootstrap 引导节点
+	PeerRoleBootstrap = "bootstrap"
+	// PeerRoleGateway Realm 网关
+	PeerRoleGateway = "gateway"
+	// PeerRoleWitness 见证节点
+	PeerRoleWitness = "witness"
+)
+
+// LoadHint 节点负载提示
+//
+// 只是提示，接收方用于排序候选，不应作为准入依据。
+type LoadHint struct {
+	// Conns 当前连接数
+	Conns int `json:"conns,omitempty"`
+
+	// MaxConns 连接数上限（0 表示未知）
+	MaxConns int `json:"max_conns,omitempty"`
+
+	// RelayCircuits 当前中继电路数
+	RelayCircuits int `json:"relay_circuits,omitempty"`
+
+	// MaxRelayCircuits 中继电路上限（0 表示未知）
+	MaxRelayCircuits int `json:"max_relay_circuits,omitempty"`
+}
+
+// Saturated 是否已满负载
+func (l *LoadHint) Saturated() bool {
+	if l == nil {
+		return false
+	}
+	if l.MaxConns > 0 && l.Conns >= l.MaxConns {
+		return true
+	}
+	return l.MaxRelayCircuits > 0 && l.RelayCircuits >= l.MaxRelayCircuits
+}
+
+// PeerCapabilities 节点能力块
+//
+// 由 Identify 和 Identify Push 携带，各子系统据此判断对端能力，
+// 无需各自探测。未知字段应被忽略以便扩展。
+type PeerCapabilities struct {
+	// Version 能力块版本
+	Version int `json:"version"`
+
+	// Roles 节点角色（relay、bootstrap、gateway、witness）
+	Roles []string `json:"roles,omitempty"`
+
+	// NATType NAT 类型
+	NATType NATType `json:"nat_type,omitempty"`
+
+	// Reachability 可达性
+	Reachability Reachability `json:"reachability,omitempty"`
+
+	// HolePunching 是否支持打洞
+	HolePunching bool `json:"hole_punching,omitempty"`
+
+	// Transports 支持的传输协议（如 tcp、quic-v1、ws）
+	Transports []string `json:"transports,omitempty"`
+
+	// Load 负载提示
+	Load *LoadHint `json:"load,omitempty"`
+
+	// RealmHashes 已加入 Realm 的哈希列表
+	//
+	// 使用 HashRealm 计算，以通告方 PeerID 加盐，不泄露 RealmID。
+	RealmHashes [][]byte `json:"realm_hashes,omitempty"`
+}
+
+// NewPeerCapabilities 创建当前版本的空能力块
+func NewPeerCapabilities() *PeerCapabilities {
+	return &PeerCapabilities{Version: CapabilitiesVersion}
+}
+
+// HasRole 是否具有指定角色
+func (c *PeerCapabilities) HasRole(role string) bool {
+	if c == nil {
+		return false
+	}
+	for _, r := range c.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// AddRole 添加角色（重复添加会被忽略）
+func (c *PeerCapabilities) AddRole(role string) {
+	if !c.HasRole(role) {
+		c.Roles = append(c.Roles, role)
+	}
+}
+
+// SupportsTransport 是否支持指定传输
+//
+// 未通告传输列表时视为支持，避免旧版本节点被误过滤。
+func (c *PeerCapabilities) SupportsTransport(transport string) bool {
+	if c == nil || len(c.Transports) == 0 {
+		return true
+	}
+	for _, t := range c.Transports {
+		if t == transport {
+			return true
+		}
+	}
+	return false
+}
+
+// AddRealm 添加已加入的 Realm
+//
+// owner 为通告方（本节点）的 PeerID。
+func (c *PeerCapabilities) AddRealm(owner PeerID, realmID RealmID) {
+	if c.InRealm(owner, realmID) {
+		return
+	}
+	c.RealmHashes = append(c.RealmHashes, HashRealm(owner, realmID))
+}
+
+// InRealm 通告方是否声明加入了指定 Realm
+//
+// owner 为通告方的 PeerID。结果只是提示，成员资格以 Realm 认证为准。
+func (c *PeerCapabilities) InRealm(owner PeerID, realmID RealmID) bool {
+	if c == nil || len(c.RealmHashes) == 0 {
+		return false
+	}
+	h := HashRealm(owner, realmID)
+	for _, rh := range c.RealmHashes {
+		if bytes.Equal(rh, h) {
+			return true
+		}
+	}
+	return false
+}
+
+// Equal 比较能力是否相同（忽略负载提示）
+//
+// 负载随时变化，不应触发 Identify Push。
+func (c *PeerCapabilities) Equal(other *PeerCapabilities) bool {
+	if c == nil || other == nil {
+		return c == other
+	}
+	if c.Version != other.Version || c.NATType != other.NATType ||
+		c.Reachability != other.Reachability || c.HolePunching != other.HolePunching {
+		return false
+	}
+	if !equalStrings(c.Roles, other.Roles) || !equalStrings(c.Transports, other.Transports) {
+		return false
+	}
+	if len(c.RealmHashes) != len(other.RealmHashes) {
+		return false
+	}
+	for i := range c.RealmHashes {
+		if !bytes.Equal(c.RealmHashes[i], other.RealmHashes[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// HashRealm 计算 Realm 哈希
+//
+// 以通告方 PeerID 加盐，不同节点对同一 Realm 的哈希不同，
+// 观察者无法跨节点关联 Realm 成员，只有已知 RealmID 的节点能够验证。
+func HashRealm(owner PeerID, realmID RealmID) []byte {
+	h := sha256.New()
+	h.Write([]byte("dep2p/realm-hint/v1\x00"))
+	h.Write([]byte(owner))
+	h.Write([]byte{0})
+	h.Write([]byte(realmID))
+	return h.Sum(nil)[:realmHashSize]
+}
+
+// AddrTransport 返回 multiaddr 字符串使用的传输协议
+//
+// 取地址中最后出现的传输协议（如 /tcp/4001/ws 为 ws），
+// 中继地址和无法识别的地址返回空字符串。
+func AddrTransport(addr string) string {
+	if strings.Contains(addr, "/p2p-circuit") {
+		return ""
+	}
+	transport := ""
+	for _, part := range strings.Split(addr, "/") {
+		switch part {
+		case "tcp", "quic", "quic-v1", "ws", "wss", "webtransport", "webrtc-direct":
+			transport = part
+		}
+	}
+	return transport
+}
+
+// ParsePeerCapabilities 解析 Peerstore 元数据中的节点能力
+//
+// 内存中为 *PeerCapabilities；持久化 Peerstore 重启后加载的是 JSON 解码的 map，
+// 此时重新编码转换。无法解析时返回 false。
+func ParsePeerCapabilities(v interface{}) (*PeerCapabilities, bool) {
+	switch caps := v.(type) {
+	case nil:
+		return nil, false
+	case *PeerCapabilities:
+		return caps, caps != nil
+	case PeerCapabilities:
+		return &caps, true
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, false
+	}
+	caps := &PeerCapabilities{}
+	if err := json.Unmarshal(data, caps); err != nil || caps.Version == 0 {
+		return nil, false
+	}
+	return caps, true
+}
+
+// equalStrings 比较字符串切片
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,147 @@
+package types
+
+import "testing"
+
+func TestPeerCapabilities_Roles(t *testing.T) {
+	caps := NewPeerCapabilities()
+	if caps.Version != CapabilitiesVersion {
+		t.Errorf("Version = %d, want %d", caps.Version, CapabilitiesVersion)
+	}
+
+	caps.AddRole(PeerRoleRelay)
+	caps.AddRole(PeerRoleRelay)
+	if len(caps.Roles) != 1 {
+		t.Errorf("len(Roles) = %d, want 1", len(caps.Roles))
+	}
+	if !caps.HasRole(PeerRoleRelay) {
+		t.Error("HasRole(relay) = false")
+	}
+	if caps.HasRole(PeerRoleBootstrap) {
+		t.Error("HasRole(bootstrap) = true")
+	}
+
+	var nilCaps *PeerCapabilities
+	if nilCaps.HasRole(PeerRoleRelay) {
+		t.Error("nil HasRole() = true")
+	}
+}
+
+func TestPeerCapabilities_SupportsTransport(t *testing.T) {
+	caps := NewPeerCapabilities()
+	if !caps.SupportsTransport("quic-v1") {
+		t.Error("empty Transports should support all")
+	}
+
+	caps.Transports = []string{"tcp"}
+	if !caps.SupportsTransport("tcp") {
+		t.Error("SupportsTransport(tcp) = false")
+	}
+	if caps.SupportsTransport("quic-v1") {
+		t.Error("SupportsTransport(quic-v1) = true")
+	}
+}
+
+func TestPeerCapabilities_Realm(t *testing.T) {
+	caps := NewPeerCapabilities()
+	caps.AddRealm("peer1", "realm-1")
+	caps.AddRealm("peer1", "realm-1")
+
+	if len(caps.RealmHashes) != 1 {
+		t.Errorf("len(RealmHashes) = %d, want 1", len(caps.RealmHashes))
+	}
+	if !caps.InRealm("peer1", "realm-1") {
+		t.Error("InRealm(realm-1) = false")
+	}
+	if caps.InRealm("peer1", "realm-2") {
+		t.Error("InRealm(realm-2) = true")
+	}
+	// 哈希以通告方加盐，换个 owner 不匹配
+	if caps.InRealm("peer2", "realm-1") {
+		t.Error("InRealm with other owner = true")
+	}
+}
+
+func TestPeerCapabilities_Equal(t *testing.T) {
+	a := NewPeerCapabilities()
+	a.AddRole(PeerRoleRelay)
+	a.Load = &LoadHint{Conns: 1}
+
+	b := NewPeerCapabilities()
+	b.AddRole(PeerRoleRelay)
+	b.Load = &LoadHint{Conns: 5}
+
+	if !a.Equal(b) {
+		t.Error("Equal() should ignore Load")
+	}
+
+	b.HolePunching = true
+	if a.Equal(b) {
+		t.Error("Equal() = true for different HolePunching")
+	}
+}
+
+func TestLoadHint_Saturated(t *testing.T) {
+	tests := []struct {
+		name string
+		load *LoadHint
+		want bool
+	}{
+		{"nil", nil, false},
+		{"unknown", &LoadHint{Conns: 10}, false},
+		{"conns", &LoadHint{Conns: 10, MaxConns: 10}, true},
+		{"circuits", &LoadHint{RelayCircuits: 2, MaxRelayCircuits: 4}, false},
+		{"circuits full", &LoadHint{RelayCircuits: 4, MaxRelayCircuits: 4}, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.load.Saturated(); got != tt.want {
+			t.Errorf("%s: Saturated() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAddrTransport(t *testing.T) {
+	tests := []struct {
+		addr string
+		want string
+	}{
+		{"/ip4/1.2.3.4/tcp/4001", "tcp"},
+		{"/ip4/1.2.3.4/udp/4001/quic-v1", "quic-v1"},
+		{"/ip4/1.2.3.4/tcp/443/wss", "wss"},
+		{"/ip4/1.2.3.4/tcp/4001/p2p/relay/p2p-circuit", ""},
+		{"/ip4/1.2.3.4", ""},
+	}
+
+	for _, tt := range tests {
+		if got := AddrTransport(tt.addr); got != tt.want {
+			t.Errorf("AddrTransport(%q) = %q, want %q", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestParsePeerCapabilities(t *testing.T) {
+	caps := NewPeerCapabilities()
+	caps.AddRole(PeerRoleBootstrap)
+
+	got, ok := ParsePeerCapabilities(caps)
+	if !ok || got != caps {
+		t.Error("ParsePeerCapabilities(*PeerCapabilities) failed")
+	}
+
+	// 持久化 Peerstore 重启后加载的是 map
+	m := map[string]interface{}{
+		"version": float64(1),
+		"roles":   []interface{}{"bootstrap"},
+	}
+	got, ok = ParsePeerCapabilities(m)
+	if !ok || !got.HasRole(PeerRoleBootstrap) {
+		t.Error("ParsePeerCapabilities(map) failed")
+	}
+
+	if _, ok := ParsePeerCapabilities(nil); ok {
+		t.Error("ParsePeerCapabilities(nil) ok = true")
+	}
+	if _, ok := ParsePeerCapabilities("invalid"); ok {
+		t.Error("ParsePeerCapabilities(string) ok = true")
+	}
+}
@@ -27,6 +27,14 @@ type PeerInfo struct {
 
 	// DiscoveredAt 发现时间
 	DiscoveredAt time.Time
+
+	// Seq 节点记录版本（来自签名节点记录，0 表示未知）
+	//
+	// 持续订阅时同一节点重新注册会携带更大的 Seq，用于区分续约与变更。
+	Seq uint64
+
+	// Expired 持续订阅时表示该节点的注册已过期或被取消
+	Expired bool
 }
 
 // String 返回 PeerInfo 的字符串表示
@@ -174,6 +174,9 @@ type EvtRealmJoined struct {
 	BaseEvent
 	RealmID RealmID
 	PeerID  PeerID
+
+	// DiscoveryKey 由 PSK 派生的局域网发现密钥（mDNS Realm 标签），仅在进程内传递
+	DiscoveryKey []byte
 }
 
 // EvtRealmLeft 离开 Realm 事件
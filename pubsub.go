@@ -60,8 +60,12 @@ type PubSub struct {
 //	    log.Fatal(err)
 //	}
 //	defer chatTopic.Close()
-func (p *PubSub) Join(topic string) (*Topic, error) {
-	internal, err := p.internal.Join(topic)
+//
+// 可选地为主题启用压缩：
+//
+//	topic, err := pubsub.Join("state/sync", interfaces.WithTopicCompression("zstd"))
+func (p *PubSub) Join(topic string, opts ...interfaces.TopicOption) (*Topic, error) {
+	internal, err := p.internal.Join(topic, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/dep2p/go-dep2p/internal/realm/topology"
 	"github.com/dep2p/go-dep2p/pkg/interfaces"
 	"github.com/dep2p/go-dep2p/pkg/types"
 )
@@ -259,6 +260,70 @@ func (r *Realm) Liveness() *Liveness {
 	return &Liveness{internal: r.internal.Liveness()}
 }
 
+// Mailbox 返回离线信箱服务
+//
+// 用于向离线成员投递消息（存储转发，端到端加密）。
+//
+// 示例：
+//
+//	mailbox := realm.Mailbox()
+//	id, _ := mailbox.Deposit(ctx, mailboxPeer, recipient, []byte("hi"), 0)
+//	msgs, _ := mailbox.Fetch(ctx, mailboxPeer)
+func (r *Realm) Mailbox() *Mailbox {
+	mb := &Mailbox{}
+	if provider, ok := r.internal.(interface{ Mailbox() interfaces.Mailbox }); ok {
+		mb.internal = provider.Mailbox()
+	}
+	return mb
+}
+
+// Transfer 返回文件传输服务
+//
+// 用于在成员之间传输大文件（分块校验、多源并行、断点续传）。
+//
+// 示例：
+//
+//	cid, _ := realm.Transfer().Share(ctx, "/data/video.mp4")
+//	err := realm.Transfer().Download(ctx, cid, "/tmp/video.mp4", dep2p.DownloadOptions{})
+func (r *Realm) Transfer() *Transfer {
+	tr := &Transfer{}
+	if provider, ok := r.internal.(interface{ Transfer() interfaces.Transfer }); ok {
+		tr.internal = provider.Transfer()
+	}
+	return tr
+}
+
+// RealmTopology Realm 拓扑图
+//
+// 包含成员、推断出的中继节点、成员间连接（类型与 RTT），以及分区、
+// 单点故障和热点中继分析结果。可通过 JSON、WriteDOT 导出。
+type RealmTopology = topology.Graph
+
+// Topology 组装当前 Realm 的连接拓扑
+//
+// 需要启用拓扑协议（WithRealmTopology），否则返回 ErrTopologyUnavailable。
+// 拓扑由成员交换的邻居摘要组装，未启用拓扑协议的成员只会出现在
+// 其他成员的邻居中。
+//
+// 示例：
+//
+//	graph, err := realm.Topology()
+//	if err == nil && len(graph.Partitions) > 1 {
+//	    log.Printf("realm partitioned: %v", graph.Partitions)
+//	}
+//	_ = graph.WriteDOT(os.Stdout)
+func (r *Realm) Topology() (*RealmTopology, error) {
+	provider, ok := r.internal.(interface{ Topology() *topology.Service })
+	if !ok {
+		return nil, ErrTopologyUnavailable
+	}
+	svc := provider.Topology()
+	if svc == nil {
+		return nil, ErrTopologyUnavailable
+	}
+	return svc.Graph(), nil
+}
+
 // ════════════════════════════════════════════════════════════════════════════
 //                              生命周期
 // ════════════════════════════════════════════════════════════════════════════
@@ -73,6 +73,26 @@ func (s *Streams) Open(ctx context.Context, peerID string, protocol string) (*Bi
 	return &BiStream{internal: internal}, nil
 }
 
+// StreamOptions 打开流的选项
+type StreamOptions = interfaces.StreamOptions
+
+// OpenWithOptions 使用选项打开流
+//
+// 可通过 Compression 按偏好顺序请求压缩（"zstd"、"snappy"），
+// 对端不支持时自动回退到未压缩流。
+//
+// 示例：
+//
+//	stream, err := streams.OpenWithOptions(ctx, peerID, "file-transfer",
+//	    dep2p.StreamOptions{Compression: []string{"zstd"}})
+func (s *Streams) OpenWithOptions(ctx context.Context, peerID string, protocol string, opts StreamOptions) (*BiStream, error) {
+	internal, err := s.internal.OpenWithOptions(ctx, peerID, protocol, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &BiStream{internal: internal}, nil
+}
+
 // ════════════════════════════════════════════════════════════════════════════
 //                              注册处理器
 // ════════════════════════════════════════════════════════════════════════════
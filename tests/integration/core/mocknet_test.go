@@ -0,0 +1,145 @@
+package core_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dep2p/go-dep2p"
+	"github.com/dep2p/go-dep2p/internal/core/transport/mocknet"
+	"github.com/dep2p/go-dep2p/pkg/types"
+	"github.com/dep2p/go-dep2p/tests/testutil"
+)
+
+// TestMockNet_ConnectAndUnlink 测试节点通过模拟网络连接
+//
+// 验证:
+//   - 节点经过真实 Upgrader/Swarm 在模拟网络上建立连接
+//   - 断开链路后双方连接被重置
+func TestMockNet_ConnectAndUnlink(t *testing.T) {
+	mn := testutil.NewMockNet(t)
+	hostA := testutil.NewMockHost(t, mn)
+	hostB := testutil.NewMockHost(t, mn)
+
+	nodeA := testutil.NewTestNode(t).WithMockHost(hostA).Start()
+	nodeB := testutil.NewTestNode(t).WithMockHost(hostB).Start()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	require.NoError(t, nodeB.Host().Connect(ctx, nodeA.ID(), nodeA.ListenAddrs()))
+	testutil.Eventually(t, 5*time.Second, func() bool {
+		return nodeA.ConnectionCount() == 1
+	}, "A 应该看到入站连接")
+
+	mn.Unlink(hostA, hostB)
+	testutil.Eventually(t, 10*time.Second, func() bool {
+		return nodeA.ConnectionCount() == 0 && nodeB.ConnectionCount() == 0
+	}, "断开链路后连接应被重置")
+}
+
+// relayedConns 统计 node 到 peer 的中继连接和直连连接数
+func relayedConns(node *dep2p.Node, peer string) (relayed, direct int) {
+	network := node.Host().Network()
+	if network == nil {
+		return 0, 0
+	}
+	for _, conn := range network.ConnsToPeer(peer) {
+		if conn.ConnType().IsRelay() {
+			relayed++
+		} else {
+			direct++
+		}
+	}
+	return relayed, direct
+}
+
+// startMockRelay 在公网主机上启动中继节点，返回中继的完整地址
+func startMockRelay(t *testing.T, mn *mocknet.Network) (*dep2p.Node, string) {
+	t.Helper()
+	host := testutil.NewMockHost(t, mn)
+	node := testutil.NewTestNode(t).
+		WithMockHost(host).
+		WithOptions(dep2p.EnableRelayServer(true)).
+		Start()
+	return node, host.Addr(testutil.MockNetListenPort) + "/p2p/" + node.ID()
+}
+
+// TestMockNet_RelayCircuit 测试两个对称型 NAT 后的节点经中继电路连接
+//
+// 验证:
+//   - 对称型 NAT 之间无法直连
+//   - 经 /p2p-circuit 地址拨号得到中继连接，双方都能看到
+func TestMockNet_RelayCircuit(t *testing.T) {
+	mn := testutil.NewMockNet(t)
+	_, relayAddr := startMockRelay(t, mn)
+
+	hostA := testutil.NewMockHost(t, mn, mocknet.BehindNAT(testutil.NewMockNAT(t, mn, types.NATTypeSymmetric)))
+	hostB := testutil.NewMockHost(t, mn, mocknet.BehindNAT(testutil.NewMockNAT(t, mn, types.NATTypeSymmetric)))
+
+	nodeA := testutil.NewTestNode(t).WithMockHost(hostA).WithRelay(true).
+		WithOptions(dep2p.WithRelayAddr(relayAddr), dep2p.WithHolePunch(false)).Start()
+	nodeB := testutil.NewTestNode(t).WithMockHost(hostB).WithRelay(true).
+		WithOptions(dep2p.WithRelayAddr(relayAddr), dep2p.WithHolePunch(false)).Start()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	// A 在中继上预留后才能被经由中继拨通
+	circuit := relayAddr + "/p2p-circuit/p2p/" + nodeA.ID()
+	testutil.Eventually(t, 30*time.Second, func() bool {
+		return nodeB.Connect(ctx, circuit) == nil
+	}, "B 应该能经中继电路连接 A")
+
+	testutil.Eventually(t, 10*time.Second, func() bool {
+		relayed, _ := relayedConns(nodeA, nodeB.ID())
+		return relayed > 0
+	}, "A 应该看到来自 B 的中继连接")
+
+	relayed, direct := relayedConns(nodeB, nodeA.ID())
+	require.Positive(t, relayed, "B 到 A 应为中继连接")
+	require.Zero(t, direct, "对称型 NAT 之间不应出现直连")
+}
+
+// TestMockNet_HolePunchUpgradesRelay 测试端口受限锥形 NAT 后的节点经中继协调打洞
+//
+// 验证:
+//   - 先经中继电路建立连接
+//   - 打洞成功后双方都看到直连连接
+func TestMockNet_HolePunchUpgradesRelay(t *testing.T) {
+	mn := testutil.NewMockNet(t)
+	_, relayAddr := startMockRelay(t, mn)
+
+	hostA := testutil.NewMockHost(t, mn, mocknet.BehindNAT(testutil.NewMockNAT(t, mn, types.NATTypePortRestricted)))
+	hostB := testutil.NewMockHost(t, mn, mocknet.BehindNAT(testutil.NewMockNAT(t, mn, types.NATTypePortRestricted)))
+
+	nodeA := testutil.NewTestNode(t).WithMockHost(hostA).WithRelay(true).
+		WithOptions(dep2p.WithRelayAddr(relayAddr), dep2p.WithHolePunch(true)).Start()
+	nodeB := testutil.NewTestNode(t).WithMockHost(hostB).WithRelay(true).
+		WithOptions(dep2p.WithRelayAddr(relayAddr), dep2p.WithHolePunch(true)).Start()
+
+	// 双方需先经中继观测到自己的 NAT 公网端点，协商时才有可用的打洞地址
+	testutil.Eventually(t, 30*time.Second, func() bool {
+		return len(nodeA.Host().HolePunchAddrs()) > 0 && len(nodeB.Host().HolePunchAddrs()) > 0
+	}, "双方应该观测到各自的打洞地址")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	circuit := relayAddr + "/p2p-circuit/p2p/" + nodeA.ID()
+	testutil.Eventually(t, 30*time.Second, func() bool {
+		return nodeB.Connect(ctx, circuit) == nil
+	}, "B 应该能经中继电路连接 A")
+
+	testutil.Eventually(t, 30*time.Second, func() bool {
+		_, direct := relayedConns(nodeB, nodeA.ID())
+		return direct > 0
+	}, "打洞后 B 与 A 之间应出现直连")
+
+	testutil.Eventually(t, 10*time.Second, func() bool {
+		_, direct := relayedConns(nodeA, nodeB.ID())
+		return direct > 0
+	}, "A 也应该看到与 B 的直连")
+}
@@ -8,6 +8,7 @@
 //   - MockHost: 模拟 interfaces.Host，支持自定义 ID、地址、流处理等
 //   - MockStream: 模拟 interfaces.Stream，支持读写数据模拟
 //   - MockConnection: 模拟 interfaces.Connection，支持连接属性和流创建
+//   - PipeNet: 基于 net.Pipe 的内存网络，MockHost 之间可真实协商协议并收发数据
 //
 // # 身份 Mock
 //
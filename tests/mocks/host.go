@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"sync"
 
 	"github.com/dep2p/go-dep2p/pkg/interfaces"
 )
@@ -32,8 +33,10 @@ type MockHost struct {
 	ShareableAddrsFunc             func() []string
 	HolePunchAddrsFunc             func() []string
 	SetReachabilityCoordinatorFunc func(coordinator interfaces.ReachabilityCoordinator)
+	HandleInboundStreamFunc        func(stream interfaces.Stream)
 
 	// 调用记录（用于验证）
+	callsMu        sync.Mutex
 	ConnectCalls   []ConnectCall
 	NewStreamCalls []NewStreamCall
 }
@@ -48,6 +51,7 @@ type ConnectCall struct {
 type NewStreamCall struct {
 	PeerID      string
 	ProtocolIDs []string
+	Priority    int
 }
 
 // NewMockHost 创建带有默认值的 MockHost
@@ -84,7 +88,9 @@ func (m *MockHost) Listen(addrs ...string) error {
 
 // Connect 连接到远程节点
 func (m *MockHost) Connect(ctx context.Context, peerID string, addrs []string) error {
+	m.callsMu.Lock()
 	m.ConnectCalls = append(m.ConnectCalls, ConnectCall{PeerID: peerID, Addrs: addrs})
+	m.callsMu.Unlock()
 	if m.ConnectFunc != nil {
 		return m.ConnectFunc(ctx, peerID, addrs)
 	}
@@ -92,7 +98,7 @@ func (m *MockHost) Connect(ctx context.Context, peerID string, addrs []string) e
 }
 
 // SetStreamHandler 设置流处理器
-func (m *MockHost) SetStreamHandler(protocolID string, handler interfaces.StreamHandler) {
+func (m *MockHost) SetStreamHandler(protocolID string, handler interfaces.StreamHandler, _ ...interfaces.StreamHandlerOption) {
 	if m.SetStreamHandlerFunc != nil {
 		m.SetStreamHandlerFunc(protocolID, handler)
 	}
@@ -107,18 +113,32 @@ func (m *MockHost) RemoveStreamHandler(protocolID string) {
 
 // NewStream 创建新流
 func (m *MockHost) NewStream(ctx context.Context, peerID string, protocolIDs ...string) (interfaces.Stream, error) {
-	m.NewStreamCalls = append(m.NewStreamCalls, NewStreamCall{PeerID: peerID, ProtocolIDs: protocolIDs})
+	return m.newStream(ctx, peerID, int(interfaces.StreamPriorityNormal), protocolIDs)
+}
+
+// NewStreamWithPriority 创建带优先级的流 (v1.2 新增)
+func (m *MockHost) NewStreamWithPriority(ctx context.Context, peerID string, protocolID string, priority int) (interfaces.Stream, error) {
+	return m.newStream(ctx, peerID, priority, []string{protocolID})
+}
+
+// StreamCalls 返回 NewStream/NewStreamWithPriority 调用记录的副本（并发安全）
+func (m *MockHost) StreamCalls() []NewStreamCall {
+	m.callsMu.Lock()
+	defer m.callsMu.Unlock()
+	return append([]NewStreamCall(nil), m.NewStreamCalls...)
+}
+
+// newStream 记录调用并创建流
+func (m *MockHost) newStream(ctx context.Context, peerID string, priority int, protocolIDs []string) (interfaces.Stream, error) {
+	m.callsMu.Lock()
+	m.NewStreamCalls = append(m.NewStreamCalls, NewStreamCall{PeerID: peerID, ProtocolIDs: protocolIDs, Priority: priority})
+	m.callsMu.Unlock()
 	if m.NewStreamFunc != nil {
 		return m.NewStreamFunc(ctx, peerID, protocolIDs...)
 	}
 	return NewMockStream(), nil
 }
 
-// NewStreamWithPriority 创建带优先级的流 (v1.2 新增)
-func (m *MockHost) NewStreamWithPriority(ctx context.Context, peerID string, protocolID string, _ int) (interfaces.Stream, error) {
-	return m.NewStream(ctx, peerID, protocolID)
-}
-
 // Peerstore 返回节点存储
 func (m *MockHost) Peerstore() interfaces.Peerstore {
 	if m.PeerstoreFunc != nil {
@@ -184,8 +204,10 @@ func (m *MockHost) SetReachabilityCoordinator(coordinator interfaces.Reachabilit
 }
 
 // HandleInboundStream 处理入站流
-func (m *MockHost) HandleInboundStream(_ interfaces.Stream) {
-	// Mock 实现：不做任何处理
+func (m *MockHost) HandleInboundStream(stream interfaces.Stream) {
+	if m.HandleInboundStreamFunc != nil {
+		m.HandleInboundStreamFunc(stream)
+	}
 }
 
 // 确保实现接口
@@ -0,0 +1,230 @@
+package mocks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/types"
+	mss "github.com/multiformats/go-multistream"
+)
+
+// ErrPipeNoHandler 目标节点不存在或未注册任何处理器
+var ErrPipeNoHandler = errors.New("mocks: no handler on remote peer")
+
+// PipeNet 基于内存管道的网络
+//
+// 网络中的 MockHost 之间可以真实地打开流：NewStream 通过
+// multistream-select 与远端已注册的处理器协商协议，
+// 适用于验证协议协商和线路字节的测试。
+//
+// 与 net.Pipe 不同，流的每个方向都带有无界缓冲，写入不会等待对端读取，
+// 行为更接近真实传输。不支持读写超时。
+type PipeNet struct {
+	mu       sync.Mutex
+	handlers map[string]map[string]interfaces.StreamHandler
+
+	wireBytes atomic.Int64
+}
+
+// NewPipeNet 创建内存网络
+func NewPipeNet() *PipeNet {
+	return &PipeNet{
+		handlers: make(map[string]map[string]interfaces.StreamHandler),
+	}
+}
+
+// WireBytes 返回所有流上写入的总字节数
+func (n *PipeNet) WireBytes() int64 {
+	return n.wireBytes.Load()
+}
+
+// ResetWireBytes 清零字节计数
+func (n *PipeNet) ResetWireBytes() {
+	n.wireBytes.Store(0)
+}
+
+// Protocols 返回节点已注册的协议
+func (n *PipeNet) Protocols(peerID string) []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	protos := make([]string, 0, len(n.handlers[peerID]))
+	for proto := range n.handlers[peerID] {
+		protos = append(protos, proto)
+	}
+	return protos
+}
+
+// AddHost 向网络添加节点，返回的 MockHost 与网络中所有节点互相连通
+func (n *PipeNet) AddHost(id string) *MockHost {
+	swarm := NewMockSwarm(id)
+	swarm.ConnectednessFunc = func(string) interfaces.Connectedness {
+		return interfaces.Connected
+	}
+
+	host := NewMockHost(id)
+	host.NetworkFunc = func() interfaces.Swarm { return swarm }
+	host.SetStreamHandlerFunc = func(protocolID string, handler interfaces.StreamHandler) {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		if n.handlers[id] == nil {
+			n.handlers[id] = make(map[string]interfaces.StreamHandler)
+		}
+		n.handlers[id][protocolID] = handler
+	}
+	host.RemoveStreamHandlerFunc = func(protocolID string) {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		delete(n.handlers[id], protocolID)
+	}
+	host.NewStreamFunc = func(_ context.Context, peerID string, protocolIDs ...string) (interfaces.Stream, error) {
+		return n.newStream(id, peerID, protocolIDs)
+	}
+	return host
+}
+
+// newStream 在两个节点之间建立流并协商协议
+func (n *PipeNet) newStream(from, to string, protocolIDs []string) (interfaces.Stream, error) {
+	n.mu.Lock()
+	mux := mss.NewMultistreamMuxer[string]()
+	handlers := make(map[string]interfaces.StreamHandler, len(n.handlers[to]))
+	for proto, h := range n.handlers[to] {
+		mux.AddHandler(proto, nil)
+		handlers[proto] = h
+	}
+	n.mu.Unlock()
+
+	if len(handlers) == 0 {
+		return nil, ErrPipeNoHandler
+	}
+
+	toRemote, toLocal := newBufPipe(), newBufPipe()
+	inbound := &pipeNetStream{in: toRemote, out: toLocal, net: n, conn: NewMockConnection(types.PeerID(to), types.PeerID(from))}
+	outbound := &pipeNetStream{in: toLocal, out: toRemote, net: n, conn: NewMockConnection(types.PeerID(from), types.PeerID(to))}
+
+	go func() {
+		proto, _, err := mux.Negotiate(inbound)
+		if err != nil {
+			inbound.Reset()
+			return
+		}
+		inbound.SetProtocol(proto)
+		handlers[proto](inbound)
+	}()
+
+	selected, err := mss.SelectOneOf(protocolIDs, outbound)
+	if err != nil {
+		outbound.Reset()
+		return nil, err
+	}
+	outbound.SetProtocol(selected)
+	return outbound, nil
+}
+
+// bufPipe 单向无界缓冲管道
+type bufPipe struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	buf    bytes.Buffer
+	closed bool
+}
+
+func newBufPipe() *bufPipe {
+	p := &bufPipe{}
+	p.cond = sync.NewCond(&p.mu)
+	return p
+}
+
+func (p *bufPipe) Read(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for p.buf.Len() == 0 {
+		if p.closed {
+			return 0, io.EOF
+		}
+		p.cond.Wait()
+	}
+	return p.buf.Read(b)
+}
+
+func (p *bufPipe) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return 0, io.ErrClosedPipe
+	}
+	p.buf.Write(b)
+	p.cond.Broadcast()
+	return len(b), nil
+}
+
+func (p *bufPipe) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	p.cond.Broadcast()
+}
+
+// pipeNetStream 基于 bufPipe 的流
+type pipeNetStream struct {
+	in   *bufPipe
+	out  *bufPipe
+	net  *PipeNet
+	conn interfaces.Connection
+
+	mu       sync.Mutex
+	protocol string
+	closed   atomic.Bool
+}
+
+var _ interfaces.Stream = (*pipeNetStream)(nil)
+
+func (s *pipeNetStream) Read(p []byte) (int, error) { return s.in.Read(p) }
+
+func (s *pipeNetStream) Write(p []byte) (int, error) {
+	n, err := s.out.Write(p)
+	s.net.wireBytes.Add(int64(n))
+	return n, err
+}
+
+// Close 关闭写方向，已写入的数据仍可被对端读完
+func (s *pipeNetStream) Close() error {
+	s.closed.Store(true)
+	s.out.Close()
+	return nil
+}
+
+// Reset 同时关闭两个方向
+func (s *pipeNetStream) Reset() error {
+	s.closed.Store(true)
+	s.out.Close()
+	s.in.Close()
+	return nil
+}
+
+func (s *pipeNetStream) CloseWrite() error                { s.out.Close(); return nil }
+func (s *pipeNetStream) CloseRead() error                 { s.in.Close(); return nil }
+func (s *pipeNetStream) SetDeadline(time.Time) error      { return nil }
+func (s *pipeNetStream) SetReadDeadline(time.Time) error  { return nil }
+func (s *pipeNetStream) SetWriteDeadline(time.Time) error { return nil }
+func (s *pipeNetStream) Conn() interfaces.Connection      { return s.conn }
+func (s *pipeNetStream) IsClosed() bool                   { return s.closed.Load() }
+func (s *pipeNetStream) Stat() types.StreamStat           { return types.StreamStat{} }
+func (s *pipeNetStream) State() types.StreamState         { return types.StreamState(0) }
+
+func (s *pipeNetStream) Protocol() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.protocol
+}
+
+func (s *pipeNetStream) SetProtocol(p string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.protocol = p
+}
@@ -0,0 +1,112 @@
+// Package testutil 提供测试辅助工具
+package testutil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+
+	"github.com/dep2p/go-dep2p"
+	"github.com/dep2p/go-dep2p/config"
+	"github.com/dep2p/go-dep2p/internal/core/transport"
+	"github.com/dep2p/go-dep2p/internal/core/transport/mocknet"
+	pkgif "github.com/dep2p/go-dep2p/pkg/interfaces"
+	"github.com/dep2p/go-dep2p/pkg/types"
+)
+
+// MockNetListenPort 模拟网络节点的默认监听端口
+const MockNetListenPort = 4001
+
+// NewMockNet 创建模拟网络
+//
+// 模拟网络在内存中运行，节点之间的连接仍经过真实的 Upgrader、Swarm、
+// Relay 和打洞代码，但不占用真实端口，并可以模拟延迟、带宽、丢包、
+// NAT 和网络分区。
+//
+// 示例:
+//
+//	mn := testutil.NewMockNet(t)
+//	nat := testutil.NewMockNAT(t, mn, types.NATTypePortRestricted)
+//	hostA := testutil.NewMockHost(t, mn)
+//	hostB := testutil.NewMockHost(t, mn, mocknet.BehindNAT(nat))
+//	nodeA := testutil.NewTestNode(t).WithMockHost(hostA).Start()
+//	nodeB := testutil.NewTestNode(t).WithMockHost(hostB).Start()
+//	mn.Unlink(hostA, hostB) // 运行时断开链路
+func NewMockNet(t *testing.T, opts ...mocknet.Option) *mocknet.Network {
+	t.Helper()
+	return mocknet.New(opts...)
+}
+
+// NewMockHost 在模拟网络中创建主机
+func NewMockHost(t *testing.T, mn *mocknet.Network, opts ...mocknet.HostOption) *mocknet.Host {
+	t.Helper()
+	host, err := mn.NewHost(opts...)
+	require.NoError(t, err, "创建模拟主机失败")
+	return host
+}
+
+// NewMockNAT 在模拟网络中创建 NAT 设备
+func NewMockNAT(t *testing.T, mn *mocknet.Network, typ types.NATType) *mocknet.NAT {
+	t.Helper()
+	nat, err := mn.NewNAT(typ, "")
+	require.NoError(t, err, "创建模拟 NAT 失败")
+	return nat
+}
+
+// WithMockHost 使用模拟网络主机替代真实传输
+//
+// 未显式设置的监听地址替换为主机地址；mDNS 被禁用（模拟网络不支持组播），
+// STUN 服务器被清空（模拟网络无法访问外部网络）。公网主机直接通告自身地址，
+// 并锁定为公网可达。
+func (b *TestNodeBuilder) WithMockHost(host *mocknet.Host) *TestNodeBuilder {
+	b.t.Helper()
+	b.mockHost = host
+	return b
+}
+
+// mockNetOptions 返回接入模拟网络的节点选项
+func mockNetOptions(host *mocknet.Host, listenAddrs []string) []dep2p.Option {
+	addrs := make([]string, 0, len(listenAddrs))
+	for _, addr := range listenAddrs {
+		// 默认的回环 QUIC 地址替换为主机地址
+		if strings.Contains(addr, "/quic") || strings.HasPrefix(addr, "/ip4/127.0.0.1/") {
+			continue
+		}
+		addrs = append(addrs, addr)
+	}
+	if len(addrs) == 0 {
+		addrs = []string{host.Addr(MockNetListenPort)}
+	}
+
+	factory := transport.Factory(func(localPeer types.PeerID, _ pkgif.Identity, upgrader pkgif.Upgrader) []pkgif.Transport {
+		return []pkgif.Transport{host.NewTransport(localPeer, upgrader)}
+	})
+
+	public := host.NAT() == nil
+	opts := []dep2p.Option{
+		dep2p.WithListenAddrs(addrs...),
+		dep2p.WithMDNS(false),
+		dep2p.WithDHT(true), // 至少需要一种发现机制
+		dep2p.WithFxOptions(
+			fx.Provide(func() transport.Factory { return factory }),
+			// 模拟网络无法访问真实 STUN 服务器，跳过 NAT 类型检测；
+			// 公网主机直接锁定为公网可达，否则启动时会等待 Relay 地址直到超时
+			fx.Decorate(func(cfg *config.Config) *config.Config {
+				cfg.NAT.STUNServers = nil
+				if public {
+					cfg.NAT.LockReachabilityPublic = true
+				}
+				return cfg
+			}),
+		),
+	}
+	// 公网主机直接通告自身地址，NAT 后的主机依赖观测地址与打洞
+	if public {
+		for _, addr := range addrs {
+			opts = append(opts, dep2p.WithPublicAddr(addr))
+		}
+	}
+	return opts
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/dep2p/go-dep2p"
+	"github.com/dep2p/go-dep2p/internal/core/transport/mocknet"
 )
 
 // TestNodeBuilder 测试节点构建器
@@ -28,6 +29,8 @@ type TestNodeBuilder struct {
 	dataDir     string
 	preset      string
 	enableRelay bool
+	mockHost    *mocknet.Host
+	extraOpts   []dep2p.Option
 }
 
 // NewTestNode 创建测试节点构建器
@@ -88,6 +91,13 @@ func (b *TestNodeBuilder) WithRelay(enable bool) *TestNodeBuilder {
 	return b
 }
 
+// WithOptions 追加节点选项
+func (b *TestNodeBuilder) WithOptions(opts ...dep2p.Option) *TestNodeBuilder {
+	b.t.Helper()
+	b.extraOpts = append(b.extraOpts, opts...)
+	return b
+}
+
 // Start 启动节点并注册清理函数
 //
 // 节点会在测试结束时自动关闭。
@@ -114,6 +124,12 @@ func (b *TestNodeBuilder) Start() *dep2p.Node {
 		opts = append(opts, dep2p.WithListenAddrs(b.listenAddrs...))
 	}
 
+	// 模拟网络
+	if b.mockHost != nil {
+		opts = append(opts, mockNetOptions(b.mockHost, b.listenAddrs)...)
+	}
+	opts = append(opts, b.extraOpts...)
+
 	// 启动节点
 	node, err := dep2p.Start(ctx, opts...)
 	require.NoError(b.t, err, "启动测试节点失败")
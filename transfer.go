@@ -0,0 +1,83 @@
+package dep2p
+
+import (
+	"context"
+
+	"github.com/dep2p/go-dep2p/pkg/interfaces"
+)
+
+// ════════════════════════════════════════════════════════════════════════════
+//                              用户 API: Transfer
+// ════════════════════════════════════════════════════════════════════════════
+
+// Transfer 用户级文件传输服务 API
+//
+// Transfer 基于 Streams 在 Realm 成员之间传输大文件：
+//   - 内容寻址：文件分块并计算 Merkle 根，CID 唯一标识内容
+//   - 校验：每个分块写入前都与清单比对，损坏的提供者会被弃用
+//   - 多源：通过 DHT 查找持有同一内容的成员，并行下载不同分块
+//   - 续传：中断后再次下载会跳过已校验的分块
+//   - 限速：按配置限制上传和下载速率
+//
+// 使用示例：
+//
+//	tr := realm.Transfer()
+//
+//	// 提供方
+//	cid, _ := tr.Share(ctx, "/data/video.mp4")
+//
+//	// 下载方
+//	err := tr.Download(ctx, cid, "/tmp/video.mp4", dep2p.DownloadOptions{
+//	    Progress: func(p dep2p.TransferProgress) {
+//	        fmt.Printf("%d/%d bytes\n", p.DoneBytes, p.TotalBytes)
+//	    },
+//	})
+type Transfer struct {
+	internal interfaces.Transfer
+}
+
+// DownloadOptions 下载选项
+type DownloadOptions = interfaces.DownloadOptions
+
+// TransferProgress 传输进度
+type TransferProgress = interfaces.TransferProgress
+
+// Share 共享本地文件，返回内容 ID
+//
+// 共享期间文件内容不应被修改；被修改的分块不会再发送给下载方。
+func (t *Transfer) Share(ctx context.Context, path string) (string, error) {
+	if t.internal == nil {
+		return "", ErrTransferUnavailable
+	}
+	return t.internal.Share(ctx, path)
+}
+
+// Unshare 停止共享内容
+func (t *Transfer) Unshare(cid string) error {
+	if t.internal == nil {
+		return ErrTransferUnavailable
+	}
+	return t.internal.Unshare(cid)
+}
+
+// Download 下载内容到 dest
+//
+// 参数：
+//   - ctx: 上下文（取消后已校验的分块保留在 dest.part 中，可续传）
+//   - cid: 内容 ID
+//   - dest: 目标文件路径
+//   - opts: 下载选项（额外提供者、并行度、进度回调、完成后做种）
+func (t *Transfer) Download(ctx context.Context, cid string, dest string, opts DownloadOptions) error {
+	if t.internal == nil {
+		return ErrTransferUnavailable
+	}
+	return t.internal.Download(ctx, cid, dest, opts)
+}
+
+// Shared 返回本节点正在共享的内容 ID
+func (t *Transfer) Shared() []string {
+	if t.internal == nil {
+		return nil
+	}
+	return t.internal.Shared()
+}
@@ -86,6 +86,50 @@ func (s BandwidthSnapshot) TotalRate() float64 {
 	return s.RateIn + s.RateOut
 }
 
+// BandwidthLimit 单个范围的限速（字节/秒，0 表示不限制）
+type BandwidthLimit = pkgif.BandwidthLimit
+
+// BandwidthLimits 带宽限速配置（节点总量、对端节点、协议、Realm）
+type BandwidthLimits = pkgif.BandwidthLimits
+
+// BandwidthLimitStatus 单个限速范围的状态
+type BandwidthLimitStatus = pkgif.BandwidthLimitStatus
+
+// DataBudgetMode 计费网络模式
+type DataBudgetMode = pkgif.DataBudgetMode
+
+// 计费网络模式
+const (
+	DataBudgetAuto      = pkgif.DataBudgetAuto
+	DataBudgetMetered   = pkgif.DataBudgetMetered
+	DataBudgetUnmetered = pkgif.DataBudgetUnmetered
+)
+
+// DataBudgetStatus 数据预算状态（今日用量、节流倍数、被节流的子系统）
+type DataBudgetStatus = pkgif.DataBudgetStatus
+
+// DataBudgetThrottle 单个子系统的节流统计
+type DataBudgetThrottle = pkgif.DataBudgetThrottle
+
+// PeerReputation 节点信誉（统一信誉分、封禁状态、各信号的贡献）
+type PeerReputation = pkgif.PeerReputation
+
+// ReputationReason 单个信号对信誉分的贡献
+type ReputationReason = pkgif.ReputationReason
+
+// ReputationSignal 信誉信号来源
+type ReputationSignal = pkgif.ReputationSignal
+
+// 信誉信号来源
+const (
+	ReputationSignalPubSub     = pkgif.ReputationSignalPubSub
+	ReputationSignalConnMgr    = pkgif.ReputationSignalConnMgr
+	ReputationSignalJitter     = pkgif.ReputationSignalJitter
+	ReputationSignalPathHealth = pkgif.ReputationSignalPathHealth
+	ReputationSignalWitness    = pkgif.ReputationSignalWitness
+	ReputationSignalDial       = pkgif.ReputationSignalDial
+)
+
 // ════════════════════════════════════════════════════════════════════════════
 //                              连接信息
 // ════════════════════════════════════════════════════════════════════════════
@@ -173,10 +217,31 @@ type NetworkDiagnosticReport struct {
 	// 中继延迟（毫秒）
 	RelayLatencies map[string]int64 `json:"relay_latencies,omitempty"`
 
+	// 中继探测详情（通过中继协议测得的延迟与负载）
+	RelayProbes map[string]RelayProbeInfo `json:"relay_probes,omitempty"`
+
+	// 首选中继（按负载加权延迟选择）
+	PreferredRelay string `json:"preferred_relay,omitempty"`
+
 	// 生成耗时（毫秒）
 	Duration int64 `json:"duration_ms"`
 }
 
+// RelayProbeInfo 中继探测详情（用户友好类型）
+type RelayProbeInfo struct {
+	// LatencyMs 协议层往返时延（毫秒）
+	LatencyMs int64 `json:"latency_ms"`
+
+	// LoadKnown 中继是否报告了负载（旧版中继不报告）
+	LoadKnown bool `json:"load_known"`
+
+	// ReservationUtilization 预留利用率（0-1）
+	ReservationUtilization float64 `json:"reservation_utilization"`
+
+	// CircuitUtilization 电路利用率（0-1）
+	CircuitUtilization float64 `json:"circuit_utilization"`
+}
+
 // ════════════════════════════════════════════════════════════════════════════
 //                              种子节点
 // ════════════════════════════════════════════════════════════════════════════